@@ -20,11 +20,15 @@ import (
 	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
 
+	"stock-ai/backend/cache"
 	"stock-ai/backend/data"
+	"stock-ai/backend/localapi"
 	"stock-ai/backend/models"
 	"stock-ai/backend/plugin"
 	"stock-ai/backend/prompt"
+	"stock-ai/backend/screener"
 
 	"gorm.io/gorm/clause"
 
@@ -39,6 +43,7 @@ var (
 // App struct
 type App struct {
 	ctx             context.Context
+	cancel          context.CancelFunc // 应用退出时取消 ctx，中止进行中的数据请求
 	stockAPI        *data.StockAPI
 	fundAPI         *data.FundAPI
 	aiClient        *data.AIClient
@@ -46,18 +51,18 @@ type App struct {
 	globalMarketAPI *data.GlobalMarketAPI
 	cryptoForexAPI  *data.CryptoForexAPI
 	sentimentAPI    *data.SentimentAPI
+	assetBuilder    *data.AssetContextBuilder
 	pluginManager   *plugin.Manager
 	promptManager   *prompt.Manager
-	// 自选股票价格缓存（用于提醒检查）
-	stockPriceCache     map[string]*models.StockPrice
-	stockPriceCacheLock sync.RWMutex
-	stockKLineCache     map[string]map[string][]models.KLineData
-	stockReportCache    map[string][]models.ResearchReport
-	stockNoticeCache    map[string][]models.StockNotice
-	stockFinancialCache map[string]*data.FinancialData
-	stockDataCacheLock  sync.RWMutex
-	fundPriceCache      map[string]*models.FundPrice
-	fundPriceCacheLock  sync.RWMutex
+	// 自选股票价格缓存（用于提醒检查），由定时刷新覆盖，不设过期
+	stockPriceCache     *cache.Cache[*models.StockPrice]
+	stockKLineCache     *cache.Cache[[]models.KLineData] // 键为 klineCacheKey(code, period)
+	stockReportCache    *cache.Cache[[]models.ResearchReport]
+	stockNoticeCache    *cache.Cache[[]models.StockNotice]
+	stockFinancialCache *cache.Cache[*data.FinancialData]
+	fundPriceCache      *cache.Cache[*models.FundPrice]
+	// 本地API服务，配置启用后监听 127.0.0.1
+	apiServer *localapi.Server
 }
 
 type klineFetchSpec struct {
@@ -208,26 +213,34 @@ func (a *App) logUpdateEvent(format string, args ...interface{}) {
 
 // NewApp creates a new App application struct
 func NewApp() *App {
-	return &App{
-		stockAPI:            data.NewStockAPI(),
+	stockAPI := data.NewStockAPI()
+	futuresAPI := data.NewFuturesAPI()
+	globalMarketAPI := data.NewGlobalMarketAPI()
+	cryptoForexAPI := data.NewCryptoForexAPI()
+	app := &App{
+		stockAPI:            stockAPI,
 		fundAPI:             data.NewFundAPI(),
-		futuresAPI:          data.NewFuturesAPI(),
-		globalMarketAPI:     data.NewGlobalMarketAPI(),
-		cryptoForexAPI:      data.NewCryptoForexAPI(),
+		futuresAPI:          futuresAPI,
+		globalMarketAPI:     globalMarketAPI,
+		cryptoForexAPI:      cryptoForexAPI,
 		sentimentAPI:        data.NewSentimentAPI(),
-		stockPriceCache:     make(map[string]*models.StockPrice),
-		stockKLineCache:     make(map[string]map[string][]models.KLineData),
-		stockReportCache:    make(map[string][]models.ResearchReport),
-		stockNoticeCache:    make(map[string][]models.StockNotice),
-		stockFinancialCache: make(map[string]*data.FinancialData),
-		fundPriceCache:      make(map[string]*models.FundPrice),
+		assetBuilder:        data.NewAssetContextBuilder(stockAPI, futuresAPI, globalMarketAPI, cryptoForexAPI),
+		stockPriceCache:     cache.New[*models.StockPrice](cache.Options{Namespace: "quote.stock_price", MaxEntries: 2000}),
+		stockKLineCache:     cache.New[[]models.KLineData](cache.Options{Namespace: "quote.stock_kline", MaxEntries: 500}),
+		stockReportCache:    cache.New[[]models.ResearchReport](cache.Options{Namespace: "report.stock", MaxEntries: 500}),
+		stockNoticeCache:    cache.New[[]models.StockNotice](cache.Options{Namespace: "notice.stock", MaxEntries: 500}),
+		stockFinancialCache: cache.New[*data.FinancialData](cache.Options{Namespace: "financial.stock", MaxEntries: 500}),
+		fundPriceCache:      cache.New[*models.FundPrice](cache.Options{Namespace: "quote.fund_price", MaxEntries: 1000}),
+		apiServer:           localapi.NewServer(),
 	}
+	app.registerLocalAPIRoutes()
+	return app
 }
 
 // startup is called when the app starts
 func (a *App) startup(ctx context.Context) {
 	setupAppLogger()
-	a.ctx = ctx
+	a.ctx, a.cancel = context.WithCancel(ctx)
 
 	// 初始化数据库
 	if err := data.InitDB(); err != nil {
@@ -240,6 +253,17 @@ func (a *App) startup(ctx context.Context) {
 	if err := a.pluginManager.Init(); err != nil {
 		log.Printf("初始化插件管理器失败: %v", err)
 	}
+	a.syncPluginQuoteProviders()
+
+	// 数据源字段结构变化时通知前端
+	data.GetSchemaMonitor().SetEventHandler(func(event models.SchemaDriftEvent) {
+		a.emitEvent("data-schema-drift", event)
+	})
+
+	// 按配置启动本地API服务
+	if config, err := a.GetConfig(); err == nil {
+		a.applyLocalAPIConfig(config)
+	}
 
 	// 初始化提示词管理器
 	promptsDir := getPromptsDir()
@@ -250,8 +274,45 @@ func (a *App) startup(ctx context.Context) {
 		a.promptManager = promptMgr
 	}
 
-	go a.prefetchWatchlistData()
-	a.startPriceCacheUpdater()
+	go a.prefetchWatchlistData(a.backgroundContext(data.PriorityPrefetch))
+	a.startPriceCacheUpdater(a.backgroundContext(data.PriorityAlert))
+	data.GetNewsCollector().Start(a.backgroundContext(data.PriorityPrefetch))
+	data.GetRequestManager().StartProxyHealthCheck(a.lifetimeContext())
+	a.startStockEventAlertChecker(a.backgroundContext(data.PriorityAlert))
+	// 定时日报走批量通道，不挤占界面操作的请求
+	a.startDailyDigestScheduler(a.backgroundContext(data.PriorityBulk))
+}
+
+// shutdown is called when the app is about to quit
+func (a *App) shutdown(ctx context.Context) {
+	// 取消进行中的后台任务与数据请求
+	if a.cancel != nil {
+		a.cancel()
+	}
+	// 放弃排队中的数据请求，避免后台预加载拖慢退出
+	data.GetRateLimiter().Shutdown()
+	a.apiServer.Stop()
+}
+
+// bindingTimeout 前端绑定调用等待数据的最长时间，超时后返回错误而不是一直挂起
+const bindingTimeout = 30 * time.Second
+
+// lifetimeContext 应用生命周期上下文，退出时取消；流式分析等长任务直接使用
+func (a *App) lifetimeContext() context.Context {
+	if a.ctx == nil {
+		return context.Background()
+	}
+	return a.ctx
+}
+
+// callContext 前端绑定调用使用的上下文，应用退出或超过 bindingTimeout 时取消
+func (a *App) callContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(a.lifetimeContext(), bindingTimeout)
+}
+
+// backgroundContext 后台任务使用的上下文，按 priority 参与请求调度，应用退出时取消
+func (a *App) backgroundContext(priority data.RequestPriority) context.Context {
+	return data.WithPriority(a.lifetimeContext(), priority)
 }
 
 // getPluginsDir 获取插件目录
@@ -284,6 +345,8 @@ func (a *App) GetStockList() ([]models.Stock, error) {
 
 // AddStock 添加自选股票
 func (a *App) AddStock(code string) error {
+	ctx, cancel := a.callContext()
+	defer cancel()
 	// 标准化股票代码
 	code = normalizeStockCode(code)
 
@@ -299,7 +362,7 @@ func (a *App) AddStock(code string) error {
 			}).Error; err != nil {
 				return err
 			}
-			go a.prefetchStockData(code, nil)
+			go a.prefetchStockData(a.backgroundContext(data.PriorityPrefetch), code, nil)
 			return nil
 		}
 		// 记录已存在且未删除
@@ -307,7 +370,7 @@ func (a *App) AddStock(code string) error {
 	}
 
 	// 获取股票名称
-	prices, err := a.stockAPI.GetStockPrice([]string{code})
+	prices, err := a.stockAPI.GetStockPrice(ctx, []string{code})
 	appendTraceLog("[TradeLevel] price fetch %s err=%v", code, err)
 	if err != nil {
 		return err
@@ -333,7 +396,7 @@ func (a *App) AddStock(code string) error {
 		return err
 	}
 
-	go a.prefetchStockData(stock.Code, nil)
+	go a.prefetchStockData(a.backgroundContext(data.PriorityPrefetch), stock.Code, nil)
 	return nil
 }
 
@@ -386,25 +449,32 @@ func (a *App) RemoveStock(code string) error {
 
 // GetStockPrice 获取股票实时价格
 func (a *App) GetStockPrice(codes []string) (map[string]*models.StockPrice, error) {
-	prices, err := a.stockAPI.GetStockPrice(codes)
+	ctx, cancel := a.callContext()
+	defer cancel()
+	return a.getStockPrice(ctx, codes)
+}
+
+// getStockPrice 获取股票实时价格并更新价格缓存
+func (a *App) getStockPrice(ctx context.Context, codes []string) (map[string]*models.StockPrice, error) {
+	prices, err := a.stockAPI.GetStockPrice(ctx, codes)
 	if err != nil {
 		return prices, err
 	}
 
 	// 更新价格缓存（用于提醒检查）
-	a.stockPriceCacheLock.Lock()
 	for code, price := range prices {
 		if price != nil {
-			a.stockPriceCache[code] = price
+			a.stockPriceCache.Set(code, price)
 		}
 	}
-	a.stockPriceCacheLock.Unlock()
 
 	return prices, nil
 }
 
 // GetKLineData 获取K线数据
 func (a *App) GetKLineData(code string, period string, count int) ([]models.KLineData, error) {
+	ctx, cancel := a.callContext()
+	defer cancel()
 	code = normalizeStockCode(code)
 	if period == "" {
 		period = "daily"
@@ -415,7 +485,7 @@ func (a *App) GetKLineData(code string, period string, count int) ([]models.KLin
 		return data, nil
 	}
 
-	klines, err := a.stockAPI.GetKLineData(code, period, count)
+	klines, err := a.stockAPI.GetKLineData(ctx, code, period, count)
 	if err != nil {
 		if cached, ok := a.getCachedKLines(code, period, count); ok {
 			appendTraceLog("[KLineTrace] 拉取失败，使用缓存数据 %s %s: %v", code, period, err)
@@ -440,7 +510,9 @@ func (a *App) GetKLineData(code string, period string, count int) ([]models.KLin
 
 // GetMinuteData 获取分时数据
 func (a *App) GetMinuteData(code string) ([]models.MinuteData, error) {
-	return a.stockAPI.GetMinuteData(code)
+	ctx, cancel := a.callContext()
+	defer cancel()
+	return a.stockAPI.GetMinuteData(ctx, code)
 }
 
 // ========== 基金相关 ==========
@@ -454,7 +526,9 @@ func (a *App) GetFundList() ([]models.Fund, error) {
 
 // AddFund 添加自选基金
 func (a *App) AddFund(code string) error {
-	prices, err := a.fundAPI.GetFundPrice([]string{code})
+	ctx, cancel := a.callContext()
+	defer cancel()
+	prices, err := a.fundAPI.GetFundPrice(ctx, []string{code})
 	if err != nil {
 		return err
 	}
@@ -465,7 +539,7 @@ func (a *App) AddFund(code string) error {
 	}
 
 	fundType := ""
-	if detail, err := a.fundAPI.GetFundDetail(code); err == nil && detail != nil {
+	if detail, err := a.fundAPI.GetFundDetail(ctx, code); err == nil && detail != nil {
 		if detail.Name != "" {
 			name = detail.Name
 		}
@@ -485,38 +559,43 @@ func (a *App) AddFund(code string) error {
 func (a *App) RemoveFund(code string) error {
 	err := data.GetDB().Where("code = ?", code).Delete(&models.Fund{}).Error
 	if err == nil {
-		a.fundPriceCacheLock.Lock()
-		delete(a.fundPriceCache, code)
-		a.fundPriceCacheLock.Unlock()
+		a.fundPriceCache.Delete(code)
 	}
 	return err
 }
 
 // GetFundPrice 获取基金估值
 func (a *App) GetFundPrice(codes []string) (map[string]*models.FundPrice, error) {
-	prices, err := a.fundAPI.GetFundPrice(codes)
+	ctx, cancel := a.callContext()
+	defer cancel()
+	return a.getFundPrice(ctx, codes)
+}
+
+// getFundPrice 获取基金估值并更新估值缓存
+func (a *App) getFundPrice(ctx context.Context, codes []string) (map[string]*models.FundPrice, error) {
+	prices, err := a.fundAPI.GetFundPrice(ctx, codes)
 	if err != nil {
 		return prices, err
 	}
 
-	a.fundPriceCacheLock.Lock()
 	for code, price := range prices {
 		if price != nil {
-			a.fundPriceCache[code] = price
+			a.fundPriceCache.Set(code, price)
 		}
 	}
-	a.fundPriceCacheLock.Unlock()
 	return prices, nil
 }
 
 // GetFundOverview 获取基金详情
 func (a *App) GetFundOverview(code string) (*models.FundOverview, error) {
+	ctx, cancel := a.callContext()
+	defer cancel()
 	code = strings.TrimSpace(code)
 	if code == "" {
 		return nil, fmt.Errorf("无效的基金代码")
 	}
 
-	priceResult, err := a.GetFundPrice([]string{code})
+	priceResult, err := a.getFundPrice(ctx, []string{code})
 	if err != nil {
 		return nil, fmt.Errorf("获取基金估值失败: %v", err)
 	}
@@ -538,22 +617,22 @@ func (a *App) GetFundOverview(code string) (*models.FundOverview, error) {
 
 	go func() {
 		defer wg.Done()
-		detail, detailErr = a.fundAPI.GetFundDetail(code)
+		detail, detailErr = a.fundAPI.GetFundDetail(ctx, code)
 	}()
 
 	go func() {
 		defer wg.Done()
-		history, historyErr = a.fundAPI.GetFundHistory(code, 120)
+		history, historyErr = a.fundAPI.GetFundHistory(ctx, code, 120)
 	}()
 
 	go func() {
 		defer wg.Done()
-		stockHoldings, bondHoldings, holdingErr = a.fundAPI.GetFundHoldings(code)
+		stockHoldings, bondHoldings, holdingErr = a.fundAPI.GetFundHoldings(ctx, code)
 	}()
 
 	go func() {
 		defer wg.Done()
-		notices, noticeErr = a.fundAPI.GetFundNotices(code, 20)
+		notices, noticeErr = a.fundAPI.GetFundNotices(ctx, code, 20)
 	}()
 
 	wg.Wait()
@@ -645,7 +724,9 @@ func (a *App) GetPopularUSStocks() []models.USStock {
 
 // GetUSStockPrice 获取美股实时行情
 func (a *App) GetUSStockPrice(symbols []string) (map[string]*models.USStockPrice, error) {
-	return a.globalMarketAPI.GetUSStockPrice(symbols)
+	ctx, cancel := a.callContext()
+	defer cancel()
+	return a.globalMarketAPI.GetUSStockPrice(ctx, symbols)
 }
 
 // SearchUSStock 搜索美股
@@ -685,7 +766,9 @@ func (a *App) GetPopularHKStocks() []models.HKStock {
 
 // GetHKStockPrice 获取港股实时行情
 func (a *App) GetHKStockPrice(codes []string) (map[string]*models.HKStockPrice, error) {
-	return a.globalMarketAPI.GetHKStockPrice(codes)
+	ctx, cancel := a.callContext()
+	defer cancel()
+	return a.globalMarketAPI.GetHKStockPrice(ctx, codes)
 }
 
 // SearchHKStock 搜索港股
@@ -723,22 +806,27 @@ func (a *App) GetGlobalIndicesList() []models.GlobalIndex {
 }
 
 // GetGlobalIndices 获取全球指数实时行情
-func (a *App) GetGlobalIndices() ([]models.GlobalIndex, error) {
-	result, err := a.globalMarketAPI.GetGlobalIndices()
-	if err == nil && len(result) > 0 {
-		// 异步保存到持久化缓存
-		go data.GetPersistentCache().SaveGlobalIndicesList(result)
+func (a *App) GetGlobalIndices() *models.GlobalIndexResult {
+	ctx, cancel := a.callContext()
+	defer cancel()
+	result := a.globalMarketAPI.GetGlobalIndices(ctx)
+	// 只持久化实时获取的数据，避免把兜底数据当作"上次成功获取"的数据
+	if result.Meta.Freshness == models.FreshnessLive {
+		go data.GetPersistentCache().SaveGlobalIndicesList(result.Items)
 	}
-	return result, err
+	return result
 }
 
 // GetGlobalNews 获取国际财经新闻
-func (a *App) GetGlobalNews(country string) ([]models.NewsItem, error) {
-	result, err := a.globalMarketAPI.GetGlobalNews(country)
-	if err == nil && len(result) > 0 {
-		go data.GetPersistentCache().SaveGlobalNews(country, result)
+func (a *App) GetGlobalNews(country string) *models.NewsListResult {
+	ctx, cancel := a.callContext()
+	defer cancel()
+	result := a.globalMarketAPI.GetGlobalNews(ctx, country)
+	// 只持久化实时获取的数据，避免把兜底数据当作"上次成功获取"的数据
+	if result.Meta.Freshness == models.FreshnessLive {
+		go data.GetPersistentCache().SaveGlobalNews(country, result.Items)
 	}
-	return result, err
+	return result
 }
 
 // ========== 外汇相关 (暂时禁用，返回空数据) ==========
@@ -755,16 +843,112 @@ func (a *App) GetForexRates() ([]models.ForexRate, error) {
 	return []models.ForexRate{}, nil
 }
 
+// GetForexHistory 获取货币对最近N天的日线历史（支持经美元换算的交叉货币对）
+func (a *App) GetForexHistory(pair string, days int) ([]models.ForexHistory, error) {
+	ctx, cancel := a.callContext()
+	defer cancel()
+	return a.cryptoForexAPI.GetForexHistory(ctx, pair, days)
+}
+
+// GetCrossRate 获取交叉汇率，如 JPY/HKD 经美元三角换算
+func (a *App) GetCrossRate(base string, quote string) (*models.CrossRate, error) {
+	ctx, cancel := a.callContext()
+	defer cancel()
+	return a.cryptoForexAPI.GetCrossRate(ctx, base, quote)
+}
+
+// ConvertCurrency 按最新汇率换算金额
+func (a *App) ConvertCurrency(amount float64, from string, to string) (float64, error) {
+	ctx, cancel := a.callContext()
+	defer cancel()
+	return a.cryptoForexAPI.ConvertCurrency(ctx, amount, from, to)
+}
+
+// ========== 加密货币相关 ==========
+
+// GetMainCryptoCoins 获取主流加密货币列表
+func (a *App) GetMainCryptoCoins() []models.CryptoPrice {
+	return a.cryptoForexAPI.GetMainCryptoCoins()
+}
+
+// GetCryptoQuotes 获取加密货币行情（USDT计价），symbols为空时返回主流币种
+func (a *App) GetCryptoQuotes(symbols []string) ([]models.CryptoPrice, error) {
+	ctx, cancel := a.callContext()
+	defer cancel()
+	return a.cryptoForexAPI.GetCryptoQuotes(ctx, symbols)
+}
+
+// GetTopCryptoQuotes 获取24h成交额排名前N的加密货币
+func (a *App) GetTopCryptoQuotes(n int) ([]models.CryptoPrice, error) {
+	ctx, cancel := a.callContext()
+	defer cancel()
+	return a.cryptoForexAPI.GetTopCryptoQuotes(ctx, n)
+}
+
+// GetCryptoKLine 获取加密货币K线，interval支持 15m/1h/4h/1d/1w
+func (a *App) GetCryptoKLine(symbol string, interval string, count int) ([]models.KLineData, error) {
+	ctx, cancel := a.callContext()
+	defer cancel()
+	return a.cryptoForexAPI.GetCryptoKLine(ctx, symbol, interval, count)
+}
+
+// GetCryptoList 获取自选加密货币列表
+func (a *App) GetCryptoList() ([]models.CryptoCoin, error) {
+	var coins []models.CryptoCoin
+	err := data.GetDB().Find(&coins).Error
+	return coins, err
+}
+
+// AddCrypto 添加自选加密货币
+func (a *App) AddCrypto(symbol string, name string) error {
+	symbol = data.NormalizeCryptoSymbol(symbol)
+	if symbol == "" {
+		return fmt.Errorf("币种代码不能为空")
+	}
+	if name == "" {
+		name = data.CryptoName(symbol)
+	}
+
+	// 先检查是否存在（包括软删除的记录）
+	var existing models.CryptoCoin
+	if err := data.GetDB().Unscoped().Where("symbol = ?", symbol).First(&existing).Error; err == nil {
+		if existing.DeletedAt.Valid {
+			// 是软删除的记录，恢复它
+			return data.GetDB().Unscoped().Model(&existing).Updates(map[string]interface{}{
+				"deleted_at": nil,
+				"name":       name,
+			}).Error
+		}
+		return fmt.Errorf("币种 %s 已存在", symbol)
+	}
+
+	coin := models.CryptoCoin{
+		Symbol: symbol,
+		Name:   name,
+	}
+	return data.GetDB().Create(&coin).Error
+}
+
+// RemoveCrypto 删除自选加密货币
+func (a *App) RemoveCrypto(symbol string) error {
+	symbol = data.NormalizeCryptoSymbol(symbol)
+	return data.GetDB().Where("symbol = ?", symbol).Delete(&models.CryptoCoin{}).Error
+}
+
 // ========== 市场情绪 ==========
 
 // GetAShareSentiment 获取A股市场情绪
 func (a *App) GetAShareSentiment() (*data.MarketSentiment, error) {
-	return a.sentimentAPI.GetAShareSentiment()
+	ctx, cancel := a.callContext()
+	defer cancel()
+	return a.sentimentAPI.GetAShareSentiment(ctx)
 }
 
 // GetGlobalMarketSentiment 获取全球市场情绪
 func (a *App) GetGlobalMarketSentiment(country string) (*data.MarketSentiment, error) {
-	result, err := a.sentimentAPI.GetGlobalMarketSentiment(country)
+	ctx, cancel := a.callContext()
+	defer cancel()
+	result, err := a.sentimentAPI.GetGlobalMarketSentiment(ctx, country)
 	if err == nil && result != nil {
 		go data.GetPersistentCache().SaveGlobalSentiment(country, result)
 	}
@@ -857,57 +1041,114 @@ func (a *App) IsFirstLoad() bool {
 }
 
 // GetMarketIndex 获取市场指数
-func (a *App) GetMarketIndex() ([]models.MarketIndex, error) {
-	result, err := a.stockAPI.GetMarketIndex()
-	if err == nil && len(result) > 0 {
+func (a *App) GetMarketIndex() *models.MarketIndexResult {
+	ctx, cancel := a.callContext()
+	defer cancel()
+	result := a.stockAPI.GetMarketIndex(ctx)
+	if result.Meta.Freshness == models.FreshnessLive {
 		// 异步保存到持久化缓存
-		go data.GetPersistentCache().SaveMarketIndex(result)
+		go data.GetPersistentCache().SaveMarketIndex(result.Items)
 	}
-	return result, err
+	return result
 }
 
 // GetIndustryRank 获取行业排行
-func (a *App) GetIndustryRank() ([]models.IndustryRank, error) {
-	result, err := a.stockAPI.GetIndustryRank()
-	if err == nil && len(result) > 0 {
-		go data.GetPersistentCache().SaveIndustryRank(result)
+func (a *App) GetIndustryRank() *models.IndustryRankResult {
+	ctx, cancel := a.callContext()
+	defer cancel()
+	result := a.stockAPI.GetIndustryRank(ctx)
+	if result.Meta.Freshness == models.FreshnessLive {
+		go data.GetPersistentCache().SaveIndustryRank(result.Items)
 	}
-	return result, err
+	return result
 }
 
 // GetMoneyFlow 获取资金流向
-func (a *App) GetMoneyFlow() ([]models.MoneyFlow, error) {
-	result, err := a.stockAPI.GetMoneyFlow()
-	if err == nil && len(result) > 0 {
-		go data.GetPersistentCache().SaveMoneyFlow(result)
+func (a *App) GetMoneyFlow() *models.MoneyFlowResult {
+	ctx, cancel := a.callContext()
+	defer cancel()
+	result := a.stockAPI.GetMoneyFlow(ctx)
+	if result.Meta.Freshness == models.FreshnessLive {
+		go data.GetPersistentCache().SaveMoneyFlow(result.Items)
 	}
-	return result, err
+	return result
 }
 
 // GetNewsList 获取新闻快讯
-func (a *App) GetNewsList() ([]models.NewsItem, error) {
-	result, err := a.stockAPI.GetNewsList()
-	if err == nil && len(result) > 0 {
-		go data.GetPersistentCache().SaveNewsList(result)
+func (a *App) GetNewsList() *models.NewsListResult {
+	ctx, cancel := a.callContext()
+	defer cancel()
+	result := a.stockAPI.GetNewsList(ctx)
+	if result.Meta.Freshness == models.FreshnessLive {
+		go data.GetPersistentCache().SaveNewsList(result.Items)
 	}
-	return result, err
+	return result
 }
 
 // GetResearchReports 获取研报列表
 func (a *App) GetResearchReports(stockCode string) ([]models.ResearchReport, error) {
+	ctx, cancel := a.callContext()
+	defer cancel()
 	code := normalizeStockCode(stockCode)
-	return a.getResearchReportsCached(code)
+	return a.getResearchReportsCached(ctx, code)
+}
+
+// GetFinancialTrend 获取多期财务报表的趋势指标（单季同比环比、TTM、杜邦分解、应计比率、自由现金流）
+func (a *App) GetFinancialTrend(stockCode string) (*models.FinancialTrend, error) {
+	ctx, cancel := a.callContext()
+	defer cancel()
+	return data.GetFinancialTrend(ctx, normalizeStockCode(stockCode))
+}
+
+// GetValuationBands 获取PE-TTM/PB/PS-TTM的3/5/10年历史分位及行业中位数对比
+func (a *App) GetValuationBands(stockCode string) (*models.ValuationBands, error) {
+	ctx, cancel := a.callContext()
+	defer cancel()
+	return data.GetValuationBands(ctx, normalizeStockCode(stockCode))
+}
+
+// GetReportHistory 获取本地保存的研报历史（含标准化评级、目标价与EPS预测）
+func (a *App) GetReportHistory(stockCode string, limit int) ([]models.ReportRecord, error) {
+	ctx, cancel := a.callContext()
+	defer cancel()
+	code := normalizeStockCode(stockCode)
+	if err := data.SyncReportHistory(ctx, a.stockAPI, code); err != nil {
+		log.Printf("[研报跟踪] %s 同步失败: %v", code, err)
+	}
+	if limit <= 0 {
+		limit = 100
+	}
+	return data.ListReportHistory(code, limit)
+}
+
+// GetReportConsensus 获取研报一致预期：评级分布、评级上调/下调次数、一致目标价与隐含空间
+func (a *App) GetReportConsensus(stockCode string) (*models.ReportConsensus, error) {
+	ctx, cancel := a.callContext()
+	defer cancel()
+	code := normalizeStockCode(stockCode)
+	if err := data.SyncReportHistory(ctx, a.stockAPI, code); err != nil {
+		log.Printf("[研报跟踪] %s 同步失败: %v", code, err)
+	}
+	var price float64
+	if stock, err := a.getPriceSnapshot(ctx, code); err == nil && stock != nil {
+		price = stock.Price
+	}
+	return data.GetReportConsensus(code, price)
 }
 
 // GetStockNotices 获取公告列表
 func (a *App) GetStockNotices(stockCode string) ([]models.StockNotice, error) {
+	ctx, cancel := a.callContext()
+	defer cancel()
 	code := normalizeStockCode(stockCode)
-	return a.getStockNoticesCached(code)
+	return a.getStockNoticesCached(ctx, code)
 }
 
 // GetLongTigerRank 获取龙虎榜
 func (a *App) GetLongTigerRank() ([]models.LongTigerItem, error) {
-	result, err := a.stockAPI.GetLongTigerRank()
+	ctx, cancel := a.callContext()
+	defer cancel()
+	result, err := a.stockAPI.GetLongTigerRank(ctx)
 	if err == nil && len(result) > 0 {
 		go data.GetPersistentCache().SaveLongTigerRank(result)
 	}
@@ -915,12 +1156,55 @@ func (a *App) GetLongTigerRank() ([]models.LongTigerItem, error) {
 }
 
 // GetHotTopics 获取热门话题
-func (a *App) GetHotTopics() ([]models.HotTopic, error) {
-	result, err := a.stockAPI.GetHotTopics()
-	if err == nil && len(result) > 0 {
-		go data.GetPersistentCache().SaveHotTopics(result)
+func (a *App) GetHotTopics() *models.HotTopicResult {
+	ctx, cancel := a.callContext()
+	defer cancel()
+	result := a.stockAPI.GetHotTopics(ctx)
+	if result.Meta.Freshness == models.FreshnessLive {
+		go data.GetPersistentCache().SaveHotTopics(result.Items)
 	}
-	return result, err
+	return result
+}
+
+// SearchNews 检索已采集的新闻
+// query 为空格分隔的关键词；codes 为关联股票代码；from/to 格式为 2006-01-02 或 2006-01-02 15:04:05，可为空
+func (a *App) SearchNews(query string, codes []string, from string, to string) ([]models.NewsArticle, error) {
+	normalized := make([]string, 0, len(codes))
+	for _, code := range codes {
+		if code = normalizeStockCode(code); code != "" {
+			normalized = append(normalized, code)
+		}
+	}
+
+	fromTime, err := parseNewsSearchTime(from, false)
+	if err != nil {
+		return nil, err
+	}
+	toTime, err := parseNewsSearchTime(to, true)
+	if err != nil {
+		return nil, err
+	}
+	return data.SearchNews(strings.TrimSpace(query), normalized, fromTime, toTime)
+}
+
+// parseNewsSearchTime 解析检索时间；只给日期且为结束时间时取次日零点，使当天包含在内
+func parseNewsSearchTime(value string, end bool) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	loc := data.MarketLocation()
+	if t, err := time.ParseInLocation("2006-01-02 15:04:05", value, loc); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", value, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("无效的时间格式: %s", value)
+	}
+	if end {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
 }
 
 // ========== 配置相关 ==========
@@ -932,16 +1216,32 @@ func (a *App) GetConfig() (*models.Config, error) {
 	if err == nil {
 		// 更新请求管理器的配置
 		data.GetRequestManager().UpdateConfig(&config)
+		data.GetMultiSourceManager().SetQuoteValidation(config.QuoteValidationEnabled)
 	}
 	return &config, err
 }
 
 // SaveConfig 保存配置
 func (a *App) SaveConfig(config models.Config) error {
+	if config.LocalApiEnabled && strings.TrimSpace(config.LocalApiToken) == "" {
+		config.LocalApiToken = localapi.GenerateToken()
+	}
+	if strings.TrimSpace(config.PolicyRates) != "" {
+		if _, err := data.ParsePolicyRates(config.PolicyRates); err != nil {
+			return err
+		}
+		if _, err := time.Parse("2006-01", strings.TrimSpace(config.PolicyRatesAsOf)); err != nil {
+			return fmt.Errorf("政策利率截止月份格式应为 2006-01")
+		}
+	}
 	err := data.GetDB().Save(&config).Error
 	if err == nil {
 		// 更新请求管理器的配置
 		data.GetRequestManager().UpdateConfig(&config)
+		data.GetMultiSourceManager().SetQuoteValidation(config.QuoteValidationEnabled)
+		if apiErr := a.applyLocalAPIConfig(&config); apiErr != nil {
+			return fmt.Errorf("配置已保存，但本地API启动失败: %w", apiErr)
+		}
 	}
 	return err
 }
@@ -1008,10 +1308,14 @@ func (a *App) GetTradingTimeInfo() *TradingTimeInfo {
 func (a *App) GetDataPipelineStatus() (*models.DataPipelineStatus, error) {
 	msm := data.GetMultiSourceManager()
 	pipeline := &models.DataPipelineStatus{
-		MarketSources: msm.GetStatusList(),
-		Financial:     data.GetFinancialClient().GetDataSourceStatus(),
-		Proxy:         data.GetRequestManager().GetProxyStatus(),
-		GeneratedAt:   time.Now().Format(time.RFC3339),
+		MarketSources:   msm.GetStatusList(),
+		Financial:       data.GetFinancialClient().GetDataSourceStatus(),
+		Proxy:           data.GetRequestManager().GetProxyStatus(),
+		QuoteValidation: msm.GetQuoteValidationStats(),
+		SchemaHealth:    msm.GetSchemaHealth(),
+		RateLimits:      data.GetRequestManager().GetRateLimitStatus(),
+		Coalesce:        data.GetCoalesceStats(),
+		GeneratedAt:     time.Now().Format(time.RFC3339),
 	}
 	return pipeline, nil
 }
@@ -1024,18 +1328,25 @@ func (a *App) FrontendTrace(message string) {
 	appendTraceLog("[Frontend] %s", message)
 }
 
-func (a *App) startPriceCacheUpdater() {
+func (a *App) startPriceCacheUpdater(ctx context.Context) {
 	go func() {
 		for {
-			if err := a.refreshPriceCache(); err != nil {
+			if err := a.refreshPriceCache(ctx); err != nil {
 				log.Printf("[PriceCache] 刷新失败: %v", err)
 			}
-			time.Sleep(a.getPriceCacheInterval())
+			if _, err := a.checkCryptoAlerts(ctx); err != nil {
+				log.Printf("[PriceCache] 检查加密货币提醒失败: %v", err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(a.getPriceCacheInterval()):
+			}
 		}
 	}()
 }
 
-func (a *App) prefetchWatchlistData() {
+func (a *App) prefetchWatchlistData(ctx context.Context) {
 	stocks, err := a.GetStockList()
 	if err != nil || len(stocks) == 0 {
 		return
@@ -1062,7 +1373,7 @@ func (a *App) prefetchWatchlistData() {
 			defer wg.Done()
 			sem <- struct{}{}
 			defer func() { <-sem }()
-			a.prefetchStockData(code, cfg)
+			a.prefetchStockData(ctx, code, cfg)
 		}(code)
 	}
 
@@ -1070,34 +1381,28 @@ func (a *App) prefetchWatchlistData() {
 	log.Printf("[Prefetch] 自选股数据预加载完成")
 }
 
-func (a *App) prefetchStockData(stockCode string, cfg *models.Config) {
+func (a *App) prefetchStockData(ctx context.Context, stockCode string, cfg *models.Config) {
 	code := normalizeStockCode(stockCode)
 	if code == "" {
 		return
 	}
 
-	if prices, err := a.stockAPI.GetStockPrice([]string{code}); err == nil {
+	if prices, err := a.stockAPI.GetStockPrice(ctx, []string{code}); err == nil {
 		if price, ok := prices[code]; ok && price != nil {
-			a.stockPriceCacheLock.Lock()
-			a.stockPriceCache[code] = price
-			a.stockPriceCacheLock.Unlock()
+			a.stockPriceCache.Set(code, price)
 		}
 	}
 
-	if klines, err := a.stockAPI.GetKLineData(code, "daily", 120); err == nil && len(klines) > 0 {
+	if klines, err := a.stockAPI.GetKLineData(ctx, code, "daily", 120); err == nil && len(klines) > 0 {
 		a.setKLineCache(code, "daily", klines)
 	}
 
-	if reports, err := a.stockAPI.GetResearchReports(code); err == nil {
-		a.stockDataCacheLock.Lock()
-		a.stockReportCache[code] = reports
-		a.stockDataCacheLock.Unlock()
+	if reports, err := a.stockAPI.GetResearchReports(ctx, code); err == nil {
+		a.stockReportCache.Set(code, reports)
 	}
 
-	if notices, err := a.stockAPI.GetStockNotices(code); err == nil {
-		a.stockDataCacheLock.Lock()
-		a.stockNoticeCache[code] = notices
-		a.stockDataCacheLock.Unlock()
+	if notices, err := a.stockAPI.GetStockNotices(ctx, code); err == nil {
+		a.stockNoticeCache.Set(code, notices)
 	}
 
 	activeCfg := cfg
@@ -1107,10 +1412,8 @@ func (a *App) prefetchStockData(stockCode string, cfg *models.Config) {
 		}
 	}
 	if activeCfg != nil {
-		if fin, err := a.fetchFinancialData(code, activeCfg); err == nil && fin != nil {
-			a.stockDataCacheLock.Lock()
-			a.stockFinancialCache[code] = fin
-			a.stockDataCacheLock.Unlock()
+		if fin, err := a.fetchFinancialData(ctx, code, activeCfg); err == nil && fin != nil {
+			a.stockFinancialCache.Set(code, fin)
 		}
 	}
 }
@@ -1131,10 +1434,17 @@ type warmupStep struct {
 	err      error
 }
 
-func (a *App) warmupStockAnalysisData(code, analysisType string, cfg *models.Config, timeout time.Duration) *stockAnalysisData {
+func (a *App) warmupStockAnalysisData(ctx context.Context, code, analysisType string, cfg *models.Config, timeout time.Duration) *stockAnalysisData {
 	result := &stockAnalysisData{}
 	var wg sync.WaitGroup
 
+	// 超时后取消未完成的请求；与其他调用方合并的请求在所有调用方都离开后才会取消
+	cancel := context.CancelFunc(func() {})
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	}
+	defer cancel()
+
 	var steps []*warmupStep
 	var stepsMu sync.Mutex
 
@@ -1161,7 +1471,7 @@ func (a *App) warmupStockAnalysisData(code, analysisType string, cfg *models.Con
 	}
 
 	addTask("price", true, func() error {
-		stock, err := a.getPriceSnapshot(code)
+		stock, err := a.getPriceSnapshot(ctx, code)
 		if err == nil {
 			result.stock = stock
 		}
@@ -1169,7 +1479,7 @@ func (a *App) warmupStockAnalysisData(code, analysisType string, cfg *models.Con
 	})
 
 	addTask("kline", true, func() error {
-		klines, err := a.getKLineDataCached(code, 120)
+		klines, err := a.getKLineDataCached(ctx, code, 120)
 		if err == nil {
 			result.klines = klines
 		}
@@ -1177,7 +1487,7 @@ func (a *App) warmupStockAnalysisData(code, analysisType string, cfg *models.Con
 	})
 
 	addTask("reports", true, func() error {
-		reports, err := a.getResearchReportsCached(code)
+		reports, err := a.getResearchReportsCached(ctx, code)
 		if err == nil {
 			result.reports = reports
 		}
@@ -1185,7 +1495,7 @@ func (a *App) warmupStockAnalysisData(code, analysisType string, cfg *models.Con
 	})
 
 	addTask("notices", true, func() error {
-		notices, err := a.getStockNoticesCached(code)
+		notices, err := a.getStockNoticesCached(ctx, code)
 		if err == nil {
 			result.notices = notices
 		}
@@ -1202,7 +1512,7 @@ func (a *App) warmupStockAnalysisData(code, analysisType string, cfg *models.Con
 
 	needFinancial := analysisType == "fundamental" || analysisType == "master"
 	addTask("financial", needFinancial, func() error {
-		fin, err := a.getFinancialDataCached(code, cfg)
+		fin, err := a.getFinancialDataCached(ctx, code, cfg)
 		if err == nil {
 			result.financial = fin
 		}
@@ -1227,8 +1537,10 @@ func (a *App) warmupStockAnalysisData(code, analysisType string, cfg *models.Con
 	case <-done:
 		result.warmupDone = true
 		log.Printf("[Prefetch] 股票 %s 预热完成，总耗时 %s", code, time.Since(start))
-	case <-time.After(timeout):
+	case <-ctx.Done():
 		log.Printf("[Prefetch] 股票 %s 预热超过 %v，终止", code, timeout)
+		cancel()
+		<-done
 	}
 
 	return result
@@ -1245,7 +1557,7 @@ func (a *App) getPriceCacheInterval() time.Duration {
 	return time.Duration(interval) * time.Second
 }
 
-func (a *App) refreshPriceCache() error {
+func (a *App) refreshPriceCache(ctx context.Context) error {
 	codes := make(map[string]struct{})
 	fundCodes := make(map[string]struct{})
 
@@ -1277,18 +1589,17 @@ func (a *App) refreshPriceCache() error {
 			codeList = append(codeList, code)
 		}
 
-		prices, err := a.stockAPI.GetStockPrice(codeList)
+		prices, err := a.stockAPI.GetStockPrice(ctx, codeList)
 		if err != nil {
 			return err
 		}
 
-		a.stockPriceCacheLock.Lock()
 		for code, price := range prices {
 			if price != nil {
-				a.stockPriceCache[code] = price
+				a.stockPriceCache.Set(code, price)
 			}
 		}
-		a.stockPriceCacheLock.Unlock()
+		a.apiServer.Publish("stock-price-updated", prices)
 	}
 
 	var funds []models.Fund
@@ -1319,17 +1630,16 @@ func (a *App) refreshPriceCache() error {
 			codeList = append(codeList, code)
 		}
 
-		prices, err := a.fundAPI.GetFundPrice(codeList)
+		prices, err := a.fundAPI.GetFundPrice(ctx, codeList)
 		if err != nil {
 			log.Printf("[PriceCache] 获取基金估值失败: %v", err)
 		} else {
-			a.fundPriceCacheLock.Lock()
 			for code, price := range prices {
 				if price != nil {
-					a.fundPriceCache[code] = price
+					a.fundPriceCache.Set(code, price)
 				}
 			}
-			a.fundPriceCacheLock.Unlock()
+			a.apiServer.Publish("fund-price-updated", prices)
 		}
 	}
 
@@ -1361,15 +1671,12 @@ func isAllowedWebContentURL(target string) bool {
 	return true
 }
 
-func (a *App) getPriceSnapshot(code string) (*models.StockPrice, error) {
-	a.stockPriceCacheLock.RLock()
-	if price, ok := a.stockPriceCache[code]; ok && price != nil {
-		a.stockPriceCacheLock.RUnlock()
+func (a *App) getPriceSnapshot(ctx context.Context, code string) (*models.StockPrice, error) {
+	if price, ok := a.stockPriceCache.Get(code); ok && price != nil {
 		return price, nil
 	}
-	a.stockPriceCacheLock.RUnlock()
 
-	prices, err := a.stockAPI.GetStockPrice([]string{code})
+	prices, err := a.stockAPI.GetStockPrice(ctx, []string{code})
 	if err != nil {
 		return nil, err
 	}
@@ -1379,17 +1686,15 @@ func (a *App) getPriceSnapshot(code string) (*models.StockPrice, error) {
 		return nil, fmt.Errorf("未找到股票: %s", code)
 	}
 
-	a.stockPriceCacheLock.Lock()
-	a.stockPriceCache[code] = price
-	a.stockPriceCacheLock.Unlock()
+	a.stockPriceCache.Set(code, price)
 	return price, nil
 }
 
-func (a *App) getKLineDataCached(code string, count int) ([]models.KLineData, error) {
-	return a.getKLineDataCachedByPeriod(code, "daily", count)
+func (a *App) getKLineDataCached(ctx context.Context, code string, count int) ([]models.KLineData, error) {
+	return a.getKLineDataCachedByPeriod(ctx, code, "daily", count)
 }
 
-func (a *App) getKLineDataCachedByPeriod(code, period string, count int) ([]models.KLineData, error) {
+func (a *App) getKLineDataCachedByPeriod(ctx context.Context, code, period string, count int) ([]models.KLineData, error) {
 	if klines, ok := a.getCachedKLines(code, period, count); ok {
 		log.Printf("[KLine] getKLineDataCached 命中缓存 %s %s -> %d 条", code, period, len(klines))
 		return klines, nil
@@ -1400,7 +1705,7 @@ func (a *App) getKLineDataCachedByPeriod(code, period string, count int) ([]mode
 		limit = min
 	}
 
-	klines, err := a.stockAPI.GetKLineData(code, period, limit)
+	klines, err := a.stockAPI.GetKLineData(ctx, code, period, limit)
 	if err != nil {
 		if cached, ok := a.getCachedKLines(code, period, count); ok {
 			return cached, nil
@@ -1429,13 +1734,14 @@ func getMinKLineFetchCount(period string) int {
 	}
 }
 
+// klineCacheKey K线缓存键，以代码开头便于按股票整体清理
+func klineCacheKey(code, period string) string {
+	return code + "|" + period
+}
+
 func (a *App) getCachedKLines(code string, period string, count int) ([]models.KLineData, bool) {
-	a.stockDataCacheLock.RLock()
-	defer a.stockDataCacheLock.RUnlock()
-	if cache, ok := a.stockKLineCache[code]; ok {
-		if klines, ok := cache[period]; ok && len(klines) > 0 {
-			return cloneKLines(klines, count), true
-		}
+	if klines, ok := a.stockKLineCache.Get(klineCacheKey(code, period)); ok && len(klines) > 0 {
+		return cloneKLines(klines, count), true
 	}
 	return nil, false
 }
@@ -1446,99 +1752,70 @@ func (a *App) setKLineCache(code string, period string, klines []models.KLineDat
 	}
 	copied := make([]models.KLineData, len(klines))
 	copy(copied, klines)
-	a.stockDataCacheLock.Lock()
-	if _, ok := a.stockKLineCache[code]; !ok {
-		a.stockKLineCache[code] = make(map[string][]models.KLineData)
-	}
-	a.stockKLineCache[code][period] = copied
-	a.stockDataCacheLock.Unlock()
+	a.stockKLineCache.Set(klineCacheKey(code, period), copied)
 }
 
-func (a *App) getResearchReportsCached(code string) ([]models.ResearchReport, error) {
-	a.stockDataCacheLock.RLock()
-	if reports, ok := a.stockReportCache[code]; ok && len(reports) > 0 {
+func (a *App) getResearchReportsCached(ctx context.Context, code string) ([]models.ResearchReport, error) {
+	if reports, ok := a.stockReportCache.Get(code); ok && len(reports) > 0 {
 		copied := make([]models.ResearchReport, len(reports))
 		copy(copied, reports)
-		a.stockDataCacheLock.RUnlock()
 		return copied, nil
 	}
-	a.stockDataCacheLock.RUnlock()
 
-	reports, err := a.stockAPI.GetResearchReports(code)
+	reports, err := a.stockAPI.GetResearchReports(ctx, code)
 	if err != nil {
-		a.stockDataCacheLock.RLock()
-		if cached, ok := a.stockReportCache[code]; ok && len(cached) > 0 {
+		if cached, ok := a.stockReportCache.Get(code); ok && len(cached) > 0 {
 			copied := make([]models.ResearchReport, len(cached))
 			copy(copied, cached)
-			a.stockDataCacheLock.RUnlock()
 			return copied, nil
 		}
-		a.stockDataCacheLock.RUnlock()
 		return nil, err
 	}
 	if len(reports) == 0 {
-		a.stockDataCacheLock.RLock()
-		if cached, ok := a.stockReportCache[code]; ok && len(cached) > 0 {
+		if cached, ok := a.stockReportCache.Get(code); ok && len(cached) > 0 {
 			copied := make([]models.ResearchReport, len(cached))
 			copy(copied, cached)
-			a.stockDataCacheLock.RUnlock()
 			return copied, nil
 		}
-		a.stockDataCacheLock.RUnlock()
 		return reports, nil
 	}
-	a.stockDataCacheLock.Lock()
-	a.stockReportCache[code] = reports
-	a.stockDataCacheLock.Unlock()
+	a.stockReportCache.Set(code, reports)
+	go data.SaveReportHistory(code, reports)
 	return reports, nil
 }
 
-func (a *App) getStockNoticesCached(code string) ([]models.StockNotice, error) {
-	a.stockDataCacheLock.RLock()
-	if notices, ok := a.stockNoticeCache[code]; ok && len(notices) > 0 {
+func (a *App) getStockNoticesCached(ctx context.Context, code string) ([]models.StockNotice, error) {
+	if notices, ok := a.stockNoticeCache.Get(code); ok && len(notices) > 0 {
 		copied := make([]models.StockNotice, len(notices))
 		copy(copied, notices)
-		a.stockDataCacheLock.RUnlock()
 		return copied, nil
 	}
-	a.stockDataCacheLock.RUnlock()
 
-	notices, err := a.stockAPI.GetStockNotices(code)
+	notices, err := a.stockAPI.GetStockNotices(ctx, code)
 	if err != nil {
-		a.stockDataCacheLock.RLock()
-		if cached, ok := a.stockNoticeCache[code]; ok && len(cached) > 0 {
+		if cached, ok := a.stockNoticeCache.Get(code); ok && len(cached) > 0 {
 			copied := make([]models.StockNotice, len(cached))
 			copy(copied, cached)
-			a.stockDataCacheLock.RUnlock()
 			return copied, nil
 		}
-		a.stockDataCacheLock.RUnlock()
 		return nil, err
 	}
 	if len(notices) == 0 {
-		a.stockDataCacheLock.RLock()
-		if cached, ok := a.stockNoticeCache[code]; ok && len(cached) > 0 {
+		if cached, ok := a.stockNoticeCache.Get(code); ok && len(cached) > 0 {
 			copied := make([]models.StockNotice, len(cached))
 			copy(copied, cached)
-			a.stockDataCacheLock.RUnlock()
 			return copied, nil
 		}
-		a.stockDataCacheLock.RUnlock()
 		return notices, nil
 	}
-	a.stockDataCacheLock.Lock()
-	a.stockNoticeCache[code] = notices
-	a.stockDataCacheLock.Unlock()
+	a.stockNoticeCache.Set(code, notices)
 	return notices, nil
 }
 
-func (a *App) getFinancialDataCached(code string, cfg *models.Config) (*data.FinancialData, error) {
-	a.stockDataCacheLock.RLock()
-	if fin, ok := a.stockFinancialCache[code]; ok && fin != nil {
-		a.stockDataCacheLock.RUnlock()
+func (a *App) getFinancialDataCached(ctx context.Context, code string, cfg *models.Config) (*data.FinancialData, error) {
+	if fin, ok := a.stockFinancialCache.Get(code); ok && fin != nil {
 		return fin, nil
 	}
-	a.stockDataCacheLock.RUnlock()
 
 	activeCfg := cfg
 	if activeCfg == nil {
@@ -1549,14 +1826,12 @@ func (a *App) getFinancialDataCached(code string, cfg *models.Config) (*data.Fin
 		}
 	}
 
-	fin, err := a.fetchFinancialData(code, activeCfg)
+	fin, err := a.fetchFinancialData(ctx, code, activeCfg)
 	if err != nil {
 		return nil, err
 	}
 	if fin != nil {
-		a.stockDataCacheLock.Lock()
-		a.stockFinancialCache[code] = fin
-		a.stockDataCacheLock.Unlock()
+		a.stockFinancialCache.Set(code, fin)
 	}
 	return fin, nil
 }
@@ -1587,13 +1862,9 @@ func (a *App) lookupStockName(code string) string {
 		}
 	}
 
-	a.stockPriceCacheLock.RLock()
-	if cached, ok := a.stockPriceCache[code]; ok && cached != nil && strings.TrimSpace(cached.Name) != "" {
-		name := cached.Name
-		a.stockPriceCacheLock.RUnlock()
-		return name
+	if cached, ok := a.stockPriceCache.Get(code); ok && cached != nil && strings.TrimSpace(cached.Name) != "" {
+		return cached.Name
 	}
-	a.stockPriceCacheLock.RUnlock()
 	return strings.ToUpper(code)
 }
 
@@ -1628,8 +1899,8 @@ func (a *App) buildPriceFromKLines(code string, daily []models.KLineData) *model
 	}
 }
 
-func (a *App) resolveTradeLevelPrice(code string, daily []models.KLineData) (*models.StockPrice, error) {
-	prices, err := a.fetchStockPriceWithTimeout(code, tradeLevelPriceTimeout)
+func (a *App) resolveTradeLevelPrice(ctx context.Context, code string, daily []models.KLineData) (*models.StockPrice, error) {
+	prices, err := a.fetchStockPriceWithTimeout(ctx, code, tradeLevelPriceTimeout)
 	if err != nil {
 		appendTraceLog("[TradeLevel] price realtime err %s: %v", code, err)
 	} else {
@@ -1649,13 +1920,10 @@ func (a *App) resolveTradeLevelPrice(code string, daily []models.KLineData) (*mo
 		return nil, fmt.Errorf("实时行情不可用，请稍后再试: %v", err)
 	}
 
-	a.stockPriceCacheLock.RLock()
-	if cached, ok := a.stockPriceCache[code]; ok && cached != nil {
-		a.stockPriceCacheLock.RUnlock()
+	if cached, ok := a.stockPriceCache.Get(code); ok && cached != nil {
 		appendTraceLog("[TradeLevel] price fallback cache %s", code)
 		return cached, nil
 	}
-	a.stockPriceCacheLock.RUnlock()
 
 	if fallback := a.buildPriceFromKLines(code, daily); fallback != nil {
 		appendTraceLog("[TradeLevel] price fallback kline %s", code)
@@ -1664,106 +1932,18 @@ func (a *App) resolveTradeLevelPrice(code string, daily []models.KLineData) (*mo
 	return nil, fmt.Errorf("无法获取股票实时价格，请稍后再试")
 }
 
-func (a *App) fetchStockPriceWithTimeout(code string, timeout time.Duration) (map[string]*models.StockPrice, error) {
-	type priceResult struct {
-		price  *models.StockPrice
-		source string
-		err    error
-	}
-
-	sources := []struct {
-		name  string
-		fetch func([]string) (map[string]*models.StockPrice, error)
-	}{
-		{"Primary", a.stockAPI.GetStockPrice},
-		{"Tencent", a.stockAPI.GetStockPriceFromTencent},
-		{"Eastmoney", a.stockAPI.GetStockPriceFromEastmoney},
-		{"Netease", a.stockAPI.GetStockPriceFromNetease},
-		{"Sohu", a.stockAPI.GetStockPriceFromSohu},
-		{"Xueqiu", a.stockAPI.GetStockPriceFromXueqiu},
-		{"Baidu", a.stockAPI.GetStockPriceFromBaidu},
-		{"Hexun", a.stockAPI.GetStockPriceFromHexun},
-	}
-
-	var (
-		ctx    context.Context
-		cancel context.CancelFunc
-	)
-	if timeout > 0 {
-		ctx, cancel = context.WithTimeout(context.Background(), timeout)
-	} else {
-		ctx, cancel = context.WithCancel(context.Background())
-	}
-	defer cancel()
-
-	resultCh := make(chan priceResult, len(sources))
-
-	var wg sync.WaitGroup
-	for _, src := range sources {
-		src := src
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			defer func() {
-				if r := recover(); r != nil {
-					select {
-					case <-ctx.Done():
-					case resultCh <- priceResult{err: fmt.Errorf("panic: %v", r), source: src.name}:
-					}
-				}
-			}()
-			prices, err := src.fetch([]string{code})
-			res := priceResult{source: src.name}
-			if err != nil {
-				res.err = err
-			} else if prices != nil {
-				if price := prices[code]; price != nil {
-					res.price = price
-				} else {
-					res.err = fmt.Errorf("%s 返回空数据", src.name)
-				}
-			} else {
-				res.err = fmt.Errorf("%s 未返回数据", src.name)
-			}
-			select {
-			case <-ctx.Done():
-				return
-			case resultCh <- res:
-			}
-		}()
+func (a *App) fetchStockPriceWithTimeout(ctx context.Context, code string, timeout time.Duration) (map[string]*models.StockPrice, error) {
+	prices, source, err := a.stockAPI.GetStockPriceWithTimeout(ctx, []string{code}, timeout)
+	if err != nil {
+		appendTraceLog("[TradeLevel] price source err %s: %v", code, err)
+		return nil, err
 	}
-
-	go func() {
-		wg.Wait()
-		close(resultCh)
-	}()
-
-	var lastErr error
-	for {
-		select {
-		case <-ctx.Done():
-			if lastErr == nil {
-				lastErr = fmt.Errorf("所有数据源均超时或无结果")
-			}
-			return nil, lastErr
-		case res, ok := <-resultCh:
-			if !ok {
-				if lastErr == nil {
-					lastErr = fmt.Errorf("所有数据源均无结果")
-				}
-				return nil, lastErr
-			}
-			if res.price != nil {
-				appendTraceLog("[TradeLevel] price realtime via %s", res.source)
-				cancel()
-				return map[string]*models.StockPrice{code: res.price}, nil
-			}
-			if res.err != nil {
-				lastErr = res.err
-				appendTraceLog("[TradeLevel] price source err %s %s: %v", code, res.source, res.err)
-			}
-		}
+	price := prices[code]
+	if price == nil {
+		return nil, fmt.Errorf("%s 返回空数据", source)
 	}
+	appendTraceLog("[TradeLevel] price realtime via %s", source)
+	return map[string]*models.StockPrice{code: price}, nil
 }
 
 func logTradeLevelFail(code, format string, args ...interface{}) {
@@ -1772,6 +1952,7 @@ func logTradeLevelFail(code, format string, args ...interface{}) {
 }
 
 func (a *App) PrefetchTradeLevelData(code string) {
+	ctx := a.backgroundContext(data.PriorityPrefetch)
 	code = normalizeStockCode(code)
 	appendTraceLog("[TradeLevel] Prefetch request %s", code)
 	if code == "" {
@@ -1785,7 +1966,7 @@ func (a *App) PrefetchTradeLevelData(code string) {
 			{period: "month", count: 180},
 		}
 		for _, spec := range specs {
-			if _, err := a.getKLineDataCachedByPeriod(stockCode, spec.period, spec.count); err != nil {
+			if _, err := a.getKLineDataCachedByPeriod(ctx, stockCode, spec.period, spec.count); err != nil {
 				log.Printf("[Prefetch] %s %s ????: %v", stockCode, spec.period, err)
 				appendTraceLog("[TradeLevel] Prefetch fail %s %s: %v", stockCode, spec.period, err)
 			} else {
@@ -1798,19 +1979,14 @@ func (a *App) PrefetchTradeLevelData(code string) {
 }
 
 func (a *App) clearStockCaches(code string) {
-	a.stockPriceCacheLock.Lock()
-	delete(a.stockPriceCache, code)
-	a.stockPriceCacheLock.Unlock()
-
-	a.stockDataCacheLock.Lock()
-	delete(a.stockKLineCache, code)
-	delete(a.stockReportCache, code)
-	delete(a.stockNoticeCache, code)
-	delete(a.stockFinancialCache, code)
-	a.stockDataCacheLock.Unlock()
+	a.stockPriceCache.Delete(code)
+	a.stockKLineCache.DeletePrefix(klineCacheKey(code, ""))
+	a.stockReportCache.Delete(code)
+	a.stockNoticeCache.Delete(code)
+	a.stockFinancialCache.Delete(code)
 }
 
-func (a *App) fetchFinancialData(code string, cfg *models.Config) (*data.FinancialData, error) {
+func (a *App) fetchFinancialData(ctx context.Context, code string, cfg *models.Config) (*data.FinancialData, error) {
 	if cfg == nil {
 		return nil, fmt.Errorf("配置不可用")
 	}
@@ -1818,8 +1994,13 @@ func (a *App) fetchFinancialData(code string, cfg *models.Config) (*data.Financi
 	if cfg.TushareToken != "" {
 		financialClient.SetTushareToken(cfg.TushareToken)
 	}
-	financialClient.SetPreferTushare(cfg.DataSourcePriority != "akshare")
-	return financialClient.GetFinancialData(code)
+	priority := cfg.DataSourcePriority
+	if priority == "" {
+		priority = "tushare"
+	}
+	financialClient.SetPreferredProvider(priority)
+	data.GetAKShareClient().SetPythonFallback(cfg.AksharePythonEnabled)
+	return financialClient.GetFinancialData(ctx, code)
 }
 
 // ClearCache 清除缓存
@@ -2188,6 +2369,8 @@ func (a *App) OpenURL(url string) {
 
 // AIChat AI对话（非流式）
 func (a *App) AIChat(request models.AIChatRequest) (*models.AIChatResponse, error) {
+	ctx, cancel := a.callContext()
+	defer cancel()
 	// 检查AI是否启用
 	var config models.Config
 	if err := data.GetDB().First(&config).Error; err != nil {
@@ -2215,7 +2398,7 @@ func (a *App) AIChat(request models.AIChatRequest) (*models.AIChatResponse, erro
 
 	// 如果指定了股票或基金代码，添加上下文
 	if request.StockCode != "" {
-		stockContext, err := a.buildStockContext(request.StockCode)
+		stockContext, err := a.buildStockContext(ctx, request.StockCode)
 		if err == nil && stockContext != "" {
 			messages = []data.ChatMessage{
 				{Role: "system", Content: data.BuildChatSystemPrompt()},
@@ -2247,6 +2430,7 @@ func (a *App) AIChat(request models.AIChatRequest) (*models.AIChatResponse, erro
 
 // AIChatStream AI对话（流式）- 通过事件推送
 func (a *App) AIChatStream(request models.AIChatRequest) error {
+	ctx := a.lifetimeContext()
 	// 检查AI是否启用
 	var config models.Config
 	if err := data.GetDB().First(&config).Error; err != nil {
@@ -2291,7 +2475,7 @@ func (a *App) AIChatStream(request models.AIChatRequest) error {
 
 	// 如果指定了股票或基金代码，添加上下文
 	if request.StockCode != "" {
-		stockContext, err := a.buildStockContext(request.StockCode)
+		stockContext, err := a.buildStockContext(ctx, request.StockCode)
 		if err == nil && stockContext != "" {
 			messages = []data.ChatMessage{
 				{Role: "system", Content: data.BuildChatSystemPrompt()},
@@ -2310,7 +2494,7 @@ func (a *App) AIChatStream(request models.AIChatRequest) error {
 
 	// 启动流式调用
 	go func() {
-		ch, err := a.aiClient.ChatStream(messages)
+		ch, err := a.aiClient.ChatStream(ctx, messages)
 		if err != nil {
 			wailsRuntime.EventsEmit(a.ctx, "ai-chat-error", err.Error())
 			return
@@ -2338,6 +2522,8 @@ func (a *App) AIChatStream(request models.AIChatRequest) error {
 
 // AIAnalyzeStock AI分析股票
 func (a *App) AIAnalyzeStock(code string) (*models.AIChatResponse, error) {
+	ctx, cancel := a.callContext()
+	defer cancel()
 	// 标准化股票代码
 	code = normalizeStockCode(code)
 
@@ -2361,7 +2547,7 @@ func (a *App) AIAnalyzeStock(code string) (*models.AIChatResponse, error) {
 	}
 
 	// 获取股票数据
-	prices, err := a.stockAPI.GetStockPrice([]string{code})
+	prices, err := a.stockAPI.GetStockPrice(ctx, []string{code})
 	if err != nil {
 		return nil, fmt.Errorf("获取股票价格失败: %v", err)
 	}
@@ -2372,13 +2558,13 @@ func (a *App) AIAnalyzeStock(code string) (*models.AIChatResponse, error) {
 	}
 
 	// 获取K线数据
-	klines, _ := a.stockAPI.GetKLineData(code, "daily", 30)
+	klines, _ := a.stockAPI.GetKLineData(ctx, code, "daily", 30)
 
 	// 获取研报
-	reports, _ := a.stockAPI.GetResearchReports(code)
+	reports, _ := a.stockAPI.GetResearchReports(ctx, code)
 
 	// 获取公告
-	notices, _ := a.stockAPI.GetStockNotices(code)
+	notices, _ := a.stockAPI.GetStockNotices(ctx, code)
 
 	// 构建分析提示词
 	prompt := data.BuildStockAnalysisPrompt(stock, klines, reports, notices)
@@ -2402,6 +2588,7 @@ func (a *App) AIAnalyzeStock(code string) (*models.AIChatResponse, error) {
 
 // AIAnalyzeStockStream AI分析股票（流式）
 func (a *App) AIAnalyzeStockStream(code string) error {
+	ctx := a.lifetimeContext()
 	// 标准化股票代码
 	code = normalizeStockCode(code)
 
@@ -2431,7 +2618,7 @@ func (a *App) AIAnalyzeStockStream(code string) error {
 		// 获取股票数据
 		wailsRuntime.EventsEmit(a.ctx, "ai-chat-stream", "正在获取股票数据...\n\n")
 
-		prices, err := a.stockAPI.GetStockPrice([]string{code})
+		prices, err := a.stockAPI.GetStockPrice(ctx, []string{code})
 		if err != nil {
 			wailsRuntime.EventsEmit(a.ctx, "ai-chat-error", "获取股票价格失败")
 			return
@@ -2444,13 +2631,13 @@ func (a *App) AIAnalyzeStockStream(code string) error {
 		}
 
 		// 获取K线数据
-		klines, _ := a.stockAPI.GetKLineData(code, "daily", 30)
+		klines, _ := a.stockAPI.GetKLineData(ctx, code, "daily", 30)
 
 		// 获取研报
-		reports, _ := a.stockAPI.GetResearchReports(code)
+		reports, _ := a.stockAPI.GetResearchReports(ctx, code)
 
 		// 获取公告
-		notices, _ := a.stockAPI.GetStockNotices(code)
+		notices, _ := a.stockAPI.GetStockNotices(ctx, code)
 
 		// 构建分析提示词
 		prompt := data.BuildStockAnalysisPrompt(stock, klines, reports, notices)
@@ -2461,7 +2648,7 @@ func (a *App) AIAnalyzeStockStream(code string) error {
 			{Role: "user", Content: prompt},
 		}
 
-		ch, err := a.aiClient.ChatStream(messages)
+		ch, err := a.aiClient.ChatStream(ctx, messages)
 		if err != nil {
 			wailsRuntime.EventsEmit(a.ctx, "ai-chat-error", err.Error())
 			return
@@ -2478,6 +2665,8 @@ func (a *App) AIAnalyzeStockStream(code string) error {
 
 // AIAnalyzeTradeLevels 计算AI买卖区间
 func (a *App) AIAnalyzeTradeLevels(code string) (*models.TradeLevelResult, error) {
+	ctx, cancel := a.callContext()
+	defer cancel()
 	code = normalizeStockCode(code)
 	appendTraceLog("[TradeLevel] start %s", code)
 
@@ -2512,7 +2701,7 @@ func (a *App) AIAnalyzeTradeLevels(code string) (*models.TradeLevelResult, error
 		wg.Add(1)
 		go func(s klineFetchSpec) {
 			defer wg.Done()
-			klines, err := a.getKLineDataCachedByPeriod(code, s.period, s.count)
+			klines, err := a.getKLineDataCachedByPeriod(ctx, code, s.period, s.count)
 			if err != nil {
 				log.Printf("[AI] 获取 %s K线失败: %v", s.period, err)
 				return
@@ -2533,7 +2722,7 @@ func (a *App) AIAnalyzeTradeLevels(code string) (*models.TradeLevelResult, error
 		return nil, fmt.Errorf("暂时无法获取该股票的K线数据，请稍后重试")
 	}
 
-	stock, err := a.resolveTradeLevelPrice(code, daily)
+	stock, err := a.resolveTradeLevelPrice(ctx, code, daily)
 	if err != nil {
 		logTradeLevelFail(code, "获取价格失败: %v", err)
 		return nil, err
@@ -2565,6 +2754,7 @@ func (a *App) AIAnalyzeTradeLevels(code string) (*models.TradeLevelResult, error
 
 // AIAnalyzeFundStream AI分析基金（流式）
 func (a *App) AIAnalyzeFundStream(code string) error {
+	ctx := a.lifetimeContext()
 	code = strings.TrimSpace(code)
 	if code == "" {
 		return fmt.Errorf("请输入基金代码")
@@ -2608,7 +2798,7 @@ func (a *App) AIAnalyzeFundStream(code string) error {
 			{Role: "user", Content: prompt},
 		}
 
-		ch, err := a.aiClient.ChatStream(messages)
+		ch, err := a.aiClient.ChatStream(ctx, messages)
 		if err != nil {
 			wailsRuntime.EventsEmit(a.ctx, "ai-chat-error", err.Error())
 			return
@@ -2625,6 +2815,8 @@ func (a *App) AIAnalyzeFundStream(code string) error {
 
 // AIRecommend AI选股推荐
 func (a *App) AIRecommend() (*models.AIChatResponse, error) {
+	ctx, cancel := a.callContext()
+	defer cancel()
 	// 检查AI是否启用
 	var config models.Config
 	if err := data.GetDB().First(&config).Error; err != nil {
@@ -2645,12 +2837,15 @@ func (a *App) AIRecommend() (*models.AIChatResponse, error) {
 	}
 
 	// 获取市场数据
-	indexes, _ := a.stockAPI.GetMarketIndex()
-	industries, _ := a.stockAPI.GetIndustryRank()
-	moneyFlow, _ := a.stockAPI.GetMoneyFlow()
+	indexes := a.stockAPI.GetMarketIndex(ctx)
+	industries := a.stockAPI.GetIndustryRank(ctx)
+	moneyFlow := a.stockAPI.GetMoneyFlow(ctx)
 
 	// 构建推荐提示词
-	prompt := data.BuildRecommendPrompt(indexes, industries, moneyFlow)
+	prompt, err := data.BuildRecommendPrompt(indexes, industries, moneyFlow)
+	if err != nil {
+		return nil, err
+	}
 
 	// 调用AI
 	messages := []data.ChatMessage{
@@ -2669,8 +2864,12 @@ func (a *App) AIRecommend() (*models.AIChatResponse, error) {
 	}, nil
 }
 
+// marketCacheFreshFor 本地缓存的市场数据在该时长内视为有效，超过后在提示词中标注可能过时
+const marketCacheFreshFor = 30 * time.Minute
+
 // AIRecommendStream AI选股推荐（流式）
 func (a *App) AIRecommendStream() error {
+	ctx := a.lifetimeContext()
 	// 检查AI是否启用
 	var config models.Config
 	if err := data.GetDB().First(&config).Error; err != nil {
@@ -2708,28 +2907,40 @@ func (a *App) AIRecommendStream() error {
 		wailsRuntime.EventsEmit(a.ctx, "ai-chat-stream", "正在分析市场数据...\n\n")
 
 		// 优先从本地缓存获取市场数据，避免重新请求
-		var indexes []models.MarketIndex
-		var industries []models.IndustryRank
-		var moneyFlow []models.MoneyFlow
+		var indexes *models.MarketIndexResult
+		var industries *models.IndustryRankResult
+		var moneyFlow *models.MoneyFlowResult
 
 		pc := data.GetPersistentCache()
 		cached, err := pc.LoadCache()
 		if err == nil && cached != nil && len(cached.MarketIndex) > 0 {
-			// 使用缓存数据
-			indexes = cached.MarketIndex
-			industries = cached.IndustryRank
-			moneyFlow = cached.MoneyFlow
+			// 使用缓存数据（缓存中只保存实时获取成功的数据）
+			meta := models.DataMeta{
+				Source:    "local-cache",
+				FetchedAt: cached.CacheTime.Format("2006-01-02 15:04:05"),
+				Freshness: models.FreshnessCached,
+			}
+			if time.Since(cached.CacheTime) > marketCacheFreshFor {
+				meta.Freshness = models.FreshnessStale
+			}
+			indexes = &models.MarketIndexResult{Items: cached.MarketIndex, Meta: meta}
+			industries = &models.IndustryRankResult{Items: cached.IndustryRank, Meta: meta}
+			moneyFlow = &models.MoneyFlowResult{Items: cached.MoneyFlow, Meta: meta}
 			log.Printf("[AI推荐] 使用本地缓存数据，缓存时间: %v", cached.CacheTime)
 		} else {
 			// 缓存不可用，重新获取
 			log.Printf("[AI推荐] 缓存不可用，重新获取数据")
-			indexes, _ = a.stockAPI.GetMarketIndex()
-			industries, _ = a.stockAPI.GetIndustryRank()
-			moneyFlow, _ = a.stockAPI.GetMoneyFlow()
+			indexes = a.stockAPI.GetMarketIndex(ctx)
+			industries = a.stockAPI.GetIndustryRank(ctx)
+			moneyFlow = a.stockAPI.GetMoneyFlow(ctx)
 		}
 
 		// 构建推荐提示词
-		prompt := data.BuildRecommendPrompt(indexes, industries, moneyFlow)
+		prompt, err := data.BuildRecommendPrompt(indexes, industries, moneyFlow)
+		if err != nil {
+			wailsRuntime.EventsEmit(a.ctx, "ai-chat-error", err.Error())
+			return
+		}
 
 		// 调用AI
 		messages := []data.ChatMessage{
@@ -2737,7 +2948,7 @@ func (a *App) AIRecommendStream() error {
 			{Role: "user", Content: prompt},
 		}
 
-		ch, err := a.aiClient.ChatStream(messages)
+		ch, err := a.aiClient.ChatStream(ctx, messages)
 		if err != nil {
 			wailsRuntime.EventsEmit(a.ctx, "ai-chat-error", err.Error())
 			return
@@ -2767,6 +2978,7 @@ func (a *App) AIRecommendStream() error {
 // AISummarizeContent AI摘要内容（流式）
 // 参数: title, contentType, pageURL, infoCode(研报), artCode(公告), stockCode, manualContent
 func (a *App) AISummarizeContentStream(title string, contentType string, pageURL string, infoCode string, artCode string, stockCode string, manualContent string) error {
+	ctx := a.lifetimeContext()
 	log.Printf("[AI摘要] 开始处理: title=%s, type=%s, url=%s, infoCode=%s, artCode=%s", title, contentType, pageURL, infoCode, artCode)
 
 	// 检查AI是否启用
@@ -2837,7 +3049,7 @@ func (a *App) AISummarizeContentStream(title string, contentType string, pageURL
 			} else if contentType == "notice" && artCode != "" {
 				log.Printf("[AI摘要] 尝试API获取公告...")
 				wailsRuntime.EventsEmit(a.ctx, "ai-summary-stream", "尝试通过API获取公告内容...\n")
-				content, err := a.stockAPI.GetNoticeContent(stockCode, artCode)
+				content, err := a.stockAPI.GetNoticeContent(ctx, stockCode, artCode)
 				if err == nil && content != "" {
 					webContent = content
 					fetchMethod = "东方财富API"
@@ -2955,7 +3167,7 @@ func (a *App) AISummarizeContentStream(title string, contentType string, pageURL
 		}
 
 		log.Printf("[AI摘要] 开始调用ChatStream...")
-		ch, err := a.aiClient.ChatStream(messages)
+		ch, err := a.aiClient.ChatStream(ctx, messages)
 		if err != nil {
 			log.Printf("[AI摘要] ChatStream失败: %v", err)
 			wailsRuntime.EventsEmit(a.ctx, "ai-summary-error", err.Error())
@@ -2987,17 +3199,17 @@ func (a *App) AISummarizeContentStream(title string, contentType string, pageURL
 }
 
 // buildStockContext 构建股票上下文
-func (a *App) buildStockContext(code string) (string, error) {
+func (a *App) buildStockContext(ctx context.Context, code string) (string, error) {
 	code = normalizeStockCode(code)
 
-	stock, err := a.getPriceSnapshot(code)
+	stock, err := a.getPriceSnapshot(ctx, code)
 	if err != nil {
 		return "", err
 	}
 
-	klines, _ := a.getKLineDataCached(code, 10)
-	reports, _ := a.getResearchReportsCached(code)
-	notices, _ := a.getStockNoticesCached(code)
+	klines, _ := a.getKLineDataCached(ctx, code, 10)
+	reports, _ := a.getResearchReportsCached(ctx, code)
+	notices, _ := a.getStockNoticesCached(ctx, code)
 
 	return data.BuildStockAnalysisPrompt(stock, klines, reports, notices), nil
 }
@@ -3020,6 +3232,7 @@ func (a *App) buildFundContext(code string) (string, error) {
 // analysisType: fundamental(基本面), technical(技术面), sentiment(情绪面), master(大师模式)
 // masterStyle: buffett(巴菲特), lynch(彼得林奇), graham(格雷厄姆), liverta(利弗莫尔)
 func (a *App) AIAnalyzeByTypeStream(code string, analysisType string, masterStyle string) error {
+	ctx := a.lifetimeContext()
 	code = normalizeStockCode(code)
 	log.Printf("[专业分析] 开始: code=%s, type=%s, master=%s", code, analysisType, masterStyle)
 
@@ -3043,11 +3256,11 @@ func (a *App) AIAnalyzeByTypeStream(code string, analysisType string, masterStyl
 	a.aiClient = data.NewAIClient(&config)
 
 	// 为确保分析基于最新行情，先强制刷新该股票的行情/财务缓存
-	a.prefetchStockData(code, &config)
+	a.prefetchStockData(ctx, code, &config)
 
 	preloadChan := make(chan *stockAnalysisData, 1)
 	go func() {
-		preloadChan <- a.warmupStockAnalysisData(code, analysisType, &config, 5*time.Second)
+		preloadChan <- a.warmupStockAnalysisData(ctx, code, analysisType, &config, 5*time.Second)
 	}()
 
 	go func(preloadResult <-chan *stockAnalysisData, stockCode, aType, style string) {
@@ -3069,7 +3282,7 @@ func (a *App) AIAnalyzeByTypeStream(code string, analysisType string, masterStyl
 		stock := preloaded.stock
 		if stock == nil {
 			var err error
-			stock, err = a.getPriceSnapshot(stockCode)
+			stock, err = a.getPriceSnapshot(ctx, stockCode)
 			if err != nil {
 				wailsRuntime.EventsEmit(a.ctx, "ai-analysis-error", "获取股票价格失败")
 				return
@@ -3079,7 +3292,7 @@ func (a *App) AIAnalyzeByTypeStream(code string, analysisType string, masterStyl
 		klines := preloaded.klines
 		if len(klines) == 0 {
 			var err error
-			klines, err = a.getKLineDataCached(code, 60)
+			klines, err = a.getKLineDataCached(ctx, code, 60)
 			if err != nil {
 				log.Printf("[AI分析] 获取K线失败: %v", err)
 			}
@@ -3089,14 +3302,14 @@ func (a *App) AIAnalyzeByTypeStream(code string, analysisType string, masterStyl
 
 		reports := preloaded.reports
 		if reports == nil {
-			if r, err := a.getResearchReportsCached(code); err == nil {
+			if r, err := a.getResearchReportsCached(ctx, code); err == nil {
 				reports = r
 			}
 		}
 
 		notices := preloaded.notices
 		if notices == nil {
-			if n, err := a.getStockNoticesCached(code); err == nil {
+			if n, err := a.getStockNoticesCached(ctx, code); err == nil {
 				notices = n
 			}
 		}
@@ -3111,7 +3324,7 @@ func (a *App) AIAnalyzeByTypeStream(code string, analysisType string, masterStyl
 			financialData = preloaded.financial
 			if financialData == nil {
 				wailsRuntime.EventsEmit(a.ctx, "ai-analysis-stream", "正在获取财务数据...\n\n")
-				financialData, _ = a.getFinancialDataCached(stockCode, &config)
+				financialData, _ = a.getFinancialDataCached(ctx, stockCode, &config)
 			}
 		}
 
@@ -3120,17 +3333,40 @@ func (a *App) AIAnalyzeByTypeStream(code string, analysisType string, masterStyl
 			return
 		}
 
+		var valuation *models.ValuationBands
+		if aType == "fundamental" || aType == "master" {
+			wailsRuntime.EventsEmit(a.ctx, "ai-analysis-stream", "正在计算估值分位...\n\n")
+			var err error
+			if valuation, err = data.GetValuationBands(ctx, code); err != nil {
+				log.Printf("[AI分析] 获取估值分位失败: %v", err)
+			}
+		}
+
+		var consensus *models.ReportConsensus
+		var trend *models.FinancialTrend
+		if aType == "fundamental" {
+			wailsRuntime.EventsEmit(a.ctx, "ai-analysis-stream", "正在获取历史财务报表...\n\n")
+			var err error
+			if trend, err = data.GetFinancialTrend(ctx, code); err != nil {
+				log.Printf("[AI分析] 获取财务趋势失败: %v", err)
+			}
+			if err := data.SyncReportHistory(ctx, a.stockAPI, code); err != nil {
+				log.Printf("[AI分析] 同步研报历史失败: %v", err)
+			}
+			consensus, _ = data.GetReportConsensus(code, stock.Price)
+		}
+
 		// 构建分析提示词
 		var prompt string
 		switch aType {
 		case "fundamental":
-			prompt = buildFundamentalPrompt(stock, reports, notices, financialData)
+			prompt = buildFundamentalPrompt(stock, reports, notices, financialData, trend, consensus, valuation)
 		case "technical":
 			prompt = buildTechnicalPrompt(stock, klines)
 		case "sentiment":
 			prompt = buildSentimentPrompt(stock, reports, notices)
 		case "master":
-			prompt = buildMasterPrompt(stock, klines, reports, style, financialData)
+			prompt = buildMasterPrompt(stock, klines, reports, style, financialData, valuation)
 		default:
 			prompt = data.BuildStockAnalysisPrompt(stock, klines, reports, notices)
 		}
@@ -3147,7 +3383,7 @@ func (a *App) AIAnalyzeByTypeStream(code string, analysisType string, masterStyl
 
 		wailsRuntime.EventsEmit(a.ctx, "ai-analysis-stream", "正在进行AI分析...\n\n")
 
-		ch, err := a.aiClient.ChatStream(messages)
+		ch, err := a.aiClient.ChatStream(ctx, messages)
 		if err != nil {
 			wailsRuntime.EventsEmit(a.ctx, "ai-analysis-error", err.Error())
 			return
@@ -3165,27 +3401,108 @@ func (a *App) AIAnalyzeByTypeStream(code string, analysisType string, masterStyl
 	return nil
 }
 
-// getAnalysisSystemPrompt 获取分析系统提示词
-func getAnalysisSystemPrompt(analysisType string, masterStyle string) string {
-	// 如果有大师风格，优先使用大师提示词
-	if masterStyle != "" {
-		return getMasterSystemPrompt(masterStyle)
+// AIAnalyzeAssetStream 对期货、美股、港股、外汇进行AI分析（流式）
+// assetType: futures(期货), us(美股), hk(港股), forex(外汇)
+// analysisType/masterStyle 与 AIAnalyzeByTypeStream 一致，事件与缓存复用专业分析
+func (a *App) AIAnalyzeAssetStream(assetType string, code string, analysisType string, masterStyle string) error {
+	ctx := a.lifetimeContext()
+	if !data.IsSupportedAssetType(assetType) {
+		wailsRuntime.EventsEmit(a.ctx, "ai-analysis-error", "不支持的资产类型")
+		return fmt.Errorf("不支持的资产类型: %s", assetType)
 	}
+	code = data.NormalizeAssetCode(assetType, code)
+	log.Printf("[跨资产分析] 开始: asset=%s, code=%s, type=%s, master=%s", assetType, code, analysisType, masterStyle)
 
-	basePrompt := "你是一位专业的证券分析师。"
+	var config models.Config
+	if err := data.GetDB().First(&config).Error; err != nil {
+		wailsRuntime.EventsEmit(a.ctx, "ai-analysis-error", "获取配置失败")
+		return err
+	}
 
-	switch analysisType {
-	case "fundamental":
-		return basePrompt + `你擅长基本面分析，专注于：
-- 公司财务状况（营收、利润、现金流）
-- 估值指标（PE、PB、PS、PEG）
-- 行业地位和竞争优势
-- 管理层能力和公司治理
-- 成长性和盈利能力
-请用专业、客观的语言进行分析。`
+	if !config.AiEnabled {
+		wailsRuntime.EventsEmit(a.ctx, "ai-analysis-error", "AI功能未启用，请在设置中开启")
+		return fmt.Errorf("AI功能未启用")
+	}
 
-	case "technical":
-		return basePrompt + `你擅长技术面分析，专注于：
+	if config.AiApiKey == "" {
+		wailsRuntime.EventsEmit(a.ctx, "ai-analysis-error", "请先配置AI API Key")
+		return fmt.Errorf("请先配置AI API Key")
+	}
+
+	a.aiClient = data.NewAIClient(&config)
+
+	go func(aType, style string) {
+		wailsRuntime.EventsEmit(a.ctx, "ai-analysis-stream", "AI已开始准备数据，稍后将持续输出，请勿关闭窗口...\n\n")
+
+		assetCtx, err := a.assetBuilder.BuildWithTimeout(ctx, assetType, code)
+		if err != nil {
+			log.Printf("[跨资产分析] 构建上下文失败: %v", err)
+			wailsRuntime.EventsEmit(a.ctx, "ai-analysis-error", fmt.Sprintf("获取%s行情失败", data.AssetTypeLabel(assetType)))
+			return
+		}
+
+		prompt := data.BuildAssetAnalysisPrompt(assetCtx, aType)
+		systemPrompt := data.GetAssetSystemPrompt(assetType, aType)
+		if style != "" {
+			name := masterDisplayName(style)
+			prompt = fmt.Sprintf("请以**%s**的投资理念和分析方法进行分析，语言保持%s的口吻。\n\n", name, name) + prompt
+			systemPrompt = getMasterSystemPrompt(style)
+		}
+
+		messages := []data.ChatMessage{
+			{Role: "system", Content: systemPrompt},
+			{Role: "user", Content: prompt},
+		}
+
+		wailsRuntime.EventsEmit(a.ctx, "ai-analysis-stream", "正在进行AI分析...\n\n")
+
+		ch, err := a.aiClient.ChatStream(ctx, messages)
+		if err != nil {
+			wailsRuntime.EventsEmit(a.ctx, "ai-analysis-error", err.Error())
+			return
+		}
+
+		var builder strings.Builder
+		for content := range ch {
+			builder.WriteString(content)
+			wailsRuntime.EventsEmit(a.ctx, "ai-analysis-stream", content)
+		}
+		wailsRuntime.EventsEmit(a.ctx, "ai-analysis-done", "")
+		go a.saveProAnalysisCache(data.AssetCacheKey(assetType, code), aType, style, builder.String())
+	}(analysisType, masterStyle)
+
+	return nil
+}
+
+// GetAssetAnalysisCache 返回期货/美股/港股/外汇的分析缓存
+func (a *App) GetAssetAnalysisCache(assetType, code, analysisType, masterStyle string) *models.ProAnalysisCache {
+	if !data.IsSupportedAssetType(assetType) {
+		return nil
+	}
+	return a.GetProAnalysisCache(data.AssetCacheKey(assetType, code), analysisType, masterStyle)
+}
+
+// getAnalysisSystemPrompt 获取分析系统提示词
+func getAnalysisSystemPrompt(analysisType string, masterStyle string) string {
+	// 如果有大师风格，优先使用大师提示词
+	if masterStyle != "" {
+		return getMasterSystemPrompt(masterStyle)
+	}
+
+	basePrompt := "你是一位专业的证券分析师。"
+
+	switch analysisType {
+	case "fundamental":
+		return basePrompt + `你擅长基本面分析，专注于：
+- 公司财务状况（营收、利润、现金流）
+- 估值指标（PE、PB、PS、PEG）
+- 行业地位和竞争优势
+- 管理层能力和公司治理
+- 成长性和盈利能力
+请用专业、客观的语言进行分析。`
+
+	case "technical":
+		return basePrompt + `你擅长技术面分析，专注于：
 - K线形态分析（头肩顶/底、双顶/底、三角形等）
 - 趋势分析（上升/下降/横盘趋势）
 - 支撑位和压力位
@@ -3237,7 +3554,7 @@ func getMasterSystemPrompt(style string) string {
 分析时请用巴菲特的视角，关注：
 - 公司是否有持久的竞争优势？
 - 管理层是否诚实能干？
-- 当前价格是否提供足够的安全边际？
+- 当前价格是否提供足够的安全边际？估值处于自身历史与同行的什么位置？
 - 这是否是一门好生意？
 - 十年后这家公司会怎样？
 
@@ -3265,6 +3582,7 @@ func getMasterSystemPrompt(style string) string {
 - 市盈率低于15倍
 - 市净率低于1.5倍
 - PE×PB < 22.5
+- 当前PE、PB处于自身历史分位的低位，且不高于行业中位数
 - 流动比率大于2
 - 连续多年盈利和分红
 
@@ -3571,7 +3889,7 @@ func getMasterSystemPrompt(style string) string {
 }
 
 // buildFundamentalPrompt 构建基本面分析提示词
-func buildFundamentalPrompt(stock *models.StockPrice, reports []models.ResearchReport, notices []models.StockNotice, financialData *data.FinancialData) string {
+func buildFundamentalPrompt(stock *models.StockPrice, reports []models.ResearchReport, notices []models.StockNotice, financialData *data.FinancialData, trend *models.FinancialTrend, consensus *models.ReportConsensus, valuation *models.ValuationBands) string {
 	var sb strings.Builder
 
 	sb.WriteString("请对以下股票进行**基本面分析**：\n\n")
@@ -3579,11 +3897,20 @@ func buildFundamentalPrompt(stock *models.StockPrice, reports []models.ResearchR
 	sb.WriteString(fmt.Sprintf("- 代码：%s\n", stock.Code))
 	sb.WriteString(fmt.Sprintf("- 名称：%s\n", stock.Name))
 	sb.WriteString(fmt.Sprintf("- 现价：%.2f\n", stock.Price))
+	sb.WriteString(data.QuoteQualityNote(stock))
 	sb.WriteString(fmt.Sprintf("- 涨跌幅：%.2f%%\n\n", stock.ChangePercent))
 
-	// 添加财务数据
-	if financialData != nil {
+	// 添加财务数据：有多期报表时使用趋势，估值指标仍取自最新快照
+	if trend != nil && len(trend.Periods) > 0 {
+		sb.WriteString(data.FormatFinancialTrendForAI(trend))
+		if valuation != nil && len(valuation.Bands) > 0 {
+			sb.WriteString(data.FormatValuationBandsForAI(valuation))
+		} else if financialData != nil && (financialData.PE != 0 || financialData.PB != 0) {
+			sb.WriteString(fmt.Sprintf("## 估值\n- 市盈率(PE)：%.2f\n- 市净率(PB)：%.2f\n\n", financialData.PE, financialData.PB))
+		}
+	} else if financialData != nil {
 		sb.WriteString(data.FormatFinancialDataForAI(financialData))
+		sb.WriteString(data.FormatValuationBandsForAI(valuation))
 	}
 
 	if len(reports) > 0 {
@@ -3596,6 +3923,9 @@ func buildFundamentalPrompt(stock *models.StockPrice, reports []models.ResearchR
 		sb.WriteString("\n")
 	}
 
+	// 研报一致预期
+	sb.WriteString(data.FormatReportConsensusForAI(consensus))
+
 	if len(notices) > 0 {
 		sb.WriteString("## 最新公告\n")
 		count := min(5, len(notices))
@@ -3609,8 +3939,8 @@ func buildFundamentalPrompt(stock *models.StockPrice, reports []models.ResearchR
 	sb.WriteString(`
 请从以下方面进行基本面分析，并严格使用 Markdown 排版：
 1. **公司概况**：主营业务、所处行业
-2. **财务分析**：盈利能力、成长性、现金流、偿债能力
-3. **估值分析**：结合PE、PB、PS等指标，判断估值安全边际
+2. **财务分析**：结合多期趋势分析盈利能力、成长性、现金流质量、偿债能力
+3. **估值分析**：结合PE、PB、PS等指标及研报一致预期，判断估值安全边际
 4. **竞争优势**：行业壁垒、护城河、管理层特质
 5. **风险因素**：关键风险及触发条件
 6. **操作建议/观点**：基于以上分析给出明确结论
@@ -3636,6 +3966,7 @@ func buildTechnicalPrompt(stock *models.StockPrice, klines []models.KLineData) s
 	sb.WriteString(fmt.Sprintf("- 代码：%s\n", stock.Code))
 	sb.WriteString(fmt.Sprintf("- 名称：%s\n", stock.Name))
 	sb.WriteString(fmt.Sprintf("- 现价：%.2f\n", stock.Price))
+	sb.WriteString(data.QuoteQualityNote(stock))
 	sb.WriteString(fmt.Sprintf("- 涨跌幅：%.2f%%\n", stock.ChangePercent))
 	sb.WriteString(fmt.Sprintf("- 成交量：%d\n", stock.Volume))
 	sb.WriteString(fmt.Sprintf("- 成交额：%.2f\n\n", stock.Amount))
@@ -3688,6 +4019,7 @@ func buildSentimentPrompt(stock *models.StockPrice, reports []models.ResearchRep
 	sb.WriteString(fmt.Sprintf("- 代码：%s\n", stock.Code))
 	sb.WriteString(fmt.Sprintf("- 名称：%s\n", stock.Name))
 	sb.WriteString(fmt.Sprintf("- 现价：%.2f\n", stock.Price))
+	sb.WriteString(data.QuoteQualityNote(stock))
 	sb.WriteString(fmt.Sprintf("- 涨跌幅：%.2f%%\n", stock.ChangePercent))
 	sb.WriteString(fmt.Sprintf("- 成交量：%d\n", stock.Volume))
 	sb.WriteString(fmt.Sprintf("- 成交额：%.2f\n\n", stock.Amount))
@@ -3732,10 +4064,8 @@ func buildSentimentPrompt(stock *models.StockPrice, reports []models.ResearchRep
 	return sb.String()
 }
 
-// buildMasterPrompt 构建大师模式分析提示词
-func buildMasterPrompt(stock *models.StockPrice, klines []models.KLineData, reports []models.ResearchReport, masterStyle string, financialData *data.FinancialData) string {
-	var sb strings.Builder
-
+// masterDisplayName 获取大师风格对应的中文名
+func masterDisplayName(masterStyle string) string {
 	masterName := map[string]string{
 		"buffett":   "沃伦·巴菲特",
 		"lynch":     "彼得·林奇",
@@ -3752,16 +4082,24 @@ func buildMasterPrompt(stock *models.StockPrice, klines []models.KLineData, repo
 		"kostolany": "安德烈·科斯托拉尼",
 	}
 
-	name := masterName[masterStyle]
-	if name == "" {
-		name = "投资大师"
+	if name := masterName[masterStyle]; name != "" {
+		return name
 	}
+	return "投资大师"
+}
+
+// buildMasterPrompt 构建大师模式分析提示词
+func buildMasterPrompt(stock *models.StockPrice, klines []models.KLineData, reports []models.ResearchReport, masterStyle string, financialData *data.FinancialData, valuation *models.ValuationBands) string {
+	var sb strings.Builder
+
+	name := masterDisplayName(masterStyle)
 
 	sb.WriteString(fmt.Sprintf("请以**%s**的视角分析以下股票：\n\n", name))
 	sb.WriteString(fmt.Sprintf("## 股票信息\n"))
 	sb.WriteString(fmt.Sprintf("- 代码：%s\n", stock.Code))
 	sb.WriteString(fmt.Sprintf("- 名称：%s\n", stock.Name))
 	sb.WriteString(fmt.Sprintf("- 现价：%.2f\n", stock.Price))
+	sb.WriteString(data.QuoteQualityNote(stock))
 	sb.WriteString(fmt.Sprintf("- 涨跌幅：%.2f%%\n", stock.ChangePercent))
 	sb.WriteString(fmt.Sprintf("- 成交量：%d\n", stock.Volume))
 	sb.WriteString(fmt.Sprintf("- 成交额：%.2f\n\n", stock.Amount))
@@ -3770,6 +4108,8 @@ func buildMasterPrompt(stock *models.StockPrice, klines []models.KLineData, repo
 	if financialData != nil {
 		sb.WriteString(data.FormatFinancialDataForAI(financialData))
 	}
+	// 估值分位：让"PE 25"有历史与行业参照，供安全边际判断
+	sb.WriteString(data.FormatValuationBandsForAI(valuation))
 
 	if len(klines) > 0 {
 		sb.WriteString("## 近期K线数据\n")
@@ -3837,12 +4177,14 @@ func (a *App) GetPositionByStock(stockCode string) (*models.Position, error) {
 
 // AddPosition 添加持仓
 func (a *App) AddPosition(position models.Position) error {
+	ctx, cancel := a.callContext()
+	defer cancel()
 	position.StockCode = normalizeStockCode(position.StockCode)
 	position.Status = "holding"
 
 	// 如果没有提供股票名称，自动获取
 	if position.StockName == "" {
-		prices, err := a.stockAPI.GetStockPrice([]string{position.StockCode})
+		prices, err := a.stockAPI.GetStockPrice(ctx, []string{position.StockCode})
 		if err == nil {
 			if p, ok := prices[position.StockCode]; ok && p.Name != "" {
 				position.StockName = p.Name
@@ -3884,6 +4226,118 @@ func (a *App) GetPositionHistory() ([]models.Position, error) {
 	return positions, err
 }
 
+// PositionValuation 单个持仓的人民币估值
+type PositionValuation struct {
+	Position       models.Position `json:"position"`
+	Currency       string          `json:"currency"`       // 原币种：CNY/HKD/USD
+	CurrentPrice   float64         `json:"currentPrice"`   // 现价（原币）
+	PriceAvailable bool            `json:"priceAvailable"` // 是否取到实时价格（否则按成本价估值）
+	MarketValue    float64         `json:"marketValue"`    // 市值（原币）
+	CostValue      float64         `json:"costValue"`      // 成本（原币）
+	FxRate         float64         `json:"fxRate"`         // 1 单位原币兑人民币
+	MarketValueCNY float64         `json:"marketValueCny"` // 市值（人民币）
+	CostValueCNY   float64         `json:"costValueCny"`   // 成本（人民币）
+	ProfitCNY      float64         `json:"profitCny"`      // 浮动盈亏（人民币）
+}
+
+// PortfolioValuation 全部持仓的人民币汇总
+type PortfolioValuation struct {
+	Items               []PositionValuation `json:"items"`
+	TotalMarketValueCNY float64             `json:"totalMarketValueCny"`
+	TotalCostCNY        float64             `json:"totalCostCny"`
+	TotalProfitCNY      float64             `json:"totalProfitCny"`
+	Warnings            []string            `json:"warnings"`
+}
+
+// GetPortfolioValuationCNY 以人民币汇总A股、港股、美股持仓
+func (a *App) GetPortfolioValuationCNY() (*PortfolioValuation, error) {
+	ctx, cancel := a.callContext()
+	defer cancel()
+	positions, err := a.GetPositions()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PortfolioValuation{Items: make([]PositionValuation, 0, len(positions))}
+
+	var converter *data.ForexConverter
+	for _, p := range positions {
+		if data.CurrencyForStockCode(p.StockCode) != "CNY" {
+			converter, err = a.cryptoForexAPI.GetForexConverter(ctx)
+			if err != nil {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("获取汇率失败，外币持仓按1:1计入: %v", err))
+			}
+			break
+		}
+	}
+
+	for _, p := range positions {
+		item := PositionValuation{
+			Position: p,
+			Currency: data.CurrencyForStockCode(p.StockCode),
+			FxRate:   1,
+		}
+		cost := p.CostPrice
+		if cost <= 0 {
+			cost = p.BuyPrice
+		}
+		item.CostValue = cost * float64(p.Quantity)
+
+		if price := a.lookupPositionPrice(ctx, p.StockCode, item.Currency); price > 0 {
+			item.CurrentPrice = price
+			item.PriceAvailable = true
+		} else {
+			item.CurrentPrice = cost
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%s 未取到实时价格，按成本价估值", p.StockCode))
+		}
+		item.MarketValue = item.CurrentPrice * float64(p.Quantity)
+
+		if item.Currency != "CNY" && converter != nil {
+			if rate, err := converter.CrossRate(item.Currency, "CNY"); err == nil {
+				item.FxRate = rate.Rate
+			} else {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("%s 汇率换算失败: %v", p.StockCode, err))
+			}
+		}
+
+		item.MarketValueCNY = item.MarketValue * item.FxRate
+		item.CostValueCNY = item.CostValue * item.FxRate
+		item.ProfitCNY = item.MarketValueCNY - item.CostValueCNY
+
+		result.TotalMarketValueCNY += item.MarketValueCNY
+		result.TotalCostCNY += item.CostValueCNY
+		result.Items = append(result.Items, item)
+	}
+	result.TotalProfitCNY = result.TotalMarketValueCNY - result.TotalCostCNY
+
+	return result, nil
+}
+
+// lookupPositionPrice 按币种选择对应市场获取持仓现价，失败返回0
+func (a *App) lookupPositionPrice(ctx context.Context, code, currency string) float64 {
+	switch currency {
+	case "HKD":
+		hkCode := data.NormalizeAssetCode(data.AssetTypeHKStock, code)
+		if prices, err := a.globalMarketAPI.GetHKStockPrice(ctx, []string{hkCode}); err == nil {
+			if p, ok := prices[hkCode]; ok && p != nil {
+				return p.Price
+			}
+		}
+	case "USD":
+		symbol := data.USStockSymbol(code)
+		if prices, err := a.globalMarketAPI.GetUSStockPrice(ctx, []string{symbol}); err == nil {
+			if p, ok := prices[symbol]; ok && p != nil {
+				return p.Price
+			}
+		}
+	default:
+		if price, err := a.getPriceSnapshot(ctx, code); err == nil && price != nil {
+			return price.Price
+		}
+	}
+	return 0
+}
+
 // GetFundPosition 获取基金持仓
 func (a *App) GetFundPosition(fundCode string) (*models.FundPosition, error) {
 	var position models.FundPosition
@@ -4177,6 +4631,7 @@ func (a *App) ExportAIAnalysisHistory(format string) (string, error) {
 type DataCleanupInfo struct {
 	CacheStats       map[string]interface{} `json:"cacheStats"`
 	RateLimiterStats map[string]interface{} `json:"rateLimiterStats"`
+	CoalesceStats    []models.CoalesceStat  `json:"coalesceStats"` // 并发相同请求的合并统计
 	AIDataInfo       map[string]interface{} `json:"aiDataInfo"`
 	CleanupConfig    map[string]interface{} `json:"cleanupConfig"`
 }
@@ -4191,6 +4646,7 @@ func (a *App) GetDataCleanupInfo() *DataCleanupInfo {
 	return &DataCleanupInfo{
 		CacheStats:       rm.GetCacheStats(),
 		RateLimiterStats: rm.GetRateLimiterStats("default"),
+		CoalesceStats:    data.GetCoalesceStats(),
 		AIDataInfo:       aiInfo,
 		CleanupConfig: map[string]interface{}{
 			"quoteCleanupMinutes":     5,
@@ -4212,7 +4668,7 @@ func (a *App) CleanupAllCache() map[string]int {
 		"news":      rm.CleanupNewsCache(),
 		"report":    rm.CleanupReportCache(),
 		"notice":    rm.CleanupNoticeCache(),
-		"financial": data.GetTushareClient().ClearExpiredCache(),
+		"financial": rm.CleanupExpiredFinancialCache(),
 	}
 
 	return result
@@ -4225,7 +4681,7 @@ func (a *App) CleanupQuoteCache() int {
 
 // CleanupFinancialCache 清理财务数据缓存
 func (a *App) CleanupFinancialCache() int {
-	return data.GetTushareClient().ClearCache()
+	return data.GetRequestManager().CleanupFinancialCache()
 }
 
 // CleanupNewsCache 清理新闻缓存
@@ -4321,11 +4777,18 @@ func (a *App) AddStockAlert(alert models.StockAlert) error {
 	alert.StockCode = normalizeStockCode(alert.StockCode)
 	alert.Enabled = true
 	alert.Triggered = false
+	alert.LastSeenKey = ""
+	alert.SeenKeys = ""
+	alert.Keywords = strings.Join(splitAlertKeywords(alert.Keywords), ",")
+	if alert.AlertType == "news" && alert.Keywords == "" {
+		alert.Keywords = strings.Join(defaultNewsAlertKeywords, ",")
+	}
 	return data.GetDB().Create(&alert).Error
 }
 
 // UpdateStockAlert 更新股票提醒
 func (a *App) UpdateStockAlert(alert models.StockAlert) error {
+	alert.Keywords = strings.Join(splitAlertKeywords(alert.Keywords), ",")
 	return data.GetDB().Save(&alert).Error
 }
 
@@ -4349,9 +4812,9 @@ func (a *App) ResetStockAlert(id uint) error {
 
 // CheckStockAlerts 检查股票提醒（由前端定时调用，使用本地缓存数据）
 func (a *App) CheckStockAlerts() ([]models.AlertNotification, error) {
-	// 获取所有启用且未触发的提醒
+	// 获取所有启用且未触发的价格类提醒（公告/研报/新闻提醒由 CheckStockEventAlerts 处理）
 	var alerts []models.StockAlert
-	err := data.GetDB().Where("enabled = ? AND triggered = ?", true, false).Find(&alerts).Error
+	err := data.GetDB().Where("enabled = ? AND triggered = ? AND alert_type IN ?", true, false, []string{"price", "change"}).Find(&alerts).Error
 	if err != nil {
 		return nil, err
 	}
@@ -4361,12 +4824,11 @@ func (a *App) CheckStockAlerts() ([]models.AlertNotification, error) {
 	}
 
 	// 使用本地缓存的价格数据（由 GetStockPrice 更新）
-	a.stockPriceCacheLock.RLock()
 	prices := make(map[string]*models.StockPrice)
-	for code, price := range a.stockPriceCache {
+	a.stockPriceCache.Range(func(code string, price *models.StockPrice) bool {
 		prices[code] = price
-	}
-	a.stockPriceCacheLock.RUnlock()
+		return true
+	})
 
 	// 如果缓存为空，跳过本次检查
 	if len(prices) == 0 {
@@ -4384,7 +4846,8 @@ func (a *App) CheckStockAlerts() ([]models.AlertNotification, error) {
 
 	for _, alert := range alerts {
 		price, ok := prices[alert.StockCode]
-		if !ok || price == nil {
+		// 未通过校验的行情不触发提醒，等待下一轮刷新
+		if !ok || price == nil || price.Suspect {
 			continue
 		}
 
@@ -4438,7 +4901,7 @@ func (a *App) CheckStockAlerts() ([]models.AlertNotification, error) {
 			notifications = append(notifications, notification)
 
 			// 发送事件到前端
-			wailsRuntime.EventsEmit(a.ctx, "stock-alert-triggered", notification)
+			a.emitEvent("stock-alert-triggered", notification)
 
 			// 推送到外部通道
 			if pushConfig != nil {
@@ -4475,6 +4938,294 @@ func (a *App) CheckStockAlerts() ([]models.AlertNotification, error) {
 	return notifications, nil
 }
 
+// ========== 公告/研报/新闻提醒 ==========
+
+// 公告/研报/新闻提醒相关参数
+const (
+	stockEventAlertInterval    = 5 * time.Minute // 后台轮询间隔
+	stockEventAlertMaxPerCheck = 5               // 单个提醒每轮最多推送条数，避免刷屏
+	stockEventSeenKeysMax      = 200             // 每个提醒保留的已处理公告/研报标识数
+)
+
+// defaultNewsAlertKeywords 新闻提醒默认关键词
+var defaultNewsAlertKeywords = []string{"减持", "立案调查", "业绩预告", "回购"}
+
+// stockEvent 待推送的公告/研报/新闻
+type stockEvent struct {
+	title   string
+	url     string
+	keyword string // 新闻命中的关键词
+}
+
+// startStockEventAlertChecker 后台定时检查公告/研报/新闻提醒
+func (a *App) startStockEventAlertChecker(ctx context.Context) {
+	go func() {
+		for {
+			if _, err := a.checkStockEventAlerts(ctx); err != nil {
+				log.Printf("[事件提醒] 检查失败: %v", err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(stockEventAlertInterval):
+			}
+		}
+	}()
+}
+
+// CheckStockEventAlerts 检查公告/研报/新闻提醒
+// 公告/研报记录最近已处理的条目标识，不在其中的视为新条目；新闻记录已处理的最大ID。
+// 首次检查只记录位置、不推送历史内容
+func (a *App) CheckStockEventAlerts() ([]models.AlertNotification, error) {
+	ctx, cancel := a.callContext()
+	defer cancel()
+	return a.checkStockEventAlerts(ctx)
+}
+
+// checkStockEventAlerts 检查公告/研报/新闻提醒，ctx 决定请求优先级与取消
+func (a *App) checkStockEventAlerts(ctx context.Context) ([]models.AlertNotification, error) {
+	var alerts []models.StockAlert
+	err := data.GetDB().Where("enabled = ? AND alert_type IN ?", true, []string{"notice", "report", "news"}).Find(&alerts).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(alerts) == 0 {
+		return nil, nil
+	}
+
+	var pushConfig *models.Config
+	if cfg, err := a.GetConfig(); err == nil && cfg.AlertPushEnabled {
+		pushConfig = cfg
+	}
+
+	// 同一股票的公告/研报在本轮只请求一次
+	noticeLists := make(map[string][]models.StockNotice)
+	reportLists := make(map[string][]models.ResearchReport)
+
+	var notifications []models.AlertNotification
+	now := time.Now()
+
+	for _, alert := range alerts {
+		var events []stockEvent
+		var latestKey, seenKeys string
+		seen := make(map[string]bool)
+		for _, key := range strings.Split(alert.SeenKeys, "\n") {
+			seen[key] = key != ""
+		}
+
+		switch alert.AlertType {
+		case "notice":
+			notices, ok := noticeLists[alert.StockCode]
+			if !ok {
+				notices, err = a.refreshStockNotices(ctx, alert.StockCode)
+				if err != nil {
+					log.Printf("[事件提醒] %s 获取公告失败: %v", alert.StockCode, err)
+					continue
+				}
+				noticeLists[alert.StockCode] = notices
+			}
+			// 公告按时间倒序，未处理过的均为新公告
+			keys := make([]string, 0, len(notices))
+			for _, n := range notices {
+				if !seen[n.ArtCode] {
+					events = append(events, stockEvent{title: n.Title, url: n.Url})
+				}
+				keys = append(keys, n.ArtCode)
+			}
+			if len(notices) > 0 {
+				latestKey = notices[0].ArtCode
+				seenKeys = mergeSeenKeys(keys, alert.SeenKeys)
+			}
+		case "report":
+			reports, ok := reportLists[alert.StockCode]
+			if !ok {
+				reports, err = a.refreshResearchReports(ctx, alert.StockCode)
+				if err != nil {
+					log.Printf("[事件提醒] %s 获取研报失败: %v", alert.StockCode, err)
+					continue
+				}
+				reportLists[alert.StockCode] = reports
+			}
+			keys := make([]string, 0, len(reports))
+			for _, r := range reports {
+				keys = append(keys, r.InfoCode)
+				if seen[r.InfoCode] {
+					continue
+				}
+				title := r.Title
+				if r.OrgName != "" {
+					title = fmt.Sprintf("%s（%s %s）", r.Title, r.OrgName, r.Rating)
+				}
+				events = append(events, stockEvent{title: title, url: r.Url})
+			}
+			if len(reports) > 0 {
+				latestKey = reports[0].InfoCode
+				seenKeys = mergeSeenKeys(keys, alert.SeenKeys)
+			}
+		case "news":
+			if alert.LastSeenKey == "" {
+				latestKey = strconv.FormatUint(uint64(data.LatestNewsID()), 10)
+				break
+			}
+			lastID, _ := strconv.ParseUint(alert.LastSeenKey, 10, 64)
+			articles, err := data.ListTaggedNewsAfter(alert.StockCode, uint(lastID))
+			if err != nil {
+				log.Printf("[事件提醒] %s 查询新闻失败: %v", alert.StockCode, err)
+				continue
+			}
+			latestKey = alert.LastSeenKey
+			for _, article := range articles {
+				if keyword := matchNewsKeyword(article, alert.Keywords); keyword != "" {
+					events = append(events, stockEvent{title: article.Title, url: article.Url, keyword: keyword})
+				}
+				latestKey = strconv.FormatUint(uint64(article.ID), 10)
+			}
+		}
+
+		if latestKey == "" || (latestKey == alert.LastSeenKey && seenKeys == alert.SeenKeys) {
+			continue
+		}
+
+		updates := map[string]interface{}{"last_seen_key": latestKey}
+		// 首次检查只记录位置；升级前创建的公告/研报提醒没有已处理集合，同样先记录一次
+		firstCheck := alert.LastSeenKey == ""
+		if alert.AlertType != "news" {
+			updates["seen_keys"] = seenKeys
+			firstCheck = firstCheck || alert.SeenKeys == ""
+		}
+		if firstCheck {
+			events = nil
+		}
+		if len(events) > 0 {
+			updates["triggered_at"] = now
+		}
+		data.GetDB().Model(&alert).Updates(updates)
+
+		if len(events) > stockEventAlertMaxPerCheck {
+			events = events[:stockEventAlertMaxPerCheck]
+		}
+		for _, event := range events {
+			notification := a.notifyStockEvent(alert, event, now, pushConfig)
+			notifications = append(notifications, notification)
+		}
+	}
+
+	return notifications, nil
+}
+
+// notifyStockEvent 发送公告/研报/新闻提醒到前端、外部推送通道与通知插件
+func (a *App) notifyStockEvent(alert models.StockAlert, event stockEvent, now time.Time, pushConfig *models.Config) models.AlertNotification {
+	alertTypeText := map[string]string{
+		"notice": "公告提醒",
+		"report": "研报提醒",
+		"news":   "新闻提醒",
+	}[alert.AlertType]
+	message := fmt.Sprintf("%s 发布新公告：%s", alert.StockName, event.title)
+	switch alert.AlertType {
+	case "report":
+		message = fmt.Sprintf("%s 有新研报：%s", alert.StockName, event.title)
+	case "news":
+		message = fmt.Sprintf("%s 相关新闻（%s）：%s", alert.StockName, event.keyword, event.title)
+		alertTypeText = fmt.Sprintf("%s（%s）", alertTypeText, event.keyword)
+	}
+
+	notification := models.AlertNotification{
+		ID:        alert.ID,
+		StockCode: alert.StockCode,
+		StockName: alert.StockName,
+		AlertType: alert.AlertType,
+		Message:   message,
+		Time:      now.Format("15:04:05"),
+		AssetType: "stock",
+		Title:     event.title,
+		Url:       event.url,
+	}
+
+	a.emitEvent("stock-alert-triggered", notification)
+
+	if pushConfig != nil {
+		go a.dispatchAlertPush(pushConfig, notification)
+	}
+
+	if a.pluginManager.HasEnabledNotificationPlugins() {
+		notifyData := &plugin.NotificationData{
+			StockCode:   alert.StockCode,
+			StockName:   alert.StockName,
+			AlertType:   alertTypeText,
+			Condition:   event.title,
+			TriggerTime: now.Format("2006-01-02 15:04:05"),
+			Title:       event.title,
+			Link:        event.url,
+		}
+		go a.pluginManager.SendNotificationToAll(notifyData)
+	}
+	return notification
+}
+
+// mergeSeenKeys 把本轮列表的标识并入已处理集合（换行分隔），本轮的在前，最多保留 stockEventSeenKeysMax 个
+func mergeSeenKeys(current []string, seenKeys string) string {
+	merged := make([]string, 0, stockEventSeenKeysMax)
+	added := make(map[string]bool)
+	for _, key := range append(current, strings.Split(seenKeys, "\n")...) {
+		if key == "" || added[key] {
+			continue
+		}
+		added[key] = true
+		merged = append(merged, key)
+		if len(merged) == stockEventSeenKeysMax {
+			break
+		}
+	}
+	return strings.Join(merged, "\n")
+}
+
+// splitAlertKeywords 拆分新闻提醒关键词，中英文逗号均可分隔
+func splitAlertKeywords(keywords string) []string {
+	var result []string
+	for _, keyword := range strings.FieldsFunc(keywords, func(r rune) bool { return r == ',' || r == '，' }) {
+		if keyword = strings.TrimSpace(keyword); keyword != "" {
+			result = append(result, keyword)
+		}
+	}
+	return result
+}
+
+// matchNewsKeyword 返回新闻命中的第一个关键词，未命中返回空字符串
+func matchNewsKeyword(article models.NewsArticle, keywords string) string {
+	text := article.Title + "\n" + article.Content
+	for _, keyword := range splitAlertKeywords(keywords) {
+		if strings.Contains(text, keyword) {
+			return keyword
+		}
+	}
+	return ""
+}
+
+// refreshStockNotices 实时获取公告并更新缓存（提醒轮询需要绕过缓存）
+func (a *App) refreshStockNotices(ctx context.Context, code string) ([]models.StockNotice, error) {
+	notices, err := a.stockAPI.GetStockNotices(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	if len(notices) > 0 {
+		a.stockNoticeCache.Set(code, notices)
+	}
+	return notices, nil
+}
+
+// refreshResearchReports 实时获取研报并更新缓存
+func (a *App) refreshResearchReports(ctx context.Context, code string) ([]models.ResearchReport, error) {
+	reports, err := a.stockAPI.GetResearchReports(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	if len(reports) > 0 {
+		a.stockReportCache.Set(code, reports)
+		go data.SaveReportHistory(code, reports)
+	}
+	return reports, nil
+}
+
 // GetFundAlerts 获取基金提醒
 func (a *App) GetFundAlerts(fundCode string) ([]models.FundAlert, error) {
 	var alerts []models.FundAlert
@@ -4522,12 +5273,11 @@ func (a *App) CheckFundAlerts() ([]models.AlertNotification, error) {
 		return nil, nil
 	}
 
-	a.fundPriceCacheLock.RLock()
-	prices := make(map[string]*models.FundPrice, len(a.fundPriceCache))
-	for code, price := range a.fundPriceCache {
+	prices := make(map[string]*models.FundPrice)
+	a.fundPriceCache.Range(func(code string, price *models.FundPrice) bool {
 		prices[code] = price
-	}
-	a.fundPriceCacheLock.RUnlock()
+		return true
+	})
 	if len(prices) == 0 {
 		return nil, nil
 	}
@@ -4599,7 +5349,7 @@ func (a *App) CheckFundAlerts() ([]models.AlertNotification, error) {
 		}
 
 		notifications = append(notifications, notification)
-		wailsRuntime.EventsEmit(a.ctx, "fund-alert-triggered", notification)
+		a.emitEvent("fund-alert-triggered", notification)
 
 		if pushConfig != nil {
 			go a.dispatchAlertPush(pushConfig, notification)
@@ -4633,29 +5383,203 @@ func (a *App) CheckFundAlerts() ([]models.AlertNotification, error) {
 	return notifications, nil
 }
 
-// TestAlertPush 测试外部推送通道
-func (a *App) TestAlertPush(channel string) error {
-	cfg, err := a.GetConfig()
-	if err != nil {
-		return err
+// GetCryptoAlerts 获取加密货币提醒
+func (a *App) GetCryptoAlerts(symbol string) ([]models.CryptoAlert, error) {
+	var alerts []models.CryptoAlert
+	query := data.GetDB().Where("enabled = ?", true)
+	if symbol != "" {
+		query = query.Where("symbol = ?", data.NormalizeCryptoSymbol(symbol))
 	}
+	err := query.Order("created_at DESC").Find(&alerts).Error
+	return alerts, err
+}
 
-	sample := models.AlertNotification{
-		ID:            0,
-		StockCode:     "sh000001",
-		StockName:     "上证指数",
-		AlertType:     "price",
-		TargetValue:   3200,
-		CurrentPrice:  3210,
-		CurrentChange: 0.56,
-		Message:       "测试提醒：当前价格 3210.00，预设目标 3200.00",
-		Time:          time.Now().Format("15:04:05"),
-		AssetType:     "stock",
+// AddCryptoAlert 添加加密货币提醒
+func (a *App) AddCryptoAlert(alert models.CryptoAlert) error {
+	alert.Symbol = data.NormalizeCryptoSymbol(alert.Symbol)
+	if alert.Symbol == "" {
+		return fmt.Errorf("币种代码不能为空")
 	}
-
-	switch strings.ToLower(channel) {
-	case "wecom":
-		if strings.TrimSpace(cfg.WecomWebhook) == "" {
+	if alert.Name == "" {
+		alert.Name = data.CryptoName(alert.Symbol)
+	}
+	alert.Enabled = true
+	alert.Triggered = false
+	return data.GetDB().Create(&alert).Error
+}
+
+// DeleteCryptoAlert 删除加密货币提醒
+func (a *App) DeleteCryptoAlert(id uint) error {
+	return data.GetDB().Delete(&models.CryptoAlert{}, id).Error
+}
+
+// ToggleCryptoAlert 切换加密货币提醒状态
+func (a *App) ToggleCryptoAlert(id uint, enabled bool) error {
+	return data.GetDB().Model(&models.CryptoAlert{}).Where("id = ?", id).Update("enabled", enabled).Error
+}
+
+// ResetCryptoAlert 重置加密货币提醒
+func (a *App) ResetCryptoAlert(id uint) error {
+	return data.GetDB().Model(&models.CryptoAlert{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"triggered":    false,
+		"triggered_at": nil,
+	}).Error
+}
+
+// CheckCryptoAlerts 检查加密货币提醒（加密市场7x24小时交易，不受交易时段限制）
+func (a *App) CheckCryptoAlerts() ([]models.AlertNotification, error) {
+	ctx, cancel := a.callContext()
+	defer cancel()
+	return a.checkCryptoAlerts(ctx)
+}
+
+// checkCryptoAlerts 检查加密货币提醒，行情缓存刷新时在后台调用，触发结果通过事件推送
+func (a *App) checkCryptoAlerts(ctx context.Context) ([]models.AlertNotification, error) {
+	var alerts []models.CryptoAlert
+	err := data.GetDB().Where("enabled = ? AND triggered = ?", true, false).Find(&alerts).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(alerts) == 0 {
+		return nil, nil
+	}
+
+	symbols := make([]string, 0, len(alerts))
+	for _, alert := range alerts {
+		symbols = append(symbols, alert.Symbol)
+	}
+	quotes, err := a.cryptoForexAPI.GetCryptoQuotes(ctx, symbols)
+	if err != nil {
+		return nil, err
+	}
+	prices := make(map[string]models.CryptoPrice, len(quotes))
+	for _, q := range quotes {
+		if q.Price > 0 {
+			prices[q.Symbol] = q
+		}
+	}
+
+	var pushConfig *models.Config
+	if cfg, err := a.GetConfig(); err == nil && cfg.AlertPushEnabled {
+		pushConfig = cfg
+	}
+
+	var notifications []models.AlertNotification
+	now := time.Now()
+
+	for _, alert := range alerts {
+		quote, ok := prices[alert.Symbol]
+		if !ok {
+			continue
+		}
+
+		currentPrice := quote.Price
+		currentChange := quote.ChangePercent
+
+		triggered := false
+		var message string
+
+		switch alert.AlertType {
+		case "price":
+			if alert.Condition == "above" && currentPrice >= alert.TargetValue {
+				triggered = true
+				message = fmt.Sprintf("%s 价格已达到 %.4f USDT（目标：%.4f）", alert.Name, currentPrice, alert.TargetValue)
+			} else if alert.Condition == "below" && currentPrice <= alert.TargetValue {
+				triggered = true
+				message = fmt.Sprintf("%s 价格已跌至 %.4f USDT（目标：%.4f）", alert.Name, currentPrice, alert.TargetValue)
+			}
+		case "change":
+			if alert.Condition == "above" && currentChange >= alert.TargetValue {
+				triggered = true
+				message = fmt.Sprintf("%s 24h涨幅已达 %.2f%%（目标：%.2f%%）", alert.Name, currentChange, alert.TargetValue)
+			} else if alert.Condition == "below" && currentChange <= -alert.TargetValue {
+				triggered = true
+				message = fmt.Sprintf("%s 24h跌幅已达 %.2f%%（目标：-%.2f%%）", alert.Name, currentChange, alert.TargetValue)
+			}
+		}
+
+		if !triggered {
+			continue
+		}
+
+		data.GetDB().Model(&alert).Updates(map[string]interface{}{
+			"triggered":        true,
+			"triggered_at":     now,
+			"triggered_price":  currentPrice,
+			"triggered_change": currentChange,
+		})
+
+		notification := models.AlertNotification{
+			ID:            alert.ID,
+			StockCode:     alert.Symbol,
+			StockName:     alert.Name,
+			AlertType:     alert.AlertType,
+			TargetValue:   alert.TargetValue,
+			CurrentPrice:  currentPrice,
+			CurrentChange: currentChange,
+			Message:       message,
+			Time:          now.Format("15:04:05"),
+			AssetType:     "crypto",
+		}
+
+		notifications = append(notifications, notification)
+		a.emitEvent("crypto-alert-triggered", notification)
+
+		if pushConfig != nil {
+			go a.dispatchAlertPush(pushConfig, notification)
+		}
+
+		if a.pluginManager.HasEnabledNotificationPlugins() {
+			alertTypeText := "加密货币价格提醒"
+			if alert.AlertType == "change" {
+				alertTypeText = "加密货币涨跌提醒"
+			}
+			conditionText := "高于"
+			if alert.Condition == "below" {
+				conditionText = "低于"
+			}
+
+			notifyData := &plugin.NotificationData{
+				StockCode:     alert.Symbol,
+				StockName:     alert.Name,
+				AlertType:     alertTypeText,
+				CurrentPrice:  currentPrice,
+				Condition:     conditionText,
+				TargetValue:   alert.TargetValue,
+				TriggerTime:   now.Format("2006-01-02 15:04:05"),
+				Change:        quote.Change,
+				ChangePercent: currentChange,
+			}
+			go a.pluginManager.SendNotificationToAll(notifyData)
+		}
+	}
+
+	return notifications, nil
+}
+
+// TestAlertPush 测试外部推送通道
+func (a *App) TestAlertPush(channel string) error {
+	cfg, err := a.GetConfig()
+	if err != nil {
+		return err
+	}
+
+	sample := models.AlertNotification{
+		ID:            0,
+		StockCode:     "sh000001",
+		StockName:     "上证指数",
+		AlertType:     "price",
+		TargetValue:   3200,
+		CurrentPrice:  3210,
+		CurrentChange: 0.56,
+		Message:       "测试提醒：当前价格 3210.00，预设目标 3200.00",
+		Time:          time.Now().Format("15:04:05"),
+		AssetType:     "stock",
+	}
+
+	switch strings.ToLower(channel) {
+	case "wecom":
+		if strings.TrimSpace(cfg.WecomWebhook) == "" {
 			return fmt.Errorf("请先配置企业微信 Webhook")
 		}
 		return sendWecomWebhook(cfg.WecomWebhook, "Stock AI 提醒测试", formatAlertMarkdown(sample))
@@ -4679,8 +5603,11 @@ func (a *App) dispatchAlertPush(cfg *models.Config, notification models.AlertNot
 		return
 	}
 	assetLabel := "股票"
-	if notification.AssetType == "fund" {
+	switch notification.AssetType {
+	case "fund":
 		assetLabel = "基金"
+	case "crypto":
+		assetLabel = "加密货币"
 	}
 	title := fmt.Sprintf("%s%s提醒", notification.StockName, assetLabel)
 	markdown := formatAlertMarkdown(notification)
@@ -4705,9 +5632,22 @@ func (a *App) dispatchAlertPush(cfg *models.Config, notification models.AlertNot
 
 func formatAlertMarkdown(n models.AlertNotification) string {
 	var sb strings.Builder
+	if n.Title != "" {
+		// 公告/研报/新闻提醒
+		sb.WriteString(fmt.Sprintf("**%s (%s)**\n", n.StockName, n.StockCode))
+		sb.WriteString(fmt.Sprintf("> %s\n", n.Message))
+		if n.Url != "" {
+			sb.WriteString(fmt.Sprintf("> [查看原文](%s)\n", n.Url))
+		}
+		sb.WriteString(fmt.Sprintf("> 时间：%s\n", n.Time))
+		return sb.String()
+	}
 	priceLabel := "现价"
-	if n.AssetType == "fund" {
+	switch n.AssetType {
+	case "fund":
 		priceLabel = "净值"
+	case "crypto":
+		priceLabel = "现价(USDT)"
 	}
 	sb.WriteString(fmt.Sprintf("**%s (%s)**\n", n.StockName, n.StockCode))
 	sb.WriteString(fmt.Sprintf("> %s\n", n.Message))
@@ -4717,9 +5657,15 @@ func formatAlertMarkdown(n models.AlertNotification) string {
 }
 
 func formatAlertText(n models.AlertNotification) string {
+	if n.Title != "" {
+		return fmt.Sprintf("%s (%s)\n%s\n链接: %s\n时间: %s\n", n.StockName, n.StockCode, n.Message, n.Url, n.Time)
+	}
 	priceLabel := "现价"
-	if n.AssetType == "fund" {
+	switch n.AssetType {
+	case "fund":
 		priceLabel = "净值"
+	case "crypto":
+		priceLabel = "现价(USDT)"
 	}
 	return fmt.Sprintf(
 		"%s (%s)\n%s\n%s: %.4f\n涨跌幅: %.2f%%\n目标值: %.2f\n时间: %s\n",
@@ -4876,6 +5822,381 @@ func postJSON(target string, payload interface{}) error {
 	return nil
 }
 
+// ========== AI资讯日报 ==========
+
+// AI资讯日报相关参数
+const (
+	dailyDigestCheckInterval = 5 * time.Minute // 定时任务检查间隔
+	defaultDailyDigestTime   = "17:30"         // 默认生成时间
+	dailyDigestFetchWorkers  = 5               // 并发获取资讯的股票数
+	wecomMarkdownMaxBytes    = 4000            // 企业微信 markdown 消息长度上限
+)
+
+// startDailyDigestScheduler 每日到点后自动生成资讯日报并推送，ctx 结束后停止
+func (a *App) startDailyDigestScheduler(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(dailyDigestCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				a.runScheduledDailyDigest(ctx)
+			}
+		}
+	}()
+}
+
+// runScheduledDailyDigest 已到生成时间且当天尚未生成时执行
+func (a *App) runScheduledDailyDigest(ctx context.Context) {
+	cfg, err := a.GetConfig()
+	if err != nil || !cfg.DailyDigestEnabled {
+		return
+	}
+
+	digestTime := strings.TrimSpace(cfg.DailyDigestTime)
+	if digestTime == "" {
+		digestTime = defaultDailyDigestTime
+	}
+	scheduled, err := time.Parse("15:04", digestTime)
+	if err != nil {
+		log.Printf("[资讯日报] 无效的生成时间: %s", cfg.DailyDigestTime)
+		return
+	}
+	now := time.Now().In(data.MarketLocation())
+	if now.Hour()*60+now.Minute() < scheduled.Hour()*60+scheduled.Minute() {
+		return
+	}
+
+	var count int64
+	data.GetDB().Model(&models.DailyDigest{}).Where("date = ?", now.Format("2006-01-02")).Count(&count)
+	if count > 0 {
+		return
+	}
+	if _, err := a.generateDailyDigest(ctx, true); err != nil {
+		log.Printf("[资讯日报] 定时生成失败: %v", err)
+	}
+}
+
+// GenerateDailyDigest 汇总自选股当日公告、研报与新闻，由AI按股票生成带情绪标签的日报
+// 同一天重复生成会覆盖之前的结果；push 为 true 时通过已配置的推送通道发送
+func (a *App) GenerateDailyDigest(push bool) (*models.DailyDigest, error) {
+	return a.generateDailyDigest(a.lifetimeContext(), push)
+}
+
+// generateDailyDigest 在 ctx 下生成日报，ctx 决定请求的排队优先级与取消
+func (a *App) generateDailyDigest(ctx context.Context, push bool) (*models.DailyDigest, error) {
+	var config models.Config
+	if err := data.GetDB().First(&config).Error; err != nil {
+		return nil, fmt.Errorf("获取配置失败: %w", err)
+	}
+	if !config.AiEnabled {
+		return nil, fmt.Errorf("AI功能未启用")
+	}
+	if config.AiApiKey == "" {
+		return nil, fmt.Errorf("请先配置AI API Key")
+	}
+
+	date := time.Now().In(data.MarketLocation()).Format("2006-01-02")
+	groups, err := a.collectDigestGroups(ctx, date)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[资讯日报] %s 共 %d 只股票有资讯", date, len(groups))
+
+	client := data.NewAIClient(&config)
+	summaries := make([]*data.DigestStockSummary, 0, len(groups))
+	itemCount := 0
+	for _, group := range groups {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		summary, err := data.SummarizeDigestGroup(ctx, client, group)
+		if err != nil {
+			log.Printf("[资讯日报] %s AI汇总失败: %v", group.Code, err)
+			summary = &data.DigestStockSummary{
+				Code:      group.Code,
+				Name:      group.Name,
+				Sentiment: data.SentimentNeutral,
+				Summary:   fmt.Sprintf("AI汇总失败：%v", err),
+				Articles:  group.Articles,
+				Briefs:    make([]string, len(group.Articles)),
+			}
+		}
+		itemCount += len(summary.Articles)
+		summaries = append(summaries, summary)
+	}
+
+	content := data.RenderDigestMarkdown(date, summaries)
+	if len(summaries) == 0 {
+		content = fmt.Sprintf("# 自选股资讯日报 %s\n\n今日自选股暂无公告、研报或相关新闻。\n", date)
+	}
+
+	var digest models.DailyDigest
+	data.GetDB().Where("date = ?", date).First(&digest)
+	digest.Date = date
+	digest.Content = content
+	digest.StockCount = len(summaries)
+	digest.ItemCount = itemCount
+	digest.PushedAt = nil
+	if err := data.GetDB().Save(&digest).Error; err != nil {
+		return nil, fmt.Errorf("保存日报失败: %w", err)
+	}
+
+	if push {
+		if err := a.pushDailyDigest(&config, &digest); err != nil {
+			log.Printf("[资讯日报] 推送失败: %v", err)
+		}
+	}
+
+	a.emitEvent("daily-digest-generated", digest)
+	return &digest, nil
+}
+
+// collectDigestGroups 按股票收集当日公告、研报与已关联的新闻，保持自选股顺序
+func (a *App) collectDigestGroups(ctx context.Context, date string) ([]data.DigestGroup, error) {
+	stocks, err := a.GetStockList()
+	if err != nil {
+		return nil, err
+	}
+
+	dayStart, err := time.ParseInLocation("2006-01-02", date, data.MarketLocation())
+	if err != nil {
+		return nil, err
+	}
+	dayEnd := dayStart.AddDate(0, 0, 1)
+
+	groups := make([]data.DigestGroup, len(stocks))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, dailyDigestFetchWorkers)
+
+	for i, stock := range stocks {
+		code := normalizeStockCode(stock.Code)
+		if code == "" {
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, code string, name string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			group := data.DigestGroup{Code: code, Name: name}
+			if notices, err := a.refreshStockNotices(ctx, code); err == nil {
+				for _, n := range notices {
+					if n.Date != date {
+						continue
+					}
+					// 正文获取失败时仅凭标题汇总
+					content, _ := a.stockAPI.GetNoticeContent(ctx, code, n.ArtCode)
+					group.Articles = append(group.Articles, data.DigestArticle{Kind: "notice", Title: n.Title, Url: n.Url, Content: content})
+				}
+			} else {
+				log.Printf("[资讯日报] %s 获取公告失败: %v", code, err)
+			}
+
+			if reports, err := a.refreshResearchReports(ctx, code); err == nil {
+				for _, r := range reports {
+					if r.PublishDate != date {
+						continue
+					}
+					// 研报接口不提供正文，使用机构与评级信息
+					content := fmt.Sprintf("机构：%s；评级：%s；研究员：%s", r.OrgName, r.Rating, r.Researcher)
+					group.Articles = append(group.Articles, data.DigestArticle{Kind: "report", Title: r.Title, Url: r.Url, Content: content})
+				}
+			} else {
+				log.Printf("[资讯日报] %s 获取研报失败: %v", code, err)
+			}
+
+			if articles, err := data.SearchNews("", []string{code}, dayStart, dayEnd); err == nil {
+				for _, article := range articles {
+					group.Articles = append(group.Articles, data.DigestArticle{Kind: "news", Title: article.Title, Url: article.Url, Content: article.Content})
+				}
+			} else {
+				log.Printf("[资讯日报] %s 查询新闻失败: %v", code, err)
+			}
+
+			groups[i] = group
+		}(i, code, stock.Name)
+	}
+	wg.Wait()
+
+	result := make([]data.DigestGroup, 0, len(groups))
+	for _, group := range groups {
+		if len(group.Articles) > 0 {
+			result = append(result, group)
+		}
+	}
+	return result, nil
+}
+
+// pushDailyDigest 通过告警推送通道发送日报
+func (a *App) pushDailyDigest(cfg *models.Config, digest *models.DailyDigest) error {
+	if !cfg.AlertPushEnabled {
+		return fmt.Errorf("未开启推送")
+	}
+
+	title := fmt.Sprintf("自选股资讯日报 %s", digest.Date)
+	var errs []string
+	if hook := strings.TrimSpace(cfg.WecomWebhook); hook != "" {
+		if err := sendWecomWebhook(hook, title, truncateUTF8Bytes(digest.Content, wecomMarkdownMaxBytes)); err != nil {
+			errs = append(errs, fmt.Sprintf("企业微信: %v", err))
+		}
+	}
+	if hook := strings.TrimSpace(cfg.DingtalkWebhook); hook != "" {
+		if err := sendDingTalkWebhook(hook, title, digest.Content); err != nil {
+			errs = append(errs, fmt.Sprintf("钉钉: %v", err))
+		}
+	}
+	if cfg.EmailPushEnabled && strings.TrimSpace(cfg.EmailTo) != "" {
+		if err := sendEmailNotification(cfg, "Stock AI "+title, digest.Content); err != nil {
+			errs = append(errs, fmt.Sprintf("邮件: %v", err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+
+	now := time.Now()
+	digest.PushedAt = &now
+	return data.GetDB().Model(digest).Update("pushed_at", now).Error
+}
+
+// truncateUTF8Bytes 按字节截断文本且不破坏多字节字符
+func truncateUTF8Bytes(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	const suffix = "\n\n……（内容过长，完整日报请在应用内查看）"
+	cut := max - len(suffix)
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + suffix
+}
+
+// PushDailyDigest 手动推送指定日期的日报
+func (a *App) PushDailyDigest(date string) error {
+	cfg, err := a.GetConfig()
+	if err != nil {
+		return err
+	}
+	var digest models.DailyDigest
+	if err := data.GetDB().Where("date = ?", date).First(&digest).Error; err != nil {
+		return fmt.Errorf("未找到 %s 的日报", date)
+	}
+	return a.pushDailyDigest(cfg, &digest)
+}
+
+// GetDailyDigests 获取最近的日报列表
+func (a *App) GetDailyDigests(limit int) ([]models.DailyDigest, error) {
+	if limit <= 0 {
+		limit = 30
+	}
+	var digests []models.DailyDigest
+	err := data.GetDB().Order("date DESC").Limit(limit).Find(&digests).Error
+	return digests, err
+}
+
+// ========== 量化选股 ==========
+
+// GetScreenerFields 获取选股表达式可用的字段
+func (a *App) GetScreenerFields() []screener.Field {
+	return screener.AllFields()
+}
+
+// ValidateScreenExpression 校验选股表达式，返回空字符串表示合法
+func (a *App) ValidateScreenExpression(expression string) string {
+	if _, err := screener.Compile(expression); err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+// RefreshScreenerData 刷新选股因子快照（财务、估值分位、技术指标）
+// codes 为空时刷新全部自选A股，返回成功刷新的数量
+func (a *App) RefreshScreenerData(codes []string) (int, error) {
+	ctx := a.backgroundContext(data.PriorityBulk)
+	var stocks []models.Stock
+	if len(codes) == 0 {
+		watchlist, err := a.GetStockList()
+		if err != nil {
+			return 0, fmt.Errorf("获取自选股失败: %w", err)
+		}
+		stocks = watchlist
+	} else {
+		for _, c := range codes {
+			code := normalizeStockCode(c)
+			var security models.SecurityName
+			data.GetDB().Where("code = ?", code).First(&security)
+			stocks = append(stocks, models.Stock{Code: code, Name: security.Name})
+		}
+	}
+
+	// 估值与技术快照依赖东方财富A股接口，仅处理沪深代码
+	var ashares []models.Stock
+	for _, s := range stocks {
+		if strings.HasPrefix(s.Code, "sh") || strings.HasPrefix(s.Code, "sz") {
+			ashares = append(ashares, s)
+		}
+	}
+	if len(ashares) == 0 {
+		return 0, fmt.Errorf("没有可刷新的沪深A股")
+	}
+	success := data.RefreshFactorSnapshots(ctx, ashares)
+
+	// 全量刷新时清理已移出自选的股票快照
+	if len(codes) == 0 {
+		keep := make([]string, 0, len(ashares))
+		for _, s := range ashares {
+			keep = append(keep, s.Code)
+		}
+		if _, err := data.PruneFactorSnapshots(keep); err != nil {
+			log.Printf("[量化选股] 清理因子快照失败: %v", err)
+		}
+	}
+	return success, nil
+}
+
+// RunScreen 在本地因子快照上执行选股表达式（不保存）
+func (a *App) RunScreen(expression string) (*models.ScreenResult, error) {
+	return data.RunScreenExpression(expression)
+}
+
+// SaveScreen 保存选股条件（id 为0时新建）
+func (a *App) SaveScreen(id uint, name string, expression string, description string) (*models.SavedScreen, error) {
+	screen := &models.SavedScreen{ID: id, Name: name, Expression: expression, Description: description}
+	if err := data.SaveScreen(screen); err != nil {
+		return nil, err
+	}
+	return screen, nil
+}
+
+// ListScreens 获取保存的选股条件
+func (a *App) ListScreens() ([]models.SavedScreen, error) {
+	return data.ListScreens()
+}
+
+// DeleteScreen 删除选股条件
+func (a *App) DeleteScreen(id uint) error {
+	return data.DeleteScreen(id)
+}
+
+// RunSavedScreen 运行保存的选股条件，结果按日记录并标出相比上一次的新入选股票
+func (a *App) RunSavedScreen(id uint) (*models.ScreenResult, error) {
+	return data.RunSavedScreen(id)
+}
+
+// GetScreenRuns 获取选股条件的历史运行记录
+func (a *App) GetScreenRuns(id uint, limit int) ([]models.ScreenRun, error) {
+	if limit <= 0 {
+		limit = 30
+	}
+	return data.ListScreenRuns(id, limit)
+}
+
 // ========== 插件管理 ==========
 
 // GetPlugins 获取所有插件
@@ -4917,7 +6238,11 @@ func (a *App) AddPlugin(pluginData map[string]interface{}) error {
 		Config:      configJSON,
 	}
 
-	return a.pluginManager.AddPlugin(p)
+	if err := a.pluginManager.AddPlugin(p); err != nil {
+		return err
+	}
+	a.syncPluginQuoteProviders()
+	return nil
 }
 
 // UpdatePlugin 更新插件
@@ -4943,17 +6268,51 @@ func (a *App) UpdatePlugin(pluginData map[string]interface{}) error {
 		Config:      configJSON,
 	}
 
-	return a.pluginManager.UpdatePlugin(p)
+	if err := a.pluginManager.UpdatePlugin(p); err != nil {
+		return err
+	}
+	a.syncPluginQuoteProviders()
+	return nil
 }
 
 // DeletePlugin 删除插件
 func (a *App) DeletePlugin(id string) error {
-	return a.pluginManager.DeletePlugin(id)
+	if err := a.pluginManager.DeletePlugin(id); err != nil {
+		return err
+	}
+	a.syncPluginQuoteProviders()
+	return nil
 }
 
 // TogglePlugin 启用/禁用插件
 func (a *App) TogglePlugin(id string, enabled bool) error {
-	return a.pluginManager.TogglePlugin(id, enabled)
+	if err := a.pluginManager.TogglePlugin(id, enabled); err != nil {
+		return err
+	}
+	a.syncPluginQuoteProviders()
+	return nil
+}
+
+// pluginProviderPriority 数据源插件的默认优先级，排在内置数据源之后
+const pluginProviderPriority = 20
+
+// syncPluginQuoteProviders 将启用的数据源插件同步注册为行情数据源
+func (a *App) syncPluginQuoteProviders() {
+	if a.pluginManager == nil {
+		return
+	}
+	msm := data.GetMultiSourceManager()
+
+	active := make(map[string]bool)
+	for _, p := range a.pluginManager.DatasourceProviders() {
+		msm.RegisterQuoteProvider(p, pluginProviderPriority)
+		active[p.Name()] = true
+	}
+	for _, name := range msm.QuoteProviderNames() {
+		if data.IsPluginProvider(name) && !active[name] {
+			msm.UnregisterQuoteProvider(name)
+		}
+	}
 }
 
 // GetNotificationTemplates 获取预置通知模板
@@ -4967,7 +6326,11 @@ func (a *App) CreatePluginFromTemplate(templateID string, name string, params ma
 	if err != nil {
 		return err
 	}
-	return a.pluginManager.AddPlugin(p)
+	if err := a.pluginManager.AddPlugin(p); err != nil {
+		return err
+	}
+	a.syncPluginQuoteProviders()
+	return nil
 }
 
 // TestNotification 测试通知
@@ -4987,7 +6350,11 @@ func (a *App) HasEnabledNotificationPlugins() bool {
 
 // ImportPlugin 导入插件（从JSON字符串）
 func (a *App) ImportPlugin(jsonData string) (*plugin.Plugin, error) {
-	return a.pluginManager.ImportPlugin(jsonData)
+	p, err := a.pluginManager.ImportPlugin(jsonData)
+	if err == nil {
+		a.syncPluginQuoteProviders()
+	}
+	return p, err
 }
 
 // ExportPlugin 导出插件为JSON字符串
@@ -5015,6 +6382,9 @@ func (a *App) OpenPluginsDir() error {
 // RefreshPlugins 刷新插件列表（扫描并导入新插件文件）
 func (a *App) RefreshPlugins() (int, []string) {
 	imported, errors := a.pluginManager.ImportAllPluginFiles()
+	if imported > 0 {
+		a.syncPluginQuoteProviders()
+	}
 	var errStrings []string
 	for _, err := range errors {
 		errStrings = append(errStrings, err.Error())
@@ -5036,17 +6406,23 @@ func (a *App) GetDatasourceTemplates() []struct {
 
 // TestDatasource 测试数据源插件
 func (a *App) TestDatasource(pluginID string, code string) (*plugin.DatasourceResult, error) {
-	return a.pluginManager.TestDatasource(pluginID, code)
+	ctx, cancel := a.callContext()
+	defer cancel()
+	return a.pluginManager.TestDatasource(ctx, pluginID, code)
 }
 
 // FetchQuoteFromPlugin 从指定数据源插件获取行情
 func (a *App) FetchQuoteFromPlugin(pluginID string, code string) (*plugin.DatasourceResult, error) {
-	return a.pluginManager.FetchQuote(pluginID, code)
+	ctx, cancel := a.callContext()
+	defer cancel()
+	return a.pluginManager.FetchQuote(ctx, pluginID, code)
 }
 
 // FetchQuoteFromAllPlugins 从所有启用的数据源插件获取行情
 func (a *App) FetchQuoteFromAllPlugins(code string) (*plugin.DatasourceResult, string, error) {
-	return a.pluginManager.FetchQuoteFromAll(code)
+	ctx, cancel := a.callContext()
+	defer cancel()
+	return a.pluginManager.FetchQuoteFromAll(ctx, code)
 }
 
 // HasEnabledDatasourcePlugins 检查是否有启用的数据源插件
@@ -5231,6 +6607,8 @@ func (a *App) OpenPromptsDir() error {
 
 // ExecuteIndicatorPrompt 执行指标提示词分析
 func (a *App) ExecuteIndicatorPrompt(promptName string, stockCode string) (*prompt.IndicatorResult, error) {
+	ctx, cancel := a.callContext()
+	defer cancel()
 	if a.promptManager == nil {
 		return nil, fmt.Errorf("提示词管理器未初始化")
 	}
@@ -5242,7 +6620,7 @@ func (a *App) ExecuteIndicatorPrompt(promptName string, stockCode string) (*prom
 	}
 
 	// 获取股票数据
-	stockData, err := a.getStockDataForPrompt(stockCode)
+	stockData, err := a.getStockDataForPrompt(ctx, stockCode)
 	if err != nil {
 		return nil, err
 	}
@@ -5268,39 +6646,64 @@ func (a *App) ExecuteIndicatorPrompt(promptName string, stockCode string) (*prom
 
 // ExecuteScreenerPrompt 执行选股提示词
 func (a *App) ExecuteScreenerPrompt(promptName string) (*prompt.ScreenerResult, error) {
-	if a.promptManager == nil {
-		return nil, fmt.Errorf("提示词管理器未初始化")
-	}
-
-	// 获取提示词
-	promptInfo, err := a.promptManager.Get(prompt.PromptTypeScreener, promptName)
-	if err != nil {
-		return nil, fmt.Errorf("获取提示词失败: %w", err)
-	}
-
+	ctx, cancel := a.callContext()
+	defer cancel()
 	// 获取自选股列表数据
 	stocks, err := a.GetStockList()
 	if err != nil {
 		return nil, fmt.Errorf("获取股票列表失败: %w", err)
 	}
 
-	// 获取股票价格
 	var codes []string
 	for _, s := range stocks {
 		codes = append(codes, s.Code)
 	}
+	return a.executeScreenerPrompt(ctx, promptName, codes, "")
+}
+
+// ExecuteScreenerPromptOnScreen 以保存的量化选股结果作为 {stockList} 执行选股提示词
+func (a *App) ExecuteScreenerPromptOnScreen(promptName string, screenID uint) (*prompt.ScreenerResult, error) {
+	ctx, cancel := a.callContext()
+	defer cancel()
+	result, err := data.RunSavedScreen(screenID)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Matches) == 0 {
+		return nil, fmt.Errorf("选股条件当前没有入选股票")
+	}
+
+	var codes []string
+	for _, m := range result.Matches {
+		codes = append(codes, m.Code)
+	}
+	return a.executeScreenerPrompt(ctx, promptName, codes, result.Expression)
+}
+
+// executeScreenerPrompt 获取股票行情并执行选股提示词
+func (a *App) executeScreenerPrompt(ctx context.Context, promptName string, codes []string, expression string) (*prompt.ScreenerResult, error) {
+	if a.promptManager == nil {
+		return nil, fmt.Errorf("提示词管理器未初始化")
+	}
+
+	// 获取提示词
+	promptInfo, err := a.promptManager.Get(prompt.PromptTypeScreener, promptName)
+	if err != nil {
+		return nil, fmt.Errorf("获取提示词失败: %w", err)
+	}
 
-	prices, err := a.stockAPI.GetStockPrice(codes)
+	// 获取股票价格
+	prices, err := a.stockAPI.GetStockPrice(ctx, codes)
 	if err != nil {
 		return nil, fmt.Errorf("获取股票价格失败: %w", err)
 	}
 
 	// 构建股票数据列表
 	var stockDataList []*prompt.StockData
-	for _, s := range stocks {
-		if price, ok := prices[s.Code]; ok {
+	for _, code := range codes {
+		if price, ok := prices[code]; ok {
 			stockDataList = append(stockDataList, &prompt.StockData{
-				Code:          s.Code,
+				Code:          code,
 				Name:          price.Name,
 				Price:         price.Price,
 				Change:        price.Change,
@@ -5317,6 +6720,7 @@ func (a *App) ExecuteScreenerPrompt(promptName string) (*prompt.ScreenerResult,
 
 	// 构建提示词
 	builtPrompt := prompt.BuildPromptWithStockList(promptInfo.Content, stockDataList)
+	builtPrompt = strings.ReplaceAll(builtPrompt, "{screenExpression}", expression)
 
 	// 调用AI
 	aiResponse, err := a.callAIForPrompt(builtPrompt)
@@ -5335,6 +6739,8 @@ func (a *App) ExecuteScreenerPrompt(promptName string) (*prompt.ScreenerResult,
 
 // ExecuteReviewPrompt 执行复盘提示词
 func (a *App) ExecuteReviewPrompt(promptName string) (*prompt.ReviewResult, error) {
+	ctx, cancel := a.callContext()
+	defer cancel()
 	if a.promptManager == nil {
 		return nil, fmt.Errorf("提示词管理器未初始化")
 	}
@@ -5357,7 +6763,7 @@ func (a *App) ExecuteReviewPrompt(promptName string) (*prompt.ReviewResult, erro
 		codes = append(codes, p.StockCode)
 	}
 
-	prices, err := a.stockAPI.GetStockPrice(codes)
+	prices, err := a.stockAPI.GetStockPrice(ctx, codes)
 	if err != nil {
 		return nil, fmt.Errorf("获取股票价格失败: %w", err)
 	}
@@ -5436,16 +6842,16 @@ func (a *App) SetActivePersona(personaName string) error {
 }
 
 // getStockDataForPrompt 获取用于提示词的股票数据
-func (a *App) getStockDataForPrompt(stockCode string) (*prompt.StockData, error) {
+func (a *App) getStockDataForPrompt(ctx context.Context, stockCode string) (*prompt.StockData, error) {
 	stockCode = normalizeStockCode(stockCode)
 
-	stock, err := a.getPriceSnapshot(stockCode)
+	stock, err := a.getPriceSnapshot(ctx, stockCode)
 	if err != nil {
 		return nil, fmt.Errorf("获取股票价格失败: %w", err)
 	}
 
 	// 获取K线数据
-	klines, _ := a.getKLineDataCached(stockCode, 30)
+	klines, _ := a.getKLineDataCached(ctx, stockCode, 30)
 
 	// 构建股票数据
 	stockData := &prompt.StockData{
@@ -5498,3 +6904,137 @@ func (a *App) callAIForPrompt(promptText string) (string, error) {
 	}
 	return a.aiClient.Chat(messages)
 }
+
+// ========== 本地API服务 ==========
+
+// emitEvent 通知前端，并推送给本地API的事件订阅者
+func (a *App) emitEvent(name string, payload interface{}) {
+	wailsRuntime.EventsEmit(a.ctx, name, payload)
+	a.apiServer.Publish(name, payload)
+}
+
+// applyLocalAPIConfig 按配置启停本地API服务
+func (a *App) applyLocalAPIConfig(config *models.Config) error {
+	if config == nil || !config.LocalApiEnabled {
+		a.apiServer.Stop()
+		return nil
+	}
+	port := config.LocalApiPort
+	if port <= 0 {
+		port = localapi.DefaultPort
+	}
+	if err := a.apiServer.Start(port, config.LocalApiToken); err != nil {
+		log.Printf("[本地API] 启动失败: %v", err)
+		return err
+	}
+	return nil
+}
+
+// GetLocalAPIAddress 获取本地API服务地址，未运行时返回空字符串
+func (a *App) GetLocalAPIAddress() string {
+	if addr := a.apiServer.Addr(); addr != "" {
+		return "http://" + addr
+	}
+	return ""
+}
+
+// registerLocalAPIRoutes 注册本地API的只读接口，数据与前端绑定调用一致
+// 需要联网的接口使用请求自身的上下文，客户端断开时随之取消
+func (a *App) registerLocalAPIRoutes() {
+	s := a.apiServer
+
+	s.Handle("/api/stocks", func(r *http.Request) (interface{}, error) {
+		return a.GetStockList()
+	})
+	// 不带 codes 时返回定时刷新的价格缓存，不触发网络请求
+	s.Handle("/api/quotes", func(r *http.Request) (interface{}, error) {
+		if codes := localapi.SplitCodes(r.URL.Query().Get("codes")); len(codes) > 0 {
+			return a.getStockPrice(r.Context(), codes)
+		}
+		prices := make(map[string]*models.StockPrice)
+		a.stockPriceCache.Range(func(code string, price *models.StockPrice) bool {
+			prices[code] = price
+			return true
+		})
+		return prices, nil
+	})
+	s.Handle("/api/kline", func(r *http.Request) (interface{}, error) {
+		query := r.URL.Query()
+		code := strings.TrimSpace(query.Get("code"))
+		if code == "" {
+			return nil, localapi.BadRequest("缺少参数 code")
+		}
+		count := 120
+		if raw := query.Get("count"); raw != "" {
+			n, err := strconv.Atoi(raw)
+			if err != nil || n <= 0 {
+				return nil, localapi.BadRequest("参数 count 无效: %s", raw)
+			}
+			count = n
+		}
+		period := query.Get("period")
+		if period == "" {
+			period = "daily"
+		}
+		return a.getKLineDataCachedByPeriod(r.Context(), normalizeStockCode(code), period, count)
+	})
+	s.Handle("/api/funds", func(r *http.Request) (interface{}, error) {
+		return a.GetFundList()
+	})
+	s.Handle("/api/fund-quotes", func(r *http.Request) (interface{}, error) {
+		if codes := localapi.SplitCodes(r.URL.Query().Get("codes")); len(codes) > 0 {
+			return a.getFundPrice(r.Context(), codes)
+		}
+		prices := make(map[string]*models.FundPrice)
+		a.fundPriceCache.Range(func(code string, price *models.FundPrice) bool {
+			prices[code] = price
+			return true
+		})
+		return prices, nil
+	})
+	s.Handle("/api/positions", func(r *http.Request) (interface{}, error) {
+		return a.GetPositions()
+	})
+	s.Handle("/api/positions/history", func(r *http.Request) (interface{}, error) {
+		return a.GetPositionHistory()
+	})
+	s.Handle("/api/fund-positions", func(r *http.Request) (interface{}, error) {
+		code := strings.TrimSpace(r.URL.Query().Get("code"))
+		if code == "" {
+			return nil, localapi.BadRequest("缺少参数 code")
+		}
+		return a.GetFundPosition(code)
+	})
+	s.Handle("/api/alerts", func(r *http.Request) (interface{}, error) {
+		stockAlerts, err := a.GetAllAlerts()
+		if err != nil {
+			return nil, err
+		}
+		fundAlerts, err := a.GetFundAlerts("")
+		if err != nil {
+			return nil, err
+		}
+		cryptoAlerts, err := a.GetCryptoAlerts("")
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{
+			"stock":  stockAlerts,
+			"fund":   fundAlerts,
+			"crypto": cryptoAlerts,
+		}, nil
+	})
+	s.Handle("/api/ai/chats", func(r *http.Request) (interface{}, error) {
+		return a.GetAIChatHistory()
+	})
+	s.Handle("/api/ai/analyses", func(r *http.Request) (interface{}, error) {
+		return a.GetAIAnalysisHistory()
+	})
+	s.Handle("/api/digests", func(r *http.Request) (interface{}, error) {
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		return a.GetDailyDigests(limit)
+	})
+	s.Handle("/api/status", func(r *http.Request) (interface{}, error) {
+		return a.GetDataPipelineStatus()
+	})
+}
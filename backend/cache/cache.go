@@ -0,0 +1,344 @@
+// Package cache 提供带命名空间的分层缓存：LRU 内存层 + 可选的磁盘持久层
+//
+// 每个命名空间是一个强类型的 Cache[V]，独立配置 TTL、过期后可继续提供的
+// 旧值窗口（stale-while-revalidate）与容量上限；同一个键的并发加载会被合并为一次。
+// 命名空间以 "分组.名称" 命名（如 quote.forex_rates），可按分组统一清理与统计。
+package cache
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// backgroundRefreshTimeout 旧值窗口内后台刷新的最长耗时
+const backgroundRefreshTimeout = time.Minute
+
+// Options 命名空间配置
+type Options struct {
+	// Namespace 命名空间名称，格式为 "分组.名称"
+	Namespace string
+	// TTL 默认有效期，0 表示永不过期
+	TTL time.Duration
+	// StaleTTL 过期后仍保留并可作为旧值返回的时长
+	StaleTTL time.Duration
+	// MaxEntries 内存层最大条目数，超出后按最近最少使用淘汰，0 表示不限
+	MaxEntries int
+	// Persist 是否同时写入磁盘层，重启后可恢复
+	Persist bool
+}
+
+// Stats 命名空间统计
+type Stats struct {
+	Namespace  string `json:"namespace"`
+	Entries    int    `json:"entries"`
+	Expired    int    `json:"expired"`
+	MaxEntries int    `json:"maxEntries"`
+	Hits       int64  `json:"hits"`
+	StaleHits  int64  `json:"staleHits"`
+	DiskHits   int64  `json:"diskHits"`
+	Misses     int64  `json:"misses"`
+	Loads      int64  `json:"loads"`
+	LoadErrors int64  `json:"loadErrors"`
+	Evictions  int64  `json:"evictions"`
+	Persist    bool   `json:"persist"`
+}
+
+// entry 缓存条目
+type entry[V any] struct {
+	value    V
+	storedAt time.Time
+	expireAt time.Time // 零值表示永不过期
+}
+
+func (e *entry[V]) fresh(now time.Time) bool {
+	return e.expireAt.IsZero() || now.Before(e.expireAt)
+}
+
+// retained 是否仍在旧值窗口内
+func (e *entry[V]) retained(now time.Time, staleTTL time.Duration) bool {
+	return e.expireAt.IsZero() || now.Before(e.expireAt.Add(staleTTL))
+}
+
+// Cache 强类型命名空间缓存
+type Cache[V any] struct {
+	opts   Options
+	mu     sync.Mutex
+	mem    *lru[*entry[V]]
+	disk   *diskStore
+	flight FlightGroup[V]
+
+	hits, staleHits, diskHits, misses atomic.Int64
+	loads, loadErrors, evictions      atomic.Int64
+}
+
+// New 创建命名空间缓存并注册到全局注册表
+func New[V any](opts Options) *Cache[V] {
+	c := &Cache[V]{
+		opts: opts,
+		mem:  newLRU[*entry[V]](opts.MaxEntries),
+	}
+	if opts.Persist {
+		c.disk = newDiskStore(opts.Namespace)
+	}
+	register(c)
+	return c
+}
+
+// Name 命名空间名称
+func (c *Cache[V]) Name() string {
+	return c.opts.Namespace
+}
+
+// Get 获取未过期的值
+func (c *Cache[V]) Get(key string) (V, bool) {
+	e, ok := c.lookup(key)
+	if ok && e.fresh(time.Now()) {
+		c.hits.Add(1)
+		return e.value, true
+	}
+	c.misses.Add(1)
+	var zero V
+	return zero, false
+}
+
+// GetStale 获取仍保留的值（含已过期但在旧值窗口内的），fresh 表示是否仍在有效期内
+func (c *Cache[V]) GetStale(key string) (value V, fresh bool, ok bool) {
+	e, found := c.lookup(key)
+	now := time.Now()
+	if !found || !e.retained(now, c.opts.StaleTTL) {
+		var zero V
+		return zero, false, false
+	}
+	if e.fresh(now) {
+		c.hits.Add(1)
+	} else {
+		c.staleHits.Add(1)
+	}
+	return e.value, e.fresh(now), true
+}
+
+// Set 按默认TTL写入
+func (c *Cache[V]) Set(key string, value V) {
+	c.SetWithTTL(key, value, c.opts.TTL)
+}
+
+// SetWithTTL 按指定TTL写入，ttl 为 0 表示永不过期
+func (c *Cache[V]) SetWithTTL(key string, value V, ttl time.Duration) {
+	now := time.Now()
+	e := &entry[V]{value: value, storedAt: now}
+	if ttl > 0 {
+		e.expireAt = now.Add(ttl)
+	}
+	c.mu.Lock()
+	if c.mem.put(key, e) {
+		c.evictions.Add(1)
+	}
+	c.mu.Unlock()
+	if c.disk != nil {
+		if err := c.disk.save(key, e.value, e.storedAt, e.expireAt); err != nil {
+			log.Printf("[Cache] %s 写入磁盘失败: %v", c.opts.Namespace, err)
+		}
+	}
+}
+
+// GetOrLoad 获取值，未命中时调用 loader 加载并写入缓存
+// 已过期但仍在旧值窗口内时直接返回旧值并在后台刷新；同一键的并发加载只执行一次；
+// 加载失败且存在旧值时返回旧值。加载由多个调用方共享，所有调用方都取消或到期后才取消加载
+func (c *Cache[V]) GetOrLoad(ctx context.Context, key string, loader func(ctx context.Context) (V, error)) (V, error) {
+	e, found := c.lookup(key)
+	now := time.Now()
+	if found && e.fresh(now) {
+		c.hits.Add(1)
+		return e.value, nil
+	}
+	if found && e.retained(now, c.opts.StaleTTL) {
+		c.staleHits.Add(1)
+		// 调用方已拿到旧值返回，后台刷新不随其取消，但限定最长耗时
+		go func() {
+			refreshCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), backgroundRefreshTimeout)
+			defer cancel()
+			c.load(refreshCtx, key, loader)
+		}()
+		return e.value, nil
+	}
+
+	c.misses.Add(1)
+	value, err := c.load(ctx, key, loader)
+	if err != nil && found {
+		log.Printf("[Cache] %s 加载 %s 失败，返回旧值: %v", c.opts.Namespace, key, err)
+		return e.value, nil
+	}
+	return value, err
+}
+
+// load 通过 singleflight 调用 loader 并写入缓存
+func (c *Cache[V]) load(ctx context.Context, key string, loader func(ctx context.Context) (V, error)) (V, error) {
+	value, err, _ := c.flight.DoContext(ctx, key, func(ctx context.Context) (V, error) {
+		c.loads.Add(1)
+		value, err := loader(ctx)
+		if err != nil {
+			c.loadErrors.Add(1)
+			return value, err
+		}
+		c.Set(key, value)
+		return value, nil
+	})
+	return value, err
+}
+
+// Delete 删除指定键
+func (c *Cache[V]) Delete(key string) {
+	c.mu.Lock()
+	c.mem.remove(key)
+	c.mu.Unlock()
+	if c.disk != nil {
+		c.disk.remove(key)
+	}
+}
+
+// DeletePrefix 删除内存层中以 prefix 开头的全部键（磁盘层同步删除这些键），返回删除的数量
+func (c *Cache[V]) DeletePrefix(prefix string) int {
+	c.mu.Lock()
+	var keys []string
+	c.mem.each(func(key string, _ *entry[V]) {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	})
+	for _, key := range keys {
+		c.mem.remove(key)
+	}
+	c.mu.Unlock()
+	if c.disk != nil {
+		for _, key := range keys {
+			c.disk.remove(key)
+		}
+	}
+	return len(keys)
+}
+
+// Range 遍历内存层中未过期的条目，fn 返回 false 时停止
+func (c *Cache[V]) Range(fn func(key string, value V) bool) {
+	now := time.Now()
+	type kv struct {
+		key   string
+		value V
+	}
+	var items []kv
+	c.mu.Lock()
+	c.mem.each(func(key string, e *entry[V]) {
+		if e.fresh(now) {
+			items = append(items, kv{key, e.value})
+		}
+	})
+	c.mu.Unlock()
+	for _, item := range items {
+		if !fn(item.key, item.value) {
+			return
+		}
+	}
+}
+
+// Purge 清空命名空间（含磁盘层），返回清理的条目数
+func (c *Cache[V]) Purge() int {
+	c.mu.Lock()
+	count := c.mem.len()
+	c.mem.clear()
+	c.mu.Unlock()
+	if c.disk != nil {
+		if n := c.disk.clear(); n > count {
+			count = n
+		}
+	}
+	return count
+}
+
+// PurgeExpired 清理内存层与磁盘层超出旧值窗口的条目，返回清理的条目数
+func (c *Cache[V]) PurgeExpired() int {
+	now := time.Now()
+	return c.purgeExpiredMemory(now) + c.purgeExpiredDisk(now)
+}
+
+// purgeExpiredMemory 清理内存层超出旧值窗口的条目
+func (c *Cache[V]) purgeExpiredMemory(now time.Time) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var keys []string
+	c.mem.each(func(key string, e *entry[V]) {
+		if !e.retained(now, c.opts.StaleTTL) {
+			keys = append(keys, key)
+		}
+	})
+	for _, key := range keys {
+		c.mem.remove(key)
+	}
+	return len(keys)
+}
+
+// purgeExpiredDisk 清理磁盘层超出旧值窗口的文件，未开启持久化时返回0
+func (c *Cache[V]) purgeExpiredDisk(now time.Time) int {
+	if c.disk == nil {
+		return 0
+	}
+	return c.disk.purgeExpired(now, c.opts.StaleTTL, c.opts.MaxEntries)
+}
+
+// Stats 返回命名空间统计
+func (c *Cache[V]) Stats() Stats {
+	now := time.Now()
+	c.mu.Lock()
+	entries := c.mem.len()
+	expired := 0
+	c.mem.each(func(_ string, e *entry[V]) {
+		if !e.fresh(now) {
+			expired++
+		}
+	})
+	c.mu.Unlock()
+	return Stats{
+		Namespace:  c.opts.Namespace,
+		Entries:    entries,
+		Expired:    expired,
+		MaxEntries: c.opts.MaxEntries,
+		Hits:       c.hits.Load(),
+		StaleHits:  c.staleHits.Load(),
+		DiskHits:   c.diskHits.Load(),
+		Misses:     c.misses.Load(),
+		Loads:      c.loads.Load(),
+		LoadErrors: c.loadErrors.Load(),
+		Evictions:  c.evictions.Load(),
+		Persist:    c.opts.Persist,
+	}
+}
+
+// lookup 先查内存层，未命中再查磁盘层并回填内存
+func (c *Cache[V]) lookup(key string) (*entry[V], bool) {
+	c.mu.Lock()
+	e, ok := c.mem.get(key)
+	c.mu.Unlock()
+	if ok || c.disk == nil {
+		return e, ok
+	}
+
+	var value V
+	storedAt, expireAt, found := c.disk.load(key, &value)
+	if !found {
+		return nil, false
+	}
+	e = &entry[V]{value: value, storedAt: storedAt, expireAt: expireAt}
+	if !e.retained(time.Now(), c.opts.StaleTTL) {
+		c.disk.remove(key)
+		return nil, false
+	}
+	c.diskHits.Add(1)
+	c.mu.Lock()
+	if c.mem.put(key, e) {
+		c.evictions.Add(1)
+	}
+	c.mu.Unlock()
+	return e, true
+}
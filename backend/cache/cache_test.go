@@ -0,0 +1,177 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLRUEviction(t *testing.T) {
+	c := New[int](Options{Namespace: "test.lru", MaxEntries: 2})
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Get("a") // a 成为最近使用
+	c.Set("c", 3)
+
+	if _, ok := c.Get("b"); ok {
+		t.Fatal("b 应被淘汰")
+	}
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Fatalf("a = %v, %v", v, ok)
+	}
+	if got := c.Stats().Evictions; got != 1 {
+		t.Fatalf("Evictions = %d, want 1", got)
+	}
+}
+
+func TestStaleWhileRevalidate(t *testing.T) {
+	c := New[string](Options{Namespace: "test.swr", TTL: 20 * time.Millisecond, StaleTTL: time.Hour})
+	c.Set("k", "old")
+	time.Sleep(30 * time.Millisecond)
+
+	if _, ok := c.Get("k"); ok {
+		t.Fatal("过期值不应由 Get 返回")
+	}
+	refreshed := make(chan struct{})
+	v, err := c.GetOrLoad(context.Background(), "k", func(context.Context) (string, error) {
+		defer close(refreshed)
+		return "new", nil
+	})
+	if err != nil || v != "old" {
+		t.Fatalf("GetOrLoad = %q, %v; want 旧值", v, err)
+	}
+	<-refreshed
+	time.Sleep(10 * time.Millisecond)
+	if v, ok := c.Get("k"); !ok || v != "new" {
+		t.Fatalf("后台刷新后 Get = %q, %v", v, ok)
+	}
+}
+
+func TestGetOrLoadFallsBackOnError(t *testing.T) {
+	c := New[string](Options{Namespace: "test.fallback", TTL: 10 * time.Millisecond})
+	c.Set("k", "old")
+	time.Sleep(20 * time.Millisecond)
+
+	v, err := c.GetOrLoad(context.Background(), "k", func(context.Context) (string, error) { return "", errors.New("boom") })
+	if err != nil || v != "old" {
+		t.Fatalf("GetOrLoad = %q, %v; want 旧值", v, err)
+	}
+}
+
+func TestGetOrLoadCoalesces(t *testing.T) {
+	c := New[int](Options{Namespace: "test.flight", TTL: time.Minute})
+	var calls atomic.Int32
+	release := make(chan struct{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err := c.GetOrLoad(context.Background(), "k", func(context.Context) (int, error) {
+				calls.Add(1)
+				<-release
+				return 42, nil
+			})
+			if err != nil || v != 42 {
+				t.Errorf("GetOrLoad = %d, %v", v, err)
+			}
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if calls.Load() != 1 {
+		t.Fatalf("loader 调用 %d 次, want 1", calls.Load())
+	}
+}
+
+func TestGetOrLoadErrorWithoutStale(t *testing.T) {
+	c := New[int](Options{Namespace: "test.err", TTL: time.Minute})
+	want := errors.New("boom")
+	if _, err := c.GetOrLoad(context.Background(), "k", func(context.Context) (int, error) { return 0, want }); !errors.Is(err, want) {
+		t.Fatalf("err = %v, want %v", err, want)
+	}
+	if got := c.Stats().LoadErrors; got != 1 {
+		t.Fatalf("LoadErrors = %d, want 1", got)
+	}
+}
+
+func TestDiskTier(t *testing.T) {
+	SetDiskDir(t.TempDir())
+	defer SetDiskDir("")
+
+	type payload struct {
+		Name string  `json:"name"`
+		Vals []int64 `json:"vals"`
+	}
+	first := New[*payload](Options{Namespace: "test.disk", TTL: time.Hour, Persist: true})
+	first.Set("k", &payload{Name: "x", Vals: []int64{1, 2}})
+
+	// 模拟重启：新实例内存层为空，应从磁盘恢复
+	second := New[*payload](Options{Namespace: "test.disk", TTL: time.Hour, Persist: true})
+	v, ok := second.Get("k")
+	if !ok || v.Name != "x" || len(v.Vals) != 2 {
+		t.Fatalf("Get = %+v, %v", v, ok)
+	}
+	if second.Stats().DiskHits != 1 {
+		t.Fatalf("DiskHits = %d, want 1", second.Stats().DiskHits)
+	}
+
+	if n := Purge("test"); n == 0 {
+		t.Fatal("Purge 应清理条目")
+	}
+	third := New[*payload](Options{Namespace: "test.disk", TTL: time.Hour, Persist: true})
+	if _, ok := third.Get("k"); ok {
+		t.Fatal("Purge 后磁盘层应为空")
+	}
+}
+
+func TestFlightCancelsWhenAllWaitersLeave(t *testing.T) {
+	var g FlightGroup[int]
+	loadCtx := make(chan context.Context, 1)
+	fn := func(ctx context.Context) (int, error) {
+		loadCtx <- ctx
+		<-ctx.Done()
+		return 0, ctx.Err()
+	}
+
+	short, cancelShort := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancelShort()
+	long, cancelLong := context.WithTimeout(context.Background(), time.Hour)
+	defer cancelLong()
+
+	errs := make(chan error, 2)
+	go func() { _, err, _ := g.DoContext(short, "k", fn); errs <- err }()
+	shared := <-loadCtx
+	go func() { _, err, _ := g.DoContext(long, "k", fn); errs <- err }()
+
+	// 截止时间取等待方中最晚的一个
+	time.Sleep(10 * time.Millisecond)
+	if deadline, ok := shared.Deadline(); !ok || time.Until(deadline) < 50*time.Minute {
+		t.Fatalf("Deadline = %v, %v", deadline, ok)
+	}
+	if err := <-errs; !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("先到期的等待方 err = %v", err)
+	}
+	if shared.Err() != nil {
+		t.Fatal("仍有等待方时不应取消加载")
+	}
+
+	cancelLong()
+	if err := <-errs; !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v", err)
+	}
+	select {
+	case <-shared.Done():
+	case <-time.After(time.Second):
+		t.Fatal("所有等待方离开后应取消加载")
+	}
+	if !errors.Is(shared.Err(), context.Canceled) {
+		t.Fatalf("shared.Err() = %v", shared.Err())
+	}
+}
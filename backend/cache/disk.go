@@ -0,0 +1,167 @@
+package cache
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	diskRootMu sync.RWMutex
+	diskRoot   string
+)
+
+// SetDiskDir 设置磁盘层根目录，默认为 ~/.stock-ai/cache
+func SetDiskDir(dir string) {
+	diskRootMu.Lock()
+	defer diskRootMu.Unlock()
+	diskRoot = dir
+}
+
+// DiskDir 返回磁盘层根目录
+func DiskDir() string {
+	diskRootMu.RLock()
+	dir := diskRoot
+	diskRootMu.RUnlock()
+	if dir != "" {
+		return dir
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		homeDir = "."
+	}
+	return filepath.Join(homeDir, ".stock-ai", "cache")
+}
+
+// diskRecord 磁盘层文件内容
+type diskRecord struct {
+	Key      string          `json:"key"`
+	StoredAt time.Time       `json:"storedAt"`
+	ExpireAt time.Time       `json:"expireAt"`
+	Value    json.RawMessage `json:"value"`
+}
+
+// diskStore 磁盘层，每个键一个JSON文件，位于 <根目录>/<命名空间>/ 下
+type diskStore struct {
+	namespace string
+	mu        sync.Mutex
+}
+
+func newDiskStore(namespace string) *diskStore {
+	return &diskStore{namespace: namespace}
+}
+
+func (d *diskStore) dir() string {
+	return filepath.Join(DiskDir(), d.namespace)
+}
+
+func (d *diskStore) path(key string) string {
+	sum := sha1.Sum([]byte(key))
+	return filepath.Join(d.dir(), hex.EncodeToString(sum[:])+".json")
+}
+
+func (d *diskStore) save(key string, value interface{}, storedAt, expireAt time.Time) error {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(diskRecord{Key: key, StoredAt: storedAt, ExpireAt: expireAt, Value: raw})
+	if err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := os.MkdirAll(d.dir(), 0755); err != nil {
+		return err
+	}
+	// 先写临时文件再重命名，避免进程中断留下半个文件
+	path := d.path(key)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// load 读取键对应的记录并解码到 out
+func (d *diskStore) load(key string, out interface{}) (storedAt, expireAt time.Time, ok bool) {
+	d.mu.Lock()
+	data, err := os.ReadFile(d.path(key))
+	d.mu.Unlock()
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	var record diskRecord
+	if err := json.Unmarshal(data, &record); err != nil || record.Key != key {
+		return time.Time{}, time.Time{}, false
+	}
+	if err := json.Unmarshal(record.Value, out); err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	return record.StoredAt, record.ExpireAt, true
+}
+
+func (d *diskStore) remove(key string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	os.Remove(d.path(key))
+}
+
+// clear 删除命名空间下的全部文件，返回删除的数量
+func (d *diskStore) clear() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	files, _ := filepath.Glob(filepath.Join(d.dir(), "*.json"))
+	for _, f := range files {
+		os.Remove(f)
+	}
+	return len(files)
+}
+
+// purgeExpired 删除超出旧值窗口的文件，maxEntries 大于 0 时按写入时间仅保留最新的条目
+func (d *diskStore) purgeExpired(now time.Time, staleTTL time.Duration, maxEntries int) int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	files, _ := filepath.Glob(filepath.Join(d.dir(), "*.json"))
+	type kept struct {
+		path     string
+		storedAt time.Time
+	}
+	var live []kept
+	removed := 0
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		var record diskRecord
+		if err == nil {
+			err = json.Unmarshal(data, &record)
+		}
+		if err != nil || (!record.ExpireAt.IsZero() && !now.Before(record.ExpireAt.Add(staleTTL))) {
+			os.Remove(f)
+			removed++
+			continue
+		}
+		live = append(live, kept{f, record.StoredAt})
+	}
+
+	if maxEntries > 0 && len(live) > maxEntries {
+		sort.Slice(live, func(i, j int) bool { return live[i].storedAt.After(live[j].storedAt) })
+		for _, k := range live[maxEntries:] {
+			os.Remove(k.path)
+			removed++
+		}
+	}
+
+	// 清理异常中断残留的临时文件
+	tmps, _ := filepath.Glob(filepath.Join(d.dir(), "*.json.tmp"))
+	for _, f := range tmps {
+		os.Remove(f)
+	}
+	return removed
+}
@@ -0,0 +1,124 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// FlightGroup 合并同一键的并发调用，只有第一个调用方真正执行，其余等待并共享结果
+// 零值可直接使用
+type FlightGroup[V any] struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall[V]
+}
+
+type flightCall[V any] struct {
+	done    chan struct{}
+	value   V
+	err     error
+	ctx     *flightContext
+	cancel  context.CancelCauseFunc
+	waiters int // 仍在等待结果的调用方数量
+}
+
+// Do 执行 fn 并返回结果；若同一键已有调用在进行中则等待其结果，shared 表示结果来自其他调用方
+func (g *FlightGroup[V]) Do(key string, fn func() (V, error)) (value V, err error, shared bool) {
+	return g.DoContext(context.Background(), key, func(context.Context) (V, error) { return fn() })
+}
+
+// DoContext 与 Do 相同，fn 收到的上下文由所有等待方共享：保留首个调用方的上下文值，
+// 截止时间取各等待方中最晚的一个，最后一个等待方离开（ctx 取消或到期）时取消。
+// 调用方的 ctx 结束时立即返回 ctx.Err()，不影响仍在等待的其他调用方
+func (g *FlightGroup[V]) DoContext(ctx context.Context, key string, fn func(ctx context.Context) (V, error)) (value V, err error, shared bool) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*flightCall[V])
+	}
+	call, shared := g.calls[key]
+	if shared {
+		call.ctx.extendDeadline(ctx)
+	} else {
+		call = &flightCall[V]{done: make(chan struct{})}
+		call.ctx, call.cancel = newFlightContext(ctx)
+		g.calls[key] = call
+		go g.run(key, call, fn)
+	}
+	call.waiters++
+	g.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.value, call.err, shared
+	case <-ctx.Done():
+		g.mu.Lock()
+		call.waiters--
+		if call.waiters == 0 {
+			// 已无人等待，取消加载；之后的调用方重新发起
+			call.cancel(ctx.Err())
+			if g.calls[key] == call {
+				delete(g.calls, key)
+			}
+		}
+		g.mu.Unlock()
+		var zero V
+		return zero, ctx.Err(), shared
+	}
+}
+
+func (g *FlightGroup[V]) run(key string, call *flightCall[V], fn func(ctx context.Context) (V, error)) {
+	defer func() {
+		g.mu.Lock()
+		if g.calls[key] == call {
+			delete(g.calls, key)
+		}
+		g.mu.Unlock()
+		call.cancel(context.Canceled)
+		close(call.done)
+	}()
+	call.value, call.err = fn(call.ctx)
+}
+
+// flightContext 合并调用共享的上下文，Err 返回最后一个等待方离开的原因（取消或到期）
+type flightContext struct {
+	context.Context
+	mu       sync.Mutex
+	deadline time.Time
+	bounded  bool // 为 false 表示至少有一个等待方没有截止时间
+}
+
+func newFlightContext(ctx context.Context) (*flightContext, context.CancelCauseFunc) {
+	inner, cancel := context.WithCancelCause(context.WithoutCancel(ctx))
+	fc := &flightContext{Context: inner}
+	fc.deadline, fc.bounded = ctx.Deadline()
+	return fc, cancel
+}
+
+// extendDeadline 新的等待方加入时延长截止时间
+func (c *flightContext) extendDeadline(ctx context.Context) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.bounded {
+		return
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		c.bounded = false
+		c.deadline = time.Time{}
+	} else if deadline.After(c.deadline) {
+		c.deadline = deadline
+	}
+}
+
+func (c *flightContext) Deadline() (time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.deadline, c.bounded
+}
+
+func (c *flightContext) Err() error {
+	if c.Context.Err() == nil {
+		return nil
+	}
+	return context.Cause(c.Context)
+}
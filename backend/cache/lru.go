@@ -0,0 +1,73 @@
+package cache
+
+import "container/list"
+
+// lru 最近最少使用淘汰的内存层，调用方负责加锁
+type lru[T any] struct {
+	max   int
+	order *list.List
+	items map[string]*list.Element
+}
+
+type lruItem[T any] struct {
+	key   string
+	value T
+}
+
+func newLRU[T any](max int) *lru[T] {
+	return &lru[T]{
+		max:   max,
+		order: list.New(),
+		items: make(map[string]*list.Element),
+	}
+}
+
+func (l *lru[T]) get(key string) (T, bool) {
+	el, ok := l.items[key]
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	l.order.MoveToFront(el)
+	return el.Value.(*lruItem[T]).value, true
+}
+
+// put 写入条目，返回是否因超出容量淘汰了旧条目
+func (l *lru[T]) put(key string, value T) bool {
+	if el, ok := l.items[key]; ok {
+		el.Value.(*lruItem[T]).value = value
+		l.order.MoveToFront(el)
+		return false
+	}
+	l.items[key] = l.order.PushFront(&lruItem[T]{key: key, value: value})
+	if l.max > 0 && l.order.Len() > l.max {
+		oldest := l.order.Back()
+		l.order.Remove(oldest)
+		delete(l.items, oldest.Value.(*lruItem[T]).key)
+		return true
+	}
+	return false
+}
+
+func (l *lru[T]) remove(key string) {
+	if el, ok := l.items[key]; ok {
+		l.order.Remove(el)
+		delete(l.items, key)
+	}
+}
+
+func (l *lru[T]) each(fn func(key string, value T)) {
+	for el := l.order.Front(); el != nil; el = el.Next() {
+		item := el.Value.(*lruItem[T])
+		fn(item.key, item.value)
+	}
+}
+
+func (l *lru[T]) len() int {
+	return l.order.Len()
+}
+
+func (l *lru[T]) clear() {
+	l.order.Init()
+	l.items = make(map[string]*list.Element)
+}
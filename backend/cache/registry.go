@@ -0,0 +1,107 @@
+package cache
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Namespace 注册表中命名空间的类型无关视图
+type Namespace interface {
+	Name() string
+	Purge() int
+	PurgeExpired() int
+	Stats() Stats
+	purgeExpiredMemory(now time.Time) int
+	purgeExpiredDisk(now time.Time) int
+}
+
+// JanitorInterval 后台清理内存层过期条目的周期
+const JanitorInterval = time.Minute
+
+// DiskJanitorInterval 后台清理磁盘层过期文件的周期；磁盘清理需要读取并解析全部文件，
+// 启动后的第一次清理之后按此周期执行
+const DiskJanitorInterval = time.Hour
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Namespace)
+	janitor    sync.Once
+)
+
+func register(ns Namespace) {
+	registryMu.Lock()
+	registry[ns.Name()] = ns
+	registryMu.Unlock()
+
+	janitor.Do(func() {
+		go func() {
+			ticker := time.NewTicker(JanitorInterval)
+			defer ticker.Stop()
+			var lastDiskPurge time.Time
+			for now := range ticker.C {
+				diskDue := now.Sub(lastDiskPurge) >= DiskJanitorInterval
+				if diskDue {
+					lastDiskPurge = now
+				}
+				for _, ns := range Namespaces("") {
+					ns.purgeExpiredMemory(now)
+					if diskDue {
+						ns.purgeExpiredDisk(now)
+					}
+				}
+			}
+		}()
+	})
+}
+
+// GroupOf 返回命名空间所属分组（名称中第一个 "." 之前的部分）
+func GroupOf(namespace string) string {
+	if idx := strings.Index(namespace, "."); idx >= 0 {
+		return namespace[:idx]
+	}
+	return namespace
+}
+
+// Namespaces 返回属于指定分组的命名空间，group 为空时返回全部，按名称排序
+func Namespaces(group string) []Namespace {
+	registryMu.RLock()
+	list := make([]Namespace, 0, len(registry))
+	for name, ns := range registry {
+		if group == "" || GroupOf(name) == group {
+			list = append(list, ns)
+		}
+	}
+	registryMu.RUnlock()
+	sort.Slice(list, func(i, j int) bool { return list[i].Name() < list[j].Name() })
+	return list
+}
+
+// Purge 清空分组下的全部命名空间，group 为空时清空全部，返回清理的条目数
+func Purge(group string) int {
+	count := 0
+	for _, ns := range Namespaces(group) {
+		count += ns.Purge()
+	}
+	return count
+}
+
+// PurgeExpired 清理分组下超出旧值窗口的条目（含磁盘层），group 为空时处理全部
+func PurgeExpired(group string) int {
+	count := 0
+	for _, ns := range Namespaces(group) {
+		count += ns.PurgeExpired()
+	}
+	return count
+}
+
+// AllStats 返回全部命名空间的统计
+func AllStats() []Stats {
+	namespaces := Namespaces("")
+	stats := make([]Stats, 0, len(namespaces))
+	for _, ns := range namespaces {
+		stats = append(stats, ns.Stats())
+	}
+	return stats
+}
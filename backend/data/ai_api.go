@@ -10,6 +10,7 @@ import (
 	"log"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"stock-ai/backend/models"
@@ -17,17 +18,29 @@ import (
 
 // AIClient AI客户端
 type AIClient struct {
-	config *models.Config
-	client *http.Client
+	config       *models.Config
+	client       *http.Client
+	streamClient *http.Client
 }
 
-// NewAIClient 创建AI客户端
+// AI请求共用的HTTP客户端，连接池随代理配置变化由 RequestManager 重建
+var (
+	aiHTTPClientOnce sync.Once
+	aiHTTPClient     *http.Client
+	aiStreamClient   *http.Client
+)
+
+// NewAIClient 创建AI客户端，请求按代理路由表选择上游
 func NewAIClient(config *models.Config) *AIClient {
+	aiHTTPClientOnce.Do(func() {
+		aiHTTPClient = NewRoutedClient(60 * time.Second) // 60秒超时（流式响应需要较长时间）
+		// 流式请求不设置超时，由读取循环控制
+		aiStreamClient = NewRoutedClient(0)
+	})
 	return &AIClient{
-		config: config,
-		client: &http.Client{
-			Timeout: 60 * time.Second, // 60秒超时（流式响应需要较长时间）
-		},
+		config:       config,
+		client:       aiHTTPClient,
+		streamClient: aiStreamClient,
 	}
 }
 
@@ -120,10 +133,14 @@ func (c *AIClient) Chat(messages []ChatMessage) (string, error) {
 
 // ChatWithTimeout 允许自定义超时时间的聊天请求
 func (c *AIClient) ChatWithTimeout(messages []ChatMessage, timeout time.Duration) (string, error) {
-	ctx := context.Background()
-	var cancel context.CancelFunc
+	return c.ChatContext(context.Background(), messages, timeout)
+}
+
+// ChatContext 绑定 ctx 的聊天请求，ctx 取消时中止；timeout 大于0时另加超时
+func (c *AIClient) ChatContext(ctx context.Context, messages []ChatMessage, timeout time.Duration) (string, error) {
 	if timeout > 0 {
-		ctx, cancel = context.WithTimeout(context.Background(), timeout)
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
 		defer cancel()
 	}
 	return c.chatWithContext(ctx, messages)
@@ -181,7 +198,7 @@ func (c *AIClient) chatWithContext(ctx context.Context, messages []ChatMessage)
 }
 
 // ChatStream 发送聊天请求（流式）
-func (c *AIClient) ChatStream(messages []ChatMessage) (<-chan string, error) {
+func (c *AIClient) ChatStream(ctx context.Context, messages []ChatMessage) (<-chan string, error) {
 	baseURL, apiKey, model := c.getAPIConfig()
 
 	log.Printf("[AI] ChatStream开始: model=%s, baseURL=%s", model, baseURL)
@@ -201,10 +218,7 @@ func (c *AIClient) ChatStream(messages []ChatMessage) (<-chan string, error) {
 
 	log.Printf("[AI] 请求体长度: %d", len(jsonData))
 
-	// 流式请求不设置超时，由读取循环控制
-	client := &http.Client{}
-
-	req, err := http.NewRequest("POST", baseURL+"/chat/completions", bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "POST", baseURL+"/chat/completions", bytes.NewBuffer(jsonData))
 	if err != nil {
 		return nil, fmt.Errorf("创建请求失败: %v", err)
 	}
@@ -214,7 +228,7 @@ func (c *AIClient) ChatStream(messages []ChatMessage) (<-chan string, error) {
 	req.Header.Set("Accept", "text/event-stream")
 
 	log.Printf("[AI] 发送请求...")
-	resp, err := client.Do(req)
+	resp, err := c.streamClient.Do(req)
 	if err != nil {
 		log.Printf("[AI] 请求失败: %v", err)
 		return nil, fmt.Errorf("请求失败（请检查网络连接）: %v", err)
@@ -288,6 +302,7 @@ func BuildStockAnalysisPrompt(stock *models.StockPrice, klines []models.KLineDat
 	sb.WriteString(fmt.Sprintf("- 代码：%s\n", stock.Code))
 	sb.WriteString(fmt.Sprintf("- 名称：%s\n", stock.Name))
 	sb.WriteString(fmt.Sprintf("- 现价：%.2f\n", stock.Price))
+	sb.WriteString(QuoteQualityNote(stock))
 	sb.WriteString(fmt.Sprintf("- 涨跌幅：%.2f%%\n", stock.ChangePercent))
 	sb.WriteString(fmt.Sprintf("- 成交量：%d\n", stock.Volume))
 	sb.WriteString(fmt.Sprintf("- 成交额：%.2f\n\n", stock.Amount))
@@ -554,49 +569,60 @@ func BuildSummaryPrompt(content string, contentType string) string {
 `, typeDesc, content)
 }
 
-// BuildRecommendPrompt 构建市场分析提示词
-func BuildRecommendPrompt(indexes []models.MarketIndex, industries []models.IndustryRank, moneyFlow []models.MoneyFlow) string {
+// BuildRecommendPrompt 构建市场分析提示词，占位数据不会写入提示词；全部为占位数据时返回错误
+func BuildRecommendPrompt(indexes *models.MarketIndexResult, industries *models.IndustryRankResult, moneyFlow *models.MoneyFlowResult) (string, error) {
 	var sb strings.Builder
+	usable := 0
 
 	sb.WriteString("请根据以下市场数据，分析当前市场热点方向：\n\n")
 
 	// 市场指数
-	if len(indexes) > 0 {
+	if indexes != nil && !IsPlaceholder(indexes.Meta) && len(indexes.Items) > 0 {
+		usable++
 		sb.WriteString("## 市场指数\n")
-		for _, idx := range indexes {
+		sb.WriteString(DataFreshnessNote("市场指数", indexes.Meta))
+		for _, idx := range indexes.Items {
 			sb.WriteString(fmt.Sprintf("- %s: %.2f (%.2f%%)\n", idx.Name, idx.Price, idx.ChangePercent))
 		}
 		sb.WriteString("\n")
 	}
 
 	// 行业排行
-	if len(industries) > 0 {
+	if industries != nil && !IsPlaceholder(industries.Meta) && len(industries.Items) > 0 {
+		usable++
 		sb.WriteString("## 行业涨幅排行（前10）\n")
-		count := len(industries)
+		sb.WriteString(DataFreshnessNote("行业排行", industries.Meta))
+		count := len(industries.Items)
 		if count > 10 {
 			count = 10
 		}
 		for i := 0; i < count; i++ {
-			ind := industries[i]
+			ind := industries.Items[i]
 			sb.WriteString(fmt.Sprintf("- %s: %.2f%% (领涨股: %s)\n", ind.Name, ind.ChangePercent, ind.LeadStock))
 		}
 		sb.WriteString("\n")
 	}
 
 	// 资金流向
-	if len(moneyFlow) > 0 {
+	if moneyFlow != nil && !IsPlaceholder(moneyFlow.Meta) && len(moneyFlow.Items) > 0 {
+		usable++
 		sb.WriteString("## 主力资金流入（前10）\n")
-		count := len(moneyFlow)
+		sb.WriteString(DataFreshnessNote("资金流向", moneyFlow.Meta))
+		count := len(moneyFlow.Items)
 		if count > 10 {
 			count = 10
 		}
 		for i := 0; i < count; i++ {
-			mf := moneyFlow[i]
+			mf := moneyFlow.Items[i]
 			sb.WriteString(fmt.Sprintf("- %s(%s): 主力净流入%.2f亿\n", mf.Name, mf.Code, mf.MainFlow/100000000))
 		}
 		sb.WriteString("\n")
 	}
 
+	if usable == 0 {
+		return "", fmt.Errorf("市场数据暂不可用，请稍后重试")
+	}
+
 	sb.WriteString(`
 请分析：
 1. **市场整体情况**：当前市场处于什么状态
@@ -607,5 +633,5 @@ func BuildRecommendPrompt(indexes []models.MarketIndex, industries []models.Indu
 重要声明：以上分析由AI生成，仅供学习研究参考，不构成任何投资建议，不作为买卖依据。投资有风险，入市需谨慎。
 `)
 
-	return sb.String()
+	return sb.String(), nil
 }
@@ -1,6 +1,7 @@
 package data
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -11,21 +12,24 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"sync"
 	"time"
 )
 
 // AKShareClient AKShare数据客户端
-// AKShare是Python库，通过本地HTTP服务调用
+// 默认由Go原生实现直接请求 akshare 所用的上游接口（新浪财经、东方财富）；
+// 本地Python AKShare服务仅在显式开启后作为回退
 type AKShareClient struct {
-	baseURL     string
-	client      *http.Client
-	rateLimiter *RateLimiter
-	cache       *DataCache
-	serverCmd   *exec.Cmd
-	serverPort  int
-	mu          sync.RWMutex
-	running     bool
+	baseURL        string
+	client         *http.Client
+	rm             *RequestManager
+	rateLimiter    *RateLimiter
+	serverCmd      *exec.Cmd
+	serverPort     int
+	mu             sync.RWMutex
+	running        bool
+	pythonFallback bool
 }
 
 var (
@@ -49,10 +53,8 @@ func NewAKShareClient() *AKShareClient {
 		client: &http.Client{
 			Timeout: 60 * time.Second,
 		},
+		rm:          GetRequestManager(),
 		rateLimiter: GetRateLimiter(),
-		cache: &DataCache{
-			data: make(map[string]*CacheItem),
-		},
 	}
 }
 
@@ -63,9 +65,39 @@ func (c *AKShareClient) IsRunning() bool {
 	return c.running
 }
 
+// Name 数据源名称
+func (c *AKShareClient) Name() string {
+	return providerAKShare
+}
+
+// IsAvailable 原生实现无需Python，始终视为可用，失败由熔断处理
+func (c *AKShareClient) IsAvailable() bool {
+	return true
+}
+
+// SetPythonFallback 设置原生接口失败时是否回退到本地Python AKShare服务，关闭时停止已启动的服务
+func (c *AKShareClient) SetPythonFallback(enabled bool) {
+	c.mu.Lock()
+	changed := c.pythonFallback != enabled
+	c.pythonFallback = enabled
+	running := c.running
+	c.mu.Unlock()
+
+	if changed && !enabled && running {
+		c.StopServer()
+	}
+}
+
+// PythonFallbackEnabled 是否启用Python回退
+func (c *AKShareClient) PythonFallbackEnabled() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.pythonFallback
+}
+
 // CheckServer 检查服务器是否可用
-func (c *AKShareClient) CheckServer() bool {
-	resp, err := c.client.Get(c.baseURL + "/health")
+func (c *AKShareClient) CheckServer(ctx context.Context) bool {
+	resp, err := getWithContext(ctx, c.client, c.baseURL+"/health")
 	if err != nil {
 		return false
 	}
@@ -74,7 +106,7 @@ func (c *AKShareClient) CheckServer() bool {
 }
 
 // StartServer 启动AKShare服务
-func (c *AKShareClient) StartServer() error {
+func (c *AKShareClient) StartServer(ctx context.Context) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
@@ -106,7 +138,7 @@ func (c *AKShareClient) StartServer() error {
 	// 等待服务启动
 	for i := 0; i < 30; i++ {
 		time.Sleep(time.Second)
-		if c.CheckServer() {
+		if c.CheckServer(ctx) {
 			c.running = true
 			log.Printf("[AKShare] 服务已启动，端口: %d", c.serverPort)
 			return nil
@@ -185,16 +217,19 @@ import traceback
 from http.server import HTTPServer, BaseHTTPRequestHandler
 from urllib.parse import urlparse, parse_qs
 
-# 检查并安装akshare
+# akshare 需由用户自行安装，不在运行时自动下载
 try:
     import akshare as ak
     print(f"[AKShare] 版本: {ak.__version__}")
 except ImportError:
-    print("[AKShare] 正在安装akshare...")
-    import subprocess
-    subprocess.check_call([sys.executable, "-m", "pip", "install", "akshare", "-i", "https://pypi.tuna.tsinghua.edu.cn/simple"])
-    import akshare as ak
-    print(f"[AKShare] 安装完成，版本: {ak.__version__}")
+    print("[AKShare] 未安装akshare，请先执行: pip install akshare")
+    sys.exit(1)
+
+
+def to_records(df, limit):
+    """DataFrame 转为记录列表，NaN 转为 None（JSON 不支持 NaN）"""
+    df = df.head(limit)
+    return df.astype(object).where(df.notna(), None).to_dict('records')
 
 
 class AKShareHandler(BaseHTTPRequestHandler):
@@ -231,19 +266,22 @@ class AKShareHandler(BaseHTTPRequestHandler):
             elif path == '/balance':
                 # 获取资产负债表
                 code = params.get('code', '')
-                data = self.get_balance_sheet(code)
+                limit = int(params.get('limit', 4))
+                data = self.get_balance_sheet(code, limit)
                 self.send_json({'code': 0, 'data': data})
 
             elif path == '/income':
                 # 获取利润表
                 code = params.get('code', '')
-                data = self.get_income_statement(code)
+                limit = int(params.get('limit', 4))
+                data = self.get_income_statement(code, limit)
                 self.send_json({'code': 0, 'data': data})
 
             elif path == '/cashflow':
                 # 获取现金流量表
                 code = params.get('code', '')
-                data = self.get_cashflow(code)
+                limit = int(params.get('limit', 4))
+                data = self.get_cashflow(code, limit)
                 self.send_json({'code': 0, 'data': data})
 
             elif path == '/indicators':
@@ -271,6 +309,16 @@ class AKShareHandler(BaseHTTPRequestHandler):
             return code[2:]
         return code
 
+    def convert_em_code(self, code):
+        """转换为东方财富报表接口的代码格式（SH600519）"""
+        if code[:2] in ('sh', 'sz', 'bj'):
+            return code.upper()
+        if code.startswith('6'):
+            return 'SH' + code
+        if code.startswith(('4', '8')):
+            return 'BJ' + code
+        return 'SZ' + code
+
     def get_financial_data(self, code):
         """获取综合财务数据"""
         code = self.convert_code(code)
@@ -295,36 +343,36 @@ class AKShareHandler(BaseHTTPRequestHandler):
 
         return result
 
-    def get_balance_sheet(self, code):
+    def get_balance_sheet(self, code, limit=4):
         """获取资产负债表"""
-        code = self.convert_code(code)
+        code = self.convert_em_code(code)
         try:
             df = ak.stock_balance_sheet_by_report_em(symbol=code)
             if df is not None and len(df) > 0:
-                # 只返回最近4期
-                return df.head(4).to_dict('records')
+                # 默认只返回最近4期
+                return to_records(df, limit)
         except Exception as e:
             print(f"[AKShare] 获取资产负债表失败: {e}")
         return []
 
-    def get_income_statement(self, code):
+    def get_income_statement(self, code, limit=4):
         """获取利润表"""
-        code = self.convert_code(code)
+        code = self.convert_em_code(code)
         try:
             df = ak.stock_profit_sheet_by_report_em(symbol=code)
             if df is not None and len(df) > 0:
-                return df.head(4).to_dict('records')
+                return to_records(df, limit)
         except Exception as e:
             print(f"[AKShare] 获取利润表失败: {e}")
         return []
 
-    def get_cashflow(self, code):
+    def get_cashflow(self, code, limit=4):
         """获取现金流量表"""
-        code = self.convert_code(code)
+        code = self.convert_em_code(code)
         try:
             df = ak.stock_cash_flow_sheet_by_report_em(symbol=code)
             if df is not None and len(df) > 0:
-                return df.head(4).to_dict('records')
+                return to_records(df, limit)
         except Exception as e:
             print(f"[AKShare] 获取现金流量表失败: {e}")
         return []
@@ -377,11 +425,24 @@ if __name__ == '__main__':
 	return scriptPath, nil
 }
 
+// pythonFallbackRequest 原生接口失败且已启用Python回退时，改由本地AKShare服务获取
+func (c *AKShareClient) pythonFallbackRequest(ctx context.Context, nativeErr error, endpoint string, params map[string]string) (map[string]interface{}, error) {
+	if !c.PythonFallbackEnabled() {
+		return nil, nativeErr
+	}
+	log.Printf("[AKShare] 原生接口失败，回退到Python服务: %v", nativeErr)
+	result, err := c.request(ctx, endpoint, params)
+	if err != nil {
+		return nil, fmt.Errorf("%v（Python回退失败: %v）", nativeErr, err)
+	}
+	return result, nil
+}
+
 // request 发送请求（带限流保护）
-func (c *AKShareClient) request(endpoint string, params map[string]string) (map[string]interface{}, error) {
-	if !c.IsRunning() && !c.CheckServer() {
+func (c *AKShareClient) request(ctx context.Context, endpoint string, params map[string]string) (map[string]interface{}, error) {
+	if !c.IsRunning() && !c.CheckServer(ctx) {
 		// 尝试启动服务
-		if err := c.StartServer(); err != nil {
+		if err := c.StartServer(ctx); err != nil {
 			return nil, fmt.Errorf("AKShare服务未运行: %v", err)
 		}
 	}
@@ -390,7 +451,7 @@ func (c *AKShareClient) request(endpoint string, params map[string]string) (map[
 	var err error
 
 	err = c.rateLimiter.ExecuteWithRateLimit("akshare.local", func() error {
-		result, err = c.doRequest(endpoint, params)
+		result, err = c.doRequest(ctx, endpoint, params)
 		return err
 	})
 
@@ -398,7 +459,7 @@ func (c *AKShareClient) request(endpoint string, params map[string]string) (map[
 }
 
 // doRequest 实际执行请求
-func (c *AKShareClient) doRequest(endpoint string, params map[string]string) (map[string]interface{}, error) {
+func (c *AKShareClient) doRequest(ctx context.Context, endpoint string, params map[string]string) (map[string]interface{}, error) {
 	u, err := url.Parse(c.baseURL + endpoint)
 	if err != nil {
 		return nil, err
@@ -412,7 +473,7 @@ func (c *AKShareClient) doRequest(endpoint string, params map[string]string) (ma
 
 	log.Printf("[AKShare] 请求: %s", u.String())
 
-	resp, err := c.client.Get(u.String())
+	resp, err := getWithContext(ctx, c.client, u.String())
 	if err != nil {
 		return nil, fmt.Errorf("请求失败: %v", err)
 	}
@@ -436,179 +497,118 @@ func (c *AKShareClient) doRequest(endpoint string, params map[string]string) (ma
 	return result, nil
 }
 
-// getCache 获取缓存
-func (c *AKShareClient) getCache(key string) (interface{}, bool) {
-	c.cache.mu.RLock()
-	defer c.cache.mu.RUnlock()
-
-	item, ok := c.cache.data[key]
-	if !ok {
-		return nil, false
-	}
-
-	if time.Now().After(item.ExpireAt) {
-		return nil, false
-	}
-
-	return item.Data, true
-}
-
-// setCache 设置缓存
-func (c *AKShareClient) setCache(key string, data interface{}, ttl time.Duration) {
-	c.cache.mu.Lock()
-	defer c.cache.mu.Unlock()
-
-	c.cache.data[key] = &CacheItem{
-		Data:     data,
-		ExpireAt: time.Now().Add(ttl),
-	}
-}
-
 // GetFinancialData 获取财务数据
-func (c *AKShareClient) GetFinancialData(stockCode string) (*FinancialData, error) {
+func (c *AKShareClient) GetFinancialData(ctx context.Context, stockCode string) (*FinancialData, error) {
 	cacheKey := fmt.Sprintf("akshare_financial_%s", stockCode)
 
-	if cached, ok := c.getCache(cacheKey); ok {
+	if cached, ok := financialSummaryCache.Get(cacheKey); ok {
 		log.Printf("[AKShare] 使用缓存的财务数据: %s", stockCode)
-		return cached.(*FinancialData), nil
+		return cached, nil
 	}
 
-	result, err := c.request("/financial", map[string]string{"code": stockCode})
+	indicators, err := c.nativeFinancialIndicators(ctx, stockCode)
 	if err != nil {
-		return nil, err
-	}
-
-	data := &FinancialData{
-		Code: stockCode,
-	}
-
-	// 解析返回的数据
-	if dataMap, ok := result["data"].(map[string]interface{}); ok {
-		if indicators, ok := dataMap["indicators"].(map[string]interface{}); ok {
-			if v, ok := indicators["净资产收益率(%)"].(float64); ok {
-				data.ROE = v
-			}
-			if v, ok := indicators["总资产收益率(%)"].(float64); ok {
-				data.ROA = v
-			}
-			if v, ok := indicators["销售毛利率(%)"].(float64); ok {
-				data.GrossMargin = v
-			}
-			if v, ok := indicators["销售净利率(%)"].(float64); ok {
-				data.NetMargin = v
-			}
-			if v, ok := indicators["资产负债率(%)"].(float64); ok {
-				data.DebtRatio = v
-			}
-			if v, ok := indicators["流动比率"].(float64); ok {
-				data.CurrentRatio = v
-			}
-			if v, ok := indicators["速动比率"].(float64); ok {
-				data.QuickRatio = v
-			}
+		result, err := c.pythonFallbackRequest(ctx, err, "/financial", map[string]string{"code": stockCode})
+		if err != nil {
+			return nil, err
+		}
+		if dataMap, ok := result["data"].(map[string]interface{}); ok {
+			indicators, _ = dataMap["indicators"].(map[string]interface{})
 		}
 	}
 
-	c.setCache(cacheKey, data, time.Hour)
+	data := &FinancialData{
+		Code:          stockCode,
+		ROE:           emRowFloat(indicators, "净资产收益率(%)"),
+		ROA:           emRowFloat(indicators, "总资产收益率(%)", "总资产净利润率(%)"),
+		GrossMargin:   emRowFloat(indicators, "销售毛利率(%)"),
+		NetMargin:     emRowFloat(indicators, "销售净利率(%)"),
+		DebtRatio:     emRowFloat(indicators, "资产负债率(%)"),
+		CurrentRatio:  emRowFloat(indicators, "流动比率"),
+		QuickRatio:    emRowFloat(indicators, "速动比率"),
+		EPS:           emRowFloat(indicators, "摊薄每股收益(元)", "加权每股收益(元)"),
+		BPS:           emRowFloat(indicators, "每股净资产_调整前(元)", "每股净资产_调整后(元)"),
+		RevenueGrowth: emRowFloat(indicators, "主营业务收入增长率(%)"),
+		ProfitGrowth:  emRowFloat(indicators, "净利润增长率(%)"),
+	}
+	if date := emRowString(indicators, "日期"); len(date) >= 10 {
+		data.ReportDate = date[:10]
+	}
+
+	financialSummaryCache.Set(cacheKey, data)
 	return data, nil
 }
 
-// GetBalanceSheet 获取资产负债表
-func (c *AKShareClient) GetBalanceSheet(stockCode string) ([]map[string]interface{}, error) {
-	cacheKey := fmt.Sprintf("akshare_balance_%s", stockCode)
-
-	if cached, ok := c.getCache(cacheKey); ok {
-		return cached.([]map[string]interface{}), nil
+// statementRows 获取报表原始行，table 为 balance / income / cashflow
+func (c *AKShareClient) statementRows(ctx context.Context, stockCode, table string, limit int) ([]map[string]interface{}, error) {
+	rows, err := c.nativeStatementRows(ctx, stockCode, table, limit)
+	if err == nil {
+		return rows, nil
 	}
 
-	result, err := c.request("/balance", map[string]string{"code": stockCode})
+	result, err := c.pythonFallbackRequest(ctx, err, "/"+table, map[string]string{"code": stockCode, "limit": strconv.Itoa(limit)})
 	if err != nil {
 		return nil, err
 	}
-
-	var data []map[string]interface{}
 	if dataList, ok := result["data"].([]interface{}); ok {
 		for _, item := range dataList {
 			if m, ok := item.(map[string]interface{}); ok {
-				data = append(data, m)
+				rows = append(rows, m)
 			}
 		}
 	}
-
-	c.setCache(cacheKey, data, time.Hour)
-	return data, nil
+	return rows, nil
 }
 
-// GetIncomeStatement 获取利润表
-func (c *AKShareClient) GetIncomeStatement(stockCode string) ([]map[string]interface{}, error) {
-	cacheKey := fmt.Sprintf("akshare_income_%s", stockCode)
+// getStatementTable 获取最近4期报表（带缓存）
+func (c *AKShareClient) getStatementTable(ctx context.Context, stockCode, table string) ([]map[string]interface{}, error) {
+	cacheKey := fmt.Sprintf("akshare_%s_%s", table, stockCode)
 
-	if cached, ok := c.getCache(cacheKey); ok {
-		return cached.([]map[string]interface{}), nil
+	if cached, ok := financialTableCache.Get(cacheKey); ok {
+		return cached, nil
 	}
 
-	result, err := c.request("/income", map[string]string{"code": stockCode})
+	data, err := c.statementRows(ctx, stockCode, table, 4)
 	if err != nil {
 		return nil, err
 	}
 
-	var data []map[string]interface{}
-	if dataList, ok := result["data"].([]interface{}); ok {
-		for _, item := range dataList {
-			if m, ok := item.(map[string]interface{}); ok {
-				data = append(data, m)
-			}
-		}
-	}
-
-	c.setCache(cacheKey, data, time.Hour)
+	financialTableCache.Set(cacheKey, data)
 	return data, nil
 }
 
-// GetCashFlow 获取现金流量表
-func (c *AKShareClient) GetCashFlow(stockCode string) ([]map[string]interface{}, error) {
-	cacheKey := fmt.Sprintf("akshare_cashflow_%s", stockCode)
-
-	if cached, ok := c.getCache(cacheKey); ok {
-		return cached.([]map[string]interface{}), nil
-	}
-
-	result, err := c.request("/cashflow", map[string]string{"code": stockCode})
-	if err != nil {
-		return nil, err
-	}
+// GetBalanceSheet 获取资产负债表
+func (c *AKShareClient) GetBalanceSheet(ctx context.Context, stockCode string) ([]map[string]interface{}, error) {
+	return c.getStatementTable(ctx, stockCode, "balance")
+}
 
-	var data []map[string]interface{}
-	if dataList, ok := result["data"].([]interface{}); ok {
-		for _, item := range dataList {
-			if m, ok := item.(map[string]interface{}); ok {
-				data = append(data, m)
-			}
-		}
-	}
+// GetIncomeStatement 获取利润表
+func (c *AKShareClient) GetIncomeStatement(ctx context.Context, stockCode string) ([]map[string]interface{}, error) {
+	return c.getStatementTable(ctx, stockCode, "income")
+}
 
-	c.setCache(cacheKey, data, time.Hour)
-	return data, nil
+// GetCashFlow 获取现金流量表
+func (c *AKShareClient) GetCashFlow(ctx context.Context, stockCode string) ([]map[string]interface{}, error) {
+	return c.getStatementTable(ctx, stockCode, "cashflow")
 }
 
 // GetValuation 获取估值数据
-func (c *AKShareClient) GetValuation(stockCode string) (map[string]interface{}, error) {
+func (c *AKShareClient) GetValuation(ctx context.Context, stockCode string) (map[string]interface{}, error) {
 	cacheKey := fmt.Sprintf("akshare_valuation_%s", stockCode)
 
-	if cached, ok := c.getCache(cacheKey); ok {
-		return cached.(map[string]interface{}), nil
+	if cached, ok := financialValuationCache.Get(cacheKey); ok {
+		return cached, nil
 	}
 
-	result, err := c.request("/valuation", map[string]string{"code": stockCode})
+	data, err := c.nativeIndividualInfo(ctx, stockCode)
 	if err != nil {
-		return nil, err
-	}
-
-	var data map[string]interface{}
-	if dataMap, ok := result["data"].(map[string]interface{}); ok {
-		data = dataMap
+		result, err := c.pythonFallbackRequest(ctx, err, "/valuation", map[string]string{"code": stockCode})
+		if err != nil {
+			return nil, err
+		}
+		data, _ = result["data"].(map[string]interface{})
 	}
 
-	c.setCache(cacheKey, data, 30*time.Minute)
+	financialValuationCache.Set(cacheKey, data)
 	return data, nil
 }
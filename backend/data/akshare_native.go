@@ -0,0 +1,260 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/text/encoding/simplifiedchinese"
+)
+
+// ==================== AKShare 接口的Go原生实现 ====================
+//
+// 与 akshare 库请求相同的上游接口，无需Python环境：
+//   stock_financial_analysis_indicator -> 新浪财经 财务指标页
+//   stock_*_sheet_by_report_em         -> 东方财富F10 NewFinanceAnalysis
+//   stock_individual_info_em           -> 东方财富 push2 个股信息
+
+const (
+	sinaFinancialGuideURL = "https://money.finance.sina.com.cn/corp/go.php/vFD_FinancialGuideLine/stockid/%s/ctrl/%d/displaytype/4.phtml"
+	emFinanceAnalysisURL  = "https://emweb.securities.eastmoney.com/PC_HSF10/NewFinanceAnalysis/"
+	emFinanceReferer      = "https://emweb.securities.eastmoney.com/"
+	emReportDateBatch     = 5 // 报表接口每次最多查询的报告期数
+)
+
+// emStatementPages 三张报表在 NewFinanceAnalysis 中的接口前缀
+var emStatementPages = map[string]string{
+	"balance":  "zcfzb",
+	"income":   "lrb",
+	"cashflow": "xjllb",
+}
+
+// emIndividualInfoFields 个股信息字段（与 stock_individual_info_em 的输出一致）
+var emIndividualInfoFields = []struct {
+	field string
+	item  string
+}{
+	{"f57", "股票代码"},
+	{"f58", "股票简称"},
+	{"f43", "最新"},
+	{"f84", "总股本"},
+	{"f85", "流通股"},
+	{"f116", "总市值"},
+	{"f117", "流通市值"},
+	{"f127", "行业"},
+	{"f189", "上市时间"},
+}
+
+var (
+	sinaTableRowRe  = regexp.MustCompile(`(?is)<tr[^>]*>(.*?)</tr>`)
+	sinaTableCellRe = regexp.MustCompile(`(?is)<t[dh][^>]*>(.*?)</t[dh]>`)
+	htmlTagRe       = regexp.MustCompile(`(?s)<[^>]+>`)
+	emCompanyTypeRe = regexp.MustCompile(`id="hidctype"[^>]*value="(\d+)"`)
+)
+
+// toEMWebCode 转换为F10页面的代码格式，如 sh600519 -> SH600519
+func toEMWebCode(code string) (string, error) {
+	if _, err := toSecuCode(code); err != nil {
+		return "", err
+	}
+	return strings.ToUpper(code[:2]) + trimMarketPrefix(code), nil
+}
+
+// nativeFinancialIndicators 从新浪财经财务指标页获取最新一期指标，键为页面上的指标名（如“净资产收益率(%)”）
+func (c *AKShareClient) nativeFinancialIndicators(ctx context.Context, stockCode string) (map[string]interface{}, error) {
+	if _, err := toSecuCode(stockCode); err != nil {
+		return nil, err
+	}
+	symbol := trimMarketPrefix(stockCode)
+
+	// 年初新一年尚无报告期时回退到上一年
+	year := time.Now().Year()
+	for _, y := range []int{year, year - 1} {
+		body, err := getWithRateLimit(ctx, c.rm, fmt.Sprintf(sinaFinancialGuideURL, symbol, y), "https://finance.sina.com.cn/", "sina.com.cn")
+		if err != nil {
+			return nil, err
+		}
+		utf8Body, err := simplifiedchinese.GBK.NewDecoder().Bytes(body)
+		if err != nil {
+			return nil, fmt.Errorf("转换编码失败: %v", err)
+		}
+		if indicators := parseSinaFinancialGuide(string(utf8Body)); indicators != nil {
+			return indicators, nil
+		}
+	}
+	return nil, fmt.Errorf("无财务指标数据")
+}
+
+// parseSinaFinancialGuide 解析财务指标表格，取第一列（最新报告期）
+func parseSinaFinancialGuide(page string) map[string]interface{} {
+	start := strings.Index(page, `id="BalanceSheetNewTable0"`)
+	if start < 0 {
+		return nil
+	}
+	table := page[start:]
+	if end := strings.Index(table, "</table>"); end >= 0 {
+		table = table[:end]
+	}
+
+	var indicators map[string]interface{}
+	for _, row := range sinaTableRowRe.FindAllStringSubmatch(table, -1) {
+		cells := sinaTableCellRe.FindAllStringSubmatch(row[1], -1)
+		if len(cells) < 2 {
+			continue // 分组标题行
+		}
+		label := cleanHTMLText(cells[0][1])
+		value := cleanHTMLText(cells[1][1])
+		if label == "报告日期" {
+			if value == "" {
+				return nil
+			}
+			indicators = map[string]interface{}{"日期": value}
+			continue
+		}
+		if indicators == nil || label == "" {
+			continue
+		}
+		if f, err := strconv.ParseFloat(strings.ReplaceAll(value, ",", ""), 64); err == nil {
+			indicators[label] = f
+		}
+	}
+	return indicators
+}
+
+// cleanHTMLText 去除标签与实体，返回单元格文本
+func cleanHTMLText(s string) string {
+	s = html.UnescapeString(htmlTagRe.ReplaceAllString(s, ""))
+	return strings.TrimSpace(strings.ReplaceAll(s, "\u00a0", " "))
+}
+
+// getEMCompanyType 获取F10报表的公司类型（一般企业、银行、证券、保险的报表科目不同）
+func (c *AKShareClient) getEMCompanyType(ctx context.Context, emCode string) (string, error) {
+	cacheKey := fmt.Sprintf("akshare_ctype_%s", emCode)
+	if cached, ok := companyTypeCache.Get(cacheKey); ok {
+		return cached, nil
+	}
+
+	body, err := getWithRateLimit(ctx, c.rm, emFinanceAnalysisURL+"Index?type=web&code="+emCode, emFinanceReferer, "eastmoney.com")
+	if err != nil {
+		return "", err
+	}
+	m := emCompanyTypeRe.FindSubmatch(body)
+	if m == nil {
+		return "", fmt.Errorf("未找到公司类型: %s", emCode)
+	}
+	companyType := string(m[1])
+	companyTypeCache.Set(cacheKey, companyType)
+	return companyType, nil
+}
+
+// getEMFinanceAnalysis 请求 NewFinanceAnalysis 接口并返回 data 数组
+func (c *AKShareClient) getEMFinanceAnalysis(ctx context.Context, page string, params url.Values) ([]map[string]interface{}, error) {
+	body, err := getWithRateLimit(ctx, c.rm, emFinanceAnalysisURL+page+"?"+params.Encode(), emFinanceReferer, "eastmoney.com")
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Data []map[string]interface{} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("解析报表数据失败: %v", err)
+	}
+	return resp.Data, nil
+}
+
+// nativeStatementRows 获取按报告期的报表原始行（最近 limit 期，倒序），table 为 balance / income / cashflow
+func (c *AKShareClient) nativeStatementRows(ctx context.Context, stockCode, table string, limit int) ([]map[string]interface{}, error) {
+	page, ok := emStatementPages[table]
+	if !ok {
+		return nil, fmt.Errorf("未知报表类型: %s", table)
+	}
+	emCode, err := toEMWebCode(stockCode)
+	if err != nil {
+		return nil, err
+	}
+	companyType, err := c.getEMCompanyType(ctx, emCode)
+	if err != nil {
+		return nil, err
+	}
+
+	dateRows, err := c.getEMFinanceAnalysis(ctx, page+"DateAjaxNew", url.Values{
+		"companyType":    {companyType},
+		"reportDateType": {"0"},
+		"code":           {emCode},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var dates []string
+	for _, row := range dateRows {
+		if date := emRowString(row, "REPORT_DATE"); len(date) >= 10 {
+			dates = append(dates, date[:10])
+		}
+		if len(dates) >= limit {
+			break
+		}
+	}
+	if len(dates) == 0 {
+		return nil, fmt.Errorf("无报告期数据")
+	}
+
+	var rows []map[string]interface{}
+	for i := 0; i < len(dates); i += emReportDateBatch {
+		end := i + emReportDateBatch
+		if end > len(dates) {
+			end = len(dates)
+		}
+		batch, err := c.getEMFinanceAnalysis(ctx, page+"AjaxNew", url.Values{
+			"companyType":    {companyType},
+			"reportDateType": {"0"},
+			"reportType":     {"1"},
+			"dates":          {strings.Join(dates[i:end], ",")},
+			"code":           {emCode},
+		})
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, batch...)
+	}
+	return rows, nil
+}
+
+// nativeIndividualInfo 获取个股基本信息（代码、简称、股本、市值、行业等）
+func (c *AKShareClient) nativeIndividualInfo(ctx context.Context, stockCode string) (map[string]interface{}, error) {
+	secid, err := toEastMoneySecID(stockCode)
+	if err != nil {
+		return nil, err
+	}
+	fields := make([]string, 0, len(emIndividualInfoFields))
+	for _, f := range emIndividualInfoFields {
+		fields = append(fields, f.field)
+	}
+	u := fmt.Sprintf("https://push2.eastmoney.com/api/qt/stock/get?secid=%s&ut=%s&fltt=2&invt=2&fields=%s", secid, eastMoneyUT, strings.Join(fields, ","))
+	body, err := getWithRateLimit(ctx, c.rm, u, "https://quote.eastmoney.com/", "eastmoney.com")
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("解析个股信息失败: %v", err)
+	}
+	if resp.Data == nil {
+		return nil, fmt.Errorf("个股信息为空")
+	}
+	info := make(map[string]interface{}, len(emIndividualInfoFields))
+	for _, f := range emIndividualInfoFields {
+		if v, ok := resp.Data[f.field]; ok && v != "-" {
+			info[f.item] = v
+		}
+	}
+	return map[string]interface{}{"info": info}, nil
+}
@@ -0,0 +1,1081 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"stock-ai/backend/models"
+)
+
+// 跨资产分析支持的资产类型
+const (
+	AssetTypeFutures = "futures" // 期货
+	AssetTypeUSStock = "us"      // 美股
+	AssetTypeHKStock = "hk"      // 港股
+	AssetTypeForex   = "forex"   // 外汇
+	AssetTypeCrypto  = "crypto"  // 加密货币
+)
+
+// AssetAnalysisContext 跨资产AI分析上下文
+// 统一承载期货、美股、港股、外汇的行情与衍生指标，供提示词构建使用
+type AssetAnalysisContext struct {
+	AssetType     string             `json:"assetType"`
+	Code          string             `json:"code"`
+	Name          string             `json:"name"`
+	Currency      string             `json:"currency"`
+	Price         float64            `json:"price"`
+	Change        float64            `json:"change"`
+	ChangePercent float64            `json:"changePercent"`
+	Open          float64            `json:"open"`
+	High          float64            `json:"high"`
+	Low           float64            `json:"low"`
+	PreClose      float64            `json:"preClose"`
+	Volume        int64              `json:"volume"`
+	Amount        float64            `json:"amount"`
+	UpdateTime    string             `json:"updateTime"`
+	KLines        []models.KLineData `json:"klines"`
+	News          []models.NewsItem  `json:"news"`
+	MarketNews    bool               `json:"marketNews"` // News 为大盘资讯，未找到提及该标的的新闻
+
+	// 期货专属
+	Futures           *models.FuturesPrice   `json:"futures,omitempty"`
+	Product           *models.FuturesProduct `json:"product,omitempty"`
+	OpenInterest      int64                  `json:"openInterest"`      // 持仓量
+	OIChange          int64                  `json:"oiChange"`          // 持仓量较上一交易日变化
+	SpotCode          string                 `json:"spotCode"`          // 现货/标的代码
+	SpotName          string                 `json:"spotName"`          // 现货/标的名称
+	SpotPrice         float64                `json:"spotPrice"`         // 现货/标的价格
+	Basis             float64                `json:"basis"`             // 基差 = 现货 - 期货
+	MainContract      string                 `json:"mainContract"`      // 主力连续合约
+	MainPrice         float64                `json:"mainPrice"`         // 主力连续价格
+	CalendarSpread    float64                `json:"calendarSpread"`    // 当前合约 - 主力连续
+	HasSpotBasis      bool                   `json:"hasSpotBasis"`      // 是否成功计算基差
+	HasCalendarSpread bool                   `json:"hasCalendarSpread"` // 是否成功计算跨期价差
+
+	// 美股/港股专属
+	MarketCap float64 `json:"marketCap"`
+	PE        float64 `json:"pe"`
+	Exchange  string  `json:"exchange"`
+
+	// 外汇专属
+	Forex         *models.ForexRate  `json:"forex,omitempty"`
+	BaseCurrency  string             `json:"baseCurrency"`
+	QuoteCurrency string             `json:"quoteCurrency"`
+	BaseRate      float64            `json:"baseRate"`  // 基础货币政策利率（%）
+	QuoteRate     float64            `json:"quoteRate"` // 计价货币政策利率（%）
+	RateDiff      float64            `json:"rateDiff"`  // 利差 = 基础 - 计价
+	HasRateDiff   bool               `json:"hasRateDiff"`
+	RateAsOf      string             `json:"rateAsOf"`     // 政策利率参考的截止月份
+	RateSource    string             `json:"rateSource"`   // 政策利率参考的来源
+	RateOutdated  bool               `json:"rateOutdated"` // 政策利率参考过旧，未计算利差
+	RelatedRates  []models.ForexRate `json:"relatedRates"`
+
+	// 加密货币专属
+	Crypto         *models.CryptoPrice `json:"crypto,omitempty"`
+	QuoteVolume24h float64             `json:"quoteVolume24h"` // 24h成交额（USDT）
+	IntradayKLines []models.KLineData  `json:"intradayKlines"` // 4小时K线
+}
+
+// PolicyRateAsOf 内置政策利率参考值的截止月份
+const PolicyRateAsOf = "2025-10"
+
+// policyRateMaxAgeMonths 政策利率参考超过该月数未更新时不再计算利差，避免用过时的利率误导分析
+const policyRateMaxAgeMonths = 4
+
+// PolicyRateReference 内置的主要货币政策利率参考值（%），设置中未填写且无法实时获取时使用，仅用于利差方向判断
+var PolicyRateReference = map[string]float64{
+	"USD": 4.00, // 美联储联邦基金利率上限
+	"CNY": 1.40, // 央行7天逆回购利率
+	"EUR": 2.00, // 欧央行存款便利利率
+	"GBP": 4.00, // 英格兰银行基准利率
+	"JPY": 0.50, // 日本央行政策利率
+	"AUD": 3.60, // 澳洲联储现金利率
+	"CAD": 2.25, // 加拿大央行隔夜利率
+	"CHF": 0.00, // 瑞士央行政策利率
+	"NZD": 2.50, // 新西兰联储官方现金利率
+	"HKD": 4.25, // 香港金管局基本利率
+}
+
+// PolicyRateTable 生效的政策利率参考
+type PolicyRateTable struct {
+	Rates  map[string]float64
+	AsOf   string // 截止月份，格式 2006-01
+	Source string
+}
+
+// CurrentPolicyRates 返回生效的政策利率参考：设置中填写了则使用设置，否则使用内置参考值
+func CurrentPolicyRates(cfg *models.Config) PolicyRateTable {
+	if cfg != nil && strings.TrimSpace(cfg.PolicyRates) != "" {
+		if rates, err := ParsePolicyRates(cfg.PolicyRates); err == nil {
+			return PolicyRateTable{Rates: rates, AsOf: strings.TrimSpace(cfg.PolicyRatesAsOf), Source: PolicyRateSourceManual}
+		}
+	}
+	return PolicyRateTable{Rates: PolicyRateReference, AsOf: PolicyRateAsOf, Source: PolicyRateSourceBuiltin}
+}
+
+// ParsePolicyRates 解析每行“货币=利率”或“货币 利率”的政策利率设置，# 开头为注释
+func ParsePolicyRates(text string) (map[string]float64, error) {
+	rates := make(map[string]float64)
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.FieldsFunc(line, func(r rune) bool { return r == '=' || r == ' ' || r == '\t' })
+		if len(fields) != 2 || len(fields[0]) != 3 {
+			return nil, fmt.Errorf("政策利率格式错误: %s", line)
+		}
+		rate, err := strconv.ParseFloat(strings.TrimSuffix(fields[1], "%"), 64)
+		if err != nil {
+			return nil, fmt.Errorf("政策利率数值无效: %s", line)
+		}
+		rates[strings.ToUpper(fields[0])] = rate
+	}
+	if len(rates) == 0 {
+		return nil, fmt.Errorf("政策利率为空")
+	}
+	return rates, nil
+}
+
+// Outdated 截止月份距今超过 policyRateMaxAgeMonths 个月或无法解析时返回 true
+func (t PolicyRateTable) Outdated(now time.Time) bool {
+	asOf, err := time.ParseInLocation("2006-01", t.AsOf, now.Location())
+	if err != nil {
+		return true
+	}
+	// 截止月份按月末计算
+	return now.After(asOf.AddDate(0, policyRateMaxAgeMonths+1, 0))
+}
+
+// 股指期货对应的现货指数
+var futuresUnderlyingIndex = map[string]struct {
+	Code string
+	Name string
+}{
+	"IF": {Code: "sh000300", Name: "沪深300指数"},
+	"IH": {Code: "sh000016", Name: "上证50指数"},
+	"IC": {Code: "sh000905", Name: "中证500指数"},
+	"IM": {Code: "sh000852", Name: "中证1000指数"},
+}
+
+// AssetContextBuilder 跨资产分析上下文构建器
+type AssetContextBuilder struct {
+	rm              *RequestManager
+	stockAPI        *StockAPI
+	futuresAPI      *FuturesAPI
+	globalMarketAPI *GlobalMarketAPI
+	cryptoForexAPI  *CryptoForexAPI
+}
+
+// NewAssetContextBuilder 创建跨资产分析上下文构建器
+func NewAssetContextBuilder(stockAPI *StockAPI, futuresAPI *FuturesAPI, globalMarketAPI *GlobalMarketAPI, cryptoForexAPI *CryptoForexAPI) *AssetContextBuilder {
+	return &AssetContextBuilder{
+		rm:              GetRequestManager(),
+		stockAPI:        stockAPI,
+		futuresAPI:      futuresAPI,
+		globalMarketAPI: globalMarketAPI,
+		cryptoForexAPI:  cryptoForexAPI,
+	}
+}
+
+// IsSupportedAssetType 判断是否为跨资产分析支持的资产类型
+func IsSupportedAssetType(assetType string) bool {
+	switch assetType {
+	case AssetTypeFutures, AssetTypeUSStock, AssetTypeHKStock, AssetTypeForex, AssetTypeCrypto:
+		return true
+	}
+	return false
+}
+
+// NormalizeAssetCode 标准化资产代码
+func NormalizeAssetCode(assetType, code string) string {
+	code = strings.TrimSpace(code)
+	switch assetType {
+	case AssetTypeHKStock:
+		code = strings.TrimPrefix(strings.ToLower(code), "hk")
+		if len(code) > 0 && len(code) < 5 {
+			code = strings.Repeat("0", 5-len(code)) + code
+		}
+		return code
+	case AssetTypeForex:
+		return strings.ToUpper(strings.NewReplacer("/", "", "-", "", "_", "").Replace(code))
+	case AssetTypeCrypto:
+		return NormalizeCryptoSymbol(code)
+	default:
+		return strings.ToUpper(code)
+	}
+}
+
+// Build 构建指定资产的分析上下文
+func (b *AssetContextBuilder) Build(ctx context.Context, assetType, code string) (*AssetAnalysisContext, error) {
+	code = NormalizeAssetCode(assetType, code)
+	if code == "" {
+		return nil, fmt.Errorf("资产代码为空")
+	}
+
+	var ac *AssetAnalysisContext
+	var err error
+	switch assetType {
+	case AssetTypeFutures:
+		ac, err = b.buildFuturesContext(ctx, code)
+	case AssetTypeUSStock:
+		ac, err = b.buildUSStockContext(ctx, code)
+	case AssetTypeHKStock:
+		ac, err = b.buildHKStockContext(ctx, code)
+	case AssetTypeForex:
+		ac, err = b.buildForexContext(ctx, code)
+	case AssetTypeCrypto:
+		ac, err = b.buildCryptoContext(ctx, code)
+	default:
+		return nil, fmt.Errorf("不支持的资产类型: %s", assetType)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	klines, err := b.GetAssetKLine(ctx, assetType, code, 120)
+	if err != nil {
+		log.Printf("[跨资产分析] 获取K线失败(%s %s): %v", assetType, code, err)
+	} else {
+		ac.KLines = klines
+	}
+
+	// 期货持仓量变化取自日K线中的持仓字段
+	if assetType == AssetTypeFutures && len(ac.KLines) >= 2 && ac.OpenInterest > 0 {
+		ac.OIChange = ac.OpenInterest - ac.KLines[len(ac.KLines)-2].OpenInterest
+	}
+
+	return ac, nil
+}
+
+func (b *AssetContextBuilder) buildFuturesContext(ctx context.Context, code string) (*AssetAnalysisContext, error) {
+	product := findFuturesProduct(code)
+	codes := []string{code}
+	mainCode := ""
+	if product != nil {
+		mainCode = product.Code + "0"
+		if mainCode != code {
+			codes = append(codes, mainCode)
+		}
+	}
+
+	prices, err := b.futuresAPI.GetFuturesPrice(ctx, codes)
+	if err != nil {
+		return nil, fmt.Errorf("获取期货行情失败: %v", err)
+	}
+	price, ok := prices[code]
+	if !ok || price == nil || price.Price <= 0 {
+		return nil, fmt.Errorf("未获取到期货行情: %s", code)
+	}
+
+	ac := &AssetAnalysisContext{
+		AssetType:     AssetTypeFutures,
+		Code:          code,
+		Name:          price.Name,
+		Currency:      "CNY",
+		Price:         price.Price,
+		Change:        price.Change,
+		ChangePercent: price.ChangePercent,
+		Open:          price.Open,
+		High:          price.High,
+		Low:           price.Low,
+		PreClose:      price.PreClose,
+		Volume:        price.Volume,
+		Amount:        price.Amount,
+		UpdateTime:    price.UpdateTime,
+		Futures:       price,
+		Product:       product,
+		OpenInterest:  price.OpenInterest,
+		Exchange:      price.Exchange,
+	}
+
+	if mainCode != "" && mainCode != code {
+		if main, ok := prices[mainCode]; ok && main != nil && main.Price > 0 {
+			ac.MainContract = mainCode
+			ac.MainPrice = main.Price
+			ac.CalendarSpread = price.Price - main.Price
+			ac.HasCalendarSpread = true
+		}
+	}
+
+	// 股指期货：以对应现货指数计算基差
+	if product != nil && b.stockAPI != nil {
+		if underlying, ok := futuresUnderlyingIndex[product.Code]; ok {
+			ac.SpotCode = underlying.Code
+			ac.SpotName = underlying.Name
+			if spot, err := b.stockAPI.GetStockPrice(ctx, []string{underlying.Code}); err == nil {
+				if sp, ok := spot[underlying.Code]; ok && sp != nil && sp.Price > 0 {
+					ac.SpotPrice = sp.Price
+					ac.Basis = sp.Price - price.Price
+					ac.HasSpotBasis = true
+				}
+			} else {
+				log.Printf("[跨资产分析] 获取现货指数失败(%s): %v", underlying.Code, err)
+			}
+		}
+	}
+
+	return ac, nil
+}
+
+func (b *AssetContextBuilder) buildUSStockContext(ctx context.Context, symbol string) (*AssetAnalysisContext, error) {
+	prices, err := b.globalMarketAPI.GetUSStockPrice(ctx, []string{symbol})
+	price := prices[symbol]
+	if err != nil || price == nil || price.Price <= 0 {
+		// 新浪失败时回退到东方财富
+		fallback, fbErr := b.globalMarketAPI.GetUSStockPriceFromEastmoney(ctx, []string{symbol})
+		if fbErr == nil {
+			price = fallback[symbol]
+		}
+		if price == nil || price.Price <= 0 {
+			if err == nil {
+				err = fbErr
+			}
+			return nil, fmt.Errorf("获取美股行情失败: %v", err)
+		}
+	}
+
+	name := price.NameCN
+	if name == "" {
+		name = price.Name
+	}
+	ac := &AssetAnalysisContext{
+		AssetType:     AssetTypeUSStock,
+		Code:          symbol,
+		Name:          name,
+		Currency:      "USD",
+		Price:         price.Price,
+		Change:        price.Change,
+		ChangePercent: price.ChangePercent,
+		Open:          price.Open,
+		High:          price.High,
+		Low:           price.Low,
+		PreClose:      price.PreClose,
+		Volume:        price.Volume,
+		Amount:        price.Amount,
+		UpdateTime:    price.UpdateTime,
+		MarketCap:     price.MarketCap,
+		PE:            price.PE,
+		Exchange:      price.Exchange,
+	}
+
+	// 占位新闻不能作为分析依据
+	if news := b.globalMarketAPI.GetGlobalNews(ctx, "us"); !IsPlaceholder(news.Meta) {
+		ac.News, ac.MarketNews = assetNews(news.Items, price.Name, USStockSymbol(symbol))
+	}
+	return ac, nil
+}
+
+func (b *AssetContextBuilder) buildHKStockContext(ctx context.Context, code string) (*AssetAnalysisContext, error) {
+	prices, err := b.globalMarketAPI.GetHKStockPrice(ctx, []string{code})
+	if err != nil {
+		return nil, fmt.Errorf("获取港股行情失败: %v", err)
+	}
+	price, ok := prices[code]
+	if !ok || price == nil || price.Price <= 0 {
+		return nil, fmt.Errorf("未获取到港股行情: %s", code)
+	}
+
+	ac := &AssetAnalysisContext{
+		AssetType:     AssetTypeHKStock,
+		Code:          code,
+		Name:          price.Name,
+		Currency:      "HKD",
+		Price:         price.Price,
+		Change:        price.Change,
+		ChangePercent: price.ChangePercent,
+		Open:          price.Open,
+		High:          price.High,
+		Low:           price.Low,
+		PreClose:      price.PreClose,
+		Volume:        price.Volume,
+		Amount:        price.Amount,
+		UpdateTime:    price.UpdateTime,
+		MarketCap:     price.MarketCap,
+		PE:            price.PE,
+		Exchange:      "HKEX",
+	}
+
+	// 占位新闻不能作为分析依据
+	if news := b.globalMarketAPI.GetGlobalNews(ctx, "hk"); !IsPlaceholder(news.Meta) {
+		ac.News, ac.MarketNews = assetNews(news.Items, price.Name, code)
+	}
+	return ac, nil
+}
+
+// assetNews 从国际新闻中挑出标题或摘要提及该标的（名称或代码）的条目；
+// 一条都没有时原样返回并标记为大盘资讯，避免把市场新闻当作个股消息
+func assetNews(items []models.NewsItem, keywords ...string) ([]models.NewsItem, bool) {
+	var matchers []func(string) bool
+	for _, kw := range keywords {
+		kw = strings.TrimSpace(kw)
+		if len([]rune(kw)) < 2 {
+			continue
+		}
+		if kw == strings.ToUpper(kw) && strings.IndexFunc(kw, func(r rune) bool { return r > 127 }) < 0 {
+			// 英文代码按整词匹配，避免 MS 命中 MSFT
+			re := regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(kw) + `\b`)
+			matchers = append(matchers, re.MatchString)
+			continue
+		}
+		lower := strings.ToLower(kw)
+		matchers = append(matchers, func(text string) bool { return strings.Contains(strings.ToLower(text), lower) })
+	}
+
+	var related []models.NewsItem
+	for _, item := range items {
+		for _, match := range matchers {
+			if match(item.Title) || match(item.Content) {
+				related = append(related, item)
+				break
+			}
+		}
+	}
+	if len(related) == 0 {
+		return items, len(items) > 0
+	}
+	return related, false
+}
+
+func (b *AssetContextBuilder) buildForexContext(ctx context.Context, pair string) (*AssetAnalysisContext, error) {
+	if len(pair) != 6 {
+		return nil, fmt.Errorf("无效的货币对: %s", pair)
+	}
+
+	rates, err := b.cryptoForexAPI.GetForexRates(ctx)
+	var target *models.ForexRate
+	for i := range rates {
+		if rates[i].Pair == pair && rates[i].Rate > 0 {
+			target = &rates[i]
+			break
+		}
+	}
+	if target == nil {
+		if err != nil {
+			return nil, fmt.Errorf("获取外汇行情失败: %v", err)
+		}
+		return nil, fmt.Errorf("未获取到外汇行情: %s", pair)
+	}
+
+	ac := &AssetAnalysisContext{
+		AssetType:     AssetTypeForex,
+		Code:          pair,
+		Name:          target.Name,
+		Currency:      pair[3:],
+		Price:         target.Rate,
+		Change:        target.Change,
+		ChangePercent: target.ChangePercent,
+		High:          target.High,
+		Low:           target.Low,
+		PreClose:      target.Rate - target.Change,
+		UpdateTime:    target.UpdateTime,
+		Forex:         target,
+		BaseCurrency:  pair[:3],
+		QuoteCurrency: pair[3:],
+	}
+
+	policy := b.policyRates(ctx, ac.BaseCurrency, ac.QuoteCurrency)
+	ac.RateAsOf = policy.AsOf
+	ac.RateSource = policy.Source
+	ac.RateOutdated = policy.Outdated(time.Now())
+	baseRate, baseOK := policy.Rates[ac.BaseCurrency]
+	quoteRate, quoteOK := policy.Rates[ac.QuoteCurrency]
+	if baseOK && quoteOK && !ac.RateOutdated {
+		ac.BaseRate = baseRate
+		ac.QuoteRate = quoteRate
+		ac.RateDiff = baseRate - quoteRate
+		ac.HasRateDiff = true
+	}
+
+	// 关联货币对：与基础货币或计价货币相关的其它报价
+	for _, r := range rates {
+		if r.Pair == pair || r.Rate <= 0 {
+			continue
+		}
+		if strings.Contains(r.Pair, ac.BaseCurrency) || strings.Contains(r.Pair, ac.QuoteCurrency) {
+			ac.RelatedRates = append(ac.RelatedRates, r)
+		}
+	}
+
+	return ac, nil
+}
+
+func (b *AssetContextBuilder) buildCryptoContext(ctx context.Context, symbol string) (*AssetAnalysisContext, error) {
+	quote, err := b.cryptoForexAPI.GetCryptoQuote(ctx, symbol)
+	if err != nil {
+		return nil, fmt.Errorf("获取加密货币行情失败: %v", err)
+	}
+
+	ac := &AssetAnalysisContext{
+		AssetType:      AssetTypeCrypto,
+		Code:           symbol,
+		Name:           quote.Name,
+		Currency:       "USDT",
+		Price:          quote.Price,
+		Change:         quote.Change,
+		ChangePercent:  quote.ChangePercent,
+		Open:           quote.Open24h,
+		High:           quote.High24h,
+		Low:            quote.Low24h,
+		PreClose:       quote.Open24h,
+		Amount:         quote.QuoteVolume24h,
+		UpdateTime:     quote.UpdateTime,
+		Exchange:       quote.Source,
+		Crypto:         quote,
+		QuoteVolume24h: quote.QuoteVolume24h,
+	}
+
+	if klines, err := b.cryptoForexAPI.GetCryptoKLine(ctx, symbol, "4h", 60); err == nil {
+		ac.IntradayKLines = klines
+	} else {
+		log.Printf("[跨资产分析] 获取%s 4小时K线失败: %v", symbol, err)
+	}
+
+	return ac, nil
+}
+
+// findFuturesProduct 根据合约代码匹配期货品种（优先匹配最长品种代码）
+func findFuturesProduct(code string) *models.FuturesProduct {
+	code = strings.ToUpper(code)
+	prefix := strings.TrimRightFunc(code, func(r rune) bool { return r >= '0' && r <= '9' })
+
+	for i := range mainFuturesProducts {
+		if mainFuturesProducts[i].Code == prefix {
+			return &mainFuturesProducts[i]
+		}
+	}
+	return nil
+}
+
+// ==================== 跨资产K线 ====================
+
+// GetAssetKLine 获取非A股资产的日K线（期货走新浪，美股/港股/外汇走东方财富）
+func (b *AssetContextBuilder) GetAssetKLine(ctx context.Context, assetType, code string, count int) ([]models.KLineData, error) {
+	code = NormalizeAssetCode(assetType, code)
+	if count <= 0 {
+		count = 120
+	}
+
+	cacheKey := fmt.Sprintf("%s_%s_%d", assetType, code, count)
+	if cached, ok := assetKLineCache.Get(cacheKey); ok {
+		return cached, nil
+	}
+
+	var klines []models.KLineData
+	var err error
+	switch assetType {
+	case AssetTypeFutures:
+		klines, err = b.getFuturesKLineFromSina(ctx, code, count)
+	case AssetTypeUSStock:
+		klines, err = fetchEastMoneyDailyKLine(ctx, b.rm, []string{"105." + code, "106." + code, "107." + code}, code, count)
+	case AssetTypeHKStock:
+		klines, err = fetchEastMoneyDailyKLine(ctx, b.rm, []string{"116." + code}, code, count)
+	case AssetTypeForex:
+		klines, err = fetchEastMoneyDailyKLine(ctx, b.rm, []string{"119." + code, "133." + code, "120." + code}, code, count)
+	case AssetTypeCrypto:
+		klines, err = b.cryptoForexAPI.GetCryptoKLine(ctx, code, "1d", count)
+	default:
+		return nil, fmt.Errorf("不支持的资产类型: %s", assetType)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	assetKLineCache.Set(cacheKey, klines)
+	return klines, nil
+}
+
+// getFuturesKLineFromSina 从新浪获取期货日K线（含持仓量）
+func (b *AssetContextBuilder) getFuturesKLineFromSina(ctx context.Context, code string, count int) ([]models.KLineData, error) {
+	var url string
+	if product := findFuturesProduct(code); product != nil && product.Exchange == "CFFEX" {
+		url = fmt.Sprintf("https://stock2.finance.sina.com.cn/futures/api/jsonp.php/var%%20_=/CffexFuturesService.getCffexFuturesDailyKLine?symbol=%s", code)
+	} else {
+		url = fmt.Sprintf("https://stock2.finance.sina.com.cn/futures/api/jsonp.php/var%%20_=/InnerFuturesNewService.getDailyKLine?symbol=%s", code)
+	}
+
+	body, err := getWithRateLimit(ctx, b.rm, url, "https://finance.sina.com.cn/", "sina.com.cn")
+	if err != nil {
+		return nil, err
+	}
+
+	content := strings.TrimSpace(string(body))
+	if start := strings.Index(content, "("); start != -1 {
+		content = content[start+1:]
+	}
+	content = strings.TrimSuffix(strings.TrimSuffix(content, ";"), ")")
+
+	var rows []struct {
+		D string `json:"d"`
+		O string `json:"o"`
+		H string `json:"h"`
+		L string `json:"l"`
+		C string `json:"c"`
+		V string `json:"v"`
+		P string `json:"p"`
+	}
+	if err := json.Unmarshal([]byte(content), &rows); err != nil {
+		return nil, fmt.Errorf("解析期货K线失败: %v", err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("新浪期货K线为空: %s", code)
+	}
+	if len(rows) > count {
+		rows = rows[len(rows)-count:]
+	}
+
+	klines := make([]models.KLineData, 0, len(rows))
+	for _, row := range rows {
+		klines = append(klines, models.KLineData{
+			Date:         row.D,
+			Open:         parseFloat(row.O),
+			High:         parseFloat(row.H),
+			Low:          parseFloat(row.L),
+			Close:        parseFloat(row.C),
+			Volume:       parseInt(row.V),
+			OpenInterest: parseInt(row.P),
+			Code:         code,
+		})
+	}
+	return klines, nil
+}
+
+// fetchEastMoneyDailyKLine 依次尝试多个东方财富secid获取日K线
+func fetchEastMoneyDailyKLine(ctx context.Context, rm *RequestManager, secids []string, code string, count int) ([]models.KLineData, error) {
+	var lastErr error
+	for _, secid := range secids {
+		url := fmt.Sprintf(
+			"https://push2his.eastmoney.com/api/qt/stock/kline/get?secid=%s&ut=%s&klt=101&fqt=1&end=20500101&fields1=%s&fields2=%s&lmt=%d",
+			secid, eastMoneyUT, eastMoneyFields1, eastMoneyFields2, count,
+		)
+		body, err := getWithRateLimit(ctx, rm, url, "https://quote.eastmoney.com/", "push2his.eastmoney.com")
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		var result struct {
+			Data *struct {
+				Klines []string `json:"klines"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(body, &result); err != nil {
+			lastErr = err
+			continue
+		}
+		if result.Data == nil || len(result.Data.Klines) == 0 {
+			lastErr = fmt.Errorf("东方财富K线为空: %s", secid)
+			continue
+		}
+
+		klines := make([]models.KLineData, 0, len(result.Data.Klines))
+		for _, line := range result.Data.Klines {
+			parts := strings.Split(line, ",")
+			if len(parts) < 6 {
+				continue
+			}
+			klines = append(klines, models.KLineData{
+				Date:   parts[0],
+				Open:   parseFloat(parts[1]),
+				Close:  parseFloat(parts[2]),
+				High:   parseFloat(parts[3]),
+				Low:    parseFloat(parts[4]),
+				Volume: int64(parseFloat(parts[5])),
+				Code:   code,
+			})
+		}
+		if len(klines) > 0 {
+			return klines, nil
+		}
+	}
+
+	if lastErr == nil {
+		lastErr = fmt.Errorf("暂无可用的K线数据: %s", code)
+	}
+	return nil, lastErr
+}
+
+// getWithRateLimit 按域名限流发起GET请求并读取响应体
+func getWithRateLimit(ctx context.Context, rm *RequestManager, url, referer, domain string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	rm.SetRequestHeaders(req, referer)
+
+	resp, err := rm.DoRequestWithRateLimit(domain, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// ==================== 跨资产提示词 ====================
+
+// GetAssetSystemPrompt 获取跨资产分析的系统提示词
+func GetAssetSystemPrompt(assetType, analysisType string) string {
+	var role string
+	switch assetType {
+	case AssetTypeFutures:
+		role = "你是一位资深的期货分析师，熟悉国内商品期货与金融期货的期现结构、持仓变化与产业供需。"
+	case AssetTypeUSStock:
+		role = "你是一位专注美股市场的证券分析师，熟悉美国上市公司估值、财报季与美联储政策对股市的影响。"
+	case AssetTypeHKStock:
+		role = "你是一位专注港股市场的证券分析师，熟悉港股估值体系、南向资金与联系汇率制度下的流动性特征。"
+	case AssetTypeForex:
+		role = "你是一位外汇策略分析师，熟悉利率平价、央行政策分化、风险偏好与国际收支对汇率的影响。"
+	case AssetTypeCrypto:
+		role = "你是一位加密资产研究员，熟悉比特币周期、链上与交易所资金流、美元流动性对加密市场的影响，并清楚其7x24小时交易与高波动特征。"
+	default:
+		role = "你是一位专业的跨市场投资分析师。"
+	}
+
+	switch analysisType {
+	case "technical":
+		return role + "本次请侧重技术面：趋势、形态、关键价位、量能与技术指标，给出具体价位与操作建议。"
+	case "fundamental":
+		return role + "本次请侧重基本面：结合提供的结构性数据（基差/持仓、估值、利差等）判断中期方向。"
+	case "sentiment":
+		return role + "本次请侧重情绪面：市场风险偏好、资金/持仓动向与消息面影响。"
+	default:
+		return role + "请进行全面、客观的分析。"
+	}
+}
+
+// BuildAssetAnalysisPrompt 构建跨资产分析提示词
+func BuildAssetAnalysisPrompt(ctx *AssetAnalysisContext, analysisType string) string {
+	var sb strings.Builder
+
+	typeLabel := map[string]string{
+		"fundamental": "基本面分析",
+		"technical":   "技术面分析",
+		"sentiment":   "情绪面分析",
+		"master":      "大师视角分析",
+	}[analysisType]
+	if typeLabel == "" {
+		typeLabel = "综合分析"
+	}
+
+	sb.WriteString(fmt.Sprintf("请对以下%s进行**%s**：\n\n", AssetTypeLabel(ctx.AssetType), typeLabel))
+	sb.WriteString("## 行情信息\n")
+	sb.WriteString(fmt.Sprintf("- 代码：%s\n", ctx.Code))
+	if ctx.Name != "" {
+		sb.WriteString(fmt.Sprintf("- 名称：%s\n", ctx.Name))
+	}
+	priceFormat := "%.2f"
+	if ctx.AssetType == AssetTypeForex || (ctx.AssetType == AssetTypeCrypto && ctx.Price < 1) {
+		priceFormat = "%.4f"
+	}
+	sb.WriteString(fmt.Sprintf("- 现价："+priceFormat+" %s\n", ctx.Price, ctx.Currency))
+	sb.WriteString(fmt.Sprintf("- 涨跌幅：%.2f%%\n", ctx.ChangePercent))
+	if ctx.High > 0 && ctx.Low > 0 {
+		sb.WriteString(fmt.Sprintf("- 日内区间："+priceFormat+" ~ "+priceFormat+"\n", ctx.Low, ctx.High))
+	}
+	if ctx.Volume > 0 {
+		sb.WriteString(fmt.Sprintf("- 成交量：%s\n", formatVolume(ctx.Volume)))
+	}
+	if ctx.UpdateTime != "" {
+		sb.WriteString(fmt.Sprintf("- 更新时间：%s\n", ctx.UpdateTime))
+	}
+	sb.WriteString("\n")
+
+	switch ctx.AssetType {
+	case AssetTypeFutures:
+		sb.WriteString(buildFuturesSection(ctx))
+	case AssetTypeUSStock, AssetTypeHKStock:
+		sb.WriteString(buildOverseasStockSection(ctx))
+	case AssetTypeForex:
+		sb.WriteString(buildForexSection(ctx))
+	case AssetTypeCrypto:
+		sb.WriteString(buildCryptoSection(ctx, priceFormat))
+	}
+
+	if len(ctx.KLines) > 0 {
+		sb.WriteString(summarizeKLinePeriod("日线指标", ctx.KLines))
+		sb.WriteString("## 近期日K线\n")
+		start := len(ctx.KLines) - 20
+		if start < 0 {
+			start = 0
+		}
+		for _, k := range ctx.KLines[start:] {
+			line := fmt.Sprintf("- %s: 开"+priceFormat+" 高"+priceFormat+" 低"+priceFormat+" 收"+priceFormat,
+				k.Date, k.Open, k.High, k.Low, k.Close)
+			if k.Volume > 0 {
+				line += fmt.Sprintf(" 量%d", k.Volume)
+			}
+			if k.OpenInterest > 0 {
+				line += fmt.Sprintf(" 持仓%d", k.OpenInterest)
+			}
+			sb.WriteString(line + "\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(ctx.News) > 0 && analysisType != "technical" {
+		if ctx.MarketNews {
+			sb.WriteString("## 大盘资讯（未找到该标的专属新闻，仅作市场背景参考）\n")
+		} else {
+			sb.WriteString("## 标的相关新闻\n")
+		}
+		count := min(5, len(ctx.News))
+		for _, n := range ctx.News[:count] {
+			sb.WriteString(fmt.Sprintf("- [%s] %s\n", n.Time, n.Title))
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(assetAnalysisOutline(ctx.AssetType, analysisType))
+	return sb.String()
+}
+
+// AssetTypeLabel 资产类型中文名称
+func AssetTypeLabel(assetType string) string {
+	switch assetType {
+	case AssetTypeFutures:
+		return "期货合约"
+	case AssetTypeUSStock:
+		return "美股"
+	case AssetTypeHKStock:
+		return "港股"
+	case AssetTypeForex:
+		return "外汇货币对"
+	case AssetTypeCrypto:
+		return "加密货币"
+	default:
+		return "资产"
+	}
+}
+
+func buildFuturesSection(ctx *AssetAnalysisContext) string {
+	var sb strings.Builder
+	sb.WriteString("## 期货结构数据\n")
+	if ctx.Product != nil {
+		sb.WriteString(fmt.Sprintf("- 品种：%s（%s），交易单位 %s，保证金约 %s\n",
+			ctx.Product.Name, futuresExchangeMap[ctx.Product.Exchange], ctx.Product.Unit, ctx.Product.Margin))
+	}
+	if ctx.Futures != nil {
+		if ctx.Futures.PreSettle > 0 {
+			sb.WriteString(fmt.Sprintf("- 昨结算：%.2f\n", ctx.Futures.PreSettle))
+		}
+		if ctx.Futures.Settle > 0 {
+			sb.WriteString(fmt.Sprintf("- 今结算：%.2f\n", ctx.Futures.Settle))
+		}
+	}
+	if ctx.OpenInterest > 0 {
+		sb.WriteString(fmt.Sprintf("- 持仓量：%d 手", ctx.OpenInterest))
+		if ctx.OIChange != 0 {
+			sb.WriteString(fmt.Sprintf("（较上一交易日 %+d 手）", ctx.OIChange))
+		}
+		sb.WriteString("\n")
+	}
+	if ctx.HasSpotBasis {
+		basisPct := ctx.Basis / ctx.Price * 100
+		state := "期货贴水（现货 > 期货）"
+		if ctx.Basis < 0 {
+			state = "期货升水（期货 > 现货）"
+		}
+		sb.WriteString(fmt.Sprintf("- 现货标的：%s %.2f\n", ctx.SpotName, ctx.SpotPrice))
+		sb.WriteString(fmt.Sprintf("- 基差（现货-期货）：%.2f（%.2f%%），%s\n", ctx.Basis, basisPct, state))
+	} else {
+		sb.WriteString("- 基差：暂无可靠的现货报价，请结合品种季节性与产业库存自行判断期现结构\n")
+	}
+	if ctx.HasCalendarSpread {
+		sb.WriteString(fmt.Sprintf("- 跨期价差（本合约-主力连续%s）：%.2f\n", ctx.MainContract, ctx.CalendarSpread))
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+func buildOverseasStockSection(ctx *AssetAnalysisContext) string {
+	if ctx.MarketCap <= 0 && ctx.PE <= 0 && ctx.Exchange == "" {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("## 估值与市场\n")
+	if ctx.Exchange != "" {
+		sb.WriteString(fmt.Sprintf("- 交易所：%s\n", ctx.Exchange))
+	}
+	if ctx.MarketCap > 0 {
+		sb.WriteString(fmt.Sprintf("- 市值：%.2f 亿%s\n", ctx.MarketCap/1e8, ctx.Currency))
+	}
+	if ctx.PE > 0 {
+		sb.WriteString(fmt.Sprintf("- 市盈率：%.2f\n", ctx.PE))
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+func buildForexSection(ctx *AssetAnalysisContext) string {
+	var sb strings.Builder
+	sb.WriteString("## 利差与关联汇率\n")
+	sb.WriteString(fmt.Sprintf("- 基础货币：%s，计价货币：%s\n", ctx.BaseCurrency, ctx.QuoteCurrency))
+	if ctx.HasRateDiff {
+		note := "可能已调整"
+		if ctx.RateSource == PolicyRateSourceLive {
+			note = ctx.RateSource
+		}
+		sb.WriteString(fmt.Sprintf("- 政策利率参考（截至%s，%s）：%s %.2f%% / %s %.2f%%\n",
+			ctx.RateAsOf, note, ctx.BaseCurrency, ctx.BaseRate, ctx.QuoteCurrency, ctx.QuoteRate))
+		direction := "基础货币利率更高，利差支撑多头套息"
+		if ctx.RateDiff < 0 {
+			direction = "计价货币利率更高，利差不利于持有基础货币"
+		} else if ctx.RateDiff == 0 {
+			direction = "两国利率持平，利差中性"
+		}
+		sb.WriteString(fmt.Sprintf("- 利差（基础-计价）：%+.2f 个百分点，%s\n", ctx.RateDiff, direction))
+	} else if ctx.RateOutdated {
+		sb.WriteString(fmt.Sprintf("- 利差：政策利率参考截至%s，已超过%d个月未更新，不提供利差数据，请勿自行假设两国利率\n", ctx.RateAsOf, policyRateMaxAgeMonths))
+	} else {
+		sb.WriteString("- 利差：缺少该货币对的政策利率参考\n")
+	}
+	if len(ctx.RelatedRates) > 0 {
+		rates := append([]models.ForexRate(nil), ctx.RelatedRates...)
+		sort.Slice(rates, func(i, j int) bool { return rates[i].Pair < rates[j].Pair })
+		sb.WriteString("- 关联货币对：")
+		items := make([]string, 0, len(rates))
+		for _, r := range rates {
+			items = append(items, fmt.Sprintf("%s %.4f（%+.2f%%）", r.Pair, r.Rate, r.ChangePercent))
+		}
+		sb.WriteString(strings.Join(items, "；"))
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+func buildCryptoSection(ctx *AssetAnalysisContext, priceFormat string) string {
+	var sb strings.Builder
+	sb.WriteString("## 24小时统计\n")
+	if ctx.Exchange != "" {
+		sb.WriteString(fmt.Sprintf("- 数据来源：%s（USDT交易对，7x24小时交易）\n", ctx.Exchange))
+	}
+	if ctx.Open > 0 {
+		sb.WriteString(fmt.Sprintf("- 24h开盘："+priceFormat+"，涨跌额：%+.4f\n", ctx.Open, ctx.Change))
+	}
+	if ctx.High > 0 && ctx.Low > 0 {
+		amplitude := (ctx.High - ctx.Low) / ctx.Low * 100
+		sb.WriteString(fmt.Sprintf("- 24h振幅：%.2f%%\n", amplitude))
+	}
+	if ctx.Crypto != nil && ctx.Crypto.Volume24h > 0 {
+		sb.WriteString(fmt.Sprintf("- 24h成交量：%.2f %s\n", ctx.Crypto.Volume24h, ctx.Code))
+	}
+	if ctx.QuoteVolume24h > 0 {
+		sb.WriteString(fmt.Sprintf("- 24h成交额：%.2f 亿USDT\n", ctx.QuoteVolume24h/1e8))
+	}
+	sb.WriteString("\n")
+	if len(ctx.IntradayKLines) > 0 {
+		sb.WriteString(summarizeKLinePeriod("4小时线指标", ctx.IntradayKLines))
+	}
+	return sb.String()
+}
+
+func assetAnalysisOutline(assetType, analysisType string) string {
+	var focus string
+	switch assetType {
+	case AssetTypeFutures:
+		switch analysisType {
+		case "technical":
+			focus = `1. **趋势结构**：均线与价格通道，主力合约换月影响
+2. **量仓配合**：价格与持仓量、成交量的组合（增仓上涨/减仓下跌等）
+3. **支撑压力**：关键价位与突破条件
+4. **技术指标**：MACD、KDJ、RSI 状态
+5. **策略建议**：入场、止损、仓位与杠杆控制`
+		default:
+			focus = `1. **期现结构**：基差与升贴水含义，跨期价差反映的近远月供需
+2. **持仓分析**：持仓量变化反映的多空资金博弈
+3. **供需逻辑**：产业链上下游、库存与季节性因素
+4. **宏观与政策**：利率、汇率、政策对该品种的影响
+5. **策略建议**：方向判断、套保或套利思路、风险控制`
+		}
+	case AssetTypeForex:
+		switch analysisType {
+		case "technical":
+			focus = `1. **趋势结构**：均线与通道，所处波段位置
+2. **关键价位**：整数关口、前高前低
+3. **技术指标**：MACD、RSI、KDJ 状态
+4. **策略建议**：入场、止损、仓位`
+		default:
+			focus = `1. **利差驱动**：两国政策利率与预期变化对汇率的影响
+2. **央行政策**：货币政策分化、干预风险
+3. **宏观基本面**：通胀、增长、贸易与资本流动
+4. **关联汇率**：美元指数与交叉汇率的联动
+5. **策略建议**：方向判断、关键价位、风险事件`
+		}
+	case AssetTypeCrypto:
+		switch analysisType {
+		case "technical":
+			focus = `1. **趋势结构**：日线与4小时级别趋势、均线排列
+2. **关键价位**：前高前低、整数关口与成交密集区
+3. **量价关系**：24h成交额变化与放量突破/缩量回调
+4. **技术指标**：MACD、RSI、KDJ 状态，是否超买超卖
+5. **策略建议**：入场、止损、仓位，注意高波动与杠杆风险`
+		default:
+			focus = `1. **市场周期**：所处牛熊阶段、与比特币的联动
+2. **资金与流动性**：美元流动性、成交额与交易所资金动向
+3. **项目与生态**：链上活跃度、生态发展与代币经济
+4. **政策与监管**：主要司法辖区的监管动态
+5. **策略建议**：方向判断、仓位控制与极端行情应对`
+		}
+	default:
+		switch analysisType {
+		case "technical":
+			focus = `1. **趋势分析**：上升/下降/震荡，配合均线结构
+2. **支撑压力**：关键价位、突破/失守条件
+3. **量价关系**：成交量配合情况
+4. **技术指标**：MACD、KDJ、RSI 状态
+5. **策略建议**：入场、止盈止损、仓位`
+		default:
+			focus = `1. **公司与行业**：主营业务、行业地位
+2. **估值水平**：结合市盈率、市值与同业比较
+3. **市场环境**：所在市场的利率、汇率与资金面
+4. **消息面影响**：近期资讯与事件
+5. **操作建议**：明确观点与仓位策略`
+		}
+	}
+
+	return fmt.Sprintf(`
+请从以下方面进行分析，并严格使用 Markdown 排版：
+%s
+
+输出格式要求：
+- 顶部使用“### 综合结论”，随后按上述要点使用“###”标题展开
+- 关键数字加粗，结论使用列表展示
+- 末尾必须包含“### 风险提示”和“### 操作建议”两个段落
+
+重要声明：以上分析由AI生成，仅供学习研究参考，不构成任何投资建议。
+`, focus)
+}
+
+// AssetCacheKey 跨资产分析缓存的代码键，加资产类型前缀避免与A股代码冲突
+func AssetCacheKey(assetType, code string) string {
+	return strings.ToLower(assetType + ":" + NormalizeAssetCode(assetType, code))
+}
+
+// assetContextTimeout 构建上下文的兜底超时
+const assetContextTimeout = 15 * time.Second
+
+// BuildWithTimeout 带超时地构建分析上下文，到期后取消全部未完成的请求
+func (b *AssetContextBuilder) BuildWithTimeout(ctx context.Context, assetType, code string) (*AssetAnalysisContext, error) {
+	ctx, cancel := context.WithTimeout(ctx, assetContextTimeout)
+	defer cancel()
+	ac, err := b.Build(ctx, assetType, code)
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return nil, fmt.Errorf("获取%s数据超时", AssetTypeLabel(assetType))
+	}
+	return ac, err
+}
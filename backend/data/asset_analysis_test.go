@@ -0,0 +1,142 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"stock-ai/backend/cache"
+	"stock-ai/backend/models"
+)
+
+func TestParsePolicyRates(t *testing.T) {
+	rates, err := ParsePolicyRates("# 2026年9月\nusd=4.25\nCNY 1.30%\n\n")
+	if err != nil || len(rates) != 2 || rates["USD"] != 4.25 || rates["CNY"] != 1.30 {
+		t.Fatalf("rates = %v, %v", rates, err)
+	}
+	for _, text := range []string{"", "# 仅注释", "USD", "USD=abc", "DOLLAR=4"} {
+		if _, err := ParsePolicyRates(text); err == nil {
+			t.Errorf("ParsePolicyRates(%q) 应报错", text)
+		}
+	}
+}
+
+func TestPolicyRateTableOutdated(t *testing.T) {
+	now := time.Date(2026, 10, 18, 0, 0, 0, 0, time.Local)
+	cases := map[string]bool{
+		"2026-09": false,
+		"2026-06": false,
+		"2026-05": true,
+		"2025-10": true,
+		"":        true,
+		"2026/09": true,
+	}
+	for asOf, want := range cases {
+		if got := (PolicyRateTable{AsOf: asOf}).Outdated(now); got != want {
+			t.Errorf("Outdated(%q) = %v, want %v", asOf, got, want)
+		}
+	}
+
+	cfg := &models.Config{PolicyRates: "USD=4.25\nJPY=0.75", PolicyRatesAsOf: "2026-09"}
+	if table := CurrentPolicyRates(cfg); table.AsOf != "2026-09" || table.Rates["JPY"] != 0.75 {
+		t.Fatalf("table = %+v", table)
+	}
+	if table := CurrentPolicyRates(&models.Config{}); table.AsOf != PolicyRateAsOf {
+		t.Fatalf("未配置时应使用内置参考值: %+v", table)
+	}
+}
+
+func TestBuildForexSectionOutdatedRates(t *testing.T) {
+	section := buildForexSection(&AssetAnalysisContext{BaseCurrency: "USD", QuoteCurrency: "CNY", RateAsOf: "2025-10", RateOutdated: true})
+	if !strings.Contains(section, "不提供利差数据") || strings.Contains(section, "利差（基础-计价）") {
+		t.Fatalf("section = %s", section)
+	}
+}
+
+// 按金十数据中心利率决议接口的格式构造，最新一条尚未公布（今值为 null）
+const rateDecisionSample = `{"status":200,"message":"","data":{"keys":[{"name":"日期","type":"date"},{"name":"今值","type":"float"},{"name":"预测值","type":"float"},{"name":"前值","type":"float"}],
+"values":[["%s",null,4.0,%s],["%s",%s,4.25,4.5],["2024-12-19",4.5,4.5,4.75]]}}`
+
+func TestParseRateDecision(t *testing.T) {
+	rate, date, err := parseRateDecision([]byte(fmt.Sprintf(rateDecisionSample, "2026-10-29", "4.25", "2026-09-17", "4.25")))
+	if err != nil || rate != 4.25 || date.Format("2006-01-02") != "2026-09-17" {
+		t.Fatalf("rate = %v, date = %v, err = %v", rate, date, err)
+	}
+	for _, body := range []string{`{}`, `{"data":{"keys":[{"name":"日期"}],"values":[["2026-09-17",4.25]]}}`, `not json`} {
+		if _, _, err := parseRateDecision([]byte(body)); err == nil {
+			t.Errorf("parseRateDecision(%s) 应报错", body)
+		}
+	}
+}
+
+// rateDecisionTransport 按 attr_id 返回利率决议样例
+type rateDecisionTransport map[string]string
+
+func (t rateDecisionTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, ok := t[req.URL.Query().Get("attr_id")]
+	status := http.StatusOK
+	if !ok {
+		status, body = http.StatusNotFound, ""
+	}
+	return &http.Response{StatusCode: status, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(body)), Request: req}, nil
+}
+
+func TestPolicyRatesDefaultPath(t *testing.T) {
+	cache.SetDiskDir(t.TempDir())
+	defer cache.SetDiskDir("")
+
+	now := time.Now()
+	recent := now.AddDate(0, 0, -30).Format("2006-01-02")
+	rm := NewRequestManager()
+	rm.SetTransport(rateDecisionTransport{
+		"24": fmt.Sprintf(rateDecisionSample, now.AddDate(0, 0, 20).Format("2006-01-02"), "4.25", recent, "4.25"),
+		"91": fmt.Sprintf(rateDecisionSample, now.AddDate(0, 0, 20).Format("2006-01-02"), "3.0", recent, "3.0"),
+		// 停更的数据源不采用
+		"21": fmt.Sprintf(rateDecisionSample, "2020-01-01", "0", "2019-09-12", "0"),
+	})
+	b := &AssetContextBuilder{rm: rm}
+	for _, currency := range []string{"USD", "CNY", "EUR"} {
+		policyRateCache.Delete(currency)
+	}
+
+	table := b.policyRates(context.Background(), "USD", "CNY")
+	if table.Source != PolicyRateSourceLive || table.Outdated(now) || table.Rates["USD"] != 4.25 || table.Rates["CNY"] != 3.0 {
+		t.Fatalf("默认应使用实时利率决议: %+v", table)
+	}
+
+	for _, pair := range [][]string{{"EUR", "USD"}, {"USD", "HKD"}} {
+		if table := b.policyRates(context.Background(), pair...); table.Source != PolicyRateSourceBuiltin {
+			t.Errorf("%v 无法实时获取时应整体退回内置参考值: %+v", pair, table)
+		}
+	}
+
+	rm.UpdateConfig(&models.Config{PolicyRates: "USD=4.00\nCNY=1.40", PolicyRatesAsOf: "2026-09"})
+	if table := b.policyRates(context.Background(), "USD", "CNY"); table.Source != PolicyRateSourceManual || table.Rates["CNY"] != 1.40 {
+		t.Fatalf("手动填写的利率应优先: %+v", table)
+	}
+}
+
+func TestAssetNews(t *testing.T) {
+	items := []models.NewsItem{
+		{Title: "美股三大指数收涨，纳指创新高"},
+		{Title: "Morgan Stanley raises target on MSFT"},
+		{Title: "苹果发布新款iPhone", Content: "AAPL 盘后上涨"},
+	}
+	if news, market := assetNews(items, "微软", "MSFT"); market || len(news) != 1 || news[0].Title != items[1].Title {
+		t.Fatalf("MSFT news = %+v, market = %v", news, market)
+	}
+	// 代码按整词匹配，MS 不应命中 MSFT
+	if news, market := assetNews(items, "摩根士丹利", "MS"); !market || len(news) != len(items) {
+		t.Fatalf("MS news = %+v, market = %v", news, market)
+	}
+	if news, market := assetNews(items, "苹果", "AAPL"); market || len(news) != 1 {
+		t.Fatalf("AAPL news = %+v, market = %v", news, market)
+	}
+	if _, market := assetNews(nil, "苹果"); market {
+		t.Fatal("没有新闻时不应标记为大盘资讯")
+	}
+}
@@ -0,0 +1,93 @@
+package data
+
+import (
+	"time"
+
+	"stock-ai/backend/cache"
+	"stock-ai/backend/models"
+)
+
+// ==================== 缓存命名空间 ====================
+//
+// 所有数据接口的缓存统一在此声明，分组与清理入口对应：
+//   quote     行情类（CleanupQuoteCache）
+//   news      新闻类（CleanupNewsCache）
+//   report    研报（CleanupReportCache）
+//   notice    公告（CleanupNoticeCache）
+//   financial 财务数据（CleanupFinancialCache），持久化到磁盘
+//   snapshot  需跨重启保留的快照：上次市场数据（PersistentCache）与自适应限流状态
+
+// 缓存分组
+const (
+	CacheGroupQuote     = "quote"
+	CacheGroupNews      = "news"
+	CacheGroupReport    = "report"
+	CacheGroupNotice    = "notice"
+	CacheGroupFinancial = "financial"
+	CacheGroupSnapshot  = "snapshot"
+)
+
+// globalIndexCacheKey 全球指数列表只有一份，使用固定键
+const globalIndexCacheKey = "all"
+
+// cachedTickers 加密货币行情及其来源交易所
+type cachedTickers struct {
+	List   []models.CryptoPrice `json:"list"`
+	Source string               `json:"source"`
+}
+
+var (
+	cryptoTickerCache = cache.New[cachedTickers](cache.Options{
+		Namespace: "quote.crypto_tickers", TTL: 15 * time.Second,
+	})
+	cryptoKLineCache = cache.New[[]models.KLineData](cache.Options{
+		Namespace: "quote.crypto_kline", TTL: time.Minute, MaxEntries: 200,
+	})
+	forexRateCache = cache.New[[]models.ForexRate](cache.Options{
+		Namespace: "quote.forex_rates", TTL: 60 * time.Second,
+	})
+	futuresContractCache = cache.New[[]models.FuturesPrice](cache.Options{
+		Namespace: "quote.futures_main", TTL: 30 * time.Second,
+	})
+	globalIndexCache = cache.New[*models.GlobalIndexResult](cache.Options{
+		Namespace: "quote.global_indices", TTL: 60 * time.Second,
+	})
+	sentimentCache = cache.New[*MarketSentiment](cache.Options{
+		Namespace: "quote.sentiment", TTL: 60 * time.Second, MaxEntries: 50,
+	})
+	assetKLineCache = cache.New[[]models.KLineData](cache.Options{
+		Namespace: "quote.asset_kline", TTL: 5 * time.Minute, MaxEntries: 200,
+	})
+
+	policyRateCache = cache.New[policyRateDecision](cache.Options{
+		Namespace: "quote.policy_rates", TTL: 12 * time.Hour, StaleTTL: 7 * 24 * time.Hour,
+	})
+
+	globalNewsCache = cache.New[*models.NewsListResult](cache.Options{
+		Namespace: "news.global", TTL: 5 * time.Minute, MaxEntries: 50,
+	})
+
+	financialSummaryCache = cache.New[*FinancialData](cache.Options{
+		Namespace: "financial.summary", TTL: time.Hour, StaleTTL: 24 * time.Hour, MaxEntries: 1000, Persist: true,
+	})
+	financialTableCache = cache.New[[]map[string]interface{}](cache.Options{
+		Namespace: "financial.table", TTL: time.Hour, MaxEntries: 500,
+	})
+	financialStatementCache = cache.New[[]models.FinancialStatement](cache.Options{
+		Namespace: "financial.statements", TTL: time.Hour, MaxEntries: 500, Persist: true,
+	})
+	financialValuationCache = cache.New[map[string]interface{}](cache.Options{
+		Namespace: "financial.valuation", TTL: 30 * time.Minute, MaxEntries: 500,
+	})
+	companyTypeCache = cache.New[string](cache.Options{
+		Namespace: "financial.company_type", TTL: 24 * time.Hour, MaxEntries: 2000, Persist: true,
+	})
+	industryValuationCache = cache.New[*industryValuation](cache.Options{
+		Namespace: "financial.industry_valuation", TTL: industryValuationTTL, StaleTTL: 6 * time.Hour, MaxEntries: 200,
+	})
+
+	// 超过一天未更新的状态自然过期，各域名恢复默认速率
+	rateLimitStateCache = cache.New[map[string]*adaptiveState](cache.Options{
+		Namespace: "snapshot.rate_limits", TTL: 24 * time.Hour, Persist: true,
+	})
+)
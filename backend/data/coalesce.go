@@ -0,0 +1,126 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"stock-ai/backend/cache"
+	"stock-ai/backend/models"
+)
+
+// ==================== 并发请求合并 ====================
+//
+// 打开个股页面时，预加载、分析预热与前端轮询常常同时请求同一份K线/研报/公告，
+// 每次请求都会占用按域名限流的额度。coalesce 以 (接口, 参数) 为键合并进行中的相同请求，
+// 只有第一个调用方真正发起请求，其余调用方等待并共享结果。
+
+type coalesceCounter struct {
+	calls, executed, saved atomic.Int64
+}
+
+var (
+	requestFlight   cache.FlightGroup[any]
+	coalesceMu      sync.RWMutex
+	coalesceMetrics = make(map[string]*coalesceCounter)
+)
+
+// coalesce 合并 endpoint 与 params 相同的并发请求
+// 合并后的请求由多个调用方共享（保留首个调用方的优先级等上下文值），截止时间取各调用方中最晚的一个，
+// 所有调用方都取消或到期后才取消请求；单个调用方的 ctx 结束时直接返回，不再等待结果。
+// 返回的切片与 map 每个调用方各有一份副本，可自行增删；其中的指针元素仍与其他调用方共享，只能读取
+func coalesce[T any](ctx context.Context, endpoint string, params []interface{}, fn func(ctx context.Context) (T, error)) (T, error) {
+	counter := coalesceCounterFor(endpoint)
+	counter.calls.Add(1)
+
+	value, err, shared := requestFlight.DoContext(ctx, coalesceKey(endpoint, params), func(ctx context.Context) (any, error) {
+		counter.executed.Add(1)
+		return fn(ctx)
+	})
+	if shared && ctx.Err() == nil {
+		counter.saved.Add(1)
+	}
+	result, _ := value.(T)
+	return cloneShared(result), err
+}
+
+// cloneShared 浅复制切片与 map 容器，其余类型原样返回
+func cloneShared[T any](v T) T {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice:
+		if rv.IsNil() {
+			return v
+		}
+		copied := reflect.MakeSlice(rv.Type(), rv.Len(), rv.Len())
+		reflect.Copy(copied, rv)
+		return copied.Interface().(T)
+	case reflect.Map:
+		if rv.IsNil() {
+			return v
+		}
+		copied := reflect.MakeMapWithSize(rv.Type(), rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			copied.SetMapIndex(iter.Key(), iter.Value())
+		}
+		return copied.Interface().(T)
+	}
+	return v
+}
+
+// coalesceKey 由接口名与参数构造合并键
+func coalesceKey(endpoint string, params []interface{}) string {
+	var sb strings.Builder
+	sb.WriteString(endpoint)
+	for _, p := range params {
+		sb.WriteByte('|')
+		sb.WriteString(fmt.Sprint(p))
+	}
+	return sb.String()
+}
+
+// codesParam 代码列表与顺序无关，排序后作为合并参数
+func codesParam(codes []string) string {
+	sorted := append([]string(nil), codes...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, ",")
+}
+
+func coalesceCounterFor(endpoint string) *coalesceCounter {
+	coalesceMu.RLock()
+	counter, ok := coalesceMetrics[endpoint]
+	coalesceMu.RUnlock()
+	if ok {
+		return counter
+	}
+
+	coalesceMu.Lock()
+	defer coalesceMu.Unlock()
+	if counter, ok = coalesceMetrics[endpoint]; !ok {
+		counter = &coalesceCounter{}
+		coalesceMetrics[endpoint] = counter
+	}
+	return counter
+}
+
+// GetCoalesceStats 获取各接口的请求合并统计，按接口名排序
+func GetCoalesceStats() []models.CoalesceStat {
+	coalesceMu.RLock()
+	stats := make([]models.CoalesceStat, 0, len(coalesceMetrics))
+	for endpoint, counter := range coalesceMetrics {
+		stats = append(stats, models.CoalesceStat{
+			Endpoint: endpoint,
+			Calls:    counter.calls.Load(),
+			Executed: counter.executed.Load(),
+			Saved:    counter.saved.Load(),
+		})
+	}
+	coalesceMu.RUnlock()
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Endpoint < stats[j].Endpoint })
+	return stats
+}
@@ -0,0 +1,119 @@
+package data
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCoalesceSharesInFlightCall(t *testing.T) {
+	const endpoint = "test.coalesce"
+	var executed atomic.Int32
+	release := make(chan struct{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, err := coalesce(context.Background(), endpoint, []interface{}{codesParam([]string{"sz000001", "sh600519"})}, func(context.Context) (int, error) {
+				executed.Add(1)
+				<-release
+				return 7, nil
+			})
+			if err != nil || got != 7 {
+				t.Errorf("coalesce = %d, %v", got, err)
+			}
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if executed.Load() != 1 {
+		t.Fatalf("实际请求 %d 次, want 1", executed.Load())
+	}
+	for _, s := range GetCoalesceStats() {
+		if s.Endpoint == endpoint {
+			if s.Calls != 5 || s.Executed != 1 || s.Saved != 4 {
+				t.Fatalf("stats = %+v", s)
+			}
+			return
+		}
+	}
+	t.Fatal("未找到合并统计")
+}
+
+func TestCodesParamIgnoresOrder(t *testing.T) {
+	a := codesParam([]string{"sh600519", "sz000001"})
+	b := codesParam([]string{"sz000001", "sh600519"})
+	if a != b {
+		t.Fatalf("%q != %q", a, b)
+	}
+}
+
+func TestCoalesceCallerCancelDoesNotFailOthers(t *testing.T) {
+	const endpoint = "test.coalesce.cancel"
+	release := make(chan struct{})
+	fn := func(ctx context.Context) (int, error) {
+		<-release
+		return 1, ctx.Err()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := coalesce(ctx, endpoint, nil, fn)
+		first <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	second := make(chan error, 1)
+	go func() {
+		_, err := coalesce(context.Background(), endpoint, nil, fn)
+		second <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	cancel()
+	if err := <-first; err != context.Canceled {
+		t.Fatalf("取消的调用方 err = %v", err)
+	}
+	close(release)
+	if err := <-second; err != nil {
+		t.Fatalf("其余调用方 err = %v", err)
+	}
+}
+
+func TestCoalesceCallersGetOwnContainers(t *testing.T) {
+	const endpoint = "test.coalesce.clone"
+	release := make(chan struct{})
+	fn := func(context.Context) (map[string]int, error) {
+		<-release
+		return map[string]int{"sh600519": 1}, nil
+	}
+
+	results := make(chan map[string]int, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			got, _ := coalesce(context.Background(), endpoint, nil, fn)
+			results <- got
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	a, b := <-results, <-results
+	delete(a, "sh600519")
+	if b["sh600519"] != 1 {
+		t.Fatal("一个调用方修改结果不应影响其他调用方")
+	}
+
+	var nilSlice []int
+	if cloneShared(nilSlice) != nil {
+		t.Fatal("nil 切片应原样返回")
+	}
+	if got := cloneShared([]int{1, 2}); len(got) != 2 || got[1] != 2 {
+		t.Fatalf("cloneShared = %v", got)
+	}
+}
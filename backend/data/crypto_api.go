@@ -0,0 +1,522 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"stock-ai/backend/models"
+)
+
+// ==================== 加密货币行情 ====================
+
+// 主流加密货币（均以USDT计价）
+var mainCryptoCoins = []models.CryptoPrice{
+	{Symbol: "BTC", Name: "比特币"},
+	{Symbol: "ETH", Name: "以太坊"},
+	{Symbol: "BNB", Name: "币安币"},
+	{Symbol: "SOL", Name: "Solana"},
+	{Symbol: "XRP", Name: "瑞波币"},
+	{Symbol: "DOGE", Name: "狗狗币"},
+	{Symbol: "ADA", Name: "艾达币"},
+	{Symbol: "TRX", Name: "波场"},
+	{Symbol: "TON", Name: "Toncoin"},
+	{Symbol: "AVAX", Name: "雪崩"},
+	{Symbol: "LINK", Name: "Chainlink"},
+	{Symbol: "DOT", Name: "波卡"},
+	{Symbol: "LTC", Name: "莱特币"},
+	{Symbol: "BCH", Name: "比特币现金"},
+}
+
+// 加密货币数据源列表（公开REST接口，无需API Key）
+var cryptoSources = []string{"binance", "okx", "gateio"}
+
+// 加密货币数据源对应的限流域名
+var cryptoSourceDomains = map[string]string{
+	"binance": "api.binance.com",
+	"okx":     "www.okx.com",
+	"gateio":  "api.gateio.ws",
+}
+
+// 稳定币不参与涨幅/成交额排行
+var cryptoStablecoins = map[string]bool{
+	"USDC": true, "FDUSD": true, "TUSD": true, "DAI": true, "USDP": true, "BUSD": true, "USDE": true,
+}
+
+// GetMainCryptoCoins 获取主流加密货币列表
+func (api *CryptoForexAPI) GetMainCryptoCoins() []models.CryptoPrice {
+	return mainCryptoCoins
+}
+
+// CryptoName 获取币种中文名称，未知币种返回代码本身
+func CryptoName(symbol string) string {
+	for _, c := range mainCryptoCoins {
+		if c.Symbol == symbol {
+			return c.Name
+		}
+	}
+	return symbol
+}
+
+// NormalizeCryptoSymbol 规范化币种代码：BTCUSDT / BTC-USDT / btc_usdt / BTC/USDT → BTC
+func NormalizeCryptoSymbol(symbol string) string {
+	s := strings.ToUpper(strings.TrimSpace(symbol))
+	for _, sep := range []string{"-", "_", "/"} {
+		s = strings.ReplaceAll(s, sep, "")
+	}
+	if len(s) > 4 && strings.HasSuffix(s, "USDT") {
+		s = strings.TrimSuffix(s, "USDT")
+	}
+	return s
+}
+
+// GetCryptoQuotes 获取指定币种行情，symbols为空时返回主流币种
+func (api *CryptoForexAPI) GetCryptoQuotes(ctx context.Context, symbols []string) ([]models.CryptoPrice, error) {
+	if len(symbols) == 0 {
+		for _, c := range mainCryptoCoins {
+			symbols = append(symbols, c.Symbol)
+		}
+	}
+
+	tickers, source, err := api.getCryptoTickers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	index := make(map[string]models.CryptoPrice, len(tickers))
+	for _, t := range tickers {
+		index[t.Symbol] = t
+	}
+
+	result := make([]models.CryptoPrice, 0, len(symbols))
+	for _, s := range symbols {
+		symbol := NormalizeCryptoSymbol(s)
+		if symbol == "" {
+			continue
+		}
+		if t, ok := index[symbol]; ok {
+			result = append(result, t)
+		} else {
+			// 当前交易所未上架该币种，保留占位便于前端展示
+			result = append(result, models.CryptoPrice{Symbol: symbol, Name: CryptoName(symbol), Source: source})
+		}
+	}
+	return result, nil
+}
+
+// GetCryptoQuote 获取单个币种行情
+func (api *CryptoForexAPI) GetCryptoQuote(ctx context.Context, symbol string) (*models.CryptoPrice, error) {
+	quotes, err := api.GetCryptoQuotes(ctx, []string{symbol})
+	if err != nil {
+		return nil, err
+	}
+	if len(quotes) == 0 || quotes[0].Price <= 0 {
+		return nil, fmt.Errorf("未找到币种: %s", symbol)
+	}
+	return &quotes[0], nil
+}
+
+// GetTopCryptoQuotes 获取24h成交额排名前N的币种（剔除稳定币）
+func (api *CryptoForexAPI) GetTopCryptoQuotes(ctx context.Context, n int) ([]models.CryptoPrice, error) {
+	if n <= 0 || n > 100 {
+		n = 20
+	}
+
+	tickers, _, err := api.getCryptoTickers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return topCryptoByQuoteVolume(tickers, n), nil
+}
+
+// topCryptoByQuoteVolume 按24h成交额排序取前N
+func topCryptoByQuoteVolume(tickers []models.CryptoPrice, n int) []models.CryptoPrice {
+	list := make([]models.CryptoPrice, 0, len(tickers))
+	for _, t := range tickers {
+		if t.Price <= 0 || cryptoStablecoins[t.Symbol] {
+			continue
+		}
+		list = append(list, t)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].QuoteVolume24h > list[j].QuoteVolume24h
+	})
+	if len(list) > n {
+		list = list[:n]
+	}
+	return list
+}
+
+// getCryptoTickers 获取全部USDT交易对行情（循环轮询多个交易所）
+func (api *CryptoForexAPI) getCryptoTickers(ctx context.Context) ([]models.CryptoPrice, string, error) {
+	cacheKey := "all"
+	if cached, ok := cryptoTickerCache.Get(cacheKey); ok {
+		return cached.List, cached.Source, nil
+	}
+
+	api.cryptoMu.Lock()
+	currentIndex := api.cryptoIndex
+	api.cryptoIndex = (api.cryptoIndex + 1) % len(cryptoSources)
+	api.cryptoMu.Unlock()
+
+	var lastErr error
+	for i := 0; i < len(cryptoSources); i++ {
+		source := cryptoSources[(currentIndex+i)%len(cryptoSources)]
+
+		var result []models.CryptoPrice
+		var err error
+
+		switch source {
+		case "binance":
+			result, err = api.fetchCryptoTickers(ctx, source, "https://api.binance.com/api/v3/ticker/24hr", parseBinanceTickers)
+		case "okx":
+			result, err = api.fetchCryptoTickers(ctx, source, "https://www.okx.com/api/v5/market/tickers?instType=SPOT", parseOKXTickers)
+		case "gateio":
+			result, err = api.fetchCryptoTickers(ctx, source, "https://api.gateio.ws/api/v4/spot/tickers", parseGateTickers)
+		}
+
+		if err == nil && len(result) > 0 {
+			cryptoTickerCache.Set(cacheKey, cachedTickers{List: result, Source: source})
+			return result, source, nil
+		}
+		if err == nil {
+			err = fmt.Errorf("%s 返回空数据", source)
+		}
+		lastErr = err
+	}
+
+	return nil, "", fmt.Errorf("所有加密货币数据源均失败: %v", lastErr)
+}
+
+// fetchCryptoTickers 请求交易所行情接口并解析
+func (api *CryptoForexAPI) fetchCryptoTickers(ctx context.Context, source, url string, parse func([]byte) ([]models.CryptoPrice, error)) ([]models.CryptoPrice, error) {
+	body, err := getWithRateLimit(ctx, api.rm, url, "", cryptoSourceDomains[source])
+	if err != nil {
+		return nil, err
+	}
+	list, err := parse(body)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().Format("15:04:05")
+	for i := range list {
+		list[i].Name = CryptoName(list[i].Symbol)
+		list[i].Source = source
+		if list[i].UpdateTime == "" {
+			list[i].UpdateTime = now
+		}
+	}
+	return list, nil
+}
+
+// parseBinanceTickers 解析Binance /api/v3/ticker/24hr 响应
+func parseBinanceTickers(body []byte) ([]models.CryptoPrice, error) {
+	var items []struct {
+		Symbol             string `json:"symbol"`
+		LastPrice          string `json:"lastPrice"`
+		PriceChange        string `json:"priceChange"`
+		PriceChangePercent string `json:"priceChangePercent"`
+		OpenPrice          string `json:"openPrice"`
+		HighPrice          string `json:"highPrice"`
+		LowPrice           string `json:"lowPrice"`
+		Volume             string `json:"volume"`
+		QuoteVolume        string `json:"quoteVolume"`
+		CloseTime          int64  `json:"closeTime"`
+	}
+	if err := json.Unmarshal(body, &items); err != nil {
+		return nil, fmt.Errorf("解析Binance行情失败: %v", err)
+	}
+
+	var result []models.CryptoPrice
+	for _, item := range items {
+		if !strings.HasSuffix(item.Symbol, "USDT") || len(item.Symbol) <= 4 {
+			continue
+		}
+		price := parseFloat(item.LastPrice)
+		if price <= 0 {
+			continue
+		}
+		result = append(result, models.CryptoPrice{
+			Symbol:         strings.TrimSuffix(item.Symbol, "USDT"),
+			Price:          price,
+			Change:         parseFloat(item.PriceChange),
+			ChangePercent:  parseFloat(item.PriceChangePercent),
+			Open24h:        parseFloat(item.OpenPrice),
+			High24h:        parseFloat(item.HighPrice),
+			Low24h:         parseFloat(item.LowPrice),
+			Volume24h:      parseFloat(item.Volume),
+			QuoteVolume24h: parseFloat(item.QuoteVolume),
+			UpdateTime:     formatCryptoMillis(item.CloseTime, "15:04:05"),
+		})
+	}
+	return result, nil
+}
+
+// parseOKXTickers 解析OKX /api/v5/market/tickers 响应
+func parseOKXTickers(body []byte) ([]models.CryptoPrice, error) {
+	var resp struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+		Data []struct {
+			InstID    string `json:"instId"`
+			Last      string `json:"last"`
+			Open24h   string `json:"open24h"`
+			High24h   string `json:"high24h"`
+			Low24h    string `json:"low24h"`
+			Vol24h    string `json:"vol24h"`
+			VolCcy24h string `json:"volCcy24h"` // 现货为计价货币成交额
+			Ts        string `json:"ts"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("解析OKX行情失败: %v", err)
+	}
+	if resp.Code != "0" {
+		return nil, fmt.Errorf("OKX返回错误: %s %s", resp.Code, resp.Msg)
+	}
+
+	var result []models.CryptoPrice
+	for _, item := range resp.Data {
+		if !strings.HasSuffix(item.InstID, "-USDT") {
+			continue
+		}
+		price := parseFloat(item.Last)
+		if price <= 0 {
+			continue
+		}
+		open := parseFloat(item.Open24h)
+		p := models.CryptoPrice{
+			Symbol:         strings.TrimSuffix(item.InstID, "-USDT"),
+			Price:          price,
+			Open24h:        open,
+			High24h:        parseFloat(item.High24h),
+			Low24h:         parseFloat(item.Low24h),
+			Volume24h:      parseFloat(item.Vol24h),
+			QuoteVolume24h: parseFloat(item.VolCcy24h),
+			UpdateTime:     formatCryptoMillis(parseInt64(item.Ts), "15:04:05"),
+		}
+		if open > 0 {
+			p.Change = price - open
+			p.ChangePercent = round(p.Change/open*100, 2)
+		}
+		result = append(result, p)
+	}
+	return result, nil
+}
+
+// parseGateTickers 解析Gate.io /api/v4/spot/tickers 响应
+func parseGateTickers(body []byte) ([]models.CryptoPrice, error) {
+	var items []struct {
+		CurrencyPair     string `json:"currency_pair"`
+		Last             string `json:"last"`
+		ChangePercentage string `json:"change_percentage"`
+		High24h          string `json:"high_24h"`
+		Low24h           string `json:"low_24h"`
+		BaseVolume       string `json:"base_volume"`
+		QuoteVolume      string `json:"quote_volume"`
+	}
+	if err := json.Unmarshal(body, &items); err != nil {
+		return nil, fmt.Errorf("解析Gate.io行情失败: %v", err)
+	}
+
+	var result []models.CryptoPrice
+	for _, item := range items {
+		if !strings.HasSuffix(item.CurrencyPair, "_USDT") {
+			continue
+		}
+		price := parseFloat(item.Last)
+		if price <= 0 {
+			continue
+		}
+		pct := parseFloat(item.ChangePercentage)
+		p := models.CryptoPrice{
+			Symbol:         strings.TrimSuffix(item.CurrencyPair, "_USDT"),
+			Price:          price,
+			ChangePercent:  pct,
+			High24h:        parseFloat(item.High24h),
+			Low24h:         parseFloat(item.Low24h),
+			Volume24h:      parseFloat(item.BaseVolume),
+			QuoteVolume24h: parseFloat(item.QuoteVolume),
+		}
+		// Gate.io不返回开盘价，按涨跌幅反推
+		if pct > -100 {
+			p.Open24h = price / (1 + pct/100)
+			p.Change = price - p.Open24h
+		}
+		result = append(result, p)
+	}
+	return result, nil
+}
+
+// ==================== 加密货币K线 ====================
+
+// 各交易所K线周期写法
+var cryptoIntervalMap = map[string]map[string]string{
+	"binance": {"15m": "15m", "1h": "1h", "4h": "4h", "1d": "1d", "1w": "1w"},
+	"okx":     {"15m": "15m", "1h": "1H", "4h": "4H", "1d": "1Dutc", "1w": "1Wutc"},
+	"gateio":  {"15m": "15m", "1h": "1h", "4h": "4h", "1d": "1d", "1w": "7d"},
+}
+
+// GetCryptoKLine 获取加密货币K线，interval支持 15m/1h/4h/1d/1w
+func (api *CryptoForexAPI) GetCryptoKLine(ctx context.Context, symbol, interval string, count int) ([]models.KLineData, error) {
+	symbol = NormalizeCryptoSymbol(symbol)
+	if symbol == "" {
+		return nil, fmt.Errorf("币种代码不能为空")
+	}
+	interval = strings.ToLower(strings.TrimSpace(interval))
+	if _, ok := cryptoIntervalMap["binance"][interval]; !ok {
+		interval = "1d"
+	}
+	if count <= 0 || count > 500 {
+		count = 120
+	}
+
+	cacheKey := fmt.Sprintf("%s_%s_%d", symbol, interval, count)
+	if cached, ok := cryptoKLineCache.Get(cacheKey); ok {
+		return cached, nil
+	}
+
+	api.cryptoMu.Lock()
+	currentIndex := api.cryptoIndex
+	api.cryptoIndex = (api.cryptoIndex + 1) % len(cryptoSources)
+	api.cryptoMu.Unlock()
+
+	var lastErr error
+	for i := 0; i < len(cryptoSources); i++ {
+		source := cryptoSources[(currentIndex+i)%len(cryptoSources)]
+		bar := cryptoIntervalMap[source][interval]
+
+		var url string
+		var parse func([]byte, string) ([]models.KLineData, error)
+		switch source {
+		case "binance":
+			url = fmt.Sprintf("https://api.binance.com/api/v3/klines?symbol=%sUSDT&interval=%s&limit=%d", symbol, bar, count)
+			parse = parseBinanceKLines
+		case "okx":
+			url = fmt.Sprintf("https://www.okx.com/api/v5/market/candles?instId=%s-USDT&bar=%s&limit=%d", symbol, bar, min(count, 300))
+			parse = parseOKXKLines
+		case "gateio":
+			url = fmt.Sprintf("https://api.gateio.ws/api/v4/spot/candlesticks?currency_pair=%s_USDT&interval=%s&limit=%d", symbol, bar, count)
+			parse = parseGateKLines
+		}
+
+		body, err := getWithRateLimit(ctx, api.rm, url, "", cryptoSourceDomains[source])
+		if err == nil {
+			var klines []models.KLineData
+			klines, err = parse(body, symbol)
+			if err == nil && len(klines) > 0 {
+				cryptoKLineCache.Set(cacheKey, klines)
+				return klines, nil
+			}
+			if err == nil {
+				err = fmt.Errorf("%s 返回空K线", source)
+			}
+		}
+		lastErr = err
+	}
+
+	return nil, fmt.Errorf("获取%s K线失败: %v", symbol, lastErr)
+}
+
+// parseBinanceKLines 解析Binance /api/v3/klines 响应（按时间升序）
+// 每行: [开盘时间, 开, 高, 低, 收, 成交量, 收盘时间, 成交额, ...]
+func parseBinanceKLines(body []byte, symbol string) ([]models.KLineData, error) {
+	var rows [][]interface{}
+	if err := json.Unmarshal(body, &rows); err != nil {
+		return nil, fmt.Errorf("解析Binance K线失败: %v", err)
+	}
+
+	result := make([]models.KLineData, 0, len(rows))
+	for _, row := range rows {
+		if len(row) < 6 {
+			continue
+		}
+		ts, _ := row[0].(float64)
+		result = append(result, models.KLineData{
+			Date:   formatCryptoMillis(int64(ts), "2006-01-02 15:04"),
+			Open:   parseFloat(fmt.Sprint(row[1])),
+			High:   parseFloat(fmt.Sprint(row[2])),
+			Low:    parseFloat(fmt.Sprint(row[3])),
+			Close:  parseFloat(fmt.Sprint(row[4])),
+			Volume: int64(math.Round(parseFloat(fmt.Sprint(row[5])))),
+			Code:   symbol,
+		})
+	}
+	return result, nil
+}
+
+// parseOKXKLines 解析OKX /api/v5/market/candles 响应（接口按时间倒序，需翻转）
+// 每行: [时间戳, 开, 高, 低, 收, 成交量, ...]
+func parseOKXKLines(body []byte, symbol string) ([]models.KLineData, error) {
+	var resp struct {
+		Code string     `json:"code"`
+		Msg  string     `json:"msg"`
+		Data [][]string `json:"data"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("解析OKX K线失败: %v", err)
+	}
+	if resp.Code != "0" {
+		return nil, fmt.Errorf("OKX返回错误: %s %s", resp.Code, resp.Msg)
+	}
+
+	result := make([]models.KLineData, 0, len(resp.Data))
+	for i := len(resp.Data) - 1; i >= 0; i-- {
+		row := resp.Data[i]
+		if len(row) < 6 {
+			continue
+		}
+		result = append(result, models.KLineData{
+			Date:   formatCryptoMillis(parseInt64(row[0]), "2006-01-02 15:04"),
+			Open:   parseFloat(row[1]),
+			High:   parseFloat(row[2]),
+			Low:    parseFloat(row[3]),
+			Close:  parseFloat(row[4]),
+			Volume: int64(math.Round(parseFloat(row[5]))),
+			Code:   symbol,
+		})
+	}
+	return result, nil
+}
+
+// parseGateKLines 解析Gate.io /api/v4/spot/candlesticks 响应（按时间升序）
+// 每行: [时间戳(秒), 成交额, 收, 高, 低, 开, 成交量, 是否完结]
+func parseGateKLines(body []byte, symbol string) ([]models.KLineData, error) {
+	var rows [][]string
+	if err := json.Unmarshal(body, &rows); err != nil {
+		return nil, fmt.Errorf("解析Gate.io K线失败: %v", err)
+	}
+
+	result := make([]models.KLineData, 0, len(rows))
+	for _, row := range rows {
+		if len(row) < 7 {
+			continue
+		}
+		result = append(result, models.KLineData{
+			Date:   formatCryptoMillis(parseInt64(row[0])*1000, "2006-01-02 15:04"),
+			Open:   parseFloat(row[5]),
+			High:   parseFloat(row[3]),
+			Low:    parseFloat(row[4]),
+			Close:  parseFloat(row[2]),
+			Volume: int64(math.Round(parseFloat(row[6]))),
+			Code:   symbol,
+		})
+	}
+	return result, nil
+}
+
+// formatCryptoMillis 毫秒时间戳格式化（北京时间）
+func formatCryptoMillis(ms int64, layout string) string {
+	if ms <= 0 {
+		return ""
+	}
+	return time.UnixMilli(ms).In(cryptoLocation).Format(layout)
+}
+
+// cryptoLocation 加密货币时间统一按北京时间展示
+var cryptoLocation = time.FixedZone("CST", 8*3600)
@@ -0,0 +1,202 @@
+package data
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"stock-ai/backend/models"
+)
+
+// 测试数据均为交易所公开接口的录制响应，位于 testdata/crypto，不访问真实网络
+
+func loadCryptoFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	body, err := os.ReadFile(filepath.Join("testdata", "crypto", name))
+	if err != nil {
+		t.Fatalf("读取测试数据 %s 失败: %v", name, err)
+	}
+	return body
+}
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-6
+}
+
+func findCrypto(list []models.CryptoPrice, symbol string) *models.CryptoPrice {
+	for i := range list {
+		if list[i].Symbol == symbol {
+			return &list[i]
+		}
+	}
+	return nil
+}
+
+func TestParseCryptoTickers(t *testing.T) {
+	tests := []struct {
+		name    string
+		fixture string
+		parse   func([]byte) ([]models.CryptoPrice, error)
+		count   int // 仅保留有效的USDT交易对
+		symbol  string
+		want    models.CryptoPrice
+	}{
+		{
+			name:    "binance",
+			fixture: "binance_ticker_24hr.json",
+			parse:   parseBinanceTickers,
+			count:   3,
+			symbol:  "BTC",
+			want: models.CryptoPrice{
+				Price: 68000, Change: 1250.5, ChangePercent: 1.873, Open24h: 66749.5,
+				High24h: 68420, Low24h: 66300.1, Volume24h: 25431.12345, QuoteVolume24h: 1709234567.89,
+			},
+		},
+		{
+			name:    "okx",
+			fixture: "okx_tickers.json",
+			parse:   parseOKXTickers,
+			count:   2,
+			symbol:  "ETH",
+			want: models.CryptoPrice{
+				Price: 2550, Change: -50, ChangePercent: -1.92, Open24h: 2600,
+				High24h: 2612, Low24h: 2531, Volume24h: 157000.4, QuoteVolume24h: 401234567.8,
+			},
+		},
+		{
+			name:    "gateio",
+			fixture: "gateio_tickers.json",
+			parse:   parseGateTickers,
+			count:   2,
+			symbol:  "BTC",
+			want: models.CryptoPrice{
+				Price: 68005.3, Change: 68005.3 - 68005.3/1.02, ChangePercent: 2, Open24h: 68005.3 / 1.02,
+				High24h: 68430, Low24h: 66310, Volume24h: 8123.45, QuoteVolume24h: 551234567.12,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := tt.parse(loadCryptoFixture(t, tt.fixture))
+			if err != nil {
+				t.Fatalf("解析失败: %v", err)
+			}
+			if len(list) != tt.count {
+				t.Fatalf("交易对数量 = %d, 期望 %d: %+v", len(list), tt.count, list)
+			}
+			got := findCrypto(list, tt.symbol)
+			if got == nil {
+				t.Fatalf("未找到 %s", tt.symbol)
+			}
+			checks := []struct {
+				field     string
+				got, want float64
+			}{
+				{"Price", got.Price, tt.want.Price},
+				{"Change", got.Change, tt.want.Change},
+				{"ChangePercent", got.ChangePercent, tt.want.ChangePercent},
+				{"Open24h", got.Open24h, tt.want.Open24h},
+				{"High24h", got.High24h, tt.want.High24h},
+				{"Low24h", got.Low24h, tt.want.Low24h},
+				{"Volume24h", got.Volume24h, tt.want.Volume24h},
+				{"QuoteVolume24h", got.QuoteVolume24h, tt.want.QuoteVolume24h},
+			}
+			for _, c := range checks {
+				if !almostEqual(c.got, c.want) {
+					t.Errorf("%s = %v, 期望 %v", c.field, c.got, c.want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseOKXTickersError(t *testing.T) {
+	if _, err := parseOKXTickers(loadCryptoFixture(t, "okx_error.json")); err == nil {
+		t.Fatal("OKX返回错误码时应返回错误，以便切换到下一个数据源")
+	}
+	if _, err := parseBinanceTickers([]byte(`{"code":-1003,"msg":"Too many requests"}`)); err == nil {
+		t.Fatal("Binance返回非数组时应返回错误")
+	}
+}
+
+func TestParseCryptoKLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		fixture string
+		parse   func([]byte, string) ([]models.KLineData, error)
+	}{
+		{"binance", "binance_klines.json", parseBinanceKLines},
+		{"okx", "okx_candles.json", parseOKXKLines},
+		{"gateio", "gateio_candlesticks.json", parseGateKLines},
+	}
+
+	// 三个交易所录制的是同一组日K线，解析后应一致且按时间升序
+	want := []models.KLineData{
+		{Date: "2025-10-16 08:00", Open: 66100, High: 67200.5, Low: 65800, Close: 66749.5, Volume: 21035, Code: "BTC"},
+		{Date: "2025-10-17 08:00", Open: 66749.5, High: 68420, Low: 66300.1, Close: 68000, Volume: 25432, Code: "BTC"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			klines, err := tt.parse(loadCryptoFixture(t, tt.fixture), "BTC")
+			if err != nil {
+				t.Fatalf("解析失败: %v", err)
+			}
+			if len(klines) != len(want) {
+				t.Fatalf("K线数量 = %d, 期望 %d", len(klines), len(want))
+			}
+			for i, k := range klines {
+				w := want[i]
+				if k.Date != w.Date || k.Code != w.Code {
+					t.Errorf("第%d根 Date/Code = %s/%s, 期望 %s/%s", i, k.Date, k.Code, w.Date, w.Code)
+				}
+				if !almostEqual(k.Open, w.Open) || !almostEqual(k.High, w.High) ||
+					!almostEqual(k.Low, w.Low) || !almostEqual(k.Close, w.Close) {
+					t.Errorf("第%d根 OHLC = %v/%v/%v/%v, 期望 %v/%v/%v/%v",
+						i, k.Open, k.High, k.Low, k.Close, w.Open, w.High, w.Low, w.Close)
+				}
+				if k.Volume != w.Volume {
+					t.Errorf("第%d根 Volume = %d, 期望 %d", i, k.Volume, w.Volume)
+				}
+			}
+		})
+	}
+}
+
+func TestTopCryptoByQuoteVolume(t *testing.T) {
+	list, err := parseBinanceTickers(loadCryptoFixture(t, "binance_ticker_24hr.json"))
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+
+	top := topCryptoByQuoteVolume(list, 5)
+	if len(top) != 2 {
+		t.Fatalf("排行数量 = %d, 期望 2（剔除稳定币）", len(top))
+	}
+	if top[0].Symbol != "BTC" || top[1].Symbol != "ETH" {
+		t.Errorf("排行顺序 = %s,%s, 期望 BTC,ETH", top[0].Symbol, top[1].Symbol)
+	}
+
+	if top = topCryptoByQuoteVolume(list, 1); len(top) != 1 {
+		t.Errorf("截断后数量 = %d, 期望 1", len(top))
+	}
+}
+
+func TestNormalizeCryptoSymbol(t *testing.T) {
+	tests := map[string]string{
+		"btc":       "BTC",
+		"BTCUSDT":   "BTC",
+		"eth-usdt":  "ETH",
+		"DOGE_USDT": "DOGE",
+		"sol/usdt":  "SOL",
+		" USDT ":    "USDT",
+		"":          "",
+	}
+	for in, want := range tests {
+		if got := NormalizeCryptoSymbol(in); got != want {
+			t.Errorf("NormalizeCryptoSymbol(%q) = %q, 期望 %q", in, got, want)
+		}
+	}
+}
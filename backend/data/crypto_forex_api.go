@@ -1,6 +1,7 @@
 package data
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -12,14 +13,17 @@ import (
 	"stock-ai/backend/models"
 )
 
-// ForexAPI 外汇数据API
+// CryptoForexAPI 加密货币与外汇数据API
 type CryptoForexAPI struct {
 	rm         *RequestManager
 	forexIndex int // 外汇数据源轮询索引
 	forexMu    sync.Mutex
+
+	cryptoIndex int // 加密货币数据源轮询索引
+	cryptoMu    sync.Mutex
 }
 
-// NewCryptoForexAPI 创建外汇API实例
+// NewCryptoForexAPI 创建加密货币与外汇API实例
 func NewCryptoForexAPI() *CryptoForexAPI {
 	return &CryptoForexAPI{
 		rm: GetRequestManager(),
@@ -51,11 +55,11 @@ func (api *CryptoForexAPI) GetMainForexPairs() []models.ForexRate {
 var forexSources = []string{"sina", "eastmoney", "tencent", "hexun", "netease", "baidu", "xueqiu"}
 
 // GetForexRates 获取外汇汇率（循环轮询多个数据源）
-func (api *CryptoForexAPI) GetForexRates() ([]models.ForexRate, error) {
+func (api *CryptoForexAPI) GetForexRates(ctx context.Context) ([]models.ForexRate, error) {
 	// 缓存检查
-	cacheKey := "forex_rates"
-	if cached, ok := api.rm.GetCache(cacheKey); ok {
-		return cached.([]models.ForexRate), nil
+	cacheKey := "main"
+	if cached, ok := forexRateCache.Get(cacheKey); ok {
+		return cached, nil
 	}
 
 	// 获取当前数据源索引
@@ -75,23 +79,24 @@ func (api *CryptoForexAPI) GetForexRates() ([]models.ForexRate, error) {
 
 		switch source {
 		case "sina":
-			result, err = api.getForexRatesFromSina()
+			result, err = api.getForexRatesFromSina(ctx)
 		case "eastmoney":
-			result, err = api.getForexRatesFromEastMoney()
+			result, err = api.getForexRatesFromEastMoney(ctx)
 		case "tencent":
-			result, err = api.getForexRatesFromTencent()
+			result, err = api.getForexRatesFromTencent(ctx)
 		case "hexun":
-			result, err = api.getForexRatesFromHexun()
+			result, err = api.getForexRatesFromHexun(ctx)
 		case "netease":
-			result, err = api.getForexRatesFromNetease()
+			result, err = api.getForexRatesFromNetease(ctx)
 		case "baidu":
-			result, err = api.getForexRatesFromBaidu()
+			result, err = api.getForexRatesFromBaidu(ctx)
 		case "xueqiu":
-			result, err = api.getForexRatesFromXueqiu()
+			result, err = api.getForexRatesFromXueqiu(ctx)
 		}
 
 		if err == nil && len(result) > 0 && result[0].Rate > 0 {
-			api.rm.SetCache(cacheKey, result, 60*time.Second)
+			forexRateCache.Set(cacheKey, result)
+			go RecordForexSnapshot(result)
 			return result, nil
 		}
 		lastErr = err
@@ -102,7 +107,7 @@ func (api *CryptoForexAPI) GetForexRates() ([]models.ForexRate, error) {
 }
 
 // getForexRatesFromSina 从新浪获取外汇汇率
-func (api *CryptoForexAPI) getForexRatesFromSina() ([]models.ForexRate, error) {
+func (api *CryptoForexAPI) getForexRatesFromSina(ctx context.Context) ([]models.ForexRate, error) {
 	// 新浪外汇代码映射
 	forexCodeMap := map[string]string{
 		"USDCNY": "fx_susdcny",
@@ -128,7 +133,7 @@ func (api *CryptoForexAPI) getForexRatesFromSina() ([]models.ForexRate, error) {
 
 	url := fmt.Sprintf("https://hq.sinajs.cn/list=%s", strings.Join(sinaCodeList, ","))
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -161,11 +166,11 @@ func (api *CryptoForexAPI) getForexRatesFromSina() ([]models.ForexRate, error) {
 }
 
 // getForexRatesFromEastMoney 从东方财富获取外汇汇率
-func (api *CryptoForexAPI) getForexRatesFromEastMoney() ([]models.ForexRate, error) {
+func (api *CryptoForexAPI) getForexRatesFromEastMoney(ctx context.Context) ([]models.ForexRate, error) {
 	// 东方财富外汇接口
 	url := "https://push2.eastmoney.com/api/qt/clist/get?pn=1&pz=50&po=1&np=1&fltt=2&invt=2&fid=f3&fs=m:119,m:120&fields=f1,f2,f3,f4,f12,f13,f14"
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -234,7 +239,7 @@ func (api *CryptoForexAPI) getForexRatesFromEastMoney() ([]models.ForexRate, err
 }
 
 // getForexRatesFromTencent 从腾讯财经获取外汇汇率
-func (api *CryptoForexAPI) getForexRatesFromTencent() ([]models.ForexRate, error) {
+func (api *CryptoForexAPI) getForexRatesFromTencent(ctx context.Context) ([]models.ForexRate, error) {
 	// 腾讯外汇代码映射
 	tencentCodeMap := map[string]string{
 		"USDCNY": "fx_susdcnh",
@@ -260,7 +265,7 @@ func (api *CryptoForexAPI) getForexRatesFromTencent() ([]models.ForexRate, error
 
 	url := fmt.Sprintf("https://qt.gtimg.cn/q=%s", strings.Join(codeList, ","))
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -330,11 +335,11 @@ func (api *CryptoForexAPI) getForexRatesFromTencent() ([]models.ForexRate, error
 }
 
 // getForexRatesFromHexun 从和讯获取外汇汇率
-func (api *CryptoForexAPI) getForexRatesFromHexun() ([]models.ForexRate, error) {
+func (api *CryptoForexAPI) getForexRatesFromHexun(ctx context.Context) ([]models.ForexRate, error) {
 	// 和讯外汇接口
 	url := "https://api.hexun.com/forex/quotelist?code=USDCNY,EURUSD,GBPUSD,USDJPY,AUDUSD,USDCAD,USDCHF,NZDUSD,EURCNY,GBPCNY,JPYCNY,HKDCNY"
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -388,11 +393,11 @@ func (api *CryptoForexAPI) getForexRatesFromHexun() ([]models.ForexRate, error)
 }
 
 // getForexRatesFromNetease 从网易获取外汇汇率
-func (api *CryptoForexAPI) getForexRatesFromNetease() ([]models.ForexRate, error) {
+func (api *CryptoForexAPI) getForexRatesFromNetease(ctx context.Context) ([]models.ForexRate, error) {
 	// 网易外汇接口
 	url := "https://api.money.126.net/data/feed/FX_SUSDCNY,FX_SEURUSD,FX_SGBPUSD,FX_SUSDJPY,FX_SAUDUSD,FX_SUSDCAD,FX_SUSDCHF,FX_SNZDUSD,FX_SEURCNY,FX_SGBPCNY,FX_SJPYCNY,FX_SHKDCNY?callback=cb"
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -474,11 +479,11 @@ func (api *CryptoForexAPI) getForexRatesFromNetease() ([]models.ForexRate, error
 }
 
 // getForexRatesFromBaidu 从百度获取外汇汇率
-func (api *CryptoForexAPI) getForexRatesFromBaidu() ([]models.ForexRate, error) {
+func (api *CryptoForexAPI) getForexRatesFromBaidu(ctx context.Context) ([]models.ForexRate, error) {
 	// 百度股市通外汇接口
 	url := "https://gushitong.baidu.com/opendata?resource_id=5352&query=外汇&code=USDCNY,EURUSD,GBPUSD,USDJPY,AUDUSD,USDCAD,USDCHF,NZDUSD&market=forex"
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -534,11 +539,11 @@ func (api *CryptoForexAPI) getForexRatesFromBaidu() ([]models.ForexRate, error)
 }
 
 // getForexRatesFromXueqiu 从雪球获取外汇汇率
-func (api *CryptoForexAPI) getForexRatesFromXueqiu() ([]models.ForexRate, error) {
+func (api *CryptoForexAPI) getForexRatesFromXueqiu(ctx context.Context) ([]models.ForexRate, error) {
 	// 雪球外汇接口
 	url := "https://stock.xueqiu.com/v5/stock/batch/quote.json?symbol=USDCNY,EURUSD,GBPUSD,USDJPY,AUDUSD,USDCAD,USDCHF,NZDUSD,EURCNY,GBPCNY,JPYCNY,HKDCNY"
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,52 @@
+package data
+
+import (
+	"fmt"
+	"time"
+
+	"stock-ai/backend/models"
+)
+
+// ==================== 数据来源与新鲜度 ====================
+
+const (
+	sourceLocalCache  = "local-cache" // 持久化缓存中上次成功获取的数据
+	sourcePlaceholder = "placeholder" // 内置示例数据
+)
+
+// newDataMeta 构建数据来源信息，fetchedAt 为零值表示从未成功获取
+func newDataMeta(source, freshness string, fetchedAt time.Time, err error) models.DataMeta {
+	meta := models.DataMeta{
+		Source:    source,
+		Freshness: freshness,
+	}
+	if !fetchedAt.IsZero() {
+		meta.FetchedAt = fetchedAt.Format("2006-01-02 15:04:05")
+	}
+	if err != nil {
+		meta.Error = err.Error()
+	}
+	return meta
+}
+
+// loadLastGoodData 读取持久化缓存中上次成功获取的市场数据
+func loadLastGoodData() (*CachedData, bool) {
+	cached, err := GetPersistentCache().LoadCache()
+	if err != nil || cached == nil {
+		return nil, false
+	}
+	return cached, true
+}
+
+// IsPlaceholder 判断是否为占位数据，占位数据不得用于构建AI提示词
+func IsPlaceholder(meta models.DataMeta) bool {
+	return meta.Freshness == models.FreshnessPlaceholder
+}
+
+// DataFreshnessNote 生成数据时效提示，供AI提示词使用；实时或缓存有效期内的数据返回空字符串
+func DataFreshnessNote(label string, meta models.DataMeta) string {
+	if meta.Freshness != models.FreshnessStale {
+		return ""
+	}
+	return fmt.Sprintf("> 注意：%s实时获取失败，以下为 %s 获取的数据，可能已过时\n", label, meta.FetchedAt)
+}
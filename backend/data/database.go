@@ -59,14 +59,38 @@ func InitDB() error {
 		&models.Futures{},
 		&models.USStock{},
 		&models.HKStock{},
+		&models.ForexHistory{},
 		// 股票提醒
 		&models.StockAlert{},
 		&models.FundAlert{},
+		// 加密货币
+		&models.CryptoCoin{},
+		&models.CryptoAlert{},
+		// 新闻采集
+		&models.NewsArticle{},
+		&models.NewsStockTag{},
+		&models.SecurityName{},
+		// AI资讯日报
+		&models.DailyDigest{},
+		&models.AISummaryCache{},
+		// 研报评级跟踪
+		&models.ReportRecord{},
+		// 财务报表历史
+		&models.FinancialStatement{},
+		// 估值分位
+		&models.ValuationDaily{},
+		// 量化选股
+		&models.StockFactorSnapshot{},
+		&models.SavedScreen{},
+		&models.ScreenRun{},
 	)
 	if err != nil {
 		return err
 	}
 
+	// 新闻全文索引
+	initNewsFTS(db)
+
 	// 初始化默认配置
 	var config models.Config
 	if db.First(&config).Error == gorm.ErrRecordNotFound {
@@ -0,0 +1,235 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"stock-ai/backend/models"
+)
+
+// ==================== 东方财富F10财务数据 ====================
+
+const eastMoneyF10DataURL = "https://datacenter.eastmoney.com/securities/api/data/v1/get?reportName=%s&columns=ALL&filter=(SECUCODE%%3D%%22%s%%22)&pageNumber=1&pageSize=%d&sortTypes=-1&sortColumns=REPORT_DATE&source=HSF10&client=PC"
+
+// f10StatementReports 三张报表在F10中的报表名前缀，后缀依次尝试：一般企业(G)、银行(B)、证券(S)、保险(I)
+var f10StatementReports = []struct {
+	table  string
+	prefix string
+}{
+	{"income", "RPT_F10_FINANCE_%sINCOME"},
+	{"balance", "RPT_F10_FINANCE_%sBALANCE"},
+	{"cashflow", "RPT_F10_FINANCE_%sCASHFLOW"},
+}
+
+var f10CompanyTypes = []string{"G", "B", "S", "I"}
+
+// EastMoneyF10Client 直接抓取东方财富F10财务页面接口，无需Python与Token
+type EastMoneyF10Client struct {
+	rm *RequestManager
+}
+
+var (
+	globalEastMoneyF10Client *EastMoneyF10Client
+	eastMoneyF10ClientOnce   sync.Once
+)
+
+// GetEastMoneyF10Client 获取全局东方财富F10客户端
+func GetEastMoneyF10Client() *EastMoneyF10Client {
+	eastMoneyF10ClientOnce.Do(func() {
+		globalEastMoneyF10Client = &EastMoneyF10Client{
+			rm: GetRequestManager(),
+		}
+	})
+	return globalEastMoneyF10Client
+}
+
+// Name 数据源名称
+func (c *EastMoneyF10Client) Name() string {
+	return providerEastMoneyF10
+}
+
+// IsAvailable 公开接口无需配置，始终可用
+func (c *EastMoneyF10Client) IsAvailable() bool {
+	return true
+}
+
+// toSecuCode 转换为F10证券代码格式，如 sh600519 -> 600519.SH
+func toSecuCode(code string) (string, error) {
+	switch {
+	case strings.HasPrefix(code, "sh"):
+		return trimMarketPrefix(code) + ".SH", nil
+	case strings.HasPrefix(code, "sz"):
+		return trimMarketPrefix(code) + ".SZ", nil
+	default:
+		return "", fmt.Errorf("暂不支持的证券代码: %s", code)
+	}
+}
+
+// fetchReport 获取F10数据中心报表，按报告期倒序
+func (c *EastMoneyF10Client) fetchReport(ctx context.Context, reportName, secuCode string, pageSize int) ([]map[string]interface{}, error) {
+	url := fmt.Sprintf(eastMoneyF10DataURL, reportName, secuCode, pageSize)
+	body, err := getWithRateLimit(ctx, c.rm, url, "https://emweb.securities.eastmoney.com/", "eastmoney.com")
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Success bool   `json:"success"`
+		Message string `json:"message"`
+		Result  *struct {
+			Data []map[string]interface{} `json:"data"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("解析F10数据失败: %v", err)
+	}
+	if resp.Result == nil {
+		return nil, nil
+	}
+	return resp.Result.Data, nil
+}
+
+// GetQuarterlyStatements 获取多期财务报表，三张表按报告期合并
+func (c *EastMoneyF10Client) GetQuarterlyStatements(ctx context.Context, stockCode string) ([]models.FinancialStatement, error) {
+	cacheKey := fmt.Sprintf("f10_statements_%s", stockCode)
+	if cached, ok := financialStatementCache.Get(cacheKey); ok {
+		return cached, nil
+	}
+	secuCode, err := toSecuCode(stockCode)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := make(map[string]*models.FinancialStatement)
+	for _, report := range f10StatementReports {
+		var rows []map[string]interface{}
+		for _, companyType := range f10CompanyTypes {
+			rows, err = c.fetchReport(ctx, fmt.Sprintf(report.prefix, companyType), secuCode, statementPeriods)
+			if err != nil {
+				return nil, err
+			}
+			if len(rows) > 0 {
+				break
+			}
+		}
+		for _, row := range rows {
+			date := emRowString(row, "REPORT_DATE")
+			if len(date) < 10 {
+				continue
+			}
+			date = date[:10]
+			stmt, ok := merged[date]
+			if !ok {
+				stmt = &models.FinancialStatement{StockCode: stockCode, ReportDate: date, Source: providerEastMoneyF10}
+				merged[date] = stmt
+			}
+			applyEMStatementRow(stmt, report.table, row)
+		}
+	}
+
+	statements := sortedStatements(merged)
+	if len(statements) == 0 {
+		return nil, fmt.Errorf("无财务报表数据")
+	}
+	financialStatementCache.Set(cacheKey, statements)
+	return statements, nil
+}
+
+// GetFinancialData 获取最新一期财务数据：主要指标 + 三张报表 + 实时估值
+func (c *EastMoneyF10Client) GetFinancialData(ctx context.Context, stockCode string) (*FinancialData, error) {
+	cacheKey := fmt.Sprintf("f10_financial_%s", stockCode)
+	if cached, ok := financialSummaryCache.Get(cacheKey); ok {
+		return cached, nil
+	}
+	secuCode, err := toSecuCode(stockCode)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := c.fetchReport(ctx, "RPT_F10_FINANCE_MAINFINADATA", secuCode, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("无主要财务指标数据")
+	}
+	row := rows[0]
+	data := &FinancialData{
+		Code:          stockCode,
+		Name:          emRowString(row, "SECURITY_NAME_ABBR"),
+		ReportDate:    emRowString(row, "REPORT_DATE"),
+		Revenue:       emRowFloat(row, "TOTALOPERATEREVE") / yuanPerYi,
+		NetProfit:     emRowFloat(row, "PARENTNETPROFIT") / yuanPerYi,
+		GrossMargin:   emRowFloat(row, "XSMLL"),
+		NetMargin:     emRowFloat(row, "XSJLL"),
+		ROE:           emRowFloat(row, "ROEJQ"),
+		ROA:           emRowFloat(row, "ZZCJLL"),
+		DebtRatio:     emRowFloat(row, "ZCFZL"),
+		CurrentRatio:  emRowFloat(row, "LD"),
+		QuickRatio:    emRowFloat(row, "SD"),
+		EPS:           emRowFloat(row, "EPSJB"),
+		BPS:           emRowFloat(row, "BPS"),
+		RevenueGrowth: emRowFloat(row, "TOTALOPERATEREVETZ"),
+		ProfitGrowth:  emRowFloat(row, "PARENTNETPROFITTZ"),
+	}
+	if len(data.ReportDate) >= 10 {
+		data.ReportDate = data.ReportDate[:10]
+	}
+
+	// 资产负债与现金流取同一报告期的报表
+	if statements, err := c.GetQuarterlyStatements(ctx, stockCode); err == nil {
+		for _, stmt := range statements {
+			if stmt.ReportDate != data.ReportDate {
+				continue
+			}
+			data.TotalAssets = stmt.TotalAssets / yuanPerYi
+			data.TotalLiab = stmt.TotalLiab / yuanPerYi
+			data.TotalEquity = stmt.TotalEquity / yuanPerYi
+			data.OperatingCF = stmt.OperatingCF / yuanPerYi
+			data.InvestingCF = stmt.InvestingCF / yuanPerYi
+			data.FinancingCF = stmt.FinancingCF / yuanPerYi
+		}
+	} else {
+		log.Printf("[EastMoneyF10] 获取财务报表失败: %v", err)
+	}
+
+	// 实时估值（PE-TTM、PB）
+	if pe, pb, err := c.getValuation(ctx, stockCode); err == nil {
+		data.PE, data.PB = pe, pb
+	} else {
+		log.Printf("[EastMoneyF10] 获取估值失败: %v", err)
+	}
+
+	financialSummaryCache.Set(cacheKey, data)
+	return data, nil
+}
+
+// getValuation 获取实时市盈率TTM与市净率
+func (c *EastMoneyF10Client) getValuation(ctx context.Context, stockCode string) (pe, pb float64, err error) {
+	secid, err := toEastMoneySecID(stockCode)
+	if err != nil {
+		return 0, 0, err
+	}
+	url := fmt.Sprintf("https://push2.eastmoney.com/api/qt/stock/get?secid=%s&ut=%s&fltt=2&invt=2&fields=f164,f167", secid, eastMoneyUT)
+	body, err := getWithRateLimit(ctx, c.rm, url, "https://quote.eastmoney.com/", "eastmoney.com")
+	if err != nil {
+		return 0, 0, err
+	}
+	var result struct {
+		Data *struct {
+			PE interface{} `json:"f164"`
+			PB interface{} `json:"f167"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return 0, 0, err
+	}
+	if result.Data == nil {
+		return 0, 0, fmt.Errorf("估值数据为空")
+	}
+	return eastMoneyNumber(result.Data.PE), eastMoneyNumber(result.Data.PB), nil
+}
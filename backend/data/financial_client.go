@@ -1,39 +1,58 @@
 package data
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"sort"
 	"strings"
 	"sync"
 	"time"
+
+	"stock-ai/backend/models"
 )
 
 // FinancialDataProvider 财务数据提供者接口
 type FinancialDataProvider interface {
-	GetFinancialData(stockCode string) (*FinancialData, error)
+	GetFinancialData(ctx context.Context, stockCode string) (*FinancialData, error)
 	IsAvailable() bool
 	Name() string
 }
 
+// FinancialStatementProvider 支持多期财务报表的数据提供者
+type FinancialStatementProvider interface {
+	GetQuarterlyStatements(ctx context.Context, stockCode string) ([]models.FinancialStatement, error)
+}
+
+// 内置财务数据源名称
+const (
+	providerTushare      = "Tushare"
+	providerAKShare      = "AKShare"
+	providerEastMoneyF10 = "EastMoneyF10"
+)
+
+// providerState 数据源及其失败熔断状态
+type providerState struct {
+	provider      FinancialDataProvider
+	rank          int // 注册顺序，决定默认优先级
+	failCount     int
+	disabledUntil time.Time
+}
+
 // UnifiedFinancialClient 统一财务数据客户端
-// 自动在多个数据源之间切换，优先使用可用的数据源
+// 按优先级依次尝试数据源链，首个数据源缺失的字段由后续数据源补齐
 type UnifiedFinancialClient struct {
 	tushare     *TushareClient
 	akshare     *AKShareClient
 	rateLimiter *RateLimiter
-	cache       *DataCache
 	mu          sync.RWMutex
 
-	// 数据源优先级配置
-	preferTushare bool
-	// 失败计数，用于自动切换
-	tushareFailCount int
-	akshareFailCount int
+	// 数据源链（按优先级排序）
+	providers []*providerState
 	// 最大连续失败次数，超过后暂时禁用该数据源
 	maxFailCount int
-	// 禁用恢复时间
-	tushareDisabledUntil time.Time
-	akshareDisabledUntil time.Time
+	// 禁用时长
+	disableDuration time.Duration
 }
 
 var (
@@ -50,17 +69,21 @@ func GetFinancialClient() *UnifiedFinancialClient {
 }
 
 // NewUnifiedFinancialClient 创建统一财务数据客户端
+// 默认顺序：Tushare（更稳定）→ AKShare → 东方财富F10（无需Token与Python）
 func NewUnifiedFinancialClient() *UnifiedFinancialClient {
-	return &UnifiedFinancialClient{
-		tushare:       GetTushareClient(),
-		akshare:       GetAKShareClient(),
-		rateLimiter:   GetRateLimiter(),
-		preferTushare: true, // 默认优先使用Tushare（更稳定）
-		maxFailCount:  3,    // 连续失败3次后暂时禁用
-		cache: &DataCache{
-			data: make(map[string]*CacheItem),
-		},
+	c := &UnifiedFinancialClient{
+		tushare:         GetTushareClient(),
+		akshare:         GetAKShareClient(),
+		rateLimiter:     GetRateLimiter(),
+		maxFailCount:    3, // 连续失败3次后暂时禁用
+		disableDuration: 5 * time.Minute,
+	}
+	c.providers = []*providerState{
+		{provider: c.tushare, rank: 0},
+		{provider: c.akshare, rank: 1},
+		{provider: GetEastMoneyF10Client(), rank: 2},
 	}
+	return c
 }
 
 // SetTushareToken 设置Tushare Token
@@ -68,212 +91,224 @@ func (c *UnifiedFinancialClient) SetTushareToken(token string) {
 	c.tushare.SetToken(token)
 }
 
-// SetPreferTushare 设置是否优先使用Tushare
-func (c *UnifiedFinancialClient) SetPreferTushare(prefer bool) {
+// RegisterProvider 在数据源链末尾追加数据源（同名数据源会被替换）
+func (c *UnifiedFinancialClient) RegisterProvider(provider FinancialDataProvider) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	c.preferTushare = prefer
-}
-
-// isTushareAvailable 检查Tushare是否可用
-func (c *UnifiedFinancialClient) isTushareAvailable() bool {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-
-	// 检查是否配置了Token
-	if !c.tushare.IsConfigured() {
-		return false
+	for _, s := range c.providers {
+		if s.provider.Name() == provider.Name() {
+			s.provider = provider
+			s.failCount = 0
+			s.disabledUntil = time.Time{}
+			return
+		}
 	}
+	c.providers = append(c.providers, &providerState{provider: provider, rank: len(c.providers)})
+}
 
-	// 检查是否被暂时禁用
-	if time.Now().Before(c.tushareDisabledUntil) {
-		return false
+// SetPreferredProvider 将指定数据源（tushare / akshare / eastmoney）排在链首，其余按注册顺序
+func (c *UnifiedFinancialClient) SetPreferredProvider(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	preferred := func(s *providerState) bool {
+		return name != "" && strings.HasPrefix(strings.ToLower(s.provider.Name()), strings.ToLower(name))
 	}
-
-	return true
+	sort.SliceStable(c.providers, func(i, j int) bool {
+		pi, pj := preferred(c.providers[i]), preferred(c.providers[j])
+		if pi != pj {
+			return pi
+		}
+		return c.providers[i].rank < c.providers[j].rank
+	})
 }
 
-// isAKShareAvailable 检查AKShare是否可用
-func (c *UnifiedFinancialClient) isAKShareAvailable() bool {
+// orderedProviders 返回当前数据源链的快照
+func (c *UnifiedFinancialClient) orderedProviders() []*providerState {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
-
-	// 检查是否被暂时禁用
-	if time.Now().Before(c.akshareDisabledUntil) {
-		return false
-	}
-
-	return true
+	return append([]*providerState(nil), c.providers...)
 }
 
-// recordTushareSuccess 记录Tushare成功
-func (c *UnifiedFinancialClient) recordTushareSuccess() {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	c.tushareFailCount = 0
+// isUsable 数据源已配置且未被熔断
+func (c *UnifiedFinancialClient) isUsable(s *providerState) bool {
+	c.mu.RLock()
+	disabled := time.Now().Before(s.disabledUntil)
+	c.mu.RUnlock()
+	return !disabled && s.provider.IsAvailable()
 }
 
-// recordTushareFailure 记录Tushare失败
-func (c *UnifiedFinancialClient) recordTushareFailure() {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	c.tushareFailCount++
-	if c.tushareFailCount >= c.maxFailCount {
-		// 暂时禁用5分钟
-		c.tushareDisabledUntil = time.Now().Add(5 * time.Minute)
-		c.tushareFailCount = 0
-		log.Printf("[Financial] Tushare连续失败%d次，暂时禁用5分钟", c.maxFailCount)
+// isAvailable 按名称检查数据源是否可用
+func (c *UnifiedFinancialClient) isAvailable(name string) bool {
+	for _, s := range c.orderedProviders() {
+		if s.provider.Name() == name {
+			return c.isUsable(s)
+		}
 	}
+	return false
 }
 
-// recordAKShareSuccess 记录AKShare成功
-func (c *UnifiedFinancialClient) recordAKShareSuccess() {
+// recordSuccess 记录数据源成功
+func (c *UnifiedFinancialClient) recordSuccess(s *providerState) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	c.akshareFailCount = 0
+	s.failCount = 0
 }
 
-// recordAKShareFailure 记录AKShare失败
-func (c *UnifiedFinancialClient) recordAKShareFailure() {
+// recordFailure 记录数据源失败，连续失败达到上限后暂时禁用
+func (c *UnifiedFinancialClient) recordFailure(s *providerState) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	c.akshareFailCount++
-	if c.akshareFailCount >= c.maxFailCount {
-		// 暂时禁用5分钟
-		c.akshareDisabledUntil = time.Now().Add(5 * time.Minute)
-		c.akshareFailCount = 0
-		log.Printf("[Financial] AKShare连续失败%d次，暂时禁用5分钟", c.maxFailCount)
+	s.failCount++
+	if s.failCount >= c.maxFailCount {
+		s.disabledUntil = time.Now().Add(c.disableDuration)
+		s.failCount = 0
+		log.Printf("[Financial] %s连续失败%d次，暂时禁用%s", s.provider.Name(), c.maxFailCount, c.disableDuration)
 	}
 }
 
-// GetFinancialData 获取财务数据（自动选择数据源）
-func (c *UnifiedFinancialClient) GetFinancialData(stockCode string) (*FinancialData, error) {
-	// 检查缓存
+// GetFinancialData 获取财务数据：按数据源链依次获取，后续数据源只补齐缺失字段
+func (c *UnifiedFinancialClient) GetFinancialData(ctx context.Context, stockCode string) (*FinancialData, error) {
+	return coalesce(ctx, "financial.summary", []interface{}{stockCode}, func(ctx context.Context) (*FinancialData, error) {
+		return c.fetchFinancialData(ctx, stockCode)
+	})
+}
+
+// fetchFinancialData 实际请求，并发的相同调用由 GetFinancialData 合并
+func (c *UnifiedFinancialClient) fetchFinancialData(ctx context.Context, stockCode string) (*FinancialData, error) {
+	// 缓存1小时，过期后先返回旧值并在后台刷新
 	cacheKey := fmt.Sprintf("unified_financial_%s", stockCode)
-	if cached, ok := c.getCache(cacheKey); ok {
-		log.Printf("[Financial] 使用缓存的财务数据: %s", stockCode)
-		return cached.(*FinancialData), nil
-	}
-
-	var data *FinancialData
-	var err error
-	var source string
-
-	// 根据优先级尝试获取数据
-	if c.preferTushare {
-		// 优先Tushare
-		if c.isTushareAvailable() {
-			data, err = c.tushare.GetFinancialData(stockCode)
-			if err == nil {
-				c.recordTushareSuccess()
-				source = "Tushare"
-			} else {
-				log.Printf("[Financial] Tushare获取失败: %v，尝试AKShare", err)
-				c.recordTushareFailure()
-			}
-		}
+	return financialSummaryCache.GetOrLoad(ctx, cacheKey, func(ctx context.Context) (*FinancialData, error) {
+		return c.fetchMergedFinancialData(ctx, stockCode)
+	})
+}
 
-		// Tushare失败或不可用，尝试AKShare
-		if data == nil && c.isAKShareAvailable() {
-			data, err = c.akshare.GetFinancialData(stockCode)
-			if err == nil {
-				c.recordAKShareSuccess()
-				source = "AKShare"
-			} else {
-				log.Printf("[Financial] AKShare获取失败: %v", err)
-				c.recordAKShareFailure()
-			}
+// fetchMergedFinancialData 依次请求数据源链并合并结果
+func (c *UnifiedFinancialClient) fetchMergedFinancialData(ctx context.Context, stockCode string) (*FinancialData, error) {
+	var merged *FinancialData
+	var sources []string
+	for _, s := range c.orderedProviders() {
+		if merged != nil && !hasFinancialGaps(merged) {
+			break
 		}
-	} else {
-		// 优先AKShare
-		if c.isAKShareAvailable() {
-			data, err = c.akshare.GetFinancialData(stockCode)
-			if err == nil {
-				c.recordAKShareSuccess()
-				source = "AKShare"
-			} else {
-				log.Printf("[Financial] AKShare获取失败: %v，尝试Tushare", err)
-				c.recordAKShareFailure()
-			}
+		if !c.isUsable(s) {
+			continue
 		}
-
-		// AKShare失败或不可用，尝试Tushare
-		if data == nil && c.isTushareAvailable() {
-			data, err = c.tushare.GetFinancialData(stockCode)
-			if err == nil {
-				c.recordTushareSuccess()
-				source = "Tushare"
-			} else {
-				log.Printf("[Financial] Tushare获取失败: %v", err)
-				c.recordTushareFailure()
-			}
+		data, err := s.provider.GetFinancialData(ctx, stockCode)
+		if err != nil {
+			log.Printf("[Financial] %s获取失败: %v", s.provider.Name(), err)
+			c.recordFailure(s)
+			continue
+		}
+		c.recordSuccess(s)
+		if merged == nil {
+			copied := *data
+			merged = &copied
+			sources = append(sources, s.provider.Name())
+		} else if mergeFinancialData(merged, data) > 0 {
+			sources = append(sources, s.provider.Name())
 		}
 	}
 
-	if data == nil {
+	if merged == nil {
 		return nil, fmt.Errorf("所有数据源均不可用")
 	}
 
-	log.Printf("[Financial] 成功从 %s 获取财务数据: %s", source, stockCode)
-
-	// 缓存数据（1小时）
-	c.setCache(cacheKey, data, time.Hour)
-
-	return data, nil
+	log.Printf("[Financial] 成功从 %s 获取财务数据: %s", strings.Join(sources, "+"), stockCode)
+	return merged, nil
 }
 
-// getCache 获取缓存
-func (c *UnifiedFinancialClient) getCache(key string) (interface{}, bool) {
-	c.cache.mu.RLock()
-	defer c.cache.mu.RUnlock()
-
-	item, ok := c.cache.data[key]
-	if !ok {
-		return nil, false
+// periodFields 报告期相关字段，仅在报告期一致时合并
+func periodFields(d *FinancialData) []*float64 {
+	return []*float64{
+		&d.Revenue, &d.NetProfit, &d.GrossMargin, &d.NetMargin, &d.ROE, &d.ROA,
+		&d.DebtRatio, &d.CurrentRatio, &d.QuickRatio, &d.EPS, &d.BPS,
+		&d.TotalAssets, &d.TotalLiab, &d.TotalEquity,
+		&d.OperatingCF, &d.InvestingCF, &d.FinancingCF,
+		&d.RevenueGrowth, &d.ProfitGrowth,
 	}
+}
 
-	if time.Now().After(item.ExpireAt) {
-		return nil, false
+// coreFinancialFields 缺失时需要向后续数据源补齐的核心字段
+func coreFinancialFields(d *FinancialData) []*float64 {
+	return []*float64{&d.ROE, &d.GrossMargin, &d.DebtRatio, &d.TotalAssets, &d.OperatingCF, &d.PE, &d.PB}
+}
+
+// hasFinancialGaps 核心字段是否仍有缺失
+func hasFinancialGaps(d *FinancialData) bool {
+	for _, p := range coreFinancialFields(d) {
+		if *p == 0 {
+			return true
+		}
 	}
+	return false
+}
 
-	return item.Data, true
+// normalizeReportDate 统一报告期格式为 20060102
+func normalizeReportDate(date string) string {
+	return strings.ReplaceAll(strings.TrimSpace(date), "-", "")
 }
 
-// setCache 设置缓存
-func (c *UnifiedFinancialClient) setCache(key string, data interface{}, ttl time.Duration) {
-	c.cache.mu.Lock()
-	defer c.cache.mu.Unlock()
+// mergeFinancialData 用 src 补齐 dst 中为0的字段，返回补齐的字段数
+// 估值指标（PE/PB）始终可补；其余字段要求两者报告期一致（任一方未知报告期时视为一致）
+func mergeFinancialData(dst, src *FinancialData) int {
+	filled := 0
+	if dst.PE == 0 && src.PE != 0 {
+		dst.PE = src.PE
+		filled++
+	}
+	if dst.PB == 0 && src.PB != 0 {
+		dst.PB = src.PB
+		filled++
+	}
 
-	c.cache.data[key] = &CacheItem{
-		Data:     data,
-		ExpireAt: time.Now().Add(ttl),
+	dstDate, srcDate := normalizeReportDate(dst.ReportDate), normalizeReportDate(src.ReportDate)
+	if dstDate != "" && srcDate != "" && dstDate != srcDate {
+		return filled
+	}
+	dstFields, srcFields := periodFields(dst), periodFields(src)
+	for i, p := range dstFields {
+		if *p == 0 && *srcFields[i] != 0 {
+			*p = *srcFields[i]
+			filled++
+		}
+	}
+	if filled > 0 && dst.ReportDate == "" {
+		dst.ReportDate = src.ReportDate
+	}
+	if dst.Name == "" {
+		dst.Name = src.Name
 	}
+	return filled
 }
 
-// GetDataSourceStatus 获取数据源状态
+// GetDataSourceStatus 获取数据源状态（按数据源链顺序标注优先级）
 func (c *UnifiedFinancialClient) GetDataSourceStatus() map[string]interface{} {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-
 	now := time.Now()
-
-	return map[string]interface{}{
-		"tushare": map[string]interface{}{
-			"configured":    c.tushare.IsConfigured(),
-			"available":     c.isTushareAvailable(),
-			"failCount":     c.tushareFailCount,
-			"disabledUntil": c.tushareDisabledUntil.Format(time.RFC3339),
-			"isDisabled":    now.Before(c.tushareDisabledUntil),
-		},
-		"akshare": map[string]interface{}{
-			"running":       c.akshare.IsRunning(),
-			"available":     c.isAKShareAvailable(),
-			"failCount":     c.akshareFailCount,
-			"disabledUntil": c.akshareDisabledUntil.Format(time.RFC3339),
-			"isDisabled":    now.Before(c.akshareDisabledUntil),
-		},
-		"preferTushare": c.preferTushare,
+	status := make(map[string]interface{})
+	for i, s := range c.orderedProviders() {
+		c.mu.RLock()
+		failCount, disabledUntil := s.failCount, s.disabledUntil
+		c.mu.RUnlock()
+
+		item := map[string]interface{}{
+			"name":          s.provider.Name(),
+			"priority":      i + 1,
+			"configured":    s.provider.IsAvailable(),
+			"available":     c.isUsable(s),
+			"failCount":     failCount,
+			"disabledUntil": "",
+			"isDisabled":    now.Before(disabledUntil),
+		}
+		if !disabledUntil.IsZero() {
+			item["disabledUntil"] = disabledUntil.Format(time.RFC3339)
+		}
+		if s.provider.Name() == providerAKShare {
+			item["running"] = c.akshare.IsRunning()
+			item["pythonFallback"] = c.akshare.PythonFallbackEnabled()
+		}
+		status[strings.ToLower(s.provider.Name())] = item
 	}
+	return status
 }
 
 // FormatFinancialDataForAI 格式化财务数据供AI分析使用
@@ -375,8 +410,8 @@ func FormatFinancialDataForAI(data *FinancialData) string {
 }
 
 // StartAKShareServer 启动AKShare服务
-func (c *UnifiedFinancialClient) StartAKShareServer() error {
-	return c.akshare.StartServer()
+func (c *UnifiedFinancialClient) StartAKShareServer(ctx context.Context) error {
+	return c.akshare.StartServer(ctx)
 }
 
 // StopAKShareServer 停止AKShare服务
@@ -0,0 +1,316 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"stock-ai/backend/models"
+
+	"gorm.io/gorm/clause"
+)
+
+// ==================== 多期财务报表 ====================
+
+const (
+	statementPeriods      = 44             // 拉取的报告期数（约11年，覆盖10年估值分位）
+	statementYears        = 11             // Tushare 按起始日期拉取的年数
+	financialStatementTTL = 24 * time.Hour // 本地报表的刷新间隔
+)
+
+// GetQuarterlyStatements 获取多期财务报表，三张表按报告期合并
+func (c *AKShareClient) GetQuarterlyStatements(ctx context.Context, stockCode string) ([]models.FinancialStatement, error) {
+	cacheKey := fmt.Sprintf("akshare_statements_%s", stockCode)
+	if cached, ok := financialStatementCache.Get(cacheKey); ok {
+		return cached, nil
+	}
+
+	merged := make(map[string]*models.FinancialStatement)
+	for _, table := range []string{"income", "balance", "cashflow"} {
+		rows, err := c.statementRows(ctx, stockCode, table, statementPeriods)
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			date := emRowString(row, "REPORT_DATE")
+			if len(date) < 10 {
+				continue
+			}
+			date = date[:10]
+			stmt, ok := merged[date]
+			if !ok {
+				stmt = &models.FinancialStatement{StockCode: stockCode, ReportDate: date, Source: "AKShare"}
+				merged[date] = stmt
+			}
+			applyEMStatementRow(stmt, table, row)
+		}
+	}
+
+	statements := sortedStatements(merged)
+	if len(statements) == 0 {
+		return nil, fmt.Errorf("无财务报表数据")
+	}
+	financialStatementCache.Set(cacheKey, statements)
+	return statements, nil
+}
+
+// applyEMStatementRow 将东方财富口径的报表行写入财务报表（AKShare 与东方财富F10 字段名一致）
+// table 为 income / balance / cashflow
+func applyEMStatementRow(stmt *models.FinancialStatement, table string, row map[string]interface{}) {
+	switch table {
+	case "income":
+		stmt.Revenue = emRowFloat(row, "TOTAL_OPERATE_INCOME", "OPERATE_INCOME")
+		stmt.OperatingCost = emRowFloat(row, "OPERATE_COST")
+		stmt.OperatingProfit = emRowFloat(row, "OPERATE_PROFIT")
+		stmt.NetProfit = emRowFloat(row, "PARENT_NETPROFIT", "NETPROFIT")
+		stmt.DeductedNetProfit = emRowFloat(row, "DEDUCT_PARENT_NETPROFIT")
+	case "balance":
+		stmt.TotalAssets = emRowFloat(row, "TOTAL_ASSETS")
+		stmt.TotalLiab = emRowFloat(row, "TOTAL_LIABILITIES")
+		stmt.TotalEquity = emRowFloat(row, "TOTAL_PARENT_EQUITY", "TOTAL_EQUITY")
+		stmt.CurrentAssets = emRowFloat(row, "TOTAL_CURRENT_ASSETS")
+		stmt.CurrentLiab = emRowFloat(row, "TOTAL_CURRENT_LIAB")
+		stmt.Cash = emRowFloat(row, "MONETARYFUNDS")
+		stmt.Receivables = emRowFloat(row, "ACCOUNTS_RECE")
+		stmt.Inventory = emRowFloat(row, "INVENTORY")
+	case "cashflow":
+		stmt.OperatingCF = emRowFloat(row, "NETCASH_OPERATE")
+		stmt.InvestingCF = emRowFloat(row, "NETCASH_INVEST")
+		stmt.FinancingCF = emRowFloat(row, "NETCASH_FINANCE")
+		stmt.Capex = emRowFloat(row, "CONSTRUCT_LONG_ASSET")
+	}
+}
+
+// emRowString 读取报表行中的字符串字段
+func emRowString(row map[string]interface{}, key string) string {
+	switch v := row[key].(type) {
+	case string:
+		return v
+	case nil:
+		return ""
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// emRowFloat 读取报表行中的数值字段，依次尝试多个字段名
+func emRowFloat(row map[string]interface{}, keys ...string) float64 {
+	for _, key := range keys {
+		switch v := row[key].(type) {
+		case float64:
+			return v
+		case string:
+			if f, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+				return f
+			}
+		}
+	}
+	return 0
+}
+
+// GetQuarterlyStatements 获取多期财务报表（合并报表），三张表按报告期合并
+func (c *TushareClient) GetQuarterlyStatements(ctx context.Context, stockCode string) ([]models.FinancialStatement, error) {
+	tsCode := convertTsCode(stockCode)
+	cacheKey := fmt.Sprintf("statements_%s", tsCode)
+	if cached, ok := financialStatementCache.Get(cacheKey); ok {
+		return cached, nil
+	}
+
+	params := map[string]interface{}{
+		"ts_code":    tsCode,
+		"start_date": time.Now().AddDate(-statementYears, 0, 0).Format("20060102"),
+	}
+	tables := []struct {
+		api    string
+		fields string
+	}{
+		{"income", "ts_code,end_date,total_revenue,oper_cost,operate_profit,n_income_attr_p"},
+		{"balancesheet", "ts_code,end_date,total_assets,total_liab,total_hldr_eqy_exc_min_int,total_cur_assets,total_cur_liab,money_cap,accounts_receiv,inventories"},
+		{"cashflow", "ts_code,end_date,n_cashflow_act,n_cashflow_inv_act,n_cash_flows_fnc_act,c_pay_acq_const_fiolta"},
+	}
+
+	merged := make(map[string]*models.FinancialStatement)
+	for _, table := range tables {
+		resp, err := c.request(ctx, table.api, params, table.fields)
+		if err != nil {
+			return nil, err
+		}
+		fieldMap := makeFieldMap(resp.Data.Fields)
+		seen := make(map[string]bool)
+		for _, item := range resp.Data.Items {
+			endDate := getStringValue(item, fieldMap, "end_date")
+			if len(endDate) != 8 || seen[endDate] {
+				// 同一报告期可能有更正记录，只取第一条
+				continue
+			}
+			seen[endDate] = true
+			date := endDate[:4] + "-" + endDate[4:6] + "-" + endDate[6:]
+			stmt, ok := merged[date]
+			if !ok {
+				stmt = &models.FinancialStatement{StockCode: stockCode, ReportDate: date, Source: "Tushare"}
+				merged[date] = stmt
+			}
+			switch table.api {
+			case "income":
+				stmt.Revenue = getFloatValue(item, fieldMap, "total_revenue")
+				stmt.OperatingCost = getFloatValue(item, fieldMap, "oper_cost")
+				stmt.OperatingProfit = getFloatValue(item, fieldMap, "operate_profit")
+				stmt.NetProfit = getFloatValue(item, fieldMap, "n_income_attr_p")
+			case "balancesheet":
+				stmt.TotalAssets = getFloatValue(item, fieldMap, "total_assets")
+				stmt.TotalLiab = getFloatValue(item, fieldMap, "total_liab")
+				stmt.TotalEquity = getFloatValue(item, fieldMap, "total_hldr_eqy_exc_min_int")
+				stmt.CurrentAssets = getFloatValue(item, fieldMap, "total_cur_assets")
+				stmt.CurrentLiab = getFloatValue(item, fieldMap, "total_cur_liab")
+				stmt.Cash = getFloatValue(item, fieldMap, "money_cap")
+				stmt.Receivables = getFloatValue(item, fieldMap, "accounts_receiv")
+				stmt.Inventory = getFloatValue(item, fieldMap, "inventories")
+			case "cashflow":
+				stmt.OperatingCF = getFloatValue(item, fieldMap, "n_cashflow_act")
+				stmt.InvestingCF = getFloatValue(item, fieldMap, "n_cashflow_inv_act")
+				stmt.FinancingCF = getFloatValue(item, fieldMap, "n_cash_flows_fnc_act")
+				stmt.Capex = getFloatValue(item, fieldMap, "c_pay_acq_const_fiolta")
+			}
+		}
+	}
+
+	statements := sortedStatements(merged)
+	if len(statements) == 0 {
+		return nil, fmt.Errorf("无财务报表数据")
+	}
+	financialStatementCache.Set(cacheKey, statements)
+	return statements, nil
+}
+
+// sortedStatements 按报告期升序输出
+func sortedStatements(merged map[string]*models.FinancialStatement) []models.FinancialStatement {
+	statements := make([]models.FinancialStatement, 0, len(merged))
+	for _, stmt := range merged {
+		statements = append(statements, *stmt)
+	}
+	sort.Slice(statements, func(i, j int) bool {
+		return statements[i].ReportDate < statements[j].ReportDate
+	})
+	return statements
+}
+
+// GetFinancialStatements 获取多期财务报表：按数据源链依次获取，后续数据源只补齐同一报告期缺失的字段
+func (c *UnifiedFinancialClient) GetFinancialStatements(ctx context.Context, stockCode string) ([]models.FinancialStatement, error) {
+	return coalesce(ctx, "financial.statements", []interface{}{stockCode}, func(ctx context.Context) ([]models.FinancialStatement, error) {
+		return c.fetchFinancialStatements(ctx, stockCode)
+	})
+}
+
+// fetchFinancialStatements 实际请求，并发的相同调用由 GetFinancialStatements 合并
+func (c *UnifiedFinancialClient) fetchFinancialStatements(ctx context.Context, stockCode string) ([]models.FinancialStatement, error) {
+	var merged []models.FinancialStatement
+	var sources []string
+	for _, s := range c.orderedProviders() {
+		if merged != nil && !hasStatementGaps(merged) {
+			break
+		}
+		provider, ok := s.provider.(FinancialStatementProvider)
+		if !ok || !c.isUsable(s) {
+			continue
+		}
+		statements, err := provider.GetQuarterlyStatements(ctx, stockCode)
+		if err != nil {
+			log.Printf("[Financial] %s 获取财务报表失败: %v", s.provider.Name(), err)
+			c.recordFailure(s)
+			continue
+		}
+		c.recordSuccess(s)
+		if merged == nil {
+			merged = append([]models.FinancialStatement(nil), statements...)
+			sources = append(sources, s.provider.Name())
+		} else if mergeStatements(merged, statements) > 0 {
+			sources = append(sources, s.provider.Name())
+		}
+	}
+
+	if merged == nil {
+		return nil, fmt.Errorf("所有数据源均不可用")
+	}
+	log.Printf("[Financial] 成功从 %s 获取 %d 期财务报表: %s", strings.Join(sources, "+"), len(merged), stockCode)
+	return merged, nil
+}
+
+// statementFields 报表中可合并的数值字段
+func statementFields(s *models.FinancialStatement) []*float64 {
+	return []*float64{
+		&s.Revenue, &s.OperatingCost, &s.OperatingProfit, &s.NetProfit, &s.DeductedNetProfit,
+		&s.TotalAssets, &s.TotalLiab, &s.TotalEquity, &s.CurrentAssets, &s.CurrentLiab,
+		&s.Cash, &s.Receivables, &s.Inventory,
+		&s.OperatingCF, &s.InvestingCF, &s.FinancingCF, &s.Capex,
+	}
+}
+
+// hasStatementGaps 是否有报告期缺少营收、净利润、总资产、净资产或经营现金流
+func hasStatementGaps(statements []models.FinancialStatement) bool {
+	for _, s := range statements {
+		if s.Revenue == 0 || s.NetProfit == 0 || s.TotalAssets == 0 || s.TotalEquity == 0 || s.OperatingCF == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeStatements 用 src 中同一报告期的数据补齐 dst 为0的字段，返回补齐的字段数
+func mergeStatements(dst, src []models.FinancialStatement) int {
+	byDate := make(map[string]*models.FinancialStatement, len(src))
+	for i := range src {
+		byDate[src[i].ReportDate] = &src[i]
+	}
+	filled := 0
+	for i := range dst {
+		other, ok := byDate[dst[i].ReportDate]
+		if !ok {
+			continue
+		}
+		dstFields, srcFields := statementFields(&dst[i]), statementFields(other)
+		for j, p := range dstFields {
+			if *p == 0 && *srcFields[j] != 0 {
+				*p = *srcFields[j]
+				filled++
+			}
+		}
+	}
+	return filled
+}
+
+// SaveFinancialStatements 保存财务报表，同一股票同一报告期覆盖更新
+func SaveFinancialStatements(statements []models.FinancialStatement) error {
+	if len(statements) == 0 {
+		return nil
+	}
+	return GetDB().Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "stock_code"}, {Name: "report_date"}},
+		UpdateAll: true,
+	}).Create(&statements).Error
+}
+
+// LoadFinancialStatements 读取本地保存的财务报表，按报告期升序
+func LoadFinancialStatements(code string) ([]models.FinancialStatement, error) {
+	var statements []models.FinancialStatement
+	err := GetDB().Where("stock_code = ?", code).Order("report_date ASC").Find(&statements).Error
+	return statements, err
+}
+
+// SyncFinancialStatements 本地报表超过24小时未更新时重新拉取
+func SyncFinancialStatements(ctx context.Context, code string) error {
+	var latest models.FinancialStatement
+	err := GetDB().Where("stock_code = ?", code).Order("updated_at DESC").First(&latest).Error
+	if err == nil && time.Since(latest.UpdatedAt) < financialStatementTTL {
+		return nil
+	}
+
+	statements, err := GetFinancialClient().GetFinancialStatements(ctx, code)
+	if err != nil {
+		return err
+	}
+	return SaveFinancialStatements(statements)
+}
@@ -0,0 +1,248 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"stock-ai/backend/models"
+)
+
+// ==================== 财务趋势分析 ====================
+
+const (
+	trendQuarterRows = 8 // 提示词中展示的季度数
+	trendAnnualRows  = 5 // 提示词中展示的年度数
+	yuanPerYi        = 1e8
+)
+
+// GetFinancialTrend 同步并计算股票的多期财务趋势
+func GetFinancialTrend(ctx context.Context, code string) (*models.FinancialTrend, error) {
+	if err := SyncFinancialStatements(ctx, code); err != nil {
+		log.Printf("[Financial] %s 同步财务报表失败: %v", code, err)
+	}
+	statements, err := LoadFinancialStatements(code)
+	if err != nil {
+		return nil, err
+	}
+	if len(statements) == 0 {
+		return nil, fmt.Errorf("暂无财务报表数据")
+	}
+	return ComputeFinancialTrend(code, statements), nil
+}
+
+// ComputeFinancialTrend 根据累计口径的定期报告计算单季、TTM、同比环比、杜邦分解与盈利质量指标
+func ComputeFinancialTrend(code string, statements []models.FinancialStatement) *models.FinancialTrend {
+	byDate := make(map[string]models.FinancialStatement, len(statements))
+	for _, stmt := range statements {
+		byDate[stmt.ReportDate] = stmt
+	}
+
+	trend := &models.FinancialTrend{StockCode: code}
+	for i := len(statements) - 1; i >= 0; i-- {
+		stmt := statements[i]
+		if trend.Source == "" {
+			trend.Source = stmt.Source
+		}
+		trend.Periods = append(trend.Periods, computePeriodMetrics(stmt, byDate))
+	}
+	return trend
+}
+
+// computePeriodMetrics 计算单个报告期的衍生指标
+func computePeriodMetrics(stmt models.FinancialStatement, byDate map[string]models.FinancialStatement) models.FinancialPeriodMetrics {
+	m := models.FinancialPeriodMetrics{ReportDate: stmt.ReportDate}
+	date, err := time.Parse("2006-01-02", stmt.ReportDate)
+	if err != nil {
+		return m
+	}
+	yearAgo, hasYearAgo := byDate[shiftReportDate(date, -12)]
+
+	if stmt.TotalAssets > 0 {
+		m.DebtRatio = stmt.TotalLiab / stmt.TotalAssets * 100
+	}
+
+	// 单季数据及同比、环比
+	if rev, np, ok := quarterValues(stmt, date, byDate); ok {
+		m.HasQuarter = true
+		m.QuarterRevenue = rev / yuanPerYi
+		m.QuarterNetProfit = np / yuanPerYi
+		if hasYearAgo {
+			if prevRev, prevNP, ok := quarterValues(yearAgo, shiftDate(date, -12), byDate); ok {
+				m.RevenueYoY, m.NetProfitYoY, m.HasYoY = growthRate(rev, prevRev), growthRate(np, prevNP), true
+			}
+		}
+		prevDate := shiftDate(date, -3)
+		if prev, ok := byDate[prevDate.Format("2006-01-02")]; ok {
+			if prevRev, prevNP, ok := quarterValues(prev, prevDate, byDate); ok {
+				m.RevenueQoQ, m.NetProfitQoQ, m.HasQoQ = growthRate(rev, prevRev), growthRate(np, prevNP), true
+			}
+		}
+	}
+
+	// TTM 及杜邦分解
+	ttm, ok := ttmValues(stmt, date, byDate)
+	if !ok {
+		return m
+	}
+	m.HasTTM = true
+	m.RevenueTTM = ttm.Revenue / yuanPerYi
+	m.NetProfitTTM = ttm.NetProfit / yuanPerYi
+	m.OperatingCFTTM = ttm.OperatingCF / yuanPerYi
+	m.FreeCashFlowTTM = (ttm.OperatingCF - ttm.Capex) / yuanPerYi
+	if hasYearAgo {
+		if prevTTM, ok := ttmValues(yearAgo, shiftDate(date, -12), byDate); ok {
+			m.RevenueTTMYoY = growthRate(ttm.Revenue, prevTTM.Revenue)
+			m.NetProfitTTMYoY = growthRate(ttm.NetProfit, prevTTM.NetProfit)
+			m.HasTTMYoY = true
+		}
+	}
+
+	avgAssets, avgEquity := stmt.TotalAssets, stmt.TotalEquity
+	if hasYearAgo && yearAgo.TotalAssets > 0 && yearAgo.TotalEquity > 0 {
+		avgAssets = (stmt.TotalAssets + yearAgo.TotalAssets) / 2
+		avgEquity = (stmt.TotalEquity + yearAgo.TotalEquity) / 2
+	}
+	if ttm.Revenue != 0 {
+		m.GrossMargin = (ttm.Revenue - ttm.OperatingCost) / ttm.Revenue * 100
+		m.NetMargin = ttm.NetProfit / ttm.Revenue * 100
+	}
+	if avgAssets > 0 {
+		m.AssetTurnover = ttm.Revenue / avgAssets
+		m.AccrualRatio = (ttm.NetProfit - ttm.OperatingCF) / avgAssets * 100
+	}
+	if avgEquity > 0 {
+		m.EquityMultiplier = avgAssets / avgEquity
+		m.ROE = ttm.NetProfit / avgEquity * 100
+	}
+	if ttm.NetProfit > 0 {
+		m.CashConversion = ttm.OperatingCF / ttm.NetProfit * 100
+	}
+	return m
+}
+
+// shiftDate 按月平移报告期并对齐到月末
+func shiftDate(date time.Time, months int) time.Time {
+	firstOfMonth := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return firstOfMonth.AddDate(0, months+1, -1)
+}
+
+// shiftReportDate 按月平移报告期，返回 2006-01-02 格式
+func shiftReportDate(date time.Time, months int) string {
+	return shiftDate(date, months).Format("2006-01-02")
+}
+
+// quarterValues 由累计值推算单季营收与净利润
+func quarterValues(stmt models.FinancialStatement, date time.Time, byDate map[string]models.FinancialStatement) (revenue, netProfit float64, ok bool) {
+	if date.Month() == time.March {
+		return stmt.Revenue, stmt.NetProfit, true
+	}
+	prev, ok := byDate[shiftReportDate(date, -3)]
+	if !ok {
+		return 0, 0, false
+	}
+	return stmt.Revenue - prev.Revenue, stmt.NetProfit - prev.NetProfit, true
+}
+
+// ttmValues 滚动十二个月 = 本期累计 + 上年年报 - 上年同期累计
+func ttmValues(stmt models.FinancialStatement, date time.Time, byDate map[string]models.FinancialStatement) (models.FinancialStatement, bool) {
+	if date.Month() == time.December {
+		return stmt, true
+	}
+	lastAnnual, ok1 := byDate[fmt.Sprintf("%d-12-31", date.Year()-1)]
+	lastSame, ok2 := byDate[shiftReportDate(date, -12)]
+	if !ok1 || !ok2 {
+		return models.FinancialStatement{}, false
+	}
+	return models.FinancialStatement{
+		Revenue:       stmt.Revenue + lastAnnual.Revenue - lastSame.Revenue,
+		OperatingCost: stmt.OperatingCost + lastAnnual.OperatingCost - lastSame.OperatingCost,
+		NetProfit:     stmt.NetProfit + lastAnnual.NetProfit - lastSame.NetProfit,
+		OperatingCF:   stmt.OperatingCF + lastAnnual.OperatingCF - lastSame.OperatingCF,
+		Capex:         stmt.Capex + lastAnnual.Capex - lastSame.Capex,
+	}, true
+}
+
+// growthRate 增长率（%），基数为0时返回0；基数为负时按绝对值计算
+func growthRate(current, previous float64) float64 {
+	if previous == 0 {
+		return 0
+	}
+	return (current - previous) / math.Abs(previous) * 100
+}
+
+// FormatFinancialTrendForAI 格式化多期财务趋势供AI分析使用
+func FormatFinancialTrendForAI(trend *models.FinancialTrend) string {
+	if trend == nil || len(trend.Periods) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("\n## 财务趋势\n\n")
+
+	// 近8个季度（单季口径）
+	sb.WriteString("### 季度趋势（单季，金额单位：亿元）\n")
+	sb.WriteString("| 报告期 | 营收 | 营收同比 | 归母净利 | 净利同比 | 毛利率TTM | ROE TTM | 现金含量TTM |\n")
+	sb.WriteString("|---|---|---|---|---|---|---|---|\n")
+	rows := 0
+	for _, p := range trend.Periods {
+		if !p.HasQuarter {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("| %s | %.2f | %s | %.2f | %s | %s | %s | %s |\n",
+			p.ReportDate, p.QuarterRevenue, optionalPercent(p.RevenueYoY, p.HasYoY),
+			p.QuarterNetProfit, optionalPercent(p.NetProfitYoY, p.HasYoY),
+			optionalPercent(p.GrossMargin, p.HasTTM), optionalPercent(p.ROE, p.HasTTM),
+			optionalPercent(p.CashConversion, p.HasTTM && p.NetProfitTTM > 0)))
+		if rows++; rows >= trendQuarterRows {
+			break
+		}
+	}
+
+	// 近5个年度
+	sb.WriteString("\n### 年度概览（金额单位：亿元）\n")
+	sb.WriteString("| 年度 | 营收 | 营收增速 | 归母净利 | 净利增速 | 净利率 | 资产周转率 | 权益乘数 | ROE | 自由现金流 | 应计比率 |\n")
+	sb.WriteString("|---|---|---|---|---|---|---|---|---|---|---|\n")
+	rows = 0
+	for _, p := range trend.Periods {
+		if !p.HasTTM || !strings.HasSuffix(p.ReportDate, "-12-31") {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("| %s | %.2f | %s | %.2f | %s | %.2f%% | %.2f | %.2f | %.2f%% | %.2f | %.2f%% |\n",
+			p.ReportDate[:4], p.RevenueTTM, optionalPercent(p.RevenueTTMYoY, p.HasTTMYoY),
+			p.NetProfitTTM, optionalPercent(p.NetProfitTTMYoY, p.HasTTMYoY),
+			p.NetMargin, p.AssetTurnover, p.EquityMultiplier, p.ROE, p.FreeCashFlowTTM, p.AccrualRatio))
+		if rows++; rows >= trendAnnualRows {
+			break
+		}
+	}
+
+	// 最新一期TTM要点
+	for _, p := range trend.Periods {
+		if !p.HasTTM {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("\n### 最新TTM（截至 %s）\n", p.ReportDate))
+		sb.WriteString(fmt.Sprintf("- 营收：%.2f亿（同比 %s），归母净利：%.2f亿（同比 %s）\n",
+			p.RevenueTTM, optionalPercent(p.RevenueTTMYoY, p.HasTTMYoY), p.NetProfitTTM, optionalPercent(p.NetProfitTTMYoY, p.HasTTMYoY)))
+		sb.WriteString(fmt.Sprintf("- 杜邦分解：ROE %.2f%% = 净利率 %.2f%% × 资产周转率 %.2f × 权益乘数 %.2f\n",
+			p.ROE, p.NetMargin, p.AssetTurnover, p.EquityMultiplier))
+		sb.WriteString(fmt.Sprintf("- 经营现金流：%.2f亿，自由现金流：%.2f亿，现金含量：%s\n",
+			p.OperatingCFTTM, p.FreeCashFlowTTM, optionalPercent(p.CashConversion, p.NetProfitTTM > 0)))
+		sb.WriteString(fmt.Sprintf("- 应计比率：%.2f%%（越高说明利润中非现金部分越多），资产负债率：%.2f%%\n", p.AccrualRatio, p.DebtRatio))
+		break
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// optionalPercent 格式化百分比，数据不足时显示"-"
+func optionalPercent(value float64, ok bool) string {
+	if !ok {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f%%", value)
+}
@@ -0,0 +1,150 @@
+package data
+
+import (
+	"math"
+	"testing"
+
+	"stock-ai/backend/models"
+)
+
+// cumulativeStatements 累计口径的定期报告样例（金额单位：亿元），按报告期升序
+func cumulativeStatements(skip ...string) []models.FinancialStatement {
+	rows := []struct {
+		date            string
+		revenue, profit float64
+	}{
+		{"2023-03-31", 10, 1},
+		{"2023-06-30", 22, 2.2},
+		{"2023-09-30", 36, 3.6},
+		{"2023-12-31", 50, 5},
+		{"2024-03-31", 12, 1.5},
+		{"2024-06-30", 26, 3},
+		{"2024-09-30", 42, 4.4},
+		{"2024-12-31", 60, 6},
+	}
+	skipped := make(map[string]bool, len(skip))
+	for _, date := range skip {
+		skipped[date] = true
+	}
+	var statements []models.FinancialStatement
+	for _, r := range rows {
+		if skipped[r.date] {
+			continue
+		}
+		statements = append(statements, models.FinancialStatement{
+			ReportDate: r.date,
+			Revenue:    r.revenue * yuanPerYi,
+			NetProfit:  r.profit * yuanPerYi,
+		})
+	}
+	return statements
+}
+
+func TestComputeFinancialTrend(t *testing.T) {
+	tests := []struct {
+		name   string
+		skip   []string // 缺失的报告期
+		period string
+		// 单季
+		hasQuarter                bool
+		quarterRevenue, quarterNP float64
+		hasYoY                    bool
+		revenueYoY, netProfitYoY  float64
+		hasQoQ                    bool
+		revenueQoQ, netProfitQoQ  float64
+		// TTM
+		hasTTM                   bool
+		revenueTTM, netProfitTTM float64
+		hasTTMYoY                bool
+		revenueTTMYoY            float64
+	}{
+		{
+			name: "一季报累计值即单季，环比对比上年四季度", period: "2024-03-31",
+			hasQuarter: true, quarterRevenue: 12, quarterNP: 1.5,
+			hasYoY: true, revenueYoY: 20, netProfitYoY: 50,
+			hasQoQ: true, revenueQoQ: (12.0 - 14) / 14 * 100, netProfitQoQ: (1.5 - 1.4) / 1.4 * 100,
+			hasTTM: true, revenueTTM: 12 + 50 - 10, netProfitTTM: 1.5 + 5 - 1,
+		},
+		{
+			name: "中报单季由累计值相减", period: "2024-06-30",
+			hasQuarter: true, quarterRevenue: 14, quarterNP: 1.5,
+			hasYoY: true, revenueYoY: (14.0 - 12) / 12 * 100, netProfitYoY: (1.5 - 1.2) / 1.2 * 100,
+			hasQoQ: true, revenueQoQ: (14.0 - 12) / 12 * 100, netProfitQoQ: 0,
+			hasTTM: true, revenueTTM: 26 + 50 - 22, netProfitTTM: 3 + 5 - 2.2,
+			hasTTMYoY: false, // 上年同期 TTM 需要 2022 年报
+		},
+		{
+			name: "年报即 TTM 并可算 TTM 同比", period: "2024-12-31",
+			hasQuarter: true, quarterRevenue: 18, quarterNP: 1.6,
+			hasYoY: true, revenueYoY: (18.0 - 14) / 14 * 100, netProfitYoY: (1.6 - 1.4) / 1.4 * 100,
+			hasQoQ: true, revenueQoQ: (18.0 - 16) / 16 * 100, netProfitQoQ: (1.6 - 1.4) / 1.4 * 100,
+			hasTTM: true, revenueTTM: 60, netProfitTTM: 6,
+			hasTTMYoY: true, revenueTTMYoY: 20,
+		},
+		{
+			name: "缺三季报时年报无单季，TTM 不受影响", skip: []string{"2024-09-30"}, period: "2024-12-31",
+			hasQuarter: false,
+			hasTTM:     true, revenueTTM: 60, netProfitTTM: 6,
+			hasTTMYoY: true, revenueTTMYoY: 20,
+		},
+		{
+			name: "缺上年同期时无同比与 TTM", skip: []string{"2023-06-30"}, period: "2024-06-30",
+			hasQuarter: true, quarterRevenue: 14, quarterNP: 1.5,
+			hasYoY: false,
+			hasQoQ: true, revenueQoQ: (14.0 - 12) / 12 * 100, netProfitQoQ: 0,
+			hasTTM: false,
+		},
+		{
+			name: "缺上年年报时一季报无 TTM", period: "2023-03-31",
+			hasQuarter: true, quarterRevenue: 10, quarterNP: 1,
+			hasTTM: false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			trend := ComputeFinancialTrend("sh600000", cumulativeStatements(tc.skip...))
+			var m *models.FinancialPeriodMetrics
+			for i := range trend.Periods {
+				if trend.Periods[i].ReportDate == tc.period {
+					m = &trend.Periods[i]
+				}
+			}
+			if m == nil {
+				t.Fatalf("缺少报告期 %s", tc.period)
+			}
+
+			if m.HasQuarter != tc.hasQuarter || m.HasYoY != tc.hasYoY || m.HasQoQ != tc.hasQoQ ||
+				m.HasTTM != tc.hasTTM || m.HasTTMYoY != tc.hasTTMYoY {
+				t.Fatalf("标志位 quarter=%v yoy=%v qoq=%v ttm=%v ttmYoY=%v", m.HasQuarter, m.HasYoY, m.HasQoQ, m.HasTTM, m.HasTTMYoY)
+			}
+			checks := []struct {
+				name      string
+				got, want float64
+				enabled   bool
+			}{
+				{"单季营收", m.QuarterRevenue, tc.quarterRevenue, tc.hasQuarter},
+				{"单季净利润", m.QuarterNetProfit, tc.quarterNP, tc.hasQuarter},
+				{"营收同比", m.RevenueYoY, tc.revenueYoY, tc.hasYoY},
+				{"净利润同比", m.NetProfitYoY, tc.netProfitYoY, tc.hasYoY},
+				{"营收环比", m.RevenueQoQ, tc.revenueQoQ, tc.hasQoQ},
+				{"净利润环比", m.NetProfitQoQ, tc.netProfitQoQ, tc.hasQoQ},
+				{"营收TTM", m.RevenueTTM, tc.revenueTTM, tc.hasTTM},
+				{"净利润TTM", m.NetProfitTTM, tc.netProfitTTM, tc.hasTTM},
+				{"营收TTM同比", m.RevenueTTMYoY, tc.revenueTTMYoY, tc.hasTTMYoY},
+			}
+			for _, c := range checks {
+				if c.enabled && math.Abs(c.got-c.want) > 1e-6 {
+					t.Errorf("%s = %.6f, want %.6f", c.name, c.got, c.want)
+				}
+			}
+		})
+	}
+}
+
+func TestComputeFinancialTrendOrder(t *testing.T) {
+	trend := ComputeFinancialTrend("sh600000", cumulativeStatements())
+	if len(trend.Periods) != 8 || trend.Periods[0].ReportDate != "2024-12-31" || trend.Periods[7].ReportDate != "2023-03-31" {
+		t.Fatalf("报告期应按倒序排列, got %+v", trend.Periods)
+	}
+}
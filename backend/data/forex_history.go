@@ -0,0 +1,408 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"stock-ai/backend/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ==================== 交叉汇率 ====================
+
+// ForexConverter 基于一组汇率快照的货币换算器
+// 所有货币先折算为美元，再经美元三角换算得到任意交叉汇率
+type ForexConverter struct {
+	usdValue map[string]float64 // 1 单位货币折合的美元数
+	via      map[string]string  // 推导该货币美元价值时经过的中间货币（直接对美元为空）
+}
+
+// NewForexConverter 根据汇率列表构建换算器
+func NewForexConverter(rates []models.ForexRate) *ForexConverter {
+	// 构建货币图：pair XXXYYY 表示 1 XXX = rate YYY
+	type edge struct {
+		to    string
+		ratio float64 // 1 单位 from = ratio 单位 to
+	}
+	graph := make(map[string][]edge)
+	for _, r := range rates {
+		pair := strings.ToUpper(r.Pair)
+		if len(pair) != 6 || r.Rate <= 0 {
+			continue
+		}
+		base, quote := pair[:3], pair[3:]
+		graph[base] = append(graph[base], edge{to: quote, ratio: r.Rate})
+		graph[quote] = append(graph[quote], edge{to: base, ratio: 1 / r.Rate})
+	}
+
+	c := &ForexConverter{
+		usdValue: map[string]float64{"USD": 1},
+		via:      map[string]string{"USD": ""},
+	}
+
+	// 从美元出发广度优先遍历，直接对美元报价的货币优先
+	queue := []string{"USD"}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, e := range graph[cur] {
+			if _, ok := c.usdValue[e.to]; ok {
+				continue
+			}
+			// 1 cur = ratio to  =>  1 to = usdValue[cur] / ratio 美元
+			c.usdValue[e.to] = c.usdValue[cur] / e.ratio
+			if cur == "USD" {
+				c.via[e.to] = ""
+			} else {
+				c.via[e.to] = cur
+			}
+			queue = append(queue, e.to)
+		}
+	}
+	return c
+}
+
+// Supports 判断换算器是否可以处理该货币
+func (c *ForexConverter) Supports(currency string) bool {
+	_, ok := c.usdValue[strings.ToUpper(currency)]
+	return ok
+}
+
+// CrossRate 计算交叉汇率：1 单位 base 可兑换多少 quote
+func (c *ForexConverter) CrossRate(base, quote string) (*models.CrossRate, error) {
+	base = strings.ToUpper(strings.TrimSpace(base))
+	quote = strings.ToUpper(strings.TrimSpace(quote))
+
+	baseUSD, ok := c.usdValue[base]
+	if !ok {
+		return nil, fmt.Errorf("缺少%s的汇率数据", base)
+	}
+	quoteUSD, ok := c.usdValue[quote]
+	if !ok {
+		return nil, fmt.Errorf("缺少%s的汇率数据", quote)
+	}
+
+	return &models.CrossRate{
+		Base:  base,
+		Quote: quote,
+		Rate:  baseUSD / quoteUSD,
+		Path:  c.path(base, quote),
+	}, nil
+}
+
+// Convert 将金额从一种货币换算为另一种货币
+func (c *ForexConverter) Convert(amount float64, from, to string) (float64, error) {
+	if strings.EqualFold(from, to) {
+		return amount, nil
+	}
+	rate, err := c.CrossRate(from, to)
+	if err != nil {
+		return 0, err
+	}
+	return amount * rate.Rate, nil
+}
+
+// path 生成换算路径描述，如 JPY→USD→CNY→HKD（两条推导链在共同节点处汇合）
+func (c *ForexConverter) path(base, quote string) string {
+	if base == quote {
+		return base
+	}
+	baseChain := c.chainToUSD(base)
+	quoteChain := c.chainToUSD(quote)
+
+	quoteIndex := make(map[string]int, len(quoteChain))
+	for i, cur := range quoteChain {
+		quoteIndex[cur] = i
+	}
+
+	nodes := make([]string, 0, len(baseChain)+len(quoteChain))
+	for _, cur := range baseChain {
+		nodes = append(nodes, cur)
+		if idx, ok := quoteIndex[cur]; ok {
+			for j := idx - 1; j >= 0; j-- {
+				nodes = append(nodes, quoteChain[j])
+			}
+			break
+		}
+	}
+	return strings.Join(nodes, "→")
+}
+
+// chainToUSD 返回从货币到美元的推导链，如 [HKD CNY USD]
+func (c *ForexConverter) chainToUSD(currency string) []string {
+	chain := []string{currency}
+	for cur := currency; cur != "USD"; {
+		next := c.via[cur]
+		if next == "" {
+			next = "USD"
+		}
+		chain = append(chain, next)
+		cur = next
+	}
+	return chain
+}
+
+// GetForexConverter 使用最新汇率构建换算器
+func (api *CryptoForexAPI) GetForexConverter(ctx context.Context) (*ForexConverter, error) {
+	rates, err := api.GetForexRates(ctx)
+	converter := NewForexConverter(rates)
+	if len(converter.usdValue) <= 1 {
+		if err == nil {
+			err = fmt.Errorf("暂无可用汇率")
+		}
+		return nil, fmt.Errorf("获取汇率失败: %v", err)
+	}
+	return converter, nil
+}
+
+// GetCrossRate 获取任意货币对的交叉汇率（经美元三角换算）
+func (api *CryptoForexAPI) GetCrossRate(ctx context.Context, base, quote string) (*models.CrossRate, error) {
+	converter, err := api.GetForexConverter(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return converter.CrossRate(base, quote)
+}
+
+// ConvertCurrency 按最新汇率换算金额
+func (api *CryptoForexAPI) ConvertCurrency(ctx context.Context, amount float64, from, to string) (float64, error) {
+	if strings.EqualFold(from, to) {
+		return amount, nil
+	}
+	converter, err := api.GetForexConverter(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return converter.Convert(amount, from, to)
+}
+
+// CurrencyForStockCode 根据证券代码推断计价货币
+// hk+数字或5位数字为港股（HKD），sh/sz/bj+数字为A股（CNY），gb_/us.前缀或纯字母为美股（USD），其余视为A股
+func CurrencyForStockCode(code string) string {
+	lower := strings.ToLower(strings.TrimSpace(code))
+	switch {
+	case strings.HasPrefix(lower, "gb_"), strings.HasPrefix(lower, "us."):
+		return "USD"
+	case hasDigitsAfterPrefix(lower, "hk"):
+		return "HKD"
+	case hasDigitsAfterPrefix(lower, "sh"), hasDigitsAfterPrefix(lower, "sz"), hasDigitsAfterPrefix(lower, "bj"):
+		return "CNY"
+	}
+	if len(lower) == 5 && strings.Trim(lower, "0123456789") == "" {
+		return "HKD"
+	}
+	if lower != "" && strings.Trim(lower, "abcdefghijklmnopqrstuvwxyz.") == "" {
+		return "USD"
+	}
+	return "CNY"
+}
+
+// USStockSymbol 去掉美股代码的 gb_ 或 us. 前缀并转为大写，如 gb_aapl、us.USB → AAPL、USB
+func USStockSymbol(code string) string {
+	code = strings.TrimSpace(code)
+	lower := strings.ToLower(code)
+	for _, prefix := range []string{"gb_", "us."} {
+		if strings.HasPrefix(lower, prefix) {
+			code = code[len(prefix):]
+			break
+		}
+	}
+	return strings.ToUpper(code)
+}
+
+// hasDigitsAfterPrefix 判断 code 是否为 prefix 加纯数字，如 sh600519、hk00700
+func hasDigitsAfterPrefix(code, prefix string) bool {
+	rest, ok := strings.CutPrefix(code, prefix)
+	return ok && rest != "" && strings.Trim(rest, "0123456789") == ""
+}
+
+// ==================== 汇率历史 ====================
+
+// RecordForexSnapshot 将一次汇率快照记入当日历史（开盘取首笔，高低点滚动更新，收盘取最新）
+func RecordForexSnapshot(rates []models.ForexRate) {
+	db := GetDB()
+	if db == nil {
+		return
+	}
+
+	today := time.Now().Format("2006-01-02")
+	now := time.Now()
+	for _, r := range rates {
+		if r.Rate <= 0 || len(r.Pair) != 6 {
+			continue
+		}
+		record := models.ForexHistory{
+			Pair:      strings.ToUpper(r.Pair),
+			Date:      today,
+			Open:      r.Rate,
+			High:      r.Rate,
+			Low:       r.Rate,
+			Close:     r.Rate,
+			Source:    "snapshot",
+			UpdatedAt: now,
+		}
+		err := db.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "pair"}, {Name: "date"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"high":       gorm.Expr("MAX(high, ?)", r.Rate),
+				"low":        gorm.Expr("MIN(low, ?)", r.Rate),
+				"close":      r.Rate,
+				"updated_at": now,
+			}),
+		}).Create(&record).Error
+		if err != nil {
+			log.Printf("[外汇历史] 记录快照失败(%s): %v", r.Pair, err)
+		}
+	}
+}
+
+// SyncForexHistory 从东方财富回补货币对的日线历史，返回写入条数
+func (api *CryptoForexAPI) SyncForexHistory(ctx context.Context, pair string, days int) (int, error) {
+	db := GetDB()
+	if db == nil {
+		return 0, fmt.Errorf("数据库未初始化")
+	}
+	pair = strings.ToUpper(pair)
+	if days <= 0 {
+		days = 365
+	}
+
+	klines, err := fetchEastMoneyDailyKLine(ctx, api.rm, []string{"119." + pair, "133." + pair, "120." + pair}, pair, days)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	records := make([]models.ForexHistory, 0, len(klines))
+	for _, k := range klines {
+		if k.Close <= 0 {
+			continue
+		}
+		records = append(records, models.ForexHistory{
+			Pair:      pair,
+			Date:      k.Date,
+			Open:      k.Open,
+			High:      k.High,
+			Low:       k.Low,
+			Close:     k.Close,
+			Source:    "eastmoney",
+			UpdatedAt: now,
+		})
+	}
+	if len(records) == 0 {
+		return 0, nil
+	}
+
+	err = db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "pair"}, {Name: "date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"open", "high", "low", "close", "source", "updated_at"}),
+	}).CreateInBatches(records, 200).Error
+	if err != nil {
+		return 0, err
+	}
+	return len(records), nil
+}
+
+// GetForexHistory 获取货币对最近N天的日线历史
+// 本地数据不足时先从东方财富回补；非直接报价的货币对通过美元交叉换算得到
+func (api *CryptoForexAPI) GetForexHistory(ctx context.Context, pair string, days int) ([]models.ForexHistory, error) {
+	pair = strings.ToUpper(strings.NewReplacer("/", "", "-", "", "_", "").Replace(strings.TrimSpace(pair)))
+	if len(pair) != 6 {
+		return nil, fmt.Errorf("无效的货币对: %s", pair)
+	}
+	if days <= 0 {
+		days = 90
+	}
+
+	history, err := loadForexHistory([]string{pair}, days)
+	if err != nil {
+		return nil, err
+	}
+	// 交易日约为自然日的 5/7，不足三分之一视为本地数据缺失
+	if len(history) < days/3 && isMainForexPair(pair) {
+		if n, err := api.SyncForexHistory(ctx, pair, days); err != nil {
+			log.Printf("[外汇历史] 回补%s失败: %v", pair, err)
+		} else if n > 0 {
+			history, _ = loadForexHistory([]string{pair}, days)
+		}
+	}
+	if len(history) > 0 {
+		return history, nil
+	}
+
+	return api.GetCrossRateHistory(pair[:3], pair[3:], days)
+}
+
+// GetCrossRateHistory 按日期用主要货币对历史经美元换算出交叉汇率历史
+func (api *CryptoForexAPI) GetCrossRateHistory(base, quote string, days int) ([]models.ForexHistory, error) {
+	base = strings.ToUpper(base)
+	quote = strings.ToUpper(quote)
+
+	pairs := make([]string, 0, len(mainForexPairs))
+	for _, p := range mainForexPairs {
+		pairs = append(pairs, p.Pair)
+	}
+	history, err := loadForexHistory(pairs, days)
+	if err != nil {
+		return nil, err
+	}
+
+	byDate := make(map[string][]models.ForexRate)
+	for _, h := range history {
+		byDate[h.Date] = append(byDate[h.Date], models.ForexRate{Pair: h.Pair, Rate: h.Close})
+	}
+
+	dates := make([]string, 0, len(byDate))
+	for d := range byDate {
+		dates = append(dates, d)
+	}
+	sort.Strings(dates)
+
+	result := make([]models.ForexHistory, 0, len(dates))
+	for _, d := range dates {
+		cross, err := NewForexConverter(byDate[d]).CrossRate(base, quote)
+		if err != nil {
+			continue
+		}
+		result = append(result, models.ForexHistory{
+			Pair:   base + quote,
+			Date:   d,
+			Open:   cross.Rate,
+			High:   cross.Rate,
+			Low:    cross.Rate,
+			Close:  cross.Rate,
+			Source: "cross",
+		})
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("暂无%s/%s的历史汇率", base, quote)
+	}
+	return result, nil
+}
+
+func loadForexHistory(pairs []string, days int) ([]models.ForexHistory, error) {
+	db := GetDB()
+	if db == nil {
+		return nil, fmt.Errorf("数据库未初始化")
+	}
+	since := time.Now().AddDate(0, 0, -days).Format("2006-01-02")
+
+	var history []models.ForexHistory
+	err := db.Where("pair IN ? AND date >= ?", pairs, since).Order("date ASC").Find(&history).Error
+	return history, err
+}
+
+func isMainForexPair(pair string) bool {
+	for _, p := range mainForexPairs {
+		if p.Pair == pair {
+			return true
+		}
+	}
+	return false
+}
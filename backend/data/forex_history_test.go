@@ -0,0 +1,41 @@
+package data
+
+import "testing"
+
+func TestCurrencyForStockCode(t *testing.T) {
+	cases := map[string]string{
+		"sh600519": "CNY",
+		"sz000001": "CNY",
+		"bj430047": "CNY",
+		"hk00700":  "HKD",
+		"00700":    "HKD",
+		"gb_aapl":  "USD",
+		"us.USB":   "USD",
+		"USB":      "USD",
+		"SHOP":     "USD",
+		"HKIT":     "USD",
+		"BRK.B":    "USD",
+		"600519":   "CNY",
+	}
+	for code, want := range cases {
+		if got := CurrencyForStockCode(code); got != want {
+			t.Errorf("CurrencyForStockCode(%q) = %s, want %s", code, got, want)
+		}
+	}
+}
+
+func TestUSStockSymbol(t *testing.T) {
+	cases := map[string]string{
+		"gb_aapl": "AAPL",
+		"us.USB":  "USB",
+		"USB":     "USB",
+		"usb":     "USB",
+		"gb_usb":  "USB",
+		"UBER":    "UBER",
+	}
+	for code, want := range cases {
+		if got := USStockSymbol(code); got != want {
+			t.Errorf("USStockSymbol(%q) = %s, want %s", code, got, want)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package data
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"html"
@@ -32,11 +33,12 @@ type FundAPI struct {
 	priceMu          sync.Mutex
 	priceFirstLoad   bool
 	priceSourceIndex int
+	schema           *SchemaMonitor
 }
 
 type fundPriceSource struct {
 	name  string
-	fetch func([]string) (map[string]*models.FundPrice, error)
+	fetch func(context.Context, []string) (map[string]*models.FundPrice, error)
 }
 
 func (api *FundAPI) buildFundURL(endpoint string, params map[string]string) string {
@@ -51,8 +53,8 @@ func (api *FundAPI) buildFundURL(endpoint string, params map[string]string) stri
 	return fmt.Sprintf("%s/%s?%s", fundAPIBaseURL, endpoint, values.Encode())
 }
 
-func (api *FundAPI) doFundRequest(endpoint string, params map[string]string, target interface{}) error {
-	req, err := http.NewRequest("GET", api.buildFundURL(endpoint, params), nil)
+func (api *FundAPI) doFundRequest(ctx context.Context, endpoint string, params map[string]string, target interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", api.buildFundURL(endpoint, params), nil)
 	if err != nil {
 		return err
 	}
@@ -94,6 +96,7 @@ func NewFundAPI() *FundAPI {
 		client: &http.Client{
 			Timeout: 10 * time.Second,
 		},
+		schema: GetSchemaMonitor(),
 	}
 	api.priceSources = []fundPriceSource{
 		{name: "eastmoney", fetch: api.fetchFundPriceFromEastmoney},
@@ -104,19 +107,31 @@ func NewFundAPI() *FundAPI {
 	return api
 }
 
+// SetTransport 替换HTTP传输层（如 FixtureTransport），传nil恢复默认
+func (api *FundAPI) SetTransport(rt http.RoundTripper) {
+	api.client.Transport = rt
+}
+
 // GetFundPrice 获取基金估值（多数据源）
-func (api *FundAPI) GetFundPrice(codes []string) (map[string]*models.FundPrice, error) {
+func (api *FundAPI) GetFundPrice(ctx context.Context, codes []string) (map[string]*models.FundPrice, error) {
+	return coalesce(ctx, "fund.price", []interface{}{codesParam(codes)}, func(ctx context.Context) (map[string]*models.FundPrice, error) {
+		return api.fetchFundPrice(ctx, codes)
+	})
+}
+
+// fetchFundPrice 实际请求，并发的相同调用由 GetFundPrice 合并
+func (api *FundAPI) fetchFundPrice(ctx context.Context, codes []string) (map[string]*models.FundPrice, error) {
 	cleanCodes := api.sanitizeFundCodes(codes)
 	if len(cleanCodes) == 0 {
 		return map[string]*models.FundPrice{}, nil
 	}
 
-	result, source, err := api.fetchFundPricesWithFallback(cleanCodes)
+	result, source, err := api.fetchFundPricesWithFallback(ctx, cleanCodes)
 	if err != nil {
 		return result, err
 	}
 
-	api.fillMissingFundPrices(result, cleanCodes, source)
+	api.fillMissingFundPrices(ctx, result, cleanCodes, source)
 	return result, nil
 }
 
@@ -137,36 +152,37 @@ func (api *FundAPI) sanitizeFundCodes(codes []string) []string {
 	return result
 }
 
-func (api *FundAPI) fetchFundPricesWithFallback(codes []string) (map[string]*models.FundPrice, string, error) {
+func (api *FundAPI) fetchFundPricesWithFallback(ctx context.Context, codes []string) (map[string]*models.FundPrice, string, error) {
 	api.priceMu.Lock()
 	firstLoad := api.priceFirstLoad
 	api.priceMu.Unlock()
 
 	if firstLoad {
-		return api.parallelFetchFundPrices(codes)
+		return api.parallelFetchFundPrices(ctx, codes)
 	}
-	return api.roundRobinFetchFundPrices(codes)
+	return api.roundRobinFetchFundPrices(ctx, codes)
 }
 
-func (api *FundAPI) parallelFetchFundPrices(codes []string) (map[string]*models.FundPrice, string, error) {
+func (api *FundAPI) parallelFetchFundPrices(ctx context.Context, codes []string) (map[string]*models.FundPrice, string, error) {
 	type result struct {
 		data   map[string]*models.FundPrice
 		source string
 		err    error
 	}
 
-	resultCh := make(chan result, len(api.priceSources))
+	sources := api.healthyPriceSources()
+	resultCh := make(chan result, len(sources))
 
-	for _, src := range api.priceSources {
+	for _, src := range sources {
 		go func(source fundPriceSource) {
-			data, err := source.fetch(codes)
+			data, err := source.fetch(ctx, codes)
 			resultCh <- result{data: data, source: source.name, err: err}
 		}(src)
 	}
 
 	var best result
 	var lastErr error
-	for i := 0; i < len(api.priceSources); i++ {
+	for i := 0; i < len(sources); i++ {
 		res := <-resultCh
 		if res.err != nil {
 			lastErr = res.err
@@ -192,7 +208,7 @@ func (api *FundAPI) parallelFetchFundPrices(codes []string) (map[string]*models.
 	return nil, "", lastErr
 }
 
-func (api *FundAPI) roundRobinFetchFundPrices(codes []string) (map[string]*models.FundPrice, string, error) {
+func (api *FundAPI) roundRobinFetchFundPrices(ctx context.Context, codes []string) (map[string]*models.FundPrice, string, error) {
 	if len(api.priceSources) == 0 {
 		return nil, "", fmt.Errorf("无可用数据源")
 	}
@@ -202,11 +218,19 @@ func (api *FundAPI) roundRobinFetchFundPrices(codes []string) (map[string]*model
 	api.priceSourceIndex = (api.priceSourceIndex + 1) % len(api.priceSources)
 	api.priceMu.Unlock()
 
+	healthy := make(map[string]bool)
+	for _, src := range api.healthyPriceSources() {
+		healthy[src.name] = true
+	}
+
 	var lastErr error
 	for i := 0; i < len(api.priceSources); i++ {
 		idx := (start + i) % len(api.priceSources)
 		src := api.priceSources[idx]
-		data, err := src.fetch(codes)
+		if !healthy[src.name] {
+			continue
+		}
+		data, err := src.fetch(ctx, codes)
 		if err == nil && len(data) > 0 {
 			return data, src.name, nil
 		}
@@ -221,6 +245,20 @@ func (api *FundAPI) roundRobinFetchFundPrices(codes []string) (map[string]*model
 	return nil, "", lastErr
 }
 
+// healthyPriceSources 返回字段结构正常的估值数据源，全部异常时返回全部数据源兜底
+func (api *FundAPI) healthyPriceSources() []fundPriceSource {
+	var sources []fundPriceSource
+	for _, src := range api.priceSources {
+		if !api.schema.IsBroken(src.name, SchemaDatasetFund) {
+			sources = append(sources, src)
+		}
+	}
+	if len(sources) == 0 {
+		return api.priceSources
+	}
+	return sources
+}
+
 func (api *FundAPI) updateNextSourceIndex(source string) {
 	api.priceMu.Lock()
 	defer api.priceMu.Unlock()
@@ -233,7 +271,7 @@ func (api *FundAPI) updateNextSourceIndex(source string) {
 	api.priceSourceIndex = (api.priceSourceIndex + 1) % len(api.priceSources)
 }
 
-func (api *FundAPI) fillMissingFundPrices(result map[string]*models.FundPrice, codes []string, usedSource string) {
+func (api *FundAPI) fillMissingFundPrices(ctx context.Context, result map[string]*models.FundPrice, codes []string, usedSource string) {
 	if result == nil {
 		result = make(map[string]*models.FundPrice)
 	}
@@ -243,11 +281,11 @@ func (api *FundAPI) fillMissingFundPrices(result map[string]*models.FundPrice, c
 		return
 	}
 
-	for _, src := range api.priceSources {
+	for _, src := range api.healthyPriceSources() {
 		if src.name == usedSource {
 			continue
 		}
-		data, err := src.fetch(missing)
+		data, err := src.fetch(ctx, missing)
 		if err != nil || len(data) == 0 {
 			continue
 		}
@@ -273,10 +311,10 @@ func (api *FundAPI) findMissingCodes(codes []string, current map[string]*models.
 	return missing
 }
 
-func (api *FundAPI) fetchFundPriceFromEastmoney(codes []string) (map[string]*models.FundPrice, error) {
+func (api *FundAPI) fetchFundPriceFromEastmoney(ctx context.Context, codes []string) (map[string]*models.FundPrice, error) {
 	result := make(map[string]*models.FundPrice)
 	for _, code := range codes {
-		price, err := api.getFundEstimate(code)
+		price, err := api.getFundEstimate(ctx, code)
 		if err != nil || price == nil {
 			continue
 		}
@@ -285,10 +323,14 @@ func (api *FundAPI) fetchFundPriceFromEastmoney(codes []string) (map[string]*mod
 	if len(result) == 0 {
 		return nil, fmt.Errorf("eastmoney: 未获取到有效基金估值")
 	}
+	// 逐只请求，没有整批原始响应可保存
+	if err := api.schema.Check(fundPriceSchema("eastmoney"), nil, fundPriceRecords(result)); err != nil {
+		return nil, err
+	}
 	return result, nil
 }
 
-func (api *FundAPI) fetchFundPriceFromTencent(codes []string) (map[string]*models.FundPrice, error) {
+func (api *FundAPI) fetchFundPriceFromTencent(ctx context.Context, codes []string) (map[string]*models.FundPrice, error) {
 	if len(codes) == 0 {
 		return map[string]*models.FundPrice{}, nil
 	}
@@ -306,7 +348,7 @@ func (api *FundAPI) fetchFundPriceFromTencent(codes []string) (map[string]*model
 	}
 
 	url := fmt.Sprintf("http://qt.gtimg.cn/q=%s", strings.Join(queryCodes, ","))
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -367,10 +409,13 @@ func (api *FundAPI) fetchFundPriceFromTencent(codes []string) (map[string]*model
 	if len(result) == 0 {
 		return nil, fmt.Errorf("tencent: 未获取到有效基金估值")
 	}
+	if err := api.schema.Check(fundPriceSchema("tencent"), body, fundPriceRecords(result)); err != nil {
+		return nil, err
+	}
 	return result, nil
 }
 
-func (api *FundAPI) fetchFundPriceFromSina(codes []string) (map[string]*models.FundPrice, error) {
+func (api *FundAPI) fetchFundPriceFromSina(ctx context.Context, codes []string) (map[string]*models.FundPrice, error) {
 	if len(codes) == 0 {
 		return map[string]*models.FundPrice{}, nil
 	}
@@ -388,7 +433,7 @@ func (api *FundAPI) fetchFundPriceFromSina(codes []string) (map[string]*models.F
 	}
 
 	url := fmt.Sprintf("http://hq.sinajs.cn/list=%s", strings.Join(queryCodes, ","))
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -449,6 +494,9 @@ func (api *FundAPI) fetchFundPriceFromSina(codes []string) (map[string]*models.F
 	if len(result) == 0 {
 		return nil, fmt.Errorf("sina: 未获取到有效基金估值")
 	}
+	if err := api.schema.Check(fundPriceSchema("sina"), body, fundPriceRecords(result)); err != nil {
+		return nil, err
+	}
 	return result, nil
 }
 
@@ -472,9 +520,9 @@ func (api *FundAPI) BuildFallbackFundDetail(code string, price *models.FundPrice
 	}
 }
 
-func (api *FundAPI) fetchFundDetailFromPingzhong(code string) (*models.FundDetail, error) {
+func (api *FundAPI) fetchFundDetailFromPingzhong(ctx context.Context, code string) (*models.FundDetail, error) {
 	url := fmt.Sprintf("https://fund.eastmoney.com/pingzhongdata/%s.js", code)
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -577,9 +625,9 @@ func (api *FundAPI) fetchFundDetailFromPingzhong(code string) (*models.FundDetai
 	return detail, nil
 }
 
-func (api *FundAPI) fetchFundMetaFromSuggestion(code string) (*models.FundDetail, error) {
+func (api *FundAPI) fetchFundMetaFromSuggestion(ctx context.Context, code string) (*models.FundDetail, error) {
 	url := fmt.Sprintf("https://fundsuggest.eastmoney.com/FundSearch/api/FundSearchAPI.ashx?m=1&key=%s", code)
-	resp, err := api.client.Get(url)
+	resp, err := getWithContext(ctx, api.client, url)
 	if err != nil {
 		return nil, err
 	}
@@ -685,9 +733,9 @@ func parsePercentValue(val string) float64 {
 	return parseFloat(val)
 }
 
-func (api *FundAPI) fetchFundHoldingsFromF10(code string) ([]models.FundHolding, error) {
+func (api *FundAPI) fetchFundHoldingsFromF10(ctx context.Context, code string) ([]models.FundHolding, error) {
 	url := fmt.Sprintf("https://fundf10.eastmoney.com/FundArchivesDatas.aspx?type=jjcc&code=%s&topline=10&year=&month=", code)
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -769,7 +817,7 @@ func (api *FundAPI) fetchFundHoldingsFromF10(code string) ([]models.FundHolding,
 	return holdings, nil
 }
 
-func (api *FundAPI) fillFundMetaFromSuggestion(detail *models.FundDetail) {
+func (api *FundAPI) fillFundMetaFromSuggestion(ctx context.Context, detail *models.FundDetail) {
 	if detail == nil {
 		return
 	}
@@ -777,7 +825,7 @@ func (api *FundAPI) fillFundMetaFromSuggestion(detail *models.FundDetail) {
 	if !needMeta {
 		return
 	}
-	if meta, err := api.fetchFundMetaFromSuggestion(detail.Code); err == nil && meta != nil {
+	if meta, err := api.fetchFundMetaFromSuggestion(ctx, detail.Code); err == nil && meta != nil {
 		if detail.Name == "" && meta.Name != "" {
 			detail.Name = meta.Name
 		}
@@ -796,7 +844,7 @@ func (api *FundAPI) fillFundMetaFromSuggestion(detail *models.FundDetail) {
 	}
 }
 
-func (api *FundAPI) enrichFundDetailWithPingzhong(detail *models.FundDetail) {
+func (api *FundAPI) enrichFundDetailWithPingzhong(ctx context.Context, detail *models.FundDetail) {
 	if detail == nil {
 		return
 	}
@@ -807,13 +855,13 @@ func (api *FundAPI) enrichFundDetailWithPingzhong(detail *models.FundDetail) {
 	needNav := detail.Nav == 0 || detail.Estimate == 0 || detail.NavDate == ""
 
 	if !needPerformance && !needManager && !needNav {
-		api.fillFundMetaFromSuggestion(detail)
+		api.fillFundMetaFromSuggestion(ctx, detail)
 		return
 	}
 
-	fallback, err := api.fetchFundDetailFromPingzhong(detail.Code)
+	fallback, err := api.fetchFundDetailFromPingzhong(ctx, detail.Code)
 	if err != nil || fallback == nil {
-		api.fillFundMetaFromSuggestion(detail)
+		api.fillFundMetaFromSuggestion(ctx, detail)
 		return
 	}
 
@@ -845,7 +893,7 @@ func (api *FundAPI) enrichFundDetailWithPingzhong(detail *models.FundDetail) {
 			detail.SinceStartReturn = fallback.SinceStartReturn
 		}
 	}
-	api.fillFundMetaFromSuggestion(detail)
+	api.fillFundMetaFromSuggestion(ctx, detail)
 }
 
 func extractJSString(js, key string) string {
@@ -885,10 +933,10 @@ func extractJSArray(js, key string) string {
 }
 
 // getFundEstimate 获取单只基金估值
-func (api *FundAPI) getFundEstimate(code string) (*models.FundPrice, error) {
+func (api *FundAPI) getFundEstimate(ctx context.Context, code string) (*models.FundPrice, error) {
 	url := fmt.Sprintf("https://fundgz.1234567.com.cn/js/%s.js?rt=%d", code, time.Now().UnixMilli())
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -937,7 +985,14 @@ func (api *FundAPI) getFundEstimate(code string) (*models.FundPrice, error) {
 }
 
 // GetFundDetail 获取基金基本信息
-func (api *FundAPI) GetFundDetail(code string) (*models.FundDetail, error) {
+func (api *FundAPI) GetFundDetail(ctx context.Context, code string) (*models.FundDetail, error) {
+	return coalesce(ctx, "fund.detail", []interface{}{code}, func(ctx context.Context) (*models.FundDetail, error) {
+		return api.fetchFundDetail(ctx, code)
+	})
+}
+
+// fetchFundDetail 实际请求，并发的相同调用由 GetFundDetail 合并
+func (api *FundAPI) fetchFundDetail(ctx context.Context, code string) (*models.FundDetail, error) {
 	const maxRetries = 3
 	var lastErr error
 
@@ -969,7 +1024,7 @@ func (api *FundAPI) GetFundDetail(code string) (*models.FundDetail, error) {
 			ErrCode int    `json:"ErrCode"`
 		}
 
-		if err := api.doFundRequest("FundMNBasicInformation", map[string]string{"FCODE": code}, &resp); err != nil {
+		if err := api.doFundRequest(ctx, "FundMNBasicInformation", map[string]string{"FCODE": code}, &resp); err != nil {
 			lastErr = err
 		} else if resp.Success {
 			data := resp.Datas
@@ -994,7 +1049,7 @@ func (api *FundAPI) GetFundDetail(code string) (*models.FundDetail, error) {
 				SharpRatio:       parseFloat(data.SHARP1),
 				MaxDrawdown:      parseFloat(data.MAXRETRA1),
 			}
-			api.enrichFundDetailWithPingzhong(detail)
+			api.enrichFundDetailWithPingzhong(ctx, detail)
 			return detail, nil
 		} else {
 			msg := resp.ErrMsg
@@ -1012,8 +1067,8 @@ func (api *FundAPI) GetFundDetail(code string) (*models.FundDetail, error) {
 		}
 	}
 
-	if fallback, err := api.fetchFundDetailFromPingzhong(code); err == nil && fallback != nil {
-		api.fillFundMetaFromSuggestion(fallback)
+	if fallback, err := api.fetchFundDetailFromPingzhong(ctx, code); err == nil && fallback != nil {
+		api.fillFundMetaFromSuggestion(ctx, fallback)
 		return fallback, nil
 	}
 	if lastErr == nil {
@@ -1023,7 +1078,14 @@ func (api *FundAPI) GetFundDetail(code string) (*models.FundDetail, error) {
 }
 
 // GetFundHistory 获取基金历史净值
-func (api *FundAPI) GetFundHistory(code string, count int) ([]models.FundPerformancePoint, error) {
+func (api *FundAPI) GetFundHistory(ctx context.Context, code string, count int) ([]models.FundPerformancePoint, error) {
+	return coalesce(ctx, "fund.history", []interface{}{code, count}, func(ctx context.Context) ([]models.FundPerformancePoint, error) {
+		return api.fetchFundHistory(ctx, code, count)
+	})
+}
+
+// fetchFundHistory 实际请求，并发的相同调用由 GetFundHistory 合并
+func (api *FundAPI) fetchFundHistory(ctx context.Context, code string, count int) ([]models.FundPerformancePoint, error) {
 	if count <= 0 {
 		count = 60
 	}
@@ -1049,7 +1111,7 @@ func (api *FundAPI) GetFundHistory(code string, count int) ([]models.FundPerform
 			ErrCode int    `json:"ErrCode"`
 		}
 
-		if err := api.doFundRequest("FundMNHisNetList", params, &resp); err != nil {
+		if err := api.doFundRequest(ctx, "FundMNHisNetList", params, &resp); err != nil {
 			lastErr = err
 		} else if resp.Success {
 			history := make([]models.FundPerformancePoint, 0, len(resp.Datas))
@@ -1078,7 +1140,7 @@ func (api *FundAPI) GetFundHistory(code string, count int) ([]models.FundPerform
 		}
 	}
 
-	if history, err := api.fetchFundHistoryFromAPI(code, count); err == nil && len(history) > 0 {
+	if history, err := api.fetchFundHistoryFromAPI(ctx, code, count); err == nil && len(history) > 0 {
 		return history, nil
 	} else if err != nil && lastErr == nil {
 		lastErr = err
@@ -1091,7 +1153,7 @@ func (api *FundAPI) GetFundHistory(code string, count int) ([]models.FundPerform
 }
 
 // GetFundHoldings 获取基金持仓
-func (api *FundAPI) GetFundHoldings(code string) ([]models.FundHolding, []models.FundHolding, error) {
+func (api *FundAPI) GetFundHoldings(ctx context.Context, code string) ([]models.FundHolding, []models.FundHolding, error) {
 	const maxRetries = 3
 	var lastErr error
 
@@ -1117,7 +1179,7 @@ func (api *FundAPI) GetFundHoldings(code string) ([]models.FundHolding, []models
 			ErrCode int    `json:"ErrCode"`
 		}
 
-		if err := api.doFundRequest("FundMNInverstPosition", map[string]string{"FCODE": code}, &resp); err != nil {
+		if err := api.doFundRequest(ctx, "FundMNInverstPosition", map[string]string{"FCODE": code}, &resp); err != nil {
 			lastErr = err
 		} else if resp.Success {
 			var stockHoldings []models.FundHolding
@@ -1163,7 +1225,7 @@ func (api *FundAPI) GetFundHoldings(code string) ([]models.FundHolding, []models
 		}
 	}
 
-	if fallbackStocks, err := api.fetchFundHoldingsFromF10(code); err == nil && len(fallbackStocks) > 0 {
+	if fallbackStocks, err := api.fetchFundHoldingsFromF10(ctx, code); err == nil && len(fallbackStocks) > 0 {
 		return fallbackStocks, nil, nil
 	}
 
@@ -1174,7 +1236,14 @@ func (api *FundAPI) GetFundHoldings(code string) ([]models.FundHolding, []models
 }
 
 // GetFundNotices 获取基金公告
-func (api *FundAPI) GetFundNotices(code string, count int) ([]models.FundNotice, error) {
+func (api *FundAPI) GetFundNotices(ctx context.Context, code string, count int) ([]models.FundNotice, error) {
+	return coalesce(ctx, "fund.notices", []interface{}{code, count}, func(ctx context.Context) ([]models.FundNotice, error) {
+		return api.fetchFundNotices(ctx, code, count)
+	})
+}
+
+// fetchFundNotices 实际请求，并发的相同调用由 GetFundNotices 合并
+func (api *FundAPI) fetchFundNotices(ctx context.Context, code string, count int) ([]models.FundNotice, error) {
 	if count <= 0 {
 		count = 20
 	}
@@ -1201,7 +1270,7 @@ func (api *FundAPI) GetFundNotices(code string, count int) ([]models.FundNotice,
 			ErrCode int    `json:"ErrCode"`
 		}
 
-		if err := api.doFundRequest("FundMNNoticeList", params, &resp); err != nil {
+		if err := api.doFundRequest(ctx, "FundMNNoticeList", params, &resp); err != nil {
 			lastErr = err
 		} else if resp.Success {
 			notices := make([]models.FundNotice, 0, len(resp.Datas))
@@ -1231,7 +1300,7 @@ func (api *FundAPI) GetFundNotices(code string, count int) ([]models.FundNotice,
 		}
 	}
 
-	if notices, err := api.fetchFundNoticesFromAPI(code, count); err == nil && len(notices) > 0 {
+	if notices, err := api.fetchFundNoticesFromAPI(ctx, code, count); err == nil && len(notices) > 0 {
 		return notices, nil
 	} else if err != nil && lastErr == nil {
 		lastErr = err
@@ -1243,8 +1312,8 @@ func (api *FundAPI) GetFundNotices(code string, count int) ([]models.FundNotice,
 	return nil, lastErr
 }
 
-func (api *FundAPI) fetchJSONWithReferer(reqURL string, target interface{}) error {
-	req, err := http.NewRequest("GET", reqURL, nil)
+func (api *FundAPI) fetchJSONWithReferer(ctx context.Context, reqURL string, target interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
 	if err != nil {
 		return err
 	}
@@ -1264,7 +1333,7 @@ func (api *FundAPI) fetchJSONWithReferer(reqURL string, target interface{}) erro
 	return json.Unmarshal(body, target)
 }
 
-func (api *FundAPI) fetchFundHistoryFromAPI(code string, count int) ([]models.FundPerformancePoint, error) {
+func (api *FundAPI) fetchFundHistoryFromAPI(ctx context.Context, code string, count int) ([]models.FundPerformancePoint, error) {
 	if count <= 0 {
 		count = 60
 	}
@@ -1282,7 +1351,7 @@ func (api *FundAPI) fetchFundHistoryFromAPI(code string, count int) ([]models.Fu
 		} `json:"Data"`
 	}
 
-	if err := api.fetchJSONWithReferer(reqURL, &resp); err != nil {
+	if err := api.fetchJSONWithReferer(ctx, reqURL, &resp); err != nil {
 		return nil, err
 	}
 	if resp.ErrCode != 0 {
@@ -1304,7 +1373,7 @@ func (api *FundAPI) fetchFundHistoryFromAPI(code string, count int) ([]models.Fu
 	return history, nil
 }
 
-func (api *FundAPI) fetchFundNoticesFromAPI(code string, count int) ([]models.FundNotice, error) {
+func (api *FundAPI) fetchFundNoticesFromAPI(ctx context.Context, code string, count int) ([]models.FundNotice, error) {
 	if count <= 0 {
 		count = 20
 	}
@@ -1320,7 +1389,7 @@ func (api *FundAPI) fetchFundNoticesFromAPI(code string, count int) ([]models.Fu
 		} `json:"Data"`
 	}
 
-	if err := api.fetchJSONWithReferer(reqURL, &resp); err != nil {
+	if err := api.fetchJSONWithReferer(ctx, reqURL, &resp); err != nil {
 		return nil, err
 	}
 	if resp.ErrCode != 0 {
@@ -1345,10 +1414,10 @@ func (api *FundAPI) fetchFundNoticesFromAPI(code string, count int) ([]models.Fu
 }
 
 // SearchFund 搜索基金（东方财富接口）
-func (api *FundAPI) SearchFund(keyword string) ([]models.Fund, error) {
+func (api *FundAPI) SearchFund(ctx context.Context, keyword string) ([]models.Fund, error) {
 	url := fmt.Sprintf("https://fundsuggest.eastmoney.com/FundSearch/api/FundSearchAPI.ashx?m=1&key=%s", keyword)
 
-	resp, err := api.client.Get(url)
+	resp, err := getWithContext(ctx, api.client, url)
 	if err != nil {
 		return nil, err
 	}
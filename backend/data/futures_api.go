@@ -1,6 +1,7 @@
 package data
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -95,7 +96,14 @@ func (api *FuturesAPI) GetFuturesProducts() []models.FuturesProduct {
 }
 
 // GetFuturesPrice 获取期货实时行情（新浪接口）
-func (api *FuturesAPI) GetFuturesPrice(codes []string) (map[string]*models.FuturesPrice, error) {
+func (api *FuturesAPI) GetFuturesPrice(ctx context.Context, codes []string) (map[string]*models.FuturesPrice, error) {
+	return coalesce(ctx, "futures.price", []interface{}{codesParam(codes)}, func(ctx context.Context) (map[string]*models.FuturesPrice, error) {
+		return api.fetchFuturesPrice(ctx, codes)
+	})
+}
+
+// fetchFuturesPrice 实际请求，并发的相同调用由 GetFuturesPrice 合并
+func (api *FuturesAPI) fetchFuturesPrice(ctx context.Context, codes []string) (map[string]*models.FuturesPrice, error) {
 	result := make(map[string]*models.FuturesPrice)
 
 	// 构建新浪期货代码
@@ -114,7 +122,7 @@ func (api *FuturesAPI) GetFuturesPrice(codes []string) (map[string]*models.Futur
 	// 新浪期货行情接口
 	url := fmt.Sprintf("https://hq.sinajs.cn/list=%s", strings.Join(sinaCodeList, ","))
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -151,21 +159,22 @@ func (api *FuturesAPI) toSinaFuturesCode(code string) string {
 	code = strings.ToUpper(code)
 	// 新浪期货代码格式：品种代码+月份，如 AU2406 -> AU2406
 	// 需要添加交易所前缀
-	for _, product := range mainFuturesProducts {
-		if strings.HasPrefix(code, product.Code) {
-			switch product.Exchange {
-			case "SHFE":
-				return "nf_" + code // 上期所
-			case "DCE":
-				return "nf_" + code // 大商所
-			case "CZCE":
-				return "nf_" + code // 郑商所
-			case "CFFEX":
-				return "CFF_" + code // 中金所
-			case "INE":
-				return "nf_" + code // 能源中心
-			}
-		}
+	// 按完整品种代码匹配，避免 IF2412 被当作铁矿石 I
+	product := findFuturesProduct(code)
+	if product == nil {
+		return ""
+	}
+	switch product.Exchange {
+	case "SHFE":
+		return "nf_" + code // 上期所
+	case "DCE":
+		return "nf_" + code // 大商所
+	case "CZCE":
+		return "nf_" + code // 郑商所
+	case "CFFEX":
+		return "CFF_" + code // 中金所
+	case "INE":
+		return "nf_" + code // 能源中心
 	}
 	return ""
 }
@@ -204,6 +213,7 @@ func (api *FuturesAPI) parseSinaFuturesLine(line string) *models.FuturesPrice {
 		Volume:     parseInt64(parts[14]),
 		UpdateTime: time.Now().Format("15:04:05"),
 	}
+	price.OpenInterest = parseInt64(parts[13])
 
 	// 计算涨跌
 	if price.PreSettle > 0 {
@@ -212,11 +222,8 @@ func (api *FuturesAPI) parseSinaFuturesLine(line string) *models.FuturesPrice {
 	}
 
 	// 获取交易所
-	for _, product := range mainFuturesProducts {
-		if strings.HasPrefix(code, product.Code) {
-			price.Exchange = product.Exchange
-			break
-		}
+	if product := findFuturesProduct(code); product != nil {
+		price.Exchange = product.Exchange
 	}
 
 	return price
@@ -226,11 +233,18 @@ func (api *FuturesAPI) parseSinaFuturesLine(line string) *models.FuturesPrice {
 var futuresSources = []string{"eastmoney", "sina", "tencent", "hexun", "netease", "baidu", "xueqiu"}
 
 // GetMainContracts 获取主力合约列表（循环轮询多个数据源）
-func (api *FuturesAPI) GetMainContracts() ([]models.FuturesPrice, error) {
+func (api *FuturesAPI) GetMainContracts(ctx context.Context) ([]models.FuturesPrice, error) {
+	return coalesce(ctx, "futures.main", nil, func(ctx context.Context) ([]models.FuturesPrice, error) {
+		return api.fetchMainContracts(ctx)
+	})
+}
+
+// fetchMainContracts 实际请求，并发的相同调用由 GetMainContracts 合并
+func (api *FuturesAPI) fetchMainContracts(ctx context.Context) ([]models.FuturesPrice, error) {
 	// 缓存检查
-	cacheKey := "futures_main_contracts"
-	if cached, ok := api.rm.GetCache(cacheKey); ok {
-		return cached.([]models.FuturesPrice), nil
+	cacheKey := "main"
+	if cached, ok := futuresContractCache.Get(cacheKey); ok {
+		return cached, nil
 	}
 
 	// 获取当前数据源索引
@@ -250,23 +264,23 @@ func (api *FuturesAPI) GetMainContracts() ([]models.FuturesPrice, error) {
 
 		switch source {
 		case "eastmoney":
-			result, err = api.getMainContractsFromEastMoney()
+			result, err = api.getMainContractsFromEastMoney(ctx)
 		case "sina":
-			result, err = api.getMainContractsFromSina()
+			result, err = api.getMainContractsFromSina(ctx)
 		case "tencent":
-			result, err = api.getMainContractsFromTencent()
+			result, err = api.getMainContractsFromTencent(ctx)
 		case "hexun":
-			result, err = api.getMainContractsFromHexun()
+			result, err = api.getMainContractsFromHexun(ctx)
 		case "netease":
-			result, err = api.getMainContractsFromNetease()
+			result, err = api.getMainContractsFromNetease(ctx)
 		case "baidu":
-			result, err = api.getMainContractsFromBaidu()
+			result, err = api.getMainContractsFromBaidu(ctx)
 		case "xueqiu":
-			result, err = api.getMainContractsFromXueqiu()
+			result, err = api.getMainContractsFromXueqiu(ctx)
 		}
 
 		if err == nil && len(result) > 0 {
-			api.rm.SetCache(cacheKey, result, 30*time.Second)
+			futuresContractCache.Set(cacheKey, result)
 			return result, nil
 		}
 		lastErr = err
@@ -276,11 +290,11 @@ func (api *FuturesAPI) GetMainContracts() ([]models.FuturesPrice, error) {
 }
 
 // getMainContractsFromEastMoney 从东方财富获取主力合约
-func (api *FuturesAPI) getMainContractsFromEastMoney() ([]models.FuturesPrice, error) {
+func (api *FuturesAPI) getMainContractsFromEastMoney(ctx context.Context) ([]models.FuturesPrice, error) {
 	// 使用东方财富期货列表接口 m:113(上期所) m:114(大商所) m:115(郑商所)
 	url := "https://push2.eastmoney.com/api/qt/clist/get?pn=1&pz=100&po=1&np=1&fltt=2&invt=2&fid=f3&fs=m:113,m:114,m:115&fields=f1,f2,f3,f4,f5,f6,f7,f12,f13,f14,f15,f16,f17,f18"
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -364,7 +378,7 @@ func (api *FuturesAPI) getMainContractsFromEastMoney() ([]models.FuturesPrice, e
 }
 
 // getMainContractsFromSina 从新浪获取主力合约（备用）
-func (api *FuturesAPI) getMainContractsFromSina() ([]models.FuturesPrice, error) {
+func (api *FuturesAPI) getMainContractsFromSina(ctx context.Context) ([]models.FuturesPrice, error) {
 	// 构建主力合约代码列表
 	var codes []string
 	for _, product := range mainFuturesProducts {
@@ -373,7 +387,7 @@ func (api *FuturesAPI) getMainContractsFromSina() ([]models.FuturesPrice, error)
 	}
 
 	// 获取价格
-	prices, err := api.GetFuturesPrice(codes)
+	prices, err := api.GetFuturesPrice(ctx, codes)
 	if err != nil {
 		return nil, err
 	}
@@ -389,7 +403,7 @@ func (api *FuturesAPI) getMainContractsFromSina() ([]models.FuturesPrice, error)
 }
 
 // getMainContractsFromTencent 从腾讯获取主力合约
-func (api *FuturesAPI) getMainContractsFromTencent() ([]models.FuturesPrice, error) {
+func (api *FuturesAPI) getMainContractsFromTencent(ctx context.Context) ([]models.FuturesPrice, error) {
 	// 腾讯期货代码映射（主力合约）
 	tencentCodes := map[string]string{
 		"AU0": "nf_AU0",  // 黄金
@@ -428,7 +442,7 @@ func (api *FuturesAPI) getMainContractsFromTencent() ([]models.FuturesPrice, err
 
 	url := fmt.Sprintf("https://qt.gtimg.cn/q=%s", strings.Join(codeList, ","))
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -509,11 +523,11 @@ func (api *FuturesAPI) getMainContractsFromTencent() ([]models.FuturesPrice, err
 }
 
 // getMainContractsFromHexun 从和讯获取主力合约
-func (api *FuturesAPI) getMainContractsFromHexun() ([]models.FuturesPrice, error) {
+func (api *FuturesAPI) getMainContractsFromHexun(ctx context.Context) ([]models.FuturesPrice, error) {
 	// 和讯期货接口
 	url := "https://api.hexun.com/futures/quotelist?type=main"
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -582,7 +596,7 @@ func (api *FuturesAPI) getMainContractsFromHexun() ([]models.FuturesPrice, error
 }
 
 // getMainContractsFromNetease 从网易获取主力合约
-func (api *FuturesAPI) getMainContractsFromNetease() ([]models.FuturesPrice, error) {
+func (api *FuturesAPI) getMainContractsFromNetease(ctx context.Context) ([]models.FuturesPrice, error) {
 	// 网易期货接口 - 获取主力合约
 	// 构建期货代码列表
 	var codes []string
@@ -592,7 +606,7 @@ func (api *FuturesAPI) getMainContractsFromNetease() ([]models.FuturesPrice, err
 
 	url := fmt.Sprintf("https://api.money.126.net/data/feed/%s?callback=cb", strings.Join(codes, ","))
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -671,11 +685,11 @@ func (api *FuturesAPI) getMainContractsFromNetease() ([]models.FuturesPrice, err
 }
 
 // getMainContractsFromBaidu 从百度获取主力合约
-func (api *FuturesAPI) getMainContractsFromBaidu() ([]models.FuturesPrice, error) {
+func (api *FuturesAPI) getMainContractsFromBaidu(ctx context.Context) ([]models.FuturesPrice, error) {
 	// 百度股市通期货接口
 	url := "https://gushitong.baidu.com/opendata?resource_id=5352&query=期货&type=futures&market=futures"
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -746,7 +760,7 @@ func (api *FuturesAPI) getMainContractsFromBaidu() ([]models.FuturesPrice, error
 }
 
 // getMainContractsFromXueqiu 从雪球获取主力合约
-func (api *FuturesAPI) getMainContractsFromXueqiu() ([]models.FuturesPrice, error) {
+func (api *FuturesAPI) getMainContractsFromXueqiu(ctx context.Context) ([]models.FuturesPrice, error) {
 	// 雪球期货接口 - 构建主力合约代码
 	var symbols []string
 	for _, product := range mainFuturesProducts {
@@ -756,7 +770,7 @@ func (api *FuturesAPI) getMainContractsFromXueqiu() ([]models.FuturesPrice, erro
 
 	url := fmt.Sprintf("https://stock.xueqiu.com/v5/stock/batch/quote.json?symbol=%s", strings.Join(symbols, ","))
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
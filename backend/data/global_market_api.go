@@ -1,9 +1,11 @@
 package data
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
 	"regexp"
 	"strings"
@@ -137,7 +139,7 @@ func (api *GlobalMarketAPI) GetGlobalIndicesList() []models.GlobalIndex {
 }
 
 // GetUSStockPrice 获取美股实时行情（新浪接口）
-func (api *GlobalMarketAPI) GetUSStockPrice(symbols []string) (map[string]*models.USStockPrice, error) {
+func (api *GlobalMarketAPI) GetUSStockPrice(ctx context.Context, symbols []string) (map[string]*models.USStockPrice, error) {
 	result := make(map[string]*models.USStockPrice)
 
 	if len(symbols) == 0 {
@@ -152,7 +154,7 @@ func (api *GlobalMarketAPI) GetUSStockPrice(symbols []string) (map[string]*model
 
 	url := fmt.Sprintf("https://hq.sinajs.cn/list=%s", strings.Join(sinaCodeList, ","))
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -234,7 +236,7 @@ func (api *GlobalMarketAPI) parseSinaUSStockLine(line string) *models.USStockPri
 }
 
 // GetHKStockPrice 获取港股实时行情（新浪接口）
-func (api *GlobalMarketAPI) GetHKStockPrice(codes []string) (map[string]*models.HKStockPrice, error) {
+func (api *GlobalMarketAPI) GetHKStockPrice(ctx context.Context, codes []string) (map[string]*models.HKStockPrice, error) {
 	result := make(map[string]*models.HKStockPrice)
 
 	if len(codes) == 0 {
@@ -249,7 +251,7 @@ func (api *GlobalMarketAPI) GetHKStockPrice(codes []string) (map[string]*models.
 
 	url := fmt.Sprintf("https://hq.sinajs.cn/list=%s", strings.Join(sinaCodeList, ","))
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -320,51 +322,53 @@ func (api *GlobalMarketAPI) parseSinaHKStockLine(line string) *models.HKStockPri
 }
 
 // GetGlobalIndices 获取全球指数行情（多数据源轮询）
-func (api *GlobalMarketAPI) GetGlobalIndices() ([]models.GlobalIndex, error) {
+func (api *GlobalMarketAPI) GetGlobalIndices(ctx context.Context) *models.GlobalIndexResult {
 	// 缓存检查
-	cacheKey := "global_indices"
-	if cached, ok := api.rm.GetCache(cacheKey); ok {
-		return cached.([]models.GlobalIndex), nil
+	if cached, ok := globalIndexCache.Get(globalIndexCacheKey); ok {
+		result := *cached
+		result.Meta.Freshness = models.FreshnessCached
+		return &result
 	}
 
 	// 使用多数据源管理器获取数据
 	msm := GetMultiSourceManager()
-	indexData, _, err := msm.GetGlobalIndicesWithFallback()
+	indexData, source, err := msm.GetGlobalIndicesWithFallback(ctx)
+	if err == nil && len(indexData) == 0 {
+		err = fmt.Errorf("全球指数数据为空")
+	}
+	if err != nil {
+		log.Printf("[全球指数] 实时获取失败: %v", err)
+		if cached, ok := loadLastGoodData(); ok && len(cached.GlobalIndicesList) > 0 {
+			return &models.GlobalIndexResult{Items: cached.GlobalIndicesList, Meta: newDataMeta(sourceLocalCache, models.FreshnessStale, cached.CacheTime, err)}
+		}
+		return &models.GlobalIndexResult{Items: api.getDefaultIndices(), Meta: newDataMeta(sourcePlaceholder, models.FreshnessPlaceholder, time.Time{}, err)}
+	}
 
 	// 创建结果副本
-	result := make([]models.GlobalIndex, len(globalIndices))
-	copy(result, globalIndices)
-
-	if err != nil || len(indexData) == 0 {
-		// 所有数据源都失败，返回默认数据
-		return api.getDefaultIndices(), nil
-	}
+	items := make([]models.GlobalIndex, len(globalIndices))
+	copy(items, globalIndices)
 
 	// 更新指数数据
-	now := time.Now().Format("15:04:05")
-	for i := range result {
-		if data, ok := indexData[result[i].Code]; ok {
-			result[i].Price = data.Price
-			result[i].Change = data.Change
-			result[i].ChangePercent = data.ChangePercent
-			result[i].UpdateTime = now
+	now := time.Now()
+	for i := range items {
+		if data, ok := indexData[items[i].Code]; ok {
+			items[i].Price = data.Price
+			items[i].Change = data.Change
+			items[i].ChangePercent = data.ChangePercent
+			items[i].UpdateTime = now.Format("15:04:05")
 		}
 	}
 
+	result := &models.GlobalIndexResult{Items: items, Meta: newDataMeta(sourceProviderName(source), models.FreshnessLive, now, nil)}
 	// 缓存60秒
-	api.rm.SetCache(cacheKey, result, 60*time.Second)
-
-	return result, nil
+	globalIndexCache.Set(globalIndexCacheKey, result)
+	return result
 }
 
-// getDefaultIndices 返回默认指数数据（当API失败时）
+// getDefaultIndices 返回默认指数数据（当API失败时），不设置更新时间以免被当作实时行情
 func (api *GlobalMarketAPI) getDefaultIndices() []models.GlobalIndex {
 	result := make([]models.GlobalIndex, len(globalIndices))
 	copy(result, globalIndices)
-	now := time.Now().Format("15:04:05")
-	for i := range result {
-		result[i].UpdateTime = now
-	}
 	return result
 }
 
@@ -401,11 +405,11 @@ func (api *GlobalMarketAPI) SearchHKStock(keyword string) ([]models.HKStock, err
 }
 
 // GetUSStockPriceFromEastmoney 从东方财富获取美股行情（备用接口）
-func (api *GlobalMarketAPI) GetUSStockPriceFromEastmoney(symbols []string) (map[string]*models.USStockPrice, error) {
+func (api *GlobalMarketAPI) GetUSStockPriceFromEastmoney(ctx context.Context, symbols []string) (map[string]*models.USStockPrice, error) {
 	result := make(map[string]*models.USStockPrice)
 
 	for _, symbol := range symbols {
-		price, err := api.getUSStockFromEastmoney(symbol)
+		price, err := api.getUSStockFromEastmoney(ctx, symbol)
 		if err != nil {
 			continue
 		}
@@ -416,10 +420,10 @@ func (api *GlobalMarketAPI) GetUSStockPriceFromEastmoney(symbols []string) (map[
 }
 
 // getUSStockFromEastmoney 从东方财富获取单只美股行情
-func (api *GlobalMarketAPI) getUSStockFromEastmoney(symbol string) (*models.USStockPrice, error) {
+func (api *GlobalMarketAPI) getUSStockFromEastmoney(ctx context.Context, symbol string) (*models.USStockPrice, error) {
 	url := fmt.Sprintf("https://push2.eastmoney.com/api/qt/stock/get?secid=105.%s&fields=f43,f44,f45,f46,f47,f48,f57,f58,f60,f169,f170", symbol)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -472,35 +476,53 @@ func (api *GlobalMarketAPI) getUSStockFromEastmoney(symbol string) (*models.USSt
 	}, nil
 }
 
-// GetGlobalNews 获取国际财经新闻（按国家/地区）
-func (api *GlobalMarketAPI) GetGlobalNews(country string) ([]models.NewsItem, error) {
+// GetGlobalNews 获取国际财经新闻（按国家/地区），附带数据来源信息
+func (api *GlobalMarketAPI) GetGlobalNews(ctx context.Context, country string) *models.NewsListResult {
 	// 缓存检查
-	cacheKey := "global_news_" + country
-	if cached, ok := api.rm.GetCache(cacheKey); ok {
-		return cached.([]models.NewsItem), nil
+	if cached, ok := globalNewsCache.Get(country); ok {
+		result := *cached
+		result.Meta.Freshness = models.FreshnessCached
+		return &result
+	}
+
+	news, err := api.fetchGlobalNews(ctx, country)
+	if err == nil {
+		result := &models.NewsListResult{Items: news, Meta: newDataMeta("eastmoney", models.FreshnessLive, time.Now(), nil)}
+		// 缓存5分钟
+		globalNewsCache.Set(country, result)
+		return result
 	}
 
+	log.Printf("[国际新闻] %s 实时获取失败: %v", country, err)
+	if cached, ok := loadLastGoodData(); ok && len(cached.GlobalNews[country]) > 0 {
+		return &models.NewsListResult{Items: cached.GlobalNews[country], Meta: newDataMeta(sourceLocalCache, models.FreshnessStale, cached.CacheTime, err)}
+	}
+	return &models.NewsListResult{Items: api.getDefaultGlobalNews(country), Meta: newDataMeta(sourcePlaceholder, models.FreshnessPlaceholder, time.Time{}, err)}
+}
+
+// fetchGlobalNews 实时获取国际财经新闻
+func (api *GlobalMarketAPI) fetchGlobalNews(ctx context.Context, country string) ([]models.NewsItem, error) {
 	// 根据国家获取对应的新闻分类
 	columnID := api.getNewsColumnByCountry(country)
 
 	url := fmt.Sprintf("https://np-listapi.eastmoney.com/comm/web/getNewsByColumns?client=web&biz=web_news_col&column=%s&order=1&needInteractData=0&page_index=1&page_size=20", columnID)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		return api.getDefaultGlobalNews(country), nil
+		return nil, err
 	}
 	req.Header.Set("Referer", "https://www.eastmoney.com/")
 	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
 
 	resp, err := api.rm.DoRequestWithRateLimit("eastmoney.com", req)
 	if err != nil {
-		return api.getDefaultGlobalNews(country), nil
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return api.getDefaultGlobalNews(country), nil
+		return nil, err
 	}
 
 	var result struct {
@@ -516,7 +538,7 @@ func (api *GlobalMarketAPI) GetGlobalNews(country string) ([]models.NewsItem, er
 	}
 
 	if err := json.Unmarshal(body, &result); err != nil {
-		return api.getDefaultGlobalNews(country), nil
+		return nil, err
 	}
 
 	var news []models.NewsItem
@@ -540,16 +562,14 @@ func (api *GlobalMarketAPI) GetGlobalNews(country string) ([]models.NewsItem, er
 			Time:       item.ShowTime,
 			Source:     item.MediaName,
 			Importance: importance,
+			Url:        eastmoneyArticleURL(item.Code),
 		})
 	}
 
 	if len(news) == 0 {
-		return api.getDefaultGlobalNews(country), nil
+		return nil, fmt.Errorf("国际新闻数据为空")
 	}
 
-	// 缓存5分钟
-	api.rm.SetCache(cacheKey, news, 5*time.Minute)
-
 	return news, nil
 }
 
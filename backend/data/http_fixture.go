@@ -0,0 +1,139 @@
+package data
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ==================== HTTP录制/回放 ====================
+
+// FixtureMode 录制/回放模式
+type FixtureMode int
+
+const (
+	FixtureReplay FixtureMode = iota // 只读取已录制的响应，不访问网络
+	FixtureRecord                    // 请求真实接口并把响应写入 Dir
+)
+
+// 每次请求都会变化的查询参数（时间戳、随机数），不参与录制文件的命名
+var fixtureVolatileParams = []string{"_", "rt", "r", "ts", "random"}
+
+var fixtureUnsafeChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// FixtureTransport 录制/回放HTTP响应的 RoundTripper，用于离线测试各数据源的解析与降级逻辑
+// 录制文件按 域名/路径-请求摘要.http 存放，内容为原始HTTP响应报文（保留GBK等原始编码）
+type FixtureTransport struct {
+	Dir  string
+	Mode FixtureMode
+	Base http.RoundTripper // 录制时使用的真实传输层，为nil时使用默认代理传输层
+}
+
+// NewFixtureTransport 创建录制/回放传输层
+func NewFixtureTransport(dir string, mode FixtureMode) *FixtureTransport {
+	return &FixtureTransport{Dir: dir, Mode: mode}
+}
+
+// RoundTrip 实现 http.RoundTripper
+func (t *FixtureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	name, err := FixtureName(req)
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(t.Dir, name)
+	if t.Mode == FixtureRecord {
+		return t.record(req, path)
+	}
+	return t.replay(req, path)
+}
+
+// replay 从录制文件构造响应
+func (t *FixtureTransport) replay(req *http.Request, path string) (*http.Response, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("未找到录制响应 %s (%s %s): %w", path, req.Method, req.URL, err)
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(raw)), req)
+	if err != nil {
+		return nil, fmt.Errorf("录制响应 %s 格式错误: %w", path, err)
+	}
+	return resp, nil
+}
+
+// record 请求真实接口并保存响应，Set-Cookie 等会话信息不落盘
+func (t *FixtureTransport) record(req *http.Request, path string) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = newProxyTransport()
+	}
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	saved := *resp
+	saved.Header = resp.Header.Clone()
+	saved.Header.Del("Set-Cookie")
+	saved.Body = io.NopCloser(bytes.NewReader(body))
+	saved.ContentLength = int64(len(body))
+	saved.TransferEncoding = nil
+	dump, err := httputil.DumpResponse(&saved, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, dump, 0644); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// FixtureName 计算请求对应的录制文件相对路径
+// 摘要覆盖方法、地址（去掉时间戳类参数，参数按名称排序）与请求体，同一请求在录制与回放时命中同一文件
+func FixtureName(req *http.Request) (string, error) {
+	query := req.URL.Query()
+	for _, key := range fixtureVolatileParams {
+		query.Del(key)
+	}
+
+	h := sha1.New()
+	fmt.Fprintf(h, "%s %s%s?%s\n", req.Method, req.URL.Host, req.URL.Path, query.Encode())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return "", err
+		}
+		defer body.Close()
+		if _, err := io.Copy(h, body); err != nil {
+			return "", err
+		}
+	}
+
+	name := strings.Trim(fixtureUnsafeChars.ReplaceAllString(req.URL.Path, "_"), "_.")
+	if name == "" {
+		name = "index"
+	}
+	if len(name) > 48 {
+		name = name[:48]
+	}
+	host := fixtureUnsafeChars.ReplaceAllString(req.URL.Hostname(), "_")
+	return filepath.Join(host, fmt.Sprintf("%s-%x.http", name, h.Sum(nil)[:4])), nil
+}
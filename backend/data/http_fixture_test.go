@@ -0,0 +1,130 @@
+package data
+
+import (
+	"context"
+	"flag"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// go test ./backend/data -run <用例> -record 会请求真实接口并覆盖 testdata/fixtures 下的录制响应，
+// 行情数值随之变化，录制后需按新数据更新用例中的期望值
+var recordFixtures = flag.Bool("record", false, "请求真实接口并录制响应到 testdata/fixtures")
+
+// 正常响应与上游故障（限流、空数据、字段缺失）分目录存放，同一地址在两种场景下返回不同内容
+var (
+	fixtureDir       = filepath.Join("testdata", "fixtures")
+	outageFixtureDir = filepath.Join("testdata", "fixtures_outage")
+)
+
+func newFixtureTransport(dir string) *FixtureTransport {
+	mode := FixtureReplay
+	if *recordFixtures && dir == fixtureDir {
+		mode = FixtureRecord
+	}
+	return NewFixtureTransport(dir, mode)
+}
+
+// newFixtureRequestManager 创建使用录制响应的请求管理器
+func newFixtureRequestManager(dir string) *RequestManager {
+	rm := NewRequestManager()
+	rm.SetTransport(newFixtureTransport(dir))
+	return rm
+}
+
+// newFixtureMultiSource 创建使用录制响应的多数据源管理器（不注册任何行情数据源，结构校验状态不与其它用例共享）
+func newFixtureMultiSource(dir string) *MultiSourceManager {
+	msm := NewMultiSourceManager()
+	msm.rm = newFixtureRequestManager(dir)
+	msm.schema = NewSchemaMonitor("")
+	return msm
+}
+
+func TestFixtureName(t *testing.T) {
+	name := func(method, rawURL, body string) string {
+		t.Helper()
+		var r io.Reader
+		if body != "" {
+			r = strings.NewReader(body)
+		}
+		req, err := http.NewRequest(method, rawURL, r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		n, err := FixtureName(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+
+	base := name("GET", "https://push2.eastmoney.com/api/qt/ulist.np/get?secids=1.600519&fields=f2,f3&_=1700000000000", "")
+	if !strings.HasPrefix(base, filepath.Join("push2.eastmoney.com", "api_qt_ulist.np_get-")) || !strings.HasSuffix(base, ".http") {
+		t.Fatalf("name = %s", base)
+	}
+	cases := []struct {
+		name string
+		got  string
+		same bool
+	}{
+		{"时间戳参数不参与命名", name("GET", "https://push2.eastmoney.com/api/qt/ulist.np/get?_=1800000000000&fields=f2,f3&secids=1.600519", ""), true},
+		{"代码不同", name("GET", "https://push2.eastmoney.com/api/qt/ulist.np/get?secids=0.000001&fields=f2,f3&_=1", ""), false},
+		{"方法不同", name("POST", "https://push2.eastmoney.com/api/qt/ulist.np/get?secids=1.600519&fields=f2,f3", ""), false},
+		{"请求体不同", name("POST", "https://push2.eastmoney.com/api/qt/ulist.np/get?secids=1.600519&fields=f2,f3", "a=1"), false},
+	}
+	for _, tc := range cases {
+		if (tc.got == base) != tc.same {
+			t.Errorf("%s: %s vs %s", tc.name, tc.got, base)
+		}
+	}
+}
+
+func TestFixtureTransportRecordReplay(t *testing.T) {
+	hits := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "secret"})
+		w.Header().Set("Content-Type", "application/javascript; charset=GBK")
+		io.WriteString(w, `var hq_str_sh600000="浦发银行";`)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	get := func(rt http.RoundTripper) (*http.Response, string) {
+		t.Helper()
+		req, _ := http.NewRequestWithContext(context.Background(), "GET", server.URL+"/list=sh600000?_=1", nil)
+		resp, err := rt.RoundTrip(req)
+		if err != nil {
+			t.Fatalf("RoundTrip: %v", err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp, string(body)
+	}
+
+	recorder := NewFixtureTransport(dir, FixtureRecord)
+	recorder.Base = http.DefaultTransport
+	if _, body := get(recorder); !strings.Contains(body, "浦发银行") {
+		t.Fatalf("录制时应透传响应, got %q", body)
+	}
+
+	resp, body := get(NewFixtureTransport(dir, FixtureReplay))
+	if hits != 1 {
+		t.Fatalf("回放不应访问网络, hits = %d", hits)
+	}
+	if body != `var hq_str_sh600000="浦发银行";` || resp.Header.Get("Content-Type") != "application/javascript; charset=GBK" {
+		t.Fatalf("回放响应 = %q %v", body, resp.Header)
+	}
+	if resp.Header.Get("Set-Cookie") != "" {
+		t.Fatal("录制文件不应保存 Set-Cookie")
+	}
+
+	req, _ := http.NewRequest("GET", server.URL+"/list=sz000001", nil)
+	if _, err := NewFixtureTransport(dir, FixtureReplay).RoundTrip(req); err == nil || !strings.Contains(err.Error(), "未找到录制响应") {
+		t.Fatalf("未录制的请求应返回错误, got %v", err)
+	}
+}
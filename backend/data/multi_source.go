@@ -54,6 +54,12 @@ type MultiSourceManager struct {
 	isFirstLoad bool
 	// 轮询间隔（秒）
 	pollInterval int
+	// 已注册的行情数据源（内置 + 插件）
+	providers map[string]*registeredProvider
+	// 行情校验器
+	validator *QuoteValidator
+	// 解析结果的字段结构校验
+	schema *SchemaMonitor
 }
 
 var globalMultiSource *MultiSourceManager
@@ -63,6 +69,7 @@ var multiSourceOnce sync.Once
 func GetMultiSourceManager() *MultiSourceManager {
 	multiSourceOnce.Do(func() {
 		globalMultiSource = NewMultiSourceManager()
+		registerBuiltinQuoteProviders(globalMultiSource, NewStockAPI())
 	})
 	return globalMultiSource
 }
@@ -82,6 +89,9 @@ func NewMultiSourceManager() *MultiSourceManager {
 		currentIndex: 0,
 		isFirstLoad:  true, // 初始为首次加载模式
 		pollInterval: 10,   // 轮询间隔10秒
+		providers:    make(map[string]*registeredProvider),
+		validator:    NewQuoteValidator(),
+		schema:       GetSchemaMonitor(),
 	}
 	return msm
 }
@@ -206,14 +216,14 @@ func (msm *MultiSourceManager) GetPollInterval() int {
 // ==================== 全球指数多数据源获取 ====================
 
 // FetchGlobalIndicesFromSina 从新浪获取全球指数
-func (msm *MultiSourceManager) FetchGlobalIndicesFromSina() (map[string]*IndexData, error) {
+func (msm *MultiSourceManager) FetchGlobalIndicesFromSina(ctx context.Context) (map[string]*IndexData, error) {
 	result := make(map[string]*IndexData)
 
 	// 新浪全球指数代码 - 包含更多指数
 	codes := "int_dji,int_nasdaq,int_sp500,int_hangseng,int_nikkei,b_FTSE,b_DAX,b_HSI,b_SPX,b_KOSPI,b_TWII,b_STI,b_SENSEX,b_AXJO,b_GSPTSE,b_FCHI"
 	url := fmt.Sprintf("https://hq.sinajs.cn/list=%s", codes)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -246,6 +256,10 @@ func (msm *MultiSourceManager) FetchGlobalIndicesFromSina() (map[string]*IndexDa
 		}
 	}
 
+	if err := msm.schema.Check(globalIndexSchema("sina"), body, indexRecords(result)); err != nil {
+		return nil, err
+	}
+
 	return result, nil
 }
 
@@ -316,14 +330,14 @@ func (msm *MultiSourceManager) parseSinaIndexLine(line string) *IndexData {
 }
 
 // FetchGlobalIndicesFromTencent 从腾讯获取全球指数
-func (msm *MultiSourceManager) FetchGlobalIndicesFromTencent() (map[string]*IndexData, error) {
+func (msm *MultiSourceManager) FetchGlobalIndicesFromTencent(ctx context.Context) (map[string]*IndexData, error) {
 	result := make(map[string]*IndexData)
 
 	// 腾讯全球指数代码 - 扩展更多指数
 	codes := "usDJI,usIXIC,usSPX,hkHSI,jpN225,ukFTSE,deDAX,frCAC,krKOSPI,twTWSE,sgSTI,inSENSEX,auASX,caTSX"
 	url := fmt.Sprintf("https://qt.gtimg.cn/q=%s", codes)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -356,13 +370,17 @@ func (msm *MultiSourceManager) FetchGlobalIndicesFromTencent() (map[string]*Inde
 		}
 	}
 
+	if err := msm.schema.Check(globalIndexSchema("tencent"), body, indexRecords(result)); err != nil {
+		return nil, err
+	}
+
 	return result, nil
 }
 
 // parseTencentIndexLine 解析腾讯指数行
 func (msm *MultiSourceManager) parseTencentIndexLine(line string) *IndexData {
-	// 格式: v_usDJI="200~道琼斯~DJI~46247.29~..."
-	re := regexp.MustCompile(`v_([a-zA-Z]+)="([^"]*)"`)
+	// 格式: v_usDJI="200~道琼斯~DJI~46247.29~..."，代码可能带数字（如 jpN225）
+	re := regexp.MustCompile(`v_([a-zA-Z][a-zA-Z0-9]*)="([^"]*)"`)
 	matches := re.FindStringSubmatch(line)
 	if len(matches) < 3 || matches[2] == "" {
 		return nil
@@ -417,7 +435,7 @@ func (msm *MultiSourceManager) parseTencentIndexLine(line string) *IndexData {
 }
 
 // FetchGlobalIndicesFromEastmoney 从东方财富获取全球指数
-func (msm *MultiSourceManager) FetchGlobalIndicesFromEastmoney() (map[string]*IndexData, error) {
+func (msm *MultiSourceManager) FetchGlobalIndicesFromEastmoney(ctx context.Context) (map[string]*IndexData, error) {
 	result := make(map[string]*IndexData)
 
 	// 东方财富代码映射 - 返回的代码 -> 我们的代码
@@ -445,11 +463,12 @@ func (msm *MultiSourceManager) FetchGlobalIndicesFromEastmoney() (map[string]*In
 	for emCode := range indexCodeMap {
 		codeList = append(codeList, "i:100."+emCode)
 	}
+	sort.Strings(codeList) // 固定参数顺序，便于请求合并与录制回放
 
 	url := fmt.Sprintf("https://push2.eastmoney.com/api/qt/clist/get?pn=1&pz=50&fs=%s&fields=f2,f3,f4,f12,f14&_=%d",
 		strings.Join(codeList, ","), time.Now().UnixMilli())
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -502,36 +521,51 @@ func (msm *MultiSourceManager) FetchGlobalIndicesFromEastmoney() (map[string]*In
 		}
 	}
 
+	if err := msm.schema.Check(globalIndexSchema("eastmoney"), body, indexRecords(result)); err != nil {
+		return nil, err
+	}
+
 	return result, nil
 }
 
 // GetGlobalIndicesWithFallback 获取全球指数
 // 首次加载：并行请求所有数据源，取最快返回的
 // 后续轮询：按顺序轮询单个数据源
-func (msm *MultiSourceManager) GetGlobalIndicesWithFallback() (map[string]*IndexData, DataSource, error) {
+func (msm *MultiSourceManager) GetGlobalIndicesWithFallback(ctx context.Context) (map[string]*IndexData, DataSource, error) {
 	// 数据源列表
 	sources := []struct {
 		source DataSource
-		fetch  func() (map[string]*IndexData, error)
+		fetch  func(ctx context.Context) (map[string]*IndexData, error)
 	}{
 		{SourceEastmoney, msm.FetchGlobalIndicesFromEastmoney},
 		{SourceSina, msm.FetchGlobalIndicesFromSina},
 		{SourceTencent, msm.FetchGlobalIndicesFromTencent},
 	}
 
+	// 跳过字段结构异常的数据源；全部异常时仍按原列表请求
+	healthy := sources[:0:0]
+	for _, s := range sources {
+		if !msm.schema.IsBroken(sourceProviderName(s.source), SchemaDatasetIndex) {
+			healthy = append(healthy, s)
+		}
+	}
+	if len(healthy) > 0 {
+		sources = healthy
+	}
+
 	// 首次加载：并行请求所有数据源
 	if msm.IsFirstLoad() {
-		return msm.parallelFetchGlobalIndices(sources)
+		return msm.parallelFetchGlobalIndices(ctx, sources)
 	}
 
 	// 后续轮询：按顺序轮询单个数据源
-	return msm.pollFetchGlobalIndices(sources)
+	return msm.pollFetchGlobalIndices(ctx, sources)
 }
 
 // parallelFetchGlobalIndices 并行获取全球指数（首次加载使用）
-func (msm *MultiSourceManager) parallelFetchGlobalIndices(sources []struct {
+func (msm *MultiSourceManager) parallelFetchGlobalIndices(ctx context.Context, sources []struct {
 	source DataSource
-	fetch  func() (map[string]*IndexData, error)
+	fetch  func(ctx context.Context) (map[string]*IndexData, error)
 }) (map[string]*IndexData, DataSource, error) {
 	type result struct {
 		data   map[string]*IndexData
@@ -543,9 +577,9 @@ func (msm *MultiSourceManager) parallelFetchGlobalIndices(sources []struct {
 
 	// 并行启动所有数据源请求
 	for _, s := range sources {
-		go func(src DataSource, fetch func() (map[string]*IndexData, error)) {
+		go func(src DataSource, fetch func(ctx context.Context) (map[string]*IndexData, error)) {
 			start := time.Now()
-			data, err := fetch()
+			data, err := fetch(ctx)
 			msm.recordProbe(src, start, err)
 			resultChan <- result{data: data, source: src, err: err}
 		}(s.source, s.fetch)
@@ -570,9 +604,9 @@ func (msm *MultiSourceManager) parallelFetchGlobalIndices(sources []struct {
 }
 
 // pollFetchGlobalIndices 轮询获取全球指数（后续刷新使用）
-func (msm *MultiSourceManager) pollFetchGlobalIndices(sources []struct {
+func (msm *MultiSourceManager) pollFetchGlobalIndices(ctx context.Context, sources []struct {
 	source DataSource
-	fetch  func() (map[string]*IndexData, error)
+	fetch  func(ctx context.Context) (map[string]*IndexData, error)
 }) (map[string]*IndexData, DataSource, error) {
 	// 获取下一个数据源
 	nextSource := msm.GetNextSource()
@@ -581,7 +615,7 @@ func (msm *MultiSourceManager) pollFetchGlobalIndices(sources []struct {
 	for _, s := range sources {
 		if s.source == nextSource {
 			start := time.Now()
-			data, err := s.fetch()
+			data, err := s.fetch(ctx)
 			msm.recordProbe(s.source, start, err)
 			if err == nil && len(data) > 0 {
 				msm.MarkSourceSuccess(s.source)
@@ -598,7 +632,7 @@ func (msm *MultiSourceManager) pollFetchGlobalIndices(sources []struct {
 	for _, s := range sources {
 		if s.source != nextSource && msm.IsSourceAvailable(s.source) {
 			start := time.Now()
-			data, err := s.fetch()
+			data, err := s.fetch(ctx)
 			msm.recordProbe(s.source, start, err)
 			if err == nil && len(data) > 0 {
 				msm.MarkSourceSuccess(s.source)
@@ -616,7 +650,7 @@ func (msm *MultiSourceManager) pollFetchGlobalIndices(sources []struct {
 // ==================== A股数据多数据源获取 ====================
 
 // FetchAStockFromSina 从新浪获取A股数据
-func (msm *MultiSourceManager) FetchAStockFromSina(codes []string) (map[string]*models.StockPrice, error) {
+func (msm *MultiSourceManager) FetchAStockFromSina(ctx context.Context, codes []string) (map[string]*models.StockPrice, error) {
 	result := make(map[string]*models.StockPrice)
 
 	if len(codes) == 0 {
@@ -644,7 +678,7 @@ func (msm *MultiSourceManager) FetchAStockFromSina(codes []string) (map[string]*
 
 	url := fmt.Sprintf("https://hq.sinajs.cn/list=%s", strings.Join(sinaCodes, ","))
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -677,6 +711,10 @@ func (msm *MultiSourceManager) FetchAStockFromSina(codes []string) (map[string]*
 		}
 	}
 
+	if err := msm.schema.Check(stockQuoteSchema("sina"), body, stockPriceRecords(result)); err != nil {
+		return nil, err
+	}
+
 	return result, nil
 }
 
@@ -728,7 +766,7 @@ func (msm *MultiSourceManager) parseSinaStockLine(line string) *models.StockPric
 }
 
 // FetchAStockFromTencent 从腾讯获取A股数据
-func (msm *MultiSourceManager) FetchAStockFromTencent(codes []string) (map[string]*models.StockPrice, error) {
+func (msm *MultiSourceManager) FetchAStockFromTencent(ctx context.Context, codes []string) (map[string]*models.StockPrice, error) {
 	result := make(map[string]*models.StockPrice)
 
 	if len(codes) == 0 {
@@ -754,7 +792,7 @@ func (msm *MultiSourceManager) FetchAStockFromTencent(codes []string) (map[strin
 
 	url := fmt.Sprintf("https://qt.gtimg.cn/q=%s", strings.Join(qqCodes, ","))
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -787,6 +825,10 @@ func (msm *MultiSourceManager) FetchAStockFromTencent(codes []string) (map[strin
 		}
 	}
 
+	if err := msm.schema.Check(stockQuoteSchema("tencent"), body, stockPriceRecords(result)); err != nil {
+		return nil, err
+	}
+
 	return result, nil
 }
 
@@ -838,7 +880,7 @@ func (msm *MultiSourceManager) parseTencentStockLine(line string) *models.StockP
 }
 
 // FetchAStockFromEastmoney 从东方财富获取A股数据
-func (msm *MultiSourceManager) FetchAStockFromEastmoney(codes []string) (map[string]*models.StockPrice, error) {
+func (msm *MultiSourceManager) FetchAStockFromEastmoney(ctx context.Context, codes []string) (map[string]*models.StockPrice, error) {
 	result := make(map[string]*models.StockPrice)
 
 	if len(codes) == 0 {
@@ -874,7 +916,7 @@ func (msm *MultiSourceManager) FetchAStockFromEastmoney(codes []string) (map[str
 	url := fmt.Sprintf("https://push2.eastmoney.com/api/qt/ulist.np/get?secids=%s&fields=f2,f3,f4,f5,f6,f12,f14,f15,f16,f17,f18&_=%d",
 		strings.Join(emCodes, ","), time.Now().UnixMilli())
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -951,124 +993,21 @@ func (msm *MultiSourceManager) FetchAStockFromEastmoney(codes []string) (map[str
 		}
 	}
 
-	return result, nil
-}
-
-// GetAStockWithFallback 获取A股数据（全链路并行，谁先返回就用谁的数据）
-func (msm *MultiSourceManager) GetAStockWithFallback(codes []string) (map[string]*models.StockPrice, DataSource, error) {
-	sources := []struct {
-		source DataSource
-		fetch  func([]string) (map[string]*models.StockPrice, error)
-	}{
-		{SourceEastmoney, msm.FetchAStockFromEastmoney},
-		{SourceSina, msm.FetchAStockFromSina},
-		{SourceTencent, msm.FetchAStockFromTencent},
-	}
-
-	return msm.parallelFetchAStock(sources, codes)
-}
-
-// parallelFetchAStock 并行获取A股数据，谁先返回就用谁的数据
-func (msm *MultiSourceManager) parallelFetchAStock(sources []struct {
-	source DataSource
-	fetch  func([]string) (map[string]*models.StockPrice, error)
-}, codes []string) (map[string]*models.StockPrice, DataSource, error) {
-	type result struct {
-		data   map[string]*models.StockPrice
-		source DataSource
-		err    error
-	}
-
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
-	resultChan := make(chan result, len(sources))
-
-	for _, s := range sources {
-		go func(src DataSource, fetch func([]string) (map[string]*models.StockPrice, error)) {
-			start := time.Now()
-			data, err := fetch(codes)
-			msm.recordProbe(src, start, err)
-			select {
-			case resultChan <- result{data: data, source: src, err: err}:
-			case <-ctx.Done():
-			}
-		}(s.source, s.fetch)
-	}
-
-	var firstErr error
-	for i := 0; i < len(sources); i++ {
-		res := <-resultChan
-		if res.err == nil && len(res.data) > 0 {
-			msm.MarkSourceSuccess(res.source)
-			msm.SetFirstLoadComplete()
-			cancel()
-			return res.data, res.source, nil
-		}
-		if res.err != nil {
-			firstErr = res.err
-			msm.MarkSourceFailed(res.source)
-		} else {
-			if firstErr == nil {
-				if info, ok := msm.sources[res.source]; ok {
-					firstErr = fmt.Errorf("%s返回空数据", info.Name)
-				} else {
-					firstErr = fmt.Errorf("数据源(%d)返回空数据", res.source)
-				}
-			}
-			msm.MarkSourceFailed(res.source)
-		}
-	}
-
-	if firstErr == nil {
-		firstErr = fmt.Errorf("所有数据源均返回空数据")
+	if err := msm.schema.Check(stockQuoteSchema("eastmoney"), body, stockPriceRecords(result)); err != nil {
+		return nil, err
 	}
 
-	return nil, SourceEastmoney, firstErr
+	return result, nil
 }
 
-// pollFetchAStock 轮询获取A股数据（后续刷新使用）
-func (msm *MultiSourceManager) pollFetchAStock(sources []struct {
-	source DataSource
-	fetch  func([]string) (map[string]*models.StockPrice, error)
-}, codes []string) (map[string]*models.StockPrice, DataSource, error) {
-	// 获取下一个数据源
-	nextSource := msm.GetNextSource()
-
-	// 找到对应的fetch函数
-	for _, s := range sources {
-		if s.source == nextSource {
-			start := time.Now()
-			data, err := s.fetch(codes)
-			msm.recordProbe(s.source, start, err)
-			if err == nil && len(data) > 0 {
-				msm.MarkSourceSuccess(s.source)
-				return data, s.source, nil
-			}
-			if err != nil {
-				msm.MarkSourceFailed(s.source)
-			}
-			break
-		}
-	}
-
-	// 当前数据源失败，尝试下一个
-	for _, s := range sources {
-		if s.source != nextSource && msm.IsSourceAvailable(s.source) {
-			start := time.Now()
-			data, err := s.fetch(codes)
-			msm.recordProbe(s.source, start, err)
-			if err == nil && len(data) > 0 {
-				msm.MarkSourceSuccess(s.source)
-				return data, s.source, nil
-			}
-			if err != nil {
-				msm.MarkSourceFailed(s.source)
-			}
-		}
+// GetAStockWithFallback 获取A股数据（由行情数据源调度器按健康分对冲请求）
+func (msm *MultiSourceManager) GetAStockWithFallback(ctx context.Context, codes []string) (map[string]*models.StockPrice, DataSource, error) {
+	data, name, err := msm.FetchQuotes(ctx, codes)
+	source, ok := builtinProviderSources[name]
+	if !ok {
+		source = SourceEastmoney
 	}
-
-	return nil, SourceEastmoney, fmt.Errorf("all data sources failed")
+	return data, source, err
 }
 
 // GetStatusList 获取详细状态列表
@@ -1089,6 +1028,32 @@ func (msm *MultiSourceManager) GetStatusList() []models.DataSourceStatus {
 			Status:       deriveSourceStatus(info),
 			FailCount:    info.FailCount,
 		}
+		if len(msm.schema.BrokenDatasets(sourceProviderName(source))) > 0 {
+			status.Status = "schema-broken"
+		}
+		statuses = append(statuses, status)
+	}
+
+	// 非内置的数据源（新增内置源与插件）单独展示
+	for name, entry := range msm.providers {
+		if entry.builtin {
+			continue
+		}
+		info := entry.info
+		status := models.DataSourceStatus{
+			Key:          "provider:" + name,
+			Name:         info.Name,
+			Domain:       info.Domain,
+			Latency:      info.LastLatency,
+			LatencyLabel: formatLatencyLabel(info.LastLatency),
+			LastChecked:  formatStatusTime(info.LastChecked),
+			LastSuccess:  formatStatusTime(info.LastSuccess),
+			Status:       deriveSourceStatus(info),
+			FailCount:    info.FailCount,
+		}
+		if len(msm.schema.BrokenDatasets(name)) > 0 {
+			status.Status = "schema-broken"
+		}
 		statuses = append(statuses, status)
 	}
 
@@ -1101,6 +1066,21 @@ func (msm *MultiSourceManager) GetStatusList() []models.DataSourceStatus {
 	return statuses
 }
 
+// GetSchemaHealth 获取各数据源的字段结构校验统计
+func (msm *MultiSourceManager) GetSchemaHealth() models.SchemaHealthStats {
+	return msm.schema.Stats()
+}
+
+// sourceProviderName 内置数据源对应的行情数据源名称，结构校验按该名称记录
+func sourceProviderName(source DataSource) string {
+	for name, s := range builtinProviderSources {
+		if s == source {
+			return name
+		}
+	}
+	return ""
+}
+
 // GetSourceStats 获取数据源统计信息
 func (msm *MultiSourceManager) GetSourceStats() map[string]interface{} {
 	msm.mu.RLock()
@@ -0,0 +1,262 @@
+package data
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"stock-ai/backend/models"
+
+	"gorm.io/gorm/clause"
+)
+
+// ==================== AI资讯日报 ====================
+
+const (
+	digestArticleMaxRunes = 1500              // 单条资讯送入模型的最大字数
+	digestGroupMaxItems   = 12                // 单只股票最多汇总的资讯条数
+	digestAITimeout       = 120 * time.Second // 单只股票汇总的模型超时
+)
+
+// 日报情绪标签
+const (
+	SentimentPositive = "利好"
+	SentimentNeutral  = "中性"
+	SentimentNegative = "利空"
+)
+
+// digestKindLabels 资讯类型显示名称
+var digestKindLabels = map[string]string{
+	"notice": "公告",
+	"report": "研报",
+	"news":   "新闻",
+}
+
+// DigestArticle 日报中的单条资讯
+type DigestArticle struct {
+	Kind    string // notice, report, news
+	Title   string
+	Url     string
+	Content string
+}
+
+// Hash 资讯内容哈希，作为单条摘要的缓存键
+func (a DigestArticle) Hash() string {
+	return digestContentHash(a.Kind, a.Title, a.Content)
+}
+
+// DigestGroup 单只股票的当日资讯
+type DigestGroup struct {
+	Code     string
+	Name     string
+	Articles []DigestArticle
+}
+
+// DigestStockSummary 单只股票的AI汇总结果
+type DigestStockSummary struct {
+	Code      string
+	Name      string
+	Sentiment string
+	Summary   string
+	Articles  []DigestArticle
+	Briefs    []string // 与 Articles 一一对应的单条摘要
+	Cached    bool     // 整组结果来自缓存
+}
+
+// digestGroupResponse 模型返回的汇总结果
+type digestGroupResponse struct {
+	Sentiment string `json:"sentiment"`
+	Summary   string `json:"summary"`
+	Items     []struct {
+		Index   int    `json:"index"`
+		Summary string `json:"summary"`
+	} `json:"items"`
+}
+
+// digestContentHash 计算内容哈希
+func digestContentHash(parts ...string) string {
+	sum := sha1.Sum([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+// loadSummaryCache 批量读取摘要缓存
+func loadSummaryCache(hashes []string) map[string]models.AISummaryCache {
+	result := make(map[string]models.AISummaryCache)
+	if len(hashes) == 0 {
+		return result
+	}
+	var rows []models.AISummaryCache
+	GetDB().Where("content_hash IN ?", hashes).Find(&rows)
+	for _, row := range rows {
+		result[row.ContentHash] = row
+	}
+	return result
+}
+
+// saveSummaryCache 写入摘要缓存（已存在则覆盖）
+func saveSummaryCache(hash, summary, sentiment string) {
+	GetDB().Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "content_hash"}},
+		DoUpdates: clause.AssignmentColumns([]string{"summary", "sentiment"}),
+	}).Create(&models.AISummaryCache{ContentHash: hash, Summary: summary, Sentiment: sentiment})
+}
+
+// normalizeSentiment 将模型输出的情绪统一为 利好/中性/利空
+func normalizeSentiment(s string) string {
+	switch {
+	case strings.Contains(s, "利好"), strings.Contains(s, "正面"), strings.EqualFold(s, "positive"):
+		return SentimentPositive
+	case strings.Contains(s, "利空"), strings.Contains(s, "负面"), strings.EqualFold(s, "negative"):
+		return SentimentNegative
+	default:
+		return SentimentNeutral
+	}
+}
+
+// truncateRunes 按字符截断文本
+func truncateRunes(s string, max int) string {
+	runes := []rune(strings.TrimSpace(s))
+	if len(runes) <= max {
+		return string(runes)
+	}
+	return string(runes[:max]) + "..."
+}
+
+// SummarizeDigestGroup 汇总单只股票的当日资讯并给出情绪标签
+// 整组内容未变化时直接复用缓存；已摘要过的资讯只向模型提供摘要而非原文
+func SummarizeDigestGroup(ctx context.Context, client *AIClient, group DigestGroup) (*DigestStockSummary, error) {
+	articles := group.Articles
+	if len(articles) > digestGroupMaxItems {
+		articles = articles[:digestGroupMaxItems]
+	}
+
+	hashes := make([]string, len(articles))
+	for i, article := range articles {
+		hashes[i] = article.Hash()
+	}
+	sorted := append([]string(nil), hashes...)
+	sort.Strings(sorted)
+	groupHash := digestContentHash(append([]string{"group", group.Code}, sorted...)...)
+
+	cache := loadSummaryCache(append(hashes, groupHash))
+	summary := &DigestStockSummary{
+		Code:     group.Code,
+		Name:     group.Name,
+		Articles: articles,
+		Briefs:   make([]string, len(articles)),
+	}
+	for i, hash := range hashes {
+		summary.Briefs[i] = cache[hash].Summary
+	}
+
+	if cached, ok := cache[groupHash]; ok {
+		summary.Sentiment = cached.Sentiment
+		summary.Summary = cached.Summary
+		summary.Cached = true
+		return summary, nil
+	}
+
+	prompt := buildDigestGroupPrompt(group.Name, group.Code, articles, summary.Briefs)
+	resp, err := client.ChatContext(ctx, []ChatMessage{{Role: "user", Content: prompt}}, digestAITimeout)
+	if err != nil {
+		return nil, err
+	}
+	jsonStr := extractJSONBlock(resp)
+	if jsonStr == "" {
+		return nil, fmt.Errorf("AI未返回有效的JSON结果")
+	}
+	var result digestGroupResponse
+	if err := json.Unmarshal([]byte(jsonStr), &result); err != nil {
+		return nil, fmt.Errorf("解析AI输出失败: %w", err)
+	}
+
+	summary.Sentiment = normalizeSentiment(result.Sentiment)
+	summary.Summary = strings.TrimSpace(result.Summary)
+	for _, item := range result.Items {
+		i := item.Index - 1
+		brief := strings.TrimSpace(item.Summary)
+		if i < 0 || i >= len(articles) || brief == "" || summary.Briefs[i] != "" {
+			continue
+		}
+		summary.Briefs[i] = brief
+		saveSummaryCache(hashes[i], brief, "")
+	}
+	if summary.Summary != "" {
+		saveSummaryCache(groupHash, summary.Summary, summary.Sentiment)
+	}
+	return summary, nil
+}
+
+// buildDigestGroupPrompt 构建单只股票资讯汇总提示词
+func buildDigestGroupPrompt(name, code string, articles []DigestArticle, briefs []string) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("以下是 %s（%s）今日的公告、研报与新闻，请汇总要点并判断整体影响。\n\n", name, code))
+	for i, article := range articles {
+		sb.WriteString(fmt.Sprintf("【%d】[%s] %s\n", i+1, digestKindLabels[article.Kind], article.Title))
+		if briefs[i] != "" {
+			sb.WriteString(fmt.Sprintf("已有摘要：%s\n\n", briefs[i]))
+			continue
+		}
+		if content := truncateRunes(article.Content, digestArticleMaxRunes); content != "" {
+			sb.WriteString(content)
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString(`请完成：
+1. 为没有"已有摘要"的每条资讯写一句话摘要（不超过50字）
+2. 用2-3句话概括这些资讯对该股的整体影响
+3. 给出整体情绪标签：利好、中性 或 利空
+
+请严格以纯JSON格式返回（不要附加任何文字或代码块），格式如下：
+{
+  "sentiment": "利好",
+  "summary": "整体点评",
+  "items": [{ "index": 1, "summary": "一句话摘要" }]
+}`)
+	return sb.String()
+}
+
+// RenderDigestMarkdown 生成日报 Markdown 正文
+func RenderDigestMarkdown(date string, summaries []*DigestStockSummary) string {
+	counts := make(map[string]int)
+	itemCount := 0
+	for _, s := range summaries {
+		counts[s.Sentiment]++
+		itemCount += len(s.Articles)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("# 自选股资讯日报 %s\n\n", date))
+	sb.WriteString(fmt.Sprintf("共 %d 只股票、%d 条资讯｜利好 %d · 中性 %d · 利空 %d\n\n",
+		len(summaries), itemCount, counts[SentimentPositive], counts[SentimentNeutral], counts[SentimentNegative]))
+
+	for _, s := range summaries {
+		sb.WriteString(fmt.Sprintf("## %s (%s)【%s】\n\n", s.Name, s.Code, s.Sentiment))
+		if s.Summary != "" {
+			sb.WriteString(s.Summary)
+			sb.WriteString("\n\n")
+		}
+		for i, article := range s.Articles {
+			title := article.Title
+			if article.Url != "" {
+				title = fmt.Sprintf("[%s](%s)", article.Title, article.Url)
+			}
+			sb.WriteString(fmt.Sprintf("- [%s] %s", digestKindLabels[article.Kind], title))
+			if s.Briefs[i] != "" {
+				sb.WriteString("：")
+				sb.WriteString(s.Briefs[i])
+			}
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("> 以上内容由AI根据公开资讯生成，仅供参考，不构成投资建议。\n")
+	return sb.String()
+}
@@ -0,0 +1,522 @@
+package data
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"stock-ai/backend/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ==================== 新闻采集与全文检索 ====================
+
+const (
+	newsCollectInterval     = 3 * time.Minute      // 交易时段采集间隔
+	newsCollectIdleInterval = 10 * time.Minute     // 非交易时段采集间隔
+	newsRetention           = 180 * 24 * time.Hour // 新闻保留时长
+	securityNamesTTL        = 24 * time.Hour       // 证券名称表刷新间隔
+	stockMatcherTTL         = 10 * time.Minute     // 股票识别器重建间隔（自选股可能变化）
+	newsSearchLimit         = 200                  // 单次检索最多返回条数
+	newsFTSMinTermRunes     = 3                    // trigram 分词要求检索词至少3个字符
+)
+
+// 采集的国际新闻频道，对应 GetGlobalNews 的 country 参数
+var newsGlobalChannels = []string{"us", "hk", "global"}
+
+// newsFTSEnabled 全文索引是否可用，不可用时检索退化为 LIKE 匹配
+var newsFTSEnabled bool
+
+// initNewsFTS 创建新闻全文索引（FTS5 trigram 分词，支持中文子串检索）
+func initNewsFTS(db *gorm.DB) {
+	err := db.Exec("CREATE VIRTUAL TABLE IF NOT EXISTS news_fts USING fts5(title, content, tokenize='trigram')").Error
+	if err != nil {
+		log.Printf("[新闻检索] 创建全文索引失败，检索将退化为模糊匹配: %v", err)
+		return
+	}
+	newsFTSEnabled = true
+}
+
+// eastmoneyArticleURL 根据东方财富资讯代码生成文章链接
+func eastmoneyArticleURL(code string) string {
+	if code == "" {
+		return ""
+	}
+	return fmt.Sprintf("https://finance.eastmoney.com/a/%s.html", code)
+}
+
+// newsTitleHash 标准化标题后计算哈希，用于跨数据源去重
+func newsTitleHash(title string) string {
+	normalized := strings.ToLower(strings.Join(strings.Fields(title), ""))
+	sum := sha1.Sum([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+// ==================== 股票识别 ====================
+
+// stockMatcher 识别文本中提及的股票（名称或6位代码）
+type stockMatcher struct {
+	names   []stockNameEntry  // 按名称长度降序，优先匹配长名称
+	codes   map[string]string // 6位代码 -> 带前缀代码
+	builtAt time.Time
+}
+
+type stockNameEntry struct {
+	name string
+	code string
+}
+
+var (
+	newsMatcher   *stockMatcher
+	newsMatcherMu sync.Mutex
+	sixDigitRe    = regexp.MustCompile(`\b\d{6}\b`)
+)
+
+// normalizeSecurityName 去掉空格、全角字母及ST等前缀，便于与新闻正文匹配
+func normalizeSecurityName(name string) string {
+	name = strings.Join(strings.Fields(name), "")
+	name = strings.Map(func(r rune) rune {
+		if r >= 'Ａ' && r <= 'Ｚ' {
+			return r - 'Ａ' + 'A'
+		}
+		return r
+	}, name)
+	for _, prefix := range []string{"*ST", "ST", "XD", "XR", "DR"} {
+		name = strings.TrimPrefix(name, prefix)
+	}
+	return name
+}
+
+// getStockMatcher 获取股票识别器（证券名称表 + 自选股），定期重建
+func getStockMatcher() *stockMatcher {
+	newsMatcherMu.Lock()
+	defer newsMatcherMu.Unlock()
+
+	if newsMatcher != nil && time.Since(newsMatcher.builtAt) < stockMatcherTTL {
+		return newsMatcher
+	}
+
+	db := GetDB()
+	var securities []models.SecurityName
+	db.Find(&securities)
+	var watchlist []models.Stock
+	db.Find(&watchlist)
+
+	m := &stockMatcher{codes: make(map[string]string), builtAt: time.Now()}
+	seen := make(map[string]bool)
+	add := func(code, name string) {
+		code = strings.ToLower(code)
+		if len(code) == 8 {
+			m.codes[code[2:]] = code
+		}
+		name = normalizeSecurityName(name)
+		// 名称过短容易误匹配
+		if utf8.RuneCountInString(name) < 3 || seen[name] {
+			return
+		}
+		seen[name] = true
+		m.names = append(m.names, stockNameEntry{name: name, code: code})
+	}
+	for _, s := range securities {
+		add(s.Code, s.Name)
+	}
+	for _, s := range watchlist {
+		add(s.Code, s.Name)
+	}
+	sort.Slice(m.names, func(i, j int) bool {
+		return len(m.names[i].name) > len(m.names[j].name)
+	})
+
+	newsMatcher = m
+	return m
+}
+
+// invalidateStockMatcher 证券名称表更新后重建识别器
+func invalidateStockMatcher() {
+	newsMatcherMu.Lock()
+	newsMatcher = nil
+	newsMatcherMu.Unlock()
+}
+
+// Match 返回文本中提及的股票代码（去重、保持出现顺序）
+func (m *stockMatcher) Match(text string) []string {
+	var codes []string
+	seen := make(map[string]bool)
+	addCode := func(code string) {
+		if !seen[code] {
+			seen[code] = true
+			codes = append(codes, code)
+		}
+	}
+
+	for _, entry := range m.names {
+		if strings.Contains(text, entry.name) {
+			addCode(entry.code)
+			// 屏蔽已匹配的名称，避免长名称中的短名称被重复识别
+			text = strings.ReplaceAll(text, entry.name, " ")
+		}
+	}
+	for _, digits := range sixDigitRe.FindAllString(text, -1) {
+		if code, ok := m.codes[digits]; ok {
+			addCode(code)
+		}
+	}
+	return codes
+}
+
+// ==================== 入库 ====================
+
+// IngestNews 新闻入库：按标题哈希/链接去重，识别提及的股票并写入全文索引，返回新增条数
+func IngestNews(channel string, items []models.NewsItem) (int, error) {
+	db := GetDB()
+	if db == nil {
+		return 0, fmt.Errorf("数据库未初始化")
+	}
+	matcher := getStockMatcher()
+
+	added := 0
+	for _, item := range items {
+		title := strings.TrimSpace(item.Title)
+		if title == "" {
+			continue
+		}
+		hash := newsTitleHash(title)
+
+		var count int64
+		query := db.Model(&models.NewsArticle{}).Where("title_hash = ?", hash)
+		if item.Url != "" {
+			query = query.Or("url = ?", item.Url)
+		}
+		query.Count(&count)
+		if count > 0 {
+			continue
+		}
+
+		// 统一按北京时间存储，保证按时间范围检索时可直接比较
+		publishTime, err := time.ParseInLocation("2006-01-02 15:04:05", item.Time, quoteLocation)
+		if err != nil {
+			publishTime = time.Now().In(quoteLocation)
+		}
+		codes := matcher.Match(title + "\n" + item.Content)
+
+		article := models.NewsArticle{
+			TitleHash:   hash,
+			Url:         item.Url,
+			Title:       title,
+			Content:     item.Content,
+			Source:      item.Source,
+			Channel:     channel,
+			Codes:       strings.Join(codes, ","),
+			PublishTime: publishTime,
+		}
+		err = db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Create(&article).Error; err != nil {
+				return err
+			}
+			for _, code := range codes {
+				if err := tx.Create(&models.NewsStockTag{NewsID: article.ID, Code: code}).Error; err != nil {
+					return err
+				}
+			}
+			if newsFTSEnabled {
+				return tx.Exec("INSERT INTO news_fts(rowid, title, content) VALUES (?, ?, ?)", article.ID, article.Title, article.Content).Error
+			}
+			return nil
+		})
+		if err != nil {
+			return added, fmt.Errorf("新闻入库失败: %v", err)
+		}
+		added++
+	}
+	return added, nil
+}
+
+// PruneNews 删除超过保留期的新闻及其标签、索引
+func PruneNews(before time.Time) (int64, error) {
+	db := GetDB()
+	var ids []uint
+	if err := db.Model(&models.NewsArticle{}).Where("publish_time < ?", before).Pluck("id", &ids).Error; err != nil {
+		return 0, err
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("news_id IN ?", ids).Delete(&models.NewsStockTag{}).Error; err != nil {
+			return err
+		}
+		if newsFTSEnabled {
+			if err := tx.Exec("DELETE FROM news_fts WHERE rowid IN ?", ids).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Where("id IN ?", ids).Delete(&models.NewsArticle{}).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(ids)), nil
+}
+
+// ==================== 检索 ====================
+
+// SearchNews 检索入库新闻：query 为空格分隔的关键词（全部命中），codes 为关联股票（任一命中），
+// from/to 为零值表示不限制；结果按发布时间倒序
+func SearchNews(query string, codes []string, from, to time.Time) ([]models.NewsArticle, error) {
+	db := GetDB()
+	if db == nil {
+		return nil, fmt.Errorf("数据库未初始化")
+	}
+
+	tx := db.Model(&models.NewsArticle{})
+	if len(codes) > 0 {
+		tx = tx.Where("id IN (SELECT news_id FROM news_stock_tags WHERE code IN ?)", codes)
+	}
+	if !from.IsZero() {
+		tx = tx.Where("publish_time >= ?", from.In(quoteLocation))
+	}
+	if !to.IsZero() {
+		tx = tx.Where("publish_time < ?", to.In(quoteLocation))
+	}
+
+	var ftsTerms []string
+	for _, term := range strings.Fields(query) {
+		if newsFTSEnabled && utf8.RuneCountInString(term) >= newsFTSMinTermRunes {
+			ftsTerms = append(ftsTerms, `"`+strings.ReplaceAll(term, `"`, `""`)+`"`)
+			continue
+		}
+		// 短词无法使用 trigram 索引，退化为模糊匹配
+		like := "%" + term + "%"
+		tx = tx.Where("(title LIKE ? OR content LIKE ?)", like, like)
+	}
+	if len(ftsTerms) > 0 {
+		tx = tx.Where("id IN (SELECT rowid FROM news_fts WHERE news_fts MATCH ?)", strings.Join(ftsTerms, " AND "))
+	}
+
+	var articles []models.NewsArticle
+	err := tx.Order("publish_time DESC").Limit(newsSearchLimit).Find(&articles).Error
+	return articles, err
+}
+
+// ListTaggedNewsAfter 获取关联指定股票且ID大于 afterID 的新闻，按ID升序
+func ListTaggedNewsAfter(code string, afterID uint) ([]models.NewsArticle, error) {
+	var articles []models.NewsArticle
+	err := GetDB().
+		Where("id > ? AND id IN (SELECT news_id FROM news_stock_tags WHERE code = ?)", afterID, code).
+		Order("id ASC").
+		Limit(newsSearchLimit).
+		Find(&articles).Error
+	return articles, err
+}
+
+// LatestNewsID 获取当前最大新闻ID
+func LatestNewsID() uint {
+	var id uint
+	GetDB().Model(&models.NewsArticle{}).Select("COALESCE(MAX(id), 0)").Scan(&id)
+	return id
+}
+
+// ==================== 证券名称表 ====================
+
+// RefreshSecurityNames 从东方财富拉取全部A股代码与名称；距上次更新不足24小时且 force 为 false 时跳过
+func RefreshSecurityNames(ctx context.Context, force bool) error {
+	db := GetDB()
+	if !force {
+		var latest models.SecurityName
+		if err := db.Order("updated_at DESC").First(&latest).Error; err == nil && time.Since(latest.UpdatedAt) < securityNamesTTL {
+			return nil
+		}
+	}
+
+	rm := GetRequestManager()
+	const pageSize = 100
+	var securities []models.SecurityName
+	for page := 1; page <= 100; page++ {
+		url := fmt.Sprintf("https://push2.eastmoney.com/api/qt/clist/get?pn=%d&pz=%d&po=1&np=1&fltt=2&invt=2&fid=f12&fs=m:0+t:6,m:0+t:80,m:1+t:2,m:1+t:23,m:0+t:81+s:2048&fields=f12,f13,f14", page, pageSize)
+		body, err := getWithRateLimit(ctx, rm, url, "https://quote.eastmoney.com/", "eastmoney.com")
+		if err != nil {
+			return fmt.Errorf("获取A股列表失败: %v", err)
+		}
+
+		var result struct {
+			Data *struct {
+				Total int `json:"total"`
+				Diff  []struct {
+					Code   string `json:"f12"`
+					Market int    `json:"f13"`
+					Name   string `json:"f14"`
+				} `json:"diff"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(body, &result); err != nil {
+			return fmt.Errorf("解析A股列表失败: %v", err)
+		}
+		if result.Data == nil || len(result.Data.Diff) == 0 {
+			break
+		}
+
+		now := time.Now()
+		for _, item := range result.Data.Diff {
+			if item.Code == "" || item.Name == "" {
+				continue
+			}
+			securities = append(securities, models.SecurityName{
+				Code:      securityMarketPrefix(item.Code, item.Market) + item.Code,
+				Name:      item.Name,
+				UpdatedAt: now,
+			})
+		}
+		if page*pageSize >= result.Data.Total {
+			break
+		}
+	}
+
+	if len(securities) == 0 {
+		return fmt.Errorf("A股列表为空")
+	}
+
+	err := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "code"}},
+		DoUpdates: clause.AssignmentColumns([]string{"name", "updated_at"}),
+	}).CreateInBatches(securities, 500).Error
+	if err != nil {
+		return fmt.Errorf("保存A股列表失败: %v", err)
+	}
+
+	log.Printf("[新闻采集] 证券名称表已更新，共 %d 只", len(securities))
+	invalidateStockMatcher()
+	return nil
+}
+
+// securityMarketPrefix 根据东方财富市场编号与代码推断交易所前缀
+func securityMarketPrefix(code string, market int) string {
+	if market == 1 {
+		return "sh"
+	}
+	if strings.HasPrefix(code, "8") || strings.HasPrefix(code, "4") || strings.HasPrefix(code, "92") {
+		return "bj"
+	}
+	return "sz"
+}
+
+// ==================== 后台采集 ====================
+
+// NewsCollector 后台新闻采集器
+type NewsCollector struct {
+	stockAPI  *StockAPI
+	globalAPI *GlobalMarketAPI
+	mu        sync.Mutex
+	started   bool
+	lastPrune time.Time
+}
+
+var (
+	newsCollector     *NewsCollector
+	newsCollectorOnce sync.Once
+)
+
+// GetNewsCollector 获取新闻采集器单例
+func GetNewsCollector() *NewsCollector {
+	newsCollectorOnce.Do(func() {
+		newsCollector = &NewsCollector{
+			stockAPI:  NewStockAPI(),
+			globalAPI: NewGlobalMarketAPI(),
+		}
+	})
+	return newsCollector
+}
+
+// Start 启动后台采集（重复调用无副作用）
+func (c *NewsCollector) Start(ctx context.Context) {
+	c.mu.Lock()
+	if c.started {
+		c.mu.Unlock()
+		return
+	}
+	c.started = true
+	c.mu.Unlock()
+
+	go func() {
+		if err := RefreshSecurityNames(ctx, false); err != nil {
+			log.Printf("[新闻采集] %v", err)
+		}
+		ticker := time.NewTicker(newsCollectIdleInterval)
+		defer ticker.Stop()
+		for {
+			if added, err := c.CollectOnce(ctx); err != nil {
+				log.Printf("[新闻采集] 采集失败: %v", err)
+			} else if added > 0 {
+				log.Printf("[新闻采集] 新增 %d 条新闻", added)
+			}
+
+			// 交易时段采集更频繁，每轮按当前时段重设间隔
+			interval := newsCollectIdleInterval
+			if IsTradingTime() {
+				interval = newsCollectInterval
+			}
+			ticker.Reset(interval)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// CollectOnce 采集一轮国内快讯与国际新闻，返回新增条数
+func (c *NewsCollector) CollectOnce(ctx context.Context) (int, error) {
+	total := 0
+
+	// 占位数据不入库；持久化缓存中的旧数据会被去重跳过
+	if result := c.stockAPI.GetNewsList(ctx); !IsPlaceholder(result.Meta) {
+		added, err := IngestNews("cn", result.Items)
+		if err != nil {
+			return total, err
+		}
+		total += added
+	}
+	for _, channel := range newsGlobalChannels {
+		result := c.globalAPI.GetGlobalNews(ctx, channel)
+		if IsPlaceholder(result.Meta) {
+			continue
+		}
+		added, err := IngestNews(channel, result.Items)
+		if err != nil {
+			return total, err
+		}
+		total += added
+	}
+
+	c.mu.Lock()
+	needPrune := time.Since(c.lastPrune) > 24*time.Hour
+	if needPrune {
+		c.lastPrune = time.Now()
+	}
+	c.mu.Unlock()
+	if needPrune {
+		if removed, err := PruneNews(time.Now().Add(-newsRetention)); err != nil {
+			log.Printf("[新闻采集] 清理过期新闻失败: %v", err)
+		} else if removed > 0 {
+			log.Printf("[新闻采集] 已清理 %d 条过期新闻", removed)
+		}
+	}
+
+	// 证券名称表按天刷新
+	if err := RefreshSecurityNames(ctx, false); err != nil {
+		log.Printf("[新闻采集] %v", err)
+	}
+	return total, nil
+}
@@ -3,21 +3,29 @@ package data
 import (
 	"encoding/json"
 	"log"
+	"maps"
 	"os"
 	"path/filepath"
 	"sync"
 	"time"
 
+	"stock-ai/backend/cache"
 	"stock-ai/backend/models"
 )
 
 // PersistentCache 持久化缓存管理器
-// 用于在应用重启后快速加载上次的数据
+// 用于在应用重启后快速加载上次的数据，底层为 snapshot.market 命名空间的磁盘层
 type PersistentCache struct {
-	cacheDir string
-	mu       sync.RWMutex
+	store *cache.Cache[*CachedData]
+	mu    sync.Mutex
 }
 
+// marketSnapshotKey 市场数据快照只有一份，使用固定键
+const marketSnapshotKey = "market"
+
+// marketSnapshotTTL 超过该时长的快照视为过期
+const marketSnapshotTTL = 24 * time.Hour
+
 // CachedData 缓存的数据结构
 type CachedData struct {
 	// 市场指数
@@ -54,86 +62,75 @@ var (
 // GetPersistentCache 获取持久化缓存单例
 func GetPersistentCache() *PersistentCache {
 	persistentCacheOnce.Do(func() {
-		// 获取用户数据目录
-		homeDir, err := os.UserHomeDir()
-		if err != nil {
-			homeDir = "."
-		}
-		cacheDir := filepath.Join(homeDir, ".stock-ai", "cache")
-
-		// 确保目录存在
-		os.MkdirAll(cacheDir, 0755)
-
 		globalPersistentCache = &PersistentCache{
-			cacheDir: cacheDir,
+			store: cache.New[*CachedData](cache.Options{
+				Namespace: "snapshot.market",
+				TTL:       marketSnapshotTTL,
+				Persist:   true,
+			}),
 		}
+		globalPersistentCache.migrateLegacyFile()
 	})
 	return globalPersistentCache
 }
 
-// getCacheFilePath 获取缓存文件路径
-func (pc *PersistentCache) getCacheFilePath() string {
-	return filepath.Join(pc.cacheDir, "market_data.json")
+// migrateLegacyFile 导入旧版单文件缓存 market_data.json，导入后删除
+func (pc *PersistentCache) migrateLegacyFile() {
+	legacyPath := filepath.Join(cache.DiskDir(), "market_data.json")
+	jsonData, err := os.ReadFile(legacyPath)
+	if err != nil {
+		return
+	}
+	defer os.Remove(legacyPath)
+
+	var data CachedData
+	if err := json.Unmarshal(jsonData, &data); err != nil {
+		log.Printf("[PersistentCache] 解析旧版缓存文件失败: %v", err)
+		return
+	}
+	if _, _, ok := pc.store.GetStale(marketSnapshotKey); ok {
+		return
+	}
+	if age := time.Since(data.CacheTime); age < marketSnapshotTTL {
+		pc.store.SetWithTTL(marketSnapshotKey, &data, marketSnapshotTTL-age)
+		log.Printf("[PersistentCache] 已导入旧版缓存文件 (缓存时间: %v)", data.CacheTime)
+	}
 }
 
-// SaveCache 保存缓存到文件
+// SaveCache 保存缓存
 func (pc *PersistentCache) SaveCache(data *CachedData) error {
 	pc.mu.Lock()
 	defer pc.mu.Unlock()
 
 	data.CacheTime = time.Now()
-
-	jsonData, err := json.MarshalIndent(data, "", "  ")
-	if err != nil {
-		log.Printf("[PersistentCache] 序列化缓存数据失败: %v", err)
-		return err
-	}
-
-	filePath := pc.getCacheFilePath()
-	err = os.WriteFile(filePath, jsonData, 0644)
-	if err != nil {
-		log.Printf("[PersistentCache] 写入缓存文件失败: %v", err)
-		return err
-	}
-
-	log.Printf("[PersistentCache] 缓存已保存到 %s", filePath)
+	pc.store.Set(marketSnapshotKey, data)
 	return nil
 }
 
-// LoadCache 从文件加载缓存
+// LoadCache 加载缓存，超过24小时的快照视为过期并返回 nil
 func (pc *PersistentCache) LoadCache() (*CachedData, error) {
-	pc.mu.RLock()
-	defer pc.mu.RUnlock()
-
-	filePath := pc.getCacheFilePath()
-
-	// 检查文件是否存在
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		log.Printf("[PersistentCache] 缓存文件不存在: %s", filePath)
-		return nil, err
-	}
-
-	jsonData, err := os.ReadFile(filePath)
-	if err != nil {
-		log.Printf("[PersistentCache] 读取缓存文件失败: %v", err)
-		return nil, err
+	data, ok := pc.store.Get(marketSnapshotKey)
+	if !ok || data == nil {
+		return nil, nil
 	}
+	return data, nil
+}
 
-	var data CachedData
-	err = json.Unmarshal(jsonData, &data)
-	if err != nil {
-		log.Printf("[PersistentCache] 解析缓存数据失败: %v", err)
-		return nil, err
-	}
+// update 在锁内基于当前快照的副本修改并保存，避免并发保存互相覆盖
+func (pc *PersistentCache) update(modify func(data *CachedData)) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
 
-	// 检查缓存是否过期（超过24小时认为过期）
-	if time.Since(data.CacheTime) > 24*time.Hour {
-		log.Printf("[PersistentCache] 缓存已过期 (缓存时间: %v)", data.CacheTime)
-		return nil, nil
+	next := &CachedData{}
+	if current, ok := pc.store.Get(marketSnapshotKey); ok && current != nil {
+		copied := *current
+		copied.GlobalNews = maps.Clone(current.GlobalNews)
+		copied.GlobalSentiment = maps.Clone(current.GlobalSentiment)
+		next = &copied
 	}
-
-	log.Printf("[PersistentCache] 成功加载缓存 (缓存时间: %v)", data.CacheTime)
-	return &data, nil
+	modify(next)
+	next.CacheTime = time.Now()
+	pc.store.Set(marketSnapshotKey, next)
 }
 
 // HasValidCache 检查是否有有效的缓存
@@ -153,118 +150,85 @@ func (pc *PersistentCache) GetCacheAge() time.Duration {
 
 // SaveMarketIndex 保存市场指数
 func (pc *PersistentCache) SaveMarketIndex(data []models.MarketIndex) {
-	cached, _ := pc.LoadCache()
-	if cached == nil {
-		cached = &CachedData{}
-	}
-	cached.MarketIndex = data
-	pc.SaveCache(cached)
+	pc.update(func(cached *CachedData) {
+		cached.MarketIndex = data
+	})
 }
 
 // SaveIndustryRank 保存行业排行
 func (pc *PersistentCache) SaveIndustryRank(data []models.IndustryRank) {
-	cached, _ := pc.LoadCache()
-	if cached == nil {
-		cached = &CachedData{}
-	}
-	cached.IndustryRank = data
-	pc.SaveCache(cached)
+	pc.update(func(cached *CachedData) {
+		cached.IndustryRank = data
+	})
 }
 
 // SaveMoneyFlow 保存资金流向
 func (pc *PersistentCache) SaveMoneyFlow(data []models.MoneyFlow) {
-	cached, _ := pc.LoadCache()
-	if cached == nil {
-		cached = &CachedData{}
-	}
-	cached.MoneyFlow = data
-	pc.SaveCache(cached)
+	pc.update(func(cached *CachedData) {
+		cached.MoneyFlow = data
+	})
 }
 
 // SaveNewsList 保存新闻列表
 func (pc *PersistentCache) SaveNewsList(data []models.NewsItem) {
-	cached, _ := pc.LoadCache()
-	if cached == nil {
-		cached = &CachedData{}
-	}
-	cached.NewsList = data
-	pc.SaveCache(cached)
+	pc.update(func(cached *CachedData) {
+		cached.NewsList = data
+	})
 }
 
 // SaveLongTigerRank 保存龙虎榜
 func (pc *PersistentCache) SaveLongTigerRank(data []models.LongTigerItem) {
-	cached, _ := pc.LoadCache()
-	if cached == nil {
-		cached = &CachedData{}
-	}
-	cached.LongTigerRank = data
-	pc.SaveCache(cached)
+	pc.update(func(cached *CachedData) {
+		cached.LongTigerRank = data
+	})
 }
 
 // SaveHotTopics 保存热门话题
 func (pc *PersistentCache) SaveHotTopics(data []models.HotTopic) {
-	cached, _ := pc.LoadCache()
-	if cached == nil {
-		cached = &CachedData{}
-	}
-	cached.HotTopics = data
-	pc.SaveCache(cached)
+	pc.update(func(cached *CachedData) {
+		cached.HotTopics = data
+	})
 }
 
 // SaveAShareSentiment 保存A股情绪
 func (pc *PersistentCache) SaveAShareSentiment(data *MarketSentiment) {
-	cached, _ := pc.LoadCache()
-	if cached == nil {
-		cached = &CachedData{}
-	}
-	cached.AShareSentiment = data
-	pc.SaveCache(cached)
+	pc.update(func(cached *CachedData) {
+		cached.AShareSentiment = data
+	})
 }
 
 // SaveGlobalIndices 保存全球指数
 func (pc *PersistentCache) SaveGlobalIndices(data map[string]*IndexData) {
-	cached, _ := pc.LoadCache()
-	if cached == nil {
-		cached = &CachedData{}
-	}
-	cached.GlobalIndices = data
-	pc.SaveCache(cached)
+	pc.update(func(cached *CachedData) {
+		cached.GlobalIndices = data
+	})
 }
 
 // SaveGlobalIndicesList 保存全球指数列表
 func (pc *PersistentCache) SaveGlobalIndicesList(data []models.GlobalIndex) {
-	cached, _ := pc.LoadCache()
-	if cached == nil {
-		cached = &CachedData{}
-	}
-	cached.GlobalIndicesList = data
-	pc.SaveCache(cached)
+	pc.update(func(cached *CachedData) {
+		cached.GlobalIndicesList = data
+	})
 }
 
 // SaveGlobalNews 保存某国新闻
 func (pc *PersistentCache) SaveGlobalNews(country string, data []models.NewsItem) {
-	cached, _ := pc.LoadCache()
-	if cached == nil {
-		cached = &CachedData{}
-	}
-	if cached.GlobalNews == nil {
-		cached.GlobalNews = make(map[string][]models.NewsItem)
-	}
-	cached.GlobalNews[country] = data
-	pc.SaveCache(cached)
+	pc.update(func(cached *CachedData) {
+		if cached.GlobalNews == nil {
+			cached.GlobalNews = make(map[string][]models.NewsItem)
+		}
+		cached.GlobalNews[country] = data
+	})
 }
 
 // SaveGlobalSentiment 保存某国市场情绪
 func (pc *PersistentCache) SaveGlobalSentiment(country string, data *MarketSentiment) {
-	cached, _ := pc.LoadCache()
-	if cached == nil {
-		cached = &CachedData{}
-	}
-	if cached.GlobalSentiment == nil {
-		cached.GlobalSentiment = make(map[string]*MarketSentiment)
-	}
-	cached.GlobalSentiment[country] = data
-	pc.SaveCache(cached)
+	pc.update(func(cached *CachedData) {
+		if cached.GlobalSentiment == nil {
+			cached.GlobalSentiment = make(map[string]*MarketSentiment)
+		}
+		cached.GlobalSentiment[country] = data
+	})
 }
 
 // SaveAllData 一次性保存所有数据
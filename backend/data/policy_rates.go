@@ -0,0 +1,182 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ==================== 央行政策利率 ====================
+
+// jin10RateDecisionURL 金十数据中心各央行利率决议，按公布日期倒序
+const jin10RateDecisionURL = "https://datacenter-api.jin10.com/reports/list_v2?max_date=&category=ec&attr_id=%d"
+
+// policyRateMaxDecisionAge 最近一次利率决议早于该时长时视为数据源已停更，不采用
+const policyRateMaxDecisionAge = 400 * 24 * time.Hour
+
+// 政策利率来源
+const (
+	PolicyRateSourceLive    = "各央行最新利率决议"
+	PolicyRateSourceManual  = "设置中手动填写"
+	PolicyRateSourceBuiltin = "内置参考值"
+)
+
+// policyRateSeries 各货币对应的利率决议指标
+var policyRateSeries = []struct {
+	Currency string
+	AttrID   int
+	Name     string
+}{
+	{"USD", 24, "美联储利率决议"},
+	{"EUR", 21, "欧洲央行利率决议"},
+	{"JPY", 22, "日本央行利率决议"},
+	{"NZD", 23, "新西兰联储利率决议"},
+	{"CHF", 25, "瑞士央行利率决议"},
+	{"GBP", 26, "英国央行利率决议"},
+	{"AUD", 27, "澳洲联储利率决议"},
+	{"CNY", 91, "中国人民银行利率决议"},
+}
+
+// policyRateDecision 某货币最近一次利率决议
+type policyRateDecision struct {
+	Rate float64   `json:"rate"`
+	Date time.Time `json:"date"`
+}
+
+// policyRates 返回外汇分析所需货币的政策利率：设置中手动填写的优先，其次为各央行最新利率决议，
+// 有货币无法实时获取时整体退回内置参考值，避免新旧利率混用
+func (b *AssetContextBuilder) policyRates(ctx context.Context, currencies ...string) PolicyRateTable {
+	cfg := b.rm.GetConfig()
+	if cfg != nil && strings.TrimSpace(cfg.PolicyRates) != "" {
+		return CurrentPolicyRates(cfg)
+	}
+	now := time.Now()
+	rates := make(map[string]float64, len(currencies))
+	for _, currency := range currencies {
+		decision, err := b.rateDecision(ctx, currency)
+		if err == nil && now.Sub(decision.Date) > policyRateMaxDecisionAge {
+			err = fmt.Errorf("最近一次决议为 %s，数据源可能已停更", decision.Date.Format("2006-01-02"))
+		}
+		if err != nil {
+			log.Printf("[跨资产分析] 获取 %s 利率决议失败，使用内置参考值: %v", currency, err)
+			return CurrentPolicyRates(nil)
+		}
+		rates[currency] = decision.Rate
+	}
+	// 最新决议在下次调整前一直有效，截止月份取当前月份
+	return PolicyRateTable{Rates: rates, AsOf: now.Format("2006-01"), Source: PolicyRateSourceLive}
+}
+
+// rateDecision 获取某货币最近一次利率决议，结果按货币缓存
+func (b *AssetContextBuilder) rateDecision(ctx context.Context, currency string) (policyRateDecision, error) {
+	for _, series := range policyRateSeries {
+		if series.Currency != currency {
+			continue
+		}
+		return policyRateCache.GetOrLoad(ctx, currency, func(ctx context.Context) (policyRateDecision, error) {
+			rate, date, err := fetchRateDecision(ctx, b.rm, series.AttrID)
+			if err != nil {
+				return policyRateDecision{}, fmt.Errorf("%s: %w", series.Name, err)
+			}
+			return policyRateDecision{Rate: rate, Date: date}, nil
+		})
+	}
+	return policyRateDecision{}, fmt.Errorf("暂无 %s 的利率决议数据源", currency)
+}
+
+// fetchRateDecision 获取单个央行最近一次公布的利率
+func fetchRateDecision(ctx context.Context, rm *RequestManager, attrID int) (float64, time.Time, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf(jin10RateDecisionURL, attrID), nil)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	rm.SetRequestHeaders(req, "https://datacenter.jin10.com/")
+	req.Header.Set("x-app-id", "rU6QIu7JHe2gOUeR")
+	req.Header.Set("x-version", "1.0.0")
+	req.Header.Set("x-csrf-token", "x-csrf-token")
+
+	resp, err := rm.DoRequestWithRateLimit("jin10.com", req)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return 0, time.Time{}, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	return parseRateDecision(body)
+}
+
+// parseRateDecision 解析利率决议列表，返回公布日期最新且已公布（今值非空）的一条
+// values 每行按 keys 的顺序排列，如 ["2025-09-18", 4.25, 4.25, 4.5] 对应 日期/今值/预测值/前值
+func parseRateDecision(body []byte) (float64, time.Time, error) {
+	var resp struct {
+		Data struct {
+			Keys []struct {
+				Name string `json:"name"`
+			} `json:"keys"`
+			Values [][]interface{} `json:"values"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return 0, time.Time{}, fmt.Errorf("解析利率决议失败: %v", err)
+	}
+	dateIdx, valueIdx := -1, -1
+	for i, key := range resp.Data.Keys {
+		switch strings.TrimSpace(key.Name) {
+		case "日期":
+			dateIdx = i
+		case "今值":
+			valueIdx = i
+		}
+	}
+	if dateIdx < 0 || valueIdx < 0 {
+		return 0, time.Time{}, fmt.Errorf("利率决议缺少日期或今值字段")
+	}
+
+	var (
+		latest time.Time
+		rate   float64
+		found  bool
+	)
+	for _, row := range resp.Data.Values {
+		if len(row) <= dateIdx || len(row) <= valueIdx {
+			continue
+		}
+		dateStr, _ := row[dateIdx].(string)
+		date, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(dateStr), time.Local)
+		if err != nil {
+			continue
+		}
+		value, ok := rateDecisionValue(row[valueIdx])
+		if !ok || (found && !date.After(latest)) {
+			continue
+		}
+		latest, rate, found = date, value, true
+	}
+	if !found {
+		return 0, time.Time{}, fmt.Errorf("利率决议为空")
+	}
+	return rate, latest, nil
+}
+
+// rateDecisionValue 今值可能是数字、数字字符串或 null（尚未公布）
+func rateDecisionValue(v interface{}) (float64, bool) {
+	switch val := v.(type) {
+	case float64:
+		return val, true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(val), "%"), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
@@ -0,0 +1,596 @@
+package data
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"stock-ai/backend/models"
+)
+
+// ==================== 代理路由与健康检查 ====================
+//
+// 每个请求先按域名路由规则决定直连、走固定代理、走代理池还是走规则指定的上游代理（SOCKS5/HTTP）。
+// 代理池中的每个代理记录成功率与延迟（EWMA）并据此打分，按分数加权选取；
+// 连续失败或成功率过低的代理会被剔除，后台探测恢复后重新加入。
+// 同一域名在一段时间内固定使用同一个代理（粘性会话），代理失败时立即换一个。
+
+// 代理路由方式
+const (
+	ProxyRouteDirect   = "direct"   // 直连
+	ProxyRouteProxy    = "proxy"    // 固定代理（ProxyUrl）
+	ProxyRoutePool     = "pool"     // 代理池
+	ProxyRouteUpstream = "upstream" // 规则中直接写明的上游代理地址
+)
+
+const (
+	proxyProbeURL         = "https://www.baidu.com/"
+	proxyProbeInterval    = time.Minute
+	proxyProbeTimeout     = 5 * time.Second
+	proxyEvictFailures    = 3   // 连续失败达到该次数后剔除
+	proxyEvictMinSamples  = 5   // 计算成功率的最少样本数
+	proxyEvictSuccessRate = 0.3 // 成功率低于该值时剔除
+	proxyStickyTTL        = 10 * time.Minute
+	proxyLatencyAlpha     = 0.3 // 延迟 EWMA 平滑系数
+)
+
+// proxyHealth 单个代理的健康统计
+type proxyHealth struct {
+	successes   int
+	failures    int
+	consecutive int           // 连续失败次数
+	latency     time.Duration // 延迟 EWMA
+	lastChecked time.Time
+	lastError   string
+	evicted     bool
+}
+
+// score 代理得分：平滑后的成功率除以延迟（秒），未测得延迟时按1秒计
+func (h *proxyHealth) score() float64 {
+	rate := float64(h.successes+1) / float64(h.successes+h.failures+2)
+	latency := h.latency.Seconds()
+	if latency <= 0 {
+		latency = 1
+	}
+	return rate / (0.1 + latency)
+}
+
+func (h *proxyHealth) shouldEvict() bool {
+	if h.consecutive >= proxyEvictFailures {
+		return true
+	}
+	total := h.successes + h.failures
+	return total >= proxyEvictMinSamples && float64(h.successes)/float64(total) < proxyEvictSuccessRate
+}
+
+// stickyProxy 域名粘性会话
+type stickyProxy struct {
+	proxy string
+	until time.Time
+}
+
+// healthUnsafe 获取或创建代理的健康统计（调用方持有 pp.mu）
+func (ps *ProxyPoolState) healthUnsafe(proxy string) *proxyHealth {
+	if ps.health == nil {
+		ps.health = make(map[string]*proxyHealth)
+	}
+	h, ok := ps.health[proxy]
+	if !ok {
+		h = &proxyHealth{}
+		ps.health[proxy] = h
+	}
+	return h
+}
+
+// replaceProxiesUnsafe 替换代理列表，保留仍在列表中的代理的健康统计与粘性会话
+func (ps *ProxyPoolState) replaceProxiesUnsafe(proxies []string) {
+	keep := make(map[string]*proxyHealth, len(proxies))
+	for _, p := range proxies {
+		if h, ok := ps.health[p]; ok {
+			keep[p] = h
+		} else {
+			keep[p] = &proxyHealth{}
+		}
+	}
+	ps.proxies = proxies
+	ps.health = keep
+	for host, s := range ps.sticky {
+		if _, ok := keep[s.proxy]; !ok {
+			delete(ps.sticky, host)
+		}
+	}
+}
+
+// healthyCountUnsafe 未被剔除的代理数量
+func (ps *ProxyPoolState) healthyCountUnsafe() int {
+	count := 0
+	for _, p := range ps.proxies {
+		if h, ok := ps.health[p]; !ok || !h.evicted {
+			count++
+		}
+	}
+	return count
+}
+
+// pickUnsafe 为 host 选取代理：优先沿用粘性会话，否则按得分加权随机选取
+func (ps *ProxyPoolState) pickUnsafe(host string, now time.Time) string {
+	if s, ok := ps.sticky[host]; ok && now.Before(s.until) {
+		if h := ps.healthUnsafe(s.proxy); !h.evicted {
+			return s.proxy
+		}
+	}
+
+	var candidates []string
+	var weights []float64
+	total := 0.0
+	for _, p := range ps.proxies {
+		h := ps.healthUnsafe(p)
+		if h.evicted {
+			continue
+		}
+		w := h.score()
+		candidates = append(candidates, p)
+		weights = append(weights, w)
+		total += w
+	}
+	if len(candidates) == 0 {
+		return ""
+	}
+
+	chosen := candidates[len(candidates)-1]
+	r := rand.Float64() * total
+	for i, w := range weights {
+		if r < w {
+			chosen = candidates[i]
+			break
+		}
+		r -= w
+	}
+
+	if host != "" {
+		if ps.sticky == nil {
+			ps.sticky = make(map[string]stickyProxy)
+		}
+		ps.sticky[host] = stickyProxy{proxy: chosen, until: now.Add(proxyStickyTTL)}
+	}
+	return chosen
+}
+
+// reportProxyResult 记录一次经由代理的请求或探测结果
+// probe 为 true 表示后台探测，只有探测成功才能让被剔除的代理重新加入；探测失败只记录错误，不计入剔除统计
+func (rm *RequestManager) reportProxyResult(proxy, host string, latency time.Duration, err error, probe bool) {
+	pp := rm.proxyPool
+	if pp == nil {
+		return
+	}
+	pp.mu.Lock()
+	defer pp.mu.Unlock()
+
+	h, ok := pp.health[proxy]
+	if !ok {
+		return // 代理已不在当前列表中
+	}
+	h.lastChecked = time.Now()
+	if err == nil {
+		h.successes++
+		h.consecutive = 0
+		h.lastError = ""
+		if h.latency <= 0 {
+			h.latency = latency
+		} else {
+			h.latency = time.Duration(proxyLatencyAlpha*float64(latency) + (1-proxyLatencyAlpha)*float64(h.latency))
+		}
+		if h.evicted && probe {
+			h.evicted = false
+			h.failures = 0
+			log.Printf("[ProxyPool] 代理 %s 探测恢复，重新加入", redactProxy(proxy))
+		}
+		return
+	}
+
+	h.lastError = err.Error()
+	if probe {
+		// 探测地址与实际数据源不同，探测失败不代表代理不可用
+		return
+	}
+	h.failures++
+	h.consecutive++
+	if s, ok := pp.sticky[host]; ok && s.proxy == proxy {
+		delete(pp.sticky, host)
+	}
+	if !h.evicted && h.shouldEvict() {
+		h.evicted = true
+		for d, s := range pp.sticky {
+			if s.proxy == proxy {
+				delete(pp.sticky, d)
+			}
+		}
+		log.Printf("[ProxyPool] 剔除代理 %s: 成功 %d 次，失败 %d 次，最近错误 %v", redactProxy(proxy), h.successes, h.failures, err)
+	}
+}
+
+// ==================== 域名路由规则 ====================
+
+// proxyRouteRule 域名路由规则
+type proxyRouteRule struct {
+	pattern  string // 域名或 *.域名，* 匹配全部
+	mode     string
+	upstream string // mode 为 upstream 时的代理地址
+}
+
+// parseProxyRoutes 解析路由规则，每行一条“域名 目标”或“域名=目标”，# 开头为注释
+// 目标为 direct、proxy、pool 或上游代理地址（http/https/socks5/socks5h，可带用户名密码）
+func parseProxyRoutes(text string) []proxyRouteRule {
+	var rules []proxyRouteRule
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		idx := strings.IndexAny(line, " \t=")
+		if idx <= 0 {
+			log.Printf("[ProxyRoute] 忽略无效规则: %s", line)
+			continue
+		}
+		pattern := strings.TrimPrefix(strings.ToLower(line[:idx]), "*.")
+		target := strings.TrimSpace(strings.TrimLeft(line[idx:], " \t="))
+		if target == "" || strings.ContainsAny(target, " \t") {
+			log.Printf("[ProxyRoute] 忽略无效规则: %s", line)
+			continue
+		}
+
+		rule := proxyRouteRule{pattern: pattern, mode: strings.ToLower(target)}
+		switch rule.mode {
+		case ProxyRouteDirect, ProxyRouteProxy, ProxyRoutePool:
+		default:
+			if !strings.Contains(target, ":") {
+				log.Printf("[ProxyRoute] 忽略未知路由目标: %s", line)
+				continue
+			}
+			upstream, err := parseUpstreamProxy(target)
+			if err != nil {
+				log.Printf("[ProxyRoute] 忽略规则 %s: %v", pattern, err)
+				continue
+			}
+			rule.mode = ProxyRouteUpstream
+			rule.upstream = upstream.String()
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// routeTargetLabel 路由目标的展示文本，隐藏上游代理密码
+func routeTargetLabel(rule proxyRouteRule) string {
+	if rule.mode == ProxyRouteUpstream {
+		return redactProxy(rule.upstream)
+	}
+	return rule.mode
+}
+
+// matchDomainPattern 判断 host 是否匹配 pattern（pattern 本身或其子域名）
+func matchDomainPattern(pattern, host string) bool {
+	if pattern == "*" {
+		return true
+	}
+	return host == pattern || strings.HasSuffix(host, "."+pattern)
+}
+
+// routeFor 返回 host 的路由：最长匹配的规则优先，无匹配时沿用全局代理设置
+func routeFor(rules []proxyRouteRule, cfg *models.Config, host string) proxyRouteRule {
+	host = strings.ToLower(host)
+	matched := -1
+	for i, rule := range rules {
+		if matchDomainPattern(rule.pattern, host) && (matched < 0 || len(rule.pattern) > len(rules[matched].pattern)) {
+			matched = i
+		}
+	}
+	if matched >= 0 {
+		return rules[matched]
+	}
+	if proxyPoolConfigured(cfg) {
+		return proxyRouteRule{pattern: "*", mode: ProxyRoutePool}
+	}
+	if cfg != nil && strings.TrimSpace(cfg.ProxyUrl) != "" {
+		return proxyRouteRule{pattern: "*", mode: ProxyRouteProxy}
+	}
+	return proxyRouteRule{pattern: "*", mode: ProxyRouteDirect}
+}
+
+// proxyPoolConfigured 是否配置了代理池（服务商接口或自定义列表）
+func proxyPoolConfigured(cfg *models.Config) bool {
+	if cfg == nil {
+		return false
+	}
+	return cfg.ProxyPoolEnabled || strings.TrimSpace(cfg.ProxyPoolList) != "" || cfg.ProxyApiUrl != "" || cfg.ProxyApiKey != ""
+}
+
+// proxyConfigKey 代理相关配置的指纹，未变化时保留代理池与健康统计
+func proxyConfigKey(cfg *models.Config) string {
+	if cfg == nil {
+		return ""
+	}
+	return strings.Join([]string{
+		cfg.ProxyUrl, cfg.ProxyUsername, cfg.ProxyPassword, cfg.ProxyRoutes, fmt.Sprint(cfg.ProxyPoolEnabled), cfg.ProxyProvider, cfg.ProxyApiUrl, cfg.ProxyApiKey,
+		cfg.ProxyApiSecret, cfg.ProxyRegion, cfg.ProxyPoolList, cfg.ProxyPoolProtocol,
+		fmt.Sprint(cfg.ProxyPoolTTL), fmt.Sprint(cfg.ProxyPoolSize),
+	}, "\x00")
+}
+
+// ==================== 请求路由 ====================
+
+type proxyContextKey struct{}
+
+// proxyFromContext 作为 http.Transport.Proxy，使用路由层写入请求上下文的代理
+func proxyFromContext(req *http.Request) (*url.URL, error) {
+	proxy, _ := req.Context().Value(proxyContextKey{}).(*url.URL)
+	return proxy, nil
+}
+
+// proxyRoutingTransport 按域名路由规则为请求选择代理，并把代理池请求的结果计入代理得分
+type proxyRoutingTransport struct {
+	rm   *RequestManager
+	base http.RoundTripper // 为nil时使用 rm 当前的底层传输层
+	// envFallback 路由为直连时改用环境变量（HTTP_PROXY/HTTPS_PROXY）中的代理
+	envFallback bool
+}
+
+// RoundTrip 实现 http.RoundTripper
+func (t *proxyRoutingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = t.rm.baseTransport()
+	}
+	host := req.URL.Hostname()
+	proxy, poolKey := t.rm.selectProxy(req.Context(), host)
+	if proxy == nil && t.envFallback {
+		var err error
+		if proxy, err = http.ProxyFromEnvironment(req); err != nil {
+			return nil, err
+		}
+	}
+	if proxy == nil {
+		return base.RoundTrip(req)
+	}
+
+	req = req.WithContext(context.WithValue(req.Context(), proxyContextKey{}, proxy))
+	start := time.Now()
+	resp, err := base.RoundTrip(req)
+	if poolKey == "" || errors.Is(req.Context().Err(), context.Canceled) {
+		return resp, err // 调用方主动取消不计入代理得分
+	}
+
+	result := err
+	if err == nil && resp.StatusCode == http.StatusProxyAuthRequired {
+		result = fmt.Errorf("代理认证失败: %s", resp.Status)
+	}
+	t.rm.reportProxyResult(poolKey, host, time.Since(start), result, false)
+	return resp, err
+}
+
+// selectProxy 按路由表为 host 选择上游代理，poolKey 非空表示代理来自代理池
+func (rm *RequestManager) selectProxy(ctx context.Context, host string) (proxy *url.URL, poolKey string) {
+	rm.mu.RLock()
+	cfg := rm.config
+	rules := rm.proxyRoutes
+	rm.mu.RUnlock()
+
+	var proxyStr string
+	route := routeFor(rules, cfg, host)
+	switch route.mode {
+	case ProxyRouteDirect:
+		return nil, ""
+	case ProxyRouteUpstream:
+		proxyStr = route.upstream
+	case ProxyRouteProxy:
+		if cfg != nil {
+			proxyStr = cfg.ProxyUrl
+		}
+	case ProxyRoutePool:
+		proxyStr = rm.getProxyFromPool(ctx, cfg, host)
+		if proxyPoolConfigured(cfg) {
+			poolKey = proxyStr
+		}
+	}
+	if strings.TrimSpace(proxyStr) == "" {
+		return nil, ""
+	}
+	proxyURL, err := resolveProxyURL(proxyStr, cfg)
+	if err != nil {
+		log.Printf("[ProxyRoute] 代理 %s 无效: %v", redactProxy(proxyStr), err)
+		return nil, ""
+	}
+	return proxyURL, poolKey
+}
+
+// resolveProxyURL 解析代理地址，地址中未带用户名密码时使用配置中的代理认证信息
+func resolveProxyURL(proxyStr string, cfg *models.Config) (*url.URL, error) {
+	proxyURL, err := parseUpstreamProxy(proxyStr)
+	if err != nil {
+		return nil, err
+	}
+	if proxyURL.User == nil && cfg != nil && cfg.ProxyUsername != "" {
+		proxyURL.User = url.UserPassword(cfg.ProxyUsername, cfg.ProxyPassword)
+	}
+	return proxyURL, nil
+}
+
+// ==================== 上游代理 ====================
+//
+// http/https 代理通过 CONNECT 建立 HTTPS 隧道；net/http 对 socks5 与 socks5h 的处理相同，
+// 都把域名交给代理解析，socks5h 仅作为写法兼容保留。
+// 地址中的用户名密码分别用于 Proxy-Authorization 与 SOCKS5 用户名/密码认证。
+
+// normalizeProxyScheme 规范化代理协议名，不支持的协议返回空字符串
+func normalizeProxyScheme(scheme string) string {
+	switch strings.ToLower(strings.TrimSpace(scheme)) {
+	case "http":
+		return "http"
+	case "https":
+		return "https"
+	case "socks", "socks5":
+		return "socks5"
+	case "socks5h":
+		return "socks5h"
+	}
+	return ""
+}
+
+// parseUpstreamProxy 解析上游代理地址，未写协议时按 http 处理
+func parseUpstreamProxy(value string) (*url.URL, error) {
+	value = strings.TrimSpace(value)
+	if !strings.Contains(value, "://") {
+		value = "http://" + value
+	}
+	proxyURL, err := url.Parse(value)
+	if err != nil {
+		return nil, fmt.Errorf("解析代理地址失败: %w", err)
+	}
+	scheme := normalizeProxyScheme(proxyURL.Scheme)
+	if scheme == "" {
+		return nil, fmt.Errorf("不支持的代理协议: %s", proxyURL.Scheme)
+	}
+	if proxyURL.Hostname() == "" {
+		return nil, fmt.Errorf("代理地址缺少主机名")
+	}
+	proxyURL.Scheme = scheme
+	return proxyURL, nil
+}
+
+// newProxyTransport 创建使用路由层所选代理的底层 Transport
+func newProxyTransport() *http.Transport {
+	return &http.Transport{
+		Proxy:           proxyFromContext,
+		TLSClientConfig: &tls.Config{},
+		MaxIdleConns:    100,
+		IdleConnTimeout: 90 * time.Second,
+	}
+}
+
+// NewRoutedClient 创建与数据请求共用代理路由表与连接池的 HTTP 客户端（AI 接口、插件等），
+// 直连时沿用环境变量中的代理；timeout 为单次请求（含读取响应体）的超时，<=0 表示不限时，适用于流式响应
+func NewRoutedClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Transport: withRequestDeadline(&proxyRoutingTransport{rm: GetRequestManager(), envFallback: true}, timeout),
+	}
+}
+
+// ==================== 后台健康探测 ====================
+
+// StartProxyHealthCheck 启动代理池后台健康探测，ctx 取消时停止
+func (rm *RequestManager) StartProxyHealthCheck(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(proxyProbeInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				rm.probeProxies(ctx)
+			}
+		}
+	}()
+}
+
+// probeProxies 并发探测代理池中的全部代理（包括已剔除的）
+func (rm *RequestManager) probeProxies(ctx context.Context) {
+	pp := rm.proxyPool
+	if pp == nil {
+		return
+	}
+	pp.mu.Lock()
+	proxies := append([]string(nil), pp.proxies...)
+	pp.mu.Unlock()
+
+	rm.mu.RLock()
+	cfg := rm.config
+	rm.mu.RUnlock()
+
+	var wg sync.WaitGroup
+	for _, proxy := range proxies {
+		wg.Add(1)
+		go func(proxy string) {
+			defer wg.Done()
+			start := time.Now()
+			err := probeProxy(ctx, proxy, cfg)
+			if ctx.Err() != nil {
+				return
+			}
+			rm.reportProxyResult(proxy, "", time.Since(start), err, true)
+		}(proxy)
+	}
+	wg.Wait()
+}
+
+// probeProxy 经由 proxy 请求探测地址，认证信息与实际请求一致
+func probeProxy(ctx context.Context, proxy string, cfg *models.Config) error {
+	proxyURL, err := resolveProxyURL(proxy, cfg)
+	if err != nil {
+		return err
+	}
+	transport := &http.Transport{Proxy: http.ProxyURL(proxyURL), DisableKeepAlives: true}
+	defer transport.CloseIdleConnections()
+	client := &http.Client{Transport: withRequestDeadline(transport, proxyProbeTimeout)}
+
+	req, err := http.NewRequestWithContext(ctx, "HEAD", proxyProbeURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode == http.StatusProxyAuthRequired || resp.StatusCode >= 500 {
+		return fmt.Errorf("探测失败: %s", resp.Status)
+	}
+	return nil
+}
+
+// proxyHealthStatusUnsafe 各代理的健康状况，按得分从高到低排列（调用方持有 pp.mu）
+func (ps *ProxyPoolState) proxyHealthStatusUnsafe() []models.ProxyHealth {
+	sticky := make(map[string][]string)
+	for host, s := range ps.sticky {
+		sticky[s.proxy] = append(sticky[s.proxy], host)
+	}
+
+	result := make([]models.ProxyHealth, 0, len(ps.proxies))
+	for _, p := range ps.proxies {
+		h := ps.healthUnsafe(p)
+		item := models.ProxyHealth{
+			Proxy:         redactProxy(p),
+			Healthy:       !h.evicted,
+			Score:         h.score(),
+			LatencyMs:     h.latency.Milliseconds(),
+			Successes:     h.successes,
+			Failures:      h.failures,
+			LastError:     h.lastError,
+			StickyDomains: sticky[p],
+		}
+		sort.Strings(item.StickyDomains)
+		if !h.lastChecked.IsZero() {
+			item.LastChecked = h.lastChecked.Format(time.RFC3339)
+		}
+		result = append(result, item)
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Score > result[j].Score })
+	return result
+}
+
+// redactProxy 隐藏代理地址中的密码
+func redactProxy(proxy string) string {
+	if u, err := url.Parse(proxy); err == nil && u.User != nil {
+		return u.Redacted()
+	}
+	return proxy
+}
@@ -0,0 +1,562 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"stock-ai/backend/models"
+)
+
+// ==================== 行情数据源插件化 ====================
+
+// ProviderCapability 数据源能力（位掩码）
+type ProviderCapability int
+
+const (
+	CapQuote  ProviderCapability = 1 << iota // 实时行情
+	CapKLine                                 // K线
+	CapMinute                                // 分时
+)
+
+// Has 判断是否具备指定能力
+func (c ProviderCapability) Has(cap ProviderCapability) bool {
+	return c&cap == cap
+}
+
+// QuoteProvider 行情数据源接口，内置数据源与数据源插件统一实现该接口
+type QuoteProvider interface {
+	// Name 数据源唯一标识，如 sina / tencent / plugin:xxx
+	Name() string
+	// Domain 数据源域名，用于限流与状态展示
+	Domain() string
+	// Capabilities 数据源支持的能力
+	Capabilities() ProviderCapability
+	// FetchQuotes 批量获取实时行情，返回 代码->行情
+	FetchQuotes(ctx context.Context, codes []string) (map[string]*models.StockPrice, error)
+	// FetchKLine 获取K线，period 为 daily/weekly/monthly 或分钟周期
+	FetchKLine(ctx context.Context, code string, period string, count int) ([]models.KLineData, error)
+	// FetchMinute 获取当日分时
+	FetchMinute(ctx context.Context, code string) ([]models.MinuteData, error)
+}
+
+// ErrCapabilityNotSupported 数据源不支持该能力
+var ErrCapabilityNotSupported = fmt.Errorf("数据源不支持该能力")
+
+// FuncQuoteProvider 基于函数的数据源实现，便于把已有的抓取函数包装为 QuoteProvider
+type FuncQuoteProvider struct {
+	ProviderName   string
+	ProviderDomain string
+	Quotes         func(ctx context.Context, codes []string) (map[string]*models.StockPrice, error)
+	KLine          func(ctx context.Context, code string, period string, count int) ([]models.KLineData, error)
+	Minute         func(ctx context.Context, code string) ([]models.MinuteData, error)
+}
+
+// Name 数据源标识
+func (p *FuncQuoteProvider) Name() string { return p.ProviderName }
+
+// Domain 数据源域名
+func (p *FuncQuoteProvider) Domain() string { return p.ProviderDomain }
+
+// Capabilities 根据已设置的函数推导能力
+func (p *FuncQuoteProvider) Capabilities() ProviderCapability {
+	var caps ProviderCapability
+	if p.Quotes != nil {
+		caps |= CapQuote
+	}
+	if p.KLine != nil {
+		caps |= CapKLine
+	}
+	if p.Minute != nil {
+		caps |= CapMinute
+	}
+	return caps
+}
+
+// FetchQuotes 获取实时行情
+func (p *FuncQuoteProvider) FetchQuotes(ctx context.Context, codes []string) (map[string]*models.StockPrice, error) {
+	if p.Quotes == nil {
+		return nil, ErrCapabilityNotSupported
+	}
+	return p.Quotes(ctx, codes)
+}
+
+// FetchKLine 获取K线
+func (p *FuncQuoteProvider) FetchKLine(ctx context.Context, code string, period string, count int) ([]models.KLineData, error) {
+	if p.KLine == nil {
+		return nil, ErrCapabilityNotSupported
+	}
+	return p.KLine(ctx, code, period, count)
+}
+
+// FetchMinute 获取分时
+func (p *FuncQuoteProvider) FetchMinute(ctx context.Context, code string) ([]models.MinuteData, error) {
+	if p.Minute == nil {
+		return nil, ErrCapabilityNotSupported
+	}
+	return p.Minute(ctx, code)
+}
+
+// registeredProvider 已注册的数据源及其健康信息
+type registeredProvider struct {
+	provider QuoteProvider
+	info     *DataSourceInfo
+	builtin  bool // 健康信息与 MultiSourceManager.sources 共享
+}
+
+// 内置数据源与 DataSource 枚举的对应关系，共享同一份健康信息
+var builtinProviderSources = map[string]DataSource{
+	"eastmoney": SourceEastmoney,
+	"sina":      SourceSina,
+	"tencent":   SourceTencent,
+	"netease":   Source163,
+	"xueqiu":    SourceXueqiu,
+}
+
+const (
+	quoteHedgeDelay  = 800 * time.Millisecond // 对冲请求间隔：最优数据源超过该时间未返回则追加下一个
+	providerCooldown = 30 * time.Second       // 被禁用数据源的冷却时间
+)
+
+// RegisterQuoteProvider 注册行情数据源，priority 越小越优先（健康分相同时生效）
+// 同名数据源重复注册时替换旧实例，保留健康统计
+func (msm *MultiSourceManager) RegisterQuoteProvider(p QuoteProvider, priority int) {
+	msm.mu.Lock()
+	defer msm.mu.Unlock()
+
+	name := p.Name()
+	if existing, ok := msm.providers[name]; ok {
+		existing.provider = p
+		existing.info.Domain = p.Domain()
+		if !existing.builtin {
+			existing.info.Priority = priority
+		}
+		return
+	}
+
+	entry := &registeredProvider{provider: p}
+	if source, ok := builtinProviderSources[name]; ok {
+		if info, ok := msm.sources[source]; ok {
+			entry.info = info
+			entry.builtin = true
+		}
+	}
+	if entry.info == nil {
+		entry.info = &DataSourceInfo{Name: name, Domain: p.Domain(), Priority: priority}
+	}
+	msm.providers[name] = entry
+}
+
+// UnregisterQuoteProvider 注销行情数据源
+func (msm *MultiSourceManager) UnregisterQuoteProvider(name string) {
+	msm.mu.Lock()
+	defer msm.mu.Unlock()
+	delete(msm.providers, name)
+}
+
+// QuoteProviderNames 获取已注册的数据源名称（按当前健康排序）
+func (msm *MultiSourceManager) QuoteProviderNames() []string {
+	ranked := msm.rankProviders(0)
+	names := make([]string, 0, len(ranked))
+	for _, p := range ranked {
+		names = append(names, p.Name())
+	}
+	return names
+}
+
+// GetQuoteProvider 按名称获取数据源
+func (msm *MultiSourceManager) GetQuoteProvider(name string) (QuoteProvider, bool) {
+	msm.mu.RLock()
+	defer msm.mu.RUnlock()
+	entry, ok := msm.providers[name]
+	if !ok {
+		return nil, false
+	}
+	return entry.provider, true
+}
+
+// healthScore 根据数据源健康信息计算得分（0~100），冷却期内被禁用的数据源得0分
+func healthScore(info *DataSourceInfo, now time.Time) float64 {
+	if info.Disabled && now.Sub(info.LastFail) < providerCooldown {
+		return 0
+	}
+
+	score := 100.0
+	score -= float64(info.FailCount) * 20
+	if info.LastError != "" {
+		score -= 10
+	}
+	switch {
+	case info.LastLatency <= 0:
+	case info.LastLatency < 80*time.Millisecond:
+	case info.LastLatency < 200*time.Millisecond:
+		score -= 5
+	case info.LastLatency < 500*time.Millisecond:
+		score -= 10
+	default:
+		score -= 20
+	}
+	// 未被禁用的数据源始终保留最低分，排在末尾兜底
+	if score < 1 {
+		score = 1
+	}
+	return score
+}
+
+// rankProviders 按健康分降序、优先级升序返回具备指定能力的数据源；cap 为0时返回全部
+func (msm *MultiSourceManager) rankProviders(cap ProviderCapability) []QuoteProvider {
+	msm.mu.RLock()
+	defer msm.mu.RUnlock()
+
+	now := time.Now()
+	type candidate struct {
+		provider QuoteProvider
+		score    float64
+		priority int
+	}
+	var candidates []candidate
+	for _, entry := range msm.providers {
+		if cap != 0 && !entry.provider.Capabilities().Has(cap) {
+			continue
+		}
+		candidates = append(candidates, candidate{
+			provider: entry.provider,
+			score:    healthScore(entry.info, now),
+			priority: entry.info.Priority,
+		})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].score != candidates[j].score {
+			return candidates[i].score > candidates[j].score
+		}
+		if candidates[i].priority != candidates[j].priority {
+			return candidates[i].priority < candidates[j].priority
+		}
+		return candidates[i].provider.Name() < candidates[j].provider.Name()
+	})
+
+	// 行情字段结构异常的数据源不参与调度，全部异常时保留原排序兜底
+	if cap == CapQuote {
+		healthy := candidates[:0:0]
+		for _, c := range candidates {
+			if !msm.schema.IsBroken(c.provider.Name(), SchemaDatasetQuote) {
+				healthy = append(healthy, c)
+			}
+		}
+		if len(healthy) > 0 {
+			candidates = healthy
+		}
+	}
+
+	result := make([]QuoteProvider, 0, len(candidates))
+	for _, c := range candidates {
+		result = append(result, c.provider)
+	}
+	return result
+}
+
+// recordProviderResult 记录一次数据源调用结果，更新健康信息
+func (msm *MultiSourceManager) recordProviderResult(name string, start time.Time, err error) {
+	msm.mu.Lock()
+	defer msm.mu.Unlock()
+
+	entry, ok := msm.providers[name]
+	if !ok {
+		return
+	}
+	info := entry.info
+
+	now := time.Now()
+	info.LastChecked = now
+	info.LastLatency = now.Sub(start)
+	if err == nil {
+		info.LastError = ""
+		info.LastSuccess = now
+		info.FailCount = 0
+		info.Disabled = false
+		return
+	}
+
+	info.LastError = err.Error()
+	info.FailCount++
+	info.LastFail = now
+	// 连续失败3次，进入冷却
+	if info.FailCount >= 3 {
+		info.Disabled = true
+	}
+}
+
+// FetchQuotes 获取实时行情（按健康分对冲请求，谁先成功用谁）
+func (msm *MultiSourceManager) FetchQuotes(ctx context.Context, codes []string) (map[string]*models.StockPrice, string, error) {
+	return msm.FetchQuotesWithTimeout(ctx, codes, 0)
+}
+
+// FetchQuotesWithTimeout 获取实时行情，timeout<=0 表示不设整体超时
+// 首次加载时所有数据源同时发起；之后先请求健康分最高的数据源，
+// 超过 quoteHedgeDelay 未返回或失败时再追加下一个数据源
+func (msm *MultiSourceManager) FetchQuotesWithTimeout(ctx context.Context, codes []string, timeout time.Duration) (map[string]*models.StockPrice, string, error) {
+	if len(codes) == 0 {
+		return nil, "", nil
+	}
+
+	candidates := msm.rankProviders(CapQuote)
+	if len(candidates) == 0 {
+		return nil, "", fmt.Errorf("没有可用的行情数据源")
+	}
+
+	type result struct {
+		data map[string]*models.StockPrice
+		name string
+		err  error
+	}
+
+	delay := quoteHedgeDelay
+	if msm.IsFirstLoad() {
+		delay = 0
+	}
+
+	results := make(chan result, len(candidates))
+	next, inflight := 0, 0
+	launch := func() {
+		p := candidates[next]
+		next++
+		inflight++
+		go func() {
+			start := time.Now()
+			data, err := safeFetchQuotes(ctx, p, codes)
+			if err == nil && len(data) == 0 {
+				err = fmt.Errorf("%s返回空数据", p.Name())
+			}
+			msm.recordProviderResult(p.Name(), start, err)
+			results <- result{data: data, name: p.Name(), err: err}
+		}()
+	}
+
+	launch()
+	hedge := time.NewTimer(delay)
+	defer hedge.Stop()
+
+	var deadline <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+
+	var lastErr error
+	for inflight > 0 {
+		select {
+		case res := <-results:
+			inflight--
+			if res.err == nil {
+				msm.SetFirstLoadComplete()
+				return msm.validateQuotes(ctx, res.name, res.data, codes, candidates), res.name, nil
+			}
+			lastErr = res.err
+			if next < len(candidates) {
+				launch()
+				hedge.Reset(delay)
+			}
+		case <-hedge.C:
+			if next < len(candidates) {
+				launch()
+				hedge.Reset(delay)
+			}
+		case <-deadline:
+			if lastErr == nil {
+				lastErr = fmt.Errorf("所有数据源均超时")
+			}
+			return nil, "", lastErr
+		}
+	}
+
+	return nil, "", fmt.Errorf("所有数据源均失败: %v", lastErr)
+}
+
+// safeFetchQuotes 调用数据源并兜住panic，避免单个数据源拖垮调度
+func safeFetchQuotes(ctx context.Context, p QuoteProvider, codes []string) (data map[string]*models.StockPrice, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%s panic: %v", p.Name(), r)
+		}
+	}()
+	return p.FetchQuotes(ctx, codes)
+}
+
+// validateQuotes 校验行情；启用双源比对时向另一个健康数据源补充请求一次
+func (msm *MultiSourceManager) validateQuotes(ctx context.Context, primaryName string, primary map[string]*models.StockPrice, codes []string, candidates []QuoteProvider) map[string]*models.StockPrice {
+	if !msm.validator.Enabled() {
+		return msm.validator.Validate(primaryName, primary, "", nil)
+	}
+
+	var secondary QuoteProvider
+	for _, p := range candidates {
+		// 插件逐个代码请求，开销较大，不作为校验源
+		if p.Name() != primaryName && !IsPluginProvider(p.Name()) {
+			secondary = p
+			break
+		}
+	}
+	if secondary == nil {
+		return msm.validator.Validate(primaryName, primary, "", nil)
+	}
+
+	type result struct {
+		data map[string]*models.StockPrice
+		err  error
+	}
+	ch := make(chan result, 1)
+	go func() {
+		start := time.Now()
+		data, err := safeFetchQuotes(ctx, secondary, codes)
+		msm.recordProviderResult(secondary.Name(), start, err)
+		ch <- result{data: data, err: err}
+	}()
+
+	var secondaryData map[string]*models.StockPrice
+	select {
+	case res := <-ch:
+		if res.err == nil {
+			secondaryData = res.data
+		}
+	case <-time.After(quoteSecondaryTimeout):
+	}
+	return msm.validator.Validate(primaryName, primary, secondary.Name(), secondaryData)
+}
+
+// SetQuoteValidation 启用/关闭行情双源比对
+func (msm *MultiSourceManager) SetQuoteValidation(enabled bool) {
+	msm.validator.SetEnabled(enabled)
+}
+
+// GetQuoteValidationStats 获取行情校验统计
+func (msm *MultiSourceManager) GetQuoteValidationStats() models.QuoteValidationStats {
+	return msm.validator.Stats()
+}
+
+// FetchKLine 获取K线（按健康分依次回退）
+func (msm *MultiSourceManager) FetchKLine(ctx context.Context, code string, period string, count int) ([]models.KLineData, string, error) {
+	var lastErr error
+	for _, p := range msm.rankProviders(CapKLine) {
+		start := time.Now()
+		klines, err := p.FetchKLine(ctx, code, period, count)
+		if err == nil && len(klines) == 0 {
+			err = fmt.Errorf("%s返回空K线", p.Name())
+		}
+		msm.recordProviderResult(p.Name(), start, err)
+		if err == nil {
+			return klines, p.Name(), nil
+		}
+		lastErr = err
+		log.Printf("[KLine] %s数据源失败(%s %s): %v", p.Name(), code, period, err)
+	}
+
+	if lastErr == nil {
+		lastErr = fmt.Errorf("没有可用的K线数据源")
+	}
+	return nil, "", lastErr
+}
+
+// FetchMinute 获取分时（按健康分依次回退）
+func (msm *MultiSourceManager) FetchMinute(ctx context.Context, code string) ([]models.MinuteData, string, error) {
+	var lastErr error
+	for _, p := range msm.rankProviders(CapMinute) {
+		start := time.Now()
+		minutes, err := p.FetchMinute(ctx, code)
+		if err == nil && len(minutes) == 0 {
+			err = fmt.Errorf("%s返回空分时", p.Name())
+		}
+		msm.recordProviderResult(p.Name(), start, err)
+		if err == nil {
+			return minutes, p.Name(), nil
+		}
+		lastErr = err
+		log.Printf("[Minute] %s数据源失败(%s): %v", p.Name(), code, err)
+	}
+
+	if lastErr == nil {
+		lastErr = fmt.Errorf("没有可用的分时数据源")
+	}
+	return nil, "", lastErr
+}
+
+// FetchQuotesFrom 从指定数据源获取实时行情（不参与对冲，仍记录健康信息）
+func (msm *MultiSourceManager) FetchQuotesFrom(ctx context.Context, name string, codes []string) (map[string]*models.StockPrice, error) {
+	p, ok := msm.GetQuoteProvider(name)
+	if !ok {
+		return nil, fmt.Errorf("未注册的数据源: %s", name)
+	}
+	start := time.Now()
+	data, err := safeFetchQuotes(ctx, p, codes)
+	msm.recordProviderResult(name, start, err)
+	return data, err
+}
+
+// ==================== 内置数据源注册 ====================
+
+var builtinProvidersOnce sync.Once
+
+// registerBuiltinQuoteProviders 注册内置数据源（新浪/腾讯/东方财富等）
+func registerBuiltinQuoteProviders(msm *MultiSourceManager, api *StockAPI) {
+	builtinProvidersOnce.Do(func() {
+		addBuiltinQuoteProviders(msm, api)
+	})
+}
+
+// addBuiltinQuoteProviders 向 msm 注册内置数据源，不做去重（测试中用于构造独立的管理器）
+func addBuiltinQuoteProviders(msm *MultiSourceManager, api *StockAPI) {
+	msm.RegisterQuoteProvider(&FuncQuoteProvider{
+		ProviderName:   "eastmoney",
+		ProviderDomain: "eastmoney.com",
+		Quotes:         msm.FetchAStockFromEastmoney,
+		KLine:          api.getKLineFromEastMoney,
+	}, 1)
+	msm.RegisterQuoteProvider(&FuncQuoteProvider{
+		ProviderName:   "sina",
+		ProviderDomain: "sina.com.cn",
+		Quotes:         msm.FetchAStockFromSina,
+		KLine:          api.getKLineFromSina,
+	}, 2)
+	msm.RegisterQuoteProvider(&FuncQuoteProvider{
+		ProviderName:   "tencent",
+		ProviderDomain: "qq.com",
+		Quotes:         msm.FetchAStockFromTencent,
+		KLine:          api.getKLineFromTencent,
+		Minute:         api.getMinuteFromTencent,
+	}, 3)
+	msm.RegisterQuoteProvider(&FuncQuoteProvider{
+		ProviderName:   "netease",
+		ProviderDomain: "126.net",
+		Quotes:         api.GetStockPriceFromNetease,
+	}, 4)
+	msm.RegisterQuoteProvider(&FuncQuoteProvider{
+		ProviderName:   "xueqiu",
+		ProviderDomain: "xueqiu.com",
+		Quotes:         api.GetStockPriceFromXueqiu,
+	}, 5)
+	msm.RegisterQuoteProvider(&FuncQuoteProvider{
+		ProviderName:   "sohu",
+		ProviderDomain: "sohu.com",
+		Quotes:         api.GetStockPriceFromSohu,
+	}, 7)
+	msm.RegisterQuoteProvider(&FuncQuoteProvider{
+		ProviderName:   "baidu",
+		ProviderDomain: "baidu.com",
+		Quotes:         api.GetStockPriceFromBaidu,
+	}, 8)
+	msm.RegisterQuoteProvider(&FuncQuoteProvider{
+		ProviderName:   "hexun",
+		ProviderDomain: "hexun.com",
+		Quotes:         api.GetStockPriceFromHexun,
+	}, 9)
+}
+
+// IsPluginProvider 判断数据源是否来自插件
+func IsPluginProvider(name string) bool {
+	return strings.HasPrefix(name, "plugin:")
+}
@@ -0,0 +1,377 @@
+package data
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"stock-ai/backend/models"
+)
+
+// ==================== 行情交叉校验 ====================
+
+// 行情异常类型
+const (
+	QuoteFlagInvalidPrice     = "invalid_price"     // 价格非正数
+	QuoteFlagOutOfLimit       = "out_of_limit"      // 超出涨跌停范围
+	QuoteFlagRangeMismatch    = "range_mismatch"    // 现价不在最高最低价之间
+	QuoteFlagStale            = "stale"             // 行情时间过旧
+	QuoteFlagZeroVolume       = "zero_volume"       // 交易时段成交量为0
+	QuoteFlagPriceMismatch    = "price_mismatch"    // 双源现价差异过大
+	QuoteFlagPreCloseMismatch = "preclose_mismatch" // 双源昨收不一致
+	QuoteFlagDecimalShift     = "decimal_shift"     // 双源价格相差10倍/100倍，疑似小数点错位
+)
+
+// 严重异常：出现即视为不可信，若另一数据源正常则直接替换
+var hardQuoteFlags = map[string]bool{
+	QuoteFlagInvalidPrice:  true,
+	QuoteFlagOutOfLimit:    true,
+	QuoteFlagRangeMismatch: true,
+	QuoteFlagStale:         true,
+	QuoteFlagDecimalShift:  true,
+}
+
+const (
+	quoteStaleAfter          = 3 * time.Minute         // 交易时段内行情时间落后超过该值视为过旧
+	quotePriceMismatchPct    = 1.0                     // 双源现价差异阈值（%）
+	quotePreCloseMismatchPct = 0.5                     // 双源昨收差异阈值（%）
+	quoteSecondaryTimeout    = 1500 * time.Millisecond // 校验源请求超时
+	quoteRecentLimit         = 20                      // 保留的最近异常记录条数
+)
+
+// QuoteValidator 行情校验器：单源合理性检查 + 可选的双源比对
+type QuoteValidator struct {
+	mu      sync.Mutex
+	enabled bool
+	stats   models.QuoteValidationStats
+}
+
+// NewQuoteValidator 创建行情校验器
+func NewQuoteValidator() *QuoteValidator {
+	return &QuoteValidator{
+		stats: models.QuoteValidationStats{
+			Reasons:     make(map[string]int64),
+			SourceFlags: make(map[string]int64),
+		},
+	}
+}
+
+// SetEnabled 启用/关闭双源比对（单源合理性检查始终开启）
+func (v *QuoteValidator) SetEnabled(enabled bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.enabled = enabled
+}
+
+// Enabled 是否启用双源比对
+func (v *QuoteValidator) Enabled() bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.enabled
+}
+
+// Stats 获取校验统计快照
+func (v *QuoteValidator) Stats() models.QuoteValidationStats {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	stats := v.stats
+	stats.Enabled = v.enabled
+	stats.Reasons = make(map[string]int64, len(v.stats.Reasons))
+	for k, n := range v.stats.Reasons {
+		stats.Reasons[k] = n
+	}
+	stats.SourceFlags = make(map[string]int64, len(v.stats.SourceFlags))
+	for k, n := range v.stats.SourceFlags {
+		stats.SourceFlags[k] = n
+	}
+	stats.Recent = append([]models.QuoteDiscrepancy(nil), v.stats.Recent...)
+	return stats
+}
+
+// record 记录一条异常
+func (v *QuoteValidator) record(d models.QuoteDiscrepancy) {
+	d.Time = time.Now().Format("2006-01-02 15:04:05")
+	v.stats.Reasons[d.Reason]++
+	if d.Source != "" {
+		v.stats.SourceFlags[d.Source]++
+	}
+	v.stats.Recent = append(v.stats.Recent, d)
+	if len(v.stats.Recent) > quoteRecentLimit {
+		v.stats.Recent = v.stats.Recent[len(v.stats.Recent)-quoteRecentLimit:]
+	}
+	log.Printf("[行情校验] %s %s(%s): %s", d.Code, d.Reason, d.Source, d.Detail)
+}
+
+// quoteIssue 单条校验问题
+type quoteIssue struct {
+	flag   string
+	detail string
+}
+
+func hasHardIssue(issues []quoteIssue) bool {
+	for _, i := range issues {
+		if hardQuoteFlags[i.flag] {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate 校验一批行情；secondary 为空时只做单源检查
+// 返回的行情已合并：主源严重异常而校验源正常时使用校验源数据
+func (v *QuoteValidator) Validate(primaryName string, primary map[string]*models.StockPrice, secondaryName string, secondary map[string]*models.StockPrice) map[string]*models.StockPrice {
+	now := time.Now().In(quoteLocation)
+	trading := IsTradingTime()
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	result := make(map[string]*models.StockPrice, len(primary))
+	for code, p := range primary {
+		if p == nil {
+			continue
+		}
+		v.stats.Checked++
+		issues := checkQuoteSanity(code, p, now, trading)
+
+		// 标记写在副本上，不修改数据源返回的行情
+		copied := *p
+		s := secondary[code]
+		if s == nil {
+			result[code] = v.applyIssues(code, &copied, primaryName, "", 0, issues)
+			continue
+		}
+
+		v.stats.CrossChecked++
+		secondaryIssues := checkQuoteSanity(code, s, now, trading)
+		crossIssues := compareQuotes(p, s)
+
+		switch {
+		case hasHardIssue(issues) && !hasHardIssue(secondaryIssues):
+			// 主源不可信，采用校验源
+			v.stats.Rejected++
+			for _, i := range issues {
+				v.record(models.QuoteDiscrepancy{
+					Code: code, Reason: i.flag, Detail: i.detail, Source: primaryName, Compare: secondaryName,
+					Price: p.Price, ComparePrice: s.Price, Action: "rejected",
+				})
+			}
+			replaced := *s
+			replaced.Source = secondaryName
+			result[code] = &replaced
+		case hasHardIssue(secondaryIssues) && !hasHardIssue(issues):
+			// 校验源不可信，保留主源
+			for _, i := range secondaryIssues {
+				v.record(models.QuoteDiscrepancy{
+					Code: code, Reason: i.flag, Detail: i.detail, Source: secondaryName, Compare: primaryName,
+					Price: s.Price, ComparePrice: p.Price, Action: "rejected",
+				})
+			}
+			result[code] = v.applyIssues(code, &copied, primaryName, secondaryName, s.Price, crossIssues)
+		default:
+			chosenName, otherPrice := primaryName, s.Price
+			// 双源不一致时优先采用时间更新的一方
+			if len(crossIssues) > 0 && !hasHardIssue(issues) && quoteTimeAfter(s.UpdateTime, p.UpdateTime, now) {
+				copied, chosenName, otherPrice = *s, secondaryName, p.Price
+			}
+			result[code] = v.applyIssues(code, &copied, chosenName, "", otherPrice, append(issues, crossIssues...))
+		}
+	}
+	return result
+}
+
+// applyIssues 将问题写入行情标记并计入统计
+func (v *QuoteValidator) applyIssues(code string, p *models.StockPrice, source, compare string, comparePrice float64, issues []quoteIssue) *models.StockPrice {
+	if p.Source == "" {
+		p.Source = source
+	}
+	if len(issues) == 0 {
+		return p
+	}
+
+	// 副本与原行情共享 QualityFlags 底层数组，追加前先截断容量
+	p.QualityFlags = p.QualityFlags[:len(p.QualityFlags):len(p.QualityFlags)]
+	suspect := false
+	for _, i := range issues {
+		p.QualityFlags = append(p.QualityFlags, i.flag)
+		// 双源比对的差异同样视为可疑，避免错误行情触发提醒
+		if hardQuoteFlags[i.flag] || i.flag == QuoteFlagPreCloseMismatch {
+			suspect = true
+		}
+		v.record(models.QuoteDiscrepancy{
+			Code: code, Reason: i.flag, Detail: i.detail, Source: source, Compare: compare,
+			Price: p.Price, ComparePrice: comparePrice, Action: "flagged",
+		})
+	}
+	if suspect {
+		p.Suspect = true
+		v.stats.Flagged++
+	}
+	return p
+}
+
+// checkQuoteSanity 单源合理性检查
+func checkQuoteSanity(code string, p *models.StockPrice, now time.Time, trading bool) []quoteIssue {
+	var issues []quoteIssue
+
+	// 停牌股票现价为0属正常情况，不做价格检查
+	if p.Price <= 0 {
+		if p.PreClose <= 0 {
+			issues = append(issues, quoteIssue{QuoteFlagInvalidPrice, "现价与昨收均为0"})
+		}
+		return issues
+	}
+
+	if limit := priceLimitPct(code, p.Name); limit > 0 && p.PreClose > 0 {
+		// 涨跌停价按分位四舍五入，留出1分钱容差
+		maxMove := p.PreClose*limit + 0.011
+		if math.Abs(p.Price-p.PreClose) > maxMove {
+			issues = append(issues, quoteIssue{QuoteFlagOutOfLimit,
+				fmt.Sprintf("现价 %.2f 超出昨收 %.2f 的 ±%.0f%% 涨跌幅限制", p.Price, p.PreClose, limit*100)})
+		}
+	}
+
+	if p.High > 0 && p.Low > 0 {
+		tolerance := p.Price * 0.001
+		if p.High+tolerance < p.Low || p.Price > p.High+tolerance || p.Price < p.Low-tolerance {
+			issues = append(issues, quoteIssue{QuoteFlagRangeMismatch,
+				fmt.Sprintf("现价 %.2f 不在 %.2f ~ %.2f 区间内", p.Price, p.Low, p.High)})
+		}
+	}
+
+	if trading {
+		if t, ok := parseQuoteTime(p.UpdateTime, now); ok {
+			if lag := tradingLag(t, now); lag > quoteStaleAfter {
+				issues = append(issues, quoteIssue{QuoteFlagStale,
+					fmt.Sprintf("行情时间 %s 落后当前 %s", p.UpdateTime, lag.Round(time.Second))})
+			}
+		}
+		if p.Volume == 0 && !isIndexCode(code) {
+			issues = append(issues, quoteIssue{QuoteFlagZeroVolume, "交易时段成交量为0"})
+		}
+	}
+
+	return issues
+}
+
+// compareQuotes 双源比对
+func compareQuotes(a, b *models.StockPrice) []quoteIssue {
+	var issues []quoteIssue
+	if a.Price <= 0 || b.Price <= 0 {
+		return issues
+	}
+
+	ratio := a.Price / b.Price
+	for _, shift := range []float64{10, 100, 0.1, 0.01} {
+		if math.Abs(ratio/shift-1) < 0.02 {
+			return append(issues, quoteIssue{QuoteFlagDecimalShift,
+				fmt.Sprintf("双源现价 %.4f / %.4f 相差约 %.0f 倍", a.Price, b.Price, math.Max(ratio, 1/ratio))})
+		}
+	}
+
+	if diff := math.Abs(a.Price-b.Price) / b.Price * 100; diff > quotePriceMismatchPct {
+		issues = append(issues, quoteIssue{QuoteFlagPriceMismatch,
+			fmt.Sprintf("双源现价 %.2f / %.2f 相差 %.2f%%", a.Price, b.Price, diff)})
+	}
+	if a.PreClose > 0 && b.PreClose > 0 {
+		if diff := math.Abs(a.PreClose-b.PreClose) / b.PreClose * 100; diff > quotePreCloseMismatchPct {
+			issues = append(issues, quoteIssue{QuoteFlagPreCloseMismatch,
+				fmt.Sprintf("双源昨收 %.2f / %.2f 不一致，可能有一方仍是上一交易日数据", a.PreClose, b.PreClose)})
+		}
+	}
+	return issues
+}
+
+// priceLimitPct 按板块返回涨跌幅限制，返回0表示不做检查（指数、新股等）
+func priceLimitPct(code, name string) float64 {
+	code = strings.ToLower(code)
+	if isIndexCode(code) {
+		return 0
+	}
+	// 上市首日等不设涨跌幅的新股（名称以N/C开头）
+	if strings.HasPrefix(name, "N") || strings.HasPrefix(name, "C") {
+		return 0
+	}
+
+	digits := strings.TrimLeft(code, "shzbj")
+	switch {
+	case strings.HasPrefix(code, "bj"), strings.HasPrefix(digits, "8"), strings.HasPrefix(digits, "4"), strings.HasPrefix(digits, "92"):
+		return 0.30
+	case strings.HasPrefix(digits, "688"), strings.HasPrefix(digits, "689"), strings.HasPrefix(digits, "300"), strings.HasPrefix(digits, "301"):
+		return 0.20
+	case strings.HasPrefix(digits, "11"), strings.HasPrefix(digits, "12"):
+		// 可转债
+		return 0.20
+	case strings.Contains(strings.ToUpper(name), "ST"):
+		return 0.05
+	default:
+		return 0.10
+	}
+}
+
+// isIndexCode 判断是否为指数代码
+func isIndexCode(code string) bool {
+	code = strings.ToLower(code)
+	return strings.HasPrefix(code, "sh000") || strings.HasPrefix(code, "sh880") || strings.HasPrefix(code, "sz399")
+}
+
+// quoteLocation A股行情时间统一按北京时间解析
+var quoteLocation = func() *time.Location {
+	if loc, err := time.LoadLocation("Asia/Shanghai"); err == nil {
+		return loc
+	}
+	return time.FixedZone("CST", 8*3600)
+}()
+
+// MarketLocation 返回A股所在时区（北京时间），供按交易日归档的数据统一使用
+func MarketLocation() *time.Location {
+	return quoteLocation
+}
+
+// tradingLag 计算行情时间落后当前的交易时长，扣除午间休市
+func tradingLag(t, now time.Time) time.Duration {
+	lag := now.Sub(t)
+	morningClose := time.Date(now.Year(), now.Month(), now.Day(), 11, 30, 59, 0, now.Location())
+	afternoonOpen := time.Date(now.Year(), now.Month(), now.Day(), 13, 0, 0, 0, now.Location())
+	if !t.After(morningClose) && !now.Before(afternoonOpen) && t.After(morningClose.Add(-3*time.Hour)) {
+		lag -= afternoonOpen.Sub(morningClose)
+	}
+	return lag
+}
+
+// parseQuoteTime 解析行情时间，支持完整时间与仅时分秒两种格式
+func parseQuoteTime(s string, now time.Time) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, false
+	}
+	loc := now.Location()
+	for _, layout := range []string{"2006-01-02 15:04:05", "2006/01/02 15:04:05", "20060102150405"} {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, true
+		}
+	}
+	if t, err := time.ParseInLocation("15:04:05", s, loc); err == nil {
+		return time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), t.Second(), 0, loc), true
+	}
+	return time.Time{}, false
+}
+
+// quoteTimeAfter 判断行情时间 a 是否晚于 b，无法解析时返回 false
+func quoteTimeAfter(a, b string, now time.Time) bool {
+	ta, okA := parseQuoteTime(a, now)
+	tb, okB := parseQuoteTime(b, now)
+	return okA && okB && ta.After(tb)
+}
+
+// QuoteQualityNote 生成行情可信度提示，供AI提示词使用；行情正常时返回空字符串
+func QuoteQualityNote(p *models.StockPrice) string {
+	if p == nil || !p.Suspect {
+		return ""
+	}
+	return fmt.Sprintf("- 注意：该行情未通过数据校验（%s），价格可能不准确，请勿据此给出具体价位\n", strings.Join(p.QualityFlags, "、"))
+}
@@ -0,0 +1,40 @@
+package data
+
+import (
+	"testing"
+
+	"stock-ai/backend/models"
+)
+
+func TestQuoteValidatorReplacesBrokenPrimary(t *testing.T) {
+	v := NewQuoteValidator()
+	// 同时超出涨跌停与最高最低价区间
+	primary := &models.StockPrice{Code: "sh600000", Name: "浦发银行", Price: 20, PreClose: 10, High: 12, Low: 9.8, Volume: 1000}
+	secondary := &models.StockPrice{Code: "sh600000", Name: "浦发银行", Price: 10.5, PreClose: 10, High: 11, Low: 9.8, Volume: 1000}
+
+	result := v.Validate("sina", map[string]*models.StockPrice{"sh600000": primary}, "tencent", map[string]*models.StockPrice{"sh600000": secondary})
+	if got := result["sh600000"]; got == nil || got.Price != 10.5 || got.Source != "tencent" {
+		t.Fatalf("result = %+v", got)
+	}
+	stats := v.Stats()
+	if stats.Rejected != 1 || stats.Reasons[QuoteFlagOutOfLimit] != 1 || stats.Reasons[QuoteFlagRangeMismatch] != 1 {
+		t.Fatalf("一条行情被替换应只计一次: %+v", stats)
+	}
+}
+
+func TestQuoteValidatorDoesNotMutateProviderQuote(t *testing.T) {
+	v := NewQuoteValidator()
+	primary := &models.StockPrice{Code: "sh600000", Name: "浦发银行", Price: 20, PreClose: 10, Volume: 1000, QualityFlags: make([]string, 0, 4)}
+
+	result := v.Validate("sina", map[string]*models.StockPrice{"sh600000": primary}, "", nil)
+	got := result["sh600000"]
+	if got == primary || !got.Suspect || len(got.QualityFlags) != 1 || got.Source != "sina" {
+		t.Fatalf("result = %+v", got)
+	}
+	if primary.Suspect || primary.Source != "" || len(primary.QualityFlags) != 0 {
+		t.Fatalf("数据源返回的行情被修改: %+v", primary)
+	}
+	if stats := v.Stats(); stats.Rejected != 0 || stats.Flagged != 1 {
+		t.Fatalf("stats = %+v", stats)
+	}
+}
@@ -1,6 +1,7 @@
 package data
 
 import (
+	"context"
 	"crypto/rand"
 	"log"
 	"math/big"
@@ -18,11 +19,16 @@ type RateLimiter struct {
 	// 每个域名的配置
 	domainConfigs map[string]*DomainConfig
 
-	// 全局请求队列
-	requestQueue chan *QueuedRequest
+	// 每个域名根据服务端反馈调整的自适应状态
+	adaptive map[string]*adaptiveState
 
-	// 是否正在处理队列
-	processing bool
+	// 每个域名的优先级等待队列与令牌桶
+	lanes map[string]*domainLanes
+	seq   uint64
+
+	// 关闭后排队中的请求全部放弃
+	closed    chan struct{}
+	closeOnce sync.Once
 }
 
 // DomainConfig 域名配置
@@ -51,13 +57,6 @@ type DomainConfig struct {
 	CooldownEndTime time.Time
 }
 
-// QueuedRequest 队列中的请求
-type QueuedRequest struct {
-	Domain   string
-	Callback func() error
-	Result   chan error
-}
-
 // 默认域名配置
 var defaultDomainConfigs = map[string]*DomainConfig{
 	// 东方财富 - 最严格的限制
@@ -190,6 +189,46 @@ var defaultDomainConfigs = map[string]*DomainConfig{
 		CooldownAfterBurst:   15,
 		BurstThreshold:       10,
 	},
+	// 币安 - 公开行情接口，按权重限流，限制宽松
+	"api.binance.com": {
+		MaxRequestsPerMinute: 30,
+		MaxRequestsPerHour:   600,
+		MinIntervalMs:        500,
+		MaxIntervalMs:        1500,
+		RandomDelay:          true,
+		CooldownAfterBurst:   15,
+		BurstThreshold:       20,
+	},
+	// OKX - 公开行情接口，每2秒20次
+	"www.okx.com": {
+		MaxRequestsPerMinute: 30,
+		MaxRequestsPerHour:   600,
+		MinIntervalMs:        500,
+		MaxIntervalMs:        1500,
+		RandomDelay:          true,
+		CooldownAfterBurst:   15,
+		BurstThreshold:       20,
+	},
+	// Gate.io - 公开行情接口
+	"api.gateio.ws": {
+		MaxRequestsPerMinute: 20,
+		MaxRequestsPerHour:   400,
+		MinIntervalMs:        1000,
+		MaxIntervalMs:        2000,
+		RandomDelay:          true,
+		CooldownAfterBurst:   20,
+		BurstThreshold:       15,
+	},
+	// 金十数据中心 - 央行利率决议，按货币缓存，请求很少
+	"jin10.com": {
+		MaxRequestsPerMinute: 20,
+		MaxRequestsPerHour:   200,
+		MinIntervalMs:        500,
+		MaxIntervalMs:        1500,
+		RandomDelay:          true,
+		CooldownAfterBurst:   20,
+		BurstThreshold:       10,
+	},
 	// 默认配置 - 用于未知域名
 	"default": {
 		MaxRequestsPerMinute: 8,
@@ -220,7 +259,9 @@ func NewRateLimiter() *RateLimiter {
 	rl := &RateLimiter{
 		domainRequests: make(map[string][]time.Time),
 		domainConfigs:  make(map[string]*DomainConfig),
-		requestQueue:   make(chan *QueuedRequest, 100),
+		adaptive:       loadAdaptiveStates(),
+		lanes:          make(map[string]*domainLanes),
+		closed:         make(chan struct{}),
 	}
 
 	// 复制默认配置
@@ -236,9 +277,6 @@ func NewRateLimiter() *RateLimiter {
 		}
 	}
 
-	// 启动队列处理器
-	go rl.processQueue()
-
 	return rl
 }
 
@@ -247,15 +285,7 @@ func (rl *RateLimiter) getConfig(domain string) *DomainConfig {
 	rl.mu.Lock()
 	defer rl.mu.Unlock()
 
-	// 尝试匹配域名
-	for key, config := range rl.domainConfigs {
-		if key != "default" && containsDomain(domain, key) {
-			return config
-		}
-	}
-
-	// 返回默认配置
-	return rl.domainConfigs["default"]
+	return rl.getConfigUnsafe(domain)
 }
 
 // containsDomain 检查域名是否包含指定的关键字
@@ -270,6 +300,11 @@ func (rl *RateLimiter) CanRequest(domain string) (bool, time.Duration) {
 	rl.mu.Lock()
 	defer rl.mu.Unlock()
 
+	return rl.canRequestUnsafe(domain)
+}
+
+// canRequestUnsafe 检查是否可以发起请求（调用方持有锁）
+func (rl *RateLimiter) canRequestUnsafe(domain string) (bool, time.Duration) {
 	config := rl.getConfigUnsafe(domain)
 	now := time.Now()
 
@@ -286,6 +321,18 @@ func (rl *RateLimiter) CanRequest(domain string) (bool, time.Duration) {
 		config.ConsecutiveRequests = 0
 	}
 
+	// 检查服务端限流触发的冷却
+	if state, ok := rl.adaptive[domain]; ok && now.Before(state.CooldownUntil) {
+		waitTime := state.CooldownUntil.Sub(now)
+		log.Printf("[RateLimiter] 域名 %s 被服务端限流 (%s)，还需等待 %.1f 秒", domain, state.LastReason, waitTime.Seconds())
+		return false, waitTime
+	}
+
+	// 按自适应系数折算限额
+	factor := rl.adaptiveFactorUnsafe(domain)
+	maxPerMinute := scaleLimit(config.MaxRequestsPerMinute, factor)
+	maxPerHour := scaleLimit(config.MaxRequestsPerHour, factor)
+
 	// 清理过期的请求记录
 	rl.cleanOldRequests(domain)
 
@@ -299,10 +346,10 @@ func (rl *RateLimiter) CanRequest(domain string) (bool, time.Duration) {
 			minuteCount++
 		}
 	}
-	if minuteCount >= config.MaxRequestsPerMinute {
-		waitTime := time.Minute - now.Sub(requests[len(requests)-config.MaxRequestsPerMinute])
+	if minuteCount >= maxPerMinute {
+		waitTime := time.Minute - now.Sub(requests[len(requests)-maxPerMinute])
 		log.Printf("[RateLimiter] 域名 %s 达到每分钟限制 (%d/%d)，需等待 %.1f 秒",
-			domain, minuteCount, config.MaxRequestsPerMinute, waitTime.Seconds())
+			domain, minuteCount, maxPerMinute, waitTime.Seconds())
 		return false, waitTime
 	}
 
@@ -314,17 +361,17 @@ func (rl *RateLimiter) CanRequest(domain string) (bool, time.Duration) {
 			hourCount++
 		}
 	}
-	if hourCount >= config.MaxRequestsPerHour {
-		waitTime := time.Hour - now.Sub(requests[len(requests)-config.MaxRequestsPerHour])
+	if hourCount >= maxPerHour {
+		waitTime := time.Hour - now.Sub(requests[len(requests)-maxPerHour])
 		log.Printf("[RateLimiter] 域名 %s 达到每小时限制 (%d/%d)，需等待 %.1f 秒",
-			domain, hourCount, config.MaxRequestsPerHour, waitTime.Seconds())
+			domain, hourCount, maxPerHour, waitTime.Seconds())
 		return false, waitTime
 	}
 
 	// 检查最小间隔
 	if !config.LastRequestTime.IsZero() {
 		elapsed := now.Sub(config.LastRequestTime)
-		minInterval := time.Duration(config.MinIntervalMs) * time.Millisecond
+		minInterval := time.Duration(scaleInterval(config.MinIntervalMs, factor)) * time.Millisecond
 		if elapsed < minInterval {
 			waitTime := minInterval - elapsed
 			return false, waitTime
@@ -334,14 +381,18 @@ func (rl *RateLimiter) CanRequest(domain string) (bool, time.Duration) {
 	return true, 0
 }
 
-// getConfigUnsafe 获取配置（不加锁，内部使用）
+// getConfigUnsafe 获取配置（不加锁，内部使用），多个配置匹配时取最具体的域名
 func (rl *RateLimiter) getConfigUnsafe(domain string) *DomainConfig {
-	for key, config := range rl.domainConfigs {
-		if key != "default" && containsDomain(domain, key) {
-			return config
+	matched := ""
+	for key := range rl.domainConfigs {
+		if key != "default" && len(key) > len(matched) && containsDomain(domain, key) {
+			matched = key
 		}
 	}
-	return rl.domainConfigs["default"]
+	if matched == "" {
+		return rl.domainConfigs["default"]
+	}
+	return rl.domainConfigs[matched]
 }
 
 // cleanOldRequests 清理过期的请求记录
@@ -369,7 +420,11 @@ func (rl *RateLimiter) RecordRequest(domain string) {
 	rl.mu.Lock()
 	defer rl.mu.Unlock()
 
-	now := time.Now()
+	rl.recordRequestUnsafe(domain, time.Now())
+}
+
+// recordRequestUnsafe 记录一次请求（调用方持有锁）
+func (rl *RateLimiter) recordRequestUnsafe(domain string, now time.Time) {
 	config := rl.getConfigUnsafe(domain)
 
 	// 记录请求时间
@@ -386,79 +441,43 @@ func (rl *RateLimiter) RecordRequest(domain string) {
 	}
 }
 
-// GetRandomDelay 获取随机延迟时间
+// GetRandomDelay 获取随机延迟时间，被服务端限流过的域名按自适应系数拉长间隔
 func (rl *RateLimiter) GetRandomDelay(domain string) time.Duration {
-	config := rl.getConfig(domain)
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	return rl.randomDelayUnsafe(domain)
+}
+
+// randomDelayUnsafe 获取随机延迟时间（调用方持有锁）
+func (rl *RateLimiter) randomDelayUnsafe(domain string) time.Duration {
+	config := rl.getConfigUnsafe(domain)
+	factor := rl.adaptiveFactorUnsafe(domain)
+	minMs := scaleInterval(config.MinIntervalMs, factor)
+	maxMs := scaleInterval(config.MaxIntervalMs, factor)
 
 	if !config.RandomDelay {
-		return time.Duration(config.MinIntervalMs) * time.Millisecond
+		return time.Duration(minMs) * time.Millisecond
 	}
 
 	// 生成随机延迟
-	rangeMs := config.MaxIntervalMs - config.MinIntervalMs
+	rangeMs := maxMs - minMs
 	if rangeMs <= 0 {
-		return time.Duration(config.MinIntervalMs) * time.Millisecond
+		return time.Duration(minMs) * time.Millisecond
 	}
 
 	randomMs, err := rand.Int(rand.Reader, big.NewInt(int64(rangeMs)))
 	if err != nil {
-		return time.Duration(config.MinIntervalMs) * time.Millisecond
+		return time.Duration(minMs) * time.Millisecond
 	}
 
-	delay := config.MinIntervalMs + int(randomMs.Int64())
+	delay := minMs + int(randomMs.Int64())
 	return time.Duration(delay) * time.Millisecond
 }
 
-// WaitForSlot 等待可用的请求槽位
-func (rl *RateLimiter) WaitForSlot(domain string) {
-	for {
-		canRequest, waitTime := rl.CanRequest(domain)
-		if canRequest {
-			// 添加随机延迟
-			delay := rl.GetRandomDelay(domain)
-			log.Printf("[RateLimiter] 域名 %s 等待随机延迟 %.1f 秒", domain, delay.Seconds())
-			time.Sleep(delay)
-			return
-		}
-
-		// 等待指定时间后重试
-		log.Printf("[RateLimiter] 域名 %s 需要等待 %.1f 秒", domain, waitTime.Seconds())
-		time.Sleep(waitTime)
-	}
-}
-
-// ExecuteWithRateLimit 带限流执行请求
+// ExecuteWithRateLimit 带限流执行请求（用户交互优先级）
 func (rl *RateLimiter) ExecuteWithRateLimit(domain string, fn func() error) error {
-	// 等待可用槽位
-	rl.WaitForSlot(domain)
-
-	// 记录请求
-	rl.RecordRequest(domain)
-
-	// 执行请求
-	return fn()
-}
-
-// QueueRequest 将请求加入队列（异步执行）
-func (rl *RateLimiter) QueueRequest(domain string, fn func() error) <-chan error {
-	result := make(chan error, 1)
-
-	rl.requestQueue <- &QueuedRequest{
-		Domain:   domain,
-		Callback: fn,
-		Result:   result,
-	}
-
-	return result
-}
-
-// processQueue 处理请求队列
-func (rl *RateLimiter) processQueue() {
-	for req := range rl.requestQueue {
-		err := rl.ExecuteWithRateLimit(req.Domain, req.Callback)
-		req.Result <- err
-		close(req.Result)
-	}
+	return rl.ExecuteWithContext(context.Background(), domain, fn)
 }
 
 // ResetCooldown 重置指定域名的冷却状态（仅用于测试）
@@ -470,6 +489,7 @@ func (rl *RateLimiter) ResetCooldown(domain string) {
 	config.InCooldown = false
 	config.ConsecutiveRequests = 0
 	config.CooldownEndTime = time.Time{}
+	delete(rl.adaptive, domain)
 }
 
 // GetStats 获取限流统计信息
@@ -504,6 +524,7 @@ func (rl *RateLimiter) GetStats(domain string) map[string]interface{} {
 		"inCooldown":           config.InCooldown,
 		"cooldownRemaining":    config.CooldownEndTime.Sub(now).Seconds(),
 		"consecutiveRequests":  config.ConsecutiveRequests,
+		"adaptiveFactor":       rl.adaptiveFactorUnsafe(domain),
 	}
 }
 
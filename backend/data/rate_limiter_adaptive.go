@@ -0,0 +1,367 @@
+package data
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"stock-ai/backend/models"
+)
+
+// ==================== 自适应限流 ====================
+//
+// 默认域名配置是静态的，而服务端的实际容忍度会随时间变化。限流器根据响应反馈
+// 按 AIMD 调整每个域名的速率系数：遇到 403/429、反爬页面或空响应时系数减半并进入冷却
+// （优先遵循 Retry-After），之后每连续成功若干次系数加回一点，直到恢复默认速率。
+// 系数与冷却状态持久化到 snapshot.rate_limits 命名空间，重启后继续生效。
+
+const (
+	// adaptiveMinFactor 速率系数下限
+	adaptiveMinFactor = 0.1
+	// adaptiveDecrease 被限流时系数的乘性减小比例
+	adaptiveDecrease = 0.5
+	// adaptiveIncrease 每个成功窗口系数的加性增加量
+	adaptiveIncrease = 0.1
+	// adaptiveSuccessWindow 连续成功多少次后增加一次系数
+	adaptiveSuccessWindow = 10
+	// adaptiveMaxCooldown 单次冷却的上限（含 Retry-After）
+	adaptiveMaxCooldown = 10 * time.Minute
+	// adaptiveSniffBytes 检测反爬页面时读取的响应体长度
+	adaptiveSniffBytes = 8 * 1024
+)
+
+// rateLimitStateKey 全部域名的自适应状态保存在同一个键下
+const rateLimitStateKey = "domains"
+
+// 限流原因
+const (
+	throttleReasonForbidden  = "http_403"
+	throttleReasonTooMany    = "http_429"
+	throttleReasonAntiBot    = "anti_bot"
+	throttleReasonEmptyBody  = "empty_body"
+	throttleReasonRetryAfter = "retry_after"
+)
+
+// antiBotMarkers 反爬/验证页面中常见的关键字（小写匹配）
+var antiBotMarkers = []string{
+	"captcha",
+	"验证码",
+	"滑动验证",
+	"安全验证",
+	"人机验证",
+	"访问过于频繁",
+	"请求过于频繁",
+	"访问频率过快",
+	"access denied",
+	"too many requests",
+	"are you a robot",
+}
+
+// adaptiveState 单个域名的自适应限流状态
+type adaptiveState struct {
+	Factor        float64   `json:"factor"`
+	CooldownUntil time.Time `json:"cooldownUntil"`
+	Strikes       int       `json:"strikes"`   // 连续被限流次数，用于指数退避
+	Successes     int       `json:"successes"` // 当前成功窗口内的成功次数
+	Throttles     int64     `json:"throttles"`
+	LastReason    string    `json:"lastReason"`
+	LastThrottle  time.Time `json:"lastThrottle"`
+}
+
+// throttleSignal 从响应中识别出的限流信号
+type throttleSignal struct {
+	reason     string
+	retryAfter time.Duration
+}
+
+// loadAdaptiveStates 读取上次保存的自适应状态
+func loadAdaptiveStates() map[string]*adaptiveState {
+	states := make(map[string]*adaptiveState)
+	saved, ok := rateLimitStateCache.Get(rateLimitStateKey)
+	if !ok {
+		return states
+	}
+	for domain, state := range saved {
+		if state == nil || state.Factor <= 0 {
+			continue
+		}
+		copied := *state
+		states[domain] = &copied
+	}
+	if len(states) > 0 {
+		log.Printf("[RateLimiter] 已恢复 %d 个域名的自适应限流状态", len(states))
+	}
+	return states
+}
+
+// adaptiveFactorUnsafe 返回域名当前的速率系数（调用方持有锁）
+func (rl *RateLimiter) adaptiveFactorUnsafe(domain string) float64 {
+	if state, ok := rl.adaptive[domain]; ok {
+		return state.Factor
+	}
+	return 1
+}
+
+// adaptiveStateUnsafe 获取或创建域名的自适应状态（调用方持有锁）
+func (rl *RateLimiter) adaptiveStateUnsafe(domain string) *adaptiveState {
+	state, ok := rl.adaptive[domain]
+	if !ok {
+		state = &adaptiveState{Factor: 1}
+		rl.adaptive[domain] = state
+	}
+	return state
+}
+
+// scaleLimit 按系数折算次数上限，至少为 1
+func scaleLimit(limit int, factor float64) int {
+	scaled := int(float64(limit) * factor)
+	if scaled < 1 {
+		return 1
+	}
+	return scaled
+}
+
+// scaleInterval 按系数折算请求间隔，系数越小间隔越长
+func scaleInterval(ms int, factor float64) int {
+	if factor <= 0 || factor >= 1 {
+		return ms
+	}
+	return int(float64(ms) / factor)
+}
+
+// ReportThrottle 服务端拒绝请求时调用：系数乘性减小并进入冷却
+// 已在冷却中的域名不再重复减小系数（冷却前发出的请求可能陆续被拒），只按需延长冷却
+func (rl *RateLimiter) ReportThrottle(domain, reason string, retryAfter time.Duration) {
+	rl.mu.Lock()
+	now := time.Now()
+	config := rl.getConfigUnsafe(domain)
+	state := rl.adaptiveStateUnsafe(domain)
+	state.Throttles++
+	state.LastReason = reason
+	state.LastThrottle = now
+	state.Successes = 0
+
+	cooldown := retryAfter
+	if now.Before(state.CooldownUntil) {
+		if until := now.Add(minDuration(cooldown, adaptiveMaxCooldown)); until.After(state.CooldownUntil) {
+			state.CooldownUntil = until
+		}
+		rl.mu.Unlock()
+		rl.saveAdaptiveStates()
+		return
+	}
+
+	state.Strikes++
+	state.Factor *= adaptiveDecrease
+	if state.Factor < adaptiveMinFactor {
+		state.Factor = adaptiveMinFactor
+	}
+	if cooldown <= 0 {
+		// 无 Retry-After 时按连续被限流次数指数退避
+		shift := state.Strikes - 1
+		if shift > 6 {
+			shift = 6
+		}
+		cooldown = time.Duration(config.CooldownAfterBurst) * time.Second << shift
+	}
+	cooldown = minDuration(cooldown, adaptiveMaxCooldown)
+	state.CooldownUntil = now.Add(cooldown)
+	factor := state.Factor
+	rl.mu.Unlock()
+
+	log.Printf("[RateLimiter] 域名 %s 被服务端限流 (%s)，速率系数降至 %.2f，冷却 %.0f 秒",
+		domain, reason, factor, cooldown.Seconds())
+	rl.saveAdaptiveStates()
+}
+
+// ReportSuccess 请求正常返回时调用：连续成功一个窗口后系数加性增加
+func (rl *RateLimiter) ReportSuccess(domain string) {
+	rl.mu.Lock()
+	state, ok := rl.adaptive[domain]
+	if !ok || state.Factor >= 1 {
+		rl.mu.Unlock()
+		return
+	}
+	state.Strikes = 0
+	state.Successes++
+	if state.Successes < adaptiveSuccessWindow {
+		rl.mu.Unlock()
+		return
+	}
+	state.Successes = 0
+	state.Factor += adaptiveIncrease
+	if state.Factor >= 1 {
+		state.Factor = 1
+		log.Printf("[RateLimiter] 域名 %s 已恢复默认速率", domain)
+	}
+	rl.mu.Unlock()
+	rl.saveAdaptiveStates()
+}
+
+// saveAdaptiveStates 持久化全部域名的自适应状态
+func (rl *RateLimiter) saveAdaptiveStates() {
+	rl.mu.Lock()
+	snapshot := make(map[string]*adaptiveState, len(rl.adaptive))
+	for domain, state := range rl.adaptive {
+		copied := *state
+		snapshot[domain] = &copied
+	}
+	rl.mu.Unlock()
+	rateLimitStateCache.Set(rateLimitStateKey, snapshot)
+}
+
+// ObserveResponse 根据响应识别限流信号并反馈给限流器
+// 对正常状态码的响应会读取响应体检测反爬页面与空响应，随后重置 Body 供调用方继续读取
+func (rl *RateLimiter) ObserveResponse(domain string, resp *http.Response) {
+	if resp == nil {
+		return
+	}
+	if signal, throttled := detectThrottle(resp); throttled {
+		rl.ReportThrottle(domain, signal.reason, signal.retryAfter)
+		return
+	}
+	rl.ReportSuccess(domain)
+}
+
+// detectThrottle 识别响应中的限流信号
+func detectThrottle(resp *http.Response) (throttleSignal, bool) {
+	retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return throttleSignal{reason: throttleReasonTooMany, retryAfter: retryAfter}, true
+	case resp.StatusCode == http.StatusForbidden:
+		return throttleSignal{reason: throttleReasonForbidden, retryAfter: retryAfter}, true
+	case resp.StatusCode == http.StatusServiceUnavailable && retryAfter > 0:
+		return throttleSignal{reason: throttleReasonRetryAfter, retryAfter: retryAfter}, true
+	case resp.StatusCode != http.StatusOK:
+		return throttleSignal{}, false
+	}
+
+	if resp.Body == nil {
+		return throttleSignal{}, false
+	}
+	raw, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	// 调用方按 Content-Encoding 自行解压，还原为原始响应体
+	resp.Body = io.NopCloser(bytes.NewReader(raw))
+	if err != nil {
+		return throttleSignal{}, false
+	}
+	// 请求头声明了 Accept-Encoding 时 Transport 不会自动解压，这里解压后再检测内容
+	body, err := readResponseBody(&http.Response{Header: resp.Header, Body: io.NopCloser(bytes.NewReader(raw))})
+	if err != nil {
+		return throttleSignal{}, false
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return throttleSignal{reason: throttleReasonEmptyBody, retryAfter: retryAfter}, true
+	}
+	if isAntiBotPage(resp.Header.Get("Content-Type"), body) {
+		return throttleSignal{reason: throttleReasonAntiBot, retryAfter: retryAfter}, true
+	}
+	return throttleSignal{}, false
+}
+
+// isAntiBotPage 判断响应是否为反爬/验证页面：HTML 内容且包含验证相关关键字
+func isAntiBotPage(contentType string, body []byte) bool {
+	if len(body) > adaptiveSniffBytes {
+		body = body[:adaptiveSniffBytes]
+	}
+	head := strings.ToLower(string(bytes.TrimSpace(body)))
+	isHTML := strings.Contains(strings.ToLower(contentType), "text/html") ||
+		strings.HasPrefix(head, "<!doctype html") || strings.HasPrefix(head, "<html")
+	if !isHTML {
+		return false
+	}
+	for _, marker := range antiBotMarkers {
+		if strings.Contains(head, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseRetryAfter 解析 Retry-After 头，支持秒数与 HTTP 日期两种格式
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds <= 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil && at.After(now) {
+		return at.Sub(now)
+	}
+	return 0
+}
+
+func minDuration(a, b time.Duration) time.Duration {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// GetAdaptiveStatus 获取各域名的自适应限流状态，包含近期有请求或曾被限流的域名，按域名排序
+func (rl *RateLimiter) GetAdaptiveStatus() []models.RateLimitStatus {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := time.Now()
+	minuteAgo := now.Add(-time.Minute)
+	domains := make(map[string]struct{}, len(rl.adaptive)+len(rl.domainRequests))
+	for domain := range rl.adaptive {
+		domains[domain] = struct{}{}
+	}
+	for domain, requests := range rl.domainRequests {
+		if len(requests) > 0 {
+			domains[domain] = struct{}{}
+		}
+	}
+	for domain, q := range rl.lanes {
+		if len(q.waiters) > 0 {
+			domains[domain] = struct{}{}
+		}
+	}
+
+	list := make([]models.RateLimitStatus, 0, len(domains))
+	for domain := range domains {
+		config := rl.getConfigUnsafe(domain)
+		factor := rl.adaptiveFactorUnsafe(domain)
+		status := models.RateLimitStatus{
+			Domain:             domain,
+			Factor:             factor,
+			MaxPerMinute:       config.MaxRequestsPerMinute,
+			EffectivePerMinute: scaleLimit(config.MaxRequestsPerMinute, factor),
+			MinIntervalMs:      scaleInterval(config.MinIntervalMs, factor),
+			Queued:             rl.queuedByPriorityUnsafe(domain),
+		}
+		for _, t := range rl.domainRequests[domain] {
+			if t.After(minuteAgo) {
+				status.RequestsLastMinute++
+			}
+		}
+		if state, ok := rl.adaptive[domain]; ok {
+			status.Throttles = state.Throttles
+			status.LastReason = state.LastReason
+			if !state.LastThrottle.IsZero() {
+				status.LastThrottleAt = state.LastThrottle.Format(time.RFC3339)
+			}
+			if now.Before(state.CooldownUntil) {
+				status.InCooldown = true
+				status.CooldownSeconds = int(state.CooldownUntil.Sub(now).Seconds())
+			}
+		}
+		list = append(list, status)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Domain < list[j].Domain })
+	return list
+}
@@ -0,0 +1,129 @@
+package data
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"stock-ai/backend/cache"
+	"stock-ai/backend/models"
+)
+
+func newTestResponse(status int, contentType, body string, header http.Header) *http.Response {
+	if header == nil {
+		header = http.Header{}
+	}
+	if contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+	return &http.Response{StatusCode: status, Header: header, Body: io.NopCloser(strings.NewReader(body))}
+}
+
+func TestDetectThrottle(t *testing.T) {
+	cases := []struct {
+		name   string
+		resp   *http.Response
+		reason string
+	}{
+		{"429", newTestResponse(429, "", "", http.Header{"Retry-After": {"30"}}), throttleReasonTooMany},
+		{"403", newTestResponse(403, "text/html", "forbidden", nil), throttleReasonForbidden},
+		{"空响应", newTestResponse(200, "application/json", "  \n", nil), throttleReasonEmptyBody},
+		{"验证页面", newTestResponse(200, "text/html", "<html><body>请输入验证码</body></html>", nil), throttleReasonAntiBot},
+		{"正常JSON", newTestResponse(200, "application/json", `{"data":{"captcha":1}}`, nil), ""},
+		{"404", newTestResponse(404, "", "", nil), ""},
+	}
+	for _, tc := range cases {
+		signal, throttled := detectThrottle(tc.resp)
+		if throttled != (tc.reason != "") || signal.reason != tc.reason {
+			t.Errorf("%s: got (%q, %v), want %q", tc.name, signal.reason, throttled, tc.reason)
+		}
+	}
+
+	resp := newTestResponse(200, "application/json", `{"ok":true}`, nil)
+	detectThrottle(resp)
+	if body, _ := io.ReadAll(resp.Body); string(body) != `{"ok":true}` {
+		t.Fatalf("检测后响应体应可继续读取, got %q", body)
+	}
+}
+
+func TestDetectThrottleGzip(t *testing.T) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write([]byte("<html><body>访问过于频繁，请完成滑动验证</body></html>"))
+	zw.Close()
+	compressed := buf.String()
+
+	resp := newTestResponse(200, "text/html", compressed, http.Header{"Content-Encoding": {"gzip"}})
+	signal, throttled := detectThrottle(resp)
+	if !throttled || signal.reason != throttleReasonAntiBot {
+		t.Fatalf("gzip 验证页面未识别: (%q, %v)", signal.reason, throttled)
+	}
+	// 调用方仍按 Content-Encoding 自行解压，响应体需保持压缩原样
+	if body, _ := io.ReadAll(resp.Body); string(body) != compressed {
+		t.Fatal("检测后应还原压缩的原始响应体")
+	}
+
+	resp = newTestResponse(200, "text/html", "not gzip", http.Header{"Content-Encoding": {"gzip"}})
+	if _, throttled := detectThrottle(resp); throttled {
+		t.Fatal("无法解压的响应不应判定为限流")
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	if got := parseRetryAfter("120", now); got != 2*time.Minute {
+		t.Fatalf("秒数格式 = %v", got)
+	}
+	if got := parseRetryAfter(now.Add(45*time.Second).Format(http.TimeFormat), now); got != 45*time.Second {
+		t.Fatalf("日期格式 = %v", got)
+	}
+	if got := parseRetryAfter("soon", now); got != 0 {
+		t.Fatalf("非法值 = %v", got)
+	}
+}
+
+func TestAdaptiveAIMD(t *testing.T) {
+	cache.SetDiskDir(t.TempDir())
+	defer cache.SetDiskDir("")
+
+	const domain = "push2.eastmoney.com"
+	rl := NewRateLimiter()
+	rl.ReportThrottle(domain, throttleReasonTooMany, 30*time.Second)
+	rl.ReportThrottle(domain, throttleReasonTooMany, 0) // 冷却中不再重复减半
+
+	status := findRateLimitStatus(t, rl, domain)
+	if status.Factor != adaptiveDecrease || !status.InCooldown || status.Throttles != 2 {
+		t.Fatalf("限流后状态 = %+v", status)
+	}
+	if ok, wait := rl.CanRequest(domain); ok || wait <= 25*time.Second {
+		t.Fatalf("冷却中 CanRequest = %v, %v", ok, wait)
+	}
+
+	// 重启后恢复状态
+	restored := NewRateLimiter()
+	if got := findRateLimitStatus(t, restored, domain).Factor; got != adaptiveDecrease {
+		t.Fatalf("重启后系数 = %v", got)
+	}
+
+	for i := 0; i < adaptiveSuccessWindow; i++ {
+		rl.ReportSuccess(domain)
+	}
+	if got := findRateLimitStatus(t, rl, domain).Factor; got < adaptiveDecrease+adaptiveIncrease-1e-9 {
+		t.Fatalf("成功窗口后系数 = %v", got)
+	}
+}
+
+func findRateLimitStatus(t *testing.T, rl *RateLimiter, domain string) models.RateLimitStatus {
+	t.Helper()
+	for _, s := range rl.GetAdaptiveStatus() {
+		if s.Domain == domain {
+			return s
+		}
+	}
+	t.Fatalf("未找到域名 %s 的限流状态", domain)
+	return models.RateLimitStatus{}
+}
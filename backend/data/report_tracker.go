@@ -0,0 +1,353 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"stock-ai/backend/models"
+
+	"gorm.io/gorm/clause"
+)
+
+// ==================== 研报评级跟踪 ====================
+
+const (
+	reportSyncTTL         = 12 * time.Hour // 同一股票研报历史的同步间隔
+	reportHistoryDays     = 365            // 同步时回溯的天数
+	reportHistoryPageSize = 100            // 同步时单页拉取条数
+	reportConsensusDays   = 180            // 一致预期统计窗口
+	reportContentParseMax = 5              // 单次同步最多解析正文的研报数
+)
+
+var (
+	reportSyncMu   sync.Mutex
+	reportSyncedAt = make(map[string]time.Time)
+)
+
+// ratingKeywords 评级关键词与5分制映射，较长的关键词需排在前面
+var ratingKeywords = []struct {
+	keyword string
+	score   int
+}{
+	{"强烈推荐", 5}, {"强烈买入", 5}, {"强推", 5},
+	{"谨慎增持", 4}, {"谨慎推荐", 4}, {"谨慎买入", 4},
+	{"买入", 5},
+	{"增持", 4}, {"推荐", 4}, {"跑赢", 4}, {"优于大市", 4}, {"领先大市", 4}, {"超配", 4}, {"优于行业", 4},
+	{"中性", 3}, {"持有", 3}, {"同步大市", 3}, {"标配", 3}, {"观望", 3},
+	{"减持", 2}, {"跑输", 2}, {"弱于大市", 2}, {"落后大市", 2}, {"低配", 2},
+	{"卖出", 1}, {"回避", 1},
+}
+
+// ratingLabels 5分制评级显示名称
+var ratingLabels = map[int]string{5: "买入", 4: "增持", 3: "中性", 2: "减持", 1: "卖出"}
+
+var (
+	reportTargetPriceRe = regexp.MustCompile(`目标价(?:格|位)?(?:区间)?\s*(?:为|至|到|:|：)?\s*(\d+(?:\.\d+)?)(?:\s*[-~～至]\s*(\d+(?:\.\d+)?))?\s*元`)
+	reportEpsRe         = regexp.MustCompile(`(?:EPS|每股收益)[^0-9\-]{0,12}(-?\d+\.\d+)\s*元?\s*[/、,，]\s*(-?\d+\.\d+)`)
+)
+
+// NormalizeRatingScore 将机构评级统一为5分制，无法识别返回0
+func NormalizeRatingScore(rating string) int {
+	rating = strings.TrimSpace(rating)
+	if rating == "" {
+		return 0
+	}
+	for _, item := range ratingKeywords {
+		if strings.Contains(rating, item.keyword) {
+			return item.score
+		}
+	}
+	return 0
+}
+
+// reportTargetPrice 目标价区间取中值，只给出一端时取该值
+func reportTargetPrice(low, high float64) float64 {
+	switch {
+	case low > 0 && high > 0:
+		return (low + high) / 2
+	case high > 0:
+		return high
+	default:
+		return low
+	}
+}
+
+// ExtractReportForecasts 从研报正文提取目标价与当年/次年EPS预测，未找到的值为0
+func ExtractReportForecasts(text string) (targetPrice, epsThisYear, epsNextYear float64) {
+	if m := reportTargetPriceRe.FindStringSubmatch(text); m != nil {
+		targetPrice = reportTargetPrice(parseFloat(m[1]), parseFloat(m[2]))
+		if targetPrice <= 0 || targetPrice > 100000 {
+			targetPrice = 0
+		}
+	}
+	if m := reportEpsRe.FindStringSubmatch(text); m != nil {
+		epsThisYear = parseFloat(m[1])
+		epsNextYear = parseFloat(m[2])
+	}
+	return
+}
+
+// SaveReportHistory 保存研报元数据，已存在的研报跳过，返回新增条数
+func SaveReportHistory(code string, reports []models.ResearchReport) int {
+	added := 0
+	// 接口按时间倒序返回，倒序写入以便与同机构的上一篇比较评级
+	for i := len(reports) - 1; i >= 0; i-- {
+		r := reports[i]
+		if r.InfoCode == "" {
+			continue
+		}
+		record := models.ReportRecord{
+			InfoCode:    r.InfoCode,
+			StockCode:   code,
+			StockName:   r.StockName,
+			Title:       r.Title,
+			OrgName:     r.OrgName,
+			Researcher:  r.Researcher,
+			PublishDate: r.PublishDate,
+			Rating:      r.Rating,
+			RatingScore: NormalizeRatingScore(r.Rating),
+			LastRating:  r.LastRating,
+			TargetPrice: r.TargetPrice,
+			EpsThisYear: r.EpsThisYear,
+			EpsNextYear: r.EpsNextYear,
+			Url:         r.Url,
+		}
+		record.RatingChange = reportRatingChange(record)
+
+		result := GetDB().Clauses(clause.OnConflict{DoNothing: true}).Create(&record)
+		if result.Error != nil {
+			log.Printf("[研报跟踪] 保存 %s 失败: %v", r.InfoCode, result.Error)
+			continue
+		}
+		added += int(result.RowsAffected)
+	}
+	return added
+}
+
+// reportRatingChange 判断评级变动：优先使用接口给出的上次评级，否则与本地同机构上一篇比较
+func reportRatingChange(record models.ReportRecord) int {
+	if record.RatingScore == 0 {
+		return 0
+	}
+	previous := NormalizeRatingScore(record.LastRating)
+	if previous == 0 && record.OrgName != "" {
+		var last models.ReportRecord
+		err := GetDB().
+			Where("stock_code = ? AND org_name = ? AND publish_date < ? AND rating_score > 0", record.StockCode, record.OrgName, record.PublishDate).
+			Order("publish_date DESC").
+			First(&last).Error
+		if err == nil {
+			previous = last.RatingScore
+		}
+	}
+	switch {
+	case previous == 0 || previous == record.RatingScore:
+		return 0
+	case record.RatingScore > previous:
+		return 1
+	default:
+		return -1
+	}
+}
+
+// SyncReportHistory 同步近一年研报元数据并解析部分正文，距上次同步不足12小时跳过
+func SyncReportHistory(ctx context.Context, api *StockAPI, code string) error {
+	reportSyncMu.Lock()
+	if last, ok := reportSyncedAt[code]; ok && time.Since(last) < reportSyncTTL {
+		reportSyncMu.Unlock()
+		return nil
+	}
+	reportSyncedAt[code] = time.Now()
+	reportSyncMu.Unlock()
+
+	beginTime := time.Now().AddDate(0, 0, -reportHistoryDays).Format("2006-01-02")
+	reports, err := api.GetResearchReportsPage(ctx, code, 1, reportHistoryPageSize, beginTime)
+	if err != nil {
+		reportSyncMu.Lock()
+		delete(reportSyncedAt, code)
+		reportSyncMu.Unlock()
+		return err
+	}
+	if added := SaveReportHistory(code, reports); added > 0 {
+		log.Printf("[研报跟踪] %s 新增 %d 篇研报", code, added)
+	}
+
+	parseReportContents(ctx, api, code)
+	return nil
+}
+
+// parseReportContents 对缺少目标价的研报抓取正文，提取目标价与EPS预测
+func parseReportContents(ctx context.Context, api *StockAPI, code string) {
+	var records []models.ReportRecord
+	GetDB().
+		Where("stock_code = ? AND content_parsed = ? AND target_price = 0 AND url <> ''", code, false).
+		Order("publish_date DESC").
+		Limit(reportContentParseMax).
+		Find(&records)
+
+	for _, record := range records {
+		updates := map[string]interface{}{"content_parsed": true}
+		body, err := api.doGetWithRetry(ctx, record.Url, "https://data.eastmoney.com/report/", nil)
+		if err != nil {
+			log.Printf("[研报跟踪] 获取正文失败 %s: %v", record.InfoCode, err)
+			continue
+		}
+		target, epsThisYear, epsNextYear := ExtractReportForecasts(extractTextContent(string(body)))
+		if target > 0 {
+			updates["target_price"] = target
+		}
+		if record.EpsThisYear == 0 && epsThisYear != 0 {
+			updates["eps_this_year"] = epsThisYear
+			updates["eps_next_year"] = epsNextYear
+		}
+		GetDB().Model(&models.ReportRecord{}).Where("id = ?", record.ID).Updates(updates)
+	}
+}
+
+// ListReportHistory 获取股票的研报历史，按发布日期倒序
+func ListReportHistory(code string, limit int) ([]models.ReportRecord, error) {
+	var records []models.ReportRecord
+	err := GetDB().Where("stock_code = ?", code).Order("publish_date DESC, id DESC").Limit(limit).Find(&records).Error
+	return records, err
+}
+
+// GetReportConsensus 统计近180天研报的一致预期：评级与目标价按机构取最新一篇，评级变动统计全部研报
+func GetReportConsensus(code string, currentPrice float64) (*models.ReportConsensus, error) {
+	now := time.Now()
+	since := now.AddDate(0, 0, -reportConsensusDays).Format("2006-01-02")
+	var records []models.ReportRecord
+	err := GetDB().
+		Where("stock_code = ? AND publish_date >= ?", code, since).
+		Order("publish_date DESC, id DESC").
+		Find(&records).Error
+	if err != nil {
+		return nil, err
+	}
+
+	consensus := &models.ReportConsensus{
+		StockCode:    code,
+		WindowDays:   reportConsensusDays,
+		ReportCount:  len(records),
+		CurrentPrice: currentPrice,
+	}
+	if len(records) == 0 {
+		return consensus, nil
+	}
+	consensus.StockName = records[0].StockName
+	consensus.LatestReportDate = records[0].PublishDate
+
+	day30 := now.AddDate(0, 0, -30).Format("2006-01-02")
+	day90 := now.AddDate(0, 0, -90).Format("2006-01-02")
+	ratedOrgs := make(map[string]bool)
+	targetOrgs := make(map[string]bool)
+	epsOrgs := make(map[string]bool)
+	orgs := make(map[string]bool)
+	counts := make(map[int]int)
+	var scoreSum, targetSum, epsThisSum, epsNextSum float64
+
+	for _, r := range records {
+		org := r.OrgName
+		if org == "" {
+			org = r.InfoCode
+		}
+		orgs[org] = true
+
+		if r.RatingChange != 0 && r.PublishDate >= day90 {
+			recent := r.PublishDate >= day30
+			if r.RatingChange > 0 {
+				consensus.Upgrades90++
+				if recent {
+					consensus.Upgrades30++
+				}
+			} else {
+				consensus.Downgrades90++
+				if recent {
+					consensus.Downgrades30++
+				}
+			}
+		}
+
+		if r.RatingScore > 0 && !ratedOrgs[org] {
+			ratedOrgs[org] = true
+			counts[r.RatingScore]++
+			scoreSum += float64(r.RatingScore)
+		}
+		if r.TargetPrice > 0 && !targetOrgs[org] {
+			targetOrgs[org] = true
+			targetSum += r.TargetPrice
+			if consensus.TargetPriceHigh == 0 || r.TargetPrice > consensus.TargetPriceHigh {
+				consensus.TargetPriceHigh = r.TargetPrice
+			}
+			if consensus.TargetPriceLow == 0 || r.TargetPrice < consensus.TargetPriceLow {
+				consensus.TargetPriceLow = r.TargetPrice
+			}
+		}
+		if r.EpsThisYear != 0 && !epsOrgs[org] {
+			epsOrgs[org] = true
+			epsThisSum += r.EpsThisYear
+			epsNextSum += r.EpsNextYear
+		}
+	}
+
+	consensus.OrgCount = len(orgs)
+	if len(ratedOrgs) > 0 {
+		consensus.AvgRatingScore = scoreSum / float64(len(ratedOrgs))
+	}
+	for score := 5; score >= 1; score-- {
+		if counts[score] > 0 {
+			consensus.RatingDistribution = append(consensus.RatingDistribution, models.RatingBucket{
+				Label: ratingLabels[score],
+				Score: score,
+				Count: counts[score],
+			})
+		}
+	}
+	if n := len(targetOrgs); n > 0 {
+		consensus.TargetPriceCount = n
+		consensus.TargetPriceAvg = targetSum / float64(n)
+		if currentPrice > 0 {
+			consensus.ImpliedUpside = (consensus.TargetPriceAvg/currentPrice - 1) * 100
+		}
+	}
+	if n := len(epsOrgs); n > 0 {
+		consensus.EpsThisYearAvg = epsThisSum / float64(n)
+		consensus.EpsNextYearAvg = epsNextSum / float64(n)
+	}
+	return consensus, nil
+}
+
+// FormatReportConsensusForAI 格式化研报一致预期供AI分析，无研报时返回空字符串
+func FormatReportConsensusForAI(c *models.ReportConsensus) string {
+	if c == nil || c.ReportCount == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("## 研报一致预期（近%d天）\n", c.WindowDays))
+	sb.WriteString(fmt.Sprintf("- 覆盖机构：%d家（研报%d篇，最新 %s）\n", c.OrgCount, c.ReportCount, c.LatestReportDate))
+	if len(c.RatingDistribution) > 0 {
+		parts := make([]string, 0, len(c.RatingDistribution))
+		for _, bucket := range c.RatingDistribution {
+			parts = append(parts, fmt.Sprintf("%s %d家", bucket.Label, bucket.Count))
+		}
+		sb.WriteString(fmt.Sprintf("- 评级分布：%s（平均 %.1f/5，5为买入）\n", strings.Join(parts, "、"), c.AvgRatingScore))
+	}
+	sb.WriteString(fmt.Sprintf("- 评级变动：近30天上调%d次、下调%d次；近90天上调%d次、下调%d次\n",
+		c.Upgrades30, c.Downgrades30, c.Upgrades90, c.Downgrades90))
+	if c.TargetPriceCount > 0 {
+		sb.WriteString(fmt.Sprintf("- 一致目标价：%.2f（%d家，区间 %.2f ~ %.2f）\n",
+			c.TargetPriceAvg, c.TargetPriceCount, c.TargetPriceLow, c.TargetPriceHigh))
+		if c.CurrentPrice > 0 {
+			sb.WriteString(fmt.Sprintf("- 隐含空间：%+.2f%%（现价 %.2f）\n", c.ImpliedUpside, c.CurrentPrice))
+		}
+	}
+	if c.EpsThisYearAvg != 0 {
+		sb.WriteString(fmt.Sprintf("- EPS一致预期：当年 %.2f，次年 %.2f\n", c.EpsThisYearAvg, c.EpsNextYearAvg))
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
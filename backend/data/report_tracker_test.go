@@ -0,0 +1,91 @@
+package data
+
+import "testing"
+
+func TestNormalizeRatingScore(t *testing.T) {
+	tests := []struct {
+		rating string
+		want   int
+	}{
+		{"买入", 5},
+		{"买入-A", 5},
+		{"强烈推荐", 5},
+		{"强烈推荐-A", 5},
+		{"增持", 4},
+		{"谨慎增持", 4},
+		{"审慎增持", 4},
+		{"推荐", 4},
+		{"谨慎推荐", 4},
+		{"优于大市", 4},
+		{"跑赢行业", 4},
+		{"超配", 4},
+		{"中性", 3},
+		{"持有", 3},
+		{"同步大市-A", 3},
+		{"减持", 2},
+		{"弱于大市", 2},
+		{"跑输行业", 2},
+		{"卖出", 1},
+		{"回避", 1},
+		{" 买入 ", 5},
+		{"", 0},
+		{"未评级", 0},
+		{"-", 0},
+	}
+	for _, tc := range tests {
+		if got := NormalizeRatingScore(tc.rating); got != tc.want {
+			t.Errorf("NormalizeRatingScore(%q) = %d, want %d", tc.rating, got, tc.want)
+		}
+	}
+}
+
+func TestExtractReportForecasts(t *testing.T) {
+	tests := []struct {
+		name                          string
+		text                          string
+		targetPrice, epsThis, epsNext float64
+	}{
+		{
+			name:        "EPS 斜杠分隔并给出目标价",
+			text:        "贵州茅台(600519)：业绩稳健增长，维持“买入”评级。我们预计公司2024-2026年EPS分别为68.64/79.10/90.46元，给予目标价2100元。",
+			targetPrice: 2100, epsThis: 68.64, epsNext: 79.10,
+		},
+		{
+			name:    "每股收益顿号分隔",
+			text:    "预计公司2024-2026年归母净利润分别为12.3、14.8、17.6亿元，对应每股收益分别为1.23元、1.48元、1.76元，维持“增持”评级。",
+			epsThis: 1.23, epsNext: 1.48,
+		},
+		{
+			name:        "目标价区间取中值",
+			text:        "参考可比公司估值，给予2025年25-28倍PE，目标价格区间为25.5-28.3元，首次覆盖给予“推荐”评级。",
+			targetPrice: (25.5 + 28.3) / 2,
+		},
+		{
+			name:        "上调目标价至",
+			text:        "宁德时代(300750)三季报点评：盈利能力超预期，上调目标价至268.5元，EPS为11.02、13.24元，维持买入评级。",
+			targetPrice: 268.5, epsThis: 11.02, epsNext: 13.24,
+		},
+		{
+			name:        "目标价冒号写法与亏损EPS",
+			text:        "公司短期仍处亏损，预计2024/2025年EPS为-0.35/0.12元，目标价：8.6元。",
+			targetPrice: 8.6, epsThis: -0.35, epsNext: 0.12,
+		},
+		{
+			name: "只有标题无预测",
+			text: "中国平安(601318)：寿险新业务价值持续改善，投资端弹性可期",
+		},
+		{
+			name: "整数EPS不误识别年份",
+			text: "预计2024年EPS 2025年进一步提升",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			price, epsThis, epsNext := ExtractReportForecasts(tc.text)
+			if price != tc.targetPrice || epsThis != tc.epsThis || epsNext != tc.epsNext {
+				t.Fatalf("ExtractReportForecasts = (%v, %v, %v), want (%v, %v, %v)",
+					price, epsThis, epsNext, tc.targetPrice, tc.epsThis, tc.epsNext)
+			}
+		})
+	}
+}
@@ -2,7 +2,7 @@ package data
 
 import (
 	"compress/gzip"
-	"crypto/tls"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -18,17 +18,20 @@ import (
 
 	"golang.org/x/text/encoding/simplifiedchinese"
 	"golang.org/x/text/transform"
+	"stock-ai/backend/cache"
 	"stock-ai/backend/models"
 )
 
 // RequestManager 请求管理器
 type RequestManager struct {
 	client       *http.Client
-	cache        *Cache
 	config       *models.Config
 	sourceStatus map[string]*SourceStatus
 	rateLimiter  *RateLimiter // 新增：限流器
 	proxyPool    *ProxyPoolState
+	proxyRoutes  []proxyRouteRule  // 按域名的代理路由规则
+	transport    http.RoundTripper // 非nil时替代默认传输层，用于测试录制/回放
+	base         http.RoundTripper // 当前底层传输层，数据请求与 NewRoutedClient 共用连接池
 	mu           sync.RWMutex
 }
 
@@ -46,7 +49,8 @@ type SourceStatus struct {
 type ProxyPoolState struct {
 	proxies   []string
 	expiresAt time.Time
-	current   int
+	health    map[string]*proxyHealth // 各代理的成功率与延迟
+	sticky    map[string]stickyProxy  // 域名 -> 粘性会话
 	mu        sync.Mutex
 	lastFetch time.Time
 	lastError string
@@ -57,24 +61,12 @@ func (ps *ProxyPoolState) reset() {
 	defer ps.mu.Unlock()
 	ps.proxies = nil
 	ps.expiresAt = time.Time{}
-	ps.current = 0
+	ps.health = nil
+	ps.sticky = nil
 	ps.lastFetch = time.Time{}
 	ps.lastError = ""
 }
 
-// Cache 缓存管理
-type Cache struct {
-	data map[string]*CacheItem
-	mu   sync.RWMutex
-}
-
-// CacheItem 缓存项
-type CacheItem struct {
-	Data      interface{}
-	ExpireAt  time.Time
-	CacheTime time.Duration
-}
-
 // User-Agent池
 var userAgents = []string{
 	"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
@@ -120,62 +112,104 @@ func GetRequestManager() *RequestManager {
 // NewRequestManager 创建请求管理器
 func NewRequestManager() *RequestManager {
 	rm := &RequestManager{
-		cache: &Cache{
-			data: make(map[string]*CacheItem),
-		},
 		sourceStatus: make(map[string]*SourceStatus),
 		rateLimiter:  GetRateLimiter(), // 初始化限流器
 		proxyPool:    &ProxyPoolState{},
 	}
 	rm.initClient()
 
-	// 启动缓存清理调度器
-	go rm.startCacheCleanupScheduler()
-
 	return rm
 }
 
-// initClient 初始化HTTP客户端
+// initClient 初始化HTTP客户端，代理配置变化时重建底层传输层并关闭旧连接池的空闲连接
 func (rm *RequestManager) initClient() {
-	transport := &http.Transport{
-		TLSClientConfig: &tls.Config{},
-		MaxIdleConns:    100,
-		IdleConnTimeout: 90 * time.Second,
+	if old, ok := rm.base.(*http.Transport); ok {
+		old.CloseIdleConnections()
 	}
-
-	transport.Proxy = rm.proxySelectorFunc()
-
+	base := rm.transport
+	if base == nil {
+		base = newProxyTransport()
+	}
+	rm.base = base
 	rm.client = &http.Client{
-		Timeout:   5 * time.Second, // 适当放宽超时，避免优质接口偶发超时
-		Transport: transport,
+		Transport: withRequestDeadline(&proxyRoutingTransport{rm: rm, base: base}, DefaultRequestTimeout),
 	}
 }
 
-func (rm *RequestManager) proxySelectorFunc() func(*http.Request) (*url.URL, error) {
-	return func(req *http.Request) (*url.URL, error) {
-		rm.mu.RLock()
-		cfg := rm.config
-		rm.mu.RUnlock()
+// SetTransport 替换底层传输层（如 FixtureTransport），代理路由与单次超时仍然生效；传nil恢复默认
+func (rm *RequestManager) SetTransport(rt http.RoundTripper) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	rm.transport = rt
+	rm.initClient()
+}
 
-		if hasProxyConfigured(cfg) {
-			if proxyStr := rm.getProxyFromPool(cfg); proxyStr != "" {
-				if proxyURL, err := url.Parse(proxyStr); err == nil {
-					return proxyURL, nil
-				}
-				log.Printf("[ProxyPool] 解析代理失败: %v", proxyStr)
-			}
-		}
+// baseTransport 返回当前底层传输层
+func (rm *RequestManager) baseTransport() http.RoundTripper {
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
+	return rm.base
+}
+
+// DefaultRequestTimeout 单次HTTP请求的默认超时，可通过 WithRequestTimeout 按请求覆盖
+const DefaultRequestTimeout = 5 * time.Second
+
+type requestTimeoutKey struct{}
+
+// WithRequestTimeout 覆盖 ctx 下单次HTTP请求的超时，用于响应较慢的接口
+func WithRequestTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, requestTimeoutKey{}, timeout)
+}
+
+// deadlineTransport 为每次请求附加单次超时（不晚于 ctx 本身的截止时间），
+// 超时覆盖到响应体读取完毕，替代固定的 http.Client.Timeout，使调用方可以通过 ctx 取消请求
+type deadlineTransport struct {
+	base    http.RoundTripper
+	timeout time.Duration
+}
+
+func withRequestDeadline(base http.RoundTripper, timeout time.Duration) http.RoundTripper {
+	return &deadlineTransport{base: base, timeout: timeout}
+}
 
-		return nil, nil
+// RoundTrip 实现 http.RoundTripper
+func (t *deadlineTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	timeout := t.timeout
+	if override, ok := req.Context().Value(requestTimeoutKey{}).(time.Duration); ok {
+		timeout = override
+	}
+	if timeout <= 0 {
+		return t.base.RoundTrip(req)
 	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), timeout)
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
 }
 
-func (rm *RequestManager) getProxyFromPool(cfg *models.Config) string {
+// cancelOnClose 关闭响应体时释放单次请求的超时上下文
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// getProxyFromPool 从代理池为 host 选取代理，列表过期或全部被剔除时重新拉取
+func (rm *RequestManager) getProxyFromPool(ctx context.Context, cfg *models.Config, host string) string {
 	if cfg == nil {
 		return ""
 	}
-	customPoolConfigured := strings.TrimSpace(cfg.ProxyPoolList) != "" || cfg.ProxyApiUrl != "" || cfg.ProxyApiKey != ""
-	if !cfg.ProxyPoolEnabled && !customPoolConfigured {
+	if !proxyPoolConfigured(cfg) {
 		return strings.TrimSpace(cfg.ProxyUrl)
 	}
 	if rm.proxyPool == nil {
@@ -186,13 +220,12 @@ func (rm *RequestManager) getProxyFromPool(cfg *models.Config) string {
 	defer pp.mu.Unlock()
 
 	now := time.Now()
-	if len(pp.proxies) == 0 || (!pp.expiresAt.IsZero() && now.After(pp.expiresAt)) {
-		proxies, ttl, err := rm.fetchProxyList(cfg)
+	if pp.healthyCountUnsafe() == 0 || (!pp.expiresAt.IsZero() && now.After(pp.expiresAt)) {
+		proxies, ttl, err := rm.fetchProxyList(ctx, cfg)
 		if err != nil {
 			log.Printf("[ProxyPool] 获取代理失败: %v", err)
-			pp.proxies = nil
+			pp.replaceProxiesUnsafe(nil)
 			pp.expiresAt = time.Time{}
-			pp.current = 0
 			pp.lastError = err.Error()
 			return ""
 		}
@@ -204,24 +237,17 @@ func (rm *RequestManager) getProxyFromPool(cfg *models.Config) string {
 		if ttl <= 0 {
 			ttl = rm.getProxyPoolTTL(cfg)
 		}
-		pp.proxies = proxies
+		pp.replaceProxiesUnsafe(proxies)
 		pp.expiresAt = now.Add(ttl)
-		pp.current = 0
 		pp.lastFetch = now
 		pp.lastError = ""
 		log.Printf("[ProxyPool] 拉取到 %d 个代理，有效期 %s", len(proxies), ttl)
 	}
 
-	if len(pp.proxies) == 0 {
-		return ""
-	}
-
-	proxy := pp.proxies[pp.current]
-	pp.current = (pp.current + 1) % len(pp.proxies)
-	return proxy
+	return pp.pickUnsafe(host, now)
 }
 
-func (rm *RequestManager) fetchProxyList(cfg *models.Config) ([]string, time.Duration, error) {
+func (rm *RequestManager) fetchProxyList(ctx context.Context, cfg *models.Config) ([]string, time.Duration, error) {
 	scheme := getProxyScheme(cfg)
 	provider := strings.ToLower(cfg.ProxyProvider)
 	manualList := strings.TrimSpace(cfg.ProxyPoolList)
@@ -238,8 +264,7 @@ func (rm *RequestManager) fetchProxyList(cfg *models.Config) ([]string, time.Dur
 
 	apiURL := rm.buildProxyAPIURL(cfg)
 	if apiURL != "" {
-		client := &http.Client{Timeout: 5 * time.Second}
-		resp, err := client.Get(apiURL)
+		resp, err := fetchProxyAPI(ctx, apiURL)
 		if err == nil {
 			defer resp.Body.Close()
 
@@ -269,6 +294,18 @@ func (rm *RequestManager) fetchProxyList(cfg *models.Config) ([]string, time.Dur
 	return nil, 0, fmt.Errorf("未配置可用的代理源")
 }
 
+// proxyAPIClient 拉取代理列表的客户端，不经过代理池本身
+var proxyAPIClient = &http.Client{Transport: withRequestDeadline(http.DefaultTransport, DefaultRequestTimeout)}
+
+// fetchProxyAPI 请求代理服务商接口
+func fetchProxyAPI(ctx context.Context, apiURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	return proxyAPIClient.Do(req)
+}
+
 func (rm *RequestManager) getProxyPoolTTL(cfg *models.Config) time.Duration {
 	ttl := time.Duration(cfg.ProxyPoolTTL) * time.Second
 	if ttl <= 0 {
@@ -467,24 +504,27 @@ func normalizeProxyString(value string, scheme string) string {
 	if value == "" {
 		return ""
 	}
-	lower := strings.ToLower(value)
-	if strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://") || strings.HasPrefix(lower, "socks5://") {
-		return value
+	if idx := strings.Index(value, "://"); idx > 0 {
+		if normalized := normalizeProxyScheme(value[:idx]); normalized != "" {
+			return normalized + value[idx:]
+		}
+		return ""
 	}
+	scheme = normalizeProxyScheme(strings.TrimSuffix(scheme, "://"))
 	if scheme == "" {
 		scheme = "http"
 	}
-	if !strings.HasSuffix(scheme, "://") {
-		scheme += "://"
-	}
-	return scheme + value
+	return scheme + "://" + value
 }
 
 func getProxyScheme(cfg *models.Config) string {
 	if cfg == nil || cfg.ProxyPoolProtocol == "" {
 		return "http"
 	}
-	return strings.ToLower(cfg.ProxyPoolProtocol)
+	if scheme := normalizeProxyScheme(cfg.ProxyPoolProtocol); scheme != "" {
+		return scheme
+	}
+	return "http"
 }
 
 func hasProxyConfigured(cfg *models.Config) bool {
@@ -510,7 +550,15 @@ func hasProxyConfigured(cfg *models.Config) bool {
 func (rm *RequestManager) UpdateConfig(config *models.Config) {
 	rm.mu.Lock()
 	defer rm.mu.Unlock()
+	changed := proxyConfigKey(rm.config) != proxyConfigKey(config)
 	rm.config = config
+	if config != nil {
+		rm.proxyRoutes = parseProxyRoutes(config.ProxyRoutes)
+	}
+	// 代理设置未变化时保留代理池的健康统计与连接
+	if !changed && rm.client != nil {
+		return
+	}
 	if rm.proxyPool != nil {
 		rm.proxyPool.reset()
 	}
@@ -540,49 +588,6 @@ func (rm *RequestManager) GetClient() *http.Client {
 	return rm.client
 }
 
-// GetCache 获取缓存数据
-func (rm *RequestManager) GetCache(key string) (interface{}, bool) {
-	rm.cache.mu.RLock()
-	defer rm.cache.mu.RUnlock()
-
-	item, exists := rm.cache.data[key]
-	if !exists {
-		return nil, false
-	}
-
-	if time.Now().After(item.ExpireAt) {
-		return nil, false
-	}
-
-	return item.Data, true
-}
-
-// SetCache 设置缓存数据
-func (rm *RequestManager) SetCache(key string, data interface{}, duration time.Duration) {
-	rm.cache.mu.Lock()
-	defer rm.cache.mu.Unlock()
-
-	rm.cache.data[key] = &CacheItem{
-		Data:      data,
-		ExpireAt:  time.Now().Add(duration),
-		CacheTime: duration,
-	}
-}
-
-// ClearCache 清除缓存
-func (rm *RequestManager) ClearCache(key string) {
-	rm.cache.mu.Lock()
-	defer rm.cache.mu.Unlock()
-	delete(rm.cache.data, key)
-}
-
-// ClearAllCache 清除所有缓存
-func (rm *RequestManager) ClearAllCache() {
-	rm.cache.mu.Lock()
-	defer rm.cache.mu.Unlock()
-	rm.cache.data = make(map[string]*CacheItem)
-}
-
 // MarkSourceFailed 标记数据源失败
 func (rm *RequestManager) MarkSourceFailed(sourceName string) {
 	rm.mu.Lock()
@@ -719,25 +724,23 @@ func init() {
 	rand.Seed(time.Now().UnixNano())
 }
 
+// webContentClient 抓取网页正文的客户端，单次请求默认10秒超时
+var webContentClient = &http.Client{Transport: withRequestDeadline(http.DefaultTransport, 10*time.Second)}
+
 // FetchWebContent 抓取网页内容
-func (rm *RequestManager) FetchWebContent(pageURL string) (string, error) {
+func (rm *RequestManager) FetchWebContent(ctx context.Context, pageURL string) (string, error) {
 	if pageURL == "" {
 		return "", nil
 	}
 
-	// 创建带超时的客户端
-	client := &http.Client{
-		Timeout: 10 * time.Second, // 10秒超时
-	}
-
-	req, err := http.NewRequest("GET", pageURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", pageURL, nil)
 	if err != nil {
 		return "", err
 	}
 
 	rm.SetRequestHeaders(req, "")
 
-	resp, err := client.Do(req)
+	resp, err := webContentClient.Do(req)
 	if err != nil {
 		return "", err
 	}
@@ -948,6 +951,7 @@ func decodeHTMLEntities(text string) string {
 
 // DoRequestWithRateLimit 带限流的HTTP请求
 // domain: 用于限流的域名标识（如 "eastmoney.com", "sina.com.cn"）
+// 排队优先级与取消信号取自 req.Context()，见 WithPriority
 func (rm *RequestManager) DoRequestWithRateLimit(domain string, req *http.Request) (*http.Response, error) {
 	var resp *http.Response
 	var err error
@@ -959,21 +963,26 @@ func (rm *RequestManager) DoRequestWithRateLimit(domain string, req *http.Reques
 		resp, err = rm.client.Do(req)
 		// 仍然记录请求，用于后续限流计算
 		rm.rateLimiter.RecordRequest(domain)
+		rm.rateLimiter.ObserveResponse(domain, resp)
 		return resp, err
 	}
 
 	// 后续请求：使用限流
-	err = rm.rateLimiter.ExecuteWithRateLimit(domain, func() error {
+	err = rm.rateLimiter.ExecuteWithContext(req.Context(), domain, func() error {
 		resp, err = rm.client.Do(req)
 		return err
 	})
+	if err == nil {
+		// 根据响应反馈调整该域名的自适应速率
+		rm.rateLimiter.ObserveResponse(domain, resp)
+	}
 
 	return resp, err
 }
 
 // GetWithRateLimit 带限流的GET请求
-func (rm *RequestManager) GetWithRateLimit(domain string, url string) (*http.Response, error) {
-	req, err := http.NewRequest("GET", url, nil)
+func (rm *RequestManager) GetWithRateLimit(ctx context.Context, domain string, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -981,11 +990,25 @@ func (rm *RequestManager) GetWithRateLimit(domain string, url string) (*http.Res
 	return rm.DoRequestWithRateLimit(domain, req)
 }
 
+// getWithContext 发起绑定 ctx 的GET请求
+func getWithContext(ctx context.Context, client *http.Client, rawURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	return client.Do(req)
+}
+
 // GetRateLimiterStats 获取限流器统计信息
 func (rm *RequestManager) GetRateLimiterStats(domain string) map[string]interface{} {
 	return rm.rateLimiter.GetStats(domain)
 }
 
+// GetRateLimitStatus 获取各域名的自适应限流状态
+func (rm *RequestManager) GetRateLimitStatus() []models.RateLimitStatus {
+	return rm.rateLimiter.GetAdaptiveStatus()
+}
+
 // extractDomainFromURL 从URL中提取域名
 func extractDomainFromURL(rawURL string) string {
 	u, err := url.Parse(rawURL)
@@ -1001,76 +1024,54 @@ func extractDomainFromURL(rawURL string) string {
 }
 
 // ==================== 缓存清理功能 ====================
+// 缓存由 backend/cache 统一管理并在后台清理过期条目，以下按分组清理
 
-// startCacheCleanupScheduler 启动缓存清理调度器
-func (rm *RequestManager) startCacheCleanupScheduler() {
-	// 每分钟检查一次过期缓存
-	ticker := time.NewTicker(1 * time.Minute)
-	defer ticker.Stop()
-
-	for range ticker.C {
-		rm.cleanupExpiredCache()
-	}
-}
-
-// cleanupExpiredCache 清理过期的缓存
-func (rm *RequestManager) cleanupExpiredCache() {
-	rm.cache.mu.Lock()
-	defer rm.cache.mu.Unlock()
-
-	now := time.Now()
-	expiredKeys := make([]string, 0)
-
-	for key, item := range rm.cache.data {
-		if now.After(item.ExpireAt) {
-			expiredKeys = append(expiredKeys, key)
-		}
-	}
-
-	for _, key := range expiredKeys {
-		delete(rm.cache.data, key)
-	}
-}
-
-// CleanupCacheByPrefix 按前缀清理缓存
-func (rm *RequestManager) CleanupCacheByPrefix(prefix string) int {
-	rm.cache.mu.Lock()
-	defer rm.cache.mu.Unlock()
-
+// ClearAllCache 清除所有缓存，保留用于启动展示与降级回退的市场数据快照
+func (rm *RequestManager) ClearAllCache() int {
 	count := 0
-	for key := range rm.cache.data {
-		if strings.HasPrefix(key, prefix) {
-			delete(rm.cache.data, key)
-			count++
+	for _, ns := range cache.Namespaces("") {
+		if cache.GroupOf(ns.Name()) != CacheGroupSnapshot {
+			count += ns.Purge()
 		}
 	}
 	return count
 }
 
-// CleanupQuoteCache 清理行情缓存（5分钟前的数据）
+// CleanupQuoteCache 清理行情缓存
 func (rm *RequestManager) CleanupQuoteCache() int {
-	return rm.CleanupCacheByPrefix("quote_")
+	return cache.Purge(CacheGroupQuote)
 }
 
 // CleanupNewsCache 清理新闻缓存
 func (rm *RequestManager) CleanupNewsCache() int {
-	return rm.CleanupCacheByPrefix("news_")
+	return cache.Purge(CacheGroupNews)
 }
 
 // CleanupReportCache 清理研报缓存
 func (rm *RequestManager) CleanupReportCache() int {
-	return rm.CleanupCacheByPrefix("report_")
+	return cache.Purge(CacheGroupReport)
 }
 
 // CleanupNoticeCache 清理公告缓存
 func (rm *RequestManager) CleanupNoticeCache() int {
-	return rm.CleanupCacheByPrefix("notice_")
+	return cache.Purge(CacheGroupNotice)
+}
+
+// CleanupFinancialCache 清理财务数据缓存（含磁盘层）
+func (rm *RequestManager) CleanupFinancialCache() int {
+	return cache.Purge(CacheGroupFinancial)
+}
+
+// CleanupExpiredFinancialCache 仅清理已过期的财务数据缓存
+func (rm *RequestManager) CleanupExpiredFinancialCache() int {
+	return cache.PurgeExpired(CacheGroupFinancial)
 }
 
 // GetProxyStatus 返回代理池状态
 func (rm *RequestManager) GetProxyStatus() models.ProxyStatus {
 	rm.mu.RLock()
 	cfg := rm.config
+	rules := rm.proxyRoutes
 	rm.mu.RUnlock()
 
 	status := models.ProxyStatus{
@@ -1078,15 +1079,23 @@ func (rm *RequestManager) GetProxyStatus() models.ProxyStatus {
 		Provider:    "",
 		PoolEnabled: false,
 	}
+	for _, rule := range rules {
+		if rule.mode == ProxyRouteUpstream {
+			status.Enabled = true
+		}
+		status.Routes = append(status.Routes, rule.pattern+" → "+routeTargetLabel(rule))
+	}
 
 	if cfg != nil {
 		status.Provider = cfg.ProxyProvider
-		status.PoolEnabled = cfg.ProxyPoolEnabled || strings.TrimSpace(cfg.ProxyPoolList) != "" || cfg.ProxyApiUrl != "" || cfg.ProxyApiKey != ""
+		status.PoolEnabled = proxyPoolConfigured(cfg)
 	}
 
 	if rm.proxyPool != nil {
 		rm.proxyPool.mu.Lock()
 		status.ActiveProxies = len(rm.proxyPool.proxies)
+		status.HealthyProxies = rm.proxyPool.healthyCountUnsafe()
+		status.Proxies = rm.proxyPool.proxyHealthStatusUnsafe()
 		if !rm.proxyPool.expiresAt.IsZero() {
 			status.ExpiresAt = rm.proxyPool.expiresAt.Format(time.RFC3339)
 			seconds := int(time.Until(rm.proxyPool.expiresAt).Seconds())
@@ -1107,43 +1116,20 @@ func (rm *RequestManager) GetProxyStatus() models.ProxyStatus {
 
 // GetCacheStats 获取缓存统计信息
 func (rm *RequestManager) GetCacheStats() map[string]interface{} {
-	rm.cache.mu.RLock()
-	defer rm.cache.mu.RUnlock()
-
-	totalCount := len(rm.cache.data)
+	namespaces := cache.AllStats()
+	totalCount := 0
 	expiredCount := 0
-	now := time.Now()
-
-	// 按类型统计
 	typeCount := make(map[string]int)
-	for key, item := range rm.cache.data {
-		if now.After(item.ExpireAt) {
-			expiredCount++
-		}
-
-		// 提取缓存类型
-		cacheType := "other"
-		if strings.HasPrefix(key, "quote_") {
-			cacheType = "quote"
-		} else if strings.HasPrefix(key, "news_") {
-			cacheType = "news"
-		} else if strings.HasPrefix(key, "report_") {
-			cacheType = "report"
-		} else if strings.HasPrefix(key, "notice_") {
-			cacheType = "notice"
-		} else if strings.HasPrefix(key, "financial_") {
-			cacheType = "financial"
-		} else if strings.HasPrefix(key, "index_") {
-			cacheType = "index"
-		} else if strings.HasPrefix(key, "industry_") {
-			cacheType = "industry"
-		}
-		typeCount[cacheType]++
+	for _, ns := range namespaces {
+		totalCount += ns.Entries
+		expiredCount += ns.Expired
+		typeCount[cache.GroupOf(ns.Namespace)] += ns.Entries
 	}
 
 	return map[string]interface{}{
 		"totalCount":   totalCount,
 		"expiredCount": expiredCount,
 		"typeCount":    typeCount,
+		"namespaces":   namespaces,
 	}
 }
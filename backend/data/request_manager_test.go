@@ -0,0 +1,44 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestDeadlineTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(200 * time.Millisecond):
+			io.WriteString(w, "ok")
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: withRequestDeadline(http.DefaultTransport, 50*time.Millisecond)}
+
+	if _, err := getWithContext(context.Background(), client, srv.URL); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("默认超时 err = %v", err)
+	}
+
+	resp, err := getWithContext(WithRequestTimeout(context.Background(), time.Second), client, srv.URL)
+	if err != nil {
+		t.Fatalf("覆盖超时 err = %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "ok" {
+		t.Fatalf("覆盖超时 body = %q", body)
+	}
+
+	ctx, cancel := context.WithCancel(WithRequestTimeout(context.Background(), time.Second))
+	time.AfterFunc(20*time.Millisecond, cancel)
+	if _, err := getWithContext(ctx, client, srv.URL); !errors.Is(err, context.Canceled) {
+		t.Fatalf("调用方取消 err = %v", err)
+	}
+}
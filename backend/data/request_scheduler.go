@@ -0,0 +1,301 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"time"
+)
+
+// ==================== 优先级请求调度 ====================
+//
+// 同一域名的请求按优先级排队：用户正在查看的数据 > 预警相关刷新 > 后台预加载 > 批量同步。
+// 只有队首的请求可以占用槽位，随机延迟期间若有更高优先级的请求到达，队首让位并重新排队。
+// 每个域名另有一个令牌桶，低优先级通道取令牌时必须给高优先级通道留出余量，
+// 避免预加载把额度耗尽。优先级与取消信号都通过 context 传递，应用退出时排队中的请求全部放弃。
+
+// RequestPriority 请求优先级，数值越小优先级越高
+type RequestPriority int
+
+const (
+	PriorityInteractive RequestPriority = iota // 用户正在查看的数据
+	PriorityAlert                              // 预警相关的价格刷新
+	PriorityPrefetch                           // 后台预加载
+	PriorityBulk                               // 批量同步（财报、估值等）
+)
+
+// priorityNames 优先级名称，用于状态展示
+var priorityNames = [...]string{"interactive", "alert", "prefetch", "bulk"}
+
+// laneReserve 各优先级取令牌后桶内至少保留的比例，留给更高优先级的请求
+var laneReserve = [...]float64{0, 0, 0.25, 0.5}
+
+// String 优先级名称
+func (p RequestPriority) String() string {
+	if p < 0 || int(p) >= len(priorityNames) {
+		return "unknown"
+	}
+	return priorityNames[p]
+}
+
+// ErrSchedulerClosed 调度器已关闭（应用正在退出）
+var ErrSchedulerClosed = errors.New("请求调度器已关闭")
+
+type priorityKey struct{}
+
+// WithPriority 为 ctx 标记请求优先级
+func WithPriority(ctx context.Context, priority RequestPriority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, priority)
+}
+
+// PriorityFromContext 读取 ctx 中的请求优先级，未标记时视为用户交互请求
+func PriorityFromContext(ctx context.Context) RequestPriority {
+	if ctx != nil {
+		if priority, ok := ctx.Value(priorityKey{}).(RequestPriority); ok {
+			return priority
+		}
+	}
+	return PriorityInteractive
+}
+
+// slotWaiter 等待槽位的请求
+type slotWaiter struct {
+	priority RequestPriority
+	seq      uint64
+	readyAt  time.Time // 随机延迟结束时间，零值表示尚未成为队首
+}
+
+// tokenBucket 域名令牌桶
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// domainLanes 单个域名的等待队列与令牌桶
+type domainLanes struct {
+	waiters []*slotWaiter // 按优先级、到达顺序排列
+	changed chan struct{} // 队列变化时关闭并替换，唤醒等待者重新检查
+	bucket  tokenBucket
+}
+
+func newDomainLanes() *domainLanes {
+	return &domainLanes{changed: make(chan struct{})}
+}
+
+func (q *domainLanes) notify() {
+	close(q.changed)
+	q.changed = make(chan struct{})
+}
+
+func (q *domainLanes) push(w *slotWaiter) {
+	idx := len(q.waiters)
+	for i, other := range q.waiters {
+		if w.priority < other.priority {
+			idx = i
+			break
+		}
+	}
+	q.waiters = append(q.waiters, nil)
+	copy(q.waiters[idx+1:], q.waiters[idx:])
+	q.waiters[idx] = w
+	q.notify()
+}
+
+func (q *domainLanes) remove(w *slotWaiter) {
+	for i, other := range q.waiters {
+		if other == w {
+			q.waiters = append(q.waiters[:i], q.waiters[i+1:]...)
+			q.notify()
+			return
+		}
+	}
+}
+
+func (q *domainLanes) head() *slotWaiter {
+	if len(q.waiters) == 0 {
+		return nil
+	}
+	return q.waiters[0]
+}
+
+// refill 按速率补充令牌，rate 为每秒令牌数
+func (b *tokenBucket) refill(now time.Time, capacity, rate float64) {
+	if b.last.IsZero() {
+		b.tokens = capacity
+	} else {
+		b.tokens = math.Min(capacity, b.tokens+now.Sub(b.last).Seconds()*rate)
+	}
+	b.last = now
+}
+
+// waitFor 返回优先级 priority 取到一个令牌还需等待的时间，0 表示可以立即取
+func (b *tokenBucket) waitFor(priority RequestPriority, capacity, rate float64) time.Duration {
+	need := 1 + laneReserve[priority]*capacity
+	if need > capacity {
+		need = capacity
+	}
+	if b.tokens >= need {
+		return 0
+	}
+	return time.Duration((need - b.tokens) / rate * float64(time.Second))
+}
+
+// lanesUnsafe 获取或创建域名的等待队列（调用方持有锁）
+func (rl *RateLimiter) lanesUnsafe(domain string) *domainLanes {
+	q, ok := rl.lanes[domain]
+	if !ok {
+		q = newDomainLanes()
+		rl.lanes[domain] = q
+	}
+	return q
+}
+
+// Acquire 按 ctx 中的优先级排队等待 domain 的请求槽位，取得后记录本次请求
+// ctx 取消或调度器关闭时放弃排队并返回错误
+func (rl *RateLimiter) Acquire(ctx context.Context, domain string) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	w := &slotWaiter{priority: PriorityFromContext(ctx)}
+	if w.priority < PriorityInteractive || w.priority > PriorityBulk {
+		w.priority = PriorityInteractive
+	}
+
+	rl.mu.Lock()
+	if rl.isClosed() {
+		rl.mu.Unlock()
+		return ErrSchedulerClosed
+	}
+	rl.seq++
+	w.seq = rl.seq
+	q := rl.lanesUnsafe(domain)
+	q.push(w)
+	rl.mu.Unlock()
+
+	defer func() {
+		rl.mu.Lock()
+		q.remove(w)
+		rl.mu.Unlock()
+	}()
+
+	for {
+		rl.mu.Lock()
+		wait, granted := rl.trySlotUnsafe(domain, q, w, time.Now())
+		changed := q.changed
+		rl.mu.Unlock()
+		if granted {
+			return nil
+		}
+
+		var timer *time.Timer
+		var timeout <-chan time.Time
+		if wait > 0 {
+			timer = time.NewTimer(wait)
+			timeout = timer.C
+		}
+		select {
+		case <-ctx.Done():
+			stopTimer(timer)
+			return ctx.Err()
+		case <-rl.closed:
+			stopTimer(timer)
+			return ErrSchedulerClosed
+		case <-changed:
+		case <-timeout:
+		}
+		stopTimer(timer)
+	}
+}
+
+// trySlotUnsafe 尝试为队首请求分配槽位，返回还需等待的时间；非队首请求返回 0，等待队列变化
+func (rl *RateLimiter) trySlotUnsafe(domain string, q *domainLanes, w *slotWaiter, now time.Time) (time.Duration, bool) {
+	if q.head() != w {
+		// 被更高优先级的请求抢占，重新成为队首时重新计算随机延迟
+		w.readyAt = time.Time{}
+		return 0, false
+	}
+
+	if ok, wait := rl.canRequestUnsafe(domain); !ok {
+		return wait, false
+	}
+
+	config := rl.getConfigUnsafe(domain)
+	capacity := float64(config.BurstThreshold)
+	if capacity < 1 {
+		capacity = 1
+	}
+	rate := float64(scaleLimit(config.MaxRequestsPerMinute, rl.adaptiveFactorUnsafe(domain))) / 60
+	q.bucket.refill(now, capacity, rate)
+	if wait := q.bucket.waitFor(w.priority, capacity, rate); wait > 0 {
+		return wait, false
+	}
+
+	if w.readyAt.IsZero() {
+		delay := rl.randomDelayUnsafe(domain)
+		w.readyAt = now.Add(delay)
+		log.Printf("[RateLimiter] 域名 %s 等待随机延迟 %.1f 秒 (%s)", domain, delay.Seconds(), w.priority)
+	}
+	if now.Before(w.readyAt) {
+		return w.readyAt.Sub(now), false
+	}
+
+	q.bucket.tokens--
+	q.remove(w)
+	rl.recordRequestUnsafe(domain, now)
+	return 0, true
+}
+
+// ExecuteWithContext 按 ctx 中的优先级排队取得槽位后执行 fn
+func (rl *RateLimiter) ExecuteWithContext(ctx context.Context, domain string, fn func() error) error {
+	if err := rl.Acquire(ctx, domain); err != nil {
+		return err
+	}
+	return fn()
+}
+
+// QueueRequest 将请求加入对应优先级的队列（异步执行）
+func (rl *RateLimiter) QueueRequest(ctx context.Context, domain string, fn func() error) <-chan error {
+	result := make(chan error, 1)
+	go func() {
+		result <- rl.ExecuteWithContext(ctx, domain, fn)
+		close(result)
+	}()
+	return result
+}
+
+// Shutdown 关闭调度器，排队中的请求全部放弃，之后的请求不再排队
+func (rl *RateLimiter) Shutdown() {
+	rl.closeOnce.Do(func() {
+		close(rl.closed)
+		log.Printf("[RateLimiter] 调度器已关闭，放弃排队中的请求")
+	})
+}
+
+func (rl *RateLimiter) isClosed() bool {
+	select {
+	case <-rl.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+// queuedByPriorityUnsafe 统计域名各优先级的排队数量（调用方持有锁）
+func (rl *RateLimiter) queuedByPriorityUnsafe(domain string) map[string]int {
+	q, ok := rl.lanes[domain]
+	if !ok || len(q.waiters) == 0 {
+		return nil
+	}
+	queued := make(map[string]int)
+	for _, w := range q.waiters {
+		queued[w.priority.String()]++
+	}
+	return queued
+}
+
+func stopTimer(timer *time.Timer) {
+	if timer != nil {
+		timer.Stop()
+	}
+}
@@ -0,0 +1,95 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"stock-ai/backend/cache"
+)
+
+func newSchedulerTestLimiter(t *testing.T, domain string) *RateLimiter {
+	t.Helper()
+	cache.SetDiskDir(t.TempDir())
+	t.Cleanup(func() { cache.SetDiskDir("") })
+
+	rl := NewRateLimiter()
+	rl.UpdateConfig(domain, &DomainConfig{
+		MaxRequestsPerMinute: 600,
+		MaxRequestsPerHour:   6000,
+		MinIntervalMs:        40,
+		MaxIntervalMs:        40,
+		CooldownAfterBurst:   1,
+		BurstThreshold:       100,
+	})
+	return rl
+}
+
+func TestSchedulerServesHigherPriorityFirst(t *testing.T) {
+	const domain = "sched.test"
+	rl := newSchedulerTestLimiter(t, domain)
+	rl.RecordRequest(domain) // 占用最小间隔，后续请求需要排队
+
+	var mu sync.Mutex
+	var order []RequestPriority
+	var wg sync.WaitGroup
+	start := func(priority RequestPriority) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := rl.Acquire(WithPriority(context.Background(), priority), domain); err != nil {
+				t.Errorf("Acquire(%s): %v", priority, err)
+				return
+			}
+			mu.Lock()
+			order = append(order, priority)
+			mu.Unlock()
+		}()
+		time.Sleep(5 * time.Millisecond)
+	}
+	start(PriorityBulk)
+	start(PriorityPrefetch)
+	start(PriorityInteractive)
+	wg.Wait()
+
+	want := []RequestPriority{PriorityInteractive, PriorityPrefetch, PriorityBulk}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("order = %v, want %v", order, want)
+		}
+	}
+}
+
+func TestSchedulerDropsQueuedOnShutdownAndCancel(t *testing.T) {
+	const domain = "sched.test"
+	rl := newSchedulerTestLimiter(t, domain)
+	rl.ReportThrottle(domain, throttleReasonTooMany, time.Minute)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelled := make(chan error, 1)
+	go func() { cancelled <- rl.Acquire(ctx, domain) }()
+	time.Sleep(5 * time.Millisecond)
+	cancel()
+	if err := <-cancelled; !errors.Is(err, context.Canceled) {
+		t.Fatalf("取消后 Acquire = %v", err)
+	}
+
+	background := rl.QueueRequest(WithPriority(context.Background(), PriorityPrefetch), domain, func() error { return nil })
+	time.Sleep(5 * time.Millisecond)
+	rl.Shutdown()
+	if err := <-background; !errors.Is(err, ErrSchedulerClosed) {
+		t.Fatalf("关闭后排队请求 = %v", err)
+	}
+}
+
+func TestTokenBucketKeepsReserveForHigherLanes(t *testing.T) {
+	b := tokenBucket{tokens: 2}
+	if wait := b.waitFor(PriorityInteractive, 4, 1); wait != 0 {
+		t.Fatalf("交互请求应可直接取令牌, wait=%v", wait)
+	}
+	if wait := b.waitFor(PriorityBulk, 4, 1); wait != time.Second {
+		t.Fatalf("批量同步需给高优先级留出余量, wait=%v", wait)
+	}
+}
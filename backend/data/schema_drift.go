@@ -0,0 +1,417 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"stock-ai/backend/models"
+)
+
+// ==================== 数据源结构漂移检测 ====================
+
+// 上游接口改字段名后，解析结果通常不会报错，而是整批变成空字符串或0。
+// 各解析器声明字段约束，每批解析结果交给 SchemaMonitor 校验：
+// 违规批次连续出现时把数据源标记为结构异常，降级链跳过该数据源，并保存原始响应样本供排查
+
+// 数据集标识
+const (
+	SchemaDatasetQuote    = "quote"    // A股实时行情
+	SchemaDatasetIndex    = "index"    // 全球指数
+	SchemaDatasetIndustry = "industry" // 行业排行
+	SchemaDatasetFund     = "fund"     // 基金估值
+)
+
+// 违规类型
+const (
+	SchemaRuleMissing    = "missing"
+	SchemaRuleOutOfRange = "out_of_range"
+	SchemaRuleAllZero    = "all_zero"
+)
+
+const (
+	schemaBadRatio      = 0.5              // 违规记录占比达到该值视为违规批次
+	schemaMinVaryBatch  = 3                // 记录数不少于该值时才检查“整批为零”
+	schemaBrokenAfter   = 2                // 连续违规批次达到该值标记为结构异常
+	schemaRecheckAfter  = 15 * time.Minute // 结构异常后隔一段时间放行一次，校验通过即恢复
+	schemaSampleMaxSize = 256 * 1024       // 样本最多保存的字节数
+	schemaRecentLimit   = 50
+)
+
+// ErrSchemaBroken 数据源返回的数据不符合字段约束
+var ErrSchemaBroken = errors.New("数据源结构异常")
+
+// FieldRule 单个字段的约束
+type FieldRule struct {
+	Field    string
+	Required bool    // 每条记录都必须有值（非空字符串/非零数值）
+	Varies   bool    // 整批记录不能全部为零值，单条可以为0（如平盘涨跌幅）
+	Min, Max float64 // 数值合理范围，Max>Min 时检查
+}
+
+// SourceSchema 数据源某个数据集的字段约束
+type SourceSchema struct {
+	Source  string
+	Dataset string
+	Fields  []FieldRule
+}
+
+// schemaRecord 一条解析结果，字段名 -> 字符串或数值
+type schemaRecord map[string]interface{}
+
+// schemaState 单个数据源数据集的校验状态
+type schemaState struct {
+	health      models.SchemaSourceHealth
+	brokenSince time.Time
+}
+
+// SchemaMonitor 结构漂移监测器，nil 表示不做校验
+type SchemaMonitor struct {
+	sampleDir string
+	states    map[string]*schemaState
+	recent    []models.SchemaDriftEvent
+	onEvent   func(models.SchemaDriftEvent)
+	mu        sync.Mutex
+}
+
+var globalSchemaMonitor *SchemaMonitor
+var schemaMonitorOnce sync.Once
+
+// GetSchemaMonitor 获取全局结构漂移监测器，样本保存在 ~/.stock-ai/diagnostics/schema
+func GetSchemaMonitor() *SchemaMonitor {
+	schemaMonitorOnce.Do(func() {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			homeDir = "."
+		}
+		globalSchemaMonitor = NewSchemaMonitor(filepath.Join(homeDir, ".stock-ai", "diagnostics", "schema"))
+	})
+	return globalSchemaMonitor
+}
+
+// NewSchemaMonitor 创建结构漂移监测器，sampleDir 为空时不保存样本
+func NewSchemaMonitor(sampleDir string) *SchemaMonitor {
+	return &SchemaMonitor{sampleDir: sampleDir, states: make(map[string]*schemaState)}
+}
+
+// SetEventHandler 设置诊断事件回调（在校验所在的goroutine中调用，不持有锁）
+func (m *SchemaMonitor) SetEventHandler(handler func(models.SchemaDriftEvent)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onEvent = handler
+}
+
+func schemaKey(source, dataset string) string {
+	return source + "/" + dataset
+}
+
+// IsBroken 数据源数据集是否处于结构异常状态；超过复查间隔后返回 false，放行一次用于复查
+func (m *SchemaMonitor) IsBroken(source, dataset string) bool {
+	if m == nil {
+		return false
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	state, ok := m.states[schemaKey(source, dataset)]
+	if !ok || !state.health.Broken {
+		return false
+	}
+	return time.Since(state.brokenSince) < schemaRecheckAfter
+}
+
+// BrokenDatasets 返回数据源当前处于结构异常的数据集
+func (m *SchemaMonitor) BrokenDatasets(source string) []string {
+	if m == nil {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var datasets []string
+	for _, state := range m.states {
+		if state.health.Source == source && state.health.Broken {
+			datasets = append(datasets, state.health.Dataset)
+		}
+	}
+	sort.Strings(datasets)
+	return datasets
+}
+
+// Check 校验一批解析结果；违规批次返回 ErrSchemaBroken，调用方应按请求失败处理以便降级到其它数据源
+// 空批次不校验：接口对无效代码返回空数据是正常情况，由调用方的“空数据”逻辑处理
+func (m *SchemaMonitor) Check(schema *SourceSchema, payload []byte, records []schemaRecord) error {
+	if m == nil || schema == nil || len(records) == 0 {
+		return nil
+	}
+	violations := schema.validate(records)
+	bad := isBadBatch(violations, len(records))
+
+	m.mu.Lock()
+	key := schemaKey(schema.Source, schema.Dataset)
+	state, ok := m.states[key]
+	if !ok {
+		state = &schemaState{health: models.SchemaSourceHealth{
+			Source: schema.Source, Dataset: schema.Dataset, Violations: make(map[string]int64),
+		}}
+		m.states[key] = state
+	}
+	h := &state.health
+	h.Batches++
+	for _, v := range violations {
+		h.Violations[v.Field+":"+v.Rule] += int64(v.Count)
+	}
+
+	if !bad {
+		h.ConsecutiveBad = 0
+		if h.Broken {
+			h.Broken = false
+			h.BrokenSince = ""
+			log.Printf("[结构校验] 数据源 %s/%s 字段恢复正常", schema.Source, schema.Dataset)
+		}
+		m.mu.Unlock()
+		return nil
+	}
+
+	h.BadBatches++
+	h.ConsecutiveBad++
+	// 已标记异常时只在复查失败时续期，不重复上报
+	report := !h.Broken
+	if h.ConsecutiveBad >= schemaBrokenAfter {
+		if !h.Broken {
+			log.Printf("[结构校验] 数据源 %s/%s 连续 %d 批数据不符合字段约束，标记为结构异常", schema.Source, schema.Dataset, h.ConsecutiveBad)
+		}
+		h.Broken = true
+		state.brokenSince = time.Now()
+		h.BrokenSince = state.brokenSince.Format("2006-01-02 15:04:05")
+	}
+
+	var event models.SchemaDriftEvent
+	var handler func(models.SchemaDriftEvent)
+	if report {
+		event = models.SchemaDriftEvent{
+			Source: schema.Source, Dataset: schema.Dataset, Records: len(records), Violations: violations,
+			Broken: h.Broken, Time: time.Now().Format("2006-01-02 15:04:05"),
+		}
+		if path, err := m.saveSample(schema, payload); err != nil {
+			log.Printf("[结构校验] 保存样本失败: %v", err)
+		} else {
+			event.SamplePath = path
+			h.LastSamplePath = path
+		}
+		m.recent = append(m.recent, event)
+		if len(m.recent) > schemaRecentLimit {
+			m.recent = m.recent[len(m.recent)-schemaRecentLimit:]
+		}
+		handler = m.onEvent
+	}
+	m.mu.Unlock()
+
+	if report {
+		log.Printf("[结构校验] 数据源 %s/%s 字段校验失败: %s", schema.Source, schema.Dataset, describeViolations(violations))
+		if handler != nil {
+			handler(event)
+		}
+	}
+	return fmt.Errorf("%w: %s/%s %s", ErrSchemaBroken, schema.Source, schema.Dataset, describeViolations(violations))
+}
+
+// saveSample 保存原始响应样本（调用方持有锁）
+func (m *SchemaMonitor) saveSample(schema *SourceSchema, payload []byte) (string, error) {
+	if m.sampleDir == "" || len(payload) == 0 {
+		return "", nil
+	}
+	if err := os.MkdirAll(m.sampleDir, 0755); err != nil {
+		return "", err
+	}
+	if len(payload) > schemaSampleMaxSize {
+		payload = payload[:schemaSampleMaxSize]
+	}
+	name := fmt.Sprintf("%s-%s-%s.txt", schema.Source, schema.Dataset, time.Now().Format("20060102-150405.000"))
+	path := filepath.Join(m.sampleDir, name)
+	return path, os.WriteFile(path, payload, 0644)
+}
+
+// Stats 获取校验统计快照
+func (m *SchemaMonitor) Stats() models.SchemaHealthStats {
+	var stats models.SchemaHealthStats
+	if m == nil {
+		return stats
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, state := range m.states {
+		h := state.health
+		h.Violations = make(map[string]int64, len(state.health.Violations))
+		for k, n := range state.health.Violations {
+			h.Violations[k] = n
+		}
+		stats.Sources = append(stats.Sources, h)
+	}
+	sort.Slice(stats.Sources, func(i, j int) bool {
+		if stats.Sources[i].Broken != stats.Sources[j].Broken {
+			return stats.Sources[i].Broken
+		}
+		return schemaKey(stats.Sources[i].Source, stats.Sources[i].Dataset) < schemaKey(stats.Sources[j].Source, stats.Sources[j].Dataset)
+	})
+	stats.Recent = append([]models.SchemaDriftEvent(nil), m.recent...)
+	return stats
+}
+
+// validate 按字段约束校验一批记录，返回按字段汇总的违规
+func (s *SourceSchema) validate(records []schemaRecord) []models.SchemaViolation {
+	var violations []models.SchemaViolation
+	for _, rule := range s.Fields {
+		missing, outOfRange, nonZero := 0, 0, 0
+		var missingExample, rangeExample string
+		for _, rec := range records {
+			value, present := rec[rule.Field]
+			zero := isZeroField(value)
+			if !zero {
+				nonZero++
+			}
+			if rule.Required && (!present || zero) {
+				missing++
+				if missingExample == "" {
+					missingExample = fmt.Sprintf("%v", rec)
+				}
+				continue
+			}
+			if num, ok := value.(float64); ok && rule.Max > rule.Min && !zero && (num < rule.Min || num > rule.Max) {
+				outOfRange++
+				if rangeExample == "" {
+					rangeExample = fmt.Sprintf("%s=%v", rule.Field, num)
+				}
+			}
+		}
+		if missing > 0 {
+			violations = append(violations, models.SchemaViolation{Field: rule.Field, Rule: SchemaRuleMissing, Count: missing, Example: missingExample})
+		}
+		if outOfRange > 0 {
+			violations = append(violations, models.SchemaViolation{Field: rule.Field, Rule: SchemaRuleOutOfRange, Count: outOfRange, Example: rangeExample})
+		}
+		if rule.Varies && nonZero == 0 && len(records) >= schemaMinVaryBatch {
+			violations = append(violations, models.SchemaViolation{Field: rule.Field, Rule: SchemaRuleAllZero, Count: len(records)})
+		}
+	}
+	return violations
+}
+
+// isBadBatch 整批为零值，或任一字段的违规记录占比达到阈值时视为违规批次
+func isBadBatch(violations []models.SchemaViolation, records int) bool {
+	for _, v := range violations {
+		if v.Rule == SchemaRuleAllZero || float64(v.Count) >= float64(records)*schemaBadRatio {
+			return true
+		}
+	}
+	return false
+}
+
+func isZeroField(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(v) == "" || v == "-"
+	case float64:
+		return v == 0
+	case int64:
+		return v == 0
+	}
+	return false
+}
+
+func describeViolations(violations []models.SchemaViolation) string {
+	parts := make([]string, 0, len(violations))
+	for _, v := range violations {
+		parts = append(parts, fmt.Sprintf("%s %s x%d", v.Field, v.Rule, v.Count))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ==================== 各数据源的字段约束 ====================
+
+// A股行情：名称与昨收必须存在；现价在集合竞价前整批为0，只检查范围
+var stockQuoteFields = []FieldRule{
+	{Field: "name", Required: true},
+	{Field: "preClose", Required: true, Min: 0.01, Max: 100000},
+	{Field: "price", Min: 0.01, Max: 100000},
+	{Field: "changePercent", Min: -50, Max: 1000}, // 新股首日不设涨跌幅限制
+}
+
+func stockQuoteSchema(source string) *SourceSchema {
+	return &SourceSchema{Source: source, Dataset: SchemaDatasetQuote, Fields: stockQuoteFields}
+}
+
+func stockPriceRecords(prices map[string]*models.StockPrice) []schemaRecord {
+	records := make([]schemaRecord, 0, len(prices))
+	for _, p := range prices {
+		if p == nil {
+			continue
+		}
+		records = append(records, schemaRecord{
+			"code": p.Code, "name": p.Name, "price": p.Price, "preClose": p.PreClose, "changePercent": p.ChangePercent,
+		})
+	}
+	return records
+}
+
+// 全球指数：点位必须存在，涨跌幅不会超出 ±30%
+var globalIndexFields = []FieldRule{
+	{Field: "name", Required: true},
+	{Field: "price", Required: true, Min: 1, Max: 1000000},
+	{Field: "changePercent", Varies: true, Min: -30, Max: 30},
+}
+
+func globalIndexSchema(source string) *SourceSchema {
+	return &SourceSchema{Source: source, Dataset: SchemaDatasetIndex, Fields: globalIndexFields}
+}
+
+func indexRecords(indices map[string]*IndexData) []schemaRecord {
+	records := make([]schemaRecord, 0, len(indices))
+	for _, d := range indices {
+		records = append(records, schemaRecord{"code": d.Code, "name": d.Name, "price": d.Price, "changePercent": d.ChangePercent})
+	}
+	return records
+}
+
+// 行业排行：涨跌幅整批为0说明字段已改名
+var industryRankSchema = &SourceSchema{Source: "tencent", Dataset: SchemaDatasetIndustry, Fields: []FieldRule{
+	{Field: "name", Required: true},
+	{Field: "changePercent", Varies: true, Min: -25, Max: 25},
+}}
+
+func industryRankRecords(items []models.IndustryRank) []schemaRecord {
+	records := make([]schemaRecord, 0, len(items))
+	for _, item := range items {
+		records = append(records, schemaRecord{"name": item.Name, "changePercent": item.ChangePercent, "leadStock": item.LeadStock})
+	}
+	return records
+}
+
+// 基金估值：净值必须存在，单日涨跌幅不超过 ±20%
+var fundPriceFields = []FieldRule{
+	{Field: "name", Required: true},
+	{Field: "nav", Required: true, Min: 0.001, Max: 10000},
+	{Field: "changePercent", Min: -20, Max: 20},
+}
+
+func fundPriceSchema(source string) *SourceSchema {
+	return &SourceSchema{Source: source, Dataset: SchemaDatasetFund, Fields: fundPriceFields}
+}
+
+func fundPriceRecords(prices map[string]*models.FundPrice) []schemaRecord {
+	records := make([]schemaRecord, 0, len(prices))
+	for _, p := range prices {
+		if p == nil {
+			continue
+		}
+		records = append(records, schemaRecord{"code": p.Code, "name": p.Name, "nav": p.Nav, "changePercent": p.ChangePercent})
+	}
+	return records
+}
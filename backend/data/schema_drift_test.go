@@ -0,0 +1,176 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"stock-ai/backend/models"
+)
+
+func TestSourceSchemaValidate(t *testing.T) {
+	quote := func(name string, price, preClose float64) schemaRecord {
+		return schemaRecord{"name": name, "price": price, "preClose": preClose, "changePercent": 0.0}
+	}
+	industry := func(pcts ...float64) []schemaRecord {
+		var records []schemaRecord
+		for _, p := range pcts {
+			records = append(records, schemaRecord{"name": "行业", "changePercent": p})
+		}
+		return records
+	}
+
+	tests := []struct {
+		name    string
+		schema  *SourceSchema
+		records []schemaRecord
+		rules   []string // 期望的 字段:违规类型
+		bad     bool
+	}{
+		{"正常行情", stockQuoteSchema("sina"), []schemaRecord{quote("贵州茅台", 1688, 1670), quote("平安银行", 11.2, 11.1)}, nil, false},
+		{"集合竞价前现价为0", stockQuoteSchema("sina"), []schemaRecord{quote("贵州茅台", 0, 1670), quote("平安银行", 0, 11.1), quote("浦发银行", 0, 10.5)}, nil, false},
+		{"名称字段改名", stockQuoteSchema("sina"), []schemaRecord{quote("", 1688, 1670), quote("", 11.2, 11.1)}, []string{"name:missing"}, true},
+		{"少量记录缺失不算违规批次", stockQuoteSchema("sina"), []schemaRecord{quote("", 1688, 1670), quote("平安银行", 11.2, 11.1), quote("浦发银行", 10.5, 10.4)}, []string{"name:missing"}, false},
+		{"价格单位变化", stockQuoteSchema("eastmoney"), []schemaRecord{quote("贵州茅台", 168800, 167000), quote("平安银行", 1120, 1110)}, []string{"preClose:out_of_range", "price:out_of_range"}, true},
+		{"行业涨跌幅整批为0", industryRankSchema, industry(0, 0, 0, 0), []string{"changePercent:all_zero"}, true},
+		{"行业个别平盘", industryRankSchema, industry(1.2, 0, -0.5), nil, false},
+		{"记录太少不检查整批为0", industryRankSchema, industry(0, 0), nil, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			violations := tc.schema.validate(tc.records)
+			var rules []string
+			for _, v := range violations {
+				rules = append(rules, v.Field+":"+v.Rule)
+			}
+			if strings.Join(rules, ",") != strings.Join(tc.rules, ",") {
+				t.Errorf("violations = %v, want %v", rules, tc.rules)
+			}
+			if got := isBadBatch(violations, len(tc.records)); got != tc.bad {
+				t.Errorf("bad = %v, want %v", got, tc.bad)
+			}
+		})
+	}
+}
+
+func TestSchemaMonitorBrokenAndRecover(t *testing.T) {
+	monitor := NewSchemaMonitor(t.TempDir())
+	var events []models.SchemaDriftEvent
+	monitor.SetEventHandler(func(e models.SchemaDriftEvent) { events = append(events, e) })
+
+	drifted := industryRankRecords([]models.IndustryRank{{Name: "半导体"}, {Name: "白酒"}, {Name: "银行"}})
+	payload := []byte(`{"data":{"list":[{"name":"半导体","zdfd":"3.25"}]}}`)
+
+	for i := 0; i < 3; i++ {
+		if err := monitor.Check(industryRankSchema, payload, drifted); !errors.Is(err, ErrSchemaBroken) {
+			t.Fatalf("第%d批 err = %v", i+1, err)
+		}
+		if broken := monitor.IsBroken("tencent", SchemaDatasetIndustry); broken != (i >= 1) {
+			t.Fatalf("第%d批 broken = %v", i+1, broken)
+		}
+	}
+
+	// 首个违规批次与标记异常时各上报一次，之后不重复上报
+	if len(events) != 2 || events[0].Broken || !events[1].Broken {
+		t.Fatalf("events = %+v", events)
+	}
+	sample, err := os.ReadFile(events[0].SamplePath)
+	if err != nil || string(sample) != string(payload) {
+		t.Fatalf("样本 = %q, %v", sample, err)
+	}
+
+	stats := monitor.Stats()
+	if len(stats.Sources) != 1 || stats.Sources[0].BadBatches != 3 || stats.Sources[0].Violations["changePercent:all_zero"] != 9 {
+		t.Fatalf("stats = %+v", stats)
+	}
+
+	ok := industryRankRecords([]models.IndustryRank{{Name: "半导体", ChangePercent: 3.25}, {Name: "白酒", ChangePercent: 1.45}, {Name: "银行"}})
+	if err := monitor.Check(industryRankSchema, payload, ok); err != nil {
+		t.Fatalf("正常批次 err = %v", err)
+	}
+	if monitor.IsBroken("tencent", SchemaDatasetIndustry) {
+		t.Fatal("正常批次后应恢复")
+	}
+}
+
+func TestIndustryRankSchemaDrift(t *testing.T) {
+	// 录制响应中 zdf 字段被改名为 zdfd，解析结果整批涨跌幅为0
+	api := NewStockAPIWithManager(newFixtureRequestManager(outageFixtureDir))
+	api.schema = NewSchemaMonitor(t.TempDir())
+
+	for i := 0; i < schemaBrokenAfter; i++ {
+		if _, err := api.fetchIndustryRank(context.Background()); !errors.Is(err, ErrSchemaBroken) {
+			t.Fatalf("err = %v", err)
+		}
+	}
+	if !api.schema.IsBroken("tencent", SchemaDatasetIndustry) {
+		t.Fatal("行业排行应标记为结构异常")
+	}
+	if result := api.GetIndustryRank(context.Background()); result.Meta.Source == "tencent" {
+		t.Fatalf("结构异常时不应使用实时数据: %+v", result.Meta)
+	}
+	health := api.schema.Stats()
+	if len(health.Recent) == 0 {
+		t.Fatal("缺少诊断事件")
+	}
+	if sample, err := os.ReadFile(health.Recent[0].SamplePath); err != nil || !strings.Contains(string(sample), "zdfd") {
+		t.Fatalf("样本 = %q, %v", sample, err)
+	}
+}
+
+func TestQuoteFallbackSkipsSchemaBroken(t *testing.T) {
+	msm := NewMultiSourceManager()
+	msm.schema = NewSchemaMonitor("")
+	msm.SetFirstLoadComplete()
+
+	var driftedCalls int32
+	provider := func(name, stockName string) *FuncQuoteProvider {
+		return &FuncQuoteProvider{
+			ProviderName:   name,
+			ProviderDomain: name + ".com",
+			Quotes: func(ctx context.Context, codes []string) (map[string]*models.StockPrice, error) {
+				if name == "drifted" {
+					atomic.AddInt32(&driftedCalls, 1)
+				}
+				result := map[string]*models.StockPrice{
+					"sh600519": {Code: "sh600519", Name: stockName, Price: 1688, PreClose: 1670},
+				}
+				if err := msm.schema.Check(stockQuoteSchema(name), []byte(name), stockPriceRecords(result)); err != nil {
+					return nil, err
+				}
+				return result, nil
+			},
+		}
+	}
+	msm.RegisterQuoteProvider(provider("drifted", ""), 1)
+	msm.RegisterQuoteProvider(provider("backup", "贵州茅台"), 2)
+
+	for i := 0; i < schemaBrokenAfter; i++ {
+		if _, err := msm.FetchQuotesFrom(context.Background(), "drifted", []string{"sh600519"}); !errors.Is(err, ErrSchemaBroken) {
+			t.Fatalf("第%d次 err = %v", i+1, err)
+		}
+	}
+	// 清空失败记录，排除健康分对调度顺序的影响
+	msm.mu.Lock()
+	*msm.providers["drifted"].info = DataSourceInfo{Name: "drifted", Priority: 1}
+	msm.mu.Unlock()
+
+	if names := msm.QuoteProviderNames(); len(names) != 2 {
+		t.Fatalf("注册的数据源应保留: %v", names)
+	}
+	got, name, err := msm.FetchQuotesWithTimeout(context.Background(), []string{"sh600519"}, 0)
+	if err != nil || name != "backup" || got["sh600519"].Name != "贵州茅台" {
+		t.Fatalf("got = %v %s %v", got, name, err)
+	}
+	if n := atomic.LoadInt32(&driftedCalls); n != schemaBrokenAfter {
+		t.Fatalf("结构异常的数据源仍被调度: %d", n)
+	}
+	for _, s := range msm.GetStatusList() {
+		if s.Key == "provider:drifted" && s.Status != "schema-broken" {
+			t.Fatalf("status = %s", s.Status)
+		}
+	}
+}
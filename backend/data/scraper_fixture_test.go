@@ -0,0 +1,353 @@
+package data
+
+import (
+	"context"
+	"testing"
+
+	"stock-ai/backend/models"
+)
+
+// 各数据源解析与降级测试，响应来自 testdata/fixtures（正常）与 testdata/fixtures_outage（上游故障），不访问真实网络
+
+type quoteWant struct {
+	name                             string
+	price, preClose, open, high, low float64
+}
+
+// 不同数据源对同一时刻的行情应解析出一致的结果
+var fixtureQuotes = map[string]quoteWant{
+	"sh600519": {name: "贵州茅台", price: 1512.3, preClose: 1498.5, open: 1500, high: 1520, low: 1495.1},
+	"sz000001": {name: "平安银行", price: 11.32, preClose: 11.15, open: 11.2, high: 11.4, low: 11.12},
+}
+
+func checkQuote(t *testing.T, source, code string, got *models.StockPrice, want quoteWant) {
+	t.Helper()
+	if got == nil {
+		t.Errorf("%s: 缺少 %s", source, code)
+		return
+	}
+	if got.Name != want.name || !almostEqual(got.Price, want.price) || !almostEqual(got.PreClose, want.preClose) ||
+		!almostEqual(got.Open, want.open) || !almostEqual(got.High, want.high) || !almostEqual(got.Low, want.low) {
+		t.Errorf("%s: %s = %+v, want %+v", source, code, *got, want)
+	}
+	if !almostEqual(got.Change, want.price-want.preClose) {
+		t.Errorf("%s: %s 涨跌额 = %v", source, code, got.Change)
+	}
+}
+
+func TestAStockQuoteSources(t *testing.T) {
+	msm := newFixtureMultiSource(fixtureDir)
+	api := NewStockAPIWithManager(msm.rm)
+
+	tests := []struct {
+		name  string
+		fetch func(ctx context.Context, codes []string) (map[string]*models.StockPrice, error)
+	}{
+		{"sina", msm.FetchAStockFromSina},
+		{"tencent", msm.FetchAStockFromTencent},
+		{"eastmoney", msm.FetchAStockFromEastmoney},
+		{"netease", api.GetStockPriceFromNetease},
+		{"sohu", api.GetStockPriceFromSohu},
+		{"hexun", api.GetStockPriceFromHexun},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.fetch(context.Background(), []string{"sh600519", "sz000001"})
+			if err != nil {
+				t.Fatalf("fetch: %v", err)
+			}
+			if len(got) != len(fixtureQuotes) {
+				t.Fatalf("got %d quotes: %+v", len(got), got)
+			}
+			for code, want := range fixtureQuotes {
+				checkQuote(t, tc.name, code, got[code], want)
+			}
+		})
+	}
+}
+
+func TestParseQuoteLinesMalformed(t *testing.T) {
+	msm := NewMultiSourceManager()
+	futures := &FuturesAPI{}
+	global := &GlobalMarketAPI{}
+
+	parsers := map[string]func(string) bool{
+		"sinaStock":    func(l string) bool { return msm.parseSinaStockLine(l) != nil },
+		"tencentStock": func(l string) bool { return msm.parseTencentStockLine(l) != nil },
+		"sinaIndex":    func(l string) bool { return msm.parseSinaIndexLine(l) != nil },
+		"tencentIndex": func(l string) bool { return msm.parseTencentIndexLine(l) != nil },
+		"sinaFutures":  func(l string) bool { return futures.parseSinaFuturesLine(l) != nil },
+		"sinaUSStock":  func(l string) bool { return global.parseSinaUSStockLine(l) != nil },
+		"sinaHKStock":  func(l string) bool { return global.parseSinaHKStockLine(l) != nil },
+	}
+	tests := []struct {
+		parser string
+		line   string
+	}{
+		{"sinaStock", `var hq_str_sz000002="";`},
+		{"sinaStock", `var hq_str_sh600000="浦发银行,10.50,10.48,10.52";`},
+		{"sinaStock", `<html>Forbidden</html>`},
+		{"tencentStock", `v_pv_none_match="1";`},
+		{"tencentStock", `v_sh600000="1~浦发银行~600000~10.52~10.48";`},
+		{"sinaIndex", `var hq_str_int_dji="";`},
+		{"sinaIndex", `var hq_str_int_dji="道琼斯,42863.86";`},
+		{"tencentIndex", `v_usDJI="200~道琼斯~.DJI~42863.86";`},
+		{"sinaFutures", `var hq_str_nf_AU2412="";`},
+		{"sinaFutures", `var hq_str_nf_AU2412="黄金2412,150000,612.50";`},
+		{"sinaUSStock", `var hq_str_gb_aapl="苹果,228.52,1.23";`},
+		{"sinaHKStock", `var hq_str_rt_hk00700="TENCENT,腾讯控股,420.000";`},
+	}
+	for _, tc := range tests {
+		if parsers[tc.parser](tc.line) {
+			t.Errorf("%s 应拒绝 %q", tc.parser, tc.line)
+		}
+	}
+}
+
+func TestParseStockResponseVariants(t *testing.T) {
+	api := &StockAPI{}
+	tests := []struct {
+		name    string
+		parse   func(string, map[string]string) (map[string]*models.StockPrice, error)
+		data    string
+		codeMap map[string]string
+		price   float64 // 0 表示不应解析出结果
+		wantErr bool
+	}{
+		{"netease空回调", api.parseNeteaseResponse, `_ntes_quote_callback({});`, map[string]string{"0600519": "sh600519"}, 0, false},
+		{"netease非JSON", api.parseNeteaseResponse, `<html>502 Bad Gateway</html>`, map[string]string{"0600519": "sh600519"}, 0, true},
+		{"sohu数组格式", api.parseSohuResponse, `[["cn_600519","贵州茅台","1512.30","1498.50","1500.00","1520.00","1495.10","3145678","4745612345","0.92"]]`, map[string]string{"cn_600519": "sh600519"}, 1512.3, false},
+		{"sohu对象格式", api.parseSohuResponse, `{"cn_600519":{"name":"贵州茅台","price":1512.3,"preclose":1498.5}}`, map[string]string{"cn_600519": "sh600519"}, 1512.3, false},
+		{"sohu字段不足", api.parseSohuResponse, `[["cn_600519","贵州茅台","1512.30"]]`, map[string]string{"cn_600519": "sh600519"}, 0, false},
+		{"hexun未知代码", api.parseHexunResponse, `({"Data":[[["600000sha","浦发银行",1052,4,38,1050,1055,1045,1048,1,1]]]});`, map[string]string{"600519sha": "sh600519"}, 0, false},
+		{"hexun价格单位为分", api.parseHexunResponse, `({"Data":[[["600519sha","贵州茅台",151230,1380,92,150000,152000,149510,149850,3145678,4745612345]]]});`, map[string]string{"600519sha": "sh600519"}, 1512.3, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.parse(tc.data, tc.codeMap)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("err = %v", err)
+			}
+			if tc.price == 0 {
+				if len(got) != 0 {
+					t.Fatalf("不应解析出结果: %+v", got)
+				}
+				return
+			}
+			if p := got["sh600519"]; p == nil || !almostEqual(p.Price, tc.price) || p.Name != "贵州茅台" {
+				t.Fatalf("got %+v", got)
+			}
+		})
+	}
+}
+
+func TestGlobalIndexSources(t *testing.T) {
+	msm := newFixtureMultiSource(fixtureDir)
+
+	tests := []struct {
+		name  string
+		fetch func(ctx context.Context) (map[string]*IndexData, error)
+		count int
+	}{
+		{"sina", msm.FetchGlobalIndicesFromSina, 5},
+		{"tencent", msm.FetchGlobalIndicesFromTencent, 5},
+		{"eastmoney", msm.FetchGlobalIndicesFromEastmoney, 5},
+	}
+	want := map[string]IndexData{
+		"DJI":  {Price: 42863.86, Change: -140.59},
+		"IXIC": {Price: 18342.94, Change: 115.94},
+		"HSI":  {Price: 20126.45, Change: 251.3},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.fetch(context.Background())
+			if err != nil {
+				t.Fatalf("fetch: %v", err)
+			}
+			if len(got) != tc.count {
+				t.Fatalf("got %d indices: %v", len(got), got)
+			}
+			for code, w := range want {
+				idx := got[code]
+				if idx == nil || !almostEqual(idx.Price, w.Price) || !almostEqual(idx.Change, w.Change) || idx.Name == "" {
+					t.Errorf("%s = %+v, want %+v", code, idx, w)
+				}
+			}
+		})
+	}
+}
+
+func TestQuoteFallbackChain(t *testing.T) {
+	tests := []struct {
+		name       string
+		codes      []string
+		wantSource DataSource
+		wantPrice  float64
+		wantErr    bool
+	}{
+		{"新浪被限流时切换腾讯", []string{"sh600000"}, SourceTencent, 10.52, false},
+		{"全部数据源无数据", []string{"sz300750"}, SourceEastmoney, 0, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			msm := newFixtureMultiSource(outageFixtureDir)
+			msm.RegisterQuoteProvider(&FuncQuoteProvider{ProviderName: "sina", ProviderDomain: "sina.com.cn", Quotes: msm.FetchAStockFromSina}, 2)
+			msm.RegisterQuoteProvider(&FuncQuoteProvider{ProviderName: "tencent", ProviderDomain: "qq.com", Quotes: msm.FetchAStockFromTencent}, 3)
+			msm.SetFirstLoadComplete() // 按优先级依次请求，结果可预期
+
+			got, source, err := msm.GetAStockWithFallback(context.Background(), tc.codes)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("应返回错误, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err = %v", err)
+			}
+			if source != tc.wantSource {
+				t.Fatalf("source = %v, want %v", source, tc.wantSource)
+			}
+			if p := got[tc.codes[0]]; p == nil || !almostEqual(p.Price, tc.wantPrice) || p.Source != "tencent" {
+				t.Fatalf("got %+v", got)
+			}
+			if info := msm.sources[SourceSina]; info.FailCount != 1 || info.LastError == "" {
+				t.Fatalf("新浪失败应计入健康信息: %+v", info)
+			}
+		})
+	}
+}
+
+func TestGlobalIndexFallbackChain(t *testing.T) {
+	msm := newFixtureMultiSource(outageFixtureDir)
+	msm.SetFirstLoadComplete()
+
+	// 东方财富返回空数据（字段改名/接口下线），轮询应落到下一个可用数据源
+	got, source, err := msm.GetGlobalIndicesWithFallback(context.Background())
+	if err != nil {
+		t.Fatalf("err = %v", err)
+	}
+	if source != SourceSina {
+		t.Fatalf("source = %v", source)
+	}
+	if dji := got["DJI"]; dji == nil || !almostEqual(dji.Price, 42863.86) {
+		t.Fatalf("DJI = %+v", dji)
+	}
+}
+
+func TestFundPriceSources(t *testing.T) {
+	api := NewFundAPI()
+	api.SetTransport(newFixtureTransport(fixtureDir))
+
+	tests := []struct {
+		name          string
+		fetch         func(context.Context, []string) (map[string]*models.FundPrice, error)
+		estimate      float64
+		changePercent float64
+	}{
+		{"eastmoney", api.fetchFundPriceFromEastmoney, 3.8431, 0.82},
+		{"tencent", api.fetchFundPriceFromTencent, 3.8431, 0.82},
+		{"sina", api.fetchFundPriceFromSina, 3.8431, 0.8158},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.fetch(context.Background(), []string{"110022", "161725"})
+			if err != nil {
+				t.Fatalf("fetch: %v", err)
+			}
+			if len(got) != 2 {
+				t.Fatalf("got %+v", got)
+			}
+			p := got["110022"]
+			if p == nil || p.Name != "易方达消费行业股票" || !almostEqual(p.Estimate, tc.estimate) || !almostEqual(round4(p.ChangePercent), tc.changePercent) {
+				t.Fatalf("110022 = %+v", p)
+			}
+			if got["161725"] == nil || got["161725"].Name != "招商中证白酒指数(LOF)A" {
+				t.Fatalf("161725 = %+v", got["161725"])
+			}
+		})
+	}
+}
+
+func round4(v float64) float64 {
+	return float64(int64(v*10000+0.5)) / 10000
+}
+
+func TestFundPriceFallbackChain(t *testing.T) {
+	api := NewFundAPI()
+	api.SetTransport(newFixtureTransport(outageFixtureDir))
+	api.priceFirstLoad = false // 轮询模式，从东方财富开始
+
+	// 东方财富对 161725 返回空估值，缺失的基金由其余数据源补齐
+	got, err := api.fetchFundPrice(context.Background(), []string{"110022", "161725"})
+	if err != nil {
+		t.Fatalf("err = %v", err)
+	}
+	if p := got["110022"]; p == nil || !almostEqual(p.Estimate, 3.8431) {
+		t.Fatalf("110022 = %+v", p)
+	}
+	if p := got["161725"]; p == nil || !almostEqual(p.Nav, 1.0523) || p.UpdateTime != "2026-10-16" {
+		t.Fatalf("161725 应由腾讯补齐, got %+v", p)
+	}
+}
+
+func TestFuturesAndOverseasQuotes(t *testing.T) {
+	rm := newFixtureRequestManager(fixtureDir)
+
+	futures, err := (&FuturesAPI{rm: rm}).fetchFuturesPrice(context.Background(), []string{"AU2412", "IF2412"})
+	if err != nil {
+		t.Fatalf("futures: %v", err)
+	}
+	au := futures["AU2412"]
+	if au == nil || !almostEqual(au.Price, 614.6) || !almostEqual(au.PreSettle, 611.4) || au.OpenInterest != 245678 || au.Volume != 123456 || au.Exchange != "SHFE" {
+		t.Fatalf("AU2412 = %+v", au)
+	}
+	if f := futures["IF2412"]; f == nil || f.Exchange != "CFFEX" || !almostEqual(f.Change, 38) {
+		t.Fatalf("IF2412 = %+v", f)
+	}
+
+	global := &GlobalMarketAPI{rm: rm}
+	us, err := global.GetUSStockPrice(context.Background(), []string{"AAPL"})
+	if err != nil {
+		t.Fatalf("us: %v", err)
+	}
+	if p := us["AAPL"]; p == nil || !almostEqual(p.Price, 228.52) || !almostEqual(p.PreClose, 225.74) || p.Exchange == "" {
+		t.Fatalf("AAPL = %+v", p)
+	}
+	hk, err := global.GetHKStockPrice(context.Background(), []string{"00700"})
+	if err != nil {
+		t.Fatalf("hk: %v", err)
+	}
+	if p := hk["00700"]; p == nil || !almostEqual(p.Price, 423.8) || p.Volume != 23456789 {
+		t.Fatalf("00700 = %+v", p)
+	}
+}
+
+func TestForexSources(t *testing.T) {
+	api := &CryptoForexAPI{rm: newFixtureRequestManager(fixtureDir)}
+	tests := []struct {
+		name  string
+		fetch func(context.Context) ([]models.ForexRate, error)
+		rate  float64
+	}{
+		{"sina", api.getForexRatesFromSina, 7.1235},
+		{"eastmoney", api.getForexRatesFromEastMoney, 7.1235},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rates, err := tc.fetch(context.Background())
+			if err != nil {
+				t.Fatalf("fetch: %v", err)
+			}
+			if len(rates) != len(mainForexPairs) {
+				t.Fatalf("got %d rates", len(rates))
+			}
+			if rates[0].Pair != "USDCNY" || !almostEqual(rates[0].Rate, tc.rate) {
+				t.Fatalf("USDCNY = %+v", rates[0])
+			}
+			if rates[1].Pair != "EURUSD" || rates[1].Rate <= 0 {
+				t.Fatalf("EURUSD = %+v", rates[1])
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package data
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -52,10 +53,10 @@ type SentimentComponent struct {
 // 4. 北向资金流向 (15%) - 当日净流入
 // 5. 主力资金流向 (10%) - 主力净流入
 // 6. 指数位置 (10%) - 相对于20日均线
-func (api *SentimentAPI) GetAShareSentiment() (*MarketSentiment, error) {
-	cacheKey := "sentiment_ashare"
-	if cached, ok := api.rm.GetCache(cacheKey); ok {
-		return cached.(*MarketSentiment), nil
+func (api *SentimentAPI) GetAShareSentiment(ctx context.Context) (*MarketSentiment, error) {
+	cacheKey := "ashare"
+	if cached, ok := sentimentCache.Get(cacheKey); ok {
+		return cached, nil
 	}
 
 	// 使用 sync.WaitGroup 并行获取所有数据
@@ -76,35 +77,35 @@ func (api *SentimentAPI) GetAShareSentiment() (*MarketSentiment, error) {
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		advDecline, advDeclineErr = api.getAShareAdvanceDecline()
+		advDecline, advDeclineErr = api.getAShareAdvanceDecline(ctx)
 	}()
 
 	// 2. 并行获取涨停跌停数
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		limitData, limitErr = api.getAShareLimitCount()
+		limitData, limitErr = api.getAShareLimitCount(ctx)
 	}()
 
 	// 3. 并行获取北向资金
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		northFlow, northErr = api.getNorthboundFlow()
+		northFlow, northErr = api.getNorthboundFlow(ctx)
 	}()
 
 	// 4. 并行获取主力资金
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		mainFlow, mainErr = api.getMainMoneyFlow()
+		mainFlow, mainErr = api.getMainMoneyFlow(ctx)
 	}()
 
 	// 5. 并行获取指数涨跌幅
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		indexChange, indexErr = api.getMainIndexChange()
+		indexChange, indexErr = api.getMainIndexChange(ctx)
 	}()
 
 	// 等待所有请求完成
@@ -211,7 +212,7 @@ func (api *SentimentAPI) GetAShareSentiment() (*MarketSentiment, error) {
 	}
 
 	// 缓存60秒
-	api.rm.SetCache(cacheKey, sentiment, 60*time.Second)
+	sentimentCache.Set(cacheKey, sentiment)
 
 	return sentiment, nil
 }
@@ -230,7 +231,7 @@ type LimitCountData struct {
 }
 
 // getAShareAdvanceDecline 获取A股涨跌家数（并行请求优化）
-func (api *SentimentAPI) getAShareAdvanceDecline() (*AdvanceDeclineData, error) {
+func (api *SentimentAPI) getAShareAdvanceDecline(ctx context.Context) (*AdvanceDeclineData, error) {
 	var wg sync.WaitGroup
 	var totalStocks, advanceCount, declineCount int
 	var totalErr, advanceErr, declineErr error
@@ -240,7 +241,7 @@ func (api *SentimentAPI) getAShareAdvanceDecline() (*AdvanceDeclineData, error)
 	go func() {
 		defer wg.Done()
 		url := "https://push2.eastmoney.com/api/qt/clist/get?pn=1&pz=1&po=1&np=1&fltt=2&invt=2&fid=f3&fs=m:0+t:6,m:0+t:80,m:1+t:2,m:1+t:23&fields=f3"
-		req, err := http.NewRequest("GET", url, nil)
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 		if err != nil {
 			totalErr = err
 			return
@@ -281,7 +282,7 @@ func (api *SentimentAPI) getAShareAdvanceDecline() (*AdvanceDeclineData, error)
 	go func() {
 		defer wg.Done()
 		advanceURL := "https://push2.eastmoney.com/api/qt/clist/get?pn=1&pz=1&po=1&np=1&fltt=2&invt=2&fid=f3&fs=m:0+t:6,m:0+t:80,m:1+t:2,m:1+t:23&fields=f3&fid0=f3&fv0=0"
-		req, err := http.NewRequest("GET", advanceURL, nil)
+		req, err := http.NewRequestWithContext(ctx, "GET", advanceURL, nil)
 		if err != nil {
 			advanceErr = err
 			return
@@ -311,7 +312,7 @@ func (api *SentimentAPI) getAShareAdvanceDecline() (*AdvanceDeclineData, error)
 	go func() {
 		defer wg.Done()
 		declineURL := "https://push2.eastmoney.com/api/qt/clist/get?pn=1&pz=1&po=1&np=1&fltt=2&invt=2&fid=f3&fs=m:0+t:6,m:0+t:80,m:1+t:2,m:1+t:23&fields=f3&fid0=f3&fv0=-0.01&fid1=f3&fv1=-100"
-		req, err := http.NewRequest("GET", declineURL, nil)
+		req, err := http.NewRequestWithContext(ctx, "GET", declineURL, nil)
 		if err != nil {
 			declineErr = err
 			return
@@ -351,7 +352,7 @@ func (api *SentimentAPI) getAShareAdvanceDecline() (*AdvanceDeclineData, error)
 }
 
 // getAShareLimitCount 获取涨跌停家数（并行请求优化）
-func (api *SentimentAPI) getAShareLimitCount() (*LimitCountData, error) {
+func (api *SentimentAPI) getAShareLimitCount(ctx context.Context) (*LimitCountData, error) {
 	var wg sync.WaitGroup
 	var limitUpCount, limitDownCount int
 	var limitUpErr, limitDownErr error
@@ -361,7 +362,7 @@ func (api *SentimentAPI) getAShareLimitCount() (*LimitCountData, error) {
 	go func() {
 		defer wg.Done()
 		limitUpURL := "https://push2.eastmoney.com/api/qt/clist/get?pn=1&pz=1&po=1&np=1&fltt=2&invt=2&fid=f3&fs=m:0+t:6,m:0+t:80,m:1+t:2,m:1+t:23&fields=f3&fid0=f3&fv0=9.9"
-		req, err := http.NewRequest("GET", limitUpURL, nil)
+		req, err := http.NewRequestWithContext(ctx, "GET", limitUpURL, nil)
 		if err != nil {
 			limitUpErr = err
 			return
@@ -391,7 +392,7 @@ func (api *SentimentAPI) getAShareLimitCount() (*LimitCountData, error) {
 	go func() {
 		defer wg.Done()
 		limitDownURL := "https://push2.eastmoney.com/api/qt/clist/get?pn=1&pz=1&po=1&np=1&fltt=2&invt=2&fid=f3&fs=m:0+t:6,m:0+t:80,m:1+t:2,m:1+t:23&fields=f3&fid0=f3&fv0=-100&fid1=f3&fv1=-9.9"
-		req, err := http.NewRequest("GET", limitDownURL, nil)
+		req, err := http.NewRequestWithContext(ctx, "GET", limitDownURL, nil)
 		if err != nil {
 			limitDownErr = err
 			return
@@ -430,10 +431,10 @@ func (api *SentimentAPI) getAShareLimitCount() (*LimitCountData, error) {
 }
 
 // getNorthboundFlow 获取北向资金净流入
-func (api *SentimentAPI) getNorthboundFlow() (float64, error) {
+func (api *SentimentAPI) getNorthboundFlow(ctx context.Context) (float64, error) {
 	url := "https://push2.eastmoney.com/api/qt/kamt.rtmin/get?fields1=f1,f2,f3&fields2=f51,f52,f53,f54,f55,f56"
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return 0, err
 	}
@@ -466,10 +467,10 @@ func (api *SentimentAPI) getNorthboundFlow() (float64, error) {
 }
 
 // getMainMoneyFlow 获取主力资金净流入
-func (api *SentimentAPI) getMainMoneyFlow() (float64, error) {
+func (api *SentimentAPI) getMainMoneyFlow(ctx context.Context) (float64, error) {
 	url := "https://push2.eastmoney.com/api/qt/clist/get?pn=1&pz=1&po=1&np=1&fltt=2&invt=2&fid=f62&fs=m:0+t:6,m:0+t:80,m:1+t:2,m:1+t:23&fields=f62"
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return 0, err
 	}
@@ -509,10 +510,10 @@ func (api *SentimentAPI) getMainMoneyFlow() (float64, error) {
 }
 
 // getMainIndexChange 获取主要指数涨跌幅
-func (api *SentimentAPI) getMainIndexChange() (float64, error) {
+func (api *SentimentAPI) getMainIndexChange(ctx context.Context) (float64, error) {
 	url := "https://push2.eastmoney.com/api/qt/stock/get?secid=1.000001&fields=f3"
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return 0, err
 	}
@@ -674,10 +675,10 @@ func (api *SentimentAPI) getSentimentDescription(value float64) string {
 }
 
 // GetGlobalMarketSentiment 获取全球市场情绪
-func (api *SentimentAPI) GetGlobalMarketSentiment(country string) (*MarketSentiment, error) {
-	cacheKey := "sentiment_" + country
-	if cached, ok := api.rm.GetCache(cacheKey); ok {
-		return cached.(*MarketSentiment), nil
+func (api *SentimentAPI) GetGlobalMarketSentiment(ctx context.Context, country string) (*MarketSentiment, error) {
+	cacheKey := country
+	if cached, ok := sentimentCache.Get(cacheKey); ok {
+		return cached, nil
 	}
 
 	// 对于国际市场，我们使用指数涨跌幅和VIX（如果可用）来计算
@@ -687,15 +688,13 @@ func (api *SentimentAPI) GetGlobalMarketSentiment(country string) (*MarketSentim
 
 	// 尝试从缓存获取指数数据，避免重复请求
 	var indices []models.GlobalIndex
-	if cachedIndices, ok := api.rm.GetCache("global_indices"); ok {
-		indices = cachedIndices.([]models.GlobalIndex)
+	if cachedIndices, ok := globalIndexCache.Get(globalIndexCacheKey); ok {
+		indices = cachedIndices.Items
 	} else {
-		// 缓存没有，快速获取（使用较短超时）
-		globalAPI := NewGlobalMarketAPI()
-		var err error
-		indices, err = globalAPI.GetGlobalIndices()
-		if err != nil {
-			indices = nil
+		// 缓存没有，快速获取（使用较短超时）；占位数据不参与情绪计算
+		result := NewGlobalMarketAPI().GetGlobalIndices(ctx)
+		if !IsPlaceholder(result.Meta) {
+			indices = result.Items
 		}
 	}
 
@@ -730,7 +729,7 @@ func (api *SentimentAPI) GetGlobalMarketSentiment(country string) (*MarketSentim
 
 	// 对于美国市场，尝试获取VIX
 	if country == "us" {
-		vix, err := api.getVIXIndex()
+		vix, err := api.getVIXIndex(ctx)
 		if err == nil && vix > 0 {
 			value := api.calculateVIXScore(vix)
 			weight := 0.50
@@ -764,17 +763,17 @@ func (api *SentimentAPI) GetGlobalMarketSentiment(country string) (*MarketSentim
 	}
 
 	// 缓存60秒
-	api.rm.SetCache(cacheKey, sentiment, 60*time.Second)
+	sentimentCache.Set(cacheKey, sentiment)
 
 	return sentiment, nil
 }
 
 // getVIXIndex 获取VIX恐慌指数
-func (api *SentimentAPI) getVIXIndex() (float64, error) {
+func (api *SentimentAPI) getVIXIndex(ctx context.Context) (float64, error) {
 	// 从新浪获取VIX
 	url := "https://hq.sinajs.cn/list=int_vix"
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return 0, err
 	}
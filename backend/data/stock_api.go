@@ -3,6 +3,7 @@ package data
 import (
 	"compress/gzip"
 	"compress/zlib"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -11,7 +12,6 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
-	"sync"
 	"time"
 
 	"stock-ai/backend/models"
@@ -22,7 +22,8 @@ import (
 
 // StockAPI 股票数据API
 type StockAPI struct {
-	rm *RequestManager
+	rm     *RequestManager
+	schema *SchemaMonitor
 }
 
 const (
@@ -34,10 +35,16 @@ const (
 // NewStockAPI 创建股票API实例
 func NewStockAPI() *StockAPI {
 	return &StockAPI{
-		rm: GetRequestManager(),
+		rm:     GetRequestManager(),
+		schema: GetSchemaMonitor(),
 	}
 }
 
+// NewStockAPIWithManager 使用指定的请求管理器创建股票API实例（用于注入传输层）
+func NewStockAPIWithManager(rm *RequestManager) *StockAPI {
+	return &StockAPI{rm: rm, schema: GetSchemaMonitor()}
+}
+
 // getClient 获取HTTP客户端
 func (api *StockAPI) getClient() *http.Client {
 	return api.rm.GetClient()
@@ -70,10 +77,10 @@ func readResponseBody(resp *http.Response) ([]byte, error) {
 	}
 }
 
-func (api *StockAPI) doGetWithRetry(rawURL string, referer string, extraHeaders map[string]string) ([]byte, error) {
+func (api *StockAPI) doGetWithRetry(ctx context.Context, rawURL string, referer string, extraHeaders map[string]string) ([]byte, error) {
 	var lastErr error
 	for attempt := 1; attempt <= 3; attempt++ {
-		req, err := http.NewRequest("GET", addTimestampParam(rawURL), nil)
+		req, err := http.NewRequestWithContext(ctx, "GET", addTimestampParam(rawURL), nil)
 		if err != nil {
 			return nil, err
 		}
@@ -100,115 +107,50 @@ func (api *StockAPI) doGetWithRetry(rawURL string, referer string, extraHeaders
 	return nil, fmt.Errorf("请求失败: %w", lastErr)
 }
 
-// GetStockPrice 获取股票实时价格（多数据源轮询）
-func (api *StockAPI) GetStockPrice(codes []string) (map[string]*models.StockPrice, error) {
+// GetStockPrice 获取股票实时价格（多数据源对冲请求）
+func (api *StockAPI) GetStockPrice(ctx context.Context, codes []string) (map[string]*models.StockPrice, error) {
+	return coalesce(ctx, "stock.price", []interface{}{codesParam(codes)}, func(ctx context.Context) (map[string]*models.StockPrice, error) {
+		return api.fetchStockPrice(ctx, codes)
+	})
+}
+
+// fetchStockPrice 实际请求，并发的相同调用由 GetStockPrice 合并
+func (api *StockAPI) fetchStockPrice(ctx context.Context, codes []string) (map[string]*models.StockPrice, error) {
 	if len(codes) == 0 {
 		return nil, nil
 	}
 
 	// 直接传递带前缀的代码给多数据源管理器
 	// 这样可以正确区分上证指数(sh000001)和深证股票(sz000001)
-	msm := GetMultiSourceManager()
-	data, _, err := msm.GetAStockWithFallback(codes)
-
-	if err != nil || len(data) == 0 {
-		// 回退到原始新浪接口
-		return api.getStockPriceFromSina(codes)
-	}
-
-	return data, nil
+	data, _, err := GetMultiSourceManager().FetchQuotes(ctx, codes)
+	return data, err
 }
 
-// getStockPriceFromSina 从新浪获取股票价格（备用）
-func (api *StockAPI) getStockPriceFromSina(codes []string) (map[string]*models.StockPrice, error) {
-	codeList := strings.Join(codes, ",")
-	url := fmt.Sprintf("http://hq.sinajs.cn/list=%s", codeList)
-
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("Referer", "http://finance.sina.com.cn")
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36")
-
-	resp, err := api.getClient().Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	reader := transform.NewReader(resp.Body, simplifiedchinese.GBK.NewDecoder())
-	body, err := io.ReadAll(reader)
-	if err != nil {
-		return nil, err
-	}
-
-	return api.parseSinaResponse(string(body))
+// GetStockPriceWithTimeout 获取股票实时价格，超过timeout仍无数据源返回时报错
+func (api *StockAPI) GetStockPriceWithTimeout(ctx context.Context, codes []string, timeout time.Duration) (map[string]*models.StockPrice, string, error) {
+	return GetMultiSourceManager().FetchQuotesWithTimeout(ctx, codes, timeout)
 }
 
-// parseSinaResponse 解析新浪接口返回数据
-func (api *StockAPI) parseSinaResponse(data string) (map[string]*models.StockPrice, error) {
-	result := make(map[string]*models.StockPrice)
-
-	re := regexp.MustCompile(`var hq_str_(\w+)="([^"]*)"`)
-	matches := re.FindAllStringSubmatch(data, -1)
-
-	for _, match := range matches {
-		if len(match) < 3 {
-			continue
-		}
-		code := match[1]
-		values := match[2]
-
-		if values == "" {
-			continue
-		}
-
-		price := api.parseSinaStockData(code, values)
-		if price != nil {
-			result[code] = price
-		}
+// GetMarketIndex 获取市场指数；实时获取失败时依次回退到上次成功获取的数据、占位数据
+func (api *StockAPI) GetMarketIndex(ctx context.Context) *models.MarketIndexResult {
+	items, source, err := api.fetchMarketIndex(ctx)
+	if err == nil {
+		return &models.MarketIndexResult{Items: items, Meta: newDataMeta(source, models.FreshnessLive, time.Now(), nil)}
 	}
 
-	return result, nil
-}
-
-// parseSinaStockData 解析单只股票数据
-func (api *StockAPI) parseSinaStockData(code, data string) *models.StockPrice {
-	parts := strings.Split(data, ",")
-
-	if strings.HasPrefix(code, "sh") || strings.HasPrefix(code, "sz") {
-		if len(parts) < 32 {
-			return nil
-		}
-		price := &models.StockPrice{
-			Code:       code,
-			Name:       parts[0],
-			Open:       parseFloat(parts[1]),
-			PreClose:   parseFloat(parts[2]),
-			Price:      parseFloat(parts[3]),
-			High:       parseFloat(parts[4]),
-			Low:        parseFloat(parts[5]),
-			Volume:     parseInt(parts[8]),
-			Amount:     parseFloat(parts[9]),
-			UpdateTime: parts[30] + " " + parts[31],
-		}
-		price.Change = price.Price - price.PreClose
-		if price.PreClose > 0 {
-			price.ChangePercent = (price.Change / price.PreClose) * 100
-		}
-		return price
+	log.Printf("[市场指数] 实时获取失败: %v", err)
+	if cached, ok := loadLastGoodData(); ok && len(cached.MarketIndex) > 0 {
+		return &models.MarketIndexResult{Items: cached.MarketIndex, Meta: newDataMeta(sourceLocalCache, models.FreshnessStale, cached.CacheTime, err)}
 	}
-
-	return nil
+	return &models.MarketIndexResult{Items: api.getDefaultMarketIndex(), Meta: newDataMeta(sourcePlaceholder, models.FreshnessPlaceholder, time.Time{}, err)}
 }
 
-// GetMarketIndex 获取市场指数
-func (api *StockAPI) GetMarketIndex() ([]models.MarketIndex, error) {
+// fetchMarketIndex 实时获取市场指数，返回数据与数据源名称
+func (api *StockAPI) fetchMarketIndex(ctx context.Context) ([]models.MarketIndex, string, error) {
 	codes := []string{"sh000001", "sz399001", "sz399006", "sh000300", "sh000016", "sh000688"}
-	prices, err := api.GetStockPrice(codes)
-	if err != nil || len(prices) == 0 {
-		return api.getDefaultMarketIndex(), nil
+	prices, source, err := GetMultiSourceManager().FetchQuotes(ctx, codes)
+	if err != nil {
+		return nil, "", err
 	}
 
 	var indexes []models.MarketIndex
@@ -234,10 +176,10 @@ func (api *StockAPI) GetMarketIndex() ([]models.MarketIndex, error) {
 	}
 
 	if len(indexes) == 0 {
-		return api.getDefaultMarketIndex(), nil
+		return nil, "", fmt.Errorf("市场指数数据为空")
 	}
 
-	return indexes, nil
+	return indexes, source, nil
 }
 
 // getDefaultMarketIndex 返回默认市场指数数据
@@ -252,19 +194,40 @@ func (api *StockAPI) getDefaultMarketIndex() []models.MarketIndex {
 	}
 }
 
-// GetIndustryRank 获取行业排行（腾讯接口）
-func (api *StockAPI) GetIndustryRank() ([]models.IndustryRank, error) {
+// GetIndustryRank 获取行业排行（腾讯接口），回退规则同 GetMarketIndex
+func (api *StockAPI) GetIndustryRank(ctx context.Context) *models.IndustryRankResult {
+	var items []models.IndustryRank
+	var err error
+	if api.schema.IsBroken(industryRankSchema.Source, industryRankSchema.Dataset) {
+		// 接口字段已变化时直接使用缓存，不再展示整批为0的排行
+		err = fmt.Errorf("%w: 行业排行", ErrSchemaBroken)
+	} else {
+		items, err = api.fetchIndustryRank(ctx)
+	}
+	if err == nil {
+		return &models.IndustryRankResult{Items: items, Meta: newDataMeta("tencent", models.FreshnessLive, time.Now(), nil)}
+	}
+
+	log.Printf("[行业排行] 实时获取失败: %v", err)
+	if cached, ok := loadLastGoodData(); ok && len(cached.IndustryRank) > 0 {
+		return &models.IndustryRankResult{Items: cached.IndustryRank, Meta: newDataMeta(sourceLocalCache, models.FreshnessStale, cached.CacheTime, err)}
+	}
+	return &models.IndustryRankResult{Items: api.getDefaultIndustryRank(), Meta: newDataMeta(sourcePlaceholder, models.FreshnessPlaceholder, time.Time{}, err)}
+}
+
+// fetchIndustryRank 实时获取行业排行（腾讯接口）
+func (api *StockAPI) fetchIndustryRank(ctx context.Context) ([]models.IndustryRank, error) {
 	url := "https://proxy.finance.qq.com/ifzqgtimg/appstock/app/mktHs/rank?t=industry&p=1&num=20"
 
-	resp, err := api.getClient().Get(url)
+	resp, err := getWithContext(ctx, api.getClient(), url)
 	if err != nil {
-		return api.getDefaultIndustryRank(), nil
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return api.getDefaultIndustryRank(), nil
+		return nil, err
 	}
 
 	var result struct {
@@ -278,7 +241,7 @@ func (api *StockAPI) GetIndustryRank() ([]models.IndustryRank, error) {
 	}
 
 	if err := json.Unmarshal(body, &result); err != nil {
-		return api.getDefaultIndustryRank(), nil
+		return nil, err
 	}
 
 	var ranks []models.IndustryRank
@@ -291,7 +254,10 @@ func (api *StockAPI) GetIndustryRank() ([]models.IndustryRank, error) {
 	}
 
 	if len(ranks) == 0 {
-		return api.getDefaultIndustryRank(), nil
+		return nil, fmt.Errorf("行业排行数据为空")
+	}
+	if err := api.schema.Check(industryRankSchema, body, industryRankRecords(ranks)); err != nil {
+		return nil, err
 	}
 
 	return ranks, nil
@@ -313,27 +279,41 @@ func (api *StockAPI) getDefaultIndustryRank() []models.IndustryRank {
 	}
 }
 
-// GetMoneyFlow 获取资金流向（新浪接口）
-func (api *StockAPI) GetMoneyFlow() ([]models.MoneyFlow, error) {
+// GetMoneyFlow 获取资金流向（新浪接口），回退规则同 GetMarketIndex
+func (api *StockAPI) GetMoneyFlow(ctx context.Context) *models.MoneyFlowResult {
+	items, err := api.fetchMoneyFlow(ctx)
+	if err == nil {
+		return &models.MoneyFlowResult{Items: items, Meta: newDataMeta("sina", models.FreshnessLive, time.Now(), nil)}
+	}
+
+	log.Printf("[资金流向] 实时获取失败: %v", err)
+	if cached, ok := loadLastGoodData(); ok && len(cached.MoneyFlow) > 0 {
+		return &models.MoneyFlowResult{Items: cached.MoneyFlow, Meta: newDataMeta(sourceLocalCache, models.FreshnessStale, cached.CacheTime, err)}
+	}
+	return &models.MoneyFlowResult{Items: api.getDefaultMoneyFlow(), Meta: newDataMeta(sourcePlaceholder, models.FreshnessPlaceholder, time.Time{}, err)}
+}
+
+// fetchMoneyFlow 实时获取资金流向（新浪接口）
+func (api *StockAPI) fetchMoneyFlow(ctx context.Context) ([]models.MoneyFlow, error) {
 	url := "http://vip.stock.finance.sina.com.cn/quotes_service/api/json_v2.php/MoneyFlow.ssl_bkzj_ssggzj?page=1&num=20&sort=netamount&asc=0"
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		return api.getDefaultMoneyFlow(), nil
+		return nil, err
 	}
 	req.Header.Set("Referer", "http://vip.stock.finance.sina.com.cn")
 	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36")
 
 	resp, err := api.getClient().Do(req)
 	if err != nil {
-		return api.getDefaultMoneyFlow(), nil
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	reader := transform.NewReader(resp.Body, simplifiedchinese.GBK.NewDecoder())
 	body, err := io.ReadAll(reader)
 	if err != nil {
-		return api.getDefaultMoneyFlow(), nil
+		return nil, err
 	}
 
 	var data []struct {
@@ -344,7 +324,7 @@ func (api *StockAPI) GetMoneyFlow() ([]models.MoneyFlow, error) {
 	}
 
 	if err := json.Unmarshal(body, &data); err != nil {
-		return api.getDefaultMoneyFlow(), nil
+		return nil, err
 	}
 
 	var flows []models.MoneyFlow
@@ -358,7 +338,7 @@ func (api *StockAPI) GetMoneyFlow() ([]models.MoneyFlow, error) {
 	}
 
 	if len(flows) == 0 {
-		return api.getDefaultMoneyFlow(), nil
+		return nil, fmt.Errorf("资金流向数据为空")
 	}
 
 	return flows, nil
@@ -378,8 +358,15 @@ func (api *StockAPI) getDefaultMoneyFlow() []models.MoneyFlow {
 	}
 }
 
-// GetKLineData 获取K线数据（新浪接口）
-func (api *StockAPI) GetKLineData(code string, period string, count int) ([]models.KLineData, error) {
+// GetKLineData 获取K线数据（新浪/东方财富/腾讯，按数据源健康度回退）
+func (api *StockAPI) GetKLineData(ctx context.Context, code string, period string, count int) ([]models.KLineData, error) {
+	return coalesce(ctx, "stock.kline", []interface{}{code, period, count}, func(ctx context.Context) ([]models.KLineData, error) {
+		return api.fetchKLineData(ctx, code, period, count)
+	})
+}
+
+// fetchKLineData 实际请求，并发的相同调用由 GetKLineData 合并
+func (api *StockAPI) fetchKLineData(ctx context.Context, code string, period string, count int) ([]models.KLineData, error) {
 	normCode := normalizeStockCodeForAPI(code)
 	if normCode == "" {
 		return nil, fmt.Errorf("无效的股票代码: %s", code)
@@ -391,33 +378,14 @@ func (api *StockAPI) GetKLineData(code string, period string, count int) ([]mode
 		count = 240
 	}
 
-	var lastErr error
-	sources := []struct {
-		name  string
-		fetch func(string, string, int) ([]models.KLineData, error)
-	}{
-		{"新浪", api.getKLineFromSina},
-		{"东方财富", api.getKLineFromEastMoney},
-		{"腾讯", api.getKLineFromTencent},
-	}
-
-	for _, src := range sources {
-		if klines, err := src.fetch(normCode, period, count); err == nil && len(klines) > 0 {
-			return klines, nil
-		} else if err != nil {
-			lastErr = err
-			log.Printf("[KLine] %s数据源失败(%s %s): %v", src.name, normCode, period, err)
-		}
-	}
-
-	if lastErr != nil {
-		return nil, lastErr
+	klines, _, err := GetMultiSourceManager().FetchKLine(ctx, normCode, period, count)
+	if err != nil {
+		return nil, fmt.Errorf("暂无可用的K线数据(%s): %v", normCode, err)
 	}
-
-	return nil, fmt.Errorf("暂无可用的K线数据: %s", normCode)
+	return klines, nil
 }
 
-func (api *StockAPI) getKLineFromSina(code string, period string, count int) ([]models.KLineData, error) {
+func (api *StockAPI) getKLineFromSina(ctx context.Context, code string, period string, count int) ([]models.KLineData, error) {
 	scale := "240"
 	switch period {
 	case "week":
@@ -431,7 +399,7 @@ func (api *StockAPI) getKLineFromSina(code string, period string, count int) ([]
 		code, scale, count,
 	)
 
-	body, err := api.doGetWithRetry(url, "http://finance.sina.com.cn", nil)
+	body, err := api.doGetWithRetry(ctx, url, "http://finance.sina.com.cn", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -466,7 +434,7 @@ func (api *StockAPI) getKLineFromSina(code string, period string, count int) ([]
 	return klines, nil
 }
 
-func (api *StockAPI) getKLineFromEastMoney(code string, period string, count int) ([]models.KLineData, error) {
+func (api *StockAPI) getKLineFromEastMoney(ctx context.Context, code string, period string, count int) ([]models.KLineData, error) {
 	secid, err := toEastMoneySecID(code)
 	if err != nil {
 		return nil, err
@@ -477,7 +445,7 @@ func (api *StockAPI) getKLineFromEastMoney(code string, period string, count int
 		secid, eastMoneyUT, mapKlinePeriod(period), eastMoneyFields1, eastMoneyFields2, count,
 	)
 
-	body, err := api.doGetWithRetry(url, "https://quote.eastmoney.com", nil)
+	body, err := api.doGetWithRetry(ctx, url, "https://quote.eastmoney.com", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -518,7 +486,7 @@ func (api *StockAPI) getKLineFromEastMoney(code string, period string, count int
 	return klines, nil
 }
 
-func (api *StockAPI) getKLineFromTencent(code string, period string, count int) ([]models.KLineData, error) {
+func (api *StockAPI) getKLineFromTencent(ctx context.Context, code string, period string, count int) ([]models.KLineData, error) {
 	periodKey := map[string]string{
 		"weekly": "week",
 		"week":   "week",
@@ -533,7 +501,7 @@ func (api *StockAPI) getKLineFromTencent(code string, period string, count int)
 	param := fmt.Sprintf("%s,%s,,0,%d", code, klinePeriod, count)
 	url := fmt.Sprintf("https://web.ifzq.gtimg.cn/appstock/app/kline/kline?_var=%s&param=%s", varName, param)
 
-	body, err := api.doGetWithRetry(url, "https://gu.qq.com/", map[string]string{
+	body, err := api.doGetWithRetry(ctx, url, "https://gu.qq.com/", map[string]string{
 		"Referer": "https://gu.qq.com/",
 	})
 	if err != nil {
@@ -657,11 +625,24 @@ func addTimestampParam(rawURL string) string {
 	return fmt.Sprintf("%s?_=%d", rawURL, time.Now().UnixNano())
 }
 
-// GetMinuteData 获取分时数据（腾讯接口）
-func (api *StockAPI) GetMinuteData(code string) ([]models.MinuteData, error) {
+// GetMinuteData 获取分时数据
+func (api *StockAPI) GetMinuteData(ctx context.Context, code string) ([]models.MinuteData, error) {
+	return coalesce(ctx, "stock.minute", []interface{}{code}, func(ctx context.Context) ([]models.MinuteData, error) {
+		return api.fetchMinuteData(ctx, code)
+	})
+}
+
+// fetchMinuteData 实际请求，并发的相同调用由 GetMinuteData 合并
+func (api *StockAPI) fetchMinuteData(ctx context.Context, code string) ([]models.MinuteData, error) {
+	minutes, _, err := GetMultiSourceManager().FetchMinute(ctx, code)
+	return minutes, err
+}
+
+// getMinuteFromTencent 从腾讯获取分时数据
+func (api *StockAPI) getMinuteFromTencent(ctx context.Context, code string) ([]models.MinuteData, error) {
 	url := fmt.Sprintf("https://web.ifzq.gtimg.cn/appstock/app/minute/query?code=%s", code)
 
-	resp, err := api.getClient().Get(url)
+	resp, err := getWithContext(ctx, api.getClient(), url)
 	if err != nil {
 		return nil, err
 	}
@@ -717,26 +698,40 @@ func (api *StockAPI) GetMinuteData(code string) ([]models.MinuteData, error) {
 	return minutes, nil
 }
 
-// GetNewsList 获取财经快讯（东方财富）
-func (api *StockAPI) GetNewsList() ([]models.NewsItem, error) {
+// GetNewsList 获取财经快讯（东方财富），回退规则同 GetMarketIndex
+func (api *StockAPI) GetNewsList(ctx context.Context) *models.NewsListResult {
+	items, err := api.fetchNewsList(ctx)
+	if err == nil {
+		return &models.NewsListResult{Items: items, Meta: newDataMeta("eastmoney", models.FreshnessLive, time.Now(), nil)}
+	}
+
+	log.Printf("[财经快讯] 实时获取失败: %v", err)
+	if cached, ok := loadLastGoodData(); ok && len(cached.NewsList) > 0 {
+		return &models.NewsListResult{Items: cached.NewsList, Meta: newDataMeta(sourceLocalCache, models.FreshnessStale, cached.CacheTime, err)}
+	}
+	return &models.NewsListResult{Items: api.getDefaultNews(), Meta: newDataMeta(sourcePlaceholder, models.FreshnessPlaceholder, time.Time{}, err)}
+}
+
+// fetchNewsList 实时获取财经快讯（东方财富）
+func (api *StockAPI) fetchNewsList(ctx context.Context) ([]models.NewsItem, error) {
 	url := "https://np-listapi.eastmoney.com/comm/web/getNewsByColumns?client=web&biz=web_news_col&column=102&order=1&needInteractData=0&page_index=1&page_size=20"
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		return api.getDefaultNews(), nil
+		return nil, err
 	}
 	req.Header.Set("Referer", "https://www.eastmoney.com/")
 	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
 
 	resp, err := api.getClient().Do(req)
 	if err != nil {
-		return api.getDefaultNews(), nil
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return api.getDefaultNews(), nil
+		return nil, err
 	}
 
 	var result struct {
@@ -752,7 +747,7 @@ func (api *StockAPI) GetNewsList() ([]models.NewsItem, error) {
 	}
 
 	if err := json.Unmarshal(body, &result); err != nil {
-		return api.getDefaultNews(), nil
+		return nil, err
 	}
 
 	var news []models.NewsItem
@@ -776,11 +771,12 @@ func (api *StockAPI) GetNewsList() ([]models.NewsItem, error) {
 			Time:       item.ShowTime,
 			Source:     item.MediaName,
 			Importance: importance,
+			Url:        eastmoneyArticleURL(item.Code),
 		})
 	}
 
 	if len(news) == 0 {
-		return api.getDefaultNews(), nil
+		return nil, fmt.Errorf("财经快讯数据为空")
 	}
 
 	return news, nil
@@ -799,7 +795,19 @@ func (api *StockAPI) getDefaultNews() []models.NewsItem {
 }
 
 // GetResearchReports 获取研报列表（东方财富）
-func (api *StockAPI) GetResearchReports(stockCode string) ([]models.ResearchReport, error) {
+func (api *StockAPI) GetResearchReports(ctx context.Context, stockCode string) ([]models.ResearchReport, error) {
+	return api.GetResearchReportsPage(ctx, stockCode, 1, 20, "")
+}
+
+// GetResearchReportsPage 分页获取研报列表，beginTime 格式为 2006-01-02，为空表示不限制
+func (api *StockAPI) GetResearchReportsPage(ctx context.Context, stockCode string, pageNo int, pageSize int, beginTime string) ([]models.ResearchReport, error) {
+	return coalesce(ctx, "stock.reports", []interface{}{stockCode, pageNo, pageSize, beginTime}, func(ctx context.Context) ([]models.ResearchReport, error) {
+		return api.fetchResearchReportsPage(ctx, stockCode, pageNo, pageSize, beginTime)
+	})
+}
+
+// fetchResearchReportsPage 实际请求，并发的相同调用由 GetResearchReportsPage 合并
+func (api *StockAPI) fetchResearchReportsPage(ctx context.Context, stockCode string, pageNo int, pageSize int, beginTime string) ([]models.ResearchReport, error) {
 	// 去除前缀
 	code := stockCode
 	if strings.HasPrefix(stockCode, "sh") || strings.HasPrefix(stockCode, "sz") {
@@ -807,11 +815,11 @@ func (api *StockAPI) GetResearchReports(stockCode string) ([]models.ResearchRepo
 	}
 
 	// 使用code参数而不是stockCode参数来按股票代码过滤
-	url := fmt.Sprintf("https://reportapi.eastmoney.com/report/list?industryCode=*&pageSize=20&industry=*&rating=*&ratingChange=*&beginTime=&endTime=&pageNo=1&fields=&qType=0&orgCode=&rcode=&code=%s", code)
+	url := fmt.Sprintf("https://reportapi.eastmoney.com/report/list?industryCode=*&pageSize=%d&industry=*&rating=*&ratingChange=*&beginTime=%s&endTime=&pageNo=%d&fields=&qType=0&orgCode=&rcode=&code=%s", pageSize, beginTime, pageNo, code)
 
 	log.Printf("[研报] 请求URL: %s", url)
 
-	body, err := api.doGetWithRetry(url, "https://data.eastmoney.com/report/", nil)
+	body, err := api.doGetWithRetry(ctx, url, "https://data.eastmoney.com/report/", nil)
 	if err != nil {
 		log.Printf("[研报] 请求失败: %v", err)
 		return nil, err
@@ -831,6 +839,9 @@ func (api *StockAPI) GetResearchReports(stockCode string) ([]models.ResearchRepo
 			EncodeUrl       string `json:"encodeUrl"`
 			PredictThisYear string `json:"predictThisYearEps"`
 			PredictNextYear string `json:"predictNextYearEps"`
+			AimPriceHigh    string `json:"indvAimPriceT"`
+			AimPriceLow     string `json:"indvAimPriceL"`
+			LastRatingName  string `json:"lastEmRatingName"`
 		} `json:"data"`
 	}
 
@@ -860,6 +871,10 @@ func (api *StockAPI) GetResearchReports(stockCode string) ([]models.ResearchRepo
 			PublishDate: publishDate,
 			Researcher:  item.Researcher,
 			Rating:      item.EmRatingName,
+			LastRating:  item.LastRatingName,
+			TargetPrice: reportTargetPrice(parseFloat(item.AimPriceLow), parseFloat(item.AimPriceHigh)),
+			EpsThisYear: parseFloat(item.PredictThisYear),
+			EpsNextYear: parseFloat(item.PredictNextYear),
 			InfoCode:    item.InfoCode,
 			Url:         reportUrl,
 		})
@@ -869,7 +884,14 @@ func (api *StockAPI) GetResearchReports(stockCode string) ([]models.ResearchRepo
 }
 
 // GetStockNotices 获取公告列表（东方财富）
-func (api *StockAPI) GetStockNotices(stockCode string) ([]models.StockNotice, error) {
+func (api *StockAPI) GetStockNotices(ctx context.Context, stockCode string) ([]models.StockNotice, error) {
+	return coalesce(ctx, "stock.notices", []interface{}{stockCode}, func(ctx context.Context) ([]models.StockNotice, error) {
+		return api.fetchStockNotices(ctx, stockCode)
+	})
+}
+
+// fetchStockNotices 实际请求，并发的相同调用由 GetStockNotices 合并
+func (api *StockAPI) fetchStockNotices(ctx context.Context, stockCode string) ([]models.StockNotice, error) {
 	code := stockCode
 	if strings.HasPrefix(stockCode, "sh") || strings.HasPrefix(stockCode, "sz") {
 		code = stockCode[2:]
@@ -879,7 +901,7 @@ func (api *StockAPI) GetStockNotices(stockCode string) ([]models.StockNotice, er
 
 	log.Printf("[公告] 请求URL: %s", url)
 
-	body, err := api.doGetWithRetry(url, "https://data.eastmoney.com/notices/", nil)
+	body, err := api.doGetWithRetry(ctx, url, "https://data.eastmoney.com/notices/", nil)
 	if err != nil {
 		log.Printf("[公告] 请求失败: %v", err)
 		return nil, err
@@ -947,11 +969,11 @@ func (api *StockAPI) GetStockNotices(stockCode string) ([]models.StockNotice, er
 }
 
 // GetLongTigerRank 获取龙虎榜数据（新浪）
-func (api *StockAPI) GetLongTigerRank() ([]models.LongTigerItem, error) {
+func (api *StockAPI) GetLongTigerRank(ctx context.Context) ([]models.LongTigerItem, error) {
 	today := time.Now().Format("2006-01-02")
 	url := fmt.Sprintf("http://vip.stock.finance.sina.com.cn/q/go.php/vLHBData/kind/ggtj/index.phtml?last=%s&p=1", today)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -1002,19 +1024,33 @@ func (api *StockAPI) GetLongTigerRank() ([]models.LongTigerItem, error) {
 	return items, nil
 }
 
-// GetHotTopics 获取热门话题（东方财富股吧）
-func (api *StockAPI) GetHotTopics() ([]models.HotTopic, error) {
+// GetHotTopics 获取热门话题（东方财富股吧），回退规则同 GetMarketIndex
+func (api *StockAPI) GetHotTopics(ctx context.Context) *models.HotTopicResult {
+	items, err := api.fetchHotTopics(ctx)
+	if err == nil {
+		return &models.HotTopicResult{Items: items, Meta: newDataMeta("eastmoney", models.FreshnessLive, time.Now(), nil)}
+	}
+
+	log.Printf("[热门话题] 实时获取失败: %v", err)
+	if cached, ok := loadLastGoodData(); ok && len(cached.HotTopics) > 0 {
+		return &models.HotTopicResult{Items: cached.HotTopics, Meta: newDataMeta(sourceLocalCache, models.FreshnessStale, cached.CacheTime, err)}
+	}
+	return &models.HotTopicResult{Items: api.getDefaultHotTopics(), Meta: newDataMeta(sourcePlaceholder, models.FreshnessPlaceholder, time.Time{}, err)}
+}
+
+// fetchHotTopics 实时获取热门话题（东方财富股吧）
+func (api *StockAPI) fetchHotTopics(ctx context.Context) ([]models.HotTopic, error) {
 	url := "https://gubatopic.eastmoney.com/interface/GetData.aspx?path=newtopic/api/Topic/HomePageListRead&ps=20&p=1"
 
-	resp, err := api.getClient().Get(url)
+	resp, err := getWithContext(ctx, api.getClient(), url)
 	if err != nil {
-		return api.getDefaultHotTopics(), nil
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return api.getDefaultHotTopics(), nil
+		return nil, err
 	}
 
 	var result struct {
@@ -1027,7 +1063,7 @@ func (api *StockAPI) GetHotTopics() ([]models.HotTopic, error) {
 	}
 
 	if err := json.Unmarshal(body, &result); err != nil {
-		return api.getDefaultHotTopics(), nil
+		return nil, err
 	}
 
 	var topics []models.HotTopic
@@ -1042,7 +1078,7 @@ func (api *StockAPI) GetHotTopics() ([]models.HotTopic, error) {
 	}
 
 	if len(topics) == 0 {
-		return api.getDefaultHotTopics(), nil
+		return nil, fmt.Errorf("热门话题数据为空")
 	}
 
 	return topics, nil
@@ -1075,113 +1111,12 @@ func parseInt(s string) int64 {
 // ==================== 多数据源实时行情 ====================
 
 // GetStockPriceFromTencent 从腾讯获取股票实时价格
-func (api *StockAPI) GetStockPriceFromTencent(codes []string) (map[string]*models.StockPrice, error) {
-	if len(codes) == 0 {
-		return nil, nil
-	}
-
-	// 转换代码格式：sh000001 -> sh000001, sz000001 -> sz000001（腾讯格式相同）
-	codeList := strings.Join(codes, ",")
-	url := fmt.Sprintf("https://qt.gtimg.cn/q=%s", codeList)
-
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("Referer", "https://finance.qq.com")
-	req.Header.Set("User-Agent", api.rm.GetRandomUA())
-
-	// 使用限流器
-	resp, err := api.rm.DoRequestWithRateLimit("qt.gtimg.cn", req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	// 腾讯接口返回GBK编码
-	reader := transform.NewReader(resp.Body, simplifiedchinese.GBK.NewDecoder())
-	body, err := io.ReadAll(reader)
-	if err != nil {
-		return nil, err
-	}
-
-	return api.parseTencentResponse(string(body))
-}
-
-// parseTencentResponse 解析腾讯接口返回数据
-// 格式: v_sh000001="1~上证指数~000001~3150.25~3135.00~3140.50~..."
-func (api *StockAPI) parseTencentResponse(data string) (map[string]*models.StockPrice, error) {
-	result := make(map[string]*models.StockPrice)
-
-	re := regexp.MustCompile(`v_(\w+)="([^"]*)"`)
-	matches := re.FindAllStringSubmatch(data, -1)
-
-	for _, match := range matches {
-		if len(match) < 3 {
-			continue
-		}
-		code := match[1]
-		values := match[2]
-
-		if values == "" {
-			continue
-		}
-
-		price := api.parseTencentStockData(code, values)
-		if price != nil {
-			result[code] = price
-		}
-	}
-
-	return result, nil
-}
-
-// parseTencentStockData 解析腾讯单只股票数据
-// 腾讯数据格式（以~分隔）：
-// 0:未知 1:名称 2:代码 3:当前价 4:昨收 5:今开 6:成交量(手) 7:外盘 8:内盘
-// 9:买一价 10:买一量 ... 29:最高 30:最低 31:价格/涨跌/涨跌幅 32:成交量(手)
-// 33:成交额(万) 34:换手率 35:市盈率 36:最高 37:最低 38:振幅 39:流通市值
-// 40:总市值 41:市净率 42:涨停价 43:跌停价
-func (api *StockAPI) parseTencentStockData(code, data string) *models.StockPrice {
-	parts := strings.Split(data, "~")
-
-	if len(parts) < 35 {
-		return nil
-	}
-
-	price := &models.StockPrice{
-		Code:       code,
-		Name:       parts[1],
-		Price:      parseFloat(parts[3]),
-		PreClose:   parseFloat(parts[4]),
-		Open:       parseFloat(parts[5]),
-		Volume:     parseInt(parts[6]) * 100, // 腾讯返回的是手，转换为股
-		High:       parseFloat(parts[33]),
-		Low:        parseFloat(parts[34]),
-		Amount:     parseFloat(parts[37]) * 10000, // 腾讯返回的是万元
-		UpdateTime: time.Now().Format("2006-01-02 15:04:05"),
-	}
-
-	// 尝试从更准确的位置获取最高最低价
-	if len(parts) > 41 {
-		if h := parseFloat(parts[41]); h > 0 {
-			price.High = h
-		}
-		if l := parseFloat(parts[42]); l > 0 {
-			price.Low = l
-		}
-	}
-
-	price.Change = price.Price - price.PreClose
-	if price.PreClose > 0 {
-		price.ChangePercent = (price.Change / price.PreClose) * 100
-	}
-
-	return price
+func (api *StockAPI) GetStockPriceFromTencent(ctx context.Context, codes []string) (map[string]*models.StockPrice, error) {
+	return GetMultiSourceManager().FetchQuotesFrom(ctx, "tencent", codes)
 }
 
 // GetStockPriceFromNetease 从网易获取股票实时价格
-func (api *StockAPI) GetStockPriceFromNetease(codes []string) (map[string]*models.StockPrice, error) {
+func (api *StockAPI) GetStockPriceFromNetease(ctx context.Context, codes []string) (map[string]*models.StockPrice, error) {
 	if len(codes) == 0 {
 		return nil, nil
 	}
@@ -1209,7 +1144,7 @@ func (api *StockAPI) GetStockPriceFromNetease(codes []string) (map[string]*model
 	codeList := strings.Join(neteaseCodes, ",")
 	url := fmt.Sprintf("https://api.money.126.net/data/feed/%s", codeList)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -1228,7 +1163,14 @@ func (api *StockAPI) GetStockPriceFromNetease(codes []string) (map[string]*model
 		return nil, err
 	}
 
-	return api.parseNeteaseResponse(string(body), codeMap)
+	result, err := api.parseNeteaseResponse(string(body), codeMap)
+	if err != nil {
+		return nil, err
+	}
+	if err := api.schema.Check(stockQuoteSchema("netease"), body, stockPriceRecords(result)); err != nil {
+		return nil, err
+	}
+	return result, nil
 }
 
 // parseNeteaseResponse 解析网易接口返回数据
@@ -1305,200 +1247,39 @@ const (
 	DataSourceHexun
 )
 
-// 轮询状态管理
-var (
-	roundRobinIndex int
-	roundRobinMu    sync.Mutex
-)
+// dataSourceTypeProviders DataSourceType 与已注册行情数据源名称的对应关系
+var dataSourceTypeProviders = map[DataSourceType]string{
+	DataSourceSina:      "sina",
+	DataSourceTencent:   "tencent",
+	DataSourceNetease:   "netease",
+	DataSourceEastmoney: "eastmoney",
+	DataSourceSohu:      "sohu",
+	DataSourceXueqiu:    "xueqiu",
+	DataSourceBaidu:     "baidu",
+	DataSourceHexun:     "hexun",
+}
 
 // GetStockPriceMultiSource 多数据源获取股票实时价格（自动轮换和故障转移）
-func (api *StockAPI) GetStockPriceMultiSource(codes []string) (map[string]*models.StockPrice, error) {
-	if len(codes) == 0 {
-		return nil, nil
-	}
-
-	// 数据源列表，按优先级排序（限制宽松的优先）
-	sources := []struct {
-		name   string
-		fetch  func([]string) (map[string]*models.StockPrice, error)
-		domain string
-	}{
-		{"Sina", api.GetStockPrice, "hq.sinajs.cn"},
-		{"Tencent", api.GetStockPriceFromTencent, "qt.gtimg.cn"},
-		{"Netease", api.GetStockPriceFromNetease, "api.money.126.net"},
-		{"Eastmoney", api.GetStockPriceFromEastmoney, "push2.eastmoney.com"},
-		{"Sohu", api.GetStockPriceFromSohu, "hq.stock.sohu.com"},
-	}
-
-	// 尝试每个数据源
-	var lastErr error
-	for _, source := range sources {
-		// 检查数据源是否可用
-		if !api.rm.IsSourceAvailable(source.domain) {
-			log.Printf("[行情] %s 数据源暂时不可用，跳过", source.name)
-			continue
-		}
-
-		result, err := source.fetch(codes)
-		if err != nil {
-			log.Printf("[行情] %s 获取失败: %v", source.name, err)
-			api.rm.MarkSourceFailed(source.domain)
-			lastErr = err
-			continue
-		}
-
-		if len(result) > 0 {
-			log.Printf("[行情] 成功从 %s 获取 %d 条数据", source.name, len(result))
-			api.rm.MarkSourceSuccess(source.domain)
-			return result, nil
-		}
-	}
-
-	if lastErr != nil {
-		return nil, lastErr
-	}
-	return nil, fmt.Errorf("所有数据源均不可用")
+func (api *StockAPI) GetStockPriceMultiSource(ctx context.Context, codes []string) (map[string]*models.StockPrice, error) {
+	return api.GetStockPrice(ctx, codes)
 }
 
 // GetStockPriceWithSource 从指定数据源获取股票价格
-func (api *StockAPI) GetStockPriceWithSource(codes []string, source DataSourceType) (map[string]*models.StockPrice, error) {
-	switch source {
-	case DataSourceSina:
-		return api.GetStockPrice(codes)
-	case DataSourceTencent:
-		return api.GetStockPriceFromTencent(codes)
-	case DataSourceNetease:
-		return api.GetStockPriceFromNetease(codes)
-	case DataSourceEastmoney:
-		return api.GetStockPriceFromEastmoney(codes)
-	case DataSourceSohu:
-		return api.GetStockPriceFromSohu(codes)
-	case DataSourceXueqiu:
-		return api.GetStockPriceFromXueqiu(codes)
-	case DataSourceBaidu:
-		return api.GetStockPriceFromBaidu(codes)
-	case DataSourceHexun:
-		return api.GetStockPriceFromHexun(codes)
-	default:
-		return api.GetStockPriceMultiSource(codes)
+func (api *StockAPI) GetStockPriceWithSource(ctx context.Context, codes []string, source DataSourceType) (map[string]*models.StockPrice, error) {
+	name, ok := dataSourceTypeProviders[source]
+	if !ok {
+		return api.GetStockPrice(ctx, codes)
 	}
+	return GetMultiSourceManager().FetchQuotesFrom(ctx, name, codes)
 }
 
 // GetStockPriceFromEastmoney 从东方财富获取股票实时价格
-func (api *StockAPI) GetStockPriceFromEastmoney(codes []string) (map[string]*models.StockPrice, error) {
-	if len(codes) == 0 {
-		return nil, nil
-	}
-
-	result := make(map[string]*models.StockPrice)
-
-	// 东方财富需要逐个请求或使用批量接口
-	// 转换代码格式：sh600519 -> 1.600519, sz000001 -> 0.000001
-	var secids []string
-	codeMap := make(map[string]string) // secid -> 原始代码
-	for _, code := range codes {
-		var secid string
-		if strings.HasPrefix(code, "sh") {
-			secid = "1." + code[2:]
-		} else if strings.HasPrefix(code, "sz") {
-			secid = "0." + code[2:]
-		} else {
-			continue
-		}
-		secids = append(secids, secid)
-		codeMap[secid] = code
-	}
-
-	if len(secids) == 0 {
-		return nil, nil
-	}
-
-	// 使用批量接口
-	secidList := strings.Join(secids, ",")
-	url := fmt.Sprintf("https://push2.eastmoney.com/api/qt/ulist/get?fltt=2&secids=%s&fields=f1,f2,f3,f4,f5,f6,f7,f8,f9,f10,f12,f13,f14,f15,f16,f17,f18", secidList)
-
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("Referer", "https://quote.eastmoney.com")
-	req.Header.Set("User-Agent", api.rm.GetRandomUA())
-
-	// 使用限流器
-	resp, err := api.rm.DoRequestWithRateLimit("push2.eastmoney.com", req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	// 解析响应
-	var jsonResp struct {
-		Data struct {
-			Diff []struct {
-				F2  float64 `json:"f2"`  // 最新价
-				F3  float64 `json:"f3"`  // 涨跌幅
-				F4  float64 `json:"f4"`  // 涨跌额
-				F5  int64   `json:"f5"`  // 成交量（手）
-				F6  float64 `json:"f6"`  // 成交额
-				F12 string  `json:"f12"` // 代码
-				F13 int     `json:"f13"` // 市场（0深圳，1上海）
-				F14 string  `json:"f14"` // 名称
-				F15 float64 `json:"f15"` // 最高
-				F16 float64 `json:"f16"` // 最低
-				F17 float64 `json:"f17"` // 今开
-				F18 float64 `json:"f18"` // 昨收
-			} `json:"diff"`
-		} `json:"data"`
-	}
-
-	if err := json.Unmarshal(body, &jsonResp); err != nil {
-		return nil, err
-	}
-
-	for _, item := range jsonResp.Data.Diff {
-		// 构造secid
-		var secid string
-		if item.F13 == 1 {
-			secid = fmt.Sprintf("1.%s", item.F12)
-		} else {
-			secid = fmt.Sprintf("0.%s", item.F12)
-		}
-
-		originalCode, ok := codeMap[secid]
-		if !ok {
-			continue
-		}
-
-		// 注意：这个接口使用了 fltt=2 参数，返回的是原始值，不需要除以100
-		// 与 multi_source.go 中的接口不同（那个没有 fltt=2，需要除以100）
-		price := &models.StockPrice{
-			Code:          originalCode,
-			Name:          item.F14,
-			Price:         item.F2,
-			PreClose:      item.F18,
-			Open:          item.F17,
-			High:          item.F15,
-			Low:           item.F16,
-			Volume:        item.F5 * 100, // 手转股
-			Amount:        item.F6,
-			Change:        item.F4,
-			ChangePercent: item.F3,
-			UpdateTime:    time.Now().Format("2006-01-02 15:04:05"),
-		}
-
-		result[originalCode] = price
-	}
-
-	return result, nil
+func (api *StockAPI) GetStockPriceFromEastmoney(ctx context.Context, codes []string) (map[string]*models.StockPrice, error) {
+	return GetMultiSourceManager().FetchQuotesFrom(ctx, "eastmoney", codes)
 }
 
 // GetStockPriceFromSohu 从搜狐获取股票实时价格
-func (api *StockAPI) GetStockPriceFromSohu(codes []string) (map[string]*models.StockPrice, error) {
+func (api *StockAPI) GetStockPriceFromSohu(ctx context.Context, codes []string) (map[string]*models.StockPrice, error) {
 	if len(codes) == 0 {
 		return nil, nil
 	}
@@ -1526,7 +1307,7 @@ func (api *StockAPI) GetStockPriceFromSohu(codes []string) (map[string]*models.S
 	codeList := strings.Join(sohucodes, ",")
 	url := fmt.Sprintf("https://hq.stock.sohu.com/hqdata/getquote.php?code=%s&callback=", codeList)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -1545,7 +1326,14 @@ func (api *StockAPI) GetStockPriceFromSohu(codes []string) (map[string]*models.S
 		return nil, err
 	}
 
-	return api.parseSohuResponse(string(body), codeMap)
+	result, err := api.parseSohuResponse(string(body), codeMap)
+	if err != nil {
+		return nil, err
+	}
+	if err := api.schema.Check(stockQuoteSchema("sohu"), body, stockPriceRecords(result)); err != nil {
+		return nil, err
+	}
+	return result, nil
 }
 
 // parseSohuResponse 解析搜狐接口返回数据
@@ -1686,7 +1474,7 @@ func (api *StockAPI) GetReportContent(infoCode string) (string, error) {
 }
 
 // GetNoticeContent 获取公告内容（通过东方财富API）
-func (api *StockAPI) GetNoticeContent(stockCode string, artCode string) (string, error) {
+func (api *StockAPI) GetNoticeContent(ctx context.Context, stockCode string, artCode string) (string, error) {
 	if artCode == "" {
 		return "", fmt.Errorf("artCode为空")
 	}
@@ -1711,7 +1499,7 @@ func (api *StockAPI) GetNoticeContent(stockCode string, artCode string) (string,
 
 	log.Printf("[公告内容] 请求URL: %s", url)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return "", err
 	}
@@ -1757,7 +1545,7 @@ func (api *StockAPI) GetNoticeContent(stockCode string, artCode string) (string,
 	content := notice.Content
 	if content == "" {
 		// 如果API没有返回内容，尝试获取公告PDF/HTML内容
-		content = api.fetchNoticeDetailContent(fullCode, artCode)
+		content = api.fetchNoticeDetailContent(ctx, fullCode, artCode)
 	}
 
 	if content == "" {
@@ -1774,11 +1562,11 @@ func (api *StockAPI) GetNoticeContent(stockCode string, artCode string) (string,
 }
 
 // fetchNoticeDetailContent 获取公告详细内容
-func (api *StockAPI) fetchNoticeDetailContent(stockCode string, artCode string) string {
+func (api *StockAPI) fetchNoticeDetailContent(ctx context.Context, stockCode string, artCode string) string {
 	// 尝试获取公告的文本内容
 	url := fmt.Sprintf("https://data.eastmoney.com/notices/detail/%s/%s.html", stockCode, artCode)
 
-	content, err := api.rm.FetchWebContent(url)
+	content, err := api.rm.FetchWebContent(ctx, url)
 	if err != nil {
 		log.Printf("[公告详情] 抓取失败: %v", err)
 		return ""
@@ -1790,7 +1578,7 @@ func (api *StockAPI) fetchNoticeDetailContent(stockCode string, artCode string)
 // ==================== 新增数据源：雪球、百度、和讯 ====================
 
 // GetStockPriceFromXueqiu 从雪球获取股票实时价格
-func (api *StockAPI) GetStockPriceFromXueqiu(codes []string) (map[string]*models.StockPrice, error) {
+func (api *StockAPI) GetStockPriceFromXueqiu(ctx context.Context, codes []string) (map[string]*models.StockPrice, error) {
 	if len(codes) == 0 {
 		return nil, nil
 	}
@@ -1820,7 +1608,7 @@ func (api *StockAPI) GetStockPriceFromXueqiu(codes []string) (map[string]*models
 	codeList := strings.Join(xueqiuCodes, ",")
 	url := fmt.Sprintf("https://stock.xueqiu.com/v5/stock/realtime/quotec.json?symbol=%s", codeList)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -1896,7 +1684,7 @@ func (api *StockAPI) GetStockPriceFromXueqiu(codes []string) (map[string]*models
 }
 
 // GetStockPriceFromBaidu 从百度股市通获取股票实时价格
-func (api *StockAPI) GetStockPriceFromBaidu(codes []string) (map[string]*models.StockPrice, error) {
+func (api *StockAPI) GetStockPriceFromBaidu(ctx context.Context, codes []string) (map[string]*models.StockPrice, error) {
 	if len(codes) == 0 {
 		return nil, nil
 	}
@@ -1907,7 +1695,7 @@ func (api *StockAPI) GetStockPriceFromBaidu(codes []string) (map[string]*models.
 	codeList := strings.Join(codes, ",")
 	url := fmt.Sprintf("https://finance.pae.baidu.com/selfselect/getstockquotation?all=1&code=%s&isIndex=false&isBk=false&isBlock=false&isFutures=false&isStock=true&newFormat=1&is_kc=0", codeList)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -1983,7 +1771,7 @@ func (api *StockAPI) GetStockPriceFromBaidu(codes []string) (map[string]*models.
 }
 
 // GetStockPriceFromHexun 从和讯获取股票实时价格
-func (api *StockAPI) GetStockPriceFromHexun(codes []string) (map[string]*models.StockPrice, error) {
+func (api *StockAPI) GetStockPriceFromHexun(ctx context.Context, codes []string) (map[string]*models.StockPrice, error) {
 	if len(codes) == 0 {
 		return nil, nil
 	}
@@ -2011,7 +1799,7 @@ func (api *StockAPI) GetStockPriceFromHexun(codes []string) (map[string]*models.
 	codeList := strings.Join(hexunCodes, ",")
 	url := fmt.Sprintf("http://webstock.quote.hermes.hexun.com/a/quotelist?code=%s&callback=", codeList)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -2030,7 +1818,14 @@ func (api *StockAPI) GetStockPriceFromHexun(codes []string) (map[string]*models.
 		return nil, err
 	}
 
-	return api.parseHexunResponse(string(body), codeMap)
+	result, err := api.parseHexunResponse(string(body), codeMap)
+	if err != nil {
+		return nil, err
+	}
+	if err := api.schema.Check(stockQuoteSchema("hexun"), body, stockPriceRecords(result)); err != nil {
+		return nil, err
+	}
+	return result, nil
 }
 
 // parseHexunResponse 解析和讯接口返回数据
@@ -2131,75 +1926,17 @@ func getFloatFromInterface(v interface{}) float64 {
 
 // ==================== 轮询模式实现 ====================
 
-// GetStockPriceRoundRobin 轮询模式获取股票实时价格
-// 每次调用使用下一个数据源，实现真正的轮询
-func (api *StockAPI) GetStockPriceRoundRobin(codes []string) (map[string]*models.StockPrice, string, error) {
-	if len(codes) == 0 {
-		return nil, "", nil
-	}
-
-	// 数据源列表（8个数据源）
-	sources := []struct {
-		name   string
-		fetch  func([]string) (map[string]*models.StockPrice, error)
-		domain string
-	}{
-		{"Sina", api.GetStockPrice, "hq.sinajs.cn"},
-		{"Tencent", api.GetStockPriceFromTencent, "qt.gtimg.cn"},
-		{"Netease", api.GetStockPriceFromNetease, "api.money.126.net"},
-		{"Eastmoney", api.GetStockPriceFromEastmoney, "push2.eastmoney.com"},
-		{"Sohu", api.GetStockPriceFromSohu, "hq.stock.sohu.com"},
-		{"Xueqiu", api.GetStockPriceFromXueqiu, "stock.xueqiu.com"},
-		{"Baidu", api.GetStockPriceFromBaidu, "finance.pae.baidu.com"},
-		{"Hexun", api.GetStockPriceFromHexun, "webstock.quote.hermes.hexun.com"},
-	}
-
-	// 获取当前轮询索引
-	roundRobinMu.Lock()
-	currentIndex := roundRobinIndex
-	roundRobinIndex = (roundRobinIndex + 1) % len(sources)
-	roundRobinMu.Unlock()
-
-	// 尝试当前数据源
-	source := sources[currentIndex]
-	log.Printf("[行情轮询] 使用数据源 #%d: %s", currentIndex+1, source.name)
-
-	result, err := source.fetch(codes)
-	if err != nil {
-		log.Printf("[行情轮询] %s 获取失败: %v，尝试下一个数据源", source.name, err)
-		// 如果当前数据源失败，尝试下一个
-		for i := 1; i < len(sources); i++ {
-			nextIndex := (currentIndex + i) % len(sources)
-			nextSource := sources[nextIndex]
-			log.Printf("[行情轮询] 故障转移到: %s", nextSource.name)
-			result, err = nextSource.fetch(codes)
-			if err == nil && len(result) > 0 {
-				log.Printf("[行情轮询] 成功从 %s 获取 %d 条数据", nextSource.name, len(result))
-				return result, nextSource.name, nil
-			}
-		}
-		return nil, "", fmt.Errorf("所有数据源均不可用")
-	}
-
-	if len(result) > 0 {
-		log.Printf("[行情轮询] 成功从 %s 获取 %d 条数据", source.name, len(result))
-		return result, source.name, nil
-	}
-
-	return nil, "", fmt.Errorf("数据源 %s 返回空数据", source.name)
+// GetStockPriceRoundRobin 获取股票实时价格并返回实际使用的数据源名称
+// 数据源选择由 MultiSourceManager 按健康分统一调度
+func (api *StockAPI) GetStockPriceRoundRobin(ctx context.Context, codes []string) (map[string]*models.StockPrice, string, error) {
+	return GetMultiSourceManager().FetchQuotes(ctx, codes)
 }
 
-// GetCurrentRoundRobinSource 获取当前轮询数据源名称（用于显示）
+// GetCurrentRoundRobinSource 获取当前最优数据源名称（用于显示）
 func (api *StockAPI) GetCurrentRoundRobinSource() string {
-	sources := []string{"Sina", "Tencent", "Netease", "Eastmoney", "Sohu", "Xueqiu", "Baidu", "Hexun"}
-	roundRobinMu.Lock()
-	defer roundRobinMu.Unlock()
-	return sources[roundRobinIndex]
-}
-
-// ResetRoundRobinIndex 重置轮询索引（用于测试）
-func (api *StockAPI) ResetRoundRobinIndex() {
-	roundRobinMu.Lock()
-	defer roundRobinMu.Unlock()
-	roundRobinIndex = 0
+	names := GetMultiSourceManager().QuoteProviderNames()
+	if len(names) == 0 {
+		return ""
+	}
+	return names[0]
 }
@@ -0,0 +1,394 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"stock-ai/backend/models"
+	"stock-ai/backend/screener"
+
+	"gorm.io/gorm/clause"
+)
+
+// ==================== 量化选股 ====================
+
+const (
+	screenerKLineCount    = 120 // 技术快照使用的日K数量
+	screenerRefreshWorker = 4   // 刷新因子快照的并发数
+
+	screenerSnapshotMaxAge = 30 * 24 * time.Hour // 超过该时长未刷新的因子快照不参与选股
+)
+
+// BuildStockFactors 汇总单只股票的财务、估值分位与技术快照因子，缺失的数据不写入
+func BuildStockFactors(ctx context.Context, code string) (screener.Factors, string, error) {
+	factors := screener.Factors{}
+	var errs []string
+
+	// 财务指标（最新报告期）
+	if trend, err := GetFinancialTrend(ctx, code); err == nil {
+		addFundamentalFactors(factors, trend)
+		if statements, err := LoadFinancialStatements(code); err == nil && len(statements) > 0 {
+			latest := statements[len(statements)-1]
+			if latest.CurrentLiab > 0 {
+				factors["current_ratio"] = latest.CurrentAssets / latest.CurrentLiab
+			}
+		}
+	} else {
+		errs = append(errs, "财务: "+err.Error())
+	}
+
+	// 估值与历史分位
+	if bands, err := GetValuationBands(ctx, code); err == nil {
+		addValuationFactors(factors, bands)
+	} else {
+		errs = append(errs, "估值: "+err.Error())
+	}
+
+	// 日线技术快照
+	tradeDate := ""
+	if klines, _, err := GetMultiSourceManager().FetchKLine(ctx, code, "daily", screenerKLineCount); err == nil && len(klines) > 0 {
+		tradeDate = addTechnicalFactors(factors, klines)
+	} else if err != nil {
+		errs = append(errs, "K线: "+err.Error())
+	}
+
+	if len(factors) == 0 {
+		return nil, "", fmt.Errorf("无可用因子数据: %s", strings.Join(errs, "; "))
+	}
+	return factors, tradeDate, nil
+}
+
+// addFundamentalFactors 写入最新报告期的财务因子
+func addFundamentalFactors(factors screener.Factors, trend *models.FinancialTrend) {
+	// 数据源顺序不可靠，先按报告期倒序排列
+	periods := append([]models.FinancialPeriodMetrics(nil), trend.Periods...)
+	sort.SliceStable(periods, func(i, j int) bool { return periods[i].ReportDate > periods[j].ReportDate })
+
+	for _, p := range periods {
+		if !p.HasTTM {
+			continue
+		}
+		factors["roe"] = p.ROE
+		factors["gross_margin"] = p.GrossMargin
+		factors["net_margin"] = p.NetMargin
+		factors["debt_ratio"] = p.DebtRatio
+		factors["revenue"] = p.RevenueTTM
+		factors["net_profit"] = p.NetProfitTTM
+		factors["operating_cf"] = p.OperatingCFTTM
+		factors["free_cash_flow"] = p.FreeCashFlowTTM
+		factors["accrual_ratio"] = p.AccrualRatio
+		factors["asset_turnover"] = p.AssetTurnover
+		factors["equity_multiplier"] = p.EquityMultiplier
+		if p.NetProfitTTM > 0 {
+			factors["cash_conversion"] = p.CashConversion
+		}
+		if p.HasTTMYoY {
+			factors["revenue_growth"] = p.RevenueTTMYoY
+			factors["profit_growth"] = p.NetProfitTTMYoY
+		}
+		break
+	}
+	// 单季同比只取最新一期，缺失时宁可留空也不拿旧报告期顶替
+	if len(periods) > 0 && periods[0].HasYoY {
+		factors["quarter_revenue_growth"] = periods[0].RevenueYoY
+		factors["quarter_profit_growth"] = periods[0].NetProfitYoY
+	}
+}
+
+// addValuationFactors 写入估值与历史分位因子，亏损等无效估值不写入
+func addValuationFactors(factors screener.Factors, bands *models.ValuationBands) {
+	for _, band := range bands.Bands {
+		if !band.Valid {
+			continue
+		}
+		factors[band.Metric] = band.Current
+		for _, p := range band.Percentiles {
+			factors[fmt.Sprintf("%s_pct_%dy", band.Metric, p.Years)] = p.Percentile
+		}
+		if band.IndustryMedian > 0 {
+			factors[band.Metric+"_vs_industry"] = band.Premium
+		}
+	}
+}
+
+// addTechnicalFactors 写入日线技术快照因子，返回最新交易日
+func addTechnicalFactors(factors screener.Factors, klines []models.KLineData) string {
+	snapshot := buildIndicatorSnapshot(klines)
+	closes := make([]float64, len(klines))
+	for i, k := range klines {
+		closes[i] = k.Close
+	}
+
+	factors["close"] = snapshot.Close
+	factors["change_pct"] = snapshot.ChangePct
+	factors["ma5"] = snapshot.MA5
+	factors["ma10"] = snapshot.MA10
+	factors["ma20"] = snapshot.MA20
+	if ma60 := calcSimpleMA(closes, 60); ma60 > 0 {
+		factors["ma60"] = ma60
+	}
+	factors["rsi"] = snapshot.RSI
+	factors["macd_dif"] = snapshot.MACDDif
+	factors["macd_dea"] = snapshot.MACDDea
+	factors["macd_hist"] = snapshot.MACDHist
+	factors["kdj_k"] = snapshot.K
+	factors["kdj_d"] = snapshot.D
+	factors["kdj_j"] = snapshot.J
+	factors["adx"] = snapshot.ADX
+	factors["high_30"] = snapshot.RangeHigh30
+	factors["low_30"] = snapshot.RangeLow30
+	for _, days := range []int{20, 60} {
+		if len(closes) > days && closes[len(closes)-1-days] > 0 {
+			factors[fmt.Sprintf("return_%dd", days)] = (snapshot.Close/closes[len(closes)-1-days] - 1) * 100
+		}
+	}
+
+	date := klines[len(klines)-1].Date
+	if len(date) > 10 {
+		date = date[:10]
+	}
+	return date
+}
+
+// SaveFactorSnapshot 保存因子快照（同一股票覆盖）
+func SaveFactorSnapshot(code, name, tradeDate string, factors screener.Factors) error {
+	payload, err := json.Marshal(factors)
+	if err != nil {
+		return err
+	}
+	return GetDB().Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "stock_code"}},
+		DoUpdates: clause.AssignmentColumns([]string{"stock_name", "trade_date", "factors", "updated_at"}),
+	}).Create(&models.StockFactorSnapshot{
+		StockCode: code,
+		StockName: name,
+		TradeDate: tradeDate,
+		Factors:   string(payload),
+	}).Error
+}
+
+// RefreshFactorSnapshots 并发刷新一批股票的因子快照，返回成功数量
+func RefreshFactorSnapshots(ctx context.Context, stocks []models.Stock) int {
+	jobs := make(chan models.Stock)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	success := 0
+
+	for i := 0; i < screenerRefreshWorker; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for stock := range jobs {
+				factors, tradeDate, err := BuildStockFactors(ctx, stock.Code)
+				if err != nil {
+					log.Printf("[量化选股] %s 因子计算失败: %v", stock.Code, err)
+					continue
+				}
+				if err := SaveFactorSnapshot(stock.Code, stock.Name, tradeDate, factors); err != nil {
+					log.Printf("[量化选股] %s 保存因子失败: %v", stock.Code, err)
+					continue
+				}
+				mu.Lock()
+				success++
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, stock := range stocks {
+		jobs <- stock
+	}
+	close(jobs)
+	wg.Wait()
+
+	log.Printf("[量化选股] 因子快照刷新完成: %d/%d", success, len(stocks))
+	return success
+}
+
+// PruneFactorSnapshots 删除不在 keep 中的股票的因子快照（如已移出自选），返回删除数量
+func PruneFactorSnapshots(keep []string) (int64, error) {
+	query := GetDB()
+	if len(keep) > 0 {
+		query = query.Where("stock_code NOT IN ?", keep)
+	} else {
+		query = query.Where("1 = 1")
+	}
+	result := query.Delete(&models.StockFactorSnapshot{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("[量化选股] 清理 %d 条过期因子快照", result.RowsAffected)
+	}
+	return result.RowsAffected, nil
+}
+
+// RunScreenExpression 在本地因子快照上执行选股表达式，只使用近期刷新过的快照
+func RunScreenExpression(expression string) (*models.ScreenResult, error) {
+	program, err := screener.Compile(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshots []models.StockFactorSnapshot
+	cutoff := time.Now().Add(-screenerSnapshotMaxAge)
+	if err := GetDB().Where("updated_at >= ?", cutoff).Order("stock_code ASC").Find(&snapshots).Error; err != nil {
+		return nil, err
+	}
+	if len(snapshots) == 0 {
+		return nil, fmt.Errorf("暂无近期的本地因子数据，请先刷新选股数据")
+	}
+
+	result := &models.ScreenResult{
+		Expression: program.Source(),
+		Fields:     program.Fields(),
+		RunDate:    time.Now().Format("2006-01-02"),
+		Universe:   len(snapshots),
+		Matches:    []models.ScreenMatch{},
+	}
+	for _, snap := range snapshots {
+		var factors screener.Factors
+		if err := json.Unmarshal([]byte(snap.Factors), &factors); err != nil {
+			continue
+		}
+		if !program.Match(factors) {
+			continue
+		}
+		match := models.ScreenMatch{Code: snap.StockCode, Name: snap.StockName, Factors: map[string]float64{}}
+		for _, field := range program.Fields() {
+			if v, ok := factors[field]; ok {
+				match.Factors[field] = v
+			}
+		}
+		result.Matches = append(result.Matches, match)
+	}
+	return result, nil
+}
+
+// SaveScreen 新建或更新选股条件（ID 为0时新建），保存前校验表达式
+func SaveScreen(screen *models.SavedScreen) error {
+	screen.Name = strings.TrimSpace(screen.Name)
+	if screen.Name == "" {
+		return fmt.Errorf("选股条件名称不能为空")
+	}
+	program, err := screener.Compile(screen.Expression)
+	if err != nil {
+		return fmt.Errorf("表达式错误: %w", err)
+	}
+	screen.Expression = program.Source()
+	if screen.ID == 0 {
+		return GetDB().Create(screen).Error
+	}
+	return GetDB().Model(&models.SavedScreen{}).Where("id = ?", screen.ID).Updates(map[string]interface{}{
+		"name":        screen.Name,
+		"expression":  screen.Expression,
+		"description": screen.Description,
+	}).Error
+}
+
+// ListScreens 获取保存的选股条件
+func ListScreens() ([]models.SavedScreen, error) {
+	var screens []models.SavedScreen
+	err := GetDB().Order("created_at ASC").Find(&screens).Error
+	return screens, err
+}
+
+// DeleteScreen 删除选股条件及其运行记录
+func DeleteScreen(id uint) error {
+	if err := GetDB().Where("screen_id = ?", id).Delete(&models.ScreenRun{}).Error; err != nil {
+		return err
+	}
+	return GetDB().Delete(&models.SavedScreen{}, id).Error
+}
+
+// ListScreenRuns 获取选股条件的历史运行记录（按日期倒序）
+func ListScreenRuns(id uint, limit int) ([]models.ScreenRun, error) {
+	var runs []models.ScreenRun
+	err := GetDB().Where("screen_id = ?", id).Order("run_date DESC").Limit(limit).Find(&runs).Error
+	return runs, err
+}
+
+// RunSavedScreen 运行保存的选股条件，记录当日结果并与上一次运行对比
+func RunSavedScreen(id uint) (*models.ScreenResult, error) {
+	var screen models.SavedScreen
+	if err := GetDB().First(&screen, id).Error; err != nil {
+		return nil, fmt.Errorf("选股条件不存在")
+	}
+	result, err := RunScreenExpression(screen.Expression)
+	if err != nil {
+		return nil, err
+	}
+	result.ScreenID = screen.ID
+
+	codes := make([]string, len(result.Matches))
+	for i, m := range result.Matches {
+		codes[i] = m.Code
+	}
+
+	var prev models.ScreenRun
+	if err := GetDB().Where("screen_id = ? AND run_date < ?", screen.ID, result.RunDate).Order("run_date DESC").First(&prev).Error; err == nil {
+		result.PrevRunDate = prev.RunDate
+		result.NewEntrants, result.Exits = diffScreenCodes(splitScreenCodes(prev.Codes), codes)
+		entrants := make(map[string]bool, len(result.NewEntrants))
+		for _, code := range result.NewEntrants {
+			entrants[code] = true
+		}
+		for i := range result.Matches {
+			result.Matches[i].NewEntry = entrants[result.Matches[i].Code]
+		}
+	}
+
+	run := models.ScreenRun{
+		ScreenID: screen.ID,
+		RunDate:  result.RunDate,
+		Codes:    strings.Join(codes, ","),
+		Count:    len(codes),
+		Universe: result.Universe,
+	}
+	if err := GetDB().Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "screen_id"}, {Name: "run_date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"codes", "count", "universe", "updated_at"}),
+	}).Create(&run).Error; err != nil {
+		log.Printf("[量化选股] 保存运行记录失败: %v", err)
+	}
+
+	now := time.Now()
+	GetDB().Model(&screen).Updates(map[string]interface{}{"last_run_at": &now, "last_count": len(codes)})
+	return result, nil
+}
+
+// splitScreenCodes 拆分逗号分隔的代码
+func splitScreenCodes(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
+
+// diffScreenCodes 对比两次结果，返回新入选与退出的代码（均排序）
+func diffScreenCodes(prev, current []string) (entrants, exits []string) {
+	prevSet := make(map[string]bool, len(prev))
+	for _, code := range prev {
+		prevSet[code] = true
+	}
+	currentSet := make(map[string]bool, len(current))
+	for _, code := range current {
+		currentSet[code] = true
+		if !prevSet[code] {
+			entrants = append(entrants, code)
+		}
+	}
+	for _, code := range prev {
+		if !currentSet[code] {
+			exits = append(exits, code)
+		}
+	}
+	sort.Strings(entrants)
+	sort.Strings(exits)
+	return entrants, exits
+}
@@ -0,0 +1,29 @@
+package data
+
+import (
+	"testing"
+
+	"stock-ai/backend/models"
+	"stock-ai/backend/screener"
+)
+
+func TestAddFundamentalFactorsUsesLatestPeriod(t *testing.T) {
+	trend := &models.FinancialTrend{Periods: []models.FinancialPeriodMetrics{
+		{ReportDate: "2024-06-30", HasYoY: true, NetProfitYoY: 30, HasTTM: true, ROE: 10},
+		{ReportDate: "2024-09-30", HasYoY: true, NetProfitYoY: -12, HasTTM: true, ROE: 8},
+		{ReportDate: "2024-03-31", HasYoY: true, NetProfitYoY: 50},
+	}}
+	factors := screener.Factors{}
+	addFundamentalFactors(factors, trend)
+	if factors["quarter_profit_growth"] != -12 || factors["roe"] != 8 {
+		t.Fatalf("应使用最新报告期 2024-09-30, got %v", factors)
+	}
+
+	// 最新一期缺少同比时不回退到旧报告期
+	trend.Periods[1].HasYoY = false
+	factors = screener.Factors{}
+	addFundamentalFactors(factors, trend)
+	if _, ok := factors["quarter_profit_growth"]; ok {
+		t.Fatalf("最新一期无同比时不应写入, got %v", factors["quarter_profit_growth"])
+	}
+}
@@ -2,6 +2,7 @@ package data
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -13,12 +14,11 @@ import (
 
 // TushareClient Tushare数据客户端
 type TushareClient struct {
-	token      string
-	baseURL    string
-	client     *http.Client
+	token       string
+	baseURL     string
+	client      *http.Client
 	rateLimiter *RateLimiter
-	cache      *DataCache
-	mu         sync.RWMutex
+	mu          sync.RWMutex
 }
 
 // TushareRequest Tushare API请求
@@ -67,12 +67,6 @@ type FinancialData struct {
 	ProfitGrowth    float64 `json:"profitGrowth"`    // 净利润同比增长（%）
 }
 
-// DataCache 数据缓存（用于Tushare和AKShare）
-type DataCache struct {
-	mu   sync.RWMutex
-	data map[string]*CacheItem
-}
-
 var (
 	globalTushareClient *TushareClient
 	tushareClientOnce   sync.Once
@@ -95,9 +89,6 @@ func NewTushareClient(token string) *TushareClient {
 			Timeout: 30 * time.Second,
 		},
 		rateLimiter: GetRateLimiter(),
-		cache: &DataCache{
-			data: make(map[string]*CacheItem),
-		},
 	}
 }
 
@@ -120,8 +111,18 @@ func (c *TushareClient) IsConfigured() bool {
 	return c.GetToken() != ""
 }
 
+// Name 数据源名称
+func (c *TushareClient) Name() string {
+	return providerTushare
+}
+
+// IsAvailable 配置了Token即视为可用
+func (c *TushareClient) IsAvailable() bool {
+	return c.IsConfigured()
+}
+
 // request 发送API请求（带限流保护）
-func (c *TushareClient) request(apiName string, params map[string]interface{}, fields string) (*TushareResponse, error) {
+func (c *TushareClient) request(ctx context.Context, apiName string, params map[string]interface{}, fields string) (*TushareResponse, error) {
 	if !c.IsConfigured() {
 		return nil, fmt.Errorf("Tushare Token未配置")
 	}
@@ -131,7 +132,7 @@ func (c *TushareClient) request(apiName string, params map[string]interface{}, f
 	var err error
 
 	err = c.rateLimiter.ExecuteWithRateLimit("tushare.pro", func() error {
-		resp, err = c.doRequest(apiName, params, fields)
+		resp, err = c.doRequest(ctx, apiName, params, fields)
 		return err
 	})
 
@@ -139,7 +140,7 @@ func (c *TushareClient) request(apiName string, params map[string]interface{}, f
 }
 
 // doRequest 实际执行请求
-func (c *TushareClient) doRequest(apiName string, params map[string]interface{}, fields string) (*TushareResponse, error) {
+func (c *TushareClient) doRequest(ctx context.Context, apiName string, params map[string]interface{}, fields string) (*TushareResponse, error) {
 	reqBody := TushareRequest{
 		APIName: apiName,
 		Token:   c.GetToken(),
@@ -154,7 +155,7 @@ func (c *TushareClient) doRequest(apiName string, params map[string]interface{},
 
 	log.Printf("[Tushare] 请求API: %s, 参数: %v", apiName, params)
 
-	req, err := http.NewRequest("POST", c.baseURL, bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return nil, fmt.Errorf("创建请求失败: %v", err)
 	}
@@ -185,70 +186,6 @@ func (c *TushareClient) doRequest(apiName string, params map[string]interface{},
 	return &tushareResp, nil
 }
 
-// getCache 获取缓存
-func (c *TushareClient) getCache(key string) (interface{}, bool) {
-	c.cache.mu.RLock()
-	defer c.cache.mu.RUnlock()
-
-	item, ok := c.cache.data[key]
-	if !ok {
-		return nil, false
-	}
-
-	if time.Now().After(item.ExpireAt) {
-		return nil, false
-	}
-
-	return item.Data, true
-}
-
-// setCache 设置缓存
-func (c *TushareClient) setCache(key string, data interface{}, ttl time.Duration) {
-	c.cache.mu.Lock()
-	defer c.cache.mu.Unlock()
-
-	c.cache.data[key] = &CacheItem{
-		Data:     data,
-		ExpireAt: time.Now().Add(ttl),
-	}
-}
-
-// ClearCache 清理所有缓存
-func (c *TushareClient) ClearCache() int {
-	c.cache.mu.Lock()
-	defer c.cache.mu.Unlock()
-
-	count := len(c.cache.data)
-	c.cache.data = make(map[string]*CacheItem)
-	return count
-}
-
-// ClearExpiredCache 清理过期缓存
-func (c *TushareClient) ClearExpiredCache() int {
-	c.cache.mu.Lock()
-	defer c.cache.mu.Unlock()
-
-	now := time.Now()
-	count := 0
-	for key, item := range c.cache.data {
-		if now.After(item.ExpireAt) {
-			delete(c.cache.data, key)
-			count++
-		}
-	}
-	return count
-}
-
-// GetCacheStats 获取缓存统计
-func (c *TushareClient) GetCacheStats() map[string]interface{} {
-	c.cache.mu.RLock()
-	defer c.cache.mu.RUnlock()
-
-	return map[string]interface{}{
-		"totalCount": len(c.cache.data),
-	}
-}
-
 // convertTsCode 转换股票代码格式（sz000001 -> 000001.SZ）
 func convertTsCode(code string) string {
 	if len(code) < 2 {
@@ -309,36 +246,36 @@ func convertFromTsCode(tsCode string) string {
 }
 
 // GetFinancialData 获取财务数据
-func (c *TushareClient) GetFinancialData(stockCode string) (*FinancialData, error) {
+func (c *TushareClient) GetFinancialData(ctx context.Context, stockCode string) (*FinancialData, error) {
 	tsCode := convertTsCode(stockCode)
 	cacheKey := fmt.Sprintf("financial_%s", tsCode)
 
 	// 检查缓存（财务数据缓存1小时）
-	if cached, ok := c.getCache(cacheKey); ok {
+	if cached, ok := financialSummaryCache.Get(cacheKey); ok {
 		log.Printf("[Tushare] 使用缓存的财务数据: %s", stockCode)
-		return cached.(*FinancialData), nil
+		return cached, nil
 	}
 
 	// 获取最新财务指标
-	indicators, err := c.getFinancialIndicators(tsCode)
+	indicators, err := c.getFinancialIndicators(ctx, tsCode)
 	if err != nil {
 		log.Printf("[Tushare] 获取财务指标失败: %v", err)
 	}
 
 	// 获取资产负债表
-	balance, err := c.getBalanceSheet(tsCode)
+	balance, err := c.getBalanceSheet(ctx, tsCode)
 	if err != nil {
 		log.Printf("[Tushare] 获取资产负债表失败: %v", err)
 	}
 
 	// 获取现金流量表
-	cashflow, err := c.getCashFlow(tsCode)
+	cashflow, err := c.getCashFlow(ctx, tsCode)
 	if err != nil {
 		log.Printf("[Tushare] 获取现金流量表失败: %v", err)
 	}
 
 	// 获取每日指标（PE、PB等）
-	daily, err := c.getDailyBasic(tsCode)
+	daily, err := c.getDailyBasic(ctx, tsCode)
 	if err != nil {
 		log.Printf("[Tushare] 获取每日指标失败: %v", err)
 	}
@@ -381,7 +318,7 @@ func (c *TushareClient) GetFinancialData(stockCode string) (*FinancialData, erro
 	}
 
 	// 缓存数据
-	c.setCache(cacheKey, data, time.Hour)
+	financialSummaryCache.Set(cacheKey, data)
 
 	return data, nil
 }
@@ -403,7 +340,7 @@ type FinancialIndicators struct {
 }
 
 // getFinancialIndicators 获取财务指标
-func (c *TushareClient) getFinancialIndicators(tsCode string) (*FinancialIndicators, error) {
+func (c *TushareClient) getFinancialIndicators(ctx context.Context, tsCode string) (*FinancialIndicators, error) {
 	params := map[string]interface{}{
 		"ts_code": tsCode,
 		"limit":   1,
@@ -411,7 +348,7 @@ func (c *TushareClient) getFinancialIndicators(tsCode string) (*FinancialIndicat
 
 	fields := "ts_code,ann_date,end_date,roe,roa,grossprofit_margin,netprofit_margin,eps,bps,current_ratio,quick_ratio,debt_to_assets,or_yoy,netprofit_yoy"
 
-	resp, err := c.request("fina_indicator", params, fields)
+	resp, err := c.request(ctx, "fina_indicator", params, fields)
 	if err != nil {
 		return nil, err
 	}
@@ -447,7 +384,7 @@ type BalanceSheet struct {
 }
 
 // getBalanceSheet 获取资产负债表
-func (c *TushareClient) getBalanceSheet(tsCode string) (*BalanceSheet, error) {
+func (c *TushareClient) getBalanceSheet(ctx context.Context, tsCode string) (*BalanceSheet, error) {
 	params := map[string]interface{}{
 		"ts_code": tsCode,
 		"limit":   1,
@@ -455,7 +392,7 @@ func (c *TushareClient) getBalanceSheet(tsCode string) (*BalanceSheet, error) {
 
 	fields := "ts_code,end_date,total_assets,total_liab,total_hldr_eqy_exc_min_int"
 
-	resp, err := c.request("balancesheet", params, fields)
+	resp, err := c.request(ctx, "balancesheet", params, fields)
 	if err != nil {
 		return nil, err
 	}
@@ -482,7 +419,7 @@ type CashFlow struct {
 }
 
 // getCashFlow 获取现金流量表
-func (c *TushareClient) getCashFlow(tsCode string) (*CashFlow, error) {
+func (c *TushareClient) getCashFlow(ctx context.Context, tsCode string) (*CashFlow, error) {
 	params := map[string]interface{}{
 		"ts_code": tsCode,
 		"limit":   1,
@@ -490,7 +427,7 @@ func (c *TushareClient) getCashFlow(tsCode string) (*CashFlow, error) {
 
 	fields := "ts_code,end_date,n_cashflow_act,n_cashflow_inv_act,n_cash_flows_fnc_act"
 
-	resp, err := c.request("cashflow", params, fields)
+	resp, err := c.request(ctx, "cashflow", params, fields)
 	if err != nil {
 		return nil, err
 	}
@@ -516,7 +453,7 @@ type DailyBasic struct {
 }
 
 // getDailyBasic 获取每日指标
-func (c *TushareClient) getDailyBasic(tsCode string) (*DailyBasic, error) {
+func (c *TushareClient) getDailyBasic(ctx context.Context, tsCode string) (*DailyBasic, error) {
 	// 获取最近交易日
 	today := time.Now().Format("20060102")
 
@@ -527,7 +464,7 @@ func (c *TushareClient) getDailyBasic(tsCode string) (*DailyBasic, error) {
 
 	fields := "ts_code,trade_date,pe,pb"
 
-	resp, err := c.request("daily_basic", params, fields)
+	resp, err := c.request(ctx, "daily_basic", params, fields)
 	if err != nil {
 		return nil, err
 	}
@@ -538,7 +475,7 @@ func (c *TushareClient) getDailyBasic(tsCode string) (*DailyBasic, error) {
 			"ts_code": tsCode,
 			"limit":   1,
 		}
-		resp, err = c.request("daily_basic", params, fields)
+		resp, err = c.request(ctx, "daily_basic", params, fields)
 		if err != nil {
 			return nil, err
 		}
@@ -558,12 +495,12 @@ func (c *TushareClient) getDailyBasic(tsCode string) (*DailyBasic, error) {
 }
 
 // GetIncomeStatement 获取利润表数据
-func (c *TushareClient) GetIncomeStatement(stockCode string) ([]map[string]interface{}, error) {
+func (c *TushareClient) GetIncomeStatement(ctx context.Context, stockCode string) ([]map[string]interface{}, error) {
 	tsCode := convertTsCode(stockCode)
 	cacheKey := fmt.Sprintf("income_%s", tsCode)
 
-	if cached, ok := c.getCache(cacheKey); ok {
-		return cached.([]map[string]interface{}), nil
+	if cached, ok := financialTableCache.Get(cacheKey); ok {
+		return cached, nil
 	}
 
 	params := map[string]interface{}{
@@ -573,7 +510,7 @@ func (c *TushareClient) GetIncomeStatement(stockCode string) ([]map[string]inter
 
 	fields := "ts_code,end_date,revenue,operate_profit,total_profit,n_income,basic_eps"
 
-	resp, err := c.request("income", params, fields)
+	resp, err := c.request(ctx, "income", params, fields)
 	if err != nil {
 		return nil, err
 	}
@@ -593,7 +530,7 @@ func (c *TushareClient) GetIncomeStatement(stockCode string) ([]map[string]inter
 		result = append(result, data)
 	}
 
-	c.setCache(cacheKey, result, time.Hour)
+	financialTableCache.Set(cacheKey, result)
 	return result, nil
 }
 
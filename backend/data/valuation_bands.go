@@ -0,0 +1,568 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"stock-ai/backend/models"
+
+	"gorm.io/gorm/clause"
+)
+
+// ==================== 估值分位 ====================
+
+const (
+	valuationHistoryYears   = 10             // 估值历史回看年数
+	valuationKLineCount     = 2500           // 推算估值时拉取的日K数量（约10年）
+	valuationMinSamples     = 20             // 计算分位所需的最少样本数
+	valuationHistoryTTL     = 12 * time.Hour // 本地估值历史的刷新间隔
+	industryValuationTTL    = 1 * time.Hour  // 行业估值中位数缓存时间
+	valuationSourceTushare  = "Tushare"
+	valuationSourceComputed = "Computed"
+)
+
+// valuationWindows 分位回看窗口（年）
+var valuationWindows = []int{3, 5, 10}
+
+// valuationMetrics 估值指标及显示名称
+var valuationMetrics = []struct {
+	key   string
+	label string
+	value func(models.ValuationDaily) float64
+}{
+	{"pe", "市盈率TTM", func(v models.ValuationDaily) float64 { return v.PE }},
+	{"pb", "市净率", func(v models.ValuationDaily) float64 { return v.PB }},
+	{"ps", "市销率TTM", func(v models.ValuationDaily) float64 { return v.PS }},
+}
+
+// StockProfile 东方财富个股概况（总股本与所属行业）
+type StockProfile struct {
+	TotalShares float64
+	Industry    string
+}
+
+// GetStockProfile 获取个股总股本与东方财富行业
+func GetStockProfile(ctx context.Context, code string) (*StockProfile, error) {
+	return coalesce(ctx, "financial.profile", []interface{}{code}, func(ctx context.Context) (*StockProfile, error) {
+		return fetchStockProfile(ctx, code)
+	})
+}
+
+// fetchStockProfile 实际请求，并发的相同调用由 GetStockProfile 合并
+func fetchStockProfile(ctx context.Context, code string) (*StockProfile, error) {
+	secid, err := toEastMoneySecID(code)
+	if err != nil {
+		return nil, err
+	}
+	url := fmt.Sprintf("https://push2.eastmoney.com/api/qt/stock/get?secid=%s&ut=%s&fltt=2&invt=2&fields=f57,f58,f84,f127", secid, eastMoneyUT)
+	body, err := getWithRateLimit(ctx, GetRequestManager(), url, "https://quote.eastmoney.com/", "eastmoney.com")
+	if err != nil {
+		return nil, fmt.Errorf("获取个股概况失败: %v", err)
+	}
+
+	var result struct {
+		Data *struct {
+			TotalShares interface{} `json:"f84"`
+			Industry    string      `json:"f127"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("解析个股概况失败: %v", err)
+	}
+	if result.Data == nil {
+		return nil, fmt.Errorf("个股概况为空")
+	}
+	return &StockProfile{
+		TotalShares: eastMoneyNumber(result.Data.TotalShares),
+		Industry:    strings.TrimSpace(result.Data.Industry),
+	}, nil
+}
+
+// eastMoneyNumber 解析东方财富数值字段，停牌或缺失时为 "-"
+func eastMoneyNumber(v interface{}) float64 {
+	switch n := v.(type) {
+	case float64:
+		return n
+	case string:
+		return parseFloat(n)
+	default:
+		return 0
+	}
+}
+
+// GetValuationHistory 获取每日估值历史（PE-TTM/PB/PS-TTM）
+func (c *TushareClient) GetValuationHistory(ctx context.Context, stockCode string, startDate time.Time) ([]models.ValuationDaily, error) {
+	return coalesce(ctx, "financial.valuation_history", []interface{}{stockCode, startDate.Format("20060102")}, func(ctx context.Context) ([]models.ValuationDaily, error) {
+		return c.fetchValuationHistory(ctx, stockCode, startDate)
+	})
+}
+
+// fetchValuationHistory 实际请求，并发的相同调用由 GetValuationHistory 合并
+func (c *TushareClient) fetchValuationHistory(ctx context.Context, stockCode string, startDate time.Time) ([]models.ValuationDaily, error) {
+	params := map[string]interface{}{
+		"ts_code":    convertTsCode(stockCode),
+		"start_date": startDate.Format("20060102"),
+		"end_date":   time.Now().Format("20060102"),
+	}
+	resp, err := c.request(ctx, "daily_basic", params, "ts_code,trade_date,pe_ttm,pb,ps_ttm")
+	if err != nil {
+		return nil, err
+	}
+
+	fieldMap := makeFieldMap(resp.Data.Fields)
+	history := make([]models.ValuationDaily, 0, len(resp.Data.Items))
+	for _, item := range resp.Data.Items {
+		tradeDate := getStringValue(item, fieldMap, "trade_date")
+		if len(tradeDate) != 8 {
+			continue
+		}
+		history = append(history, models.ValuationDaily{
+			StockCode: stockCode,
+			TradeDate: tradeDate[:4] + "-" + tradeDate[4:6] + "-" + tradeDate[6:],
+			PE:        getFloatValue(item, fieldMap, "pe_ttm"),
+			PB:        getFloatValue(item, fieldMap, "pb"),
+			PS:        getFloatValue(item, fieldMap, "ps_ttm"),
+			Source:    valuationSourceTushare,
+		})
+	}
+	if len(history) == 0 {
+		return nil, fmt.Errorf("无每日指标数据")
+	}
+	return history, nil
+}
+
+// statementAvailableFrom 定期报告的法定披露截止日，之后才可用于估值（避免未来函数）
+func statementAvailableFrom(reportDate string) string {
+	if len(reportDate) != 10 {
+		return reportDate
+	}
+	year := reportDate[:4]
+	switch reportDate[5:] {
+	case "03-31":
+		return year + "-04-30"
+	case "06-30":
+		return year + "-08-31"
+	case "09-30":
+		return year + "-10-31"
+	default:
+		var y int
+		fmt.Sscanf(year, "%d", &y)
+		return fmt.Sprintf("%d-04-30", y+1)
+	}
+}
+
+// ComputeValuationHistory 由前复权日K、当前总股本与历史财报推算每日估值
+// 前复权价格 × 当前总股本 近似还原历史市值，财报按披露截止日生效
+func ComputeValuationHistory(code string, klines []models.KLineData, statements []models.FinancialStatement, totalShares float64) []models.ValuationDaily {
+	if totalShares <= 0 || len(klines) == 0 || len(statements) == 0 {
+		return nil
+	}
+
+	type effective struct {
+		from string
+		stmt models.FinancialStatement
+	}
+	trend := ComputeFinancialTrend(code, statements)
+	metrics := make(map[string]models.FinancialPeriodMetrics, len(trend.Periods))
+	for _, p := range trend.Periods {
+		metrics[p.ReportDate] = p
+	}
+	effectives := make([]effective, 0, len(statements))
+	for _, stmt := range statements {
+		effectives = append(effectives, effective{statementAvailableFrom(stmt.ReportDate), stmt})
+	}
+	sort.SliceStable(effectives, func(i, j int) bool {
+		if effectives[i].from != effectives[j].from {
+			return effectives[i].from < effectives[j].from
+		}
+		return effectives[i].stmt.ReportDate < effectives[j].stmt.ReportDate
+	})
+
+	var netProfitTTM, revenueTTM, equity float64
+	next := 0
+	history := make([]models.ValuationDaily, 0, len(klines))
+	for _, k := range klines {
+		if k.Close <= 0 || len(k.Date) < 10 {
+			continue
+		}
+		date := k.Date[:10]
+		for next < len(effectives) && effectives[next].from <= date {
+			stmt := effectives[next].stmt
+			if m, ok := metrics[stmt.ReportDate]; ok && m.HasTTM {
+				netProfitTTM, revenueTTM = m.NetProfitTTM*yuanPerYi, m.RevenueTTM*yuanPerYi
+			}
+			if stmt.TotalEquity != 0 {
+				equity = stmt.TotalEquity
+			}
+			next++
+		}
+
+		marketCap := k.Close * totalShares
+		day := models.ValuationDaily{StockCode: code, TradeDate: date, Source: valuationSourceComputed}
+		if netProfitTTM > 0 {
+			day.PE = marketCap / netProfitTTM
+		}
+		if equity > 0 {
+			day.PB = marketCap / equity
+		}
+		if revenueTTM > 0 {
+			day.PS = marketCap / revenueTTM
+		}
+		if day.PE > 0 || day.PB > 0 || day.PS > 0 {
+			history = append(history, day)
+		}
+	}
+	return history
+}
+
+// SaveValuationHistory 保存估值历史，同一股票同一交易日覆盖更新
+func SaveValuationHistory(history []models.ValuationDaily) error {
+	if len(history) == 0 {
+		return nil
+	}
+	return GetDB().Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "stock_code"}, {Name: "trade_date"}},
+		UpdateAll: true,
+	}).CreateInBatches(history, 500).Error
+}
+
+// LoadValuationHistory 读取本地估值历史，按交易日升序
+func LoadValuationHistory(code string, since string) ([]models.ValuationDaily, error) {
+	var history []models.ValuationDaily
+	err := GetDB().Where("stock_code = ? AND trade_date >= ?", code, since).Order("trade_date ASC").Find(&history).Error
+	return history, err
+}
+
+// SyncValuationHistory 本地估值历史超过12小时未更新时重新获取
+// 配置了 Tushare 时使用 daily_basic，否则由K线与财报推算
+func SyncValuationHistory(ctx context.Context, code string, profile *StockProfile) error {
+	var latest models.ValuationDaily
+	err := GetDB().Where("stock_code = ?", code).Order("updated_at DESC").First(&latest).Error
+	if err == nil && time.Since(latest.UpdatedAt) < valuationHistoryTTL {
+		return nil
+	}
+
+	start := time.Now().AddDate(-valuationHistoryYears, 0, 0)
+	if GetFinancialClient().isAvailable(providerTushare) {
+		history, err := GetTushareClient().GetValuationHistory(ctx, code, start)
+		if err == nil {
+			// 切换数据源时清理旧口径数据，避免两种口径混合计算分位
+			GetDB().Where("stock_code = ? AND source <> ?", code, valuationSourceTushare).Delete(&models.ValuationDaily{})
+			return SaveValuationHistory(history)
+		}
+		log.Printf("[估值分位] %s Tushare 获取失败，改用K线推算: %v", code, err)
+	}
+
+	if profile == nil || profile.TotalShares <= 0 {
+		return fmt.Errorf("缺少总股本数据，无法推算估值")
+	}
+	if err := SyncFinancialStatements(ctx, code); err != nil {
+		log.Printf("[估值分位] %s 同步财务报表失败: %v", code, err)
+	}
+	statements, err := LoadFinancialStatements(code)
+	if err != nil {
+		return err
+	}
+	if len(statements) == 0 {
+		return fmt.Errorf("暂无财务报表数据")
+	}
+	// 需要前复权价格与当前总股本配合，因此直接使用东方财富前复权日K
+	klines, err := NewStockAPI().getKLineFromEastMoney(ctx, code, "daily", valuationKLineCount)
+	if err != nil {
+		return fmt.Errorf("获取日K失败: %v", err)
+	}
+	history := ComputeValuationHistory(code, klines, statements, profile.TotalShares)
+	if len(history) == 0 {
+		return fmt.Errorf("无法推算估值历史")
+	}
+	GetDB().Where("stock_code = ? AND source <> ?", code, valuationSourceComputed).Delete(&models.ValuationDaily{})
+	return SaveValuationHistory(history)
+}
+
+// ComputeValuationPercentile 计算当前值在窗口样本中的分位，非正值（亏损、净资产为负）不参与
+func ComputeValuationPercentile(current float64, samples []float64) (percentile, minVal, median, maxVal float64) {
+	values := make([]float64, 0, len(samples))
+	for _, v := range samples {
+		if v > 0 {
+			values = append(values, v)
+		}
+	}
+	if len(values) == 0 {
+		return 0, 0, 0, 0
+	}
+	sort.Float64s(values)
+
+	below, equal := 0, 0
+	for _, v := range values {
+		if v < current {
+			below++
+		} else if v == current {
+			equal++
+		}
+	}
+	percentile = (float64(below) + float64(equal)/2) / float64(len(values)) * 100
+	return percentile, values[0], medianOfSorted(values), values[len(values)-1]
+}
+
+// medianOfSorted 已排序切片的中位数
+func medianOfSorted(values []float64) float64 {
+	n := len(values)
+	if n == 0 {
+		return 0
+	}
+	if n%2 == 1 {
+		return values[n/2]
+	}
+	return (values[n/2-1] + values[n/2]) / 2
+}
+
+// BuildValuationBands 根据估值历史计算各窗口分位
+func BuildValuationBands(code string, history []models.ValuationDaily, now time.Time) *models.ValuationBands {
+	bands := &models.ValuationBands{StockCode: code}
+	if len(history) == 0 {
+		return bands
+	}
+	latest := history[len(history)-1]
+	bands.TradeDate = latest.TradeDate
+	bands.StartDate = history[0].TradeDate
+	bands.Source = latest.Source
+
+	for _, metric := range valuationMetrics {
+		band := models.ValuationBand{Metric: metric.key, Label: metric.label, Current: metric.value(latest)}
+		band.Valid = band.Current > 0
+		for _, years := range valuationWindows {
+			since := now.AddDate(-years, 0, 0).Format("2006-01-02")
+			var samples []float64
+			for _, day := range history {
+				if day.TradeDate >= since {
+					samples = append(samples, metric.value(day))
+				}
+			}
+			p := models.ValuationPercentile{Years: years}
+			p.Percentile, p.Min, p.Median, p.Max = ComputeValuationPercentile(band.Current, samples)
+			for _, v := range samples {
+				if v > 0 {
+					p.Samples++
+				}
+			}
+			if p.Samples < valuationMinSamples {
+				continue
+			}
+			// 历史起点与窗口起点相差一个月以内视为完整覆盖
+			p.Complete = bands.StartDate <= now.AddDate(-years, 1, 0).Format("2006-01-02")
+			if !band.Valid {
+				p.Percentile = 0
+			}
+			band.Percentiles = append(band.Percentiles, p)
+		}
+		bands.Bands = append(bands.Bands, band)
+	}
+	return bands
+}
+
+// industryValuation 行业估值中位数
+type industryValuation struct {
+	Count   map[string]int
+	Medians map[string]float64
+}
+
+// GetIndustryValuation 获取东方财富行业板块成分股的估值中位数（PE-TTM/PB/PS-TTM）
+func GetIndustryValuation(ctx context.Context, industry string) (*industryValuation, error) {
+	if industry == "" {
+		return nil, fmt.Errorf("行业为空")
+	}
+	return industryValuationCache.GetOrLoad(ctx, industry, func(ctx context.Context) (*industryValuation, error) {
+		return fetchIndustryValuation(ctx, industry)
+	})
+}
+
+// fetchIndustryValuation 拉取行业板块成分股估值并计算中位数
+func fetchIndustryValuation(ctx context.Context, industry string) (*industryValuation, error) {
+	rm := GetRequestManager()
+	boardURL := "https://push2.eastmoney.com/api/qt/clist/get?pn=1&pz=500&po=1&np=1&fltt=2&invt=2&fid=f3&fs=m:90+t:2&fields=f12,f14"
+	body, err := getWithRateLimit(ctx, rm, boardURL, "https://quote.eastmoney.com/", "eastmoney.com")
+	if err != nil {
+		return nil, fmt.Errorf("获取行业板块失败: %v", err)
+	}
+	var boards struct {
+		Data *struct {
+			Diff []struct {
+				Code string `json:"f12"`
+				Name string `json:"f14"`
+			} `json:"diff"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &boards); err != nil {
+		return nil, fmt.Errorf("解析行业板块失败: %v", err)
+	}
+	boardCode := ""
+	if boards.Data != nil {
+		for _, b := range boards.Data.Diff {
+			if b.Name == industry {
+				boardCode = b.Code
+				break
+			}
+		}
+	}
+	if boardCode == "" {
+		return nil, fmt.Errorf("未找到行业板块: %s", industry)
+	}
+
+	memberURL := fmt.Sprintf("https://push2.eastmoney.com/api/qt/clist/get?pn=1&pz=1000&po=1&np=1&fltt=2&invt=2&fid=f12&fs=b:%s&fields=f12,f23,f115,f130", boardCode)
+	body, err = getWithRateLimit(ctx, rm, memberURL, "https://quote.eastmoney.com/", "eastmoney.com")
+	if err != nil {
+		return nil, fmt.Errorf("获取行业成分股失败: %v", err)
+	}
+	var members struct {
+		Data *struct {
+			Diff []struct {
+				PB interface{} `json:"f23"`
+				PE interface{} `json:"f115"`
+				PS interface{} `json:"f130"`
+			} `json:"diff"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &members); err != nil {
+		return nil, fmt.Errorf("解析行业成分股失败: %v", err)
+	}
+	if members.Data == nil || len(members.Data.Diff) == 0 {
+		return nil, fmt.Errorf("行业成分股为空: %s", industry)
+	}
+
+	values := map[string][]float64{}
+	for _, m := range members.Data.Diff {
+		for key, raw := range map[string]interface{}{"pe": m.PE, "pb": m.PB, "ps": m.PS} {
+			if v := eastMoneyNumber(raw); v > 0 {
+				values[key] = append(values[key], v)
+			}
+		}
+	}
+	result := &industryValuation{Count: map[string]int{}, Medians: map[string]float64{}}
+	for key, list := range values {
+		sort.Float64s(list)
+		result.Count[key] = len(list)
+		result.Medians[key] = medianOfSorted(list)
+	}
+	return result, nil
+}
+
+// GetValuationBands 获取股票PE/PB/PS的3/5/10年历史分位及行业中位数对比
+func GetValuationBands(ctx context.Context, code string) (*models.ValuationBands, error) {
+	profile, err := GetStockProfile(ctx, code)
+	if err != nil {
+		log.Printf("[估值分位] %s 获取个股概况失败: %v", code, err)
+	}
+	if err := SyncValuationHistory(ctx, code, profile); err != nil {
+		log.Printf("[估值分位] %s 同步估值历史失败: %v", code, err)
+	}
+
+	now := time.Now()
+	history, err := LoadValuationHistory(code, now.AddDate(-valuationHistoryYears, 0, 0).Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	if len(history) == 0 {
+		return nil, fmt.Errorf("暂无估值历史数据")
+	}
+
+	bands := BuildValuationBands(code, history, now)
+	if profile == nil || profile.Industry == "" {
+		return bands, nil
+	}
+	bands.Industry = profile.Industry
+	industry, err := GetIndustryValuation(ctx, profile.Industry)
+	if err != nil {
+		log.Printf("[估值分位] %s 获取行业估值失败: %v", code, err)
+		return bands, nil
+	}
+	for i := range bands.Bands {
+		band := &bands.Bands[i]
+		band.IndustryMedian = industry.Medians[band.Metric]
+		band.IndustryCount = industry.Count[band.Metric]
+		if band.Valid && band.IndustryMedian > 0 {
+			band.Premium = (band.Current/band.IndustryMedian - 1) * 100
+		}
+	}
+	return bands, nil
+}
+
+// FormatValuationBandsForAI 格式化估值分位供AI分析使用
+func FormatValuationBandsForAI(bands *models.ValuationBands) string {
+	if bands == nil || len(bands.Bands) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("\n## 估值分位\n")
+	sb.WriteString(fmt.Sprintf("- 数据截至 %s，历史起点 %s", bands.TradeDate, bands.StartDate))
+	if bands.Source == valuationSourceComputed {
+		sb.WriteString("（由前复权股价与历史财报推算）")
+	}
+	if bands.Industry != "" {
+		sb.WriteString(fmt.Sprintf("，所属行业：%s", bands.Industry))
+	}
+	sb.WriteString("\n\n")
+
+	header := "| 指标 | 当前值"
+	divider := "|---|---"
+	for _, years := range valuationWindows {
+		header += fmt.Sprintf(" | %d年分位", years)
+		divider += "|---"
+	}
+	sb.WriteString(header + " | 区间(最低/中位/最高) | 行业中位数 | 相对行业 |\n")
+	sb.WriteString(divider + "|---|---|---|\n")
+
+	partial := false
+	for _, band := range bands.Bands {
+		current := "亏损/无效"
+		if band.Valid {
+			current = fmt.Sprintf("%.2f", band.Current)
+		}
+		byYears := make(map[int]models.ValuationPercentile, len(band.Percentiles))
+		for _, p := range band.Percentiles {
+			byYears[p.Years] = p
+		}
+		row := fmt.Sprintf("| %s | %s", band.Label, current)
+		var widest *models.ValuationPercentile
+		for _, years := range valuationWindows {
+			p, ok := byYears[years]
+			if !ok || !band.Valid {
+				row += " | -"
+				continue
+			}
+			cell := fmt.Sprintf("%.1f%%", p.Percentile)
+			if !p.Complete {
+				cell += "*"
+				partial = true
+			}
+			row += " | " + cell
+			widest = &p
+		}
+		if widest != nil {
+			row += fmt.Sprintf(" | %.2f/%.2f/%.2f", widest.Min, widest.Median, widest.Max)
+		} else {
+			row += " | -"
+		}
+		if band.IndustryMedian > 0 {
+			row += fmt.Sprintf(" | %.2f（%d家）", band.IndustryMedian, band.IndustryCount)
+		} else {
+			row += " | -"
+		}
+		if band.Valid && band.IndustryMedian > 0 {
+			row += fmt.Sprintf(" | %+.1f%% |\n", band.Premium)
+		} else {
+			row += " | - |\n"
+		}
+		sb.WriteString(row)
+	}
+	if partial {
+		sb.WriteString("\n*历史数据未覆盖完整窗口，分位仅基于已有样本\n")
+	}
+	sb.WriteString("\n分位越低说明当前估值相对自身历史越便宜；亏损期间的PE不计入样本。\n\n")
+	return sb.String()
+}
@@ -0,0 +1,134 @@
+package localapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	subscriberBuffer  = 64               // 单个订阅者的事件缓冲，写满后丢弃新事件
+	heartbeatInterval = 15 * time.Second // SSE心跳间隔，避免连接被中间件判定空闲
+)
+
+type event struct {
+	name string
+	data []byte
+}
+
+type subscriber struct {
+	ch      chan event
+	filter  map[string]bool // 为空表示订阅全部事件
+	done    chan struct{}
+	dropped int
+}
+
+// eventHub 事件订阅管理
+type eventHub struct {
+	mu   sync.Mutex
+	subs map[*subscriber]struct{}
+}
+
+func newEventHub() *eventHub {
+	return &eventHub{subs: make(map[*subscriber]struct{})}
+}
+
+func (h *eventHub) subscribe(filter map[string]bool) *subscriber {
+	sub := &subscriber{ch: make(chan event, subscriberBuffer), filter: filter, done: make(chan struct{})}
+	h.mu.Lock()
+	h.subs[sub] = struct{}{}
+	h.mu.Unlock()
+	return sub
+}
+
+func (h *eventHub) unsubscribe(sub *subscriber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.subs[sub]; ok {
+		delete(h.subs, sub)
+		close(sub.done)
+	}
+}
+
+// closeAll 断开所有订阅者，服务停止时调用
+func (h *eventHub) closeAll() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for sub := range h.subs {
+		delete(h.subs, sub)
+		close(sub.done)
+	}
+}
+
+func (h *eventHub) publish(name string, payload interface{}) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.subs) == 0 {
+		return
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		log.Printf("[本地API] 序列化事件 %s 失败: %v", name, err)
+		return
+	}
+	for sub := range h.subs {
+		if len(sub.filter) > 0 && !sub.filter[name] {
+			continue
+		}
+		select {
+		case sub.ch <- event{name: name, data: data}:
+		default:
+			// 客户端读取过慢时丢弃事件，不阻塞行情刷新与提醒检查
+			sub.dropped++
+			if sub.dropped == 1 || sub.dropped%100 == 0 {
+				log.Printf("[本地API] 事件订阅者处理过慢，已丢弃 %d 条事件", sub.dropped)
+			}
+		}
+	}
+}
+
+// handleEvents SSE事件流，?events=a,b 只订阅指定事件
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "不支持流式响应")
+		return
+	}
+
+	var filter map[string]bool
+	if names := SplitCodes(r.URL.Query().Get("events")); len(names) > 0 {
+		filter = make(map[string]bool, len(names))
+		for _, name := range names {
+			filter[name] = true
+		}
+	}
+	sub := s.hub.subscribe(filter)
+	defer s.hub.unsubscribe(sub)
+
+	w.Header().Set("Content-Type", "text/event-stream; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-sub.done:
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+		case e := <-sub.ch:
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.name, e.data)
+		}
+		flusher.Flush()
+	}
+}
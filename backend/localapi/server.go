@@ -0,0 +1,227 @@
+package localapi
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultPort 默认监听端口
+const DefaultPort = 18790
+
+// HandlerFunc 只读接口处理函数，返回值按JSON输出
+type HandlerFunc func(r *http.Request) (interface{}, error)
+
+// BadRequestError 请求参数错误，返回400
+type BadRequestError struct {
+	Message string
+}
+
+func (e *BadRequestError) Error() string {
+	return e.Message
+}
+
+// BadRequest 构造参数错误
+func BadRequest(format string, args ...interface{}) error {
+	return &BadRequestError{Message: fmt.Sprintf(format, args...)}
+}
+
+// Server 本地HTTP接口服务，只监听回环地址，所有请求需携带令牌
+// 接口在 Start 之前通过 Handle 注册；事件通过 Publish 推送给 /api/events 的SSE订阅者
+type Server struct {
+	mu         sync.Mutex
+	routes     map[string]HandlerFunc
+	httpServer *http.Server
+	addr       string
+	port       int
+	token      string
+	hub        *eventHub
+}
+
+// NewServer 创建本地接口服务（未启动）
+func NewServer() *Server {
+	return &Server{routes: make(map[string]HandlerFunc), hub: newEventHub()}
+}
+
+// Handle 注册只读接口，path 如 /api/quotes
+func (s *Server) Handle(path string, handler HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.routes[path] = handler
+}
+
+// Start 在 127.0.0.1:port 上启动服务，port 为0时由系统分配；已按相同端口与令牌运行时不做处理，配置变化时重启
+func (s *Server) Start(port int, token string) error {
+	if strings.TrimSpace(token) == "" {
+		return fmt.Errorf("本地API令牌不能为空")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.httpServer != nil {
+		if s.port == port && s.token == token {
+			return nil
+		}
+		s.shutdownLocked()
+	}
+
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		return fmt.Errorf("本地API监听端口 %d 失败: %w", port, err)
+	}
+
+	mux := http.NewServeMux()
+	for path, handler := range s.routes {
+		mux.HandleFunc(path, s.wrap(handler))
+	}
+	mux.HandleFunc("/api/events", s.handleEvents)
+
+	s.httpServer = &http.Server{Handler: s.authenticate(mux), ReadHeaderTimeout: 10 * time.Second}
+	s.addr = listener.Addr().String()
+	s.port = port
+	s.token = token
+
+	go func(srv *http.Server) {
+		if err := srv.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("[本地API] 服务异常退出: %v", err)
+		}
+	}(s.httpServer)
+	log.Printf("[本地API] 已启动: http://%s", s.addr)
+	return nil
+}
+
+// Stop 停止服务并断开所有事件订阅
+func (s *Server) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.shutdownLocked()
+}
+
+func (s *Server) shutdownLocked() {
+	if s.httpServer == nil {
+		return
+	}
+	// SSE连接不会自行结束，先通知订阅者退出再关闭服务
+	s.hub.closeAll()
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	if err := s.httpServer.Shutdown(ctx); err != nil {
+		s.httpServer.Close()
+	}
+	log.Printf("[本地API] 已停止: http://%s", s.addr)
+	s.httpServer = nil
+	s.addr = ""
+}
+
+// Addr 当前监听地址，未运行时为空
+func (s *Server) Addr() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addr
+}
+
+// Publish 向事件订阅者推送事件，服务未运行或无订阅者时直接丢弃
+func (s *Server) Publish(event string, payload interface{}) {
+	if s == nil {
+		return
+	}
+	s.hub.publish(event, payload)
+}
+
+// authenticate 校验 Host 与令牌；令牌可放在 Authorization: Bearer 头或 token 查询参数（EventSource 无法设置请求头）
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 拒绝非本机域名，防止网页通过DNS重绑定访问本地接口
+		if !isLoopbackHost(r.Host) {
+			writeError(w, http.StatusForbidden, "仅允许通过 127.0.0.1 或 localhost 访问")
+			return
+		}
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if token == "" {
+			token = r.URL.Query().Get("token")
+		}
+		s.mu.Lock()
+		expected := s.token
+		s.mu.Unlock()
+		if token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(expected)) != 1 {
+			writeError(w, http.StatusUnauthorized, "令牌无效")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) wrap(handler HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, "仅支持 GET 请求")
+			return
+		}
+		result, err := handler(r)
+		if err != nil {
+			var badRequest *BadRequestError
+			if errors.As(err, &badRequest) {
+				writeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, result)
+	}
+}
+
+func isLoopbackHost(hostport string) bool {
+	host := hostport
+	if h, _, err := net.SplitHostPort(hostport); err == nil {
+		host = h
+	}
+	host = strings.Trim(host, "[]")
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("[本地API] 输出响应失败: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
+
+// GenerateToken 生成随机访问令牌
+func GenerateToken() string {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(buf)
+}
+
+// SplitCodes 解析逗号分隔的代码参数
+func SplitCodes(raw string) []string {
+	var codes []string
+	for _, code := range strings.Split(raw, ",") {
+		if code = strings.TrimSpace(code); code != "" {
+			codes = append(codes, code)
+		}
+	}
+	return codes
+}
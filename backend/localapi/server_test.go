@@ -0,0 +1,166 @@
+package localapi
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testToken = "secret-token"
+
+func startTestServer(t *testing.T) *Server {
+	t.Helper()
+	s := NewServer()
+	s.Handle("/api/quotes", func(r *http.Request) (interface{}, error) {
+		codes := SplitCodes(r.URL.Query().Get("codes"))
+		if len(codes) == 0 {
+			return nil, BadRequest("缺少参数 codes")
+		}
+		return map[string]interface{}{"codes": codes}, nil
+	})
+	// 端口0由系统分配，避免与本机运行中的应用冲突
+	if err := s.Start(0, testToken); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	t.Cleanup(s.Stop)
+	return s
+}
+
+func TestServerAuthAndErrors(t *testing.T) {
+	s := startTestServer(t)
+	base := "http://" + s.Addr()
+
+	tests := []struct {
+		name   string
+		path   string
+		host   string
+		header string
+		status int
+		body   string
+	}{
+		{"缺少令牌", "/api/quotes?codes=sh600519", "", "", http.StatusUnauthorized, "令牌无效"},
+		{"令牌错误", "/api/quotes?codes=sh600519", "", "Bearer wrong", http.StatusUnauthorized, "令牌无效"},
+		{"请求头令牌", "/api/quotes?codes=sh600519,sz000001", "", "Bearer " + testToken, http.StatusOK, `{"codes":["sh600519","sz000001"]}`},
+		{"查询参数令牌", "/api/quotes?codes=sh600519&token=" + testToken, "", "", http.StatusOK, `{"codes":["sh600519"]}`},
+		{"参数错误", "/api/quotes", "", "Bearer " + testToken, http.StatusBadRequest, "缺少参数 codes"},
+		{"非本机域名", "/api/quotes?codes=sh600519", "evil.example.com", "Bearer " + testToken, http.StatusForbidden, "仅允许"},
+		{"未注册接口", "/api/unknown", "", "Bearer " + testToken, http.StatusNotFound, ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", base+tc.path, nil)
+			if tc.host != "" {
+				req.Host = tc.host
+			}
+			if tc.header != "" {
+				req.Header.Set("Authorization", tc.header)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			var body strings.Builder
+			bufio.NewReader(resp.Body).WriteTo(&body)
+			if resp.StatusCode != tc.status || !strings.Contains(body.String(), tc.body) {
+				t.Fatalf("status = %d, body = %s", resp.StatusCode, body.String())
+			}
+		})
+	}
+}
+
+func TestServerRestartOnConfigChange(t *testing.T) {
+	s := startTestServer(t)
+	addr := s.Addr()
+	if err := s.Start(0, testToken); err != nil || s.Addr() != addr {
+		t.Fatalf("相同配置不应重启: %v %s -> %s", err, addr, s.Addr())
+	}
+	if err := s.Start(0, "new-token"); err != nil {
+		t.Fatal(err)
+	}
+	req, _ := http.NewRequest("GET", "http://"+s.Addr()+"/api/quotes?codes=sh600519", nil)
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("旧令牌应失效, status = %d", resp.StatusCode)
+	}
+	if err := s.Start(0, ""); err == nil {
+		t.Fatal("空令牌应拒绝启动")
+	}
+}
+
+func TestServerEvents(t *testing.T) {
+	s := startTestServer(t)
+
+	req, _ := http.NewRequest("GET", "http://"+s.Addr()+"/api/events?events=stock-alert-triggered&token="+testToken, nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/event-stream") {
+		t.Fatalf("Content-Type = %s", ct)
+	}
+
+	reader := bufio.NewReader(resp.Body)
+	if line, _ := reader.ReadString('\n'); line != ": connected\n" {
+		t.Fatalf("首行 = %q", line)
+	}
+
+	// 订阅在响应头写出前完成，此时发布的事件不会丢失
+	s.Publish("stock-price-updated", map[string]float64{"sh600519": 1688})
+	s.Publish("stock-alert-triggered", map[string]string{"stockCode": "sh600519"})
+
+	lines := make(chan string, 8)
+	go func() {
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				close(lines)
+				return
+			}
+			lines <- line
+		}
+	}()
+
+	var got []string
+	timeout := time.After(3 * time.Second)
+	for len(got) < 2 {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				t.Fatalf("连接提前关闭, got %q", got)
+			}
+			if line != "\n" {
+				got = append(got, strings.TrimSuffix(line, "\n"))
+			}
+		case <-timeout:
+			t.Fatalf("未收到事件, got %q", got)
+		}
+	}
+	if got[0] != "event: stock-alert-triggered" {
+		t.Fatalf("未订阅的事件不应推送: %q", got)
+	}
+	var payload map[string]string
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(got[1], "data: ")), &payload); err != nil || payload["stockCode"] != "sh600519" {
+		t.Fatalf("data = %q, %v", got[1], err)
+	}
+
+	// 停止服务时断开订阅连接
+	s.Stop()
+	select {
+	case _, ok := <-lines:
+		for ok {
+			_, ok = <-lines
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("停止服务后事件流未关闭")
+	}
+}
@@ -31,6 +31,10 @@ type StockPrice struct {
 	Volume        int64   `json:"volume"`
 	Amount        float64 `json:"amount"`
 	UpdateTime    string  `json:"updateTime"`
+	// 行情校验结果：Suspect 为 true 时表示未通过校验，不应用于提醒与AI分析
+	Suspect      bool     `json:"suspect,omitempty"`
+	QualityFlags []string `json:"qualityFlags,omitempty"`
+	Source       string   `json:"source,omitempty"`
 }
 
 // KLineData K线数据
@@ -42,6 +46,8 @@ type KLineData struct {
 	Close  float64 `json:"close"`
 	Volume int64   `json:"volume"`
 	Code   string  `json:"code"`
+	// OpenInterest 持仓量（仅期货K线有值）
+	OpenInterest int64 `json:"openInterest,omitempty"`
 }
 
 // TradeLevelDetail AI给出的买卖区间
@@ -181,18 +187,23 @@ type NewsItem struct {
 	Time       string `json:"time"`
 	Source     string `json:"source"`
 	Importance string `json:"importance"` // high, medium, normal
+	Url        string `json:"url,omitempty"`
 }
 
 // ResearchReport 研报
 type ResearchReport struct {
-	Title       string `json:"title"`
-	StockName   string `json:"stockName"`
-	OrgName     string `json:"orgName"`
-	PublishDate string `json:"publishDate"`
-	Researcher  string `json:"researcher"`
-	Rating      string `json:"rating"`
-	InfoCode    string `json:"infoCode"` // 用于构造详情URL
-	Url         string `json:"url"`      // 详情页URL
+	Title       string  `json:"title"`
+	StockName   string  `json:"stockName"`
+	OrgName     string  `json:"orgName"`
+	PublishDate string  `json:"publishDate"`
+	Researcher  string  `json:"researcher"`
+	Rating      string  `json:"rating"`
+	LastRating  string  `json:"lastRating"`  // 该机构上次评级
+	TargetPrice float64 `json:"targetPrice"` // 目标价，0表示未给出
+	EpsThisYear float64 `json:"epsThisYear"` // 当年EPS预测
+	EpsNextYear float64 `json:"epsNextYear"` // 次年EPS预测
+	InfoCode    string  `json:"infoCode"`    // 用于构造详情URL
+	Url         string  `json:"url"`         // 详情页URL
 }
 
 // StockNotice 公告
@@ -225,11 +236,65 @@ type HotTopic struct {
 	PostCount int64  `json:"postCount"`
 }
 
+// 数据新鲜度
+const (
+	FreshnessLive        = "live"        // 本次实时获取
+	FreshnessCached      = "cached"      // 内存缓存，仍在有效期内
+	FreshnessStale       = "stale"       // 实时获取失败，使用上次成功获取的数据
+	FreshnessPlaceholder = "placeholder" // 无可用数据，返回示例占位数据
+)
+
+// DataMeta 数据来源信息，前端与AI据此区分真实数据与兜底数据
+type DataMeta struct {
+	Source    string `json:"source"`
+	FetchedAt string `json:"fetchedAt"` // 数据实际获取时间
+	Freshness string `json:"freshness"` // live / cached / stale / placeholder
+	Error     string `json:"error,omitempty"`
+}
+
+// MarketIndexResult 市场指数（带数据来源）
+type MarketIndexResult struct {
+	Items []MarketIndex `json:"items"`
+	Meta  DataMeta      `json:"meta"`
+}
+
+// IndustryRankResult 行业排行（带数据来源）
+type IndustryRankResult struct {
+	Items []IndustryRank `json:"items"`
+	Meta  DataMeta       `json:"meta"`
+}
+
+// MoneyFlowResult 资金流向（带数据来源）
+type MoneyFlowResult struct {
+	Items []MoneyFlow `json:"items"`
+	Meta  DataMeta    `json:"meta"`
+}
+
+// GlobalIndexResult 全球指数（带数据来源）
+type GlobalIndexResult struct {
+	Items []GlobalIndex `json:"items"`
+	Meta  DataMeta      `json:"meta"`
+}
+
+// NewsListResult 新闻列表（带数据来源）
+type NewsListResult struct {
+	Items []NewsItem `json:"items"`
+	Meta  DataMeta   `json:"meta"`
+}
+
+// HotTopicResult 热门话题（带数据来源）
+type HotTopicResult struct {
+	Items []HotTopic `json:"items"`
+	Meta  DataMeta   `json:"meta"`
+}
+
 // Config 系统配置
 type Config struct {
 	ID                uint   `gorm:"primarykey" json:"id"`
 	RefreshInterval   int    `json:"refreshInterval"`
 	ProxyUrl          string `json:"proxyUrl"`
+	ProxyUsername     string `json:"proxyUsername"` // 代理认证用户名，代理地址中未带用户名时使用
+	ProxyPassword     string `json:"proxyPassword"`
 	ProxyPoolEnabled  bool   `json:"proxyPoolEnabled"`
 	ProxyProvider     string `json:"proxyProvider"`
 	ProxyApiUrl       string `json:"proxyApiUrl"`
@@ -240,6 +305,7 @@ type Config struct {
 	ProxyPoolProtocol string `json:"proxyPoolProtocol"`
 	ProxyPoolTTL      int    `json:"proxyPoolTTL"`
 	ProxyPoolSize     int    `json:"proxyPoolSize"`
+	ProxyRoutes       string `json:"proxyRoutes"` // 按域名的代理路由，每行“域名 direct|proxy|pool|上游代理地址”
 	Theme             string `json:"theme"`
 	CustomPrimary     string `json:"customPrimary"`
 	AlertPushEnabled  bool   `json:"alertPushEnabled"`
@@ -266,11 +332,24 @@ type Config struct {
 	TushareToken   string `json:"tushareToken"`   // Tushare Pro Token
 	TushareEnabled bool   `json:"tushareEnabled"` // 是否启用Tushare
 	// AKShare配置
-	AkshareEnabled bool `json:"akshareEnabled"` // 是否启用AKShare
+	AkshareEnabled       bool `json:"akshareEnabled"`       // 是否启用AKShare
+	AksharePythonEnabled bool `json:"aksharePythonEnabled"` // 原生接口失败时回退到本地Python AKShare服务（需自行安装akshare）
 	// 数据源优先级
-	DataSourcePriority string `json:"dataSourcePriority"` // tushare, akshare（优先使用哪个）
+	DataSourcePriority string `json:"dataSourcePriority"` // tushare, akshare, eastmoney（优先使用哪个，其余依次补齐缺失字段）
+	// 行情双源交叉校验
+	QuoteValidationEnabled bool `json:"quoteValidationEnabled"`
+	// AI资讯日报
+	DailyDigestEnabled bool   `json:"dailyDigestEnabled"`
+	DailyDigestTime    string `json:"dailyDigestTime"` // 每日生成时间 HH:MM
 	// AI人设
 	ActivePersona string `json:"activePersona"` // 当前激活的AI人设名称
+	// 外汇分析的政策利率参考
+	PolicyRates     string `json:"policyRates"`     // 每行“货币=利率(%)”，为空时自动获取各央行最新利率决议
+	PolicyRatesAsOf string `json:"policyRatesAsOf"` // 政策利率参考的截止月份，如 2026-09
+	// 本地API服务（仅监听127.0.0.1，供脚本读取数据）
+	LocalApiEnabled bool   `json:"localApiEnabled"`
+	LocalApiPort    int    `json:"localApiPort"`
+	LocalApiToken   string `json:"localApiToken"` // 访问令牌，启用时为空则自动生成
 	// 更新策略
 	SkipUpdateVersion string `json:"skipUpdateVersion"`
 }
@@ -309,22 +388,129 @@ type DataSourceStatus struct {
 
 // ProxyStatus 代理池状态
 type ProxyStatus struct {
-	Enabled          bool   `json:"enabled"`
-	PoolEnabled      bool   `json:"poolEnabled"`
-	Provider         string `json:"provider"`
-	ActiveProxies    int    `json:"activeProxies"`
-	ExpiresAt        string `json:"expiresAt"`
-	ExpiresInSeconds int    `json:"expiresInSeconds"`
-	LastFetch        string `json:"lastFetch"`
-	LastError        string `json:"lastError"`
+	Enabled          bool          `json:"enabled"`
+	PoolEnabled      bool          `json:"poolEnabled"`
+	Provider         string        `json:"provider"`
+	ActiveProxies    int           `json:"activeProxies"`
+	HealthyProxies   int           `json:"healthyProxies"`
+	ExpiresAt        string        `json:"expiresAt"`
+	ExpiresInSeconds int           `json:"expiresInSeconds"`
+	LastFetch        string        `json:"lastFetch"`
+	LastError        string        `json:"lastError"`
+	Proxies          []ProxyHealth `json:"proxies"`
+	Routes           []string      `json:"routes"` // 生效的域名路由规则（已隐藏密码）
+}
+
+// ProxyHealth 单个代理的健康状况
+type ProxyHealth struct {
+	Proxy         string   `json:"proxy"`
+	Healthy       bool     `json:"healthy"` // false 表示已被剔除，等待探测恢复
+	Score         float64  `json:"score"`
+	LatencyMs     int64    `json:"latencyMs"`
+	Successes     int      `json:"successes"`
+	Failures      int      `json:"failures"`
+	LastChecked   string   `json:"lastChecked"`
+	LastError     string   `json:"lastError"`
+	StickyDomains []string `json:"stickyDomains"` // 当前粘性绑定到该代理的域名
 }
 
 // DataPipelineStatus 数据通道总览
 type DataPipelineStatus struct {
-	MarketSources []DataSourceStatus     `json:"marketSources"`
-	Financial     map[string]interface{} `json:"financial"`
-	Proxy         ProxyStatus            `json:"proxy"`
-	GeneratedAt   string                 `json:"generatedAt"`
+	MarketSources   []DataSourceStatus     `json:"marketSources"`
+	Financial       map[string]interface{} `json:"financial"`
+	Proxy           ProxyStatus            `json:"proxy"`
+	QuoteValidation QuoteValidationStats   `json:"quoteValidation"`
+	SchemaHealth    SchemaHealthStats      `json:"schemaHealth"`
+	RateLimits      []RateLimitStatus      `json:"rateLimits"`
+	Coalesce        []CoalesceStat         `json:"coalesce"`
+	GeneratedAt     string                 `json:"generatedAt"`
+}
+
+// CoalesceStat 单个接口的并发请求合并统计
+type CoalesceStat struct {
+	Endpoint string `json:"endpoint"`
+	Calls    int64  `json:"calls"`    // 调用总次数
+	Executed int64  `json:"executed"` // 实际发起的请求次数
+	Saved    int64  `json:"saved"`    // 因合并而省下的请求次数
+}
+
+// RateLimitStatus 域名限流状态：自适应速率、冷却与各优先级排队情况
+type RateLimitStatus struct {
+	Domain             string         `json:"domain"`
+	Factor             float64        `json:"factor"`             // 当前速率系数，1 表示按默认配置全速
+	MaxPerMinute       int            `json:"maxPerMinute"`       // 默认每分钟上限
+	EffectivePerMinute int            `json:"effectivePerMinute"` // 按系数折算后的每分钟上限
+	MinIntervalMs      int            `json:"minIntervalMs"`      // 按系数折算后的最小请求间隔
+	RequestsLastMinute int            `json:"requestsLastMinute"`
+	InCooldown         bool           `json:"inCooldown"`
+	CooldownSeconds    int            `json:"cooldownSeconds"` // 剩余冷却秒数
+	Throttles          int64          `json:"throttles"`       // 累计被服务端限流次数
+	LastReason         string         `json:"lastReason"`      // 最近一次限流原因，如 http_429 / anti_bot / empty_body
+	LastThrottleAt     string         `json:"lastThrottleAt"`
+	Queued             map[string]int `json:"queued"` // 各优先级排队中的请求数
+}
+
+// QuoteDiscrepancy 行情校验异常记录
+type QuoteDiscrepancy struct {
+	Code         string  `json:"code"`
+	Reason       string  `json:"reason"`  // 异常类型，如 out_of_limit / stale / price_mismatch
+	Detail       string  `json:"detail"`  // 异常说明
+	Source       string  `json:"source"`  // 出现异常的数据源
+	Compare      string  `json:"compare"` // 参与比对的数据源
+	Price        float64 `json:"price"`
+	ComparePrice float64 `json:"comparePrice"`
+	Action       string  `json:"action"` // flagged 标记 / rejected 剔除
+	Time         string  `json:"time"`
+}
+
+// QuoteValidationStats 行情交叉校验统计
+type QuoteValidationStats struct {
+	Enabled      bool               `json:"enabled"`      // 是否启用双源比对
+	Checked      int64              `json:"checked"`      // 已校验行情条数
+	CrossChecked int64              `json:"crossChecked"` // 双源比对条数
+	Flagged      int64              `json:"flagged"`      // 标记为可疑的条数
+	Rejected     int64              `json:"rejected"`     // 被另一数据源替换的条数
+	Reasons      map[string]int64   `json:"reasons"`      // 按异常类型统计
+	SourceFlags  map[string]int64   `json:"sourceFlags"`  // 按数据源统计异常次数
+	Recent       []QuoteDiscrepancy `json:"recent"`       // 最近的异常记录
+}
+
+// SchemaViolation 一批数据中某个字段的校验违规
+type SchemaViolation struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`    // missing 缺失 / out_of_range 超出合理范围 / all_zero 整批为零值
+	Count   int    `json:"count"`   // 违规记录数
+	Example string `json:"example"` // 示例值
+}
+
+// SchemaDriftEvent 数据源结构变化诊断事件
+type SchemaDriftEvent struct {
+	Source     string            `json:"source"`
+	Dataset    string            `json:"dataset"` // 数据集，如 quote / index / industry / fund
+	Records    int               `json:"records"` // 本批记录数
+	Violations []SchemaViolation `json:"violations"`
+	Broken     bool              `json:"broken"`     // 是否已标记为结构异常并停用
+	SamplePath string            `json:"samplePath"` // 保存的原始响应样本
+	Time       string            `json:"time"`
+}
+
+// SchemaSourceHealth 单个数据源数据集的字段校验统计
+type SchemaSourceHealth struct {
+	Source         string           `json:"source"`
+	Dataset        string           `json:"dataset"`
+	Batches        int64            `json:"batches"`        // 已校验批次
+	BadBatches     int64            `json:"badBatches"`     // 违规批次
+	Violations     map[string]int64 `json:"violations"`     // 按 字段:规则 统计违规记录数
+	ConsecutiveBad int              `json:"consecutiveBad"` // 连续违规批次
+	Broken         bool             `json:"broken"`         // 结构异常，已从降级链中跳过
+	BrokenSince    string           `json:"brokenSince"`
+	LastSamplePath string           `json:"lastSamplePath"`
+}
+
+// SchemaHealthStats 数据源结构校验总览
+type SchemaHealthStats struct {
+	Sources []SchemaSourceHealth `json:"sources"`
+	Recent  []SchemaDriftEvent   `json:"recent"` // 最近的诊断事件
 }
 
 // AIMessage AI聊天消息
@@ -556,6 +742,355 @@ type ForexRate struct {
 	UpdateTime    string  `json:"updateTime"`    // 更新时间
 }
 
+// ForexHistory 外汇日线历史（每个货币对每天一条）
+type ForexHistory struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	Pair      string    `gorm:"uniqueIndex:idx_forex_pair_date;size:10" json:"pair"` // 货币对，如 USDCNY
+	Date      string    `gorm:"uniqueIndex:idx_forex_pair_date;size:10" json:"date"` // 日期 YYYY-MM-DD
+	Open      float64   `json:"open"`
+	High      float64   `json:"high"`
+	Low       float64   `json:"low"`
+	Close     float64   `json:"close"`
+	Source    string    `gorm:"size:20" json:"source"` // 数据来源：snapshot / eastmoney / cross
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// CrossRate 交叉汇率计算结果
+type CrossRate struct {
+	Base  string  `json:"base"`  // 基础货币
+	Quote string  `json:"quote"` // 计价货币
+	Rate  float64 `json:"rate"`  // 1 单位基础货币可兑换的计价货币数量
+	Path  string  `json:"path"`  // 计算路径，如 JPY→USD→HKD
+}
+
+// ==================== 加密货币相关模型 ====================
+
+// CryptoCoin 自选加密货币
+type CryptoCoin struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	Symbol    string         `gorm:"uniqueIndex;size:20" json:"symbol"` // 币种代码，如 BTC
+	Name      string         `gorm:"size:50" json:"name"`               // 中文名称，如 比特币
+	CreatedAt time.Time      `json:"createdAt"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// CryptoPrice 加密货币行情（以USDT计价）
+type CryptoPrice struct {
+	Symbol         string  `json:"symbol"`         // 币种代码，如 BTC
+	Name           string  `json:"name"`           // 中文名称
+	Price          float64 `json:"price"`          // 最新价（USDT）
+	Change         float64 `json:"change"`         // 24h涨跌额
+	ChangePercent  float64 `json:"changePercent"`  // 24h涨跌幅
+	Open24h        float64 `json:"open24h"`        // 24h开盘价
+	High24h        float64 `json:"high24h"`        // 24h最高价
+	Low24h         float64 `json:"low24h"`         // 24h最低价
+	Volume24h      float64 `json:"volume24h"`      // 24h成交量（币）
+	QuoteVolume24h float64 `json:"quoteVolume24h"` // 24h成交额（USDT）
+	UpdateTime     string  `json:"updateTime"`     // 更新时间
+	Source         string  `json:"source"`         // 数据来源交易所
+}
+
+// CryptoAlert 加密货币提醒
+type CryptoAlert struct {
+	ID              uint           `gorm:"primarykey" json:"id"`
+	Symbol          string         `gorm:"index;size:20" json:"symbol"`
+	Name            string         `gorm:"size:50" json:"name"`
+	AlertType       string         `gorm:"size:20" json:"alertType"` // price, change
+	TargetValue     float64        `json:"targetValue"`
+	Condition       string         `gorm:"size:10" json:"condition"` // above/below
+	Enabled         bool           `gorm:"default:true" json:"enabled"`
+	Triggered       bool           `gorm:"default:false" json:"triggered"`
+	TriggeredAt     *time.Time     `json:"triggeredAt"`
+	TriggeredPrice  float64        `json:"triggeredPrice"`
+	TriggeredChange float64        `json:"triggeredChange"`
+	CreatedAt       time.Time      `json:"createdAt"`
+	UpdatedAt       time.Time      `json:"updatedAt"`
+	DeletedAt       gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// ==================== 新闻采集相关模型 ====================
+
+// NewsArticle 采集入库的新闻
+type NewsArticle struct {
+	ID          uint      `gorm:"primarykey" json:"id"`
+	TitleHash   string    `gorm:"uniqueIndex;size:40" json:"-"` // 标准化标题的SHA1，用于去重
+	Url         string    `gorm:"index;size:300" json:"url"`
+	Title       string    `gorm:"size:300" json:"title"`
+	Content     string    `json:"content"`
+	Source      string    `gorm:"size:50" json:"source"`
+	Channel     string    `gorm:"index;size:10" json:"channel"` // cn, us, hk, global
+	Codes       string    `json:"codes"`                        // 提及的股票代码，逗号分隔
+	PublishTime time.Time `gorm:"index" json:"publishTime"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// NewsStockTag 新闻与股票的关联
+type NewsStockTag struct {
+	ID     uint   `gorm:"primarykey"`
+	NewsID uint   `gorm:"index"`
+	Code   string `gorm:"index;size:20"`
+}
+
+// SecurityName 证券名称（全部A股），用于识别新闻中提及的股票
+type SecurityName struct {
+	Code      string    `gorm:"primarykey;size:20" json:"code"`
+	Name      string    `gorm:"index;size:50" json:"name"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// ==================== AI资讯日报相关模型 ====================
+
+// DailyDigest 自选股资讯日报（按股票汇总当日公告、研报与新闻）
+type DailyDigest struct {
+	ID         uint       `gorm:"primarykey" json:"id"`
+	Date       string     `gorm:"uniqueIndex;size:10" json:"date"` // 2006-01-02
+	Content    string     `json:"content"`                         // Markdown 正文
+	StockCount int        `json:"stockCount"`
+	ItemCount  int        `json:"itemCount"`
+	PushedAt   *time.Time `json:"pushedAt"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	UpdatedAt  time.Time  `json:"updatedAt"`
+}
+
+// AISummaryCache AI摘要缓存，按内容哈希复用，避免重复调用模型
+type AISummaryCache struct {
+	ID          uint   `gorm:"primarykey"`
+	ContentHash string `gorm:"uniqueIndex;size:40"`
+	Summary     string
+	Sentiment   string `gorm:"size:10"` // 利好、中性、利空
+	CreatedAt   time.Time
+}
+
+// ==================== 研报评级跟踪相关模型 ====================
+
+// ReportRecord 研报历史记录，按股票累计保存
+type ReportRecord struct {
+	ID            uint      `gorm:"primarykey" json:"id"`
+	InfoCode      string    `gorm:"uniqueIndex;size:40" json:"infoCode"`
+	StockCode     string    `gorm:"index;size:20" json:"stockCode"`
+	StockName     string    `gorm:"size:50" json:"stockName"`
+	Title         string    `gorm:"size:300" json:"title"`
+	OrgName       string    `gorm:"size:50" json:"orgName"`
+	Researcher    string    `gorm:"size:100" json:"researcher"`
+	PublishDate   string    `gorm:"index;size:10" json:"publishDate"`
+	Rating        string    `gorm:"size:20" json:"rating"`     // 原始评级
+	RatingScore   int       `json:"ratingScore"`               // 5分制：5买入 4增持 3中性 2减持 1卖出，0未知
+	LastRating    string    `gorm:"size:20" json:"lastRating"` // 该机构上次评级
+	RatingChange  int       `json:"ratingChange"`              // 1上调 0维持/首次 -1下调
+	TargetPrice   float64   `json:"targetPrice"`
+	EpsThisYear   float64   `json:"epsThisYear"`
+	EpsNextYear   float64   `json:"epsNextYear"`
+	Url           string    `gorm:"size:300" json:"url"`
+	ContentParsed bool      `json:"-"` // 已尝试从正文提取目标价/EPS
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+// RatingBucket 评级分布
+type RatingBucket struct {
+	Label string `json:"label"`
+	Score int    `json:"score"`
+	Count int    `json:"count"`
+}
+
+// ReportConsensus 研报一致预期
+type ReportConsensus struct {
+	StockCode          string         `json:"stockCode"`
+	StockName          string         `json:"stockName"`
+	WindowDays         int            `json:"windowDays"`         // 统计窗口
+	ReportCount        int            `json:"reportCount"`        // 窗口内研报数
+	OrgCount           int            `json:"orgCount"`           // 窗口内覆盖机构数（每家取最新一篇）
+	RatingDistribution []RatingBucket `json:"ratingDistribution"` // 按机构最新评级统计
+	AvgRatingScore     float64        `json:"avgRatingScore"`
+	Upgrades30         int            `json:"upgrades30"`
+	Downgrades30       int            `json:"downgrades30"`
+	Upgrades90         int            `json:"upgrades90"`
+	Downgrades90       int            `json:"downgrades90"`
+	TargetPriceCount   int            `json:"targetPriceCount"`
+	TargetPriceAvg     float64        `json:"targetPriceAvg"`
+	TargetPriceHigh    float64        `json:"targetPriceHigh"`
+	TargetPriceLow     float64        `json:"targetPriceLow"`
+	EpsThisYearAvg     float64        `json:"epsThisYearAvg"`
+	EpsNextYearAvg     float64        `json:"epsNextYearAvg"`
+	CurrentPrice       float64        `json:"currentPrice"`
+	ImpliedUpside      float64        `json:"impliedUpside"` // 平均目标价相对现价的空间（%），无目标价时为0
+	LatestReportDate   string         `json:"latestReportDate"`
+}
+
+// ==================== 财务报表历史相关模型 ====================
+
+// FinancialStatement 定期报告财务数据（单位：元）
+// 利润表与现金流量表为年初至报告期末的累计值，资产负债表为期末值
+type FinancialStatement struct {
+	ID                uint      `gorm:"primarykey" json:"id"`
+	StockCode         string    `gorm:"uniqueIndex:idx_fin_stmt_period;size:20" json:"stockCode"`
+	ReportDate        string    `gorm:"uniqueIndex:idx_fin_stmt_period;size:10" json:"reportDate"` // 报告期 2006-01-02
+	Source            string    `gorm:"size:20" json:"source"`
+	Revenue           float64   `json:"revenue"`           // 营业总收入
+	OperatingCost     float64   `json:"operatingCost"`     // 营业成本
+	OperatingProfit   float64   `json:"operatingProfit"`   // 营业利润
+	NetProfit         float64   `json:"netProfit"`         // 归母净利润
+	DeductedNetProfit float64   `json:"deductedNetProfit"` // 扣非归母净利润
+	TotalAssets       float64   `json:"totalAssets"`
+	TotalLiab         float64   `json:"totalLiab"`
+	TotalEquity       float64   `json:"totalEquity"` // 归母股东权益
+	CurrentAssets     float64   `json:"currentAssets"`
+	CurrentLiab       float64   `json:"currentLiab"`
+	Cash              float64   `json:"cash"` // 货币资金
+	Receivables       float64   `json:"receivables"`
+	Inventory         float64   `json:"inventory"`
+	OperatingCF       float64   `json:"operatingCF"` // 经营活动现金流量净额
+	InvestingCF       float64   `json:"investingCF"`
+	FinancingCF       float64   `json:"financingCF"`
+	Capex             float64   `json:"capex"` // 购建固定资产、无形资产和其他长期资产支付的现金
+	UpdatedAt         time.Time `json:"updatedAt"`
+}
+
+// FinancialPeriodMetrics 单个报告期的衍生指标（金额单位：亿元，比率单位：%）
+type FinancialPeriodMetrics struct {
+	ReportDate string `json:"reportDate"`
+	// 单季数据
+	HasQuarter       bool    `json:"hasQuarter"`
+	QuarterRevenue   float64 `json:"quarterRevenue"`
+	QuarterNetProfit float64 `json:"quarterNetProfit"`
+	HasYoY           bool    `json:"hasYoY"` // 单季同比
+	RevenueYoY       float64 `json:"revenueYoY"`
+	NetProfitYoY     float64 `json:"netProfitYoY"`
+	HasQoQ           bool    `json:"hasQoQ"` // 单季环比
+	RevenueQoQ       float64 `json:"revenueQoQ"`
+	NetProfitQoQ     float64 `json:"netProfitQoQ"`
+	// 滚动十二个月（TTM）
+	HasTTM          bool    `json:"hasTTM"`
+	RevenueTTM      float64 `json:"revenueTTM"`
+	NetProfitTTM    float64 `json:"netProfitTTM"`
+	OperatingCFTTM  float64 `json:"operatingCFTTM"`
+	FreeCashFlowTTM float64 `json:"freeCashFlowTTM"` // 经营现金流 - 资本开支
+	HasTTMYoY       bool    `json:"hasTTMYoY"`
+	RevenueTTMYoY   float64 `json:"revenueTTMYoY"`
+	NetProfitTTMYoY float64 `json:"netProfitTTMYoY"`
+	// 杜邦分解（基于TTM与期初期末平均资产/权益）
+	GrossMargin      float64 `json:"grossMargin"`
+	NetMargin        float64 `json:"netMargin"`
+	AssetTurnover    float64 `json:"assetTurnover"` // 次
+	EquityMultiplier float64 `json:"equityMultiplier"`
+	ROE              float64 `json:"roe"`
+	// 盈利质量
+	AccrualRatio   float64 `json:"accrualRatio"`   // (净利润TTM - 经营现金流TTM) / 平均总资产
+	CashConversion float64 `json:"cashConversion"` // 经营现金流TTM / 净利润TTM
+	DebtRatio      float64 `json:"debtRatio"`
+}
+
+// FinancialTrend 多期财务趋势
+type FinancialTrend struct {
+	StockCode string                   `json:"stockCode"`
+	Source    string                   `json:"source"`
+	Periods   []FinancialPeriodMetrics `json:"periods"` // 按报告期倒序
+}
+
+// ==================== 估值分位相关模型 ====================
+
+// ValuationDaily 每日估值指标（PE-TTM、PB、PS-TTM，亏损或净资产为负时记为0）
+type ValuationDaily struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	StockCode string    `gorm:"uniqueIndex:idx_valuation_day;size:20" json:"stockCode"`
+	TradeDate string    `gorm:"uniqueIndex:idx_valuation_day;size:10" json:"tradeDate"` // 2006-01-02
+	PE        float64   `json:"pe"`
+	PB        float64   `json:"pb"`
+	PS        float64   `json:"ps"`
+	Source    string    `gorm:"size:20" json:"source"` // Tushare 或 Computed（K线与财报推算）
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// ValuationPercentile 某个回看窗口内的估值分位
+type ValuationPercentile struct {
+	Years      int     `json:"years"`
+	Samples    int     `json:"samples"`
+	Percentile float64 `json:"percentile"` // 当前值在窗口内的分位（0-100）
+	Min        float64 `json:"min"`
+	Median     float64 `json:"median"`
+	Max        float64 `json:"max"`
+	Complete   bool    `json:"complete"` // 历史数据是否覆盖整个窗口
+}
+
+// ValuationBand 单个估值指标的历史分位与行业对比
+type ValuationBand struct {
+	Metric         string                `json:"metric"` // pe, pb, ps
+	Label          string                `json:"label"`
+	Current        float64               `json:"current"`
+	Valid          bool                  `json:"valid"` // 当前值是否有意义（如亏损时PE无效）
+	Percentiles    []ValuationPercentile `json:"percentiles"`
+	IndustryMedian float64               `json:"industryMedian"`
+	IndustryCount  int                   `json:"industryCount"`
+	Premium        float64               `json:"premium"` // 相对行业中位数的溢价（%）
+}
+
+// ValuationBands 股票估值分位
+type ValuationBands struct {
+	StockCode string          `json:"stockCode"`
+	Industry  string          `json:"industry"`
+	Source    string          `json:"source"`
+	TradeDate string          `json:"tradeDate"`
+	StartDate string          `json:"startDate"` // 历史数据起始日期
+	Bands     []ValuationBand `json:"bands"`
+}
+
+// ==================== 量化选股相关模型 ====================
+
+// StockFactorSnapshot 单只股票的选股因子快照（财务、估值分位、技术指标），选股时只读本地快照
+type StockFactorSnapshot struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	StockCode string    `gorm:"uniqueIndex;size:20" json:"stockCode"`
+	StockName string    `gorm:"size:50" json:"stockName"`
+	TradeDate string    `gorm:"size:10" json:"tradeDate"` // 技术快照对应的交易日
+	Factors   string    `gorm:"type:text" json:"factors"` // JSON：字段名 -> 数值，缺失字段不写入
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// SavedScreen 保存的选股条件
+type SavedScreen struct {
+	ID          uint       `gorm:"primarykey" json:"id"`
+	Name        string     `gorm:"uniqueIndex;size:100" json:"name"`
+	Expression  string     `gorm:"type:text" json:"expression"`
+	Description string     `gorm:"size:500" json:"description"`
+	LastRunAt   *time.Time `json:"lastRunAt"`
+	LastCount   int        `json:"lastCount"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+}
+
+// ScreenRun 选股条件每日运行结果，同一天重复运行覆盖
+type ScreenRun struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	ScreenID  uint      `gorm:"uniqueIndex:idx_screen_run_day" json:"screenId"`
+	RunDate   string    `gorm:"uniqueIndex:idx_screen_run_day;size:10" json:"runDate"`
+	Codes     string    `gorm:"type:text" json:"codes"` // 逗号分隔的入选代码
+	Count     int       `json:"count"`
+	Universe  int       `json:"universe"` // 参与筛选的股票数
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// ScreenMatch 选股结果中的单只股票
+type ScreenMatch struct {
+	Code     string             `json:"code"`
+	Name     string             `json:"name"`
+	Factors  map[string]float64 `json:"factors"` // 表达式引用到的字段值
+	NewEntry bool               `json:"newEntry"`
+}
+
+// ScreenResult 选股结果及与上一次运行的差异
+type ScreenResult struct {
+	ScreenID    uint          `json:"screenId"`
+	Expression  string        `json:"expression"`
+	Fields      []string      `json:"fields"`
+	RunDate     string        `json:"runDate"`
+	Universe    int           `json:"universe"`
+	Matches     []ScreenMatch `json:"matches"`
+	PrevRunDate string        `json:"prevRunDate"`
+	NewEntrants []string      `json:"newEntrants"` // 相比上一次运行新入选的代码
+	Exits       []string      `json:"exits"`       // 相比上一次运行退出的代码
+}
+
 // ==================== 股票提醒相关模型 ====================
 
 // StockAlert 股票价格提醒
@@ -563,7 +1098,10 @@ type StockAlert struct {
 	ID              uint           `gorm:"primarykey" json:"id"`
 	StockCode       string         `gorm:"index;size:20" json:"stockCode"` // 股票代码
 	StockName       string         `gorm:"size:50" json:"stockName"`       // 股票名称
-	AlertType       string         `gorm:"size:20" json:"alertType"`       // 提醒类型：price（股价提醒）、change（涨跌提醒）
+	AlertType       string         `gorm:"size:20" json:"alertType"`       // 提醒类型：price（股价）、change（涨跌）、notice（公告）、report（研报）、news（新闻关键词）
+	Keywords        string         `gorm:"size:200" json:"keywords"`       // news类型的关键词，逗号分隔
+	LastSeenKey     string         `gorm:"size:64" json:"lastSeenKey"`     // 已处理的最新公告/研报/新闻标识
+	SeenKeys        string         `gorm:"type:text" json:"seenKeys"`      // 最近已处理的公告/研报标识，换行分隔
 	TargetValue     float64        `json:"targetValue"`                    // 目标值（股价或涨跌幅百分比）
 	Condition       string         `gorm:"size:10" json:"condition"`       // 条件：above（高于）、below（低于）
 	Enabled         bool           `gorm:"default:true" json:"enabled"`    // 是否启用
@@ -606,4 +1144,6 @@ type AlertNotification struct {
 	Message       string  `json:"message"`
 	Time          string  `json:"time"`
 	AssetType     string  `json:"assetType"`
+	Title         string  `json:"title,omitempty"` // 公告/研报/新闻标题
+	Url           string  `json:"url,omitempty"`
 }
@@ -1,16 +1,22 @@
 package plugin
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
+	"time"
+
+	"stock-ai/backend/data"
+	"stock-ai/backend/models"
 )
 
 // FetchQuote 从数据源插件获取实时行情
-func (m *Manager) FetchQuote(pluginID string, code string) (*DatasourceResult, error) {
+func (m *Manager) FetchQuote(ctx context.Context, pluginID string, code string) (*DatasourceResult, error) {
 	plugin, err := m.GetPlugin(pluginID)
 	if err != nil {
 		return nil, err
@@ -38,7 +44,7 @@ func (m *Manager) FetchQuote(pluginID string, code string) (*DatasourceResult, e
 	url := config.BaseURL + m.replaceCodeInURL(endpoint, code, config.Params)
 
 	// 发送请求
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("创建请求失败: %w", err)
 	}
@@ -91,7 +97,7 @@ func (m *Manager) FetchQuote(pluginID string, code string) (*DatasourceResult, e
 }
 
 // FetchQuoteFromAll 从所有启用的数据源插件获取行情（返回第一个成功的）
-func (m *Manager) FetchQuoteFromAll(code string) (*DatasourceResult, string, error) {
+func (m *Manager) FetchQuoteFromAll(ctx context.Context, code string) (*DatasourceResult, string, error) {
 	plugins := m.GetPluginsByType(PluginTypeDatasource)
 
 	for _, plugin := range plugins {
@@ -99,7 +105,7 @@ func (m *Manager) FetchQuoteFromAll(code string) (*DatasourceResult, string, err
 			continue
 		}
 
-		result, err := m.FetchQuote(plugin.ID, code)
+		result, err := m.FetchQuote(ctx, plugin.ID, code)
 		if err == nil && result.Price > 0 {
 			return result, plugin.ID, nil
 		}
@@ -109,11 +115,11 @@ func (m *Manager) FetchQuoteFromAll(code string) (*DatasourceResult, string, err
 }
 
 // TestDatasource 测试数据源插件
-func (m *Manager) TestDatasource(pluginID string, code string) (*DatasourceResult, error) {
+func (m *Manager) TestDatasource(ctx context.Context, pluginID string, code string) (*DatasourceResult, error) {
 	if code == "" {
 		code = "000001" // 默认测试平安银行
 	}
-	return m.FetchQuote(pluginID, code)
+	return m.FetchQuote(ctx, pluginID, code)
 }
 
 // GetEnabledDatasourcePlugins 获取所有启用的数据源插件
@@ -133,6 +139,92 @@ func (m *Manager) HasEnabledDatasourcePlugins() bool {
 	return len(m.GetEnabledDatasourcePlugins()) > 0
 }
 
+// DatasourceProvider 将数据源插件适配为统一的行情数据源（data.QuoteProvider）
+type DatasourceProvider struct {
+	manager *Manager
+	plugin  Plugin
+	config  DatasourceConfig
+}
+
+// Name 数据源标识，加 plugin: 前缀避免与内置数据源重名
+func (p *DatasourceProvider) Name() string {
+	return "plugin:" + p.plugin.ID
+}
+
+// Domain 数据源域名
+func (p *DatasourceProvider) Domain() string {
+	if u, err := url.Parse(p.config.BaseURL); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return p.plugin.Name
+}
+
+// Capabilities 插件目前仅支持实时行情
+func (p *DatasourceProvider) Capabilities() data.ProviderCapability {
+	if p.config.Endpoints.Quote == "" {
+		return 0
+	}
+	return data.CapQuote
+}
+
+// FetchQuotes 逐个代码请求插件行情
+func (p *DatasourceProvider) FetchQuotes(ctx context.Context, codes []string) (map[string]*models.StockPrice, error) {
+	result := make(map[string]*models.StockPrice)
+	var lastErr error
+	now := time.Now().Format("2006-01-02 15:04:05")
+	for _, code := range codes {
+		quote, err := p.manager.FetchQuote(ctx, p.plugin.ID, code)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if quote.Price <= 0 {
+			continue
+		}
+		result[code] = &models.StockPrice{
+			Code:          code,
+			Name:          quote.Name,
+			Price:         quote.Price,
+			Change:        quote.Change,
+			ChangePercent: quote.ChangePercent,
+			Volume:        int64(quote.Volume),
+			Amount:        quote.Amount,
+			High:          quote.High,
+			Low:           quote.Low,
+			Open:          quote.Open,
+			PreClose:      quote.PreClose,
+			UpdateTime:    now,
+		}
+	}
+	if len(result) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+	return result, nil
+}
+
+// FetchKLine 插件暂不支持K线
+func (p *DatasourceProvider) FetchKLine(ctx context.Context, code string, period string, count int) ([]models.KLineData, error) {
+	return nil, data.ErrCapabilityNotSupported
+}
+
+// FetchMinute 插件暂不支持分时
+func (p *DatasourceProvider) FetchMinute(ctx context.Context, code string) ([]models.MinuteData, error) {
+	return nil, data.ErrCapabilityNotSupported
+}
+
+// DatasourceProviders 获取所有启用的数据源插件对应的行情数据源
+func (m *Manager) DatasourceProviders() []*DatasourceProvider {
+	var providers []*DatasourceProvider
+	for _, p := range m.GetEnabledDatasourcePlugins() {
+		var config DatasourceConfig
+		if err := json.Unmarshal(p.Config, &config); err != nil {
+			continue
+		}
+		providers = append(providers, &DatasourceProvider{manager: m, plugin: p, config: config})
+	}
+	return providers
+}
+
 // replaceCodeInURL 替换URL中的股票代码和参数
 func (m *Manager) replaceCodeInURL(url string, code string, params map[string]string) string {
 	result := strings.ReplaceAll(url, "{code}", code)
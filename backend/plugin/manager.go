@@ -12,6 +12,8 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"stock-ai/backend/data"
 )
 
 // Manager 插件管理器
@@ -27,9 +29,8 @@ func NewManager(pluginsDir string) *Manager {
 	return &Manager{
 		pluginsDir: pluginsDir,
 		plugins:    make(map[string]*Plugin),
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		// 插件请求（AI、数据源、通知）与数据请求共用代理路由表
+		httpClient: data.NewRoutedClient(30 * time.Second),
 	}
 }
 
@@ -352,6 +353,8 @@ func (m *Manager) replaceVariables(template string, data *NotificationData, para
 	result = strings.ReplaceAll(result, "{triggerTime}", data.TriggerTime)
 	result = strings.ReplaceAll(result, "{change}", fmt.Sprintf("%.2f", data.Change))
 	result = strings.ReplaceAll(result, "{changePercent}", fmt.Sprintf("%.2f%%", data.ChangePercent))
+	result = strings.ReplaceAll(result, "{title}", data.Title)
+	result = strings.ReplaceAll(result, "{link}", data.Link)
 
 	// 替换自定义参数
 	for key, value := range params {
@@ -393,10 +396,19 @@ func (m *Manager) replaceVariablesInJSON(template interface{}, data *Notificatio
 	result = strings.ReplaceAll(result, "{triggerTime}", data.TriggerTime)
 	result = strings.ReplaceAll(result, "{change}", fmt.Sprintf("%.2f", data.Change))
 	result = strings.ReplaceAll(result, "{changePercent}", fmt.Sprintf("%.2f%%", data.ChangePercent))
+	// 标题可能包含引号等字符，需按JSON字符串转义
+	result = strings.ReplaceAll(result, "{title}", jsonEscape(data.Title))
+	result = strings.ReplaceAll(result, "{link}", jsonEscape(data.Link))
 
 	return result
 }
 
+// jsonEscape 转义字符串，使其可直接嵌入JSON字符串字面量
+func jsonEscape(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b[1 : len(b)-1])
+}
+
 // GetNotificationTemplates 获取预置通知模板
 func (m *Manager) GetNotificationTemplates() []NotificationTemplate {
 	return NotificationTemplates
@@ -138,6 +138,8 @@ type NotificationData struct {
 	TriggerTime  string  `json:"triggerTime"`
 	Change       float64 `json:"change"`
 	ChangePercent float64 `json:"changePercent"`
+	Title        string  `json:"title,omitempty"` // 公告/研报/新闻标题
+	Link         string  `json:"link,omitempty"`  // 公告/研报/新闻链接
 }
 
 // PluginConfig 插件配置文件结构
@@ -0,0 +1,508 @@
+package screener
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ==================== 选股表达式 ====================
+//
+// 语法（关键字不区分大小写）：
+//   expr    := or
+//   or      := and { ("or" | "||") and }
+//   and     := not { ("and" | "&&") not }
+//   not     := ("not" | "!") not | compare
+//   compare := sum [ (">" | ">=" | "<" | "<=" | "=" | "==" | "!=") sum ]
+//   sum     := term { ("+" | "-") term }
+//   term    := unary { ("*" | "/") unary }
+//   unary   := "-" unary | number | field | "(" expr ")"
+//
+// 示例：roe > 15 and debt_ratio < 50 and pe_pct_5y < 30 and revenue_growth > 10
+// 字段缺失（如亏损股的PE）时按 NaN 处理，涉及该字段的比较结果为“未知”：
+// not 取反后仍为未知，and/or 按三值逻辑传递，最终未知视为不满足。
+
+// Factors 单只股票的因子值
+type Factors map[string]float64
+
+// Program 编译后的选股表达式
+type Program struct {
+	source string
+	root   node
+	fields []string
+}
+
+// Source 原始表达式
+func (p *Program) Source() string {
+	return p.source
+}
+
+// Fields 表达式引用的字段（去重、按名称排序）
+func (p *Program) Fields() []string {
+	return p.fields
+}
+
+// Match 判断股票是否满足表达式
+func (p *Program) Match(factors Factors) bool {
+	return p.root.eval(factors).truthy()
+}
+
+// Compile 解析并校验选股表达式
+func Compile(source string) (*Program, error) {
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 1 {
+		return nil, fmt.Errorf("表达式为空")
+	}
+	p := &parser{tokens: tokens, fields: map[string]bool{}}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, fmt.Errorf("第%d个字符附近多余的内容: %s", tok.pos+1, tok.text)
+	}
+	if !root.isBool() {
+		return nil, fmt.Errorf("表达式结果必须是条件判断，例如 roe > 15")
+	}
+
+	fields := make([]string, 0, len(p.fields))
+	for name := range p.fields {
+		fields = append(fields, name)
+	}
+	sort.Strings(fields)
+	return &Program{source: strings.TrimSpace(source), root: root, fields: fields}, nil
+}
+
+// ==================== 词法分析 ====================
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokIdent
+	tokOp
+	tokLParen
+	tokRParen
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	num  float64
+	pos  int
+}
+
+// tokenize 将表达式拆分为词法单元，关键字统一为小写
+func tokenize(source string) ([]token, error) {
+	var tokens []token
+	runes := []rune(source)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			text := string(runes[start:i])
+			num, err := strconv.ParseFloat(text, 64)
+			if err != nil {
+				return nil, fmt.Errorf("第%d个字符处的数字无效: %s", start+1, text)
+			}
+			// 支持百分号写法，如 roe > 15%
+			if i < len(runes) && runes[i] == '%' {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokNumber, text: text, num: num, pos: start})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			text := strings.ToLower(string(runes[start:i]))
+			kind := tokIdent
+			switch text {
+			case "and", "or", "not":
+				kind = tokOp
+			}
+			tokens = append(tokens, token{kind: kind, text: text, pos: start})
+		case r == '(':
+			tokens = append(tokens, token{kind: tokLParen, text: "(", pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokRParen, text: ")", pos: i})
+			i++
+		default:
+			start := i
+			op, width := string(r), 1
+			if i+1 < len(runes) {
+				switch two := string(runes[i : i+2]); two {
+				case ">=", "<=", "==", "!=", "&&", "||":
+					op, width = two, 2
+				}
+			}
+			i += width
+			switch op {
+			case "&&":
+				op = "and"
+			case "||":
+				op = "or"
+			case "!":
+				op = "not"
+			case "==":
+				op = "="
+			case ">", "<", ">=", "<=", "=", "!=", "+", "-", "*", "/":
+			default:
+				return nil, fmt.Errorf("第%d个字符处无法识别: %s", start+1, op)
+			}
+			tokens = append(tokens, token{kind: tokOp, text: op, pos: start})
+		}
+	}
+	return append(tokens, token{kind: tokEOF, pos: len(runes)}), nil
+}
+
+// ==================== 语法分析 ====================
+
+type parser struct {
+	tokens []token
+	pos    int
+	fields map[string]bool
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// acceptOp 当前为指定运算符时前进并返回 true
+func (p *parser) acceptOp(ops ...string) (string, bool) {
+	tok := p.peek()
+	if tok.kind != tokOp {
+		return "", false
+	}
+	for _, op := range ops {
+		if tok.text == op {
+			p.pos++
+			return op, true
+		}
+	}
+	return "", false
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.acceptOp("or"); !ok {
+			return left, nil
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		if left, err = newLogical("or", left, right); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.acceptOp("and"); !ok {
+			return left, nil
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		if left, err = newLogical("and", left, right); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *parser) parseNot() (node, error) {
+	if _, ok := p.acceptOp("not"); ok {
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		if !operand.isBool() {
+			return nil, fmt.Errorf("not 之后必须是条件判断")
+		}
+		return notNode{operand}, nil
+	}
+	return p.parseCompare()
+}
+
+func (p *parser) parseCompare() (node, error) {
+	left, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	op, ok := p.acceptOp(">", ">=", "<", "<=", "=", "!=")
+	if !ok {
+		return left, nil
+	}
+	right, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	if left.isBool() || right.isBool() {
+		return nil, fmt.Errorf("比较运算 %s 两侧必须是数值", op)
+	}
+	return compareNode{op, left, right}, nil
+}
+
+func (p *parser) parseSum() (node, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.acceptOp("+", "-")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		if left, err = newArith(op, left, right); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *parser) parseTerm() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.acceptOp("*", "/")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if left, err = newArith(op, left, right); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if _, ok := p.acceptOp("-"); ok {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return newArith("-", numberNode(0), operand)
+	}
+
+	tok := p.next()
+	switch tok.kind {
+	case tokNumber:
+		return numberNode(tok.num), nil
+	case tokIdent:
+		if _, ok := LookupField(tok.text); !ok {
+			return nil, fmt.Errorf("未知字段: %s", tok.text)
+		}
+		p.fields[tok.text] = true
+		return fieldNode(tok.text), nil
+	case tokLParen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokRParen {
+			return nil, fmt.Errorf("第%d个字符处缺少右括号", closing.pos+1)
+		}
+		return inner, nil
+	case tokEOF:
+		return nil, fmt.Errorf("表达式不完整")
+	default:
+		return nil, fmt.Errorf("第%d个字符附近语法错误: %s", tok.pos+1, tok.text)
+	}
+}
+
+// ==================== 求值 ====================
+
+// value 求值结果：数值或布尔，布尔值可能因字段缺失而未知
+type value struct {
+	num     float64
+	b       bool
+	isBool  bool
+	unknown bool
+}
+
+func (v value) truthy() bool {
+	return v.isBool && !v.unknown && v.b
+}
+
+// falsy 条件确定不成立（未知不算）
+func (v value) falsy() bool {
+	return v.isBool && !v.unknown && !v.b
+}
+
+var unknownValue = value{isBool: true, unknown: true}
+
+type node interface {
+	eval(Factors) value
+	isBool() bool
+}
+
+type numberNode float64
+
+func (n numberNode) eval(Factors) value { return value{num: float64(n)} }
+func (n numberNode) isBool() bool       { return false }
+
+type fieldNode string
+
+func (n fieldNode) eval(f Factors) value {
+	v, ok := f[string(n)]
+	if !ok {
+		return value{num: math.NaN()}
+	}
+	return value{num: v}
+}
+func (n fieldNode) isBool() bool { return false }
+
+type arithNode struct {
+	op          string
+	left, right node
+}
+
+func newArith(op string, left, right node) (node, error) {
+	if left.isBool() || right.isBool() {
+		return nil, fmt.Errorf("算术运算 %s 两侧必须是数值", op)
+	}
+	return arithNode{op, left, right}, nil
+}
+
+func (n arithNode) eval(f Factors) value {
+	l, r := n.left.eval(f).num, n.right.eval(f).num
+	switch n.op {
+	case "+":
+		return value{num: l + r}
+	case "-":
+		return value{num: l - r}
+	case "*":
+		return value{num: l * r}
+	default:
+		if r == 0 {
+			return value{num: math.NaN()}
+		}
+		return value{num: l / r}
+	}
+}
+func (n arithNode) isBool() bool { return false }
+
+type compareNode struct {
+	op          string
+	left, right node
+}
+
+func (n compareNode) eval(f Factors) value {
+	l, r := n.left.eval(f).num, n.right.eval(f).num
+	if math.IsNaN(l) || math.IsNaN(r) {
+		return unknownValue
+	}
+	var b bool
+	switch n.op {
+	case ">":
+		b = l > r
+	case ">=":
+		b = l >= r
+	case "<":
+		b = l < r
+	case "<=":
+		b = l <= r
+	case "=":
+		b = math.Abs(l-r) < 1e-9
+	case "!=":
+		b = math.Abs(l-r) >= 1e-9
+	}
+	return value{b: b, isBool: true}
+}
+func (n compareNode) isBool() bool { return true }
+
+type logicalNode struct {
+	op          string
+	left, right node
+}
+
+func newLogical(op string, left, right node) (node, error) {
+	if !left.isBool() || !right.isBool() {
+		return nil, fmt.Errorf("%s 两侧必须是条件判断", op)
+	}
+	return logicalNode{op, left, right}, nil
+}
+
+// eval 三值逻辑：and 任一侧确定为假即为假，or 任一侧确定为真即为真，其余含未知时结果未知
+func (n logicalNode) eval(f Factors) value {
+	l := n.left.eval(f)
+	if n.op == "and" {
+		if l.falsy() {
+			return value{isBool: true}
+		}
+		r := n.right.eval(f)
+		if r.falsy() {
+			return value{isBool: true}
+		}
+		if l.unknown || r.unknown {
+			return unknownValue
+		}
+		return value{b: true, isBool: true}
+	}
+	if l.truthy() {
+		return value{b: true, isBool: true}
+	}
+	r := n.right.eval(f)
+	if r.truthy() {
+		return value{b: true, isBool: true}
+	}
+	if l.unknown || r.unknown {
+		return unknownValue
+	}
+	return value{isBool: true}
+}
+func (n logicalNode) isBool() bool { return true }
+
+type notNode struct {
+	operand node
+}
+
+func (n notNode) eval(f Factors) value {
+	v := n.operand.eval(f)
+	if v.unknown {
+		return v
+	}
+	return value{b: !v.b, isBool: true}
+}
+func (n notNode) isBool() bool { return true }
@@ -0,0 +1,93 @@
+package screener
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompileAndMatch(t *testing.T) {
+	stock := Factors{"roe": 20, "debt_ratio": 40, "pe": 12, "revenue_growth": 8, "gross_margin": 35, "net_margin": 10}
+	// 亏损股没有 pe
+	loss := Factors{"roe": -5, "debt_ratio": 70, "revenue_growth": -20}
+
+	tests := []struct {
+		name   string
+		expr   string
+		factor Factors
+		want   bool
+	}{
+		{"and 优先于 or", "roe > 30 and debt_ratio < 50 or pe < 15", stock, true},
+		{"括号改变优先级", "roe > 30 and (debt_ratio < 50 or pe < 15)", stock, false},
+		{"乘除优先于加减", "gross_margin - net_margin * 2 = 15", stock, true},
+		{"一元负号", "-roe < -10", stock, true},
+		{"百分号写法", "roe > 15% and debt_ratio < 50%", stock, true},
+		{"关键字不区分大小写", "ROE > 15 AND Debt_Ratio < 50", stock, true},
+		{"符号运算符", "roe > 15 && !(pe > 20) || revenue_growth > 50", stock, true},
+		{"not 嵌套", "not not roe > 15", stock, true},
+		{"not 优先于 and", "not roe > 30 and pe < 15", stock, true},
+		{"字段缺失时比较为假", "pe < 15", loss, false},
+		{"字段缺失时不等比较也为假", "pe != 15", loss, false},
+		{"字段缺失时 not 取反仍不满足", "not pe > 15", loss, false},
+		{"字段缺失时 not 括号内的 or 仍不满足", "not (pe > 15 or roe > 0)", loss, false},
+		{"字段缺失时 and 另一侧为假则整体为假", "not (pe > 15 and roe > 0)", loss, true},
+		{"字段缺失时 not 嵌套仍不满足", "not not pe > 15", loss, false},
+		{"字段缺失不影响 or 的另一侧", "pe < 15 or debt_ratio > 60", loss, true},
+		{"缺失字段参与算术仍为缺失", "pe * 2 < 100", loss, false},
+		{"除以零视为缺失", "roe / (pe - 12) > 0", stock, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			prog, err := Compile(tc.expr)
+			if err != nil {
+				t.Fatalf("Compile(%q): %v", tc.expr, err)
+			}
+			if got := prog.Match(tc.factor); got != tc.want {
+				t.Fatalf("Match(%q) = %v, want %v", tc.expr, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCompileFields(t *testing.T) {
+	prog, err := Compile("  roe > 15 and (pe < 20 or roe > 30)  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(prog.Fields(), ","); got != "pe,roe" {
+		t.Fatalf("Fields = %s", got)
+	}
+	if prog.Source() != "roe > 15 and (pe < 20 or roe > 30)" {
+		t.Fatalf("Source = %q", prog.Source())
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"", "表达式为空"},
+		{"   ", "表达式为空"},
+		{"roe >", "表达式不完整"},
+		{"foo > 1", "未知字段: foo"},
+		{"roe > 15 $", "第10个字符处无法识别: $"},
+		{"roe > 1.2.3", "第7个字符处的数字无效: 1.2.3"},
+		{"(roe > 15", "第10个字符处缺少右括号"},
+		{"roe > 15)", "第9个字符附近多余的内容: )"},
+		{"roe > 15 pe < 20", "第10个字符附近多余的内容: pe"},
+		{"roe > > 15", "第7个字符附近语法错误: >"},
+		{"roe + 15", "表达式结果必须是条件判断，例如 roe > 15"},
+		{"not roe", "not 之后必须是条件判断"},
+		{"roe and pe > 1", "and 两侧必须是条件判断"},
+		{"(roe > 1) > 2", "比较运算 > 两侧必须是数值"},
+		{"(roe > 1) + 2 > 0", "算术运算 + 两侧必须是数值"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.expr, func(t *testing.T) {
+			_, err := Compile(tc.expr)
+			if err == nil || err.Error() != tc.want {
+				t.Fatalf("Compile(%q) err = %v, want %q", tc.expr, err, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package screener
+
+// ==================== 可用字段 ====================
+
+// 字段分类
+const (
+	CategoryFundamental = "fundamental" // 财务指标（最新报告期TTM口径）
+	CategoryValuation   = "valuation"   // 估值与历史分位
+	CategoryTechnical   = "technical"   // 日线技术快照
+)
+
+// Field 选股表达式可用的字段
+type Field struct {
+	Name     string `json:"name"`
+	Label    string `json:"label"`
+	Category string `json:"category"`
+	Unit     string `json:"unit"`
+}
+
+// fields 字段目录，顺序即界面展示顺序
+var fields = []Field{
+	// 财务指标
+	{"roe", "净资产收益率TTM", CategoryFundamental, "%"},
+	{"gross_margin", "毛利率TTM", CategoryFundamental, "%"},
+	{"net_margin", "净利率TTM", CategoryFundamental, "%"},
+	{"debt_ratio", "资产负债率", CategoryFundamental, "%"},
+	{"current_ratio", "流动比率", CategoryFundamental, ""},
+	{"revenue", "营业收入TTM", CategoryFundamental, "亿元"},
+	{"net_profit", "归母净利润TTM", CategoryFundamental, "亿元"},
+	{"revenue_growth", "营收同比（TTM）", CategoryFundamental, "%"},
+	{"profit_growth", "净利润同比（TTM）", CategoryFundamental, "%"},
+	{"quarter_revenue_growth", "单季营收同比", CategoryFundamental, "%"},
+	{"quarter_profit_growth", "单季净利润同比", CategoryFundamental, "%"},
+	{"operating_cf", "经营现金流TTM", CategoryFundamental, "亿元"},
+	{"free_cash_flow", "自由现金流TTM", CategoryFundamental, "亿元"},
+	{"cash_conversion", "现金含量（经营现金流/净利润）", CategoryFundamental, "%"},
+	{"accrual_ratio", "应计比率", CategoryFundamental, "%"},
+	{"asset_turnover", "资产周转率", CategoryFundamental, "次"},
+	{"equity_multiplier", "权益乘数", CategoryFundamental, ""},
+	// 估值
+	{"pe", "市盈率TTM", CategoryValuation, ""},
+	{"pb", "市净率", CategoryValuation, ""},
+	{"ps", "市销率TTM", CategoryValuation, ""},
+	{"pe_pct_3y", "PE近3年分位", CategoryValuation, "%"},
+	{"pe_pct_5y", "PE近5年分位", CategoryValuation, "%"},
+	{"pe_pct_10y", "PE近10年分位", CategoryValuation, "%"},
+	{"pb_pct_3y", "PB近3年分位", CategoryValuation, "%"},
+	{"pb_pct_5y", "PB近5年分位", CategoryValuation, "%"},
+	{"pb_pct_10y", "PB近10年分位", CategoryValuation, "%"},
+	{"ps_pct_3y", "PS近3年分位", CategoryValuation, "%"},
+	{"ps_pct_5y", "PS近5年分位", CategoryValuation, "%"},
+	{"ps_pct_10y", "PS近10年分位", CategoryValuation, "%"},
+	{"pe_vs_industry", "PE相对行业中位数溢价", CategoryValuation, "%"},
+	{"pb_vs_industry", "PB相对行业中位数溢价", CategoryValuation, "%"},
+	// 技术快照
+	{"close", "收盘价", CategoryTechnical, "元"},
+	{"change_pct", "日涨跌幅", CategoryTechnical, "%"},
+	{"ma5", "5日均线", CategoryTechnical, "元"},
+	{"ma10", "10日均线", CategoryTechnical, "元"},
+	{"ma20", "20日均线", CategoryTechnical, "元"},
+	{"ma60", "60日均线", CategoryTechnical, "元"},
+	{"rsi", "RSI14", CategoryTechnical, ""},
+	{"macd_dif", "MACD DIF", CategoryTechnical, ""},
+	{"macd_dea", "MACD DEA", CategoryTechnical, ""},
+	{"macd_hist", "MACD柱", CategoryTechnical, ""},
+	{"kdj_k", "KDJ K值", CategoryTechnical, ""},
+	{"kdj_d", "KDJ D值", CategoryTechnical, ""},
+	{"kdj_j", "KDJ J值", CategoryTechnical, ""},
+	{"adx", "ADX", CategoryTechnical, ""},
+	{"high_30", "近30日最高", CategoryTechnical, "元"},
+	{"low_30", "近30日最低", CategoryTechnical, "元"},
+	{"return_20d", "近20日涨跌幅", CategoryTechnical, "%"},
+	{"return_60d", "近60日涨跌幅", CategoryTechnical, "%"},
+}
+
+var fieldIndex = func() map[string]Field {
+	index := make(map[string]Field, len(fields))
+	for _, f := range fields {
+		index[f.Name] = f
+	}
+	return index
+}()
+
+// AllFields 返回全部可用字段
+func AllFields() []Field {
+	return append([]Field(nil), fields...)
+}
+
+// LookupField 按名称查找字段
+func LookupField(name string) (Field, bool) {
+	f, ok := fieldIndex[name]
+	return f, ok
+}
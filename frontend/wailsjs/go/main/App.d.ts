@@ -5,6 +5,9 @@ import {plugin} from '../models';
 import {prompt} from '../models';
 import {main} from '../models';
 import {data} from '../models';
+import {screener} from '../models';
+
+export function AIAnalyzeAssetStream(arg1:string,arg2:string,arg3:string,arg4:string):Promise<void>;
 
 export function AIAnalyzeByTypeStream(arg1:string,arg2:string,arg3:string):Promise<void>;
 
@@ -30,6 +33,10 @@ export function AIRecommendStream():Promise<void>;
 
 export function AISummarizeContentStream(arg1:string,arg2:string,arg3:string,arg4:string,arg5:string,arg6:string,arg7:string):Promise<void>;
 
+export function AddCrypto(arg1:string,arg2:string):Promise<void>;
+
+export function AddCryptoAlert(arg1:models.CryptoAlert):Promise<void>;
+
 export function AddFund(arg1:string):Promise<void>;
 
 export function AddFundAlert(arg1:models.FundAlert):Promise<void>;
@@ -50,10 +57,14 @@ export function AddStockAlert(arg1:models.StockAlert):Promise<void>;
 
 export function AddUSStock(arg1:string,arg2:string,arg3:string,arg4:string):Promise<void>;
 
+export function CheckCryptoAlerts():Promise<Array<models.AlertNotification>>;
+
 export function CheckFundAlerts():Promise<Array<models.AlertNotification>>;
 
 export function CheckStockAlerts():Promise<Array<models.AlertNotification>>;
 
+export function CheckStockEventAlerts():Promise<Array<models.AlertNotification>>;
+
 export function CheckUpdate():Promise<models.UpdateInfo>;
 
 export function CleanupAllCache():Promise<Record<string, number>>;
@@ -70,6 +81,8 @@ export function ClearCache():Promise<void>;
 
 export function ClearOldAIData():Promise<number>;
 
+export function ConvertCurrency(arg1:number,arg2:string,arg3:string):Promise<number>;
+
 export function CreateAIPluginFromTemplate(arg1:string,arg2:string,arg3:string,arg4:string,arg5:string):Promise<void>;
 
 export function CreatePluginFromTemplate(arg1:string,arg2:string,arg3:Record<string, string>):Promise<void>;
@@ -80,6 +93,8 @@ export function DeleteAIAnalysisResult(arg1:number):Promise<void>;
 
 export function DeleteAIChatSession(arg1:string):Promise<void>;
 
+export function DeleteCryptoAlert(arg1:number):Promise<void>;
+
 export function DeleteFundAlert(arg1:number):Promise<void>;
 
 export function DeleteFundPosition(arg1:number):Promise<void>;
@@ -90,6 +105,8 @@ export function DeletePosition(arg1:number):Promise<void>;
 
 export function DeletePrompt(arg1:string,arg2:string):Promise<void>;
 
+export function DeleteScreen(arg1:number):Promise<void>;
+
 export function DeleteStockAlert(arg1:number):Promise<void>;
 
 export function DownloadAndInstallUpdate():Promise<models.UpdateInfo>;
@@ -100,6 +117,8 @@ export function ExecuteReviewPrompt(arg1:string):Promise<prompt.ReviewResult>;
 
 export function ExecuteScreenerPrompt(arg1:string):Promise<prompt.ScreenerResult>;
 
+export function ExecuteScreenerPromptOnScreen(arg1:string,arg2:number):Promise<prompt.ScreenerResult>;
+
 export function ExportAIAnalysisHistory(arg1:string):Promise<string>;
 
 export function ExportAIChatHistory(arg1:string,arg2:string):Promise<string>;
@@ -114,6 +133,8 @@ export function FetchQuoteFromPlugin(arg1:string,arg2:string):Promise<plugin.Dat
 
 export function FrontendTrace(arg1:string):Promise<void>;
 
+export function GenerateDailyDigest(arg1:boolean):Promise<models.DailyDigest>;
+
 export function GetAIAnalysisHistory():Promise<Array<models.AIAnalysisResult>>;
 
 export function GetAIChatHistory():Promise<Array<main.AIChatSession>>;
@@ -128,12 +149,26 @@ export function GetActivePersona():Promise<string>;
 
 export function GetAllAlerts():Promise<Array<models.StockAlert>>;
 
+export function GetAssetAnalysisCache(arg1:string,arg2:string,arg3:string,arg4:string):Promise<models.ProAnalysisCache>;
+
 export function GetCachedGlobalMarketData(arg1:string):Promise<main.CachedGlobalMarketData>;
 
 export function GetCachedMarketData():Promise<main.CachedMarketData>;
 
 export function GetConfig():Promise<models.Config>;
 
+export function GetCrossRate(arg1:string,arg2:string):Promise<models.CrossRate>;
+
+export function GetCryptoAlerts(arg1:string):Promise<Array<models.CryptoAlert>>;
+
+export function GetCryptoKLine(arg1:string,arg2:string,arg3:number):Promise<Array<models.KLineData>>;
+
+export function GetCryptoList():Promise<Array<models.CryptoCoin>>;
+
+export function GetCryptoQuotes(arg1:Array<string>):Promise<Array<models.CryptoPrice>>;
+
+export function GetDailyDigests(arg1:number):Promise<Array<models.DailyDigest>>;
+
 export function GetDataCleanupInfo():Promise<main.DataCleanupInfo>;
 
 export function GetDataPipelineStatus():Promise<models.DataPipelineStatus>;
@@ -144,6 +179,10 @@ export function GetEnabledAIPlugins():Promise<Array<plugin.Plugin>>;
 
 export function GetEnabledDatasourcePlugins():Promise<Array<plugin.Plugin>>;
 
+export function GetFinancialTrend(arg1:string):Promise<models.FinancialTrend>;
+
+export function GetForexHistory(arg1:string,arg2:number):Promise<Array<models.ForexHistory>>;
+
 export function GetForexRates():Promise<Array<models.ForexRate>>;
 
 export function GetFundAlerts(arg1:string):Promise<Array<models.FundAlert>>;
@@ -162,37 +201,41 @@ export function GetFuturesPrice(arg1:Array<string>):Promise<Record<string, model
 
 export function GetFuturesProducts():Promise<Array<models.FuturesProduct>>;
 
-export function GetGlobalIndices():Promise<Array<models.GlobalIndex>>;
+export function GetGlobalIndices():Promise<models.GlobalIndexResult>;
 
 export function GetGlobalIndicesList():Promise<Array<models.GlobalIndex>>;
 
 export function GetGlobalMarketSentiment(arg1:string):Promise<data.MarketSentiment>;
 
-export function GetGlobalNews(arg1:string):Promise<Array<models.NewsItem>>;
+export function GetGlobalNews(arg1:string):Promise<models.NewsListResult>;
 
 export function GetHKStockList():Promise<Array<models.HKStock>>;
 
 export function GetHKStockPrice(arg1:Array<string>):Promise<Record<string, models.HKStockPrice>>;
 
-export function GetHotTopics():Promise<Array<models.HotTopic>>;
+export function GetHotTopics():Promise<models.HotTopicResult>;
 
-export function GetIndustryRank():Promise<Array<models.IndustryRank>>;
+export function GetIndustryRank():Promise<models.IndustryRankResult>;
 
 export function GetKLineData(arg1:string,arg2:string,arg3:number):Promise<Array<models.KLineData>>;
 
+export function GetLocalAPIAddress():Promise<string>;
+
 export function GetLongTigerRank():Promise<Array<models.LongTigerItem>>;
 
 export function GetMainContracts():Promise<Array<models.FuturesPrice>>;
 
+export function GetMainCryptoCoins():Promise<Array<models.CryptoPrice>>;
+
 export function GetMainForexPairs():Promise<Array<models.ForexRate>>;
 
-export function GetMarketIndex():Promise<Array<models.MarketIndex>>;
+export function GetMarketIndex():Promise<models.MarketIndexResult>;
 
 export function GetMinuteData(arg1:string):Promise<Array<models.MinuteData>>;
 
-export function GetMoneyFlow():Promise<Array<models.MoneyFlow>>;
+export function GetMoneyFlow():Promise<models.MoneyFlowResult>;
 
-export function GetNewsList():Promise<Array<models.NewsItem>>;
+export function GetNewsList():Promise<models.NewsListResult>;
 
 export function GetNotificationTemplates():Promise<Array<plugin.NotificationTemplate>>;
 
@@ -208,6 +251,8 @@ export function GetPopularHKStocks():Promise<Array<models.HKStock>>;
 
 export function GetPopularUSStocks():Promise<Array<models.USStock>>;
 
+export function GetPortfolioValuationCNY():Promise<main.PortfolioValuation>;
+
 export function GetPositionByStock(arg1:string):Promise<models.Position>;
 
 export function GetPositionHistory():Promise<Array<models.Position>>;
@@ -222,8 +267,16 @@ export function GetPromptTypes():Promise<Array<any>>;
 
 export function GetPromptsDir():Promise<string>;
 
+export function GetReportConsensus(arg1:string):Promise<models.ReportConsensus>;
+
+export function GetReportHistory(arg1:string,arg2:number):Promise<Array<models.ReportRecord>>;
+
 export function GetResearchReports(arg1:string):Promise<Array<models.ResearchReport>>;
 
+export function GetScreenRuns(arg1:number,arg2:number):Promise<Array<models.ScreenRun>>;
+
+export function GetScreenerFields():Promise<Array<screener.Field>>;
+
 export function GetStockAlerts(arg1:string):Promise<Array<models.StockAlert>>;
 
 export function GetStockList():Promise<Array<models.Stock>>;
@@ -232,12 +285,16 @@ export function GetStockNotices(arg1:string):Promise<Array<models.StockNotice>>;
 
 export function GetStockPrice(arg1:Array<string>):Promise<Record<string, models.StockPrice>>;
 
+export function GetTopCryptoQuotes(arg1:number):Promise<Array<models.CryptoPrice>>;
+
 export function GetTradingTimeInfo():Promise<main.TradingTimeInfo>;
 
 export function GetUSStockList():Promise<Array<models.USStock>>;
 
 export function GetUSStockPrice(arg1:Array<string>):Promise<Record<string, models.USStockPrice>>;
 
+export function GetValuationBands(arg1:string):Promise<models.ValuationBands>;
+
 export function GetVersion():Promise<models.VersionInfo>;
 
 export function HasEnabledAIPlugins():Promise<boolean>;
@@ -256,6 +313,8 @@ export function ListAllPrompts():Promise<Record<string, Array<prompt.PromptInfo>
 
 export function ListPrompts(arg1:string):Promise<Array<prompt.PromptInfo>>;
 
+export function ListScreens():Promise<Array<models.SavedScreen>>;
+
 export function MarkFirstLoadComplete():Promise<void>;
 
 export function OpenPluginsDir():Promise<void>;
@@ -266,8 +325,14 @@ export function OpenURL(arg1:string):Promise<void>;
 
 export function PrefetchTradeLevelData(arg1:string):Promise<void>;
 
+export function PushDailyDigest(arg1:string):Promise<void>;
+
 export function RefreshPlugins():Promise<number|Array<string>>;
 
+export function RefreshScreenerData(arg1:Array<string>):Promise<number>;
+
+export function RemoveCrypto(arg1:string):Promise<void>;
+
 export function RemoveFund(arg1:string):Promise<void>;
 
 export function RemoveFutures(arg1:string):Promise<void>;
@@ -280,20 +345,30 @@ export function RemoveUSStock(arg1:string):Promise<void>;
 
 export function RenamePrompt(arg1:string,arg2:string,arg3:string):Promise<void>;
 
+export function ResetCryptoAlert(arg1:number):Promise<void>;
+
 export function ResetFundAlert(arg1:number):Promise<void>;
 
 export function ResetStockAlert(arg1:number):Promise<void>;
 
+export function RunSavedScreen(arg1:number):Promise<models.ScreenResult>;
+
+export function RunScreen(arg1:string):Promise<models.ScreenResult>;
+
 export function SaveAIAnalysisResult(arg1:string,arg2:string,arg3:string,arg4:string):Promise<void>;
 
 export function SaveAIChatMessage(arg1:string,arg2:string,arg3:string):Promise<void>;
 
 export function SaveConfig(arg1:models.Config):Promise<void>;
 
+export function SaveScreen(arg1:number,arg2:string,arg3:string,arg4:string):Promise<models.SavedScreen>;
+
 export function SearchFutures(arg1:string):Promise<Array<models.Futures>>;
 
 export function SearchHKStock(arg1:string):Promise<Array<models.HKStock>>;
 
+export function SearchNews(arg1:string,arg2:Array<string>,arg3:string,arg4:string):Promise<Array<models.NewsArticle>>;
+
 export function SearchUSStock(arg1:string):Promise<Array<models.USStock>>;
 
 export function SellPosition(arg1:number,arg2:number,arg3:string):Promise<void>;
@@ -312,6 +387,8 @@ export function TestDatasource(arg1:string,arg2:string):Promise<plugin.Datasourc
 
 export function TestNotification(arg1:string):Promise<void>;
 
+export function ToggleCryptoAlert(arg1:number,arg2:boolean):Promise<void>;
+
 export function ToggleFundAlert(arg1:number,arg2:boolean):Promise<void>;
 
 export function TogglePlugin(arg1:string,arg2:boolean):Promise<void>;
@@ -327,3 +404,5 @@ export function UpdatePosition(arg1:models.Position):Promise<void>;
 export function UpdatePrompt(arg1:string,arg2:string,arg3:string):Promise<prompt.PromptInfo>;
 
 export function UpdateStockAlert(arg1:models.StockAlert):Promise<void>;
+
+export function ValidateScreenExpression(arg1:string):Promise<string>;
@@ -2,6 +2,10 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function AIAnalyzeAssetStream(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['AIAnalyzeAssetStream'](arg1, arg2, arg3, arg4);
+}
+
 export function AIAnalyzeByTypeStream(arg1, arg2, arg3) {
   return window['go']['main']['App']['AIAnalyzeByTypeStream'](arg1, arg2, arg3);
 }
@@ -50,6 +54,14 @@ export function AISummarizeContentStream(arg1, arg2, arg3, arg4, arg5, arg6, arg
   return window['go']['main']['App']['AISummarizeContentStream'](arg1, arg2, arg3, arg4, arg5, arg6, arg7);
 }
 
+export function AddCrypto(arg1, arg2) {
+  return window['go']['main']['App']['AddCrypto'](arg1, arg2);
+}
+
+export function AddCryptoAlert(arg1) {
+  return window['go']['main']['App']['AddCryptoAlert'](arg1);
+}
+
 export function AddFund(arg1) {
   return window['go']['main']['App']['AddFund'](arg1);
 }
@@ -90,6 +102,10 @@ export function AddUSStock(arg1, arg2, arg3, arg4) {
   return window['go']['main']['App']['AddUSStock'](arg1, arg2, arg3, arg4);
 }
 
+export function CheckCryptoAlerts() {
+  return window['go']['main']['App']['CheckCryptoAlerts']();
+}
+
 export function CheckFundAlerts() {
   return window['go']['main']['App']['CheckFundAlerts']();
 }
@@ -98,6 +114,10 @@ export function CheckStockAlerts() {
   return window['go']['main']['App']['CheckStockAlerts']();
 }
 
+export function CheckStockEventAlerts() {
+  return window['go']['main']['App']['CheckStockEventAlerts']();
+}
+
 export function CheckUpdate() {
   return window['go']['main']['App']['CheckUpdate']();
 }
@@ -130,6 +150,10 @@ export function ClearOldAIData() {
   return window['go']['main']['App']['ClearOldAIData']();
 }
 
+export function ConvertCurrency(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ConvertCurrency'](arg1, arg2, arg3);
+}
+
 export function CreateAIPluginFromTemplate(arg1, arg2, arg3, arg4, arg5) {
   return window['go']['main']['App']['CreateAIPluginFromTemplate'](arg1, arg2, arg3, arg4, arg5);
 }
@@ -150,6 +174,10 @@ export function DeleteAIChatSession(arg1) {
   return window['go']['main']['App']['DeleteAIChatSession'](arg1);
 }
 
+export function DeleteCryptoAlert(arg1) {
+  return window['go']['main']['App']['DeleteCryptoAlert'](arg1);
+}
+
 export function DeleteFundAlert(arg1) {
   return window['go']['main']['App']['DeleteFundAlert'](arg1);
 }
@@ -170,6 +198,10 @@ export function DeletePrompt(arg1, arg2) {
   return window['go']['main']['App']['DeletePrompt'](arg1, arg2);
 }
 
+export function DeleteScreen(arg1) {
+  return window['go']['main']['App']['DeleteScreen'](arg1);
+}
+
 export function DeleteStockAlert(arg1) {
   return window['go']['main']['App']['DeleteStockAlert'](arg1);
 }
@@ -190,6 +222,10 @@ export function ExecuteScreenerPrompt(arg1) {
   return window['go']['main']['App']['ExecuteScreenerPrompt'](arg1);
 }
 
+export function ExecuteScreenerPromptOnScreen(arg1, arg2) {
+  return window['go']['main']['App']['ExecuteScreenerPromptOnScreen'](arg1, arg2);
+}
+
 export function ExportAIAnalysisHistory(arg1) {
   return window['go']['main']['App']['ExportAIAnalysisHistory'](arg1);
 }
@@ -218,6 +254,10 @@ export function FrontendTrace(arg1) {
   return window['go']['main']['App']['FrontendTrace'](arg1);
 }
 
+export function GenerateDailyDigest(arg1) {
+  return window['go']['main']['App']['GenerateDailyDigest'](arg1);
+}
+
 export function GetAIAnalysisHistory() {
   return window['go']['main']['App']['GetAIAnalysisHistory']();
 }
@@ -246,6 +286,10 @@ export function GetAllAlerts() {
   return window['go']['main']['App']['GetAllAlerts']();
 }
 
+export function GetAssetAnalysisCache(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['GetAssetAnalysisCache'](arg1, arg2, arg3, arg4);
+}
+
 export function GetCachedGlobalMarketData(arg1) {
   return window['go']['main']['App']['GetCachedGlobalMarketData'](arg1);
 }
@@ -258,6 +302,30 @@ export function GetConfig() {
   return window['go']['main']['App']['GetConfig']();
 }
 
+export function GetCrossRate(arg1, arg2) {
+  return window['go']['main']['App']['GetCrossRate'](arg1, arg2);
+}
+
+export function GetCryptoAlerts(arg1) {
+  return window['go']['main']['App']['GetCryptoAlerts'](arg1);
+}
+
+export function GetCryptoKLine(arg1, arg2, arg3) {
+  return window['go']['main']['App']['GetCryptoKLine'](arg1, arg2, arg3);
+}
+
+export function GetCryptoList() {
+  return window['go']['main']['App']['GetCryptoList']();
+}
+
+export function GetCryptoQuotes(arg1) {
+  return window['go']['main']['App']['GetCryptoQuotes'](arg1);
+}
+
+export function GetDailyDigests(arg1) {
+  return window['go']['main']['App']['GetDailyDigests'](arg1);
+}
+
 export function GetDataCleanupInfo() {
   return window['go']['main']['App']['GetDataCleanupInfo']();
 }
@@ -278,6 +346,14 @@ export function GetEnabledDatasourcePlugins() {
   return window['go']['main']['App']['GetEnabledDatasourcePlugins']();
 }
 
+export function GetFinancialTrend(arg1) {
+  return window['go']['main']['App']['GetFinancialTrend'](arg1);
+}
+
+export function GetForexHistory(arg1, arg2) {
+  return window['go']['main']['App']['GetForexHistory'](arg1, arg2);
+}
+
 export function GetForexRates() {
   return window['go']['main']['App']['GetForexRates']();
 }
@@ -350,6 +426,10 @@ export function GetKLineData(arg1, arg2, arg3) {
   return window['go']['main']['App']['GetKLineData'](arg1, arg2, arg3);
 }
 
+export function GetLocalAPIAddress() {
+  return window['go']['main']['App']['GetLocalAPIAddress']();
+}
+
 export function GetLongTigerRank() {
   return window['go']['main']['App']['GetLongTigerRank']();
 }
@@ -358,6 +438,10 @@ export function GetMainContracts() {
   return window['go']['main']['App']['GetMainContracts']();
 }
 
+export function GetMainCryptoCoins() {
+  return window['go']['main']['App']['GetMainCryptoCoins']();
+}
+
 export function GetMainForexPairs() {
   return window['go']['main']['App']['GetMainForexPairs']();
 }
@@ -406,6 +490,10 @@ export function GetPopularUSStocks() {
   return window['go']['main']['App']['GetPopularUSStocks']();
 }
 
+export function GetPortfolioValuationCNY() {
+  return window['go']['main']['App']['GetPortfolioValuationCNY']();
+}
+
 export function GetPositionByStock(arg1) {
   return window['go']['main']['App']['GetPositionByStock'](arg1);
 }
@@ -434,10 +522,26 @@ export function GetPromptsDir() {
   return window['go']['main']['App']['GetPromptsDir']();
 }
 
+export function GetReportConsensus(arg1) {
+  return window['go']['main']['App']['GetReportConsensus'](arg1);
+}
+
+export function GetReportHistory(arg1, arg2) {
+  return window['go']['main']['App']['GetReportHistory'](arg1, arg2);
+}
+
 export function GetResearchReports(arg1) {
   return window['go']['main']['App']['GetResearchReports'](arg1);
 }
 
+export function GetScreenRuns(arg1, arg2) {
+  return window['go']['main']['App']['GetScreenRuns'](arg1, arg2);
+}
+
+export function GetScreenerFields() {
+  return window['go']['main']['App']['GetScreenerFields']();
+}
+
 export function GetStockAlerts(arg1) {
   return window['go']['main']['App']['GetStockAlerts'](arg1);
 }
@@ -454,6 +558,10 @@ export function GetStockPrice(arg1) {
   return window['go']['main']['App']['GetStockPrice'](arg1);
 }
 
+export function GetTopCryptoQuotes(arg1) {
+  return window['go']['main']['App']['GetTopCryptoQuotes'](arg1);
+}
+
 export function GetTradingTimeInfo() {
   return window['go']['main']['App']['GetTradingTimeInfo']();
 }
@@ -466,6 +574,10 @@ export function GetUSStockPrice(arg1) {
   return window['go']['main']['App']['GetUSStockPrice'](arg1);
 }
 
+export function GetValuationBands(arg1) {
+  return window['go']['main']['App']['GetValuationBands'](arg1);
+}
+
 export function GetVersion() {
   return window['go']['main']['App']['GetVersion']();
 }
@@ -502,6 +614,10 @@ export function ListPrompts(arg1) {
   return window['go']['main']['App']['ListPrompts'](arg1);
 }
 
+export function ListScreens() {
+  return window['go']['main']['App']['ListScreens']();
+}
+
 export function MarkFirstLoadComplete() {
   return window['go']['main']['App']['MarkFirstLoadComplete']();
 }
@@ -522,10 +638,22 @@ export function PrefetchTradeLevelData(arg1) {
   return window['go']['main']['App']['PrefetchTradeLevelData'](arg1);
 }
 
+export function PushDailyDigest(arg1) {
+  return window['go']['main']['App']['PushDailyDigest'](arg1);
+}
+
 export function RefreshPlugins() {
   return window['go']['main']['App']['RefreshPlugins']();
 }
 
+export function RefreshScreenerData(arg1) {
+  return window['go']['main']['App']['RefreshScreenerData'](arg1);
+}
+
+export function RemoveCrypto(arg1) {
+  return window['go']['main']['App']['RemoveCrypto'](arg1);
+}
+
 export function RemoveFund(arg1) {
   return window['go']['main']['App']['RemoveFund'](arg1);
 }
@@ -550,6 +678,10 @@ export function RenamePrompt(arg1, arg2, arg3) {
   return window['go']['main']['App']['RenamePrompt'](arg1, arg2, arg3);
 }
 
+export function ResetCryptoAlert(arg1) {
+  return window['go']['main']['App']['ResetCryptoAlert'](arg1);
+}
+
 export function ResetFundAlert(arg1) {
   return window['go']['main']['App']['ResetFundAlert'](arg1);
 }
@@ -558,6 +690,14 @@ export function ResetStockAlert(arg1) {
   return window['go']['main']['App']['ResetStockAlert'](arg1);
 }
 
+export function RunSavedScreen(arg1) {
+  return window['go']['main']['App']['RunSavedScreen'](arg1);
+}
+
+export function RunScreen(arg1) {
+  return window['go']['main']['App']['RunScreen'](arg1);
+}
+
 export function SaveAIAnalysisResult(arg1, arg2, arg3, arg4) {
   return window['go']['main']['App']['SaveAIAnalysisResult'](arg1, arg2, arg3, arg4);
 }
@@ -570,6 +710,10 @@ export function SaveConfig(arg1) {
   return window['go']['main']['App']['SaveConfig'](arg1);
 }
 
+export function SaveScreen(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['SaveScreen'](arg1, arg2, arg3, arg4);
+}
+
 export function SearchFutures(arg1) {
   return window['go']['main']['App']['SearchFutures'](arg1);
 }
@@ -578,6 +722,10 @@ export function SearchHKStock(arg1) {
   return window['go']['main']['App']['SearchHKStock'](arg1);
 }
 
+export function SearchNews(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['SearchNews'](arg1, arg2, arg3, arg4);
+}
+
 export function SearchUSStock(arg1) {
   return window['go']['main']['App']['SearchUSStock'](arg1);
 }
@@ -614,6 +762,10 @@ export function TestNotification(arg1) {
   return window['go']['main']['App']['TestNotification'](arg1);
 }
 
+export function ToggleCryptoAlert(arg1, arg2) {
+  return window['go']['main']['App']['ToggleCryptoAlert'](arg1, arg2);
+}
+
 export function ToggleFundAlert(arg1, arg2) {
   return window['go']['main']['App']['ToggleFundAlert'](arg1, arg2);
 }
@@ -645,3 +797,7 @@ export function UpdatePrompt(arg1, arg2, arg3) {
 export function UpdateStockAlert(arg1) {
   return window['go']['main']['App']['UpdateStockAlert'](arg1);
 }
+
+export function ValidateScreenExpression(arg1) {
+  return window['go']['main']['App']['ValidateScreenExpression'](arg1);
+}
@@ -226,6 +226,7 @@ export namespace main {
 	export class DataCleanupInfo {
 	    cacheStats: Record<string, any>;
 	    rateLimiterStats: Record<string, any>;
+	    coalesceStats: models.CoalesceStat[];
 	    aiDataInfo: Record<string, any>;
 	    cleanupConfig: Record<string, any>;
 	
@@ -237,10 +238,116 @@ export namespace main {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.cacheStats = source["cacheStats"];
 	        this.rateLimiterStats = source["rateLimiterStats"];
+	        this.coalesceStats = this.convertValues(source["coalesceStats"], models.CoalesceStat);
 	        this.aiDataInfo = source["aiDataInfo"];
 	        this.cleanupConfig = source["cleanupConfig"];
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class PositionValuation {
+	    position: models.Position;
+	    currency: string;
+	    currentPrice: number;
+	    priceAvailable: boolean;
+	    marketValue: number;
+	    costValue: number;
+	    fxRate: number;
+	    marketValueCny: number;
+	    costValueCny: number;
+	    profitCny: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new PositionValuation(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.position = this.convertValues(source["position"], models.Position);
+	        this.currency = source["currency"];
+	        this.currentPrice = source["currentPrice"];
+	        this.priceAvailable = source["priceAvailable"];
+	        this.marketValue = source["marketValue"];
+	        this.costValue = source["costValue"];
+	        this.fxRate = source["fxRate"];
+	        this.marketValueCny = source["marketValueCny"];
+	        this.costValueCny = source["costValueCny"];
+	        this.profitCny = source["profitCny"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class PortfolioValuation {
+	    items: PositionValuation[];
+	    totalMarketValueCny: number;
+	    totalCostCny: number;
+	    totalProfitCny: number;
+	    warnings: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new PortfolioValuation(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.items = this.convertValues(source["items"], PositionValuation);
+	        this.totalMarketValueCny = source["totalMarketValueCny"];
+	        this.totalCostCny = source["totalCostCny"];
+	        this.totalProfitCny = source["totalProfitCny"];
+	        this.warnings = source["warnings"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
+	
 	export class TradingTimeInfo {
 	    isTradingTime: boolean;
 	    isPreMarketTime: boolean;
@@ -387,6 +494,8 @@ export namespace models {
 	    message: string;
 	    time: string;
 	    assetType: string;
+	    title?: string;
+	    url?: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new AlertNotification(source);
@@ -404,12 +513,16 @@ export namespace models {
 	        this.message = source["message"];
 	        this.time = source["time"];
 	        this.assetType = source["assetType"];
+	        this.title = source["title"];
+	        this.url = source["url"];
 	    }
 	}
 	export class Config {
 	    id: number;
 	    refreshInterval: number;
 	    proxyUrl: string;
+	    proxyUsername: string;
+	    proxyPassword: string;
 	    proxyPoolEnabled: boolean;
 	    proxyProvider: string;
 	    proxyApiUrl: string;
@@ -420,6 +533,7 @@ export namespace models {
 	    proxyPoolProtocol: string;
 	    proxyPoolTTL: number;
 	    proxyPoolSize: number;
+	    proxyRoutes: string;
 	    theme: string;
 	    customPrimary: string;
 	    alertPushEnabled: boolean;
@@ -444,8 +558,17 @@ export namespace models {
 	    tushareToken: string;
 	    tushareEnabled: boolean;
 	    akshareEnabled: boolean;
+	    aksharePythonEnabled: boolean;
 	    dataSourcePriority: string;
+	    quoteValidationEnabled: boolean;
+	    dailyDigestEnabled: boolean;
+	    dailyDigestTime: string;
 	    activePersona: string;
+	    policyRates: string;
+	    policyRatesAsOf: string;
+	    localApiEnabled: boolean;
+	    localApiPort: number;
+	    localApiToken: string;
 	    skipUpdateVersion: string;
 	
 	    static createFrom(source: any = {}) {
@@ -457,6 +580,8 @@ export namespace models {
 	        this.id = source["id"];
 	        this.refreshInterval = source["refreshInterval"];
 	        this.proxyUrl = source["proxyUrl"];
+	        this.proxyUsername = source["proxyUsername"];
+	        this.proxyPassword = source["proxyPassword"];
 	        this.proxyPoolEnabled = source["proxyPoolEnabled"];
 	        this.proxyProvider = source["proxyProvider"];
 	        this.proxyApiUrl = source["proxyApiUrl"];
@@ -467,6 +592,7 @@ export namespace models {
 	        this.proxyPoolProtocol = source["proxyPoolProtocol"];
 	        this.proxyPoolTTL = source["proxyPoolTTL"];
 	        this.proxyPoolSize = source["proxyPoolSize"];
+	        this.proxyRoutes = source["proxyRoutes"];
 	        this.theme = source["theme"];
 	        this.customPrimary = source["customPrimary"];
 	        this.alertPushEnabled = source["alertPushEnabled"];
@@ -491,81 +617,75 @@ export namespace models {
 	        this.tushareToken = source["tushareToken"];
 	        this.tushareEnabled = source["tushareEnabled"];
 	        this.akshareEnabled = source["akshareEnabled"];
+	        this.aksharePythonEnabled = source["aksharePythonEnabled"];
 	        this.dataSourcePriority = source["dataSourcePriority"];
+	        this.quoteValidationEnabled = source["quoteValidationEnabled"];
+	        this.dailyDigestEnabled = source["dailyDigestEnabled"];
+	        this.dailyDigestTime = source["dailyDigestTime"];
 	        this.activePersona = source["activePersona"];
+	        this.policyRates = source["policyRates"];
+	        this.policyRatesAsOf = source["policyRatesAsOf"];
+	        this.localApiEnabled = source["localApiEnabled"];
+	        this.localApiPort = source["localApiPort"];
+	        this.localApiToken = source["localApiToken"];
 	        this.skipUpdateVersion = source["skipUpdateVersion"];
 	    }
 	}
-	export class ProxyStatus {
-	    enabled: boolean;
-	    poolEnabled: boolean;
-	    provider: string;
-	    activeProxies: number;
-	    expiresAt: string;
-	    expiresInSeconds: number;
-	    lastFetch: string;
-	    lastError: string;
+	export class CrossRate {
+	    base: string;
+	    quote: string;
+	    rate: number;
+	    path: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new ProxyStatus(source);
+	        return new CrossRate(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.enabled = source["enabled"];
-	        this.poolEnabled = source["poolEnabled"];
-	        this.provider = source["provider"];
-	        this.activeProxies = source["activeProxies"];
-	        this.expiresAt = source["expiresAt"];
-	        this.expiresInSeconds = source["expiresInSeconds"];
-	        this.lastFetch = source["lastFetch"];
-	        this.lastError = source["lastError"];
+	        this.base = source["base"];
+	        this.quote = source["quote"];
+	        this.rate = source["rate"];
+	        this.path = source["path"];
 	    }
 	}
-	export class DataSourceStatus {
-	    key: string;
+	export class CryptoAlert {
+	    id: number;
+	    symbol: string;
 	    name: string;
-	    domain: string;
-	    latency: number;
-	    latencyLabel: string;
-	    lastChecked: string;
-	    lastSuccess: string;
-	    status: string;
-	    failCount: number;
+	    alertType: string;
+	    targetValue: number;
+	    condition: string;
+	    enabled: boolean;
+	    triggered: boolean;
+	    // Go type: time
+	    triggeredAt?: any;
+	    triggeredPrice: number;
+	    triggeredChange: number;
+	    // Go type: time
+	    createdAt: any;
+	    // Go type: time
+	    updatedAt: any;
 	
 	    static createFrom(source: any = {}) {
-	        return new DataSourceStatus(source);
+	        return new CryptoAlert(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.key = source["key"];
+	        this.id = source["id"];
+	        this.symbol = source["symbol"];
 	        this.name = source["name"];
-	        this.domain = source["domain"];
-	        this.latency = source["latency"];
-	        this.latencyLabel = source["latencyLabel"];
-	        this.lastChecked = source["lastChecked"];
-	        this.lastSuccess = source["lastSuccess"];
-	        this.status = source["status"];
-	        this.failCount = source["failCount"];
-	    }
-	}
-	export class DataPipelineStatus {
-	    marketSources: DataSourceStatus[];
-	    financial: Record<string, any>;
-	    proxy: ProxyStatus;
-	    generatedAt: string;
-	
-	    static createFrom(source: any = {}) {
-	        return new DataPipelineStatus(source);
-	    }
-	
-	    constructor(source: any = {}) {
-	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.marketSources = this.convertValues(source["marketSources"], DataSourceStatus);
-	        this.financial = source["financial"];
-	        this.proxy = this.convertValues(source["proxy"], ProxyStatus);
-	        this.generatedAt = source["generatedAt"];
+	        this.alertType = source["alertType"];
+	        this.targetValue = source["targetValue"];
+	        this.condition = source["condition"];
+	        this.enabled = source["enabled"];
+	        this.triggered = source["triggered"];
+	        this.triggeredAt = this.convertValues(source["triggeredAt"], null);
+	        this.triggeredPrice = source["triggeredPrice"];
+	        this.triggeredChange = source["triggeredChange"];
+	        this.createdAt = this.convertValues(source["createdAt"], null);
+	        this.updatedAt = this.convertValues(source["updatedAt"], null);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -586,53 +706,24 @@ export namespace models {
 		    return a;
 		}
 	}
-	
-	export class ForexRate {
-	    pair: string;
-	    name: string;
-	    rate: number;
-	    change: number;
-	    changePercent: number;
-	    high: number;
-	    low: number;
-	    updateTime: string;
-	
-	    static createFrom(source: any = {}) {
-	        return new ForexRate(source);
-	    }
-	
-	    constructor(source: any = {}) {
-	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.pair = source["pair"];
-	        this.name = source["name"];
-	        this.rate = source["rate"];
-	        this.change = source["change"];
-	        this.changePercent = source["changePercent"];
-	        this.high = source["high"];
-	        this.low = source["low"];
-	        this.updateTime = source["updateTime"];
-	    }
-	}
-	export class Fund {
+	export class CryptoCoin {
 	    id: number;
-	    code: string;
+	    symbol: string;
 	    name: string;
-	    type: string;
 	    // Go type: time
 	    createdAt: any;
 	    // Go type: time
 	    updatedAt: any;
 	
 	    static createFrom(source: any = {}) {
-	        return new Fund(source);
+	        return new CryptoCoin(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.id = source["id"];
-	        this.code = source["code"];
+	        this.symbol = source["symbol"];
 	        this.name = source["name"];
-	        this.type = source["type"];
 	        this.createdAt = this.convertValues(source["createdAt"], null);
 	        this.updatedAt = this.convertValues(source["updatedAt"], null);
 	    }
@@ -655,41 +746,65 @@ export namespace models {
 		    return a;
 		}
 	}
-	export class FundAlert {
+	export class CryptoPrice {
+	    symbol: string;
+	    name: string;
+	    price: number;
+	    change: number;
+	    changePercent: number;
+	    open24h: number;
+	    high24h: number;
+	    low24h: number;
+	    volume24h: number;
+	    quoteVolume24h: number;
+	    updateTime: string;
+	    source: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new CryptoPrice(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.symbol = source["symbol"];
+	        this.name = source["name"];
+	        this.price = source["price"];
+	        this.change = source["change"];
+	        this.changePercent = source["changePercent"];
+	        this.open24h = source["open24h"];
+	        this.high24h = source["high24h"];
+	        this.low24h = source["low24h"];
+	        this.volume24h = source["volume24h"];
+	        this.quoteVolume24h = source["quoteVolume24h"];
+	        this.updateTime = source["updateTime"];
+	        this.source = source["source"];
+	    }
+	}
+	export class DailyDigest {
 	    id: number;
-	    fundCode: string;
-	    fundName: string;
-	    alertType: string;
-	    targetValue: number;
-	    condition: string;
-	    enabled: boolean;
-	    triggered: boolean;
+	    date: string;
+	    content: string;
+	    stockCount: number;
+	    itemCount: number;
 	    // Go type: time
-	    triggeredAt?: any;
-	    triggeredNav: number;
-	    triggeredChange: number;
+	    pushedAt?: any;
 	    // Go type: time
 	    createdAt: any;
 	    // Go type: time
 	    updatedAt: any;
 	
 	    static createFrom(source: any = {}) {
-	        return new FundAlert(source);
+	        return new DailyDigest(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.id = source["id"];
-	        this.fundCode = source["fundCode"];
-	        this.fundName = source["fundName"];
-	        this.alertType = source["alertType"];
-	        this.targetValue = source["targetValue"];
-	        this.condition = source["condition"];
-	        this.enabled = source["enabled"];
-	        this.triggered = source["triggered"];
-	        this.triggeredAt = this.convertValues(source["triggeredAt"], null);
-	        this.triggeredNav = source["triggeredNav"];
-	        this.triggeredChange = source["triggeredChange"];
+	        this.date = source["date"];
+	        this.content = source["content"];
+	        this.stockCount = source["stockCount"];
+	        this.itemCount = source["itemCount"];
+	        this.pushedAt = this.convertValues(source["pushedAt"], null);
 	        this.createdAt = this.convertValues(source["createdAt"], null);
 	        this.updatedAt = this.convertValues(source["updatedAt"], null);
 	    }
@@ -712,12 +827,693 @@ export namespace models {
 		    return a;
 		}
 	}
-	export class FundDetail {
+	export class DataMeta {
+	    source: string;
+	    fetchedAt: string;
+	    freshness: string;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new DataMeta(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.source = source["source"];
+	        this.fetchedAt = source["fetchedAt"];
+	        this.freshness = source["freshness"];
+	        this.error = source["error"];
+	    }
+	}
+	export class QuoteDiscrepancy {
 	    code: string;
-	    name: string;
-	    type: string;
-	    riskLevel: string;
-	    manager: string;
+	    reason: string;
+	    detail: string;
+	    source: string;
+	    compare: string;
+	    price: number;
+	    comparePrice: number;
+	    action: string;
+	    time: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new QuoteDiscrepancy(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.code = source["code"];
+	        this.reason = source["reason"];
+	        this.detail = source["detail"];
+	        this.source = source["source"];
+	        this.compare = source["compare"];
+	        this.price = source["price"];
+	        this.comparePrice = source["comparePrice"];
+	        this.action = source["action"];
+	        this.time = source["time"];
+	    }
+	}
+	export class QuoteValidationStats {
+	    enabled: boolean;
+	    checked: number;
+	    crossChecked: number;
+	    flagged: number;
+	    rejected: number;
+	    reasons: Record<string, number>;
+	    sourceFlags: Record<string, number>;
+	    recent: QuoteDiscrepancy[];
+	
+	    static createFrom(source: any = {}) {
+	        return new QuoteValidationStats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.checked = source["checked"];
+	        this.crossChecked = source["crossChecked"];
+	        this.flagged = source["flagged"];
+	        this.rejected = source["rejected"];
+	        this.reasons = source["reasons"];
+	        this.sourceFlags = source["sourceFlags"];
+	        this.recent = this.convertValues(source["recent"], QuoteDiscrepancy);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SchemaViolation {
+	    field: string;
+	    rule: string;
+	    count: number;
+	    example: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SchemaViolation(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.field = source["field"];
+	        this.rule = source["rule"];
+	        this.count = source["count"];
+	        this.example = source["example"];
+	    }
+	}
+	export class SchemaDriftEvent {
+	    source: string;
+	    dataset: string;
+	    records: number;
+	    violations: SchemaViolation[];
+	    broken: boolean;
+	    samplePath: string;
+	    time: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SchemaDriftEvent(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.source = source["source"];
+	        this.dataset = source["dataset"];
+	        this.records = source["records"];
+	        this.violations = this.convertValues(source["violations"], SchemaViolation);
+	        this.broken = source["broken"];
+	        this.samplePath = source["samplePath"];
+	        this.time = source["time"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SchemaSourceHealth {
+	    source: string;
+	    dataset: string;
+	    batches: number;
+	    badBatches: number;
+	    violations: Record<string, number>;
+	    consecutiveBad: number;
+	    broken: boolean;
+	    brokenSince: string;
+	    lastSamplePath: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SchemaSourceHealth(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.source = source["source"];
+	        this.dataset = source["dataset"];
+	        this.batches = source["batches"];
+	        this.badBatches = source["badBatches"];
+	        this.violations = source["violations"];
+	        this.consecutiveBad = source["consecutiveBad"];
+	        this.broken = source["broken"];
+	        this.brokenSince = source["brokenSince"];
+	        this.lastSamplePath = source["lastSamplePath"];
+	    }
+	}
+	export class SchemaHealthStats {
+	    sources: SchemaSourceHealth[];
+	    recent: SchemaDriftEvent[];
+	
+	    static createFrom(source: any = {}) {
+	        return new SchemaHealthStats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.sources = this.convertValues(source["sources"], SchemaSourceHealth);
+	        this.recent = this.convertValues(source["recent"], SchemaDriftEvent);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ProxyHealth {
+	    proxy: string;
+	    healthy: boolean;
+	    score: number;
+	    latencyMs: number;
+	    successes: number;
+	    failures: number;
+	    lastChecked: string;
+	    lastError: string;
+	    stickyDomains: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ProxyHealth(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.proxy = source["proxy"];
+	        this.healthy = source["healthy"];
+	        this.score = source["score"];
+	        this.latencyMs = source["latencyMs"];
+	        this.successes = source["successes"];
+	        this.failures = source["failures"];
+	        this.lastChecked = source["lastChecked"];
+	        this.lastError = source["lastError"];
+	        this.stickyDomains = source["stickyDomains"];
+	    }
+	}
+	export class ProxyStatus {
+	    enabled: boolean;
+	    poolEnabled: boolean;
+	    provider: string;
+	    activeProxies: number;
+	    healthyProxies: number;
+	    expiresAt: string;
+	    expiresInSeconds: number;
+	    lastFetch: string;
+	    lastError: string;
+	    proxies: ProxyHealth[];
+	    routes: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ProxyStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.poolEnabled = source["poolEnabled"];
+	        this.provider = source["provider"];
+	        this.activeProxies = source["activeProxies"];
+	        this.healthyProxies = source["healthyProxies"];
+	        this.expiresAt = source["expiresAt"];
+	        this.expiresInSeconds = source["expiresInSeconds"];
+	        this.lastFetch = source["lastFetch"];
+	        this.lastError = source["lastError"];
+	        this.proxies = this.convertValues(source["proxies"], ProxyHealth);
+	        this.routes = source["routes"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class DataSourceStatus {
+	    key: string;
+	    name: string;
+	    domain: string;
+	    latency: number;
+	    latencyLabel: string;
+	    lastChecked: string;
+	    lastSuccess: string;
+	    status: string;
+	    failCount: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new DataSourceStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.key = source["key"];
+	        this.name = source["name"];
+	        this.domain = source["domain"];
+	        this.latency = source["latency"];
+	        this.latencyLabel = source["latencyLabel"];
+	        this.lastChecked = source["lastChecked"];
+	        this.lastSuccess = source["lastSuccess"];
+	        this.status = source["status"];
+	        this.failCount = source["failCount"];
+	    }
+	}
+	export class RateLimitStatus {
+	    domain: string;
+	    factor: number;
+	    maxPerMinute: number;
+	    effectivePerMinute: number;
+	    minIntervalMs: number;
+	    requestsLastMinute: number;
+	    inCooldown: boolean;
+	    cooldownSeconds: number;
+	    throttles: number;
+	    lastReason: string;
+	    lastThrottleAt: string;
+	    queued: Record<string, number>;
+	
+	    static createFrom(source: any = {}) {
+	        return new RateLimitStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.domain = source["domain"];
+	        this.factor = source["factor"];
+	        this.maxPerMinute = source["maxPerMinute"];
+	        this.effectivePerMinute = source["effectivePerMinute"];
+	        this.minIntervalMs = source["minIntervalMs"];
+	        this.requestsLastMinute = source["requestsLastMinute"];
+	        this.inCooldown = source["inCooldown"];
+	        this.cooldownSeconds = source["cooldownSeconds"];
+	        this.throttles = source["throttles"];
+	        this.lastReason = source["lastReason"];
+	        this.lastThrottleAt = source["lastThrottleAt"];
+	        this.queued = source["queued"];
+	    }
+	}
+	export class CoalesceStat {
+	    endpoint: string;
+	    calls: number;
+	    executed: number;
+	    saved: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new CoalesceStat(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.endpoint = source["endpoint"];
+	        this.calls = source["calls"];
+	        this.executed = source["executed"];
+	        this.saved = source["saved"];
+	    }
+	}
+	export class DataPipelineStatus {
+	    marketSources: DataSourceStatus[];
+	    financial: Record<string, any>;
+	    proxy: ProxyStatus;
+	    quoteValidation: QuoteValidationStats;
+	    schemaHealth: SchemaHealthStats;
+	    rateLimits: RateLimitStatus[];
+	    coalesce: CoalesceStat[];
+	    generatedAt: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new DataPipelineStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.marketSources = this.convertValues(source["marketSources"], DataSourceStatus);
+	        this.financial = source["financial"];
+	        this.proxy = this.convertValues(source["proxy"], ProxyStatus);
+	        this.quoteValidation = this.convertValues(source["quoteValidation"], QuoteValidationStats);
+	        this.schemaHealth = this.convertValues(source["schemaHealth"], SchemaHealthStats);
+	        this.rateLimits = this.convertValues(source["rateLimits"], RateLimitStatus);
+	        this.coalesce = this.convertValues(source["coalesce"], CoalesceStat);
+	        this.generatedAt = source["generatedAt"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class FinancialPeriodMetrics {
+	    reportDate: string;
+	    hasQuarter: boolean;
+	    quarterRevenue: number;
+	    quarterNetProfit: number;
+	    hasYoY: boolean;
+	    revenueYoY: number;
+	    netProfitYoY: number;
+	    hasQoQ: boolean;
+	    revenueQoQ: number;
+	    netProfitQoQ: number;
+	    hasTTM: boolean;
+	    revenueTTM: number;
+	    netProfitTTM: number;
+	    operatingCFTTM: number;
+	    freeCashFlowTTM: number;
+	    hasTTMYoY: boolean;
+	    revenueTTMYoY: number;
+	    netProfitTTMYoY: number;
+	    grossMargin: number;
+	    netMargin: number;
+	    assetTurnover: number;
+	    equityMultiplier: number;
+	    roe: number;
+	    accrualRatio: number;
+	    cashConversion: number;
+	    debtRatio: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new FinancialPeriodMetrics(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.reportDate = source["reportDate"];
+	        this.hasQuarter = source["hasQuarter"];
+	        this.quarterRevenue = source["quarterRevenue"];
+	        this.quarterNetProfit = source["quarterNetProfit"];
+	        this.hasYoY = source["hasYoY"];
+	        this.revenueYoY = source["revenueYoY"];
+	        this.netProfitYoY = source["netProfitYoY"];
+	        this.hasQoQ = source["hasQoQ"];
+	        this.revenueQoQ = source["revenueQoQ"];
+	        this.netProfitQoQ = source["netProfitQoQ"];
+	        this.hasTTM = source["hasTTM"];
+	        this.revenueTTM = source["revenueTTM"];
+	        this.netProfitTTM = source["netProfitTTM"];
+	        this.operatingCFTTM = source["operatingCFTTM"];
+	        this.freeCashFlowTTM = source["freeCashFlowTTM"];
+	        this.hasTTMYoY = source["hasTTMYoY"];
+	        this.revenueTTMYoY = source["revenueTTMYoY"];
+	        this.netProfitTTMYoY = source["netProfitTTMYoY"];
+	        this.grossMargin = source["grossMargin"];
+	        this.netMargin = source["netMargin"];
+	        this.assetTurnover = source["assetTurnover"];
+	        this.equityMultiplier = source["equityMultiplier"];
+	        this.roe = source["roe"];
+	        this.accrualRatio = source["accrualRatio"];
+	        this.cashConversion = source["cashConversion"];
+	        this.debtRatio = source["debtRatio"];
+	    }
+	}
+	export class FinancialTrend {
+	    stockCode: string;
+	    source: string;
+	    periods: FinancialPeriodMetrics[];
+	
+	    static createFrom(source: any = {}) {
+	        return new FinancialTrend(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.stockCode = source["stockCode"];
+	        this.source = source["source"];
+	        this.periods = this.convertValues(source["periods"], FinancialPeriodMetrics);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ForexHistory {
+	    id: number;
+	    pair: string;
+	    date: string;
+	    open: number;
+	    high: number;
+	    low: number;
+	    close: number;
+	    source: string;
+	    // Go type: time
+	    updatedAt: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new ForexHistory(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.pair = source["pair"];
+	        this.date = source["date"];
+	        this.open = source["open"];
+	        this.high = source["high"];
+	        this.low = source["low"];
+	        this.close = source["close"];
+	        this.source = source["source"];
+	        this.updatedAt = this.convertValues(source["updatedAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ForexRate {
+	    pair: string;
+	    name: string;
+	    rate: number;
+	    change: number;
+	    changePercent: number;
+	    high: number;
+	    low: number;
+	    updateTime: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ForexRate(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.pair = source["pair"];
+	        this.name = source["name"];
+	        this.rate = source["rate"];
+	        this.change = source["change"];
+	        this.changePercent = source["changePercent"];
+	        this.high = source["high"];
+	        this.low = source["low"];
+	        this.updateTime = source["updateTime"];
+	    }
+	}
+	export class Fund {
+	    id: number;
+	    code: string;
+	    name: string;
+	    type: string;
+	    // Go type: time
+	    createdAt: any;
+	    // Go type: time
+	    updatedAt: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new Fund(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.code = source["code"];
+	        this.name = source["name"];
+	        this.type = source["type"];
+	        this.createdAt = this.convertValues(source["createdAt"], null);
+	        this.updatedAt = this.convertValues(source["updatedAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class FundAlert {
+	    id: number;
+	    fundCode: string;
+	    fundName: string;
+	    alertType: string;
+	    targetValue: number;
+	    condition: string;
+	    enabled: boolean;
+	    triggered: boolean;
+	    // Go type: time
+	    triggeredAt?: any;
+	    triggeredNav: number;
+	    triggeredChange: number;
+	    // Go type: time
+	    createdAt: any;
+	    // Go type: time
+	    updatedAt: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new FundAlert(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.fundCode = source["fundCode"];
+	        this.fundName = source["fundName"];
+	        this.alertType = source["alertType"];
+	        this.targetValue = source["targetValue"];
+	        this.condition = source["condition"];
+	        this.enabled = source["enabled"];
+	        this.triggered = source["triggered"];
+	        this.triggeredAt = this.convertValues(source["triggeredAt"], null);
+	        this.triggeredNav = source["triggeredNav"];
+	        this.triggeredChange = source["triggeredChange"];
+	        this.createdAt = this.convertValues(source["createdAt"], null);
+	        this.updatedAt = this.convertValues(source["updatedAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class FundDetail {
+	    code: string;
+	    name: string;
+	    type: string;
+	    riskLevel: string;
+	    manager: string;
 	    company: string;
 	    scale: number;
 	    scaleDate: string;
@@ -725,145 +1521,697 @@ export namespace models {
 	    navDate: string;
 	    nav: number;
 	    estimate: number;
-	    oneDayReturn: number;
-	    oneYearReturn: number;
-	    threeYearReturn: number;
-	    thisYearReturn: number;
-	    sinceStartReturn: number;
-	    sharpRatio: number;
-	    maxDrawdown: number;
+	    oneDayReturn: number;
+	    oneYearReturn: number;
+	    threeYearReturn: number;
+	    thisYearReturn: number;
+	    sinceStartReturn: number;
+	    sharpRatio: number;
+	    maxDrawdown: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new FundDetail(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.code = source["code"];
+	        this.name = source["name"];
+	        this.type = source["type"];
+	        this.riskLevel = source["riskLevel"];
+	        this.manager = source["manager"];
+	        this.company = source["company"];
+	        this.scale = source["scale"];
+	        this.scaleDate = source["scaleDate"];
+	        this.inceptionDate = source["inceptionDate"];
+	        this.navDate = source["navDate"];
+	        this.nav = source["nav"];
+	        this.estimate = source["estimate"];
+	        this.oneDayReturn = source["oneDayReturn"];
+	        this.oneYearReturn = source["oneYearReturn"];
+	        this.threeYearReturn = source["threeYearReturn"];
+	        this.thisYearReturn = source["thisYearReturn"];
+	        this.sinceStartReturn = source["sinceStartReturn"];
+	        this.sharpRatio = source["sharpRatio"];
+	        this.maxDrawdown = source["maxDrawdown"];
+	    }
+	}
+	export class FundHolding {
+	    code: string;
+	    name: string;
+	    ratio: number;
+	    industry: string;
+	    trend: string;
+	    change: number;
+	    type: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new FundHolding(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.code = source["code"];
+	        this.name = source["name"];
+	        this.ratio = source["ratio"];
+	        this.industry = source["industry"];
+	        this.trend = source["trend"];
+	        this.change = source["change"];
+	        this.type = source["type"];
+	    }
+	}
+	export class FundNotice {
+	    id: string;
+	    title: string;
+	    date: string;
+	    category: string;
+	    url: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new FundNotice(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.title = source["title"];
+	        this.date = source["date"];
+	        this.category = source["category"];
+	        this.url = source["url"];
+	    }
+	}
+	export class FundPerformancePoint {
+	    date: string;
+	    nav: number;
+	    accNav: number;
+	    changePercent: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new FundPerformancePoint(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.date = source["date"];
+	        this.nav = source["nav"];
+	        this.accNav = source["accNav"];
+	        this.changePercent = source["changePercent"];
+	    }
+	}
+	export class FundPrice {
+	    code: string;
+	    name: string;
+	    nav: number;
+	    estimate: number;
+	    changePercent: number;
+	    updateTime: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new FundPrice(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.code = source["code"];
+	        this.name = source["name"];
+	        this.nav = source["nav"];
+	        this.estimate = source["estimate"];
+	        this.changePercent = source["changePercent"];
+	        this.updateTime = source["updateTime"];
+	    }
+	}
+	export class FundOverview {
+	    price?: FundPrice;
+	    detail?: FundDetail;
+	    history: FundPerformancePoint[];
+	    stockHoldings: FundHolding[];
+	    bondHoldings: FundHolding[];
+	    notices: FundNotice[];
+	
+	    static createFrom(source: any = {}) {
+	        return new FundOverview(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.price = this.convertValues(source["price"], FundPrice);
+	        this.detail = this.convertValues(source["detail"], FundDetail);
+	        this.history = this.convertValues(source["history"], FundPerformancePoint);
+	        this.stockHoldings = this.convertValues(source["stockHoldings"], FundHolding);
+	        this.bondHoldings = this.convertValues(source["bondHoldings"], FundHolding);
+	        this.notices = this.convertValues(source["notices"], FundNotice);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class FundPosition {
+	    id: number;
+	    fundCode: string;
+	    fundName: string;
+	    buyNav: number;
+	    buyDate: string;
+	    share: number;
+	    costNav: number;
+	    targetNav: number;
+	    stopLossNav: number;
+	    notes: string;
+	    status: string;
+	    redeemNav: number;
+	    redeemDate: string;
+	    // Go type: time
+	    createdAt: any;
+	    // Go type: time
+	    updatedAt: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new FundPosition(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.fundCode = source["fundCode"];
+	        this.fundName = source["fundName"];
+	        this.buyNav = source["buyNav"];
+	        this.buyDate = source["buyDate"];
+	        this.share = source["share"];
+	        this.costNav = source["costNav"];
+	        this.targetNav = source["targetNav"];
+	        this.stopLossNav = source["stopLossNav"];
+	        this.notes = source["notes"];
+	        this.status = source["status"];
+	        this.redeemNav = source["redeemNav"];
+	        this.redeemDate = source["redeemDate"];
+	        this.createdAt = this.convertValues(source["createdAt"], null);
+	        this.updatedAt = this.convertValues(source["updatedAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class Futures {
+	    id: number;
+	    code: string;
+	    name: string;
+	    exchange: string;
+	    product: string;
+	    // Go type: time
+	    createdAt: any;
+	    // Go type: time
+	    updatedAt: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new Futures(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.code = source["code"];
+	        this.name = source["name"];
+	        this.exchange = source["exchange"];
+	        this.product = source["product"];
+	        this.createdAt = this.convertValues(source["createdAt"], null);
+	        this.updatedAt = this.convertValues(source["updatedAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class FuturesPrice {
+	    code: string;
+	    name: string;
+	    price: number;
+	    change: number;
+	    changePercent: number;
+	    open: number;
+	    high: number;
+	    low: number;
+	    preClose: number;
+	    preSettle: number;
+	    settle: number;
+	    volume: number;
+	    amount: number;
+	    openInterest: number;
+	    updateTime: string;
+	    exchange: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new FuturesPrice(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.code = source["code"];
+	        this.name = source["name"];
+	        this.price = source["price"];
+	        this.change = source["change"];
+	        this.changePercent = source["changePercent"];
+	        this.open = source["open"];
+	        this.high = source["high"];
+	        this.low = source["low"];
+	        this.preClose = source["preClose"];
+	        this.preSettle = source["preSettle"];
+	        this.settle = source["settle"];
+	        this.volume = source["volume"];
+	        this.amount = source["amount"];
+	        this.openInterest = source["openInterest"];
+	        this.updateTime = source["updateTime"];
+	        this.exchange = source["exchange"];
+	    }
+	}
+	export class FuturesProduct {
+	    code: string;
+	    name: string;
+	    exchange: string;
+	    unit: string;
+	    margin: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new FuturesProduct(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.code = source["code"];
+	        this.name = source["name"];
+	        this.exchange = source["exchange"];
+	        this.unit = source["unit"];
+	        this.margin = source["margin"];
+	    }
+	}
+	export class GlobalIndex {
+	    code: string;
+	    name: string;
+	    nameCn: string;
+	    price: number;
+	    change: number;
+	    changePercent: number;
+	    open: number;
+	    high: number;
+	    low: number;
+	    preClose: number;
+	    updateTime: string;
+	    region: string;
+	    country: string;
+	    status: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new GlobalIndex(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.code = source["code"];
+	        this.name = source["name"];
+	        this.nameCn = source["nameCn"];
+	        this.price = source["price"];
+	        this.change = source["change"];
+	        this.changePercent = source["changePercent"];
+	        this.open = source["open"];
+	        this.high = source["high"];
+	        this.low = source["low"];
+	        this.preClose = source["preClose"];
+	        this.updateTime = source["updateTime"];
+	        this.region = source["region"];
+	        this.country = source["country"];
+	        this.status = source["status"];
+	    }
+	}
+	export class GlobalIndexResult {
+	    items: GlobalIndex[];
+	    meta: DataMeta;
+	
+	    static createFrom(source: any = {}) {
+	        return new GlobalIndexResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.items = this.convertValues(source["items"], GlobalIndex);
+	        this.meta = this.convertValues(source["meta"], DataMeta);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class HKStock {
+	    id: number;
+	    code: string;
+	    name: string;
+	    nameCn: string;
+	    lot: number;
+	    // Go type: time
+	    createdAt: any;
+	    // Go type: time
+	    updatedAt: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new HKStock(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.code = source["code"];
+	        this.name = source["name"];
+	        this.nameCn = source["nameCn"];
+	        this.lot = source["lot"];
+	        this.createdAt = this.convertValues(source["createdAt"], null);
+	        this.updatedAt = this.convertValues(source["updatedAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class HotTopic {
+	    rank: number;
+	    title: string;
+	    desc: string;
+	    readCount: number;
+	    postCount: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new HotTopic(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.rank = source["rank"];
+	        this.title = source["title"];
+	        this.desc = source["desc"];
+	        this.readCount = source["readCount"];
+	        this.postCount = source["postCount"];
+	    }
+	}
+	export class HotTopicResult {
+	    items: HotTopic[];
+	    meta: DataMeta;
+	
+	    static createFrom(source: any = {}) {
+	        return new HotTopicResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.items = this.convertValues(source["items"], HotTopic);
+	        this.meta = this.convertValues(source["meta"], DataMeta);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class IndustryRank {
+	    name: string;
+	    changePercent: number;
+	    leadStock: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new IndustryRank(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.changePercent = source["changePercent"];
+	        this.leadStock = source["leadStock"];
+	    }
+	}
+	export class IndustryRankResult {
+	    items: IndustryRank[];
+	    meta: DataMeta;
+	
+	    static createFrom(source: any = {}) {
+	        return new IndustryRankResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.items = this.convertValues(source["items"], IndustryRank);
+	        this.meta = this.convertValues(source["meta"], DataMeta);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class KLineData {
+	    date: string;
+	    open: number;
+	    high: number;
+	    low: number;
+	    close: number;
+	    volume: number;
+	    code: string;
+	    openInterest?: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new FundDetail(source);
+	        return new KLineData(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.date = source["date"];
+	        this.open = source["open"];
+	        this.high = source["high"];
+	        this.low = source["low"];
+	        this.close = source["close"];
+	        this.volume = source["volume"];
+	        this.code = source["code"];
+	        this.openInterest = source["openInterest"];
+	    }
+	}
+	export class LongTigerItem {
+	    rank: number;
+	    code: string;
+	    name: string;
+	    changePercent: number;
+	    buyAmount: string;
+	    sellAmount: string;
+	    date: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new LongTigerItem(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.rank = source["rank"];
 	        this.code = source["code"];
 	        this.name = source["name"];
-	        this.type = source["type"];
-	        this.riskLevel = source["riskLevel"];
-	        this.manager = source["manager"];
-	        this.company = source["company"];
-	        this.scale = source["scale"];
-	        this.scaleDate = source["scaleDate"];
-	        this.inceptionDate = source["inceptionDate"];
-	        this.navDate = source["navDate"];
-	        this.nav = source["nav"];
-	        this.estimate = source["estimate"];
-	        this.oneDayReturn = source["oneDayReturn"];
-	        this.oneYearReturn = source["oneYearReturn"];
-	        this.threeYearReturn = source["threeYearReturn"];
-	        this.thisYearReturn = source["thisYearReturn"];
-	        this.sinceStartReturn = source["sinceStartReturn"];
-	        this.sharpRatio = source["sharpRatio"];
-	        this.maxDrawdown = source["maxDrawdown"];
+	        this.changePercent = source["changePercent"];
+	        this.buyAmount = source["buyAmount"];
+	        this.sellAmount = source["sellAmount"];
+	        this.date = source["date"];
 	    }
 	}
-	export class FundHolding {
+	export class MarketIndex {
 	    code: string;
 	    name: string;
-	    ratio: number;
-	    industry: string;
-	    trend: string;
+	    price: number;
 	    change: number;
-	    type: string;
+	    changePercent: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new FundHolding(source);
+	        return new MarketIndex(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.code = source["code"];
 	        this.name = source["name"];
-	        this.ratio = source["ratio"];
-	        this.industry = source["industry"];
-	        this.trend = source["trend"];
+	        this.price = source["price"];
 	        this.change = source["change"];
-	        this.type = source["type"];
+	        this.changePercent = source["changePercent"];
 	    }
 	}
-	export class FundNotice {
-	    id: string;
-	    title: string;
-	    date: string;
-	    category: string;
-	    url: string;
+	export class MarketIndexResult {
+	    items: MarketIndex[];
+	    meta: DataMeta;
 	
 	    static createFrom(source: any = {}) {
-	        return new FundNotice(source);
+	        return new MarketIndexResult(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.id = source["id"];
-	        this.title = source["title"];
-	        this.date = source["date"];
-	        this.category = source["category"];
-	        this.url = source["url"];
+	        this.items = this.convertValues(source["items"], MarketIndex);
+	        this.meta = this.convertValues(source["meta"], DataMeta);
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
-	export class FundPerformancePoint {
-	    date: string;
-	    nav: number;
-	    accNav: number;
+	export class MinuteData {
+	    time: string;
+	    price: number;
+	    volume: number;
 	    changePercent: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new FundPerformancePoint(source);
+	        return new MinuteData(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.date = source["date"];
-	        this.nav = source["nav"];
-	        this.accNav = source["accNav"];
+	        this.time = source["time"];
+	        this.price = source["price"];
+	        this.volume = source["volume"];
 	        this.changePercent = source["changePercent"];
 	    }
 	}
-	export class FundPrice {
+	export class MoneyFlow {
 	    code: string;
 	    name: string;
-	    nav: number;
-	    estimate: number;
-	    changePercent: number;
-	    updateTime: string;
+	    mainFlow: number;
+	    superFlow: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new FundPrice(source);
+	        return new MoneyFlow(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.code = source["code"];
 	        this.name = source["name"];
-	        this.nav = source["nav"];
-	        this.estimate = source["estimate"];
-	        this.changePercent = source["changePercent"];
-	        this.updateTime = source["updateTime"];
+	        this.mainFlow = source["mainFlow"];
+	        this.superFlow = source["superFlow"];
 	    }
 	}
-	export class FundOverview {
-	    price?: FundPrice;
-	    detail?: FundDetail;
-	    history: FundPerformancePoint[];
-	    stockHoldings: FundHolding[];
-	    bondHoldings: FundHolding[];
-	    notices: FundNotice[];
+	export class MoneyFlowResult {
+	    items: MoneyFlow[];
+	    meta: DataMeta;
 	
 	    static createFrom(source: any = {}) {
-	        return new FundOverview(source);
+	        return new MoneyFlowResult(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.price = this.convertValues(source["price"], FundPrice);
-	        this.detail = this.convertValues(source["detail"], FundDetail);
-	        this.history = this.convertValues(source["history"], FundPerformancePoint);
-	        this.stockHoldings = this.convertValues(source["stockHoldings"], FundHolding);
-	        this.bondHoldings = this.convertValues(source["bondHoldings"], FundHolding);
-	        this.notices = this.convertValues(source["notices"], FundNotice);
+	        this.items = this.convertValues(source["items"], MoneyFlow);
+	        this.meta = this.convertValues(source["meta"], DataMeta);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -884,45 +2232,148 @@ export namespace models {
 		    return a;
 		}
 	}
+	export class NewsArticle {
+	    id: number;
+	    url: string;
+	    title: string;
+	    content: string;
+	    source: string;
+	    channel: string;
+	    codes: string;
+	    // Go type: time
+	    publishTime: any;
+	    // Go type: time
+	    createdAt: any;
 	
-	export class FundPosition {
+	    static createFrom(source: any = {}) {
+	        return new NewsArticle(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.url = source["url"];
+	        this.title = source["title"];
+	        this.content = source["content"];
+	        this.source = source["source"];
+	        this.channel = source["channel"];
+	        this.codes = source["codes"];
+	        this.publishTime = this.convertValues(source["publishTime"], null);
+	        this.createdAt = this.convertValues(source["createdAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class NewsItem {
 	    id: number;
-	    fundCode: string;
-	    fundName: string;
-	    buyNav: number;
+	    title: string;
+	    content: string;
+	    time: string;
+	    source: string;
+	    importance: string;
+	    url?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new NewsItem(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.title = source["title"];
+	        this.content = source["content"];
+	        this.time = source["time"];
+	        this.source = source["source"];
+	        this.importance = source["importance"];
+	        this.url = source["url"];
+	    }
+	}
+	export class NewsListResult {
+	    items: NewsItem[];
+	    meta: DataMeta;
+	
+	    static createFrom(source: any = {}) {
+	        return new NewsListResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.items = this.convertValues(source["items"], NewsItem);
+	        this.meta = this.convertValues(source["meta"], DataMeta);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Position {
+	    id: number;
+	    stockCode: string;
+	    stockName: string;
+	    buyPrice: number;
 	    buyDate: string;
-	    share: number;
-	    costNav: number;
-	    targetNav: number;
-	    stopLossNav: number;
+	    quantity: number;
+	    costPrice: number;
+	    targetPrice: number;
+	    stopLossPrice: number;
 	    notes: string;
 	    status: string;
-	    redeemNav: number;
-	    redeemDate: string;
+	    sellPrice: number;
+	    sellDate: string;
 	    // Go type: time
 	    createdAt: any;
 	    // Go type: time
 	    updatedAt: any;
 	
 	    static createFrom(source: any = {}) {
-	        return new FundPosition(source);
+	        return new Position(source);
 	    }
 	
 	    constructor(source: any = {}) {
-	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.id = source["id"];
-	        this.fundCode = source["fundCode"];
-	        this.fundName = source["fundName"];
-	        this.buyNav = source["buyNav"];
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.stockCode = source["stockCode"];
+	        this.stockName = source["stockName"];
+	        this.buyPrice = source["buyPrice"];
 	        this.buyDate = source["buyDate"];
-	        this.share = source["share"];
-	        this.costNav = source["costNav"];
-	        this.targetNav = source["targetNav"];
-	        this.stopLossNav = source["stopLossNav"];
+	        this.quantity = source["quantity"];
+	        this.costPrice = source["costPrice"];
+	        this.targetPrice = source["targetPrice"];
+	        this.stopLossPrice = source["stopLossPrice"];
 	        this.notes = source["notes"];
 	        this.status = source["status"];
-	        this.redeemNav = source["redeemNav"];
-	        this.redeemDate = source["redeemDate"];
+	        this.sellPrice = source["sellPrice"];
+	        this.sellDate = source["sellDate"];
 	        this.createdAt = this.convertValues(source["createdAt"], null);
 	        this.updatedAt = this.convertValues(source["updatedAt"], null);
 	    }
@@ -945,29 +2396,28 @@ export namespace models {
 		    return a;
 		}
 	}
-	
-	export class Futures {
+	export class ProAnalysisCache {
 	    id: number;
-	    code: string;
-	    name: string;
-	    exchange: string;
-	    product: string;
+	    stockCode: string;
+	    analysisType: string;
+	    masterStyle: string;
+	    content: string;
 	    // Go type: time
 	    createdAt: any;
 	    // Go type: time
 	    updatedAt: any;
 	
 	    static createFrom(source: any = {}) {
-	        return new Futures(source);
+	        return new ProAnalysisCache(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.id = source["id"];
-	        this.code = source["code"];
-	        this.name = source["name"];
-	        this.exchange = source["exchange"];
-	        this.product = source["product"];
+	        this.stockCode = source["stockCode"];
+	        this.analysisType = source["analysisType"];
+	        this.masterStyle = source["masterStyle"];
+	        this.content = source["content"];
 	        this.createdAt = this.convertValues(source["createdAt"], null);
 	        this.updatedAt = this.convertValues(source["updatedAt"], null);
 	    }
@@ -990,130 +2440,136 @@ export namespace models {
 		    return a;
 		}
 	}
-	export class FuturesPrice {
-	    code: string;
-	    name: string;
-	    price: number;
-	    change: number;
-	    changePercent: number;
-	    open: number;
-	    high: number;
-	    low: number;
-	    preClose: number;
-	    preSettle: number;
-	    settle: number;
-	    volume: number;
-	    amount: number;
-	    openInterest: number;
-	    updateTime: string;
-	    exchange: string;
 	
-	    static createFrom(source: any = {}) {
-	        return new FuturesPrice(source);
-	    }
 	
-	    constructor(source: any = {}) {
-	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.code = source["code"];
-	        this.name = source["name"];
-	        this.price = source["price"];
-	        this.change = source["change"];
-	        this.changePercent = source["changePercent"];
-	        this.open = source["open"];
-	        this.high = source["high"];
-	        this.low = source["low"];
-	        this.preClose = source["preClose"];
-	        this.preSettle = source["preSettle"];
-	        this.settle = source["settle"];
-	        this.volume = source["volume"];
-	        this.amount = source["amount"];
-	        this.openInterest = source["openInterest"];
-	        this.updateTime = source["updateTime"];
-	        this.exchange = source["exchange"];
-	    }
-	}
-	export class FuturesProduct {
-	    code: string;
-	    name: string;
-	    exchange: string;
-	    unit: string;
-	    margin: string;
+	
+	export class RatingBucket {
+	    label: string;
+	    score: number;
+	    count: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new FuturesProduct(source);
+	        return new RatingBucket(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.code = source["code"];
-	        this.name = source["name"];
-	        this.exchange = source["exchange"];
-	        this.unit = source["unit"];
-	        this.margin = source["margin"];
+	        this.label = source["label"];
+	        this.score = source["score"];
+	        this.count = source["count"];
 	    }
 	}
-	export class GlobalIndex {
-	    code: string;
-	    name: string;
-	    nameCn: string;
-	    price: number;
-	    change: number;
-	    changePercent: number;
-	    open: number;
-	    high: number;
-	    low: number;
-	    preClose: number;
-	    updateTime: string;
-	    region: string;
-	    country: string;
-	    status: string;
+	export class ReportConsensus {
+	    stockCode: string;
+	    stockName: string;
+	    windowDays: number;
+	    reportCount: number;
+	    orgCount: number;
+	    ratingDistribution: RatingBucket[];
+	    avgRatingScore: number;
+	    upgrades30: number;
+	    downgrades30: number;
+	    upgrades90: number;
+	    downgrades90: number;
+	    targetPriceCount: number;
+	    targetPriceAvg: number;
+	    targetPriceHigh: number;
+	    targetPriceLow: number;
+	    epsThisYearAvg: number;
+	    epsNextYearAvg: number;
+	    currentPrice: number;
+	    impliedUpside: number;
+	    latestReportDate: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new GlobalIndex(source);
+	        return new ReportConsensus(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.code = source["code"];
-	        this.name = source["name"];
-	        this.nameCn = source["nameCn"];
-	        this.price = source["price"];
-	        this.change = source["change"];
-	        this.changePercent = source["changePercent"];
-	        this.open = source["open"];
-	        this.high = source["high"];
-	        this.low = source["low"];
-	        this.preClose = source["preClose"];
-	        this.updateTime = source["updateTime"];
-	        this.region = source["region"];
-	        this.country = source["country"];
-	        this.status = source["status"];
+	        this.stockCode = source["stockCode"];
+	        this.stockName = source["stockName"];
+	        this.windowDays = source["windowDays"];
+	        this.reportCount = source["reportCount"];
+	        this.orgCount = source["orgCount"];
+	        this.ratingDistribution = this.convertValues(source["ratingDistribution"], RatingBucket);
+	        this.avgRatingScore = source["avgRatingScore"];
+	        this.upgrades30 = source["upgrades30"];
+	        this.downgrades30 = source["downgrades30"];
+	        this.upgrades90 = source["upgrades90"];
+	        this.downgrades90 = source["downgrades90"];
+	        this.targetPriceCount = source["targetPriceCount"];
+	        this.targetPriceAvg = source["targetPriceAvg"];
+	        this.targetPriceHigh = source["targetPriceHigh"];
+	        this.targetPriceLow = source["targetPriceLow"];
+	        this.epsThisYearAvg = source["epsThisYearAvg"];
+	        this.epsNextYearAvg = source["epsNextYearAvg"];
+	        this.currentPrice = source["currentPrice"];
+	        this.impliedUpside = source["impliedUpside"];
+	        this.latestReportDate = source["latestReportDate"];
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
-	export class HKStock {
+	export class ReportRecord {
 	    id: number;
-	    code: string;
-	    name: string;
-	    nameCn: string;
-	    lot: number;
+	    infoCode: string;
+	    stockCode: string;
+	    stockName: string;
+	    title: string;
+	    orgName: string;
+	    researcher: string;
+	    publishDate: string;
+	    rating: string;
+	    ratingScore: number;
+	    lastRating: string;
+	    ratingChange: number;
+	    targetPrice: number;
+	    epsThisYear: number;
+	    epsNextYear: number;
+	    url: string;
 	    // Go type: time
 	    createdAt: any;
-	    // Go type: time
-	    updatedAt: any;
 	
 	    static createFrom(source: any = {}) {
-	        return new HKStock(source);
+	        return new ReportRecord(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.id = source["id"];
-	        this.code = source["code"];
-	        this.name = source["name"];
-	        this.nameCn = source["nameCn"];
-	        this.lot = source["lot"];
+	        this.infoCode = source["infoCode"];
+	        this.stockCode = source["stockCode"];
+	        this.stockName = source["stockName"];
+	        this.title = source["title"];
+	        this.orgName = source["orgName"];
+	        this.researcher = source["researcher"];
+	        this.publishDate = source["publishDate"];
+	        this.rating = source["rating"];
+	        this.ratingScore = source["ratingScore"];
+	        this.lastRating = source["lastRating"];
+	        this.ratingChange = source["ratingChange"];
+	        this.targetPrice = source["targetPrice"];
+	        this.epsThisYear = source["epsThisYear"];
+	        this.epsNextYear = source["epsNextYear"];
+	        this.url = source["url"];
 	        this.createdAt = this.convertValues(source["createdAt"], null);
-	        this.updatedAt = this.convertValues(source["updatedAt"], null);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -1134,208 +2590,131 @@ export namespace models {
 		    return a;
 		}
 	}
-	export class HotTopic {
-	    rank: number;
+	export class ResearchReport {
 	    title: string;
-	    desc: string;
-	    readCount: number;
-	    postCount: number;
-	
-	    static createFrom(source: any = {}) {
-	        return new HotTopic(source);
-	    }
-	
-	    constructor(source: any = {}) {
-	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.rank = source["rank"];
-	        this.title = source["title"];
-	        this.desc = source["desc"];
-	        this.readCount = source["readCount"];
-	        this.postCount = source["postCount"];
-	    }
-	}
-	export class IndustryRank {
-	    name: string;
-	    changePercent: number;
-	    leadStock: string;
-	
-	    static createFrom(source: any = {}) {
-	        return new IndustryRank(source);
-	    }
-	
-	    constructor(source: any = {}) {
-	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.name = source["name"];
-	        this.changePercent = source["changePercent"];
-	        this.leadStock = source["leadStock"];
-	    }
-	}
-	export class KLineData {
-	    date: string;
-	    open: number;
-	    high: number;
-	    low: number;
-	    close: number;
-	    volume: number;
-	    code: string;
-	
-	    static createFrom(source: any = {}) {
-	        return new KLineData(source);
-	    }
-	
-	    constructor(source: any = {}) {
-	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.date = source["date"];
-	        this.open = source["open"];
-	        this.high = source["high"];
-	        this.low = source["low"];
-	        this.close = source["close"];
-	        this.volume = source["volume"];
-	        this.code = source["code"];
-	    }
-	}
-	export class LongTigerItem {
-	    rank: number;
-	    code: string;
-	    name: string;
-	    changePercent: number;
-	    buyAmount: string;
-	    sellAmount: string;
-	    date: string;
+	    stockName: string;
+	    orgName: string;
+	    publishDate: string;
+	    researcher: string;
+	    rating: string;
+	    lastRating: string;
+	    targetPrice: number;
+	    epsThisYear: number;
+	    epsNextYear: number;
+	    infoCode: string;
+	    url: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new LongTigerItem(source);
+	        return new ResearchReport(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.rank = source["rank"];
-	        this.code = source["code"];
-	        this.name = source["name"];
-	        this.changePercent = source["changePercent"];
-	        this.buyAmount = source["buyAmount"];
-	        this.sellAmount = source["sellAmount"];
-	        this.date = source["date"];
+	        this.title = source["title"];
+	        this.stockName = source["stockName"];
+	        this.orgName = source["orgName"];
+	        this.publishDate = source["publishDate"];
+	        this.researcher = source["researcher"];
+	        this.rating = source["rating"];
+	        this.lastRating = source["lastRating"];
+	        this.targetPrice = source["targetPrice"];
+	        this.epsThisYear = source["epsThisYear"];
+	        this.epsNextYear = source["epsNextYear"];
+	        this.infoCode = source["infoCode"];
+	        this.url = source["url"];
 	    }
 	}
-	export class MarketIndex {
-	    code: string;
+	export class SavedScreen {
+	    id: number;
 	    name: string;
-	    price: number;
-	    change: number;
-	    changePercent: number;
+	    expression: string;
+	    description: string;
+	    // Go type: time
+	    lastRunAt?: any;
+	    lastCount: number;
+	    // Go type: time
+	    createdAt: any;
+	    // Go type: time
+	    updatedAt: any;
 	
 	    static createFrom(source: any = {}) {
-	        return new MarketIndex(source);
+	        return new SavedScreen(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.code = source["code"];
+	        this.id = source["id"];
 	        this.name = source["name"];
-	        this.price = source["price"];
-	        this.change = source["change"];
-	        this.changePercent = source["changePercent"];
-	    }
-	}
-	export class MinuteData {
-	    time: string;
-	    price: number;
-	    volume: number;
-	    changePercent: number;
-	
-	    static createFrom(source: any = {}) {
-	        return new MinuteData(source);
+	        this.expression = source["expression"];
+	        this.description = source["description"];
+	        this.lastRunAt = this.convertValues(source["lastRunAt"], null);
+	        this.lastCount = source["lastCount"];
+	        this.createdAt = this.convertValues(source["createdAt"], null);
+	        this.updatedAt = this.convertValues(source["updatedAt"], null);
 	    }
 	
-	    constructor(source: any = {}) {
-	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.time = source["time"];
-	        this.price = source["price"];
-	        this.volume = source["volume"];
-	        this.changePercent = source["changePercent"];
-	    }
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
-	export class MoneyFlow {
+	export class ScreenMatch {
 	    code: string;
 	    name: string;
-	    mainFlow: number;
-	    superFlow: number;
+	    factors: Record<string, number>;
+	    newEntry: boolean;
 	
 	    static createFrom(source: any = {}) {
-	        return new MoneyFlow(source);
+	        return new ScreenMatch(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.code = source["code"];
 	        this.name = source["name"];
-	        this.mainFlow = source["mainFlow"];
-	        this.superFlow = source["superFlow"];
-	    }
-	}
-	export class NewsItem {
-	    id: number;
-	    title: string;
-	    content: string;
-	    time: string;
-	    source: string;
-	    importance: string;
-	
-	    static createFrom(source: any = {}) {
-	        return new NewsItem(source);
-	    }
-	
-	    constructor(source: any = {}) {
-	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.id = source["id"];
-	        this.title = source["title"];
-	        this.content = source["content"];
-	        this.time = source["time"];
-	        this.source = source["source"];
-	        this.importance = source["importance"];
+	        this.factors = source["factors"];
+	        this.newEntry = source["newEntry"];
 	    }
 	}
-	export class Position {
-	    id: number;
-	    stockCode: string;
-	    stockName: string;
-	    buyPrice: number;
-	    buyDate: string;
-	    quantity: number;
-	    costPrice: number;
-	    targetPrice: number;
-	    stopLossPrice: number;
-	    notes: string;
-	    status: string;
-	    sellPrice: number;
-	    sellDate: string;
-	    // Go type: time
-	    createdAt: any;
-	    // Go type: time
-	    updatedAt: any;
+	export class ScreenResult {
+	    screenId: number;
+	    expression: string;
+	    fields: string[];
+	    runDate: string;
+	    universe: number;
+	    matches: ScreenMatch[];
+	    prevRunDate: string;
+	    newEntrants: string[];
+	    exits: string[];
 	
 	    static createFrom(source: any = {}) {
-	        return new Position(source);
+	        return new ScreenResult(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.id = source["id"];
-	        this.stockCode = source["stockCode"];
-	        this.stockName = source["stockName"];
-	        this.buyPrice = source["buyPrice"];
-	        this.buyDate = source["buyDate"];
-	        this.quantity = source["quantity"];
-	        this.costPrice = source["costPrice"];
-	        this.targetPrice = source["targetPrice"];
-	        this.stopLossPrice = source["stopLossPrice"];
-	        this.notes = source["notes"];
-	        this.status = source["status"];
-	        this.sellPrice = source["sellPrice"];
-	        this.sellDate = source["sellDate"];
-	        this.createdAt = this.convertValues(source["createdAt"], null);
-	        this.updatedAt = this.convertValues(source["updatedAt"], null);
+	        this.screenId = source["screenId"];
+	        this.expression = source["expression"];
+	        this.fields = source["fields"];
+	        this.runDate = source["runDate"];
+	        this.universe = source["universe"];
+	        this.matches = this.convertValues(source["matches"], ScreenMatch);
+	        this.prevRunDate = source["prevRunDate"];
+	        this.newEntrants = source["newEntrants"];
+	        this.exits = source["exits"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -1356,28 +2735,30 @@ export namespace models {
 		    return a;
 		}
 	}
-	export class ProAnalysisCache {
+	export class ScreenRun {
 	    id: number;
-	    stockCode: string;
-	    analysisType: string;
-	    masterStyle: string;
-	    content: string;
+	    screenId: number;
+	    runDate: string;
+	    codes: string;
+	    count: number;
+	    universe: number;
 	    // Go type: time
 	    createdAt: any;
 	    // Go type: time
 	    updatedAt: any;
 	
 	    static createFrom(source: any = {}) {
-	        return new ProAnalysisCache(source);
+	        return new ScreenRun(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.id = source["id"];
-	        this.stockCode = source["stockCode"];
-	        this.analysisType = source["analysisType"];
-	        this.masterStyle = source["masterStyle"];
-	        this.content = source["content"];
+	        this.screenId = source["screenId"];
+	        this.runDate = source["runDate"];
+	        this.codes = source["codes"];
+	        this.count = source["count"];
+	        this.universe = source["universe"];
 	        this.createdAt = this.convertValues(source["createdAt"], null);
 	        this.updatedAt = this.convertValues(source["updatedAt"], null);
 	    }
@@ -1400,33 +2781,6 @@ export namespace models {
 		    return a;
 		}
 	}
-	
-	export class ResearchReport {
-	    title: string;
-	    stockName: string;
-	    orgName: string;
-	    publishDate: string;
-	    researcher: string;
-	    rating: string;
-	    infoCode: string;
-	    url: string;
-	
-	    static createFrom(source: any = {}) {
-	        return new ResearchReport(source);
-	    }
-	
-	    constructor(source: any = {}) {
-	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.title = source["title"];
-	        this.stockName = source["stockName"];
-	        this.orgName = source["orgName"];
-	        this.publishDate = source["publishDate"];
-	        this.researcher = source["researcher"];
-	        this.rating = source["rating"];
-	        this.infoCode = source["infoCode"];
-	        this.url = source["url"];
-	    }
-	}
 	export class Stock {
 	    id: number;
 	    code: string;
@@ -1474,6 +2828,9 @@ export namespace models {
 	    stockCode: string;
 	    stockName: string;
 	    alertType: string;
+	    keywords: string;
+	    lastSeenKey: string;
+	    seenKeys: string;
 	    targetValue: number;
 	    condition: string;
 	    enabled: boolean;
@@ -1497,6 +2854,9 @@ export namespace models {
 	        this.stockCode = source["stockCode"];
 	        this.stockName = source["stockName"];
 	        this.alertType = source["alertType"];
+	        this.keywords = source["keywords"];
+	        this.lastSeenKey = source["lastSeenKey"];
+	        this.seenKeys = source["seenKeys"];
 	        this.targetValue = source["targetValue"];
 	        this.condition = source["condition"];
 	        this.enabled = source["enabled"];
@@ -1674,6 +3034,115 @@ export namespace models {
 	        this.skipped = source["skipped"];
 	    }
 	}
+	export class ValuationPercentile {
+	    years: number;
+	    samples: number;
+	    percentile: number;
+	    min: number;
+	    median: number;
+	    max: number;
+	    complete: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ValuationPercentile(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.years = source["years"];
+	        this.samples = source["samples"];
+	        this.percentile = source["percentile"];
+	        this.min = source["min"];
+	        this.median = source["median"];
+	        this.max = source["max"];
+	        this.complete = source["complete"];
+	    }
+	}
+	export class ValuationBand {
+	    metric: string;
+	    label: string;
+	    current: number;
+	    valid: boolean;
+	    percentiles: ValuationPercentile[];
+	    industryMedian: number;
+	    industryCount: number;
+	    premium: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ValuationBand(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.metric = source["metric"];
+	        this.label = source["label"];
+	        this.current = source["current"];
+	        this.valid = source["valid"];
+	        this.percentiles = this.convertValues(source["percentiles"], ValuationPercentile);
+	        this.industryMedian = source["industryMedian"];
+	        this.industryCount = source["industryCount"];
+	        this.premium = source["premium"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ValuationBands {
+	    stockCode: string;
+	    industry: string;
+	    source: string;
+	    tradeDate: string;
+	    startDate: string;
+	    bands: ValuationBand[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ValuationBands(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.stockCode = source["stockCode"];
+	        this.industry = source["industry"];
+	        this.source = source["source"];
+	        this.tradeDate = source["tradeDate"];
+	        this.startDate = source["startDate"];
+	        this.bands = this.convertValues(source["bands"], ValuationBand);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
 	export class VersionInfo {
 	    version: string;
 	    buildTime: string;
@@ -1795,6 +3264,8 @@ export namespace plugin {
 	    triggerTime: string;
 	    change: number;
 	    changePercent: number;
+	    title?: string;
+	    link?: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new NotificationData(source);
@@ -1811,6 +3282,8 @@ export namespace plugin {
 	        this.triggerTime = source["triggerTime"];
 	        this.change = source["change"];
 	        this.changePercent = source["changePercent"];
+	        this.title = source["title"];
+	        this.link = source["link"];
 	    }
 	}
 	export class NotificationTemplate {
@@ -2080,6 +3553,29 @@ export namespace prompt {
 
 }
 
+export namespace screener {
+	
+	export class Field {
+	    name: string;
+	    label: string;
+	    category: string;
+	    unit: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Field(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.label = source["label"];
+	        this.category = source["category"];
+	        this.unit = source["unit"];
+	    }
+	}
+
+}
+
 export namespace struct { ID string "json:\"id\""; Name string "json:\"name\""; Description string "json:\"description\""; Config plugin {
 	
 	export class  {